@@ -1,65 +1,79 @@
-DROP DATABASE quickfix;
-CREATE DATABASE quickfix;
-
-USE quickfix;
-CREATE TABLE sessions (
-  beginstring CHAR(8) NOT NULL,
-  sendercompid VARCHAR(64) NOT NULL,
-  sendersubid VARCHAR(64) NOT NULL,
-  senderlocid VARCHAR(64) NOT NULL,
-  targetcompid VARCHAR(64) NOT NULL,
-  targetsubid VARCHAR(64) NOT NULL,
-  targetlocid VARCHAR(64) NOT NULL,
-  session_qualifier VARCHAR(64) NOT NULL,
-  creation_time DATETIME NOT NULL,
-  incoming_seqnum INT NOT NULL,
-  outgoing_seqnum INT NOT NULL,
-  PRIMARY KEY (beginstring, sendercompid, sendersubid, senderlocid, 
-  				targetcompid, targetsubid, targetlocid, session_qualifier)
-);
-
-CREATE TABLE messages (
-  beginstring CHAR(8) NOT NULL,
-  sendercompid VARCHAR(64) NOT NULL,
-  sendersubid VARCHAR(64) NOT NULL,
-  senderlocid VARCHAR(64) NOT NULL,
-  targetcompid VARCHAR(64) NOT NULL,
-  targetsubid VARCHAR(64) NOT NULL,
-  targetlocid VARCHAR(64) NOT NULL,
-  session_qualifier VARCHAR(64) NOT NULL,
-  msgseqnum INT NOT NULL,
-  message TEXT NOT NULL,
-  PRIMARY KEY (beginstring, sendercompid, sendersubid, senderlocid, 
-  				targetcompid, targetsubid, targetlocid, session_qualifier,
-  				msgseqnum)
-);
-
-CREATE TABLE event_log (
-  id INT NOT NULL IDENTITY,
-  time DATETIME NOT NULL,
-  beginstring CHAR(8) NOT NULL,
-  sendercompid VARCHAR(64) NOT NULL,
-  sendersubid VARCHAR(64) NOT NULL,
-  senderlocid VARCHAR(64) NOT NULL,
-  targetcompid VARCHAR(64) NOT NULL,
-  targetsubid VARCHAR(64) NOT NULL,
-  targetlocid VARCHAR(64) NOT NULL,
-  session_qualifier VARCHAR(64) NOT NULL,
-  text TEXT NOT NULL,
-  PRIMARY KEY (id)
-);
-
-CREATE TABLE messages_log (
-  id INT NOT NULL IDENTITY,
-  time DATETIME NOT NULL,
-  beginstring CHAR(8) NOT NULL,
-  sendercompid VARCHAR(64) NOT NULL,
-  sendersubid VARCHAR(64) NOT NULL,
-  senderlocid VARCHAR(64) NOT NULL,
-  targetcompid VARCHAR(64) NOT NULL,
-  targetsubid VARCHAR(64) NOT NULL,
-  targetlocid VARCHAR(64) NOT NULL,
-  session_qualifier VARCHAR(64) NOT NULL,
-  text TEXT NOT NULL,
-  PRIMARY KEY (id)
-);
+DROP DATABASE quickfix;
+CREATE DATABASE quickfix;
+
+USE quickfix;
+CREATE TABLE sessions (
+  beginstring CHAR(8) NOT NULL,
+  sendercompid VARCHAR(64) NOT NULL,
+  sendersubid VARCHAR(64) NOT NULL,
+  senderlocid VARCHAR(64) NOT NULL,
+  targetcompid VARCHAR(64) NOT NULL,
+  targetsubid VARCHAR(64) NOT NULL,
+  targetlocid VARCHAR(64) NOT NULL,
+  session_qualifier VARCHAR(64) NOT NULL,
+  creation_time DATETIME NOT NULL,
+  incoming_seqnum INT NOT NULL,
+  outgoing_seqnum INT NOT NULL,
+  PRIMARY KEY (beginstring, sendercompid, sendersubid, senderlocid, 
+  				targetcompid, targetsubid, targetlocid, session_qualifier)
+);
+
+CREATE TABLE sessions_epochs (
+  beginstring CHAR(8) NOT NULL,
+  sendercompid VARCHAR(64) NOT NULL,
+  sendersubid VARCHAR(64) NOT NULL,
+  senderlocid VARCHAR(64) NOT NULL,
+  targetcompid VARCHAR(64) NOT NULL,
+  targetsubid VARCHAR(64) NOT NULL,
+  targetlocid VARCHAR(64) NOT NULL,
+  session_qualifier VARCHAR(64) NOT NULL,
+  epoch BIGINT NOT NULL,
+  PRIMARY KEY (beginstring, sendercompid, sendersubid, senderlocid, 
+  				targetcompid, targetsubid, targetlocid, session_qualifier)
+);
+
+CREATE TABLE messages (
+  beginstring CHAR(8) NOT NULL,
+  sendercompid VARCHAR(64) NOT NULL,
+  sendersubid VARCHAR(64) NOT NULL,
+  senderlocid VARCHAR(64) NOT NULL,
+  targetcompid VARCHAR(64) NOT NULL,
+  targetsubid VARCHAR(64) NOT NULL,
+  targetlocid VARCHAR(64) NOT NULL,
+  session_qualifier VARCHAR(64) NOT NULL,
+  msgseqnum INT NOT NULL,
+  message TEXT NOT NULL,
+  PRIMARY KEY (beginstring, sendercompid, sendersubid, senderlocid, 
+  				targetcompid, targetsubid, targetlocid, session_qualifier,
+  				msgseqnum)
+);
+
+CREATE TABLE event_log (
+  id INT NOT NULL IDENTITY,
+  time DATETIME NOT NULL,
+  beginstring CHAR(8) NOT NULL,
+  sendercompid VARCHAR(64) NOT NULL,
+  sendersubid VARCHAR(64) NOT NULL,
+  senderlocid VARCHAR(64) NOT NULL,
+  targetcompid VARCHAR(64) NOT NULL,
+  targetsubid VARCHAR(64) NOT NULL,
+  targetlocid VARCHAR(64) NOT NULL,
+  session_qualifier VARCHAR(64) NOT NULL,
+  text TEXT NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE messages_log (
+  id INT NOT NULL IDENTITY,
+  time DATETIME NOT NULL,
+  beginstring CHAR(8) NOT NULL,
+  sendercompid VARCHAR(64) NOT NULL,
+  sendersubid VARCHAR(64) NOT NULL,
+  senderlocid VARCHAR(64) NOT NULL,
+  targetcompid VARCHAR(64) NOT NULL,
+  targetsubid VARCHAR(64) NOT NULL,
+  targetlocid VARCHAR(64) NOT NULL,
+  session_qualifier VARCHAR(64) NOT NULL,
+  text TEXT NOT NULL,
+  PRIMARY KEY (id)
+);
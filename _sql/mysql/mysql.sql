@@ -1,5 +1,6 @@
-source quickfix_database.sql;
-source sessions_table.sql;
-source messages_table.sql;
-source messages_log_table.sql;
+source quickfix_database.sql;
+source sessions_table.sql;
+source sessions_epochs_table.sql;
+source messages_table.sql;
+source messages_log_table.sql;
 source event_log_table.sql;
\ No newline at end of file
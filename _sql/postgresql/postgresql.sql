@@ -1,4 +1,5 @@
-\i sessions_table.sql;
-\i messages_table.sql;
-\i messages_log_table.sql;
+\i sessions_table.sql;
+\i sessions_epochs_table.sql;
+\i messages_table.sql;
+\i messages_log_table.sql;
 \i event_log_table.sql;
\ No newline at end of file
@@ -19,6 +19,7 @@ import (
 	"bufio"
 	"bytes"
 	"crypto/tls"
+	"errors"
 	"io"
 	"net"
 	"runtime/debug"
@@ -30,6 +31,8 @@ import (
 	"github.com/quickfixgo/quickfix/config"
 )
 
+var errAcceptorStopped = errors.New("Acceptor stopped")
+
 // Acceptor accepts connections from FIX clients and manages the associated sessions.
 type Acceptor struct {
 	app                   Application
@@ -38,6 +41,11 @@ type Acceptor struct {
 	storeFactory          MessageStoreFactory
 	globalLog             Log
 	sessions              map[SessionID]*Session
+	sessionsLock          sync.RWMutex
+	sessionDone           map[SessionID]chan interface{}
+	running               bool
+	stopped               bool
+	useTCPProxy           bool
 	sessionGroup          sync.WaitGroup
 	listenerShutdown      sync.WaitGroup
 	dynamicSessions       bool
@@ -49,6 +57,7 @@ type Acceptor struct {
 	listeners             map[string]net.Listener
 	connectionValidator   ConnectionValidator
 	tlsConfig             *tls.Config
+	certificates          *certificateReloader
 	newListenerCallback   NewListenerCallback
 	sessionFactory
 }
@@ -65,30 +74,22 @@ type NewListenerCallback func(address string, tlsConfig *tls.Config) (net.Listen
 
 // Start accepting connections.
 func (a *Acceptor) Start() (err error) {
-	socketAcceptHost := ""
-	if a.settings.GlobalSettings().HasSetting(config.SocketAcceptHost) {
-		if socketAcceptHost, err = a.settings.GlobalSettings().Setting(config.SocketAcceptHost); err != nil {
-			return
-		}
-	}
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
 
 	a.sessionHostPort = make(map[SessionID]int)
 	a.listeners = make(map[string]net.Listener)
 	for sessionID, sessionSettings := range a.settings.SessionSettings() {
-		if sessionSettings.HasSetting(config.SocketAcceptPort) {
-			if a.sessionHostPort[sessionID], err = sessionSettings.IntSetting(config.SocketAcceptPort); err != nil {
-				return
-			}
-		} else if a.sessionHostPort[sessionID], err = a.settings.GlobalSettings().IntSetting(config.SocketAcceptPort); err != nil {
+		var address string
+		if address, err = a.listenAddress(sessionID, sessionSettings); err != nil {
 			return
 		}
-		address := net.JoinHostPort(socketAcceptHost, strconv.Itoa(a.sessionHostPort[sessionID]))
 		a.listeners[address] = nil
 	}
 
 	if a.tlsConfig == nil {
 		var tlsConfig *tls.Config
-		if tlsConfig, err = loadTLSConfig(a.settings.GlobalSettings()); err != nil {
+		if tlsConfig, a.certificates, err = loadReloadableTLSConfig(a.settings.GlobalSettings()); err != nil {
 			return
 		}
 		a.tlsConfig = tlsConfig
@@ -103,28 +104,24 @@ func (a *Acceptor) Start() (err error) {
 		}
 	}
 
-	var useTCPProxy bool
 	if a.settings.GlobalSettings().HasSetting(config.UseTCPProxy) {
-		if useTCPProxy, err = a.settings.GlobalSettings().BoolSetting(config.UseTCPProxy); err != nil {
+		if a.useTCPProxy, err = a.settings.GlobalSettings().BoolSetting(config.UseTCPProxy); err != nil {
 			return
 		}
 	}
 
 	for address := range a.listeners {
-		if a.listeners[address], err = a.newListenerCallback(address, a.tlsConfig); err != nil {
+		if err = a.listen(address); err != nil {
 			return
-		} else if useTCPProxy {
-			a.listeners[address] = &proxyproto.Listener{Listener: a.listeners[address]}
 		}
 	}
 
-	for _, s := range a.sessions {
-		a.sessionGroup.Add(1)
-		go func(s *Session) {
-			s.run()
-			a.sessionGroup.Done()
-		}(s)
+	a.sessionDone = make(map[SessionID]chan interface{})
+	for sessID, s := range a.sessions {
+		a.runSession(sessID, s)
 	}
+	a.running = true
+	a.stopped = false
 	if a.dynamicSessions {
 		a.dynamicSessionChan = make(chan *Session)
 		a.sessionGroup.Add(1)
@@ -146,26 +143,188 @@ func (a *Acceptor) Stop() {
 		_ = recover() // suppress sending on closed channel error
 	}()
 
+	// Once stopped, AddSession opens no more listeners, so that the snapshot is complete before waiting for them.
+	a.sessionsLock.Lock()
+	a.running = false
+	a.stopped = true
+	listeners := make([]net.Listener, 0, len(a.listeners))
 	for _, listener := range a.listeners {
+		listeners = append(listeners, listener)
+	}
+	a.sessionsLock.Unlock()
+
+	for _, listener := range listeners {
 		listener.Close()
 	}
 	a.listenerShutdown.Wait()
 	if a.dynamicSessions {
 		close(a.dynamicSessionChan)
 	}
-	for _, session := range a.sessions {
+
+	a.sessionsLock.RLock()
+	sessions := make(map[SessionID]*Session, len(a.sessions))
+	for sessionID, session := range a.sessions {
+		sessions[sessionID] = session
+	}
+	a.sessionsLock.RUnlock()
+
+	for _, session := range sessions {
 		session.stop()
 	}
 	a.sessionGroup.Wait()
 
-	for sessionID := range a.sessions {
-		err := UnregisterSession(sessionID)
+	for sessionID := range sessions {
+		err := a.sessionRegistry().unregister(sessionID)
 		if err != nil {
 			return
 		}
 	}
 }
 
+// listenAddress returns the address the acceptor listens on for the session, and records the port of the session.
+func (a *Acceptor) listenAddress(sessionID SessionID, sessionSettings *SessionSettings) (string, error) {
+	socketAcceptHost := ""
+	if a.settings.GlobalSettings().HasSetting(config.SocketAcceptHost) {
+		var err error
+		if socketAcceptHost, err = a.settings.GlobalSettings().Setting(config.SocketAcceptHost); err != nil {
+			return "", err
+		}
+	}
+
+	var port int
+	var err error
+	if sessionSettings.HasSetting(config.SocketAcceptPort) {
+		port, err = sessionSettings.IntSetting(config.SocketAcceptPort)
+	} else {
+		port, err = a.settings.GlobalSettings().IntSetting(config.SocketAcceptPort)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	a.sessionHostPort[sessionID] = port
+	return net.JoinHostPort(socketAcceptHost, strconv.Itoa(port)), nil
+}
+
+// listen opens the listener for address.
+func (a *Acceptor) listen(address string) (err error) {
+	if a.listeners[address], err = a.newListenerCallback(address, a.tlsConfig); err != nil {
+		return
+	} else if a.useTCPProxy {
+		a.listeners[address] = &proxyproto.Listener{Listener: a.listeners[address]}
+	}
+	return
+}
+
+// runSession runs the session until it is stopped. The caller holds sessionsLock.
+func (a *Acceptor) runSession(sessID SessionID, s *Session) {
+	done := make(chan interface{})
+	a.sessionDone[sessID] = done
+
+	a.sessionGroup.Add(1)
+	go func() {
+		s.run()
+		close(done)
+		a.sessionGroup.Done()
+	}()
+}
+
+// AddSession creates a session for settings, overlaying the global settings of the Acceptor. If the Acceptor
+// has been started the session is run at once, and a listener is opened for its SocketAcceptPort unless the
+// Acceptor already listens on it. The message store and log of the session are created with the factories of
+// the Acceptor. Returns the SessionID of the new session, or an error once the Acceptor has been stopped.
+func (a *Acceptor) AddSession(settings *SessionSettings) (SessionID, error) {
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
+
+	if a.stopped {
+		return SessionID{}, errAcceptorStopped
+	}
+
+	sessionID, err := a.settings.AddSession(settings)
+	if err != nil {
+		return sessionID, err
+	}
+
+	sessID := sessionID
+	sessID.Qualifier = ""
+	if _, dup := a.sessions[sessID]; dup {
+		a.settings.removeSession(sessionID)
+		return sessionID, errDuplicateSessionID
+	}
+
+	sessionSettings := a.settings.overlaySessionSettings(settings)
+	session, err := a.createSession(sessionID, a.storeFactory, sessionSettings, a.logFactory, a.app)
+	if err != nil {
+		a.settings.removeSession(sessionID)
+		return sessionID, err
+	}
+
+	if a.running {
+		if err = a.listenForSession(sessionID, sessionSettings); err != nil {
+			a.settings.removeSession(sessionID)
+			_ = a.sessionRegistry().unregister(sessionID)
+			_ = session.close()
+			return sessionID, err
+		}
+		a.runSession(sessID, session)
+	}
+	a.sessions[sessID] = session
+
+	return sessionID, nil
+}
+
+// listenForSession accepts connections on the SocketAcceptPort of the session. The caller holds sessionsLock.
+func (a *Acceptor) listenForSession(sessionID SessionID, sessionSettings *SessionSettings) error {
+	address, err := a.listenAddress(sessionID, sessionSettings)
+	if err != nil {
+		return err
+	}
+	if _, ok := a.listeners[address]; ok {
+		return nil
+	}
+
+	if err = a.listen(address); err != nil {
+		delete(a.listeners, address)
+		return err
+	}
+	a.listenerShutdown.Add(1)
+	go a.listenForConnections(a.listeners[address])
+	return nil
+}
+
+// RemoveSession logs out and disconnects the session, waits for it to stop, then unregisters the session and
+// closes its message store and log. The listener of its SocketAcceptPort is kept open. Returns ErrSessionNotFound if
+// the Acceptor has no session for sessionID.
+func (a *Acceptor) RemoveSession(sessionID SessionID) error {
+	sessID := sessionID
+	sessID.Qualifier = ""
+
+	a.sessionsLock.Lock()
+	session, ok := a.sessions[sessID]
+	if !ok {
+		a.sessionsLock.Unlock()
+		return ErrSessionNotFound
+	}
+	done := a.sessionDone[sessID]
+	delete(a.sessions, sessID)
+	delete(a.sessionDone, sessID)
+	delete(a.sessionHostPort, session.sessionID)
+	a.settings.removeSession(session.sessionID)
+	a.sessionsLock.Unlock()
+
+	if done != nil {
+		session.stop()
+		<-done
+	}
+	a.sessionAddr.Delete(sessID)
+
+	if err := a.sessionRegistry().unregister(session.sessionID); err != nil {
+		return err
+	}
+	return session.close()
+}
+
 // RemoteAddr gets remote IP address for a given Session.
 func (a *Acceptor) RemoteAddr(sessionID SessionID) (net.Addr, bool) {
 	addr, ok := a.sessionAddr.Load(sessionID)
@@ -176,16 +335,22 @@ func (a *Acceptor) RemoteAddr(sessionID SessionID) (net.Addr, bool) {
 	return val, ok
 }
 
-// NewAcceptor creates and initializes a new Acceptor.
-func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Settings, logFactory LogFactory) (a *Acceptor, err error) {
+// NewAcceptor creates and initializes a new Acceptor. A nil storeFactory or logFactory defaults to an in-memory
+// store or a null log, unless set with WithStoreFactory or WithLogFactory.
+func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Settings, logFactory LogFactory, opts ...EngineOption) (a *Acceptor, err error) {
+	o := newEngineOptions(storeFactory, logFactory, opts)
+	storeFactory, logFactory = o.storeFactory, o.logFactory
+
 	a = &Acceptor{
-		app:             app,
-		storeFactory:    storeFactory,
-		settings:        settings,
-		logFactory:      logFactory,
-		sessions:        make(map[SessionID]*Session),
-		sessionHostPort: make(map[SessionID]int),
-		listeners:       make(map[string]net.Listener),
+		app:                 app,
+		storeFactory:        storeFactory,
+		settings:            settings,
+		logFactory:          logFactory,
+		sessions:            make(map[SessionID]*Session),
+		sessionHostPort:     make(map[SessionID]int),
+		listeners:           make(map[string]net.Listener),
+		newListenerCallback: o.listenerFactory,
+		sessionFactory:      sessionFactory{metrics: o.metrics, clock: o.clock, tracer: o.tracer, seqNumPublisher: o.seqNumPublisher, recorder: o.recorder, holidays: o.holidays, fieldCrypter: o.fieldCrypter, rawDecoders: o.rawDecoders, registry: o.registry},
 	}
 	if a.settings.GlobalSettings().HasSetting(config.DynamicSessions) {
 		if a.dynamicSessions, err = settings.globalSettings.BoolSetting(config.DynamicSessions); err != nil {
@@ -203,6 +368,7 @@ func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Se
 		return
 	}
 
+	preloadDataDictionaries(settings.SessionSettings())
 	for sessionID, sessionSettings := range settings.SessionSettings() {
 		sessID := sessionID
 		sessID.Qualifier = ""
@@ -325,7 +491,10 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 	}
 
 	localConnectionPort := netConn.LocalAddr().(*net.TCPAddr).Port
-	if expectedPort, ok := a.sessionHostPort[sessID]; ok && expectedPort != localConnectionPort {
+	a.sessionsLock.RLock()
+	expectedPort, ok := a.sessionHostPort[sessID]
+	a.sessionsLock.RUnlock()
+	if ok && expectedPort != localConnectionPort {
 		a.globalLog.OnEventf("Session %v not found for incoming message: %s", sessID, msgBytes)
 		return
 	}
@@ -342,7 +511,9 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 		a.dynamicQualifierCount++
 		sessID.Qualifier = strconv.Itoa(a.dynamicQualifierCount)
 	}
+	a.sessionsLock.RLock()
 	session, ok := a.sessions[sessID]
+	a.sessionsLock.RUnlock()
 	if !ok {
 		if !a.dynamicSessions {
 			a.globalLog.OnEventf("Session %v not found for incoming message: %s", sessID, msgBytes)
@@ -358,21 +529,27 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 		defer session.stop()
 	}
 
-	a.sessionAddr.Store(sessID, netConn.RemoteAddr())
-	msgIn := make(chan fixIn, session.InChanCapacity)
+	msgIn := make(chan []fixIn, session.InChanCapacity)
 	msgOut := make(chan []byte)
 
 	if err := session.connect(msgIn, msgOut); err != nil {
+		var alreadyConnected alreadyConnectedError
+		if errors.As(err, &alreadyConnected) {
+			a.rejectDuplicateConnection(sessID, session, netConn, alreadyConnected)
+			return
+		}
 		a.globalLog.OnEventf("Unable to accept Session %v connection: %v", sessID, err.Error())
 		return
 	}
+	a.sessionAddr.Store(sessID, netConn.RemoteAddr())
 
+	parser.decodeWith(session.rawDecoder)
 	go func() {
-		msgIn <- fixIn{msgBytes, parser.lastRead}
-		readLoop(parser, msgIn, a.globalLog)
+		msgIn <- []fixIn{{msgBytes, parser.lastRead}}
+		readLoop(parser, msgIn, session.InBatchSize, a.globalLog)
 	}()
 
-	writeLoop(netConn, msgOut, a.globalLog)
+	writeLoop(session.egressWriter(netConn), msgOut, a.globalLog)
 }
 
 func (a *Acceptor) dynamicSessionsLoop() {
@@ -395,7 +572,7 @@ LOOP:
 			sessions[sessionID] = session
 			go func() {
 				session.run()
-				err := UnregisterSession(session.sessionID)
+				err := a.sessionRegistry().unregister(session.sessionID)
 				if err != nil {
 					a.globalLog.OnEventf("Unregister dynamic Session %v failed: %v", session.sessionID, err)
 					return
@@ -445,8 +622,36 @@ func (a *Acceptor) SetTLSConfig(tlsConfig *tls.Config) {
 	a.tlsConfig = tlsConfig
 }
 
+// RefreshTLS re-reads the certificate files of the SocketCertificateFile and SocketPrivateKeyFile settings after
+// Start, so that new connections use the rotated certificate. The previous certificate is kept if the files
+// cannot be loaded. It does nothing when the tls.Config was set with SetTLSConfig.
+func (a *Acceptor) RefreshTLS() error {
+	if a.certificates == nil {
+		return nil
+	}
+
+	if err := a.certificates.reload(); err != nil {
+		a.globalLog.OnEventf("Failed to refresh TLS certificate: %v", err)
+		return err
+	}
+	a.globalLog.OnEvent("Refreshed TLS certificate")
+	return nil
+}
+
 // SetNewListenerCallback allows the creator of the Acceptor to specify the callback used to create each net.Listener
 // which will be used in the Start() method.
 func (a *Acceptor) SetNewListenerCallback(cb NewListenerCallback) {
 	a.newListenerCallback = cb
 }
+
+// sessionList returns the sessions of the acceptor.
+func (a *Acceptor) sessionList() []*Session {
+	a.sessionsLock.RLock()
+	defer a.sessionsLock.RUnlock()
+
+	sessions := make([]*Session, 0, len(a.sessions))
+	for _, session := range a.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
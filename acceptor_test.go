@@ -174,3 +174,68 @@ func TestAcceptor_SetCallback(t *testing.T) {
 	assert.True(t, didUseCallback)
 	defer conn.Close()
 }
+
+func TestAcceptor_AddRemoveSession(t *testing.T) {
+	settings := NewSettings()
+	acceptor, err := NewAcceptor(&MockApp{}, nil, settings, nil)
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "added")
+	sessionSettings.Set(config.TargetCompID, "target")
+	sessionSettings.Set(config.SocketAcceptPort, "5002")
+
+	sessionID, err := acceptor.AddSession(sessionSettings)
+	require.NoError(t, err)
+	assert.Len(t, acceptor.listeners, 1)
+	_, err = GetSession(sessionID)
+	require.NoError(t, err)
+
+	_, err = acceptor.AddSession(sessionSettings)
+	assert.Error(t, err)
+
+	conn, err := net.Dial("tcp", "localhost:5002")
+	require.NoError(t, err)
+	conn.Close()
+
+	require.NoError(t, acceptor.RemoveSession(sessionID))
+	_, err = GetSession(sessionID)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.Empty(t, settings.SessionSettings())
+	assert.ErrorIs(t, acceptor.RemoveSession(sessionID), ErrSessionNotFound)
+}
+
+func TestAcceptor_AddSessionStopped(t *testing.T) {
+	acceptor, err := NewAcceptor(&MockApp{}, nil, NewSettings(), nil)
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+
+	added := make(chan error)
+	go func() {
+		sessionSettings := NewSessionSettings()
+		sessionSettings.Set(config.BeginString, BeginStringFIX42)
+		sessionSettings.Set(config.SenderCompID, "added")
+		sessionSettings.Set(config.TargetCompID, "target")
+		sessionSettings.Set(config.SocketAcceptPort, "5003")
+		sessionID, err := acceptor.AddSession(sessionSettings)
+		if err == nil {
+			_ = acceptor.RemoveSession(sessionID)
+		}
+		added <- err
+	}()
+	acceptor.Stop()
+	<-added
+
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "late")
+	sessionSettings.Set(config.TargetCompID, "target")
+	sessionSettings.Set(config.SocketAcceptPort, "5004")
+	_, err = acceptor.AddSession(sessionSettings)
+	assert.ErrorIs(t, err, errAcceptorStopped)
+	_, err = net.Dial("tcp", "localhost:5004")
+	assert.Error(t, err)
+}
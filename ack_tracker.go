@@ -0,0 +1,181 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// UnacknowledgedHandler is an optional interface implemented by an Application to be notified of outbound order
+// messages the counterparty did not respond to within AckTimeout.
+type UnacknowledgedHandler interface {
+	// OnUnacknowledged is called once per unacknowledged message, with a copy of the message as sent.
+	OnUnacknowledged(msg *Message, sessionID SessionID)
+}
+
+// ackResponses maps the tracked outbound MsgTypes to the MsgTypes acknowledging them.
+var ackResponses = map[string][]string{
+	"D": {"8"},      // NewOrderSingle: ExecutionReport
+	"F": {"8", "9"}, // OrderCancelRequest: ExecutionReport, OrderCancelReject
+	"G": {"8", "9"}, // OrderCancelReplaceRequest: ExecutionReport, OrderCancelReject
+}
+
+type pendingAck struct {
+	msg      *Message
+	msgType  string
+	seqNum   int
+	deadline time.Time
+}
+
+// ackTracker correlates outbound order messages with their responses, keyed by ClOrdID.
+type ackTracker struct {
+	sync.Mutex
+	pending map[string]pendingAck
+}
+
+// track starts tracking msg if it is an order message expecting a response. Resent messages are not tracked.
+func (t *ackTracker) track(msg *Message, msgType []byte, seqNum int, deadline time.Time) {
+	if _, ok := ackResponses[string(msgType)]; !ok {
+		return
+	}
+
+	if msg.Header.Has(tagPossDupFlag) {
+		var possDup FIXBoolean
+		if err := msg.Header.GetField(tagPossDupFlag, &possDup); err != nil || possDup.Bool() {
+			return
+		}
+	}
+
+	clOrdID, err := msg.Body.GetString(tagClOrdID)
+	if err != nil {
+		return
+	}
+
+	sent := NewMessage()
+	msg.CopyInto(sent)
+
+	t.Lock()
+	defer t.Unlock()
+	if t.pending == nil {
+		t.pending = make(map[string]pendingAck)
+	}
+	t.pending[clOrdID] = pendingAck{msg: sent, msgType: string(msgType), seqNum: seqNum, deadline: deadline}
+}
+
+// acknowledge stops tracking the message msg responds to, if any.
+func (t *ackTracker) acknowledge(msg *Message, msgType []byte) {
+	t.Lock()
+	defer t.Unlock()
+	if len(t.pending) == 0 {
+		return
+	}
+
+	switch string(msgType) {
+	case string(msgTypeReject):
+		refSeqNum, err := msg.Body.GetInt(tagRefSeqNum)
+		if err != nil {
+			return
+		}
+		for clOrdID, p := range t.pending {
+			if p.seqNum == refSeqNum {
+				delete(t.pending, clOrdID)
+			}
+		}
+
+	case "j": // BusinessMessageReject
+		if refID, err := msg.Body.GetString(tagBusinessRejectRefID); err == nil {
+			delete(t.pending, refID)
+		}
+
+	default:
+		clOrdID, err := msg.Body.GetString(tagClOrdID)
+		if err != nil {
+			return
+		}
+		p, ok := t.pending[clOrdID]
+		if !ok {
+			return
+		}
+		for _, response := range ackResponses[p.msgType] {
+			if response == string(msgType) {
+				delete(t.pending, clOrdID)
+				return
+			}
+		}
+	}
+}
+
+// expired removes and returns the messages whose deadline passed, oldest first.
+func (t *ackTracker) expired(now time.Time) (msgs []*Message) {
+	t.Lock()
+	defer t.Unlock()
+
+	var expired []pendingAck
+	for clOrdID, p := range t.pending {
+		if now.Before(p.deadline) {
+			continue
+		}
+		expired = append(expired, p)
+		delete(t.pending, clOrdID)
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].seqNum < expired[j].seqNum })
+	for _, p := range expired {
+		msgs = append(msgs, p.msg)
+	}
+	return
+}
+
+// forget stops tracking the message seqNum, canceled before being sent.
+func (t *ackTracker) forget(seqNum int) {
+	t.Lock()
+	defer t.Unlock()
+	for clOrdID, p := range t.pending {
+		if p.seqNum == seqNum {
+			delete(t.pending, clOrdID)
+		}
+	}
+}
+
+func (t *ackTracker) len() int {
+	t.Lock()
+	defer t.Unlock()
+	return len(t.pending)
+}
+
+func (t *ackTracker) clear() {
+	t.Lock()
+	defer t.Unlock()
+	t.pending = nil
+}
+
+func (s *Session) checkAckTimeouts(now time.Time) {
+	if s.AckTimeout <= 0 {
+		return
+	}
+
+	for _, msg := range s.acks.expired(now) {
+		msgType, _ := msg.Header.GetString(tagMsgType)
+		clOrdID, _ := msg.Body.GetString(tagClOrdID)
+		s.log.OnEventf("No response to MsgType %v ClOrdID %v within %v", msgType, clOrdID, s.AckTimeout)
+
+		if handler, ok := s.application.(UnacknowledgedHandler); ok {
+			handler.OnUnacknowledged(msg, s.sessionID)
+		}
+	}
+}
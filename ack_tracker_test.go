@@ -0,0 +1,134 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AckTrackerTestSuite struct {
+	SessionSuiteRig
+	unacknowledged []*Message
+}
+
+func TestAckTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(AckTrackerTestSuite))
+}
+
+func (s *AckTrackerTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.AckTimeout = time.Second
+	s.Session.application = &unacknowledgedApp{MockApp: &s.MockApp, suite: s}
+	s.unacknowledged = nil
+}
+
+type unacknowledgedApp struct {
+	*MockApp
+	suite *AckTrackerTestSuite
+}
+
+func (a *unacknowledgedApp) OnUnacknowledged(msg *Message, _ SessionID) {
+	a.suite.unacknowledged = append(a.suite.unacknowledged, msg)
+}
+
+func (s *AckTrackerTestSuite) sendOrder(msgType, clOrdID string) {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString(msgType))
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	s.Require().Nil(s.Session.send(msg))
+}
+
+func (s *AckTrackerTestSuite) response(msgType, clOrdID string) *Message {
+	msg := s.MessageFactory.buildMessage(msgType)
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	return msg
+}
+
+func (s *AckTrackerTestSuite) TestUnacknowledged() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.sendOrder("D", "order1")
+	s.sendOrder("D", "order2")
+	s.Equal(2, s.Session.status().UnacknowledgedMessages)
+
+	s.Session.checkAckTimeouts(time.Now())
+	s.Empty(s.unacknowledged)
+
+	s.Session.checkAckTimeouts(time.Now().Add(time.Second))
+	s.Require().Len(s.unacknowledged, 2)
+	s.FieldEquals(tagClOrdID, "order1", s.unacknowledged[0].Body)
+	s.FieldEquals(tagClOrdID, "order2", s.unacknowledged[1].Body)
+	s.Equal(0, s.Session.status().UnacknowledgedMessages)
+
+	s.Session.checkAckTimeouts(time.Now().Add(time.Minute))
+	s.Len(s.unacknowledged, 2, "OnUnacknowledged is called once per message")
+}
+
+func (s *AckTrackerTestSuite) TestAcknowledged() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("FromApp").Return(nil)
+	s.sendOrder("D", "order1")
+	s.sendOrder("F", "cancel1")
+	s.sendOrder("G", "replace1")
+
+	s.fixMsgIn(s.Session, s.response("8", "order1"))
+	s.fixMsgIn(s.Session, s.response("9", "cancel1"))
+	s.Equal(1, s.Session.status().UnacknowledgedMessages)
+
+	s.fixMsgIn(s.Session, s.response("8", "replace1"))
+	s.Equal(0, s.Session.status().UnacknowledgedMessages)
+
+	s.Session.checkAckTimeouts(time.Now().Add(time.Minute))
+	s.Empty(s.unacknowledged)
+}
+
+func (s *AckTrackerTestSuite) TestWrongResponseType() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("FromApp").Return(nil)
+	s.sendOrder("D", "order1")
+
+	s.fixMsgIn(s.Session, s.response("9", "order1"))
+	s.Equal(1, s.Session.status().UnacknowledgedMessages)
+}
+
+func (s *AckTrackerTestSuite) TestRejects() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("FromApp").Return(nil)
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.sendOrder("D", "order1")
+	s.sendOrder("D", "order2")
+
+	reject := s.MessageFactory.buildMessage(string(msgTypeReject))
+	reject.Body.SetField(tagRefSeqNum, FIXInt(1))
+	s.fixMsgIn(s.Session, reject)
+	s.Equal(1, s.Session.status().UnacknowledgedMessages)
+
+	businessReject := s.MessageFactory.buildMessage("j")
+	businessReject.Body.SetField(tagBusinessRejectRefID, FIXString("order2"))
+	s.fixMsgIn(s.Session, businessReject)
+	s.Equal(0, s.Session.status().UnacknowledgedMessages)
+}
+
+func (s *AckTrackerTestSuite) TestDisabled() {
+	s.Session.AckTimeout = 0
+	s.MockApp.On("ToApp").Return(nil)
+	s.sendOrder("D", "order1")
+
+	s.Equal(0, s.Session.status().UnacknowledgedMessages)
+}
@@ -0,0 +1,252 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the recorded stable API")
+
+// stableAPIFile records the exported API of the stable packages, one declaration per line.
+const stableAPIFile = "_test_data/api.txt"
+
+// TestStableAPI fails when a declaration of the exported API of the stable packages is removed or changed, or when
+// a method is added to an interface. Other new declarations pass, and are recorded by running the test with -update,
+// as done for each release.
+func TestStableAPI(t *testing.T) {
+	api, err := stableAPI(".")
+	require.NoError(t, err)
+
+	if *update {
+		require.NoError(t, os.WriteFile(stableAPIFile, []byte(strings.Join(api, "\n")+"\n"), 0o644))
+	}
+
+	recorded, err := os.ReadFile(stableAPIFile)
+	require.NoError(t, err)
+
+	var broken []string
+	recordedAPI := strings.Split(strings.TrimSpace(string(recorded)), "\n")
+	for _, decl := range recordedAPI {
+		if _, found := slices.BinarySearch(api, decl); !found {
+			broken = append(broken, decl)
+		}
+	}
+	require.Empty(t, broken, "stable API removed or changed, breaking downstream users")
+
+	// A method added to an interface breaks its implementations.
+	for _, decl := range api {
+		if iface, _, ok := strings.Cut(decl, " interface, "); ok {
+			if _, found := slices.BinarySearch(recordedAPI, iface+" interface"); found && !slices.Contains(recordedAPI, decl) {
+				broken = append(broken, decl)
+			}
+		}
+	}
+	require.Empty(t, broken, "methods added to stable interfaces, breaking their implementations")
+}
+
+// stableAPI returns the sorted exported declarations of the packages under root, except for the experimental
+// packages under x, commands, internal packages and test data.
+func stableAPI(root string) ([]string, error) {
+	var api []string
+	err := filepath.WalkDir(root, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		name := d.Name()
+		if dir != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+			slices.Contains([]string{"x", "cmd", "internal", "testdata", "gen"}, name)) {
+			return filepath.SkipDir
+		}
+
+		decls, err := packageAPI(dir)
+		api = append(api, decls...)
+		return err
+	})
+
+	slices.Sort(api)
+	return slices.Compact(api), err
+}
+
+// packageAPI returns the exported declarations of the package in dir, each prefixed by the package path.
+func packageAPI(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var api []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := goparser.ParseFile(fset, file, nil, goparser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if f.Name.Name == "main" {
+			return nil, nil
+		}
+
+		pkg := path.Join("quickfix", filepath.ToSlash(dir))
+		for _, decl := range f.Decls {
+			for _, d := range exportedDecls(fset, decl) {
+				api = append(api, pkg+": "+d)
+			}
+		}
+	}
+	return api, nil
+}
+
+func exportedDecls(fset *token.FileSet, decl ast.Decl) (decls []string) {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if !decl.Name.IsExported() {
+			return nil
+		}
+		recv := ""
+		if decl.Recv != nil {
+			recvType := decl.Recv.List[0].Type
+			if !ast.IsExported(receiverName(recvType)) {
+				return nil
+			}
+			recv = "(" + node(fset, recvType) + ") "
+		}
+		return []string{"func " + recv + decl.Name.Name + strings.TrimPrefix(node(fset, unnamed(decl.Type)), "func")}
+
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if spec.Name.IsExported() {
+					decls = append(decls, typeDecls(fset, spec)...)
+				}
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if !name.IsExported() {
+						continue
+					}
+					d := decl.Tok.String() + " " + name.Name
+					if spec.Type != nil {
+						d += " " + node(fset, spec.Type)
+					}
+					decls = append(decls, d)
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// typeDecls returns the declaration of a type, followed by its exported struct fields or interface methods.
+func typeDecls(fset *token.FileSet, spec *ast.TypeSpec) []string {
+	name := "type " + spec.Name.Name
+	if spec.TypeParams != nil {
+		name += "[" + node(fset, spec.TypeParams) + "]"
+	}
+	if spec.Assign.IsValid() {
+		return []string{name + " = " + node(fset, spec.Type)}
+	}
+
+	var members *ast.FieldList
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		name += " struct"
+		members = t.Fields
+	case *ast.InterfaceType:
+		name += " interface"
+		members = t.Methods
+	default:
+		return []string{name + " " + node(fset, t)}
+	}
+
+	decls := []string{name}
+	for _, member := range members.List {
+		memberType := member.Type
+		if ft, ok := memberType.(*ast.FuncType); ok {
+			memberType = unnamed(ft)
+		}
+		typ := strings.TrimPrefix(node(fset, memberType), "func")
+		if len(member.Names) == 0 {
+			decls = append(decls, fmt.Sprintf("%v, embedded %v", name, typ))
+		}
+		for _, memberName := range member.Names {
+			if memberName.IsExported() {
+				decls = append(decls, fmt.Sprintf("%v, %v %v", name, memberName.Name, strings.TrimSpace(typ)))
+			}
+		}
+	}
+	return decls
+}
+
+// receiverName returns the name of the type of a method receiver.
+func receiverName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// unnamed returns ft without parameter and result names, which do not change the API.
+func unnamed(ft *ast.FuncType) *ast.FuncType {
+	strip := func(fields *ast.FieldList) *ast.FieldList {
+		if fields == nil {
+			return nil
+		}
+		stripped := &ast.FieldList{}
+		for _, field := range fields.List {
+			for range max(len(field.Names), 1) {
+				stripped.List = append(stripped.List, &ast.Field{Type: field.Type})
+			}
+		}
+		return stripped
+	}
+	return &ast.FuncType{TypeParams: ft.TypeParams, Params: strip(ft.Params), Results: strip(ft.Results)}
+}
+
+func node(fset *token.FileSet, n ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, n); err != nil {
+		return err.Error()
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
@@ -0,0 +1,116 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// applVerIDSettingPrefix prefixes the AppDataDictionary settings of the ApplVerIDs other than the DefaultApplVerID,
+// such as AppDataDictionary.FIX.4.4.
+const applVerIDSettingPrefix = config.AppDataDictionary + "."
+
+// parseApplVerID returns the ApplVerID of value, either an ApplVerID or the BeginString of the version.
+func parseApplVerID(value string) (string, bool) {
+	if applVerID, ok := applVerIDLookup[value]; ok {
+		return applVerID, true
+	}
+	for _, applVerID := range applVerIDLookup {
+		if applVerID == value {
+			return applVerID, true
+		}
+	}
+	return "", false
+}
+
+// applVerIDSettings returns the AppDataDictionary settings of the ApplVerIDs other than the DefaultApplVerID, set
+// to a path or in memory, in order.
+func applVerIDSettings(settings *SessionSettings) []string {
+	var names []string
+	for name := range settings.settings {
+		if strings.HasPrefix(name, applVerIDSettingPrefix) {
+			names = append(names, name)
+		}
+	}
+	for name := range settings.dataDictionaries {
+		if strings.HasPrefix(name, applVerIDSettingPrefix) && !settings.HasSetting(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadApplVerIDDataDictionaries loads the AppDataDictionary of each ApplVerID of a FIXT session, so that the
+// application messages of several FIX versions are parsed and validated with the dictionary of their version. The
+// AppDataDictionary setting without suffix is the dictionary of the DefaultApplVerID.
+func (s *Session) loadApplVerIDDataDictionaries(settings *SessionSettings, validatorSettings ValidatorSettings) error {
+	names := applVerIDSettings(settings)
+	if len(names) == 0 {
+		return nil
+	}
+
+	s.applVerDataDictionaries = map[string]*datadictionary.DataDictionary{s.DefaultApplVerID: s.appDataDictionary}
+	s.applVerValidators = map[string]Validator{s.DefaultApplVerID: s.Validator}
+	for _, name := range names {
+		applVerID, ok := parseApplVerID(strings.TrimPrefix(name, applVerIDSettingPrefix))
+		if !ok {
+			return IncorrectFormatForSetting{Setting: name, Value: []byte(name),
+				Err: errors.New("the suffix must be an ApplVerID or the BeginString of a FIX version")}
+		}
+		dict, err := loadDataDictionary(settings, name)
+		if err != nil {
+			return err
+		}
+		s.applVerDataDictionaries[applVerID] = dict
+		s.applVerValidators[applVerID] = NewValidator(validatorSettings, dict, s.transportDataDictionary)
+	}
+	return nil
+}
+
+// inboundDefaultApplVerID returns the ApplVerID of the inbound application messages without ApplVerID(1128): the
+// DefaultApplVerID of the Logon of the counterparty, or else of the session.
+func (s *Session) inboundDefaultApplVerID() string {
+	if s.targetDefaultApplVerID != "" {
+		return s.targetDefaultApplVerID
+	}
+	return s.DefaultApplVerID
+}
+
+// inboundValidator returns the Validator of an inbound message, the one of its ApplVerID if the session has an
+// AppDataDictionary for it.
+func (s *Session) inboundValidator(msg *Message) Validator {
+	if s.applVerValidators == nil {
+		return s.Validator
+	}
+
+	applVerID := s.inboundDefaultApplVerID()
+	if msg.Header.Has(tagApplVerID) {
+		if value, err := msg.Header.GetString(tagApplVerID); err == nil {
+			applVerID = value
+		}
+	}
+	if validator, ok := s.applVerValidators[applVerID]; ok {
+		return validator
+	}
+	return s.Validator
+}
@@ -0,0 +1,124 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestParseApplVerID(t *testing.T) {
+	var tests = []struct {
+		value    string
+		expected string
+		ok       bool
+	}{
+		{"FIX.4.4", "6", true},
+		{"FIX.5.0SP2", "9", true},
+		{"9", "9", true},
+		{"FIX.9.9", "", false},
+		{"99", "", false},
+	}
+	for _, test := range tests {
+		applVerID, ok := parseApplVerID(test.value)
+		assert.Equal(t, test.expected, applVerID, test.value)
+		assert.Equal(t, test.ok, ok, test.value)
+	}
+}
+
+func newApplVerIDSession(t *testing.T, settings *SessionSettings) (*Session, error) {
+	sessionID := SessionID{BeginString: BeginStringFIXT11, SenderCompID: "ISLD", TargetCompID: "TW"}
+	settings.Set(config.DefaultApplVerID, "FIX.5.0SP2")
+	return sessionFactory{}.newSession(sessionID, NewMemoryStoreFactory(), settings, nullLogFactory{}, new(MockApp))
+}
+
+func TestNewSessionApplVerIDDataDictionaries(t *testing.T) {
+	settings := NewSessionSettings()
+	settings.Set(config.TransportDataDictionary, "spec/FIXT11.xml")
+	settings.Set(config.AppDataDictionary, "spec/FIX50SP2.xml")
+	session, err := newApplVerIDSession(t, settings)
+	require.Nil(t, err)
+	assert.Nil(t, session.applVerDataDictionaries)
+
+	settings.Set(config.AppDataDictionary+".FIX.4.4", "spec/FIX44.xml")
+	settings.Set(config.AppDataDictionary+".7", "spec/FIX50.xml")
+	session, err = newApplVerIDSession(t, settings)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"6", "7", "9"}, slices.Sorted(maps.Keys(session.applVerDataDictionaries)))
+	assert.Equal(t, []string{"6", "7", "9"}, slices.Sorted(maps.Keys(session.applVerValidators)))
+	assert.Equal(t, session.appDataDictionary, session.applVerDataDictionaries["9"])
+
+	settings.Set(config.AppDataDictionary+".FIX.9.9", "spec/FIX44.xml")
+	_, err = newApplVerIDSession(t, settings)
+	assert.NotNil(t, err, "unknown ApplVerID")
+
+	settings = NewSessionSettings()
+	settings.Set(config.AppDataDictionary+".FIX.4.4", "spec/FIX44.xml")
+	_, err = newApplVerIDSession(t, settings)
+	assert.NotNil(t, err, "requires AppDataDictionary")
+}
+
+func TestSessionApplVerIDValidation(t *testing.T) {
+	settings := NewSessionSettings()
+	settings.Set(config.TransportDataDictionary, "spec/FIXT11.xml")
+	settings.Set(config.AppDataDictionary, "spec/FIX50SP2.xml")
+	settings.Set(config.AppDataDictionary+".FIX.4.4", "spec/FIX44.xml")
+	session, err := newApplVerIDSession(t, settings)
+	require.Nil(t, err)
+
+	// DisplayQty(1138) is a field of NewOrderSingle in FIX.5.0SP2, not in FIX.4.4.
+	validate := func(applVerID string) MessageRejectError {
+		order := NewMessage()
+		order.Header.SetString(tagBeginString, BeginStringFIXT11)
+		order.Header.SetString(tagMsgType, "D")
+		order.Header.SetString(tagSenderCompID, "TW")
+		order.Header.SetString(tagTargetCompID, "ISLD")
+		order.Header.SetInt(tagMsgSeqNum, 1)
+		order.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now()})
+		if applVerID != "" {
+			order.Header.SetString(tagApplVerID, applVerID)
+		}
+		order.Body.SetString(Tag(11), "ORDER")
+		order.Body.SetString(Tag(55), "SYM")
+		order.Body.SetString(Tag(54), "1")
+		order.Body.SetField(Tag(60), FIXUTCTimestamp{Time: time.Now()})
+		order.Body.SetString(Tag(40), "1")
+		order.Body.SetInt(Tag(1138), 100)
+
+		msg := NewMessage()
+		require.Nil(t, session.ParseMessage(msg, bytes.NewBufferString(order.String())))
+		return session.inboundValidator(msg).Validate(msg)
+	}
+
+	assert.Nil(t, validate(""))
+	assert.Nil(t, validate("9"))
+	reject := validate("6")
+	require.NotNil(t, reject)
+	assert.Equal(t, rejectReasonInvalidTagNumber, reject.RejectReason())
+	assert.Nil(t, validate("7"), "no dictionary for the ApplVerID, the default one is used")
+
+	session.targetDefaultApplVerID = "6"
+	assert.NotNil(t, validate(""))
+	assert.Nil(t, validate("9"))
+}
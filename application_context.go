@@ -0,0 +1,98 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "context"
+
+// ContextApplication is an optional interface implemented by an Application to receive incoming messages with a
+// context, instead of through FromAdmin and FromApp.
+//
+// The context carries the session's labels, see SessionLabelsFromContext. Its deadline is one heartbeat interval
+// after the message was received, the time the handler may take before the counterparty expects the session to
+// respond. The CallbackTracer of the engine, if any, starts a span around each callback.
+type ContextApplication interface {
+	// FromAdminCtx notification of admin message being received from target.
+	FromAdminCtx(ctx context.Context, message *Message, sessionID SessionID) MessageRejectError
+
+	// FromAppCtx notification of app message being received from target.
+	FromAppCtx(ctx context.Context, message *Message, sessionID SessionID) MessageRejectError
+}
+
+// CallbackTracer starts trace spans around the callbacks of a ContextApplication, see WithCallbackTracer.
+type CallbackTracer interface {
+	// StartCallback returns the context of the span of the callback, "FromAdmin" or "FromApp", and a func ending
+	// the span once the callback returns.
+	StartCallback(ctx context.Context, callback string, message *Message, sessionID SessionID) (context.Context, func())
+}
+
+type sessionLabelsKey struct{}
+
+// SessionLabelsFromContext returns a copy of the labels of the session passed to a ContextApplication callback,
+// as configured with the SessionLabels setting.
+func SessionLabelsFromContext(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(sessionLabelsKey{}).(map[string]string)
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return copied
+}
+
+func (s *Session) fromAdmin(msg *Message) MessageRejectError {
+	app, ok := s.application.(ContextApplication)
+	if !ok {
+		return s.application.FromAdmin(msg, s.sessionID)
+	}
+
+	ctx, done := s.callbackContext(msg, "FromAdmin")
+	defer done()
+	return app.FromAdminCtx(ctx, msg, s.sessionID)
+}
+
+func (s *Session) fromApp(msg *Message) MessageRejectError {
+	app, ok := s.application.(ContextApplication)
+	if !ok {
+		return s.application.FromApp(msg, s.sessionID)
+	}
+
+	ctx, done := s.callbackContext(msg, "FromApp")
+	defer done()
+	return app.FromAppCtx(ctx, msg, s.sessionID)
+}
+
+// callbackContext returns the context of a ContextApplication callback for msg, and a func releasing it.
+func (s *Session) callbackContext(msg *Message, callback string) (context.Context, func()) {
+	ctx := context.WithValue(context.Background(), sessionLabelsKey{}, s.SessionLabels)
+
+	cancel := context.CancelFunc(func() {})
+	if s.HeartBtInt > 0 {
+		received := msg.ReceiveTime
+		if received.IsZero() {
+			received = s.now()
+		}
+		ctx, cancel = context.WithDeadline(ctx, received.Add(s.HeartBtInt))
+	}
+
+	if s.tracer == nil {
+		return ctx, cancel
+	}
+
+	ctx, end := s.tracer.StartCallback(ctx, callback, msg, s.sessionID)
+	return ctx, func() {
+		end()
+		cancel()
+	}
+}
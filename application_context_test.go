@@ -0,0 +1,126 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type mockContextApp struct {
+	*MockApp
+	contexts  []context.Context
+	callbacks []string
+}
+
+func (a *mockContextApp) FromAdminCtx(ctx context.Context, _ *Message, _ SessionID) MessageRejectError {
+	a.contexts = append(a.contexts, ctx)
+	a.callbacks = append(a.callbacks, "FromAdmin")
+	return nil
+}
+
+func (a *mockContextApp) FromAppCtx(ctx context.Context, _ *Message, _ SessionID) MessageRejectError {
+	a.contexts = append(a.contexts, ctx)
+	a.callbacks = append(a.callbacks, "FromApp")
+	return nil
+}
+
+type spanKey struct{}
+
+type mockCallbackTracer struct {
+	started, ended []string
+}
+
+func (t *mockCallbackTracer) StartCallback(ctx context.Context, callback string, _ *Message, _ SessionID) (context.Context, func()) {
+	t.started = append(t.started, callback)
+	return context.WithValue(ctx, spanKey{}, callback), func() { t.ended = append(t.ended, callback) }
+}
+
+type ApplicationContextTestSuite struct {
+	SessionSuiteRig
+	app *mockContextApp
+}
+
+func TestApplicationContextTestSuite(t *testing.T) {
+	suite.Run(t, new(ApplicationContextTestSuite))
+}
+
+func (s *ApplicationContextTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.HeartBtInt = 30 * time.Second
+	s.Session.SessionLabels = map[string]string{"desk": "equities"}
+	s.app = &mockContextApp{MockApp: &s.MockApp}
+	s.Session.application = s.app
+}
+
+func (s *ApplicationContextTestSuite) TestFromAppCtx() {
+	msg := s.NewOrderSingle()
+	msg.ReceiveTime = time.Now()
+	s.Session.fixMsgIn(s.Session, msg)
+
+	s.MockApp.AssertNotCalled(s.T(), "FromApp")
+	s.Equal([]string{"FromApp"}, s.app.callbacks)
+	s.NextTargetMsgSeqNum(2)
+
+	ctx := s.app.contexts[0]
+	deadline, ok := ctx.Deadline()
+	s.True(ok)
+	s.Equal(msg.ReceiveTime.Add(30*time.Second), deadline)
+	s.Equal(context.Canceled, ctx.Err(), "released after the callback")
+
+	labels := SessionLabelsFromContext(ctx)
+	s.Equal(map[string]string{"desk": "equities"}, labels)
+	labels["desk"] = "changed"
+	s.Equal("equities", s.Session.SessionLabels["desk"])
+}
+
+func (s *ApplicationContextTestSuite) TestFromAdminCtx() {
+	s.Session.fixMsgIn(s.Session, s.Heartbeat())
+
+	s.MockApp.AssertNotCalled(s.T(), "FromAdmin")
+	s.Equal([]string{"FromAdmin"}, s.app.callbacks)
+}
+
+func (s *ApplicationContextTestSuite) TestNoHeartbeatNoDeadline() {
+	s.Session.HeartBtInt = 0
+	s.Session.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	_, ok := s.app.contexts[0].Deadline()
+	s.False(ok)
+}
+
+func (s *ApplicationContextTestSuite) TestCallbackTracer() {
+	tracer := &mockCallbackTracer{}
+	s.Session.tracer = tracer
+
+	s.Session.fixMsgIn(s.Session, s.Heartbeat())
+	s.Session.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.Equal([]string{"FromAdmin", "FromApp"}, tracer.started)
+	s.Equal([]string{"FromAdmin", "FromApp"}, tracer.ended)
+	s.Equal("FromApp", s.app.contexts[1].Value(spanKey{}))
+}
+
+func (s *ApplicationContextTestSuite) TestPlainApplication() {
+	s.Session.application = &s.MockApp
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, s.NewOrderSingle())
+	s.MockApp.AssertExpectations(s.T())
+}
@@ -0,0 +1,260 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrHeldMessageNotFound is returned when approving or rejecting a message that is not held, because it was already
+// decided or has expired.
+var ErrHeldMessageNotFound = errors.New("held message not found")
+
+// ApprovalAction is the event of an ApprovalRecord.
+type ApprovalAction string
+
+const (
+	ApprovalHeld     ApprovalAction = "HELD"
+	ApprovalApproved ApprovalAction = "APPROVED"
+	ApprovalRejected ApprovalAction = "REJECTED"
+	ApprovalExpired  ApprovalAction = "EXPIRED"
+)
+
+// HeldMessage is an outbound message awaiting approval before being sent, see ApprovalMsgTypes.
+type HeldMessage struct {
+	ID      string
+	MsgType string
+	Message *Message
+	HeldAt  time.Time
+
+	// Expires is when the message is discarded if not approved, zero without ApprovalTimeout.
+	Expires time.Time
+}
+
+// ApprovalRecord is an audit record of a held message.
+type ApprovalRecord struct {
+	SessionID SessionID
+	ID        string
+	MsgType   string
+	Action    ApprovalAction
+
+	// Actor is the approver or rejecter, empty when held or expired.
+	Actor  string
+	Reason string
+	Time   time.Time
+}
+
+// ApprovalAuditor is an optional interface implemented by an Application to receive the audit records of the
+// messages held for approval, for instance to persist them.
+type ApprovalAuditor interface {
+	OnApprovalAudit(record ApprovalRecord)
+}
+
+// heldMessages is the pending store of the messages held for approval.
+type heldMessages struct {
+	sync.Mutex
+	lastID   int
+	messages map[string]HeldMessage
+}
+
+func (h *heldMessages) add(msg *Message, msgType string, now time.Time, timeout time.Duration) HeldMessage {
+	h.Lock()
+	defer h.Unlock()
+
+	h.lastID++
+	held := HeldMessage{ID: strconv.Itoa(h.lastID), MsgType: msgType, Message: msg, HeldAt: now}
+	if timeout > 0 {
+		held.Expires = now.Add(timeout)
+	}
+
+	if h.messages == nil {
+		h.messages = make(map[string]HeldMessage)
+	}
+	h.messages[held.ID] = held
+	return held
+}
+
+func (h *heldMessages) take(id string) (HeldMessage, bool) {
+	h.Lock()
+	defer h.Unlock()
+	held, ok := h.messages[id]
+	delete(h.messages, id)
+	return held, ok
+}
+
+func (h *heldMessages) put(held HeldMessage) {
+	h.Lock()
+	defer h.Unlock()
+	h.messages[held.ID] = held
+}
+
+// expire removes and returns the messages expired at now.
+func (h *heldMessages) expire(now time.Time) (expired []HeldMessage) {
+	h.Lock()
+	defer h.Unlock()
+	for id, held := range h.messages {
+		if !held.Expires.IsZero() && !now.Before(held.Expires) {
+			expired = append(expired, held)
+			delete(h.messages, id)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].HeldAt.Before(expired[j].HeldAt) })
+	return
+}
+
+// list returns copies of the held messages, oldest first.
+func (h *heldMessages) list() []HeldMessage {
+	h.Lock()
+	defer h.Unlock()
+	list := make([]HeldMessage, 0, len(h.messages))
+	for _, held := range h.messages {
+		msg := NewMessage()
+		held.Message.CopyInto(msg)
+		held.Message = msg
+		list = append(list, held)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, _ := strconv.Atoi(list[i].ID)
+		b, _ := strconv.Atoi(list[j].ID)
+		return a < b
+	})
+	return list
+}
+
+func (h *heldMessages) len() int {
+	h.Lock()
+	defer h.Unlock()
+	return len(h.messages)
+}
+
+// GetHeldMessages returns the messages of the Session matching the Session id awaiting approval, oldest first.
+func GetHeldMessages(sessionID SessionID) ([]HeldMessage, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return session.held.list(), nil
+}
+
+// ApproveMessage sends the held message id of the Session matching the Session id. approver is recorded in the
+// audit record.
+func ApproveMessage(sessionID SessionID, id, approver string) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if err := session.checkCanSend(); err != nil {
+		return err
+	}
+
+	held, ok := session.held.take(id)
+	if !ok {
+		return ErrHeldMessageNotFound
+	}
+
+	if err := session.queueForSend(held.Message); err != nil {
+		session.held.put(held)
+		return err
+	}
+
+	session.auditApproval(held, ApprovalApproved, approver, "")
+	return nil
+}
+
+// RejectMessage discards the held message id of the Session matching the Session id. rejecter and reason are
+// recorded in the audit record.
+func RejectMessage(sessionID SessionID, id, rejecter, reason string) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	held, ok := session.held.take(id)
+	if !ok {
+		return ErrHeldMessageNotFound
+	}
+
+	session.auditApproval(held, ApprovalRejected, rejecter, reason)
+	return nil
+}
+
+// requiresApproval returns whether msg, sent by the application, must be held for approval.
+func (s *Session) requiresApproval(msg *Message) bool {
+	if len(s.ApprovalMsgTypes) == 0 {
+		return false
+	}
+
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil || isAdminMessageType(msgType) {
+		return false
+	}
+	return s.ApprovalMsgTypes["*"] || s.ApprovalMsgTypes[string(msgType)]
+}
+
+// hold parks msg until it is approved, rejected or expires.
+func (s *Session) hold(msg *Message) error {
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil {
+		return err
+	}
+
+	parked := NewMessage()
+	msg.CopyInto(parked)
+
+	held := s.held.add(parked, string(msgType), time.Now(), s.ApprovalTimeout)
+	s.auditApproval(held, ApprovalHeld, "", "")
+	return nil
+}
+
+// checkHeldTimeouts discards the held messages not approved within ApprovalTimeout.
+func (s *Session) checkHeldTimeouts(now time.Time) {
+	if s.ApprovalTimeout <= 0 {
+		return
+	}
+
+	for _, held := range s.held.expire(now) {
+		s.auditApproval(held, ApprovalExpired, "", "")
+	}
+}
+
+func (s *Session) auditApproval(held HeldMessage, action ApprovalAction, actor, reason string) {
+	record := ApprovalRecord{
+		SessionID: s.sessionID,
+		ID:        held.ID,
+		MsgType:   held.MsgType,
+		Action:    action,
+		Actor:     actor,
+		Reason:    reason,
+		Time:      time.Now(),
+	}
+
+	switch {
+	case actor != "" && reason != "":
+		s.log.OnEventf("Held message %v (MsgType %v) %v by %v: %v", held.ID, held.MsgType, action, actor, reason)
+	case actor != "":
+		s.log.OnEventf("Held message %v (MsgType %v) %v by %v", held.ID, held.MsgType, action, actor)
+	default:
+		s.log.OnEventf("Held message %v (MsgType %v) %v", held.ID, held.MsgType, action)
+	}
+
+	if auditor, ok := s.application.(ApprovalAuditor); ok {
+		auditor.OnApprovalAudit(record)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ApprovalTestSuite struct {
+	SessionSuiteRig
+	records []ApprovalRecord
+}
+
+func TestApprovalTestSuite(t *testing.T) {
+	suite.Run(t, new(ApprovalTestSuite))
+}
+
+type approvalAuditorApp struct {
+	*MockApp
+	suite *ApprovalTestSuite
+}
+
+func (a *approvalAuditorApp) OnApprovalAudit(record ApprovalRecord) {
+	a.suite.records = append(a.suite.records, record)
+}
+
+func (s *ApprovalTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.ApprovalMsgTypes = map[string]bool{"D": true}
+	s.Session.application = &approvalAuditorApp{MockApp: &s.MockApp, suite: s}
+	s.records = nil
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *ApprovalTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *ApprovalTestSuite) sendOrder(msgType, clOrdID string) {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString(msgType))
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	s.Require().Nil(s.Session.SendToTarget(msg))
+}
+
+func (s *ApprovalTestSuite) actions() (actions []ApprovalAction) {
+	for _, record := range s.records {
+		actions = append(actions, record.Action)
+	}
+	return
+}
+
+func (s *ApprovalTestSuite) TestApprove() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.sendOrder("D", "order1")
+	s.sendOrder("F", "cancel1")
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 1)
+	s.FieldEquals(tagClOrdID, "cancel1", s.MockApp.lastToApp.Body)
+	s.NextSenderMsgSeqNum(2)
+
+	held, err := GetHeldMessages(s.sessionID)
+	s.Require().Nil(err)
+	s.Require().Len(held, 1)
+	s.Equal("D", held[0].MsgType)
+	s.True(held[0].Expires.IsZero())
+	s.FieldEquals(tagClOrdID, "order1", held[0].Message.Body)
+	s.Equal(1, s.Session.status().HeldMessages)
+
+	s.Nil(ApproveMessage(s.sessionID, held[0].ID, "alice"))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 2)
+	s.FieldEquals(tagClOrdID, "order1", s.MockApp.lastToApp.Body)
+	s.NextSenderMsgSeqNum(3)
+	s.Equal(0, s.Session.status().HeldMessages)
+
+	s.ErrorIs(ApproveMessage(s.sessionID, held[0].ID, "alice"), ErrHeldMessageNotFound)
+	s.Equal([]ApprovalAction{ApprovalHeld, ApprovalApproved}, s.actions())
+	s.Equal("alice", s.records[1].Actor)
+	s.Equal(s.sessionID, s.records[1].SessionID)
+}
+
+func (s *ApprovalTestSuite) TestReject() {
+	s.sendOrder("D", "order1")
+	held, err := GetHeldMessages(s.sessionID)
+	s.Require().Nil(err)
+	s.Require().Len(held, 1)
+
+	s.Nil(RejectMessage(s.sessionID, held[0].ID, "bob", "too large"))
+	s.MockApp.AssertNotCalled(s.T(), "ToApp")
+	s.NextSenderMsgSeqNum(1)
+	s.ErrorIs(RejectMessage(s.sessionID, held[0].ID, "bob", "too large"), ErrHeldMessageNotFound)
+
+	s.Equal([]ApprovalAction{ApprovalHeld, ApprovalRejected}, s.actions())
+	s.Equal("bob", s.records[1].Actor)
+	s.Equal("too large", s.records[1].Reason)
+}
+
+func (s *ApprovalTestSuite) TestHoldAll() {
+	s.Session.ApprovalMsgTypes = map[string]bool{"*": true}
+	s.sendOrder("D", "order1")
+	s.sendOrder("F", "cancel1")
+
+	held, err := GetHeldMessages(s.sessionID)
+	s.Require().Nil(err)
+	s.Require().Len(held, 2)
+	s.Equal("D", held[0].MsgType)
+	s.Equal("F", held[1].MsgType)
+	s.MockApp.AssertNotCalled(s.T(), "ToApp")
+}
+
+func (s *ApprovalTestSuite) TestExpire() {
+	s.Session.ApprovalTimeout = time.Minute
+	s.sendOrder("D", "order1")
+
+	s.Session.checkHeldTimeouts(time.Now())
+	s.Equal(1, s.Session.status().HeldMessages)
+
+	s.Session.checkHeldTimeouts(time.Now().Add(time.Minute))
+	s.Equal(0, s.Session.status().HeldMessages)
+	s.Equal([]ApprovalAction{ApprovalHeld, ApprovalExpired}, s.actions())
+}
+
+func (s *ApprovalTestSuite) TestUnknownSession() {
+	other := SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}
+	_, err := GetHeldMessages(other)
+	s.ErrorIs(err, ErrSessionNotFound)
+	s.ErrorIs(ApproveMessage(other, "1", "alice"), ErrSessionNotFound)
+	s.ErrorIs(RejectMessage(other, "1", "alice", ""), ErrSessionNotFound)
+}
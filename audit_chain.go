@@ -0,0 +1,171 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// errAuditChainDisabled is returned by the audit chain API of a session without AuditChain.
+var errAuditChainDisabled = errors.New("AuditChain is not enabled for the session")
+
+// AuditRecord is the record of an inbound message in the audit hash chain of a session, see AuditChain.
+type AuditRecord struct {
+	// MsgSeqNum is zero for a message that could not be parsed.
+	MsgSeqNum int `json:"msg_seq_num"`
+
+	// RawMessage is the message as received.
+	RawMessage string    `json:"raw_message"`
+	ReceivedAt time.Time `json:"received_at"`
+
+	// PrevHash is the Hash of the previous record, empty for the first record of the chain. Hash is the hex encoded
+	// SHA-256 of PrevHash followed by RawMessage.
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// AuditStore is an optional interface implemented by a MessageStore to keep the audit hash chain, see AuditChain.
+// The chain is kept when the store is reset.
+type AuditStore interface {
+	// SaveAuditRecord appends record to the chain.
+	SaveAuditRecord(record AuditRecord) error
+
+	// AuditRecords returns the chain, oldest first.
+	AuditRecords() ([]AuditRecord, error)
+}
+
+// ErrAuditChainBroken is returned by VerifyAuditChain for the first record of a chain that was altered, removed or
+// reordered, use errors.As to retrieve the details.
+type ErrAuditChainBroken struct {
+	// Index is the position of the record in the chain.
+	Index  int
+	Record AuditRecord
+	Reason string
+}
+
+func (e ErrAuditChainBroken) Error() string {
+	return fmt.Sprintf("audit chain broken at record %d (MsgSeqNum %d): %s", e.Index, e.Record.MsgSeqNum, e.Reason)
+}
+
+// auditHash returns the Hash of the record of rawMessage following the record of prevHash.
+func auditHash(prevHash string, rawMessage []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write(rawMessage)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyAuditRecords checks that records form an unbroken audit hash chain, each record hashing its message and
+// the Hash of its predecessor. It returns an ErrAuditChainBroken for the first record failing the check.
+func VerifyAuditRecords(records []AuditRecord) error {
+	prevHash := ""
+	for i, record := range records {
+		if record.PrevHash != prevHash {
+			return ErrAuditChainBroken{Index: i, Record: record, Reason: "PrevHash does not match the previous record"}
+		}
+		if record.Hash != auditHash(prevHash, []byte(record.RawMessage)) {
+			return ErrAuditChainBroken{Index: i, Record: record, Reason: "Hash does not match the message"}
+		}
+		prevHash = record.Hash
+	}
+	return nil
+}
+
+// auditStoreProvider is implemented by a MessageStore wrapping another, such as the stores of
+// NewEncryptedMessageStoreFactory, to provide the AuditStore of the wrapped store when it has one.
+type auditStoreProvider interface {
+	auditStore() (AuditStore, bool)
+}
+
+// auditStoreOf returns the AuditStore of store, if any.
+func auditStoreOf(store MessageStore) (AuditStore, bool) {
+	if provider, ok := store.(auditStoreProvider); ok {
+		return provider.auditStore()
+	}
+	auditStore, ok := store.(AuditStore)
+	return auditStore, ok
+}
+
+// GetAuditRecords returns the audit hash chain of the Session matching the Session id, oldest first.
+func GetAuditRecords(sessionID SessionID) ([]AuditRecord, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if session.auditStore == nil {
+		return nil, errAuditChainDisabled
+	}
+	return session.auditStore.AuditRecords()
+}
+
+// VerifyAuditChain verifies the audit hash chain of the Session matching the Session id, see VerifyAuditRecords.
+func VerifyAuditChain(sessionID SessionID) error {
+	records, err := GetAuditRecords(sessionID)
+	if err != nil {
+		return err
+	}
+	return VerifyAuditRecords(records)
+}
+
+// ExportAuditRecords writes the audit hash chain of the Session matching the Session id to w as JSON, one record
+// per line.
+func ExportAuditRecords(sessionID SessionID, w io.Writer) error {
+	records, err := GetAuditRecords(sessionID)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditIncoming appends the message received as rawMessage to the audit hash chain. msg is nil if rawMessage could
+// not be parsed.
+func (s *Session) auditIncoming(rawMessage []byte, msg *Message, receivedAt time.Time) {
+	if s.auditStore == nil {
+		return
+	}
+
+	record := AuditRecord{
+		RawMessage: string(rawMessage),
+		ReceivedAt: receivedAt,
+		PrevHash:   s.lastAuditHash,
+		Hash:       auditHash(s.lastAuditHash, rawMessage),
+	}
+	if msg != nil {
+		record.MsgSeqNum, _ = msg.Header.GetInt(tagMsgSeqNum)
+	}
+	if record.ReceivedAt.IsZero() {
+		record.ReceivedAt = s.now()
+	}
+
+	if err := s.auditStore.SaveAuditRecord(record); err != nil {
+		s.log.OnEventf("Failed to save audit record: %v", err)
+		return
+	}
+	s.lastAuditHash = record.Hash
+}
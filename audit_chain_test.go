@@ -0,0 +1,126 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditChainTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestAuditChainTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditChainTestSuite))
+}
+
+func (s *AuditChainTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.auditStore = &s.MockStore
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *AuditChainTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+// receive passes the raw messages to the session, as read from the connection.
+func (s *AuditChainTestSuite) receive(rawMessages ...[]byte) {
+	var batch []fixIn
+	for _, raw := range rawMessages {
+		batch = append(batch, fixIn{bytes: bytes.NewBuffer(raw), receiveTime: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)})
+	}
+	s.Session.incomingBatch(batch)
+}
+
+func (s *AuditChainTestSuite) TestChain() {
+	s.MockApp.On("FromApp").Return(nil)
+	order := s.NewOrderSingle().Build()
+	heartbeat := s.Heartbeat().Build()
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.receive(order, heartbeat, []byte("garbled"))
+
+	records, err := GetAuditRecords(s.sessionID)
+	s.Require().Nil(err)
+	s.Require().Len(records, 3)
+
+	s.Equal(1, records[0].MsgSeqNum)
+	s.Equal(string(order), records[0].RawMessage)
+	s.Equal(time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), records[0].ReceivedAt)
+	s.Empty(records[0].PrevHash)
+	s.Equal(auditHash("", order), records[0].Hash)
+
+	s.Equal(2, records[1].MsgSeqNum)
+	s.Equal(records[0].Hash, records[1].PrevHash)
+
+	s.Equal(0, records[2].MsgSeqNum, "garbled messages are recorded too")
+	s.Equal("garbled", records[2].RawMessage)
+	s.Equal(records[2].Hash, s.Session.lastAuditHash)
+
+	s.Nil(VerifyAuditChain(s.sessionID))
+}
+
+func (s *AuditChainTestSuite) TestTampering() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.receive(s.NewOrderSingle().Build(), s.NewOrderSingle().Build(), s.NewOrderSingle().Build())
+	records, err := GetAuditRecords(s.sessionID)
+	s.Require().Nil(err)
+
+	altered := append([]AuditRecord(nil), records...)
+	altered[1].RawMessage = string(bytes.Replace([]byte(altered[1].RawMessage), []byte("34=2"), []byte("34=9"), 1))
+	var broken ErrAuditChainBroken
+	s.Require().True(errors.As(VerifyAuditRecords(altered), &broken))
+	s.Equal(1, broken.Index)
+
+	removed := []AuditRecord{records[0], records[2]}
+	s.Require().True(errors.As(VerifyAuditRecords(removed), &broken))
+	s.Equal(1, broken.Index)
+	s.Equal(3, broken.Record.MsgSeqNum)
+
+	s.Nil(VerifyAuditRecords(records))
+}
+
+func (s *AuditChainTestSuite) TestExport() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.receive(s.NewOrderSingle().Build(), s.NewOrderSingle().Build())
+
+	var buf bytes.Buffer
+	s.Require().Nil(ExportAuditRecords(s.sessionID, &buf))
+
+	dec := json.NewDecoder(&buf)
+	var exported []AuditRecord
+	for dec.More() {
+		var record AuditRecord
+		s.Require().Nil(dec.Decode(&record))
+		exported = append(exported, record)
+	}
+	s.Require().Len(exported, 2)
+	s.Nil(VerifyAuditRecords(exported))
+}
+
+func (s *AuditChainTestSuite) TestDisabled() {
+	s.Session.auditStore = nil
+	_, err := GetAuditRecords(s.sessionID)
+	s.Equal(errAuditChainDisabled, err)
+	s.Equal(ErrSessionNotFound, VerifyAuditChain(SessionID{BeginString: "FIX.4.2", SenderCompID: "unknown"}))
+}
@@ -0,0 +1,62 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// BeginStringMismatchAction is the action taken by a logged on session on receipt of a message whose BeginString
+// does not match the session.
+type BeginStringMismatchAction int
+
+const (
+	// BeginStringMismatchLogout sends a Logout and disconnects.
+	BeginStringMismatchLogout BeginStringMismatchAction = iota
+
+	// BeginStringMismatchDisconnect disconnects immediately, without sending a Logout.
+	BeginStringMismatchDisconnect
+
+	// BeginStringMismatchReject rejects the message and continues the session.
+	BeginStringMismatchReject
+)
+
+func (a BeginStringMismatchAction) String() string {
+	switch a {
+	case BeginStringMismatchDisconnect:
+		return "disconnecting"
+	case BeginStringMismatchReject:
+		return "rejecting message"
+	default:
+		return "logging out"
+	}
+}
+
+// BeginStringMismatchHandler is an optional interface implemented by an Application to decide how a message with a
+// mismatched BeginString is handled, used when BeginStringMismatchPolicy is set to CALLBACK.
+type BeginStringMismatchHandler interface {
+	// OnBeginStringMismatch is called with the offending message and its BeginString.
+	OnBeginStringMismatch(beginString string, msg *Message, sessionID SessionID) BeginStringMismatchAction
+}
+
+// beginStringMismatchPolicy is the configured BeginStringMismatchPolicy, handler is set for CALLBACK.
+type beginStringMismatchPolicy struct {
+	action  BeginStringMismatchAction
+	handler BeginStringMismatchHandler
+}
+
+func (s *Session) beginStringMismatchAction(msg *Message, rej incorrectBeginString) BeginStringMismatchAction {
+	if s.beginStringMismatch.handler == nil {
+		return s.beginStringMismatch.action
+	}
+	return s.beginStringMismatch.handler.OnBeginStringMismatch(rej.Received, msg, s.sessionID)
+}
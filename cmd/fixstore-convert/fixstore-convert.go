@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/store/file"
+	"github.com/quickfixgo/quickfix/store/mongo"
+	"github.com/quickfixgo/quickfix/store/redis"
+	"github.com/quickfixgo/quickfix/store/sql"
+)
+
+// backends are the store backends that can be converted between, by name.
+var backends = map[string]func(*quickfix.Settings) quickfix.MessageStoreFactory{
+	"file":  file.NewStoreFactory,
+	"sql":   sql.NewStoreFactory,
+	"mongo": mongo.NewStoreFactory,
+	"redis": redis.NewStoreFactory,
+}
+
+var (
+	from      = flag.String("from", "", "source store backend: "+backendNames())
+	to        = flag.String("to", "", "destination store backend: "+backendNames())
+	session   = flag.String("session", "", "only convert the session with this SessionID, e.g. FIX.4.4:SENDER->TARGET")
+	overwrite = flag.Bool("overwrite", false, "reset destination stores that are not empty instead of failing")
+)
+
+func backendNames() string {
+	var names []string
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %v -from <backend> -to <backend> [flags] <source settings file> <destination settings file>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Copies the sequence numbers and the message log of the sessions configured in the source settings file\n")
+	fmt.Fprintf(os.Stderr, "to the destination store, then verifies the copy. The destination settings file must configure the same sessions.\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func loadStoreFactory(backend, settingsPath string) (*quickfix.Settings, quickfix.MessageStoreFactory) {
+	newFactory, ok := backends[backend]
+	if !ok {
+		log.Fatalf("Unknown store backend %q, expected one of %v", backend, backendNames())
+	}
+
+	cfg, err := os.Open(settingsPath)
+	if err != nil {
+		log.Fatalf("Error opening %v: %v", settingsPath, err)
+	}
+	defer func() { _ = cfg.Close() }()
+
+	settings, err := quickfix.ParseSettings(cfg)
+	if err != nil {
+		log.Fatalf("Error reading %v: %v", settingsPath, err)
+	}
+	return settings, newFactory(settings)
+}
+
+// sessionIDs returns the configured sessions selected by the -session flag, sorted.
+func sessionIDs(settings *quickfix.Settings) []quickfix.SessionID {
+	var ids []quickfix.SessionID
+	for sessionID := range settings.SessionSettings() {
+		if *session == "" || sessionID.String() == *session {
+			ids = append(ids, sessionID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+// errStop stops an iteration early.
+var errStop = errors.New("stop")
+
+// isEmpty returns true if store holds no message and its sequence numbers were never incremented.
+func isEmpty(store quickfix.MessageStore) (bool, error) {
+	if store.NextSenderMsgSeqNum() != 1 || store.NextTargetMsgSeqNum() != 1 {
+		return false, nil
+	}
+
+	empty := true
+	err := store.IterateMessages(1, math.MaxInt32, func([]byte) error {
+		empty = false
+		return errStop
+	})
+	if errors.Is(err, errStop) {
+		err = nil
+	}
+	return empty, err
+}
+
+// storedMessage is a message of the log with the MsgSeqNum it is stored under.
+type storedMessage struct {
+	seqNum int
+	msg    []byte
+}
+
+// readMessages returns the message log of store in store order.
+func readMessages(store quickfix.MessageStore) ([]storedMessage, error) {
+	var msgs []storedMessage
+	err := store.IterateMessages(1, math.MaxInt32, func(msgBytes []byte) error {
+		msg := quickfix.NewMessage()
+		if err := quickfix.ParseMessage(msg, bytes.NewBuffer(append([]byte(nil), msgBytes...))); err != nil {
+			return fmt.Errorf("unable to parse message %v of the log: %w", len(msgs)+1, err)
+		}
+		seqNum, err := msg.Header.GetInt(quickfix.Tag(34))
+		if err != nil {
+			return fmt.Errorf("message %v of the log has no MsgSeqNum: %w", len(msgs)+1, err)
+		}
+
+		msgs = append(msgs, storedMessage{seqNum: seqNum, msg: append([]byte(nil), msgBytes...)})
+		return nil
+	})
+	return msgs, err
+}
+
+// convert copies the state of src to dst, returning the number of messages copied. The creation time of dst is
+// set to that of src for the backends supporting it, the others keep the time of the conversion.
+func convert(src, dst quickfix.MessageStore) (int, error) {
+	empty, err := isEmpty(dst)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read destination: %w", err)
+	}
+	if !empty {
+		if !*overwrite {
+			return 0, fmt.Errorf("destination store is not empty, use -overwrite to reset it")
+		}
+		if err := dst.Reset(); err != nil {
+			return 0, fmt.Errorf("unable to reset destination: %w", err)
+		}
+	}
+
+	msgs, err := readMessages(src)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read source: %w", err)
+	}
+
+	for _, m := range msgs {
+		if err := dst.SaveMessage(m.seqNum, m.msg); err != nil {
+			return 0, fmt.Errorf("unable to save message %v: %w", m.seqNum, err)
+		}
+	}
+	if err := dst.SetNextSenderMsgSeqNum(src.NextSenderMsgSeqNum()); err != nil {
+		return 0, fmt.Errorf("unable to set NextSenderMsgSeqNum: %w", err)
+	}
+	if err := dst.SetNextTargetMsgSeqNum(src.NextTargetMsgSeqNum()); err != nil {
+		return 0, fmt.Errorf("unable to set NextTargetMsgSeqNum: %w", err)
+	}
+	dst.SetCreationTime(src.CreationTime())
+
+	return len(msgs), verifyCopy(msgs, src, dst)
+}
+
+// verifyCopy checks that dst, reloaded from its backend, holds the sequence numbers of src and the messages msgs.
+func verifyCopy(msgs []storedMessage, src, dst quickfix.MessageStore) error {
+	if err := dst.Refresh(); err != nil {
+		return fmt.Errorf("unable to reload destination: %w", err)
+	}
+
+	if next := dst.NextSenderMsgSeqNum(); next != src.NextSenderMsgSeqNum() {
+		return fmt.Errorf("verification failed: NextSenderMsgSeqNum is %v, expected %v", next, src.NextSenderMsgSeqNum())
+	}
+	if next := dst.NextTargetMsgSeqNum(); next != src.NextTargetMsgSeqNum() {
+		return fmt.Errorf("verification failed: NextTargetMsgSeqNum is %v, expected %v", next, src.NextTargetMsgSeqNum())
+	}
+
+	// Backends may order the log by MsgSeqNum rather than by insertion.
+	expected := make(map[int][][]byte)
+	for _, m := range msgs {
+		expected[m.seqNum] = append(expected[m.seqNum], m.msg)
+	}
+	copied, err := readMessages(dst)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	for _, m := range copied {
+		candidates := expected[m.seqNum]
+		i := 0
+		for i < len(candidates) && !bytes.Equal(candidates[i], m.msg) {
+			i++
+		}
+		if i == len(candidates) {
+			return fmt.Errorf("verification failed: unexpected message %v in destination", m.seqNum)
+		}
+		expected[m.seqNum] = append(candidates[:i], candidates[i+1:]...)
+	}
+	if len(copied) != len(msgs) {
+		return fmt.Errorf("verification failed: destination holds %v messages, expected %v", len(copied), len(msgs))
+	}
+	return nil
+}
+
+func convertSession(srcFactory, dstFactory quickfix.MessageStoreFactory, sessionID quickfix.SessionID) (int, error) {
+	src, err := srcFactory.Create(sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open source store: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := dstFactory.Create(sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open destination store: %w", err)
+	}
+	defer func() { _ = dst.Close() }()
+
+	return convert(src, dst)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 2 || *from == "" || *to == "" {
+		usage()
+	}
+
+	srcSettings, srcFactory := loadStoreFactory(*from, flag.Arg(0))
+	_, dstFactory := loadStoreFactory(*to, flag.Arg(1))
+
+	ids := sessionIDs(srcSettings)
+	if len(ids) == 0 {
+		log.Fatalf("No session matching %q", *session)
+	}
+
+	failed := false
+	for _, sessionID := range ids {
+		n, err := convertSession(srcFactory, dstFactory, sessionID)
+		if err != nil {
+			log.Printf("%v: %v", sessionID, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%v: copied %v message(s)\n", sessionID, n)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/store/file"
+	"github.com/quickfixgo/quickfix/store/mongo"
+	"github.com/quickfixgo/quickfix/store/redis"
+	"github.com/quickfixgo/quickfix/store/sql"
+)
+
+var (
+	storeType = flag.String("store", "file", "store backend configured in the settings: file, sql, mongo or redis")
+	session   = flag.String("session", "", "only verify the session with this SessionID, e.g. FIX.4.4:SENDER->TARGET")
+	doRepair  = flag.Bool("repair", false, "repair the repairable findings")
+	export    = flag.String("export", "", "export the findings as JSON to this file, - for stdout")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %v [flags] <path to settings file>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Verifies the message stores of the sessions configured in the settings file.\n")
+	fmt.Fprintf(os.Stderr, "Exits with status 1 if any error finding is left unrepaired.\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func newStoreFactory(settings *quickfix.Settings) (quickfix.MessageStoreFactory, error) {
+	switch *storeType {
+	case "file":
+		return file.NewStoreFactory(settings), nil
+	case "sql":
+		return sql.NewStoreFactory(settings), nil
+	case "mongo":
+		return mongo.NewStoreFactory(settings), nil
+	case "redis":
+		return redis.NewStoreFactory(settings), nil
+	}
+	return nil, fmt.Errorf("unknown store %q", *storeType)
+}
+
+// sessionIDs returns the configured sessions selected by the -session flag, sorted.
+func sessionIDs(settings *quickfix.Settings) []quickfix.SessionID {
+	var ids []quickfix.SessionID
+	for sessionID := range settings.SessionSettings() {
+		if *session == "" || sessionID.String() == *session {
+			ids = append(ids, sessionID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+func verifySession(factory quickfix.MessageStoreFactory, sessionID quickfix.SessionID) ([]Finding, error) {
+	store, err := factory.Create(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open store: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	findings, err := verify(sessionID, store, time.Now())
+	if err != nil {
+		return findings, err
+	}
+
+	if *doRepair {
+		err = repair(store, findings)
+	}
+	return findings, err
+}
+
+func writeFindings(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		usage()
+	}
+
+	cfg, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Error opening %v: %v", flag.Arg(0), err)
+	}
+	settings, err := quickfix.ParseSettings(cfg)
+	_ = cfg.Close()
+	if err != nil {
+		log.Fatalf("Error reading %v: %v", flag.Arg(0), err)
+	}
+
+	factory, err := newStoreFactory(settings)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ids := sessionIDs(settings)
+	if len(ids) == 0 {
+		log.Fatalf("No session matching %q", *session)
+	}
+
+	var findings []Finding
+	failed := false
+	for _, sessionID := range ids {
+		sessionFindings, err := verifySession(factory, sessionID)
+		findings = append(findings, sessionFindings...)
+		if err != nil {
+			log.Printf("%v: %v", sessionID, err)
+			failed = true
+		}
+	}
+
+	if *export != "-" {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+		fmt.Printf("%v finding(s) in %v session(s)\n", len(findings), len(ids))
+	}
+
+	switch *export {
+	case "":
+	case "-":
+		err = writeFindings(os.Stdout, findings)
+	default:
+		var out *os.File
+		if out, err = os.Create(*export); err == nil {
+			err = writeFindings(out, findings)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	if err != nil {
+		log.Fatalf("Error exporting findings: %v", err)
+	}
+
+	for _, f := range findings {
+		if f.Severity == SeverityError && !f.Repaired {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
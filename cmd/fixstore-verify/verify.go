@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Severity of a finding.
+type Severity string
+
+const (
+	// SeverityWarning findings do not prevent the session from running but may hide lost messages.
+	SeverityWarning Severity = "warning"
+	// SeverityError findings break resends or sequencing if the session is re-enabled as is.
+	SeverityError Severity = "error"
+)
+
+// Kinds of findings.
+const (
+	KindCorruptRecord     = "corrupt-record"
+	KindChecksum          = "checksum"
+	KindWrongSession      = "wrong-session"
+	KindDuplicateSeqNum   = "duplicate-seqnum"
+	KindOutOfOrder        = "out-of-order"
+	KindSeqNumGap         = "seqnum-gap"
+	KindSeqNumAhead       = "seqnum-ahead"
+	KindCreationTimeZero  = "creation-time-zero"
+	KindCreationTimeAhead = "creation-time-future"
+	KindCreationTimeLate  = "creation-time-after-message"
+)
+
+// Finding is an inconsistency found in the store of a session.
+type Finding struct {
+	SessionID string   `json:"session_id"`
+	Kind      string   `json:"kind"`
+	Severity  Severity `json:"severity"`
+	// SeqNum is the MsgSeqNum of the message concerned, 0 if the finding is not about a single message.
+	SeqNum     int    `json:"seqnum,omitempty"`
+	Detail     string `json:"detail"`
+	Repairable bool   `json:"repairable"`
+	Repaired   bool   `json:"repaired"`
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%v: %v %v", f.SessionID, f.Severity, f.Kind)
+	if f.SeqNum != 0 {
+		s += fmt.Sprintf(" at %v", f.SeqNum)
+	}
+	s += ": " + f.Detail
+	if f.Repaired {
+		s += " (repaired)"
+	}
+	return s
+}
+
+// verifier checks the store of a single session.
+type verifier struct {
+	sessionID quickfix.SessionID
+	findings  []Finding
+}
+
+func (v *verifier) report(kind string, severity Severity, seqNum int, repairable bool, format string, a ...interface{}) {
+	v.findings = append(v.findings, Finding{
+		SessionID:  v.sessionID.String(),
+		Kind:       kind,
+		Severity:   severity,
+		SeqNum:     seqNum,
+		Detail:     fmt.Sprintf(format, a...),
+		Repairable: repairable,
+	})
+}
+
+func (v *verifier) reportGap(begin, end int) {
+	if begin == end {
+		v.report(KindSeqNumGap, SeverityWarning, begin, false, "message is not stored")
+		return
+	}
+	v.report(KindSeqNumGap, SeverityWarning, begin, false, "messages %v to %v are not stored", begin, end)
+}
+
+// verify scans store for inconsistencies. The outgoing messages are walked in store order, so the gaps reported
+// are those between consecutive records.
+func verify(sessionID quickfix.SessionID, store quickfix.MessageStore, now time.Time) ([]Finding, error) {
+	v := &verifier{sessionID: sessionID}
+
+	creationTime := store.CreationTime()
+	switch {
+	case creationTime.IsZero():
+		v.report(KindCreationTimeZero, SeverityWarning, 0, false, "store creation time is not set")
+	case creationTime.After(now):
+		v.report(KindCreationTimeAhead, SeverityWarning, 0, false, "store creation time %v is in the future", creationTime.UTC())
+	}
+
+	index, lastSeqNum, maxSeqNum := 0, 0, 0
+	seen := make(map[int]bool)
+	err := store.IterateMessages(1, math.MaxInt32, func(msgBytes []byte) error {
+		index++
+		seqNum, ok := v.checkRecord(index, msgBytes, creationTime)
+		if !ok {
+			return nil
+		}
+
+		switch {
+		case seen[seqNum]:
+			v.report(KindDuplicateSeqNum, SeverityError, seqNum, false, "message stored more than once")
+		case seqNum < lastSeqNum:
+			v.report(KindOutOfOrder, SeverityWarning, seqNum, false, "stored after %v", lastSeqNum)
+		case lastSeqNum != 0 && seqNum > lastSeqNum+1:
+			v.reportGap(lastSeqNum+1, seqNum-1)
+		}
+		seen[seqNum] = true
+		lastSeqNum = seqNum
+		if seqNum > maxSeqNum {
+			maxSeqNum = seqNum
+		}
+		return nil
+	})
+	if err != nil {
+		return v.findings, fmt.Errorf("unable to iterate messages: %w", err)
+	}
+
+	next := store.NextSenderMsgSeqNum()
+	switch {
+	case maxSeqNum >= next:
+		v.report(KindSeqNumAhead, SeverityError, maxSeqNum, true,
+			"stored message ahead of NextSenderMsgSeqNum %v, the next message would reuse its MsgSeqNum", next)
+	case maxSeqNum != 0 && maxSeqNum < next-1:
+		v.reportGap(maxSeqNum+1, next-1)
+	}
+
+	return v.findings, nil
+}
+
+// checkRecord checks the index-th stored record, returning its MsgSeqNum if the record could be parsed.
+func (v *verifier) checkRecord(index int, msgBytes []byte, creationTime time.Time) (int, bool) {
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBuffer(append([]byte(nil), msgBytes...))); err != nil {
+		v.report(KindCorruptRecord, SeverityError, 0, false, "record %v cannot be parsed: %v", index, err)
+		return 0, false
+	}
+
+	seqNum, err := msg.Header.GetInt(quickfix.Tag(34))
+	if err != nil {
+		v.report(KindCorruptRecord, SeverityError, 0, false, "record %v has no MsgSeqNum: %v", index, err)
+		return 0, false
+	}
+
+	if err := verifyChecksum(msgBytes); err != nil {
+		v.report(KindChecksum, SeverityError, seqNum, false, "%v", err)
+	}
+
+	// Stored messages are outgoing, so sent by this side of the session.
+	sender, _ := msg.Header.GetString(quickfix.Tag(49))
+	target, _ := msg.Header.GetString(quickfix.Tag(56))
+	if sender != v.sessionID.SenderCompID || target != v.sessionID.TargetCompID {
+		v.report(KindWrongSession, SeverityError, seqNum, false, "sent from %v to %v", sender, target)
+	}
+
+	// A message sent before the store was created should have been removed by its reset. The store creation time
+	// may be truncated to the second.
+	sendingTime, err := msg.Header.GetTime(quickfix.Tag(52))
+	if err == nil && !creationTime.IsZero() && sendingTime.Add(time.Second).Before(creationTime) {
+		v.report(KindCreationTimeLate, SeverityWarning, seqNum, false,
+			"sent at %v, before the store creation time %v", sendingTime.UTC(), creationTime.UTC())
+	}
+
+	return seqNum, true
+}
+
+// verifyChecksum checks the CheckSum trailer field of msgBytes.
+func verifyChecksum(msgBytes []byte) error {
+	i := bytes.LastIndex(msgBytes, []byte("\x0110="))
+	if i < 0 {
+		return fmt.Errorf("no CheckSum")
+	}
+
+	value := msgBytes[i+len("\x0110="):]
+	value = bytes.TrimSuffix(value, []byte("\x01"))
+	expected, err := strconv.Atoi(string(value))
+	if err != nil {
+		return fmt.Errorf("invalid CheckSum %q", value)
+	}
+
+	sum := 0
+	for _, b := range msgBytes[:i+1] {
+		sum += int(b)
+	}
+	if sum%256 != expected {
+		return fmt.Errorf("CheckSum %03d does not match the computed %03d", expected, sum%256)
+	}
+	return nil
+}
+
+// repair fixes the repairable findings of store, marking them as repaired.
+func repair(store quickfix.MessageStore, findings []Finding) error {
+	for i, f := range findings {
+		if !f.Repairable {
+			continue
+		}
+
+		switch f.Kind {
+		case KindSeqNumAhead:
+			if err := store.SetNextSenderMsgSeqNum(f.SeqNum + 1); err != nil {
+				return fmt.Errorf("unable to set NextSenderMsgSeqNum: %w", err)
+			}
+		default:
+			continue
+		}
+		findings[i].Repaired = true
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ var (
 
 func usage() {
 	fmt.Fprintf(os.Stderr, "usage: %v [flags] <path to data dictionary> ... \n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Dictionaries of the same FIX version are merged, each overlaying the ones before it.\n")
 	flag.PrintDefaults()
 	os.Exit(2)
 }
@@ -134,12 +135,25 @@ func main() {
 		}
 	}
 	specs := []*datadictionary.DataDictionary{}
+	specIndex := make(map[string]int)
 
 	for _, dataDictPath := range args {
 		spec, err := datadictionary.Parse(dataDictPath)
 		if err != nil {
 			log.Fatalf("Error Parsing %v: %v", dataDictPath, err)
 		}
+
+		// A dictionary of a version given already, such as the custom fields of a venue, overlays it.
+		pkg := getPackageName(spec)
+		if i, ok := specIndex[pkg]; ok {
+			var conflicts []datadictionary.Conflict
+			specs[i], conflicts = datadictionary.Merge(specs[i], spec)
+			for _, c := range conflicts {
+				log.Printf("Merging %v: %v", dataDictPath, c)
+			}
+			continue
+		}
+		specIndex[pkg] = len(specs)
 		specs = append(specs, spec)
 	}
 
@@ -15,6 +15,7 @@ var (
 	useFloat    = flag.Bool("use-float", false, "By default, FIX float fields are represented as arbitrary-precision fixed-point decimal numbers.  Set to 'true' to instead generate FIX float fields as float64 values.")
 	useUDecimal = flag.Bool("use-udecimal", false, "By default, FIX uses the shopspring/decimal library for fixed-point decimal numbers.  Set to 'true' to instead use the quagmt/udecimal library.")
 	pkgRoot     = flag.String("pkg-root", "github.com/quickfixgo", "Set a string here to provide a custom import path for generated packages.")
+	typedGet    = flag.Bool("typed-getters", false, "By default, field getters parse the field into its field type, which allocates on each call.  Set to 'true' to instead generate getters reading the value with the typed FieldMap getters, such as GetString and GetInt.")
 	tabWidth    = 8
 	printerMode = printer.UseSpaces | printer.TabIndent
 )
@@ -213,6 +213,30 @@ func quickfixValueType(quickfixType string) (goType string, err error) {
 	return
 }
 
+// typedGetter returns the FieldMap getter reading values of quickfixType without parsing into a field, see -typed-getters
+func typedGetter(quickfixType string) (getter string, err error) {
+	switch quickfixType {
+	case "FIXString":
+		getter = "GetString"
+	case "FIXBoolean":
+		getter = "GetBool"
+	case "FIXInt":
+		getter = "GetInt"
+	case "FIXUTCTimestamp":
+		getter = "GetTime"
+	case "FIXFloat":
+		getter = "GetFloat"
+	case "FIXDecimal":
+		getter = "GetDecimal"
+	case "FIXUDecimal":
+		getter = "GetUDecimal"
+	default:
+		err = fmt.Errorf("Unknown QuickFIX Type: %v", quickfixType)
+	}
+
+	return
+}
+
 func quickfixType(field *datadictionary.FieldType) (quickfixType string, err error) {
 	switch field.Type {
 	case "MULTIPLESTRINGVALUE", "MULTIPLEVALUESTRING":
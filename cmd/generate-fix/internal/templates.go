@@ -3,6 +3,8 @@ package internal
 import (
 	"strings"
 	"text/template"
+
+	"github.com/quickfixgo/quickfix/cmd/internal/fixenum"
 )
 
 var (
@@ -23,6 +25,8 @@ func init() {
 		"importRootPath":                        getImportPathRoot,
 		"quickfixType":                          quickfixType,
 		"quickfixValueType":                     quickfixValueType,
+		"typedGetters":                          func() bool { return *typedGet },
+		"typedGetter":                           typedGetter,
 		"getGlobalFieldType":                    getGlobalFieldType,
 		"collectStandardImports":                collectStandardImports,
 		"collectExtraImports":                   collectExtraImports,
@@ -92,11 +96,21 @@ Get{{ .Name }}() (v udecimal.Decimal, err quickfix.MessageRejectError) {
 {{- else -}}
 Get{{ .Name }}() (v {{ quickfixValueType $bt }}, err quickfix.MessageRejectError) {
 {{- end }}
+{{- if not typedGetters }}
 	var f field.{{ .Name }}Field
 	if err = {{ template "receiver" }}.Get(&f); err == nil {
 		v = f.Value()
 	}
 	return
+{{- else if and $ft.Enums (ne $bt "FIXBoolean") }}
+	var s string
+	if s, err = {{ template "receiver" }}.GetString(tag.{{ .Name }}); err == nil {
+		v = enum.{{ .Name }}(s)
+	}
+	return
+{{- else }}
+	return {{ template "receiver" }}.{{ typedGetter $bt }}(tag.{{ .Name }})
+{{- end }}
 }
 {{- end }}
 
@@ -438,7 +452,7 @@ func (f {{ .Name }}Field) Value() ({{ quickfixValueType $base_type }}) {
 {{ end }}{{ end }}{{ end }}
 `))
 
-	EnumTemplate = template.Must(template.New("Enum").Parse(`
+	EnumTemplate = template.Must(template.New("Enum").Funcs(template.FuncMap{"enumConstName": fixenum.ConstName}).Parse(`
 // Code generated by quickfix. DO NOT EDIT.
 package enum
 {{ range $ft := . }}
@@ -447,7 +461,7 @@ package enum
 type {{ $ft.Name }} string
 const(
 {{ range $ft.Enums }}
-{{ $ft.Name }}_{{ .Description }} {{ $ft.Name }} = "{{ .Value }}"
+{{ enumConstName $ft.Name . }} {{ $ft.Name }} = "{{ .Value }}"
 {{- end }}
 )
 {{ end }}{{ end }}
@@ -0,0 +1,474 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// protoPart is a field, component or repeating group of a message, component or group entry generated with
+// -component-messages
+type protoPart struct {
+	*datadictionary.FieldDef // Set for a field or repeating group
+	Component                *datadictionary.ComponentType
+	Required                 bool
+	version                  *fixVersion
+}
+
+// IsComponent returns true if the part is a component, generated as a field of its own proto message
+func (p protoPart) IsComponent() bool {
+	return p.Component != nil
+}
+
+// IsGroup returns true if the part is a repeating group, generated as a repeated field of its entry proto message
+func (p protoPart) IsGroup() bool {
+	return p.FieldDef != nil && p.FieldDef.IsGroup()
+}
+
+// ProtoName returns the name of the proto field of the part
+func (p protoPart) ProtoName() string {
+	if p.IsComponent() {
+		return sanitizeProtoFieldName(p.Component.Name())
+	}
+	return fieldProtoName(p.FieldDef.Name())
+}
+
+// MessageName returns the proto message of a component or of the entries of a repeating group
+func (p protoPart) MessageName() string {
+	if p.IsComponent() {
+		return p.Component.Name()
+	}
+	return generateGroupMessageName(p.FieldDef)
+}
+
+// Comment returns the comment of the proto field of the part
+func (p protoPart) Comment() string {
+	comment := "Optional"
+	if p.Required {
+		comment = "Required"
+	}
+	switch {
+	case p.IsComponent():
+		return comment + " component"
+	case p.IsGroup():
+		return comment + " group"
+	}
+	return comment + " field"
+}
+
+func (p protoPart) fieldInfo() fieldInfo {
+	return fieldInfo{FieldDef: p.FieldDef, version: p.version}
+}
+
+func (p protoPart) goFieldName() string {
+	return protoFieldNameToGoFieldName(p.ProtoName())
+}
+
+// ToProtoCodes returns the code setting the part of pbMsg from the FIX field map fieldMap
+func (p protoPart) ToProtoCodes() string {
+	fieldName := p.goFieldName()
+	switch {
+	case p.IsComponent():
+		variableName := lowerFirst(fieldName)
+		return fmt.Sprintf(`
+	if hasAnyTag(fieldMap, %s) {
+		%s, err := %sToProto(fieldMap)
+		if err != nil {
+			return nil, err
+		}
+		pbMsg.%s = %s
+	}`, componentTagsVar(p.Component.Name()), variableName, p.MessageName(), fieldName, variableName)
+	case p.IsGroup():
+		variableName := lowerFirst(fieldName)
+		return fmt.Sprintf(`
+	if fieldMap.Has(tag.%s) {
+		%s := quickfix.NewRepeatingGroup(tag.%s, %s())
+		if err := fieldMap.GetGroup(%s); err != nil {
+			return nil, fmt.Errorf("failed to get %s from FIX field map: %%w", err)
+		}
+		for i := 0; i < %s.Len(); i++ {
+			entry, err := %sToProto(&%s.Get(i).FieldMap)
+			if err != nil {
+				return nil, err
+			}
+			pbMsg.%s = append(pbMsg.%s, entry)
+		}
+	}`, p.Name(), variableName, p.Name(), groupTemplateFunc(p.FieldDef), variableName, p.Name(), variableName,
+			p.MessageName(), variableName, fieldName, fieldName)
+	}
+
+	getter, value, convert := p.fieldMapGetter()
+	if convert != "" {
+		return fmt.Sprintf(`
+	if fieldMap.Has(tag.%s) {
+		value, err := fieldMap.%s(tag.%s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s from FIX field map: %%w", err)
+		}
+		if pbMsg.%s, err = %s(value); err != nil {
+			return nil, fmt.Errorf("failed to convert %s to protobuf message: %%w", err)
+		}
+	}`, p.Name(), getter, p.Name(), p.Name(), fieldName, convert, p.Name())
+	}
+	assign := p.fieldInfo().protoAssign(fieldName, value)
+	if enumMap, ok := p.fieldInfo().fromFIXEnumMap(); ok {
+		assign = protoAssignEnum(fieldName, enumMap, value)
+	}
+	return fmt.Sprintf(`
+	if fieldMap.Has(tag.%s) {
+		value, err := fieldMap.%s(tag.%s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s from FIX field map: %%w", err)
+		}
+		%s
+	}`, p.Name(), getter, p.Name(), p.Name(), assign)
+}
+
+// fieldMapGetter returns the FieldMap getter of a field and the conversion of its value, read into value, to the
+// type of its proto field, or to the key of its enum map for an enum. Fallible conversions, such as DecimalToProto,
+// are returned as the convert function instead.
+func (p protoPart) fieldMapGetter() (getter, value, convert string) {
+	info := p.fieldInfo()
+	if _, ok := info.fromFIXEnumMap(); ok {
+		return "GetString", fmt.Sprintf("enum.%s(value)", p.Name()), ""
+	}
+
+	switch p.Type {
+	case "LENGTH":
+		return "GetInt", "uint32(value)", ""
+	case "INT", "SEQNUM", "TAGNUM", "DAYOFMONTH", "NUMINGROUP":
+		return "GetInt", "int32(value)", ""
+	case "AMT", "PERCENTAGE", "PRICE", "QTY", "PRICEOFFSET":
+		if *decimalMode == decimalMessage {
+			return "GetDecimal", "", info.sharedFunc("DecimalToProto")
+		}
+		return "GetDecimal", "value.String()", ""
+	case "FLOAT":
+		return "GetFloat", "value", ""
+	case "BOOLEAN":
+		return "GetBool", "value", ""
+	case "UTCTIMESTAMP":
+		if wellKnownTime(p.Type) {
+			return "GetTime", info.sharedFunc("TimestampToProto") + "(value)", ""
+		}
+		return "GetTime", `value.Format("2006-01-02T15:04:05.999999999Z07:00")`, ""
+	case "UTCDATEONLY", "UTCDATE":
+		if wellKnownTime(p.Type) {
+			return "GetString", "", info.sharedFunc("DateToProto")
+		}
+		return "GetString", "value", ""
+	default:
+		return "GetString", "value", ""
+	}
+}
+
+// FromProtoCodes returns the code setting the part of the FIX field map fieldMap from pbMsg, unless the part is not
+// set in pbMsg. Errors are returned prefixed by failPrefix, the other results of the enclosing function.
+func (p protoPart) FromProtoCodes(failPrefix string) string {
+	fieldName := p.goFieldName()
+	switch {
+	case p.IsComponent():
+		return fmt.Sprintf(`
+	if pbMsg.%s != nil {
+		if err := %sFromProto(pbMsg.%s, fieldMap); err != nil {
+			return %serr
+		}
+	}`, fieldName, p.MessageName(), fieldName, failPrefix)
+	case p.IsGroup():
+		variableName := lowerFirst(fieldName)
+		return fmt.Sprintf(`
+	if len(pbMsg.%s) > 0 {
+		%s := quickfix.NewRepeatingGroup(tag.%s, %s())
+		for _, entry := range pbMsg.%s {
+			if err := %sFromProto(entry, &%s.Add().FieldMap); err != nil {
+				return %serr
+			}
+		}
+		fieldMap.SetGroup(%s)
+	}`, fieldName, variableName, p.Name(), groupTemplateFunc(p.FieldDef), fieldName, p.MessageName(), variableName,
+			failPrefix, variableName)
+	}
+
+	info := p.fieldInfo()
+	read := info.protoRead(fieldName)
+	if enumMap, ok := info.toFIXEnumMap(); ok {
+		present := "ok"
+		if explicitPresence() {
+			present = fmt.Sprintf("pbMsg.%s != nil && ok", fieldName)
+		}
+		return fmt.Sprintf(`
+	if value, ok := %s[pbMsg.%s]; %s {
+		fieldMap.SetString(tag.%s, string(value))
+	}`, enumMap, read, present, p.Name())
+	}
+
+	if wellKnownTime(p.Type) {
+		value := fmt.Sprintf("%s(pbMsg.%s)", info.timeFromProtoFunc(), fieldName)
+		setter := fmt.Sprintf("fieldMap.SetString(tag.%s, %s)", p.Name(), value)
+		if p.Type == "UTCTIMESTAMP" {
+			setter = fmt.Sprintf("fieldMap.SetField(tag.%s, quickfix.FIXUTCTimestamp{Time: %s, Precision: %s})",
+				p.Name(), value, info.sharedFunc("TimestampPrecision"))
+		}
+		return fmt.Sprintf(`
+	if pbMsg.%s != nil {
+		%s
+	}`, fieldName, setter)
+	}
+
+	switch p.Type {
+	case "AMT", "PERCENTAGE", "PRICE", "QTY", "PRICEOFFSET":
+		if *decimalMode == decimalMessage {
+			return fmt.Sprintf(`
+	if pbMsg.%s != nil {
+		value := %s(pbMsg.%s)
+		fieldMap.SetField(tag.%s, quickfix.FIXDecimal{Decimal: value, Scale: decimalScale(value)})
+	}`, fieldName, info.sharedFunc("DecimalFromProto"), fieldName, p.Name())
+		}
+		return fmt.Sprintf(`
+	if %s {
+		value, err := decimal.NewFromString(pbMsg.%s)
+		if err != nil {
+			return %sfmt.Errorf("failed to parse %s from protobuf message: %%w", err)
+		}
+		fieldMap.SetField(tag.%s, quickfix.FIXDecimal{Decimal: value, Scale: decimalScale(value)})
+	}`, info.protoPresent(fieldName, fmt.Sprintf(`pbMsg.%s != ""`, fieldName)), read, failPrefix, p.Name(), p.Name())
+	case "FLOAT":
+		return fmt.Sprintf(`
+	if %s {
+		value := decimal.NewFromFloat(pbMsg.%s)
+		fieldMap.SetField(tag.%s, quickfix.FIXDecimal{Decimal: value, Scale: decimalScale(value)})
+	}`, info.protoPresent(fieldName, fmt.Sprintf("pbMsg.%s != 0", fieldName)), read, p.Name())
+	case "UTCTIMESTAMP":
+		return fmt.Sprintf(`
+	if %s {
+		value, err := time.Parse(time.RFC3339Nano, pbMsg.%s)
+		if err != nil {
+			return %sfmt.Errorf("failed to parse %s from protobuf message: %%w", err)
+		}
+		fieldMap.SetField(tag.%s, quickfix.FIXUTCTimestamp{Time: value})
+	}`, info.protoPresent(fieldName, fmt.Sprintf(`pbMsg.%s != ""`, fieldName)), read, failPrefix, p.Name(), p.Name())
+	case "LENGTH", "INT", "SEQNUM", "TAGNUM", "DAYOFMONTH", "NUMINGROUP":
+		return fmt.Sprintf(`
+	if %s {
+		fieldMap.SetInt(tag.%s, int(pbMsg.%s))
+	}`, info.protoPresent(fieldName, fmt.Sprintf("pbMsg.%s != 0", fieldName)), p.Name(), read)
+	case "BOOLEAN":
+		value := "true"
+		if explicitPresence() {
+			value = "pbMsg." + read
+		}
+		return fmt.Sprintf(`
+	if %s {
+		fieldMap.SetBool(tag.%s, %s)
+	}`, info.protoPresent(fieldName, "pbMsg."+fieldName), p.Name(), value)
+	default:
+		return fmt.Sprintf(`
+	if %s {
+		fieldMap.SetString(tag.%s, pbMsg.%s)
+	}`, info.protoPresent(fieldName, fmt.Sprintf(`pbMsg.%s != ""`, fieldName)), p.Name(), read)
+	}
+}
+
+// parsesTime returns true if the FromProto conversion of the part parses a timestamp
+func (p protoPart) parsesTime() bool {
+	if p.IsComponent() || p.IsGroup() {
+		return false
+	}
+	_, isEnum := p.fieldInfo().toFIXEnumMap()
+	return !isEnum && p.Type == "UTCTIMESTAMP"
+}
+
+// getProtoParts returns the fields, components and repeating groups of parts, the required ones first, each sorted
+// by proto name
+func getProtoParts(parts []datadictionary.MessagePart, version *fixVersion) []protoPart {
+	var out []protoPart
+	for _, part := range parts {
+		switch p := part.(type) {
+		case datadictionary.Component:
+			out = append(out, protoPart{Component: p.ComponentType, Required: p.Required(), version: version})
+		case *datadictionary.Component:
+			out = append(out, protoPart{Component: p.ComponentType, Required: p.Required(), version: version})
+		case *datadictionary.FieldDef:
+			out = append(out, protoPart{FieldDef: p, Required: p.Required(), version: version})
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Required != out[j].Required {
+			return out[i].Required
+		}
+		return out[i].ProtoName() < out[j].ProtoName()
+	})
+	return out
+}
+
+// ProtoParts returns the parts of the message generated with -component-messages
+func (m *messageInfo) ProtoParts() []protoPart {
+	return getProtoParts(m.Parts, m.version)
+}
+
+// BeginString returns the BeginString of the FIX messages of the package of the message
+func (m *messageInfo) BeginString() string {
+	if strings.HasPrefix(m.Package, "fix4") {
+		return "FIX.4." + m.Package[len("fix4"):]
+	}
+	return "FIXT.1.1"
+}
+
+// componentInfo is a component or the entry of a repeating group, generated as its own proto message with
+// -component-messages along with converters from and to a FIX field map
+type componentInfo struct {
+	Name      string
+	component *datadictionary.ComponentType
+	group     *datadictionary.FieldDef
+	version   *fixVersion
+}
+
+// IsGroup returns true if c is the entry of a repeating group
+func (c componentInfo) IsGroup() bool {
+	return c.group != nil
+}
+
+// Description returns the description of c in the comments of the generated code
+func (c componentInfo) Description() string {
+	if c.IsGroup() {
+		return "an entry of the " + c.group.Name() + " repeating group"
+	}
+	return "the " + c.Name + " component"
+}
+
+func (c componentInfo) parts() []datadictionary.MessagePart {
+	if c.IsGroup() {
+		return c.group.Parts
+	}
+	return c.component.Parts()
+}
+
+// ProtoParts returns the parts of the proto message of c
+func (c componentInfo) ProtoParts() []protoPart {
+	return getProtoParts(c.parts(), c.version)
+}
+
+// TagsVar returns the variable holding the tags of the component, telling whether a field map holds it
+func (c componentInfo) TagsVar() string {
+	return componentTagsVar(c.Name)
+}
+
+// Tags returns the tags of the fields of the component, including the fields of its nested components and the
+// number of entries of its repeating groups
+func (c componentInfo) Tags() string {
+	var tags []string
+	for _, f := range c.component.Fields() {
+		tags = append(tags, "tag."+f.Name())
+	}
+	return strings.Join(tags, ", ")
+}
+
+// TemplateFunc returns the function returning the template of the repeating group
+func (c componentInfo) TemplateFunc() string {
+	return groupTemplateFunc(c.group)
+}
+
+// TemplateItems returns the items of the template of the repeating group, in declaration order
+func (c componentInfo) TemplateItems() []string {
+	return groupTemplateItems(c.group.Parts, nil)
+}
+
+// ProtoComponents returns the components and repeating group entries of the messages, each once, sorted by name
+func (c messagesComponent) ProtoComponents() []componentInfo {
+	seen := make(map[string]bool)
+	var components []componentInfo
+
+	var walk func(parts []datadictionary.MessagePart, version *fixVersion)
+	walk = func(parts []datadictionary.MessagePart, version *fixVersion) {
+		for _, p := range getProtoParts(parts, version) {
+			if !p.IsComponent() && !p.IsGroup() {
+				continue
+			}
+			name := p.MessageName()
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			info := componentInfo{Name: name, version: version}
+			if p.IsComponent() {
+				info.component = p.Component
+			} else {
+				info.group = p.FieldDef
+			}
+			components = append(components, info)
+			walk(info.parts(), version)
+		}
+	}
+	for i := range c.Messages {
+		walk(c.Messages[i].Parts, c.Messages[i].version)
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+	return components
+}
+
+// ComponentMessages returns true if the components are generated as their own proto messages, see
+// -component-messages
+func (c messagesComponent) ComponentMessages() bool {
+	return *componentMessages
+}
+
+// componentMessagesUseTime returns true if the FromProto conversions generated with -component-messages parse
+// timestamps
+func (c messagesComponent) componentMessagesUseTime() bool {
+	var parts []protoPart
+	for i := range c.Messages {
+		parts = append(parts, c.Messages[i].ProtoParts()...)
+	}
+	for _, component := range c.ProtoComponents() {
+		parts = append(parts, component.ProtoParts()...)
+	}
+
+	for _, p := range parts {
+		if p.parsesTime() {
+			return true
+		}
+	}
+	return false
+}
+
+// groupTemplateItems appends the items of the template of a repeating group with parts, recursing into components
+func groupTemplateItems(parts []datadictionary.MessagePart, items []string) []string {
+	for _, part := range parts {
+		switch p := part.(type) {
+		case datadictionary.Component:
+			items = groupTemplateItems(p.Parts(), items)
+		case *datadictionary.Component:
+			items = groupTemplateItems(p.Parts(), items)
+		case *datadictionary.FieldDef:
+			if p.IsGroup() {
+				items = append(items, fmt.Sprintf("quickfix.NewRepeatingGroup(tag.%s, %s())", p.Name(), groupTemplateFunc(p)))
+			} else {
+				items = append(items, fmt.Sprintf("quickfix.GroupElement(tag.%s)", p.Name()))
+			}
+		}
+	}
+	return items
+}
+
+// groupTemplateFunc returns the generated function returning the template of a repeating group
+func groupTemplateFunc(group *datadictionary.FieldDef) string {
+	return lowerFirst(generateGroupMessageName(group)) + "Template"
+}
+
+// componentTagsVar returns the generated variable holding the tags of a component
+func componentTagsVar(name string) string {
+	return lowerFirst(name) + "Tags"
+}
+
+func lowerFirst(name string) string {
+	if len(name) > 0 && unicode.IsUpper(rune(name[0])) {
+		return string(unicode.ToLower(rune(name[0]))) + name[1:]
+	}
+	return name
+}
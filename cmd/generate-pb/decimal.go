@@ -0,0 +1,23 @@
+package main
+
+// Values of -decimal-mode
+const (
+	decimalString  = "string"
+	decimalMessage = "message"
+)
+
+const (
+	// decimalProtoMessage is the proto message of the FIX decimal types with -decimal-mode message
+	decimalProtoMessage = "Decimal"
+
+	// decimalProtoFile is the proto file of decimalProtoMessage, relative to PbRoot
+	decimalProtoFile = "fix.decimal.proto"
+
+	// decimalConversionFile is the Go file of the conversion functions of decimalProtoMessage, relative to GoRoot
+	decimalConversionFile = "fix.decimal.conversion.go"
+)
+
+// DecimalMessages returns true if the FIX decimal types are encoded as Decimal proto messages, see -decimal-mode
+func (c messagesComponent) DecimalMessages() bool {
+	return *decimalMode == decimalMessage
+}
@@ -0,0 +1,109 @@
+package main
+
+import "fmt"
+
+// Values of -edition
+const (
+	editionProto3 = "proto3"
+	editionProto2 = "proto2"
+	edition2023   = "2023"
+)
+
+// protoSyntax returns the syntax or edition declaration of the generated proto files, see -edition
+func protoSyntax() string {
+	if *edition == edition2023 {
+		return `edition = "2023";`
+	}
+	return fmt.Sprintf("syntax = %q;", *edition)
+}
+
+// fieldLabel returns the label of the singular proto fields, optional with -edition proto2
+func fieldLabel() string {
+	if *edition == editionProto2 {
+		return "optional "
+	}
+	return ""
+}
+
+// explicitPresence returns true if the singular proto fields have explicit presence, so that their Go fields are
+// pointers, nil unless set. This is the case of proto2 optional fields and of the fields of edition 2023.
+func explicitPresence() bool {
+	return *edition != editionProto3
+}
+
+// presenceWrapper returns the function wrapping a value of the scalar proto field of f into a pointer, such as
+// proto.String, with explicit presence. Returns an empty string without explicit presence, for message fields and
+// for enum fields, which are wrapped with their Enum method.
+func (f fieldInfo) presenceWrapper() string {
+	if !explicitPresence() {
+		return ""
+	}
+	if _, isEnum := f.fromFIXEnumMap(); isEnum {
+		return ""
+	}
+
+	switch getProtoTypeForField(f.FieldDef) {
+	case "string":
+		return "proto.String"
+	case "int32":
+		return "proto.Int32"
+	case "uint32":
+		return "proto.Uint32"
+	case "double":
+		return "proto.Float64"
+	case "bool":
+		return "proto.Bool"
+	}
+	return ""
+}
+
+// isScalar returns true if the proto field of f is a scalar or enum, not a message such as Decimal or
+// google.protobuf.Timestamp
+func (f fieldInfo) isScalar() bool {
+	if _, isEnum := f.fromFIXEnumMap(); isEnum {
+		return true
+	}
+	switch f.Type {
+	case "AMT", "PERCENTAGE", "PRICE", "QTY", "PRICEOFFSET":
+		return *decimalMode != decimalMessage
+	}
+	return !wellKnownTime(f.Type)
+}
+
+// protoAssign returns the code assigning value to the proto field fieldName of pbMsg, wrapped into a pointer with
+// explicit presence
+func (f fieldInfo) protoAssign(fieldName, value string) string {
+	if wrapper := f.presenceWrapper(); wrapper != "" {
+		return fmt.Sprintf("pbMsg.%s = %s(%s)", fieldName, wrapper, value)
+	}
+	return fmt.Sprintf("pbMsg.%s = %s", fieldName, value)
+}
+
+// protoRead returns the name of the proto field fieldName read with pbMsg.<name>: the field itself, or its getter
+// for a scalar field with explicit presence
+func (f fieldInfo) protoRead(fieldName string) string {
+	if explicitPresence() && f.isScalar() {
+		return "Get" + fieldName + "()"
+	}
+	return fieldName
+}
+
+// protoPresent returns the condition of the proto field fieldName being set: zeroCheck, comparing the field to its
+// zero value, or else a nil check with explicit presence
+func (f fieldInfo) protoPresent(fieldName, zeroCheck string) string {
+	if explicitPresence() && f.isScalar() {
+		return fmt.Sprintf("pbMsg.%s != nil", fieldName)
+	}
+	return zeroCheck
+}
+
+// protoAssignEnum returns the code assigning enumMap[key] to the enum proto field fieldName of pbMsg, with explicit
+// presence only if key is in enumMap
+func protoAssignEnum(fieldName, enumMap, key string) string {
+	if explicitPresence() {
+		return fmt.Sprintf(`if protoValue, ok := %s[%s]; ok {
+			pbMsg.%s = protoValue.Enum()
+		}`, enumMap, key, fieldName)
+	}
+	return fmt.Sprintf("pbMsg.%s = %s[%s]", fieldName, enumMap, key)
+}
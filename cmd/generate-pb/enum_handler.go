@@ -19,9 +19,10 @@ type EnumValue struct {
 // EnumDefinition represents a complete enum type definition
 type EnumDefinition struct {
 	Name      string
+	Tag       int
 	FieldType string
 	Values    []EnumValue
-	ProtoName string // Sanitized name for protobuf
+	ProtoName string // Sanitized name for protobuf, disambiguated from the other enums of the registry
 }
 
 // EnumRegistry manages all enum definitions across specifications
@@ -53,6 +54,7 @@ func (r *EnumRegistry) RegisterFieldEnums(specs []*datadictionary.DataDictionary
 func (r *EnumRegistry) registerFieldEnum(field *datadictionary.FieldType) {
 	enumDef := &EnumDefinition{
 		Name:      field.Name(),
+		Tag:       field.Tag(),
 		FieldType: field.Type, // field.Type is a string field, not a method
 		ProtoName: sanitizeEnumName(field.Name()),
 		Values:    make([]EnumValue, 0, len(field.Enums)),
@@ -206,7 +208,8 @@ func (ev *EnumValue) GetDefaultValueName() string {
 	return "UNSPECIFIED"
 }
 
-// GenerateEnumStringMapping generates Go code for enum to string mapping
+// GenerateEnumStringMapping generates Go code for enum to string mapping. The FIX values are the symbols generate-fix
+// defines in the enum package for the same specifications, merged by BuildGlobalFieldTypes.
 func (ed *EnumDefinition) GenerateEnumStringMapping() string {
 	var builder strings.Builder
 
@@ -214,24 +217,24 @@ func (ed *EnumDefinition) GenerateEnumStringMapping() string {
 
 	// Generate map for enum to string conversion
 	builder.WriteString(fmt.Sprintf("// %sToFIX converts %s enum values to their FIX enum representation\n", ed.ProtoName, ed.ProtoName))
-	builder.WriteString(fmt.Sprintf("var %sToFIX = map[%s]enum.%s{\n", ed.ProtoName, ed.ProtoName, ed.ProtoName))
+	builder.WriteString(fmt.Sprintf("var %sToFIX = map[%s]enum.%s{\n", ed.ProtoName, ed.ProtoName, ed.Name))
 
 	for _, value := range ed.Values {
 		enumValueName := value.GetProtoEnumValueName(ed.ProtoName)
 		goEnumValueName := enumTypePrefix + "_" + enumValueName
-		builder.WriteString(fmt.Sprintf("\t%s: enum.%s_%s,\n", goEnumValueName, ed.ProtoName, value.Description))
+		builder.WriteString(fmt.Sprintf("\t%s: %s,\n", goEnumValueName, globalFieldTypesLookup.Symbol(ed.Name, value.StringValue)))
 	}
 
 	builder.WriteString("}\n\n")
 
 	// Generate map for string to enum conversion
 	builder.WriteString(fmt.Sprintf("// FIXTo%s converts FIX enum values to %s enum values\n", ed.ProtoName, ed.ProtoName))
-	builder.WriteString(fmt.Sprintf("var FIXTo%s = map[enum.%s]%s{\n", ed.ProtoName, ed.ProtoName, ed.ProtoName))
+	builder.WriteString(fmt.Sprintf("var FIXTo%s = map[enum.%s]%s{\n", ed.ProtoName, ed.Name, ed.ProtoName))
 
 	for _, value := range ed.Values {
 		enumValueName := value.GetProtoEnumValueName(ed.ProtoName)
 		goEnumValueName := enumTypePrefix + "_" + enumValueName
-		builder.WriteString(fmt.Sprintf("\tenum.%s_%s: %s,\n", ed.ProtoName, value.Description, goEnumValueName))
+		builder.WriteString(fmt.Sprintf("\t%s: %s,\n", globalFieldTypesLookup.Symbol(ed.Name, value.StringValue), goEnumValueName))
 	}
 
 	builder.WriteString("}\n\n")
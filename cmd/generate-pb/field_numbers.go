@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/quickfixgo/quickfix/cmd/internal/codegen"
+)
+
+// Field numbers reserved by protobuf for its own use
+const (
+	reservedFieldNumberStart = 19000
+	reservedFieldNumberEnd   = 19999
+)
+
+// Global field number map, nil unless -field-number-map is set
+var globalFieldNumbers *FieldNumberMap
+
+// FieldNumberMap persists the field numbers of the generated proto messages across runs, see -field-number-map.
+// A field keeps its number once assigned, new fields are numbered after the highest number ever used by the message,
+// so numbers of removed fields are not reused.
+type FieldNumberMap struct {
+	mu       sync.Mutex
+	messages map[string]map[string]int // Key: message name, then proto field name
+	changed  bool
+}
+
+// LoadFieldNumberMap reads the field number map from file, returning an empty map if file does not exist
+func LoadFieldNumberMap(file string) (*FieldNumberMap, error) {
+	m := &FieldNumberMap{messages: make(map[string]map[string]int)}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return m, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read field number map %s: %w", file, err)
+	}
+
+	if err := json.Unmarshal(data, &m.messages); err != nil {
+		return nil, fmt.Errorf("failed to parse field number map %s: %w", file, err)
+	}
+	if m.messages == nil {
+		m.messages = make(map[string]map[string]int)
+	}
+	return m, nil
+}
+
+// Number returns the field number of field in message, assigning the next free number if it has none
+func (m *FieldNumberMap) Number(message, field string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fields, ok := m.messages[message]
+	if !ok {
+		fields = make(map[string]int)
+		m.messages[message] = fields
+	}
+	if number, ok := fields[field]; ok {
+		return number
+	}
+
+	next := 1
+	for _, number := range fields {
+		if number >= next {
+			next = number + 1
+		}
+	}
+	if next >= reservedFieldNumberStart && next <= reservedFieldNumberEnd {
+		next = reservedFieldNumberEnd + 1
+	}
+
+	fields[field] = next
+	m.changed = true
+	return next
+}
+
+// Save writes the field number map to file if numbers were assigned since it was loaded
+func (m *FieldNumberMap) Save(file string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.changed {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m.messages, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode field number map: %w", err)
+	}
+	if err := codegen.WriteFile(file, string(data)+"\n"); err != nil {
+		return fmt.Errorf("failed to write field number map %s: %w", file, err)
+	}
+	m.changed = false
+	return nil
+}
+
+// fieldNumber returns the number of field in message from the field number map, or sequential if there is none
+func fieldNumber(message, field string, sequential int) int {
+	if globalFieldNumbers == nil {
+		return sequential
+	}
+	return globalFieldNumbers.Number(message, field)
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// constructorFields returns the arguments of the New constructor generated by generate-fix for msgDef, in order:
+// the required fields of the message and of its required components, except repeating groups
+func constructorFields(msgDef *datadictionary.MessageDef) (required []*datadictionary.FieldDef) {
+	for _, part := range msgDef.RequiredParts() {
+		if !part.Required() {
+			continue
+		}
+		switch p := part.(type) {
+		case *datadictionary.FieldDef:
+			if !p.IsGroup() {
+				required = append(required, p)
+			}
+		case *datadictionary.Component:
+			for _, f := range p.RequiredFields() {
+				if !f.IsGroup() {
+					required = append(required, f)
+				}
+			}
+		}
+	}
+	return
+}
+
+// ConstructorFields returns the fields passed to the typed constructor of the message
+func (m *messageInfo) ConstructorFields() []fieldInfo {
+	byTag := make(map[int]fieldInfo)
+	for _, f := range m.GetFields() {
+		byTag[f.Tag()] = f
+	}
+
+	var out []fieldInfo
+	for _, f := range constructorFields(m.MessageDef) {
+		if info, ok := byTag[f.Tag()]; ok {
+			out = append(out, info)
+		} else {
+			out = append(out, fieldInfo{FieldDef: f, version: m.version})
+		}
+	}
+	return out
+}
+
+// SetterFields returns the fields of the message set with its setters, the ones not passed to its constructor
+func (m *messageInfo) SetterFields() []fieldInfo {
+	required := make(map[int]bool)
+	for _, f := range constructorFields(m.MessageDef) {
+		required[f.Tag()] = true
+	}
+
+	var out []fieldInfo
+	for _, f := range m.GetFields() {
+		if !required[f.Tag()] && !f.IsGroup() && f.Type != "NUMINGROUP" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ConstructorArgs returns the arguments of the typed constructor of the message
+func (m *messageInfo) ConstructorArgs() string {
+	var args []string
+	for _, f := range m.ConstructorFields() {
+		args = append(args, f.fieldConstructor())
+	}
+	return strings.Join(args, ", ")
+}
+
+// toFIXEnumMap returns the protobuf to FIX conversion map of the field's enum, if it has one
+func (f fieldInfo) toFIXEnumMap() (string, bool) {
+	if f.version != nil {
+		return f.version.toFIXEnumMap(f.Name())
+	}
+	if len(f.Enums) > 0 {
+		return getEnumProtoName(f.Name()) + "ToFIX", true
+	}
+	return "", false
+}
+
+// fromFIXEnumMap returns the FIX to protobuf conversion map of the field's enum, if it has one
+func (f fieldInfo) fromFIXEnumMap() (string, bool) {
+	if f.version != nil {
+		return f.version.fromFIXEnumMap(f.Name())
+	}
+	if len(f.Enums) > 0 {
+		return "FIXTo" + getEnumProtoName(f.Name()), true
+	}
+	return "", false
+}
+
+// fieldConstructor returns the call of the field package constructor of a field read by ConstructorCodes
+func (f fieldInfo) fieldConstructor() string {
+	variableName := f.GoVariableName()
+	if _, ok := f.toFIXEnumMap(); ok {
+		if f.Type == "BOOLEAN" {
+			return fmt.Sprintf(`field.New%s(string(%s) == "Y")`, f.Name(), variableName)
+		}
+		return fmt.Sprintf("field.New%s(%s)", f.Name(), variableName)
+	}
+
+	switch f.Type {
+	case "AMT", "PERCENTAGE", "PRICE", "QTY", "PRICEOFFSET", "FLOAT":
+		return fmt.Sprintf("field.New%s(%s, decimalScale(%s))", f.Name(), variableName, variableName)
+	case "UTCTIMESTAMP":
+		if wellKnownTime(f.Type) {
+			return fmt.Sprintf("field.New%sWithPrecision(%s, %s)", f.Name(), variableName, f.sharedFunc("TimestampPrecision"))
+		}
+		return fmt.Sprintf("field.New%s(%s)", f.Name(), variableName)
+	default:
+		return fmt.Sprintf("field.New%s(%s)", f.Name(), variableName)
+	}
+}
+
+// ConstructorCodes returns the code reading a field passed to the typed constructor from pbMsg, returning an error
+// if the field is not set
+func (f fieldInfo) ConstructorCodes() string {
+	fieldName := f.GetProtoFieldName()
+	if explicitPresence() && f.isScalar() {
+		return fmt.Sprintf(`
+	if pbMsg.%s == nil {
+		return fixMsg, fmt.Errorf("required field %s is not set")
+	}`, fieldName, f.Name()) + f.constructorCodes(f.protoRead(fieldName))
+	}
+	return f.constructorCodes(fieldName)
+}
+
+// constructorCodes returns the code of ConstructorCodes, reading the proto field with pbMsg.<fieldName>
+func (f fieldInfo) constructorCodes(fieldName string) string {
+	variableName := f.GoVariableName()
+
+	if enumMap, ok := f.toFIXEnumMap(); ok {
+		return fmt.Sprintf(`
+	%s, ok := %s[pbMsg.%s]
+	if !ok {
+		return fixMsg, fmt.Errorf("required field %s is not set")
+	}`, variableName, enumMap, fieldName, f.Name())
+	}
+
+	if wellKnownTime(f.Type) {
+		return fmt.Sprintf(`
+	if pbMsg.%s == nil {
+		return fixMsg, fmt.Errorf("required field %s is not set")
+	}
+	%s := %s(pbMsg.%s)`, fieldName, f.Name(), variableName, f.timeFromProtoFunc(), fieldName)
+	}
+
+	switch f.Type {
+	case "AMT", "PERCENTAGE", "PRICE", "QTY", "PRICEOFFSET":
+		if *decimalMode == decimalMessage {
+			return fmt.Sprintf(`
+	if pbMsg.%s == nil {
+		return fixMsg, fmt.Errorf("required field %s is not set")
+	}
+	%s := %s(pbMsg.%s)`, fieldName, f.Name(), variableName, f.sharedFunc("DecimalFromProto"), fieldName)
+		}
+		return fmt.Sprintf(`
+	%s, err := decimal.NewFromString(pbMsg.%s)
+	if err != nil {
+		return fixMsg, fmt.Errorf("failed to parse %s from protobuf message: %%w", err)
+	}`, variableName, fieldName, f.Name())
+	case "FLOAT":
+		return fmt.Sprintf(`
+	%s := decimal.NewFromFloat(pbMsg.%s)`, variableName, fieldName)
+	case "UTCTIMESTAMP":
+		return fmt.Sprintf(`
+	%s, err := time.Parse(time.RFC3339Nano, pbMsg.%s)
+	if err != nil {
+		return fixMsg, fmt.Errorf("failed to parse %s from protobuf message: %%w", err)
+	}`, variableName, fieldName, f.Name())
+	case "LENGTH", "INT", "SEQNUM", "TAGNUM", "DAYOFMONTH":
+		return fmt.Sprintf(`
+	%s := int(pbMsg.%s)`, variableName, fieldName)
+	case "BOOLEAN":
+		return fmt.Sprintf(`
+	%s := pbMsg.%s`, variableName, fieldName)
+	default:
+		return fmt.Sprintf(`
+	if pbMsg.%s == "" {
+		return fixMsg, fmt.Errorf("required field %s is not set")
+	}
+	%s := pbMsg.%s`, fieldName, f.Name(), variableName, fieldName)
+	}
+}
+
+// SetterCodes returns the code setting a field of fixMsg from pbMsg, unless the field is not set in pbMsg
+func (f fieldInfo) SetterCodes() string {
+	fieldName := f.GetProtoFieldName()
+	read := f.protoRead(fieldName)
+	variableName := f.GoVariableName()
+	setter := "fixMsg.Set" + f.Name()
+
+	if enumMap, ok := f.toFIXEnumMap(); ok {
+		value := variableName
+		if f.Type == "BOOLEAN" {
+			value = fmt.Sprintf(`string(%s) == "Y"`, variableName)
+		}
+		present := "ok"
+		if explicitPresence() {
+			present = fmt.Sprintf("pbMsg.%s != nil && ok", fieldName)
+		}
+		return fmt.Sprintf(`
+	if %s, ok := %s[pbMsg.%s]; %s {
+		%s(%s)
+	}`, variableName, enumMap, read, present, setter, value)
+	}
+
+	if wellKnownTime(f.Type) {
+		value := fmt.Sprintf("%s(pbMsg.%s)", f.timeFromProtoFunc(), fieldName)
+		if f.Type == "UTCTIMESTAMP" {
+			value = fmt.Sprintf("field.New%sWithPrecision(%s, %s)", f.Name(), value, f.sharedFunc("TimestampPrecision"))
+			setter = "fixMsg.Set"
+		}
+		return fmt.Sprintf(`
+	if pbMsg.%s != nil {
+		%s(%s)
+	}`, fieldName, setter, value)
+	}
+
+	switch f.Type {
+	case "AMT", "PERCENTAGE", "PRICE", "QTY", "PRICEOFFSET":
+		if *decimalMode == decimalMessage {
+			return fmt.Sprintf(`
+	if pbMsg.%s != nil {
+		%s := %s(pbMsg.%s)
+		%s(%s, decimalScale(%s))
+	}`, fieldName, variableName, f.sharedFunc("DecimalFromProto"), fieldName, setter, variableName, variableName)
+		}
+		return fmt.Sprintf(`
+	if %s {
+		%s, err := decimal.NewFromString(pbMsg.%s)
+		if err != nil {
+			return fixMsg, fmt.Errorf("failed to parse %s from protobuf message: %%w", err)
+		}
+		%s(%s, decimalScale(%s))
+	}`, f.protoPresent(fieldName, fmt.Sprintf(`pbMsg.%s != ""`, fieldName)), variableName, read, f.Name(), setter,
+			variableName, variableName)
+	case "FLOAT":
+		return fmt.Sprintf(`
+	if %s {
+		%s := decimal.NewFromFloat(pbMsg.%s)
+		%s(%s, decimalScale(%s))
+	}`, f.protoPresent(fieldName, fmt.Sprintf("pbMsg.%s != 0", fieldName)), variableName, read, setter, variableName,
+			variableName)
+	case "UTCTIMESTAMP":
+		return fmt.Sprintf(`
+	if %s {
+		%s, err := time.Parse(time.RFC3339Nano, pbMsg.%s)
+		if err != nil {
+			return fixMsg, fmt.Errorf("failed to parse %s from protobuf message: %%w", err)
+		}
+		%s(%s)
+	}`, f.protoPresent(fieldName, fmt.Sprintf(`pbMsg.%s != ""`, fieldName)), variableName, read, f.Name(), setter,
+			variableName)
+	case "LENGTH", "INT", "SEQNUM", "TAGNUM", "DAYOFMONTH":
+		return fmt.Sprintf(`
+	if %s {
+		%s(int(pbMsg.%s))
+	}`, f.protoPresent(fieldName, fmt.Sprintf("pbMsg.%s != 0", fieldName)), setter, read)
+	case "BOOLEAN":
+		value := "true"
+		if explicitPresence() {
+			value = "pbMsg." + read
+		}
+		return fmt.Sprintf(`
+	if %s {
+		%s(%s)
+	}`, f.protoPresent(fieldName, "pbMsg."+fieldName), setter, value)
+	default:
+		return fmt.Sprintf(`
+	if %s {
+		%s(pbMsg.%s)
+	}`, f.protoPresent(fieldName, fmt.Sprintf(`pbMsg.%s != ""`, fieldName)), setter, read)
+	}
+}
+
+// FromProtoUsesField returns true if the FromProto conversions of the messages construct fields of the field package
+func (c messagesComponent) FromProtoUsesField() bool {
+	if c.ComponentMessages() {
+		return false
+	}
+	for i := range c.Messages {
+		if len(constructorFields(c.Messages[i].MessageDef)) > 0 {
+			return true
+		}
+		if c.WellKnownTime() {
+			for _, f := range c.Messages[i].SetterFields() {
+				if _, isEnum := f.toFIXEnumMap(); !isEnum && f.Type == "UTCTIMESTAMP" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// FromProtoUsesTime returns true if the FromProto conversions of the messages parse timestamps
+func (c messagesComponent) FromProtoUsesTime() bool {
+	if c.WellKnownTime() {
+		return false
+	}
+	if c.ComponentMessages() {
+		return c.componentMessagesUseTime()
+	}
+	for i := range c.Messages {
+		msg := &c.Messages[i]
+		for _, f := range append(msg.ConstructorFields(), msg.SetterFields()...) {
+			if _, isEnum := f.toFIXEnumMap(); !isEnum && f.Type == "UTCTIMESTAMP" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// gatewayPackage is the proto and Go package of the gateway service, kept apart from the message packages so that
+// it can import the messages of every version
+const gatewayPackage = "service"
+
+// adminMsgTypes are the session level messages left out of the gateway service
+var adminMsgTypes = map[string]bool{
+	"0": true, "1": true, "2": true, "3": true, "4": true, "5": true, "A": true,
+}
+
+// gatewayMethod is one rpc of the gateway service, sending a message to a FIX session
+type gatewayMethod struct {
+	Name    string
+	Request string
+	Path    string
+}
+
+// gatewayComponent is the template data of the gateway service
+type gatewayComponent struct {
+	messagesComponent
+	Imports []string
+	Methods []gatewayMethod
+}
+
+// buildGateway returns the gateway service of the messages of specs, one rpc per application message.
+// With -per-version the rpc names and paths are prefixed by the version of their message.
+func buildGateway(specs []*datadictionary.DataDictionary, config *Config) gatewayComponent {
+	c := gatewayComponent{
+		messagesComponent: messagesComponent{
+			GoPackagePrefix: config.PbGoPkg + "/" + gatewayPackage,
+			QuickfixRoot:    config.FixPkg,
+		},
+	}
+
+	sharedPackage := extractPackageName(config.PbGoPkg)
+	imports := make(map[string]bool)
+	if !config.PerVersion && !config.SplitByMessage {
+		imports["fix.message.proto"] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		version := getPackageName(spec)
+		if config.PerVersion && !config.SplitByMessage {
+			imports[path.Join(version, "fix.message.proto")] = true
+		}
+
+		for _, msg := range spec.Messages {
+			if adminMsgTypes[msg.MsgType] {
+				continue
+			}
+
+			info := messageInfo{Name: msg.Name}
+			resource := strings.ToLower(info.EnumName())
+			m := gatewayMethod{
+				Name:    "Send" + msg.Name,
+				Request: sharedPackage + "." + msg.Name,
+				Path:    "/v1/" + resource,
+			}
+			if config.PerVersion {
+				m.Name = "Send" + toGoFieldName(version) + msg.Name
+				m.Request = version + "." + msg.Name
+				m.Path = "/v1/" + version + "/" + resource
+			}
+
+			// Without -per-version, messages of several specifications share a single proto message
+			if seen[m.Name] {
+				continue
+			}
+			seen[m.Name] = true
+			c.Methods = append(c.Methods, m)
+
+			// With -split-by-message only the files of the messages of the service are imported
+			if config.SplitByMessage {
+				dir := ""
+				if config.PerVersion {
+					dir = version
+				}
+				imports[path.Join(dir, messageProtoFile(msg.Name))] = true
+			}
+		}
+	}
+
+	for file := range imports {
+		c.Imports = append(c.Imports, file)
+	}
+	sort.Strings(c.Imports)
+	sort.Slice(c.Methods, func(i, j int) bool { return c.Methods[i].Name < c.Methods[j].Name })
+	return c
+}
+
+func genGateway(specs []*datadictionary.DataDictionary, config *Config) {
+	defer func() {
+		if config.Verbose {
+			log.Printf("Calling waitGroup.Done() for genGateway")
+		}
+		waitGroup.Done()
+	}()
+
+	c := buildGateway(specs, config)
+	if config.Verbose {
+		log.Printf("Generating gateway service with %d methods", len(c.Methods))
+	}
+
+	genSync(GatewayServiceProtoTemplate, path.Join(config.PbRoot, gatewayPackage, "fix.service.proto"), c, config)
+}
+
+// gatewayProtocArgs returns the protoc arguments generating the gRPC service, its gRPC-Gateway reverse proxy and
+// its OpenAPI spec
+func gatewayProtocArgs(config *Config) []string {
+	return []string{
+		"--go-grpc_out=" + config.GoRoot,
+		"--go-grpc_opt=paths=source_relative",
+		"--grpc-gateway_out=" + config.GoRoot,
+		"--grpc-gateway_opt=paths=source_relative",
+		"--openapiv2_out=" + config.GoRoot,
+		"--openapiv2_opt=allow_merge=true,merge_file_name=" + gatewayPackage + "/fix",
+	}
+}
+
+// gatewayProtoFile returns the gateway proto file relative to PbRoot, mapped to its Go package
+func gatewayProtoFile(config *Config) (string, string) {
+	return path.Join(gatewayPackage, "fix.service.proto"), fmt.Sprintf("%s/%s", config.PbGoPkg, gatewayPackage)
+}
@@ -35,6 +35,21 @@ var (
 	validate   = flag.Bool("validate", true, "Validate generated code (disable for faster generation)")
 	packageDoc = flag.String("package-doc", "", "Package documentation comment")
 	genProto   = flag.Bool("gen-proto", true, "Generate Go code from proto files using protoc")
+	perVersion = flag.Bool("per-version", false, "Generate a proto package per FIX version, sharing identical enums")
+	gateway    = flag.Bool("gateway", false, "Generate a gRPC service annotated for gRPC-Gateway, and its OpenAPI spec")
+	protoPath  = flag.String("proto-path", "", "Additional protoc import path, e.g. for google/api/annotations.proto")
+
+	flattenComponents = flag.Bool("flatten-components", false, "Inline the fields of nested components into each message, prefixed as set by -component-prefix")
+	componentPrefix   = flag.String("component-prefix", "component", "Prefix of the fields inlined by -flatten-components: none, component or path")
+	splitByMessage    = flag.Bool("split-by-message", false, "Generate a proto file per message, with the repeating groups in a shared fix.group.proto")
+	fieldNumberMap    = flag.String("field-number-map", "", "File persisting the proto field numbers across runs, keeping them stable as fields are added")
+	nameReport        = flag.String("name-report", "", "File the renames of colliding proto identifiers are written to, as JSON")
+	decimalMode       = flag.String("decimal-mode", "string", "Proto encoding of the FIX decimal types PRICE, QTY, AMT, PERCENTAGE and PRICEOFFSET: string or message")
+	timeMode          = flag.String("time-mode", "string", "Proto encoding of the FIX timestamp and date types UTCTIMESTAMP, UTCDATEONLY and UTCDATE: string or wellknown, as google.protobuf.Timestamp")
+	edition           = flag.String("edition", "proto3", "Syntax or edition of the generated proto files: proto3, proto2 or 2023. With proto2 and 2023 the fields have explicit presence")
+	postProcess       = flag.String("post-process", "", "Command each generated proto and Go file is piped through before it is written, run with the path of the file as last argument")
+	postProcessPlugin = flag.String("post-process-plugin", "", "Go plugin exporting PostProcess func(filename string, content []byte) ([]byte, error), called with each generated proto and Go file before it is written")
+	componentMessages = flag.Bool("component-messages", false, "Generate each component as its own proto message, with converters shared by the messages and repeating groups containing it")
 )
 
 // Config holds the validated configuration
@@ -48,11 +63,27 @@ type Config struct {
 	Validate   bool
 	PackageDoc string
 	GenProto   bool
+	PerVersion bool
+	Gateway    bool
+	ProtoPath  string
 	InputFiles []string
+
+	FlattenComponents bool
+	ComponentPrefix   string
+	SplitByMessage    bool
+	FieldNumberMap    string
+	NameReport        string
+	ComponentMessages bool
+	DecimalMode       string
+	TimeMode          string
+	Edition           string
+	PostProcess       string
+	PostProcessPlugin string
 }
 
 func usage() {
 	_, _ = fmt.Fprintf(os.Stderr, "usage: %v [flags] <path to data dictionary> ... \n", os.Args[0])
+	_, _ = fmt.Fprintf(os.Stderr, "Dictionaries of the same FIX version are merged, each overlaying the ones before it.\n")
 	_, _ = fmt.Fprintf(os.Stderr, "\nRequired flags:\n")
 	_, _ = fmt.Fprintf(os.Stderr, "  -pb_go_pkg string\n        Go package for generated protobuf files\n")
 	_, _ = fmt.Fprintf(os.Stderr, "  -pb_root string\n        Directory for generated proto files\n")
@@ -63,6 +94,20 @@ func usage() {
 	_, _ = fmt.Fprintf(os.Stderr, "  -dry-run\n        Perform dry run without writing files\n")
 	_, _ = fmt.Fprintf(os.Stderr, "  -validate\n        Validate generated code (default: true)\n")
 	_, _ = fmt.Fprintf(os.Stderr, "  -gen-proto\n        Generate Go code from proto files using protoc (default: true)\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -per-version\n        Generate a proto package per FIX version, sharing identical enums\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -gateway\n        Generate a gRPC service annotated for gRPC-Gateway, and its OpenAPI spec\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -proto-path string\n        Additional protoc import path, e.g. for google/api/annotations.proto\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -flatten-components\n        Inline the fields of nested components into each message, prefixed as set by -component-prefix\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -component-prefix string\n        Prefix of the fields inlined by -flatten-components: none, component or path (default: component)\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -split-by-message\n        Generate a proto file per message, with the repeating groups in a shared fix.group.proto\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -field-number-map string\n        File persisting the proto field numbers across runs, keeping them stable as fields are added\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -name-report string\n        File the renames of colliding proto identifiers are written to, as JSON\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -component-messages\n        Generate each component as its own proto message, with converters shared by the messages and repeating groups containing it\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -decimal-mode string\n        Proto encoding of the FIX decimal types PRICE, QTY, AMT, PERCENTAGE and PRICEOFFSET: string or message (default: string)\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -time-mode string\n        Proto encoding of the FIX timestamp and date types UTCTIMESTAMP, UTCDATEONLY and UTCDATE: string or wellknown, as google.protobuf.Timestamp (default: string)\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -edition string\n        Syntax or edition of the generated proto files: proto3, proto2 or 2023. With proto2 and 2023 the fields have explicit presence (default: proto3)\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -post-process string\n        Command each generated proto and Go file is piped through before it is written, run with the path of the file as last argument\n")
+	_, _ = fmt.Fprintf(os.Stderr, "  -post-process-plugin string\n        Go plugin exporting PostProcess func(filename string, content []byte) ([]byte, error), called with each generated proto and Go file before it is written\n")
 	_, _ = fmt.Fprintf(os.Stderr, "  -package-doc string\n        Package documentation comment\n")
 	_, _ = fmt.Fprintf(os.Stderr, "\nExample:\n")
 	_, _ = fmt.Fprintf(os.Stderr, "  %v -pb_go_pkg github.com/mycompany/proto -pb_root ./proto -go_root ./internal/proto -fix_pkg github.com/mycompany/quickfix spec/FIX44.xml\n", os.Args[0])
@@ -131,6 +176,34 @@ func validateConfig() (*Config, error) {
 		}
 	}
 
+	switch *componentPrefix {
+	case prefixNone, prefixComponent, prefixPath:
+	default:
+		return nil, fmt.Errorf("invalid -component-prefix: %s", *componentPrefix)
+	}
+
+	switch *decimalMode {
+	case decimalString, decimalMessage:
+	default:
+		return nil, fmt.Errorf("invalid -decimal-mode: %s", *decimalMode)
+	}
+
+	switch *timeMode {
+	case timeString, timeWellKnown:
+	default:
+		return nil, fmt.Errorf("invalid -time-mode: %s", *timeMode)
+	}
+
+	switch *edition {
+	case editionProto3, editionProto2, edition2023:
+	default:
+		return nil, fmt.Errorf("invalid -edition: %s", *edition)
+	}
+
+	if *componentMessages && (*flattenComponents || *splitByMessage) {
+		return nil, fmt.Errorf("-component-messages cannot be combined with -flatten-components or -split-by-message")
+	}
+
 	// Validate package name format
 	if !isValidGoPackage(*pbGoPkg) {
 		return nil, fmt.Errorf("invalid Go package name: %s", *pbGoPkg)
@@ -146,7 +219,22 @@ func validateConfig() (*Config, error) {
 		Validate:   *validate,
 		PackageDoc: *packageDoc,
 		GenProto:   *genProto,
+		PerVersion: *perVersion,
+		Gateway:    *gateway,
+		ProtoPath:  *protoPath,
 		InputFiles: inputFiles,
+
+		FlattenComponents: *flattenComponents,
+		ComponentPrefix:   *componentPrefix,
+		SplitByMessage:    *splitByMessage,
+		FieldNumberMap:    *fieldNumberMap,
+		NameReport:        *nameReport,
+		ComponentMessages: *componentMessages,
+		DecimalMode:       *decimalMode,
+		TimeMode:          *timeMode,
+		Edition:           *edition,
+		PostProcess:       *postProcess,
+		PostProcessPlugin: *postProcessPlugin,
 	}, nil
 }
 
@@ -184,6 +272,15 @@ func createDirectories(config *Config) error {
 		}
 	}
 
+	if config.Gateway {
+		if err := createDirIfNotExists(path.Join(config.PbRoot, gatewayPackage), "proto"); err != nil {
+			return err
+		}
+		if err := createDirIfNotExists(path.Join(goOutputDir, gatewayPackage), "Go"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -205,6 +302,7 @@ func createDirIfNotExists(dirPath, description string) error {
 
 func parseDataDictionaries(config *Config) ([]*datadictionary.DataDictionary, error) {
 	var specs []*datadictionary.DataDictionary
+	specIndex := make(map[string]int)
 
 	for _, dataDictPath := range config.InputFiles {
 		if config.Verbose {
@@ -221,6 +319,17 @@ func parseDataDictionaries(config *Config) ([]*datadictionary.DataDictionary, er
 				dataDictPath, spec.FIXType, spec.Major, spec.Minor)
 		}
 
+		// A dictionary of a version given already, such as the custom fields of a venue, overlays it
+		pkg := getPackageName(spec)
+		if i, ok := specIndex[pkg]; ok {
+			var conflicts []datadictionary.Conflict
+			specs[i], conflicts = datadictionary.Merge(specs[i], spec)
+			for _, c := range conflicts {
+				log.Printf("Merging %s: %v", dataDictPath, c)
+			}
+			continue
+		}
+		specIndex[pkg] = len(specs)
 		specs = append(specs, spec)
 	}
 
@@ -263,6 +372,10 @@ func (c messagesComponent) GetImportedPackages() []string {
 
 type fieldInfo struct {
 	*datadictionary.FieldDef
+	version *fixVersion
+
+	// Prefix is the prefix of the proto field of a component field inlined by -flatten-components
+	Prefix string
 }
 
 func (f fieldInfo) GoVariableName() string {
@@ -274,7 +387,7 @@ func (f fieldInfo) GoVariableName() string {
 }
 
 func (f fieldInfo) GoFieldName() string {
-	return toGoFieldName(f.Name())
+	return fieldGoName(f.Prefix, f.Name())
 }
 
 func (f fieldInfo) HasFIXFunctionName() string {
@@ -322,52 +435,71 @@ func (f fieldInfo) TypeConvert() string {
 	fieldName := f.GetProtoFieldName()
 	variableName := f.GoVariableName()
 
-	if len(f.Enums) > 0 {
-		//return fmt.Sprintf("_ = %s", variableName) // ignore
-		return fmt.Sprintf("pbMsg.%s = FIXTo%s[%s]", fieldName, f.Name(), variableName)
+	if enumMap, ok := f.fromFIXEnumMap(); ok {
+		return protoAssignEnum(fieldName, enumMap, variableName)
+	}
+
+	if wellKnownTime(f.Type) {
+		if f.Type == "UTCTIMESTAMP" {
+			return fmt.Sprintf("pbMsg.%s = %s(%s)", fieldName, f.sharedFunc("TimestampToProto"), variableName)
+		}
+		return fmt.Sprintf(`pbMsg.%s, err = %s(%s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to protobuf message: %%w", err)
+		}`, fieldName, f.sharedFunc("DateToProto"), variableName, f.Name())
 	}
 
 	switch f.Type {
 	case "STRING", "MULTIPLEVALUESTRING", "MULTIPLESTRINGVALUE", "MULTIPLECHARVALUE":
-		return fmt.Sprintf("pbMsg.%s = %s", fieldName, variableName)
+		return f.protoAssign(fieldName, variableName)
 	case "CHAR":
-		return fmt.Sprintf("pbMsg.%s = string(%s)", fieldName, variableName)
+		return f.protoAssign(fieldName, fmt.Sprintf("string(%s)", variableName))
 	case "LENGTH":
-		return fmt.Sprintf("pbMsg.%s = uint32(%s)", fieldName, variableName)
+		return f.protoAssign(fieldName, fmt.Sprintf("uint32(%s)", variableName))
 	case "INT", "SEQNUM", "TAGNUM", "DAYOFMONTH":
-		return fmt.Sprintf("pbMsg.%s = int32(%s)", fieldName, variableName)
+		return f.protoAssign(fieldName, fmt.Sprintf("int32(%s)", variableName))
 	case "NUMINGROUP":
 		return fmt.Sprintf("_ = %s", variableName) // ignore
 	case "AMT", "PERCENTAGE", "PRICE", "QTY", "PRICEOFFSET":
-		return fmt.Sprintf(`pbMsg.%s = %s.String()`, fieldName, variableName)
+		if *decimalMode == decimalMessage {
+			return fmt.Sprintf(`pbMsg.%s, err = %s(%s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to protobuf message: %%w", err)
+		}`, fieldName, f.sharedFunc("DecimalToProto"), variableName, f.Name())
+		}
+		return f.protoAssign(fieldName, fmt.Sprintf("%s.String()", variableName))
 	case "FLOAT":
 		//return "float64(" + variableName + ".Float64())"
+		if explicitPresence() {
+			return fmt.Sprintf(`value, _ := %s.Float64()
+		%s`, variableName, f.protoAssign(fieldName, "value"))
+		}
 		return fmt.Sprintf(`pbMsg.%s, _ = %s.Float64()`, fieldName, variableName)
 	case "BOOLEAN":
 		//return "bool(" + variableName + ")"
-		return fmt.Sprintf("pbMsg.%s = bool(%s)", fieldName, variableName)
+		return f.protoAssign(fieldName, fmt.Sprintf("bool(%s)", variableName))
 	case "UTCTIMESTAMP":
 		//return variableName + ".Unix()"
-		return fmt.Sprintf("pbMsg.%s = %s.Format(\"2006-01-02T15:04:05.999999999Z07:00\")", fieldName, variableName)
+		return f.protoAssign(fieldName, fmt.Sprintf("%s.Format(\"2006-01-02T15:04:05.999999999Z07:00\")", variableName))
 	case "UTCDATE", "UTCTIMEONLY", "LOCALMKTDATE", "TZTIMEONLY", "TZTIMESTAMP":
 		//return variableName + ".String()"
-		return fmt.Sprintf("pbMsg.%s = %s", fieldName, variableName)
+		return f.protoAssign(fieldName, variableName)
 	case "DATA", "XMLDATA":
 		//return "string(" + variableName + ")"
-		return fmt.Sprintf("pbMsg.%s = string(%s)", fieldName, variableName)
+		return f.protoAssign(fieldName, fmt.Sprintf("string(%s)", variableName))
 	case "CURRENCY", "EXCHANGE", "COUNTRY":
 		//return variableName + ".String()"
-		return fmt.Sprintf("pbMsg.%s = %s", fieldName, variableName)
+		return f.protoAssign(fieldName, variableName)
 	case "MONTHYEAR":
 		//return variableName + ".String()"
-		return fmt.Sprintf("pbMsg.%s = %s", fieldName, variableName)
+		return f.protoAssign(fieldName, variableName)
 	case "TENOR":
 		//return variableName + ".String()"
-		return fmt.Sprintf("pbMsg.%s = %s", fieldName, variableName)
+		return f.protoAssign(fieldName, variableName)
 	default:
 		// 对于未知类型，默认转换为字符串
 		//return variableName + ".String()"
-		return fmt.Sprintf("pbMsg.%s = %s", fieldName, variableName)
+		return f.protoAssign(fieldName, variableName)
 	}
 }
 
@@ -389,6 +521,12 @@ type messageInfo struct {
 	Name    string
 	Package string
 	*datadictionary.MessageDef
+	version *fixVersion
+}
+
+// MsgTypeSymbol returns the enum package symbol of the message type
+func (m *messageInfo) MsgTypeSymbol() string {
+	return globalFieldTypesLookup.Symbol("MsgType", m.MsgType)
 }
 
 func (m *messageInfo) EnumName() string {
@@ -441,10 +579,20 @@ func (m *messageInfo) FIXType() string {
 }
 
 func (m *messageInfo) GetFields() []fieldInfo {
+	if *flattenComponents {
+		fields := getMessageFields(m.MessageDef)
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Prefix+fields[i].Name() < fields[j].Prefix+fields[j].Name() })
+		out := make([]fieldInfo, len(fields))
+		for i, f := range fields {
+			out[i] = fieldInfo{FieldDef: f.FieldDef, version: m.version, Prefix: f.Prefix}
+		}
+		return out
+	}
+
 	fields := getFields(m.MessageDef)
 	out := make([]fieldInfo, len(fields))
 	for i, f := range fields {
-		out[i] = fieldInfo{FieldDef: f}
+		out[i] = fieldInfo{FieldDef: f, version: m.version}
 	}
 	return out
 }
@@ -457,6 +605,42 @@ func genAllMessages(specs []*datadictionary.DataDictionary, config *Config) {
 		waitGroup.Done()
 	}()
 
+	allMessages, packages := buildAllMessages(specs, config)
+
+	if config.Verbose {
+		log.Printf("Sorted %d messages for consistent generation order", len(allMessages))
+	}
+
+	c := messagesComponent{
+		GoPackagePrefix: *pbGoPkg,
+		QuickfixRoot:    *fixPkg,
+		Messages:        allMessages,
+		Packages:        packages,
+	}
+
+	// Generate enum proto file
+	genSync(EnumProtoTemplate, path.Join(*pbRoot, "fix.enum.proto"), c, config)
+
+	imports := []string{"fix.enum.proto"}
+	if config.DecimalMode == decimalMessage {
+		genSync(DecimalProtoTemplate, path.Join(*pbRoot, decimalProtoFile), c, config)
+		imports = append(imports, decimalProtoFile)
+	}
+	if config.TimeMode == timeWellKnown {
+		imports = append(imports, timestampProtoFile)
+	}
+
+	// Generate message proto files
+	if config.SplitByMessage {
+		genSplitMessages(c, extractPackageName(*pbGoPkg), "", imports, config,
+			func(t *template.Template, fileOut string, data interface{}) { genSync(t, fileOut, data, config) })
+		return
+	}
+	genSync(MessageProtoTemplate, path.Join(*pbRoot, "fix.message.proto"), c, config)
+}
+
+// buildAllMessages returns the messages of all specs and their QuickFIX packages, sorted for consistent generation
+func buildAllMessages(specs []*datadictionary.DataDictionary, config *Config) ([]messageInfo, []string) {
 	var packages []string
 
 	var allMessages []messageInfo
@@ -489,22 +673,7 @@ func genAllMessages(specs []*datadictionary.DataDictionary, config *Config) {
 		return packages[i] < packages[j]
 	})
 
-	if config.Verbose {
-		log.Printf("Sorted %d messages for consistent generation order", len(allMessages))
-	}
-
-	c := messagesComponent{
-		GoPackagePrefix: *pbGoPkg,
-		QuickfixRoot:    *fixPkg,
-		Messages:        allMessages,
-		Packages:        packages,
-	}
-
-	// Generate enum proto file
-	genSync(EnumProtoTemplate, path.Join(*pbRoot, "fix.enum.proto"), c, config)
-
-	// Generate message proto file
-	genSync(MessageProtoTemplate, path.Join(*pbRoot, "fix.message.proto"), c, config)
+	return allMessages, packages
 }
 
 func genSync(t *template.Template, fileOut string, data interface{}, config *Config) {
@@ -527,7 +696,7 @@ func genSync(t *template.Template, fileOut string, data interface{}, config *Con
 		return
 	}
 
-	if err := WriteFile(fileOut, writer.String()); err != nil {
+	if err := writeGenerated(fileOut, writer.String()); err != nil {
 		errors <- fmt.Errorf("failed to write %s: %w", fileOut, err)
 		return
 	}
@@ -576,7 +745,7 @@ func genEnumConversionFunctions(config *Config) {
 		return
 	}
 
-	if err := WriteFile(enumHelpersFile, writer.String()); err != nil {
+	if err := writeGenerated(enumHelpersFile, writer.String()); err != nil {
 		errors <- fmt.Errorf("failed to write %s: %w", enumHelpersFile, err)
 		return
 	}
@@ -634,6 +803,13 @@ func genConversionFunctions(specs []*datadictionary.DataDictionary, config *Conf
 		Packages:        packages,
 	}
 
+	if config.DecimalMode == decimalMessage {
+		genSync(DecimalConversionGoTemplate, path.Join(config.GoRoot, decimalConversionFile), c, config)
+	}
+	if config.TimeMode == timeWellKnown {
+		genSync(TimeConversionGoTemplate, path.Join(config.GoRoot, timeConversionFile), c, config)
+	}
+
 	// Generate FIX to Proto conversion functions directly without using gen()
 	fixToProtoFile := path.Join(config.GoRoot, "fix.message.conversion.go")
 
@@ -659,7 +835,7 @@ func genConversionFunctions(specs []*datadictionary.DataDictionary, config *Conf
 		return
 	}
 
-	if err := WriteFile(fixToProtoFile, writer.String()); err != nil {
+	if err := writeGenerated(fixToProtoFile, writer.String()); err != nil {
 		errors <- fmt.Errorf("failed to write %s: %w", fixToProtoFile, err)
 		return
 	}
@@ -670,7 +846,7 @@ func genConversionFunctions(specs []*datadictionary.DataDictionary, config *Conf
 	}
 }
 
-func genProtoGoCode(config *Config) error {
+func genProtoGoCode(config *Config, specs []*datadictionary.DataDictionary, versions []*fixVersion) error {
 	if !config.GenProto {
 		if config.Verbose {
 			log.Printf("Skipping protoc code generation (disabled)")
@@ -682,23 +858,67 @@ func genProtoGoCode(config *Config) error {
 		log.Printf("Generating Go code from proto files using protoc...")
 	}
 
-	enumProtoFile := path.Join(config.PbRoot, "fix.enum.proto")
-	messageProtoFile := path.Join(config.PbRoot, "fix.message.proto")
+	// Proto files relative to PbRoot, mapped to their Go package
+	protoFiles := map[string]string{
+		"fix.enum.proto": config.PbGoPkg,
+	}
+	if config.DecimalMode == decimalMessage {
+		protoFiles[decimalProtoFile] = config.PbGoPkg
+	}
+	if versions == nil {
+		if config.SplitByMessage {
+			messages, _ := buildAllMessages(specs, config)
+			for _, file := range splitProtoFiles("", messages) {
+				protoFiles[file] = config.PbGoPkg
+			}
+		} else {
+			protoFiles["fix.message.proto"] = config.PbGoPkg
+		}
+	}
+	for _, v := range versions {
+		if len(v.Enums) > 0 {
+			protoFiles[path.Join(v.Name, "fix.enum.proto")] = v.goPackage(config)
+		}
+		if config.SplitByMessage {
+			for _, file := range splitProtoFiles(v.Name, v.component(config).Messages) {
+				protoFiles[file] = v.goPackage(config)
+			}
+		} else {
+			protoFiles[path.Join(v.Name, "fix.message.proto")] = v.goPackage(config)
+		}
+	}
+	if config.Gateway {
+		file, goPkg := gatewayProtoFile(config)
+		protoFiles[file] = goPkg
+	}
 
 	// Check if protoc is available
 	if _, err := exec.LookPath("protoc"); err != nil {
 		return fmt.Errorf("protoc not found in PATH. Please install Protocol Buffers compiler: %w", err)
 	}
 
-	// Build protoc command for both proto files
+	// Build protoc command for all proto files
 	args := []string{
 		"--proto_path=" + config.PbRoot,
 		"--go_out=" + config.GoRoot,
 		"--go_opt=paths=source_relative",
-		"--go_opt=M" + path.Base(enumProtoFile) + "=" + config.PbGoPkg,
-		"--go_opt=M" + path.Base(messageProtoFile) + "=" + config.PbGoPkg,
-		enumProtoFile,
-		messageProtoFile,
+	}
+	if config.ProtoPath != "" {
+		args = append(args, "--proto_path="+config.ProtoPath)
+	}
+	if config.Gateway {
+		args = append(args, gatewayProtocArgs(config)...)
+	}
+	var files []string
+	for file := range protoFiles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		args = append(args, "--go_opt=M"+file+"="+protoFiles[file])
+	}
+	for _, file := range files {
+		args = append(args, path.Join(config.PbRoot, file))
 	}
 
 	if config.Verbose {
@@ -743,6 +963,10 @@ func main() {
 		log.Printf("Starting generation with config: %+v", config)
 	}
 
+	if globalPostProcess, err = LoadPostProcess(config); err != nil {
+		log.Fatalf("Post-process error: %v", err)
+	}
+
 	// Create directories
 	if err = createDirectories(config); err != nil {
 		log.Fatalf("Directory creation error: %v", err)
@@ -754,49 +978,89 @@ func main() {
 		log.Fatalf("Data dictionary parsing error: %v", err)
 	}
 
+	// Per version enums are registered before the global field types merge enums across specifications
+	var versions []*fixVersion
+	if config.PerVersion {
+		if config.Verbose {
+			log.Printf("Building per version enums from %d specifications", len(specs))
+		}
+		versions = buildVersions(specs)
+		if err = createVersionDirectories(config, versions); err != nil {
+			log.Fatalf("Directory creation error: %v", err)
+		}
+	}
+
 	if config.Verbose {
 		log.Printf("Building global field types from %d specifications", len(specs))
 	}
 
 	BuildGlobalFieldTypes(specs)
+	globalNames.ResolveFieldNames(specs)
 
-	// Initialize enum registry with parsed specifications
-	if config.Verbose {
-		log.Printf("Initializing enum registry...")
+	if config.FieldNumberMap != "" {
+		if globalFieldNumbers, err = LoadFieldNumberMap(config.FieldNumberMap); err != nil {
+			log.Fatalf("Field number map error: %v", err)
+		}
 	}
-	InitializeEnumRegistry(specs)
 
 	// Generate files
 	if config.Verbose {
 		log.Printf("Generating protobuf files...")
 	}
 
-	// Generate proto files (enum and message)
-	if config.Verbose {
-		log.Printf("Adding 1 to waitGroup for genAllMessages")
-	}
-	waitGroup.Add(1) // genAllMessages now handles both files synchronously
-	go func() {
-		genAllMessages(specs, config)
-	}()
+	if config.PerVersion {
+		if config.Verbose {
+			log.Printf("Adding 1 to waitGroup for genVersions")
+		}
+		waitGroup.Add(1)
+		go func() {
+			genVersions(versions, config)
+		}()
+	} else {
+		// Initialize enum registry with parsed specifications
+		if config.Verbose {
+			log.Printf("Initializing enum registry...")
+		}
+		InitializeEnumRegistry(specs)
 
-	// Generate conversion functions
-	if config.Verbose {
-		log.Printf("Adding 1 to waitGroup for genConversionFunctions")
+		// Generate proto files (enum and message)
+		if config.Verbose {
+			log.Printf("Adding 1 to waitGroup for genAllMessages")
+		}
+		waitGroup.Add(1) // genAllMessages now handles both files synchronously
+		go func() {
+			genAllMessages(specs, config)
+		}()
+
+		// Generate conversion functions
+		if config.Verbose {
+			log.Printf("Adding 1 to waitGroup for genConversionFunctions")
+		}
+		waitGroup.Add(1)
+		go func() {
+			genConversionFunctions(specs, config)
+		}()
+
+		// Generate enum helper functions
+		if config.Verbose {
+			log.Printf("Adding 1 to waitGroup for genEnumConversionFunctions")
+		}
+		waitGroup.Add(1)
+		go func() {
+			genEnumConversionFunctions(config)
+		}()
 	}
-	waitGroup.Add(1)
-	go func() {
-		genConversionFunctions(specs, config)
-	}()
 
-	// Generate enum helper functions
-	if config.Verbose {
-		log.Printf("Adding 1 to waitGroup for genEnumConversionFunctions")
+	// Generate gateway service
+	if config.Gateway {
+		if config.Verbose {
+			log.Printf("Adding 1 to waitGroup for genGateway")
+		}
+		waitGroup.Add(1)
+		go func() {
+			genGateway(specs, config)
+		}()
 	}
-	waitGroup.Add(1)
-	go func() {
-		genEnumConversionFunctions(config)
-	}()
 
 	go func() {
 		if config.Verbose {
@@ -822,8 +1086,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Persist the field numbers assigned to new fields
+	if globalFieldNumbers != nil && !config.DryRun {
+		if err := globalFieldNumbers.Save(config.FieldNumberMap); err != nil {
+			log.Fatalf("Field number map error: %v", err)
+		}
+	}
+
+	// Report the renames of colliding identifiers
+	reportFile := config.NameReport
+	if config.DryRun {
+		reportFile = ""
+	}
+	if err := globalNames.Report(reportFile); err != nil {
+		log.Fatalf("Name report error: %v", err)
+	}
+
 	// Generate Go code from proto files using protoc
-	if err := genProtoGoCode(config); err != nil {
+	if err := genProtoGoCode(config, specs, versions); err != nil {
 		log.Fatalf("Protoc generation error: %v", err)
 	}
 
@@ -25,19 +25,3 @@ func (h *ErrorHandler) Err() error {
 	}
 	return nil
 }
-
-// WriteFile writes content to a file, creating directories as needed
-func WriteFile(filename, content string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to close file %s: %v\n", filename, closeErr)
-		}
-	}()
-
-	_, err = file.WriteString(content)
-	return err
-}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/quickfixgo/quickfix/cmd/internal/codegen"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// Global name resolutions of the proto identifiers colliding with each other
+var globalNames = &NameResolver{fieldSuffixes: make(map[string]string)}
+
+// Kinds of NameRename
+const (
+	renameField = "field"
+	renameEnum  = "enum"
+)
+
+// NameRename is a proto identifier renamed because it collides with another, see -name-report
+type NameRename struct {
+	Kind         string   `json:"kind"`
+	Scope        string   `json:"scope,omitempty"` // FIX version of an enum generated with -per-version
+	Name         string   `json:"name"`
+	Tag          int      `json:"tag"`
+	Generated    string   `json:"generated"`
+	Renamed      string   `json:"renamed"`
+	CollidesWith []string `json:"collides_with"`
+}
+
+// NameResolver disambiguates the fields whose proto or Go names collide, such as field names that only differ by
+// case or sanitize to the same identifier. Of colliding names, the one with the lowest tag keeps its identifier, the
+// others are suffixed with their tag, so the resolution does not depend on the order of the specifications.
+type NameResolver struct {
+	mu            sync.Mutex
+	fieldSuffixes map[string]string // Key: FIX field name
+	renames       []NameRename
+}
+
+// ResolveFieldNames resolves the collisions between the names of the fields of specs
+func (r *NameResolver) ResolveFieldNames(specs []*datadictionary.DataDictionary) {
+	tags := make(map[string]int)
+	for _, spec := range specs {
+		for name, field := range spec.FieldTypeByName {
+			if tag, ok := tags[name]; !ok || field.Tag() < tag {
+				tags[name] = field.Tag()
+			}
+		}
+	}
+
+	groups := make(map[string][]string)
+	for name := range tags {
+		key := protoFieldNameToGoFieldName(sanitizeProtoFieldName(name))
+		groups[key] = append(groups[key], name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, names := range groups {
+		if len(names) < 2 {
+			continue
+		}
+		sortByTag(names, tags)
+		for _, name := range names[1:] {
+			suffix := "_tag" + strconv.Itoa(tags[name])
+			r.fieldSuffixes[name] = suffix
+			r.renames = append(r.renames, NameRename{
+				Kind:         renameField,
+				Name:         name,
+				Tag:          tags[name],
+				Generated:    sanitizeProtoFieldName(name),
+				Renamed:      sanitizeProtoFieldName(name) + suffix,
+				CollidesWith: without(names, name),
+			})
+		}
+	}
+}
+
+// resolveEnumNames disambiguates the enums of registry whose proto names collide. The values of an enum are
+// prefixed by its upper cased name, so names only differing by case collide as well.
+func (r *NameResolver) resolveEnumNames(registry *EnumRegistry, scope string) {
+	tags := make(map[string]int)
+	groups := make(map[string][]string)
+	for name, enum := range registry.enums {
+		tags[name] = enum.Tag
+		key := strings.ToUpper(enum.ProtoName)
+		groups[key] = append(groups[key], name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, names := range groups {
+		if len(names) < 2 {
+			continue
+		}
+		sortByTag(names, tags)
+		for _, name := range names[1:] {
+			enum := registry.enums[name]
+			generated := enum.ProtoName
+			enum.ProtoName += "Tag" + strconv.Itoa(enum.Tag)
+			r.renames = append(r.renames, NameRename{
+				Kind:         renameEnum,
+				Scope:        scope,
+				Name:         name,
+				Tag:          enum.Tag,
+				Generated:    generated,
+				Renamed:      enum.ProtoName,
+				CollidesWith: without(names, name),
+			})
+		}
+	}
+}
+
+// Renames returns the renames, sorted by kind, scope and tag
+func (r *NameResolver) Renames() []NameRename {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	renames := append([]NameRename{}, r.renames...)
+	sort.Slice(renames, func(i, j int) bool {
+		a, b := renames[i], renames[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		if a.Tag != b.Tag {
+			return a.Tag < b.Tag
+		}
+		return a.Name < b.Name
+	})
+	return renames
+}
+
+// Report logs the renames and writes them to file as JSON, unless file is empty
+func (r *NameResolver) Report(file string) error {
+	renames := r.Renames()
+	for _, rename := range renames {
+		log.Printf("Renamed %s %s (tag %d) from %s to %s, colliding with %s", rename.Kind, rename.Name, rename.Tag,
+			rename.Generated, rename.Renamed, strings.Join(rename.CollidesWith, ", "))
+	}
+	if file == "" {
+		return nil
+	}
+
+	if renames == nil {
+		renames = []NameRename{}
+	}
+	data, err := json.MarshalIndent(renames, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode name report: %w", err)
+	}
+	if err := codegen.WriteFile(file, string(data)+"\n"); err != nil {
+		return fmt.Errorf("failed to write name report %s: %w", file, err)
+	}
+	return nil
+}
+
+// fieldProtoName returns the proto field name of the FIX field name, prefixed by prefix with -flatten-components
+func (r *NameResolver) fieldProtoName(prefix, name string) string {
+	r.mu.Lock()
+	suffix := r.fieldSuffixes[name]
+	r.mu.Unlock()
+	return sanitizeProtoFieldName(prefix+name) + suffix
+}
+
+// fieldProtoName returns the proto field name of the FIX field name
+func fieldProtoName(name string) string {
+	return globalNames.fieldProtoName("", name)
+}
+
+// fieldGoName returns the name of the Go struct field generated by protoc for the FIX field name
+func fieldGoName(prefix, name string) string {
+	return protoFieldNameToGoFieldName(globalNames.fieldProtoName(prefix, name))
+}
+
+// sortByTag sorts names by tag, then name
+func sortByTag(names []string, tags map[string]int) {
+	sort.Slice(names, func(i, j int) bool {
+		if tags[names[i]] != tags[names[j]] {
+			return tags[names[i]] < tags[names[j]]
+		}
+		return names[i] < names[j]
+	})
+}
+
+func without(names []string, name string) []string {
+	var others []string
+	for _, n := range names {
+		if n != name {
+			others = append(others, n)
+		}
+	}
+	return others
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/quickfixgo/quickfix/cmd/internal/codegen"
+)
+
+// globalPostProcess post-processes the generated files, nil unless -post-process or -post-process-plugin is set
+var globalPostProcess codegen.PostProcessFunc
+
+// LoadPostProcess returns the post-processing of -post-process and -post-process-plugin, the command piped after
+// the plugin if both are set. Returns nil if neither is set.
+func LoadPostProcess(config *Config) (codegen.PostProcessFunc, error) {
+	return codegen.LoadPostProcess(config.PostProcessPlugin, config.PostProcess)
+}
+
+// writeGenerated writes a generated file, post-processed with globalPostProcess if set
+func writeGenerated(filename, content string) error {
+	return globalPostProcess.Write(filename, content)
+}
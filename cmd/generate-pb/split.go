@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path"
+	"text/template"
+)
+
+// groupProtoFile is the proto file of the repeating groups shared by the messages, see -split-by-message
+const groupProtoFile = "fix.group.proto"
+
+// protoFileComponent is the template data of a proto file generated with -split-by-message
+type protoFileComponent struct {
+	messagesComponent
+	Package string
+	Imports []string
+}
+
+// messageProtoFile returns the proto file of the message name generated with -split-by-message
+func messageProtoFile(name string) string {
+	return "fix.message." + sanitizeProtoFieldName(name) + ".proto"
+}
+
+// splitProtoFiles returns the proto files generated with -split-by-message for messages, relative to PbRoot, with
+// dir the directory of the files, empty unless -per-version is set
+func splitProtoFiles(dir string, messages []messageInfo) []string {
+	files := []string{path.Join(dir, groupProtoFile)}
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		file := path.Join(dir, messageProtoFile(msg.Name))
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// genSplitMessages generates a proto file per message of c into the directory dir of PbRoot, with the repeating
+// groups of all messages in a shared file. imports are the enum proto files imported by every file.
+func genSplitMessages(c messagesComponent, pkg, dir string, imports []string, config *Config,
+	gen func(t *template.Template, fileOut string, data interface{})) {
+	groups := protoFileComponent{messagesComponent: c, Package: pkg, Imports: imports}
+	gen(SplitGroupProtoTemplate, path.Join(config.PbRoot, dir, groupProtoFile), groups)
+
+	messageImports := append(append([]string{}, imports...), path.Join(dir, groupProtoFile))
+	for _, msg := range c.Messages {
+		single := c
+		single.Messages = []messageInfo{msg}
+		data := protoFileComponent{messagesComponent: single, Package: pkg, Imports: messageImports}
+		gen(SplitProtoTemplate, path.Join(config.PbRoot, dir, messageProtoFile(msg.Name)), data)
+	}
+}
@@ -14,18 +14,115 @@ import "{{.QuickfixRoot}}/enum"
 {{.GenerateEnumStringMapping}}{{end}}
 `))
 
+// DecimalConversionGoTemplate generates the conversion functions between decimals and the Decimal proto message,
+// see -decimal-mode
+var DecimalConversionGoTemplate = template.Must(template.New("fix.decimal.conversion.go").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+// This file contains conversion functions between decimals and protobuf Decimal messages.
+
+package {{extractPackageName .GoPackagePrefix}}
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"{{if explicitPresence}}
+	"google.golang.org/protobuf/proto"{{end}}
+)
+
+// DecimalToProto converts a decimal to a protobuf Decimal. Returns an error if its unscaled value overflows an int64.
+func DecimalToProto(d decimal.Decimal) (*Decimal, error) {
+	unscaled := d.Coefficient()
+	if !unscaled.IsInt64() {
+		return nil, fmt.Errorf("unscaled value of %v overflows int64", d)
+	}
+{{if explicitPresence}}	return &Decimal{Unscaled: proto.Int64(unscaled.Int64()), Scale: proto.Int32(-d.Exponent())}, nil
+{{else}}	return &Decimal{Unscaled: unscaled.Int64(), Scale: -d.Exponent()}, nil
+{{end}}}
+
+// DecimalFromProto converts a protobuf Decimal to a decimal
+func DecimalFromProto(d *Decimal) decimal.Decimal {
+	return decimal.New(d.GetUnscaled(), -d.GetScale())
+}
+`))
+
+// TimeConversionGoTemplate generates the conversion functions between the FIX timestamp and date types and
+// google.protobuf.Timestamp, see -time-mode
+var TimeConversionGoTemplate = template.Must(template.New("fix.time.conversion.go").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+// This file contains conversion functions between FIX timestamps and dates and protobuf Timestamps.
+
+package {{extractPackageName .GoPackagePrefix}}
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TimestampPrecision is the precision of the FIX UTCTimestamp fields converted from protobuf Timestamps. Set it to
+// the TimestampPrecision of the session the messages are sent to.
+var TimestampPrecision = quickfix.Millis
+
+// dateLayout is the layout of the FIX UTCDateOnly type
+const dateLayout = "20060102"
+
+// TimestampToProto converts the time of a FIX UTCTimestamp to a protobuf Timestamp
+func TimestampToProto(t time.Time) *timestamppb.Timestamp {
+	return timestamppb.New(t)
+}
+
+// TimestampFromProto converts a protobuf Timestamp to the time of a FIX UTCTimestamp, truncated to TimestampPrecision
+func TimestampFromProto(ts *timestamppb.Timestamp) time.Time {
+	t := ts.AsTime()
+	switch TimestampPrecision {
+	case quickfix.Seconds:
+		return t.Truncate(time.Second)
+	case quickfix.Millis:
+		return t.Truncate(time.Millisecond)
+	case quickfix.Micros:
+		return t.Truncate(time.Microsecond)
+	}
+	return t
+}
+
+// DateToProto converts a FIX UTCDateOnly to a protobuf Timestamp at midnight UTC of the date
+func DateToProto(date string) (*timestamppb.Timestamp, error) {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UTCDateOnly %q: %w", date, err)
+	}
+	return timestamppb.New(t), nil
+}
+
+// DateFromProto converts a protobuf Timestamp to the FIX UTCDateOnly of its UTC date
+func DateFromProto(ts *timestamppb.Timestamp) string {
+	return ts.AsTime().Format(dateLayout)
+}
+`))
+
 // MessageConversionGoTemplate generates conversion functions from FIX messages to protobuf messages
 var MessageConversionGoTemplate = template.Must(template.New("fix.message.conversion.go").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
-// This file contains conversion functions from FIX messages to protobuf messages.
+// This file contains conversion functions between FIX messages and protobuf messages.
 
 package {{extractPackageName .GoPackagePrefix}}
 
 import (
 	"fmt"
+{{- if .FromProtoUsesTime}}
+	"time"
+{{- end}}
+
+	"github.com/shopspring/decimal"
 
 	"github.com/quickfixgo/quickfix"
 	"google.golang.org/protobuf/proto"
 	"xsyphon.com/bi/fix/api/fix/enum"
+{{- if .FromProtoUsesField}}
+	"{{.QuickfixRoot}}/field"
+{{- end}}
+{{- if .ComponentMessages}}
+	"{{.QuickfixRoot}}/tag"
+{{- end}}
 {{- range .Packages}}
 	"{{.}}"
 {{- end}}
@@ -39,13 +136,155 @@ type ConvertFunc func(*quickfix.Message) (proto.Message, error)
 
 func init() {
 {{- range .Messages}}
-	Fix2PBMap[enum.MsgType_{{.EnumName}}] = func(message *quickfix.Message) (proto.Message, error) {
+	Fix2PBMap[{{.MsgTypeSymbol}}] = func(message *quickfix.Message) (proto.Message, error) {
 		return {{.Name}}FromFIX({{.PkgName}}.FromMessage(message))
 	}
 {{- end}}
 }
 
-{{range .Messages}}
+` + messageConversionBody))
+
+// VersionMessageConversionGoTemplate generates the conversion functions of one FIX version, registered with the
+// shared package on import, see -per-version
+var VersionMessageConversionGoTemplate = template.Must(template.New("version.fix.message.conversion.go").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+// This file contains conversion functions between {{.Version}} FIX messages and protobuf messages.
+
+package {{.Version}}
+
+import (
+	"fmt"
+{{- if .FromProtoUsesTime}}
+	"time"
+{{- end}}
+
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/quickfix"
+	"google.golang.org/protobuf/proto"
+	"{{.QuickfixRoot}}/enum"
+{{- if .FromProtoUsesField}}
+	"{{.QuickfixRoot}}/field"
+{{- end}}
+{{- if .ComponentMessages}}
+	"{{.QuickfixRoot}}/tag"
+{{- end}}
+	"{{.SharedGoPackage}}"
+{{- range .Packages}}
+	"{{.}}"
+{{- end}}
+)
+
+var (
+	Fix2PBMap = make(map[enum.MsgType]{{.SharedPackage}}.ConvertFunc)
+)
+
+func init() {
+{{- range .Messages}}
+	Fix2PBMap[{{.MsgTypeSymbol}}] = func(message *quickfix.Message) (proto.Message, error) {
+		return {{.Name}}FromFIX({{.PkgName}}.FromMessage(message))
+	}
+{{- end}}
+	{{.SharedPackage}}.RegisterConversions("{{.Version}}", Fix2PBMap)
+}
+
+` + messageConversionBody))
+
+// ConversionRegistryGoTemplate generates the registry of the conversions of every FIX version, see -per-version
+var ConversionRegistryGoTemplate = template.Must(template.New("fix.conversion.registry.go").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+// This file contains the registry of the conversion functions of each FIX version.
+
+package {{extractPackageName .GoPackagePrefix}}
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+	"google.golang.org/protobuf/proto"
+	"{{.QuickfixRoot}}/enum"
+	"{{.QuickfixRoot}}/tag"
+)
+
+type ConvertFunc func(*quickfix.Message) (proto.Message, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]map[enum.MsgType]ConvertFunc)
+
+	// beginStringVersions maps a BeginString to the FIX version of its session messages
+	beginStringVersions = map[string]string{
+{{- range .Versions}}{{if .BeginString}}
+		"{{.BeginString}}": "{{.Name}}",{{end}}
+{{- end}}
+	}
+
+	// applVerIDVersions maps an ApplVerID to the FIX version of its application messages
+	applVerIDVersions = map[string]string{
+{{- range .Versions}}{{if .ApplVerID}}
+		"{{.ApplVerID}}": "{{.Name}}",{{end}}
+{{- end}}
+	}
+)
+
+// RegisterConversions registers the conversion functions of a FIX version, keyed by message type.
+// The generated version packages register themselves when imported.
+func RegisterConversions(version string, conversions map[enum.MsgType]ConvertFunc) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[version] = conversions
+}
+
+// FromFIX converts a FIX message with the conversion functions of its FIX version. The version is taken from the
+// ApplVerID of the message if present, falling back to its BeginString.
+func FromFIX(msg *quickfix.Message) (proto.Message, error) {
+	msgType, err := msg.MsgType()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	if applVerID, err := msg.Header.GetString(tag.ApplVerID); err == nil {
+		versions = append(versions, applVerIDVersions[applVerID])
+	}
+	beginString, err := msg.Header.GetString(tag.BeginString)
+	if err != nil {
+		return nil, err
+	}
+	versions = append(versions, beginStringVersions[beginString])
+
+	for _, version := range versions {
+		if convert, ok := lookupConversion(version, enum.MsgType(msgType)); ok {
+			return convert(msg)
+		}
+	}
+	return nil, fmt.Errorf("no conversion registered for message type %v of %v", msgType, beginString)
+}
+
+// FromFIXVersion converts a FIX message with the conversion functions of the given FIX version, such as the version
+// of the DefaultApplVerID of a FIXT session whose messages carry no ApplVerID.
+func FromFIXVersion(version string, msg *quickfix.Message) (proto.Message, error) {
+	msgType, err := msg.MsgType()
+	if err != nil {
+		return nil, err
+	}
+
+	convert, ok := lookupConversion(version, enum.MsgType(msgType))
+	if !ok {
+		return nil, fmt.Errorf("no conversion registered for message type %v of %v", msgType, version)
+	}
+	return convert(msg)
+}
+
+func lookupConversion(version string, msgType enum.MsgType) (ConvertFunc, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	convert, ok := registry[version][msgType]
+	return convert, ok
+}
+`))
+
+// messageConversionBody is the conversion functions shared by the message conversion templates
+const messageConversionBody = `{{if .ComponentMessages}}` + componentConversionBody + `{{else}}{{range .Messages}}
 // {{.Name}}FromFIX converts a FIX {{.Name}} message to protobuf {{.Name}}
 func {{.Name}}FromFIX(fixMsg {{.FIXType}}) (*{{.Name}}, error) {
 	pbMsg := &{{.Name}}{}
@@ -55,5 +294,105 @@ func {{.Name}}FromFIX(fixMsg {{.FIXType}}) (*{{.Name}}, error) {
 	return pbMsg, nil
 }
 
+// {{.Name}}FromProto converts a protobuf {{.Name}} to a FIX {{.Name}} message, built with {{.PkgName}}.New so that
+// its header and required fields are set. Returns an error if a required field is not set.
+func {{.Name}}FromProto(pbMsg *{{.Name}}) ({{.FIXType}}, error) {
+	var fixMsg {{.FIXType}}
+{{- range .ConstructorFields}}{{.ConstructorCodes}}{{end}}
+	fixMsg = {{.PkgName}}.New({{.ConstructorArgs}})
+{{- range .SetterFields}}{{.SetterCodes}}{{end}}
+	return fixMsg, nil
+}
+
+{{end}}{{end}}
+// ToFIX converts a protobuf message to a FIX message with the FromProto function of its type
+func ToFIX(pbMsg proto.Message) (*quickfix.Message, error) {
+	switch pbMsg := pbMsg.(type) {
+{{- range .Messages}}
+	case *{{.Name}}:
+		fixMsg, err := {{.Name}}FromProto(pbMsg)
+		if err != nil {
+			return nil, err
+		}
+		return fixMsg.ToMessage(), nil
+{{- end}}
+	}
+	return nil, fmt.Errorf("no conversion to FIX for %T", pbMsg)
+}
+
+// decimalScale returns the number of digits after the decimal point of d
+func decimalScale(d decimal.Decimal) int32 {
+	if d.Exponent() < 0 {
+		return -d.Exponent()
+	}
+	return 0
+}
+`
+
+// componentConversionBody is the conversion functions of the messages, components and repeating group entries
+// generated with -component-messages. The components and group entries are converted from and to a FIX field map,
+// so that their converters are shared by the messages and group entries containing them.
+const componentConversionBody = `{{range .Messages}}
+// {{.Name}}FromFIX converts a FIX {{.Name}} message to protobuf {{.Name}}
+func {{.Name}}FromFIX(fixMsg {{.FIXType}}) (*{{.Name}}, error) {
+	pbMsg := &{{.Name}}{}
+{{- if .ProtoParts}}
+	fieldMap := &fixMsg.Body.FieldMap
+{{- range .ProtoParts}}{{.ToProtoCodes}}{{end}}
+{{- end}}
+	return pbMsg, nil
+}
+
+// {{.Name}}FromProto converts a protobuf {{.Name}} to a FIX {{.Name}} message. The fields, components and repeating
+// groups not set in pbMsg are not set in the FIX message.
+func {{.Name}}FromProto(pbMsg *{{.Name}}) ({{.FIXType}}, error) {
+	fixMsg := {{.PkgName}}.FromMessage(quickfix.NewMessage())
+	fixMsg.Header.SetField(tag.BeginString, quickfix.FIXString("{{.BeginString}}"))
+	fixMsg.Header.SetField(tag.MsgType, quickfix.FIXString({{.MsgTypeSymbol}}))
+{{- if .ProtoParts}}
+	fieldMap := &fixMsg.Body.FieldMap
+{{- range .ProtoParts}}{{.FromProtoCodes "fixMsg, "}}{{end}}
+{{- end}}
+	return fixMsg, nil
+}
+
 {{end}}
-`))
+{{- range .ProtoComponents}}
+{{- if .IsGroup}}
+// {{.TemplateFunc}} returns the template of {{.Description}}
+func {{.TemplateFunc}}() quickfix.GroupTemplate {
+	return quickfix.GroupTemplate{
+{{- range .TemplateItems}}
+		{{.}},
+{{- end}}
+	}
+}
+{{- else}}
+// {{.TagsVar}} are the tags of {{.Description}}, present in a field map holding it
+var {{.TagsVar}} = []quickfix.Tag{ {{- .Tags -}} }
+{{- end}}
+
+// {{.Name}}ToProto converts {{.Description}} of a FIX field map to protobuf {{.Name}}
+func {{.Name}}ToProto(fieldMap *quickfix.FieldMap) (*{{.Name}}, error) {
+	pbMsg := &{{.Name}}{}
+{{- range .ProtoParts}}{{.ToProtoCodes}}{{end}}
+	return pbMsg, nil
+}
+
+// {{.Name}}FromProto sets {{.Description}} of a FIX field map from protobuf {{.Name}}
+func {{.Name}}FromProto(pbMsg *{{.Name}}, fieldMap *quickfix.FieldMap) error {
+{{- range .ProtoParts}}{{.FromProtoCodes ""}}{{end}}
+	return nil
+}
+
+{{end}}
+// hasAnyTag returns true if fieldMap holds any of tags
+func hasAnyTag(fieldMap *quickfix.FieldMap, tags []quickfix.Tag) bool {
+	for _, t := range tags {
+		if fieldMap.Has(t) {
+			return true
+		}
+	}
+	return false
+}
+`
@@ -16,6 +16,7 @@ var globalEnumRegistry *EnumRegistry
 func InitializeEnumRegistry(specs []*datadictionary.DataDictionary) {
 	globalEnumRegistry = NewEnumRegistry()
 	globalEnumRegistry.RegisterFieldEnums(specs)
+	globalNames.resolveEnumNames(globalEnumRegistry, "")
 }
 
 // Template helper functions for protobuf generation
@@ -52,6 +53,10 @@ func toProtoType(fixType string) string {
 		fixType = baseType
 	}
 
+	if wellKnownTime(strings.ToUpper(fixType)) {
+		return timestampProtoMessage
+	}
+
 	// Map to protobuf primitive types
 	switch strings.ToUpper(fixType) {
 	case "INT", "SEQNUM", "NUMINGROUP", "DAYOFMONTH":
@@ -61,6 +66,9 @@ func toProtoType(fixType string) string {
 	case "FLOAT":
 		return "double"
 	case "PRICE", "PRICEOFFSET", "QTY", "PERCENTAGE", "AMT":
+		if *decimalMode == decimalMessage {
+			return decimalProtoMessage
+		}
 		return "string" // Use string for decimal types to preserve precision
 	case "CHAR":
 		return "string" // Single char as string in proto
@@ -322,6 +330,79 @@ type componentReference struct {
 	Required bool
 }
 
+// Values of -component-prefix
+const (
+	prefixNone      = "none"
+	prefixComponent = "component"
+	prefixPath      = "path"
+)
+
+// messageField is a field of a generated message. With -flatten-components the fields of nested components are
+// inlined with a prefix naming their component.
+type messageField struct {
+	*datadictionary.FieldDef
+	Prefix   string
+	Required bool
+}
+
+// ProtoName returns the name of the proto field
+func (f messageField) ProtoName() string {
+	return globalNames.fieldProtoName(f.Prefix, f.FieldType.Name())
+}
+
+// getMessageFields returns the fields of a MessageDef, the required ones first, each sorted by proto name
+func getMessageFields(msgDef *datadictionary.MessageDef) []messageField {
+	var fields []messageField
+	if *flattenComponents {
+		fields = flattenParts(msgDef.Parts, "", true, fields)
+	} else {
+		for _, field := range getRequiredFields(msgDef) {
+			fields = append(fields, messageField{FieldDef: field, Required: true})
+		}
+		for _, field := range getOptionalFields(msgDef) {
+			fields = append(fields, messageField{FieldDef: field})
+		}
+		return fields
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].Required != fields[j].Required {
+			return fields[i].Required
+		}
+		return fields[i].ProtoName() < fields[j].ProtoName()
+	})
+	return fields
+}
+
+// flattenParts appends the fields of parts, recursing into components. A field is required if all its enclosing
+// components are required.
+func flattenParts(parts []datadictionary.MessagePart, prefix string, required bool, fields []messageField) []messageField {
+	for _, part := range parts {
+		var component *datadictionary.Component
+		switch p := part.(type) {
+		case datadictionary.Component:
+			component = &p
+		case *datadictionary.Component:
+			component = p
+		case *datadictionary.FieldDef:
+			fields = append(fields, messageField{FieldDef: p, Prefix: prefix, Required: required && p.Required()})
+			continue
+		default:
+			continue
+		}
+
+		var nestedPrefix string
+		switch *componentPrefix {
+		case prefixComponent:
+			nestedPrefix = component.Name()
+		case prefixPath:
+			nestedPrefix = prefix + component.Name()
+		}
+		fields = flattenParts(component.Parts(), nestedPrefix, required && component.Required(), fields)
+	}
+	return fields
+}
+
 // getAllGroups returns all group fields from a MessageDef, sorted by field name
 func getAllGroups(msgDef *datadictionary.MessageDef) []*datadictionary.FieldDef {
 	var allGroups []*datadictionary.FieldDef
@@ -420,8 +501,7 @@ func convertFixFieldToProto(fieldDef *datadictionary.FieldDef, pbMsgVar, fixMsgV
 
 // setProtoField generates code to set a proto field
 func setProtoField(fieldDef *datadictionary.FieldDef, pbMsgVar, valueVar string) string {
-	protoFieldName := sanitizeProtoFieldName(fieldDef.FieldType.Name())
-	goFieldName := protoFieldNameToGoFieldName(protoFieldName)
+	goFieldName := fieldGoName("", fieldDef.FieldType.Name())
 	return fmt.Sprintf("%s.%s = %s", pbMsgVar, goFieldName, valueVar)
 }
 
@@ -433,8 +513,7 @@ func convertProtoFieldToFix(fieldDef *datadictionary.FieldDef, fixMsgVar, pbMsgV
 	}
 
 	fieldName := fieldDef.FieldType.Name()
-	protoFieldName := sanitizeProtoFieldName(fieldName)
-	goFieldName := protoFieldNameToGoFieldName(protoFieldName)
+	goFieldName := fieldGoName("", fieldName)
 
 	// Check if field has enum values
 	if globalEnumRegistry != nil && globalEnumRegistry.HasEnum(fieldName) {
@@ -557,15 +636,18 @@ var templateFuncs = template.FuncMap{
 	"toProtoType":                 toProtoType,
 	"getProtoTypeForField":        getProtoTypeForField,
 	"sanitizeProtoFieldName":      sanitizeProtoFieldName,
+	"fieldProtoName":              fieldProtoName,
 	"protoFieldNameToGoFieldName": protoFieldNameToGoFieldName,
 	"toGoFieldName":               toGoFieldName,
 	"hasEnumType":                 hasEnumType,
 	"getEnumTypeName":             getEnumTypeName,
 	"getGoTypeForField":           getGoTypeForField,
 	"add":                         add,
+	"fieldNumber":                 fieldNumber,
 	"getFields":                   getFields,
 	"getRequiredFields":           getRequiredFields,
 	"getOptionalFields":           getOptionalFields,
+	"getMessageFields":            getMessageFields,
 	"getFieldType":                getFieldType,
 	"extractPackageName":          extractPackageName,
 	"getRequiredComponents":       getRequiredComponents,
@@ -584,4 +666,7 @@ var templateFuncs = template.FuncMap{
 	"setProtoField":               setProtoField,
 	"convertProtoFieldToFix":      convertProtoFieldToFix,
 	"getEnumProtoName":            getEnumProtoName,
+	"protoSyntax":                 protoSyntax,
+	"fieldLabel":                  fieldLabel,
+	"explicitPresence":            explicitPresence,
 }
@@ -4,13 +4,124 @@ import "text/template"
 
 // EnumProtoTemplate generates only enum definitions in proto file
 var EnumProtoTemplate = template.Must(template.New("fix.enum.proto").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
-syntax = "proto3";
+{{protoSyntax}}
 
 package {{extractPackageName .GoPackagePrefix}};
 
 option go_package = "{{.GoPackagePrefix}}";
 
-{{/* Generate enum definitions */}}
+` + enumProtoBody))
+
+// MessageProtoTemplate generates only message definitions in proto file
+var MessageProtoTemplate = template.Must(template.New("fix.message.proto").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+{{protoSyntax}}
+
+package {{extractPackageName .GoPackagePrefix}};
+
+option go_package = "{{.GoPackagePrefix}}";
+
+// Import enum definitions
+import "fix.enum.proto";{{if .DecimalMessages}}
+import "fix.decimal.proto";{{end}}{{if .WellKnownTime}}
+import "google/protobuf/timestamp.proto";{{end}}
+
+` + messageProtoBody))
+
+// VersionEnumProtoTemplate generates the enum definitions specific to one FIX version, see -per-version
+var VersionEnumProtoTemplate = template.Must(template.New("version.fix.enum.proto").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+{{protoSyntax}}
+
+package {{.Version}};
+
+option go_package = "{{.GoPackagePrefix}}";
+
+` + enumProtoBody))
+
+// VersionMessageProtoTemplate generates the message definitions of one FIX version, see -per-version
+var VersionMessageProtoTemplate = template.Must(template.New("version.fix.message.proto").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+{{protoSyntax}}
+
+package {{.Version}};
+
+option go_package = "{{.GoPackagePrefix}}";
+
+// Import enum definitions
+import "fix.enum.proto";{{if .DecimalMessages}}
+import "fix.decimal.proto";{{end}}{{if .WellKnownTime}}
+import "google/protobuf/timestamp.proto";{{end}}{{if .HasEnums}}
+import "{{.Version}}/fix.enum.proto";{{end}}
+
+` + messageProtoBody))
+
+// DecimalProtoTemplate generates the Decimal message encoding the FIX decimal types, see -decimal-mode
+var DecimalProtoTemplate = template.Must(template.New("fix.decimal.proto").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+{{protoSyntax}}
+
+package {{extractPackageName .GoPackagePrefix}};
+
+option go_package = "{{.GoPackagePrefix}}";
+
+// Decimal is a value of the FIX decimal types PRICE, QTY, AMT, PERCENTAGE and PRICEOFFSET, equal to
+// unscaled * 10^-scale
+message Decimal {
+  {{fieldLabel}}int64 unscaled = 1;
+  {{fieldLabel}}int32 scale = 2;
+}
+`))
+
+// SplitProtoTemplate generates the proto file of a single message, see -split-by-message
+var SplitProtoTemplate = template.Must(template.New("split.fix.message.proto").Funcs(templateFuncs).Parse(splitProtoHeader +
+	messageDefinitionsBody))
+
+// SplitGroupProtoTemplate generates the proto file of the repeating groups shared by the messages, see
+// -split-by-message
+var SplitGroupProtoTemplate = template.Must(template.New("split.fix.group.proto").Funcs(templateFuncs).Parse(splitProtoHeader +
+	groupDefinitionsBody))
+
+// splitProtoHeader is the header of the proto files generated with -split-by-message
+const splitProtoHeader = `// Code generated by generate-pb. DO NOT EDIT.
+{{protoSyntax}}
+
+package {{.Package}};
+
+option go_package = "{{.GoPackagePrefix}}";
+{{range .Imports}}
+import "{{.}}";{{end}}
+
+`
+
+// GatewayServiceProtoTemplate generates the gRPC service sending messages to a FIX session, annotated with
+// google.api.http options for gRPC-Gateway, see -gateway
+var GatewayServiceProtoTemplate = template.Must(template.New("fix.service.proto").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
+{{protoSyntax}}
+
+package {{extractPackageName .GoPackagePrefix}};
+
+option go_package = "{{.GoPackagePrefix}}";
+
+import "google/api/annotations.proto";
+{{- range .Imports}}
+import "{{.}}";
+{{- end}}
+
+// SendResponse acknowledges a message queued for sending to the FIX session
+message SendResponse {}
+
+// FIXGateway sends messages to a FIX session, identified by the request metadata
+service FIXGateway {
+{{- range .Methods}}
+  rpc {{.Name}}({{.Request}}) returns (SendResponse) {
+    option (google.api.http) = {
+      post: "{{.Path}}"
+      body: "*"
+    };
+  }
+{{- end}}
+}
+`))
+
+// enumProtoBody is the enum definitions shared by the enum proto templates
+const enumProtoBody = `{{/* Generate enum definitions */}}
 {{range getAllEnumDefinitions}}
 // {{.Name}} represents the {{.FieldType}} field type enum values
 enum {{.ProtoName}} {
@@ -18,37 +129,51 @@ enum {{.ProtoName}} {
 {{end}}}
 
 {{end}}
-`))
+`
 
-// MessageProtoTemplate generates only message definitions in proto file
-var MessageProtoTemplate = template.Must(template.New("fix.message.proto").Funcs(templateFuncs).Parse(`// Code generated by generate-pb. DO NOT EDIT.
-syntax = "proto3";
+// messageProtoBody is the message and group definitions shared by the message proto templates
+const messageProtoBody = protoPartsDefinition + `{{if .ComponentMessages}}` + componentDefinitionsBody + `{{else}}` +
+	messageDefinitionsBody + "\n" + groupDefinitionsBody + `{{end}}`
 
-package {{extractPackageName .GoPackagePrefix}};
+// protoPartsDefinition defines the protoParts template, the proto fields of a message, component or group entry
+// generated with -component-messages
+const protoPartsDefinition = `{{define "protoParts"}}{{$name := .Name}}{{$fieldNum := 1}}{{range $part := .ProtoParts}}  {{if $part.IsComponent}}{{fieldLabel}}{{$part.MessageName}}{{else if $part.IsGroup}}repeated {{$part.MessageName}}{{else}}{{fieldLabel}}{{getProtoTypeForField $part.FieldDef}}{{end}} {{$part.ProtoName}} = {{fieldNumber $name $part.ProtoName $fieldNum}}; // {{$part.Comment}}
+{{$fieldNum = add $fieldNum 1}}{{end}}{{end}}`
 
-option go_package = "{{.GoPackagePrefix}}";
+// componentDefinitionsBody is the definitions of the messages, components and repeating group entries generated
+// with -component-messages, each component and group entry once
+const componentDefinitionsBody = `{{range .Messages}}
+// {{.Name}} message definition (from {{.Package}} specification)
+message {{.Name}} {
+{{template "protoParts" .}}}
 
-// Import enum definitions
-import "fix.enum.proto";
+{{end}}{{range .ProtoComponents}}
+// {{.Name}} represents {{.Description}}
+message {{.Name}} {
+{{template "protoParts" .}}}
 
-{{range .Messages}}
+{{end}}
+`
+
+// messageDefinitionsBody is the definitions of the messages of the template data
+const messageDefinitionsBody = `{{range .Messages}}
 // {{.Name}} message definition (from {{.Package}} specification)
 message {{.Name}} {
-{{$fieldNum := 1}}{{range $field := getRequiredFields .MessageDef}}{{if $field.IsGroup}}  repeated {{generateGroupMessageName $field}} {{sanitizeProtoFieldName $field.FieldType.Name}} = {{$fieldNum}}; // Required group
-{{$fieldNum = add $fieldNum 1}}{{else}}  {{getProtoTypeForField $field}} {{sanitizeProtoFieldName $field.FieldType.Name}} = {{$fieldNum}}; // Required field
-{{$fieldNum = add $fieldNum 1}}{{end}}{{end}}{{range $field := getOptionalFields .MessageDef}}{{if $field.IsGroup}}  repeated {{generateGroupMessageName $field}} {{sanitizeProtoFieldName $field.FieldType.Name}} = {{$fieldNum}}; // Optional group
-{{$fieldNum = add $fieldNum 1}}{{else}}  {{getProtoTypeForField $field}} {{sanitizeProtoFieldName $field.FieldType.Name}} = {{$fieldNum}}; // Optional field
+{{$msgName := .Name}}{{$fieldNum := 1}}{{range $field := getMessageFields .MessageDef}}{{if $field.IsGroup}}  repeated {{generateGroupMessageName $field.FieldDef}} {{$field.ProtoName}} = {{fieldNumber $msgName $field.ProtoName $fieldNum}}; // {{if $field.Required}}Required{{else}}Optional{{end}} group
+{{$fieldNum = add $fieldNum 1}}{{else}}  {{fieldLabel}}{{getProtoTypeForField $field.FieldDef}} {{$field.ProtoName}} = {{fieldNumber $msgName $field.ProtoName $fieldNum}}; // {{if $field.Required}}Required{{else}}Optional{{end}} field
 {{$fieldNum = add $fieldNum 1}}{{end}}{{end}}}
 
 {{end}}
+`
 
-{{/* Generate unique group message definitions */}}
+// groupDefinitionsBody is the definitions of the repeating groups of the messages of the template data, each once
+const groupDefinitionsBody = `{{/* Generate unique group message definitions */}}
 {{$seenGroups := dict}}{{range .Messages}}{{range $group := getAllGroups .MessageDef}}{{$groupName := generateGroupMessageName $group}}{{if not (hasKey $seenGroups $groupName)}}{{set $seenGroups $groupName true}}
 // {{$groupName}} represents a single entry in the {{$group.FieldType.Name}} repeating group
 message {{$groupName}} {
-{{$fieldNum := 1}}{{range $field := $group.RequiredFields}}  {{getProtoTypeForField $field}} {{sanitizeProtoFieldName $field.FieldType.Name}} = {{$fieldNum}}; // Required group field
-{{$fieldNum = add $fieldNum 1}}{{end}}{{range $field := $group.Fields}}{{$isRequired := false}}{{range $req := $group.RequiredFields}}{{if eq $req.FieldType.Tag $field.FieldType.Tag}}{{$isRequired = true}}{{end}}{{end}}{{if not $isRequired}}  {{getProtoTypeForField $field}} {{sanitizeProtoFieldName $field.FieldType.Name}} = {{$fieldNum}}; // Optional group field
+{{$fieldNum := 1}}{{range $field := $group.RequiredFields}}  {{fieldLabel}}{{getProtoTypeForField $field}} {{fieldProtoName $field.FieldType.Name}} = {{fieldNumber $groupName (fieldProtoName $field.FieldType.Name) $fieldNum}}; // Required group field
+{{$fieldNum = add $fieldNum 1}}{{end}}{{range $field := $group.Fields}}{{$isRequired := false}}{{range $req := $group.RequiredFields}}{{if eq $req.FieldType.Tag $field.FieldType.Tag}}{{$isRequired = true}}{{end}}{{end}}{{if not $isRequired}}  {{fieldLabel}}{{getProtoTypeForField $field}} {{fieldProtoName $field.FieldType.Name}} = {{fieldNumber $groupName (fieldProtoName $field.FieldType.Name) $fieldNum}}; // Optional group field
 {{$fieldNum = add $fieldNum 1}}{{end}}{{end}}}
 
 {{end}}{{end}}{{end}}
-`))
+`
@@ -0,0 +1,45 @@
+package main
+
+// Values of -time-mode
+const (
+	timeString    = "string"
+	timeWellKnown = "wellknown"
+)
+
+const (
+	// timestampProtoMessage is the proto message of the FIX timestamp and date types with -time-mode wellknown
+	timestampProtoMessage = "google.protobuf.Timestamp"
+
+	// timestampProtoFile is the proto file of timestampProtoMessage, shipped with protoc
+	timestampProtoFile = "google/protobuf/timestamp.proto"
+
+	// timeConversionFile is the Go file of the conversion functions of timestampProtoMessage, relative to GoRoot
+	timeConversionFile = "fix.time.conversion.go"
+)
+
+// WellKnownTime returns true if the FIX timestamp and date types are encoded as google.protobuf.Timestamp, see
+// -time-mode
+func (c messagesComponent) WellKnownTime() bool {
+	return *timeMode == timeWellKnown
+}
+
+// wellKnownTime returns true if fields of the FIX type fixType are encoded as google.protobuf.Timestamp
+func wellKnownTime(fixType string) bool {
+	if *timeMode != timeWellKnown {
+		return false
+	}
+	switch fixType {
+	case "UTCTIMESTAMP", "UTCDATEONLY", "UTCDATE":
+		return true
+	}
+	return false
+}
+
+// timeFromProtoFunc returns the function converting the google.protobuf.Timestamp of a field encoded with
+// -time-mode wellknown to the value of its FIX setter
+func (f fieldInfo) timeFromProtoFunc() string {
+	if f.Type == "UTCTIMESTAMP" {
+		return f.sharedFunc("TimestampFromProto")
+	}
+	return f.sharedFunc("DateFromProto")
+}
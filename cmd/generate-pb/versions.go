@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// applVerIDs maps FIX version package names to their ApplVerID
+var applVerIDs = map[string]string{
+	"fix40":    "2",
+	"fix41":    "3",
+	"fix42":    "4",
+	"fix43":    "5",
+	"fix44":    "6",
+	"fix50":    "7",
+	"fix50sp1": "8",
+	"fix50sp2": "9",
+}
+
+// fixVersion holds one FIX version generated into its own proto package with -per-version
+type fixVersion struct {
+	Name        string // Package name, e.g. fix44
+	BeginString string // Set for versions carried by their own BeginString, FIX.4.x and FIXT.1.1
+	ApplVerID   string // Set for application versions
+	Spec        *datadictionary.DataDictionary
+
+	// Enums are the enums specific to this version, the others being shared by all versions
+	Enums []*EnumDefinition
+
+	registry      *EnumRegistry
+	shared        map[string]bool
+	sharedPackage string
+}
+
+// versionComponent is the template data of a FIX version
+type versionComponent struct {
+	messagesComponent
+	Version         string
+	HasEnums        bool
+	SharedPackage   string
+	SharedGoPackage string
+}
+
+// registryComponent is the template data of the conversion registry
+type registryComponent struct {
+	messagesComponent
+	Versions []*fixVersion
+}
+
+func newFixVersion(spec *datadictionary.DataDictionary) *fixVersion {
+	v := &fixVersion{
+		Name:     getPackageName(spec),
+		Spec:     spec,
+		registry: NewEnumRegistry(),
+		shared:   make(map[string]bool),
+	}
+	v.registry.RegisterFieldEnums([]*datadictionary.DataDictionary{spec})
+	globalNames.resolveEnumNames(v.registry, v.Name)
+
+	if spec.FIXType == "FIXT" || spec.Major < 5 {
+		v.BeginString = spec.FIXType + "." + strconv.Itoa(spec.Major) + "." + strconv.Itoa(spec.Minor)
+	}
+	v.ApplVerID = applVerIDs[v.Name]
+	return v
+}
+
+// buildVersions registers the enums of each specification and splits them into enums identical across all
+// versions defining them, generated once in the shared package, and enums specific to a version.
+func buildVersions(specs []*datadictionary.DataDictionary) []*fixVersion {
+	sharedPackage := extractPackageName(*pbGoPkg)
+
+	var versions []*fixVersion
+	for _, spec := range specs {
+		v := newFixVersion(spec)
+		v.sharedPackage = sharedPackage
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Name < versions[j].Name })
+
+	sharedRegistry := NewEnumRegistry()
+	for _, v := range versions {
+		for _, enum := range v.registry.GetAllEnums() {
+			if !isSharedEnum(enum, versions) {
+				v.Enums = append(v.Enums, enum)
+				continue
+			}
+			v.shared[enum.Name] = true
+			sharedRegistry.enums[enum.Name] = enum
+			sharedRegistry.fieldTypeEnums[enum.Name] = true
+		}
+	}
+	sharedEnums = sharedRegistry.GetAllEnums()
+
+	return versions
+}
+
+// sharedEnums are the enums generated once in the shared package with -per-version
+var sharedEnums []*EnumDefinition
+
+// isSharedEnum returns whether enum is defined identically by all versions defining it
+func isSharedEnum(enum *EnumDefinition, versions []*fixVersion) bool {
+	for _, v := range versions {
+		other, ok := v.registry.GetEnum(enum.Name)
+		if !ok {
+			continue
+		}
+		if len(other.Values) != len(enum.Values) {
+			return false
+		}
+		for i := range other.Values {
+			if other.Values[i].StringValue != enum.Values[i].StringValue ||
+				other.Values[i].Description != enum.Values[i].Description {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (v *fixVersion) goPackage(config *Config) string {
+	return config.PbGoPkg + "/" + v.Name
+}
+
+// protoTypeForField returns the proto type of a field, qualifying shared enums with the shared package
+func (v *fixVersion) protoTypeForField(fieldDef *datadictionary.FieldDef) string {
+	if fieldDef == nil {
+		return "string"
+	}
+
+	if enum, ok := v.registry.GetEnum(fieldDef.Name()); ok {
+		if v.shared[enum.Name] {
+			return v.sharedPackage + "." + enum.ProtoName
+		}
+		return enum.ProtoName
+	}
+
+	protoType := getProtoTypeForField(fieldDef)
+	if protoType == decimalProtoMessage {
+		return v.sharedPackage + "." + protoType
+	}
+	return protoType
+}
+
+// sharedFunc returns the name of a function generated into the shared package, such as a decimal or time
+// conversion, qualified with the shared package with -per-version
+func (f fieldInfo) sharedFunc(name string) string {
+	if f.version != nil {
+		return f.version.sharedPackage + "." + name
+	}
+	return name
+}
+
+// fromFIXEnumMap returns the FIX to protobuf conversion map of a field's enum, if the field has one in this version
+func (v *fixVersion) fromFIXEnumMap(fieldName string) (string, bool) {
+	enum, ok := v.registry.GetEnum(fieldName)
+	if !ok {
+		return "", false
+	}
+	if v.shared[enum.Name] {
+		return v.sharedPackage + ".FIXTo" + enum.ProtoName, true
+	}
+	return "FIXTo" + enum.ProtoName, true
+}
+
+// toFIXEnumMap returns the protobuf to FIX conversion map of a field's enum, if the field has one in this version
+func (v *fixVersion) toFIXEnumMap(fieldName string) (string, bool) {
+	enum, ok := v.registry.GetEnum(fieldName)
+	if !ok {
+		return "", false
+	}
+	if v.shared[enum.Name] {
+		return v.sharedPackage + "." + enum.ProtoName + "ToFIX", true
+	}
+	return enum.ProtoName + "ToFIX", true
+}
+
+// funcs overrides the template functions depending on the enums of this version
+func (v *fixVersion) funcs() template.FuncMap {
+	return template.FuncMap{
+		"getAllEnumDefinitions": func() []*EnumDefinition { return v.Enums },
+		"getProtoTypeForField":  v.protoTypeForField,
+		"fieldNumber":           v.fieldNumber,
+	}
+}
+
+// fieldNumber returns the number of field in message, qualifying message with the version in the field number map
+func (v *fixVersion) fieldNumber(message, field string, sequential int) int {
+	return fieldNumber(v.Name+"."+message, field, sequential)
+}
+
+func (v *fixVersion) component(config *Config) versionComponent {
+	var messages []messageInfo
+	var packages []string
+	for _, msg := range v.Spec.Messages {
+		messages = append(messages, messageInfo{
+			Name:       msg.Name,
+			Package:    v.Name,
+			MessageDef: msg,
+			version:    v,
+		})
+		packages = append(packages, fmt.Sprintf("%s/%s/%s", config.FixPkg, v.Name, strings.ToLower(msg.Name)))
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Name < messages[j].Name })
+	sort.Strings(packages)
+
+	return versionComponent{
+		messagesComponent: messagesComponent{
+			GoPackagePrefix: v.goPackage(config),
+			QuickfixRoot:    config.FixPkg,
+			Messages:        messages,
+			Packages:        packages,
+		},
+		Version:         v.Name,
+		HasEnums:        len(v.Enums) > 0,
+		SharedPackage:   v.sharedPackage,
+		SharedGoPackage: config.PbGoPkg,
+	}
+}
+
+func createVersionDirectories(config *Config, versions []*fixVersion) error {
+	for _, v := range versions {
+		if err := createDirIfNotExists(path.Join(config.PbRoot, v.Name), "proto"); err != nil {
+			return err
+		}
+		if err := createDirIfNotExists(path.Join(config.GoRoot, v.Name), "Go"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// genVersions generates the shared enums and conversion registry, then the proto package and conversion
+// functions of each version
+func genVersions(versions []*fixVersion, config *Config) {
+	defer func() {
+		if config.Verbose {
+			log.Printf("Calling waitGroup.Done() for genVersions")
+		}
+		waitGroup.Done()
+	}()
+
+	shared := messagesComponent{
+		GoPackagePrefix: config.PbGoPkg,
+		QuickfixRoot:    config.FixPkg,
+	}
+	sharedFuncs := template.FuncMap{
+		"getAllEnumDefinitions": func() []*EnumDefinition { return sharedEnums },
+	}
+
+	genVersionSync(EnumProtoTemplate, sharedFuncs, path.Join(config.PbRoot, "fix.enum.proto"), shared, config)
+	genVersionSync(EnumConversionGoTemplate, sharedFuncs, path.Join(config.GoRoot, "fix.enum.conversion.go"), shared, config)
+	genVersionSync(ConversionRegistryGoTemplate, sharedFuncs, path.Join(config.GoRoot, "fix.conversion.registry.go"),
+		registryComponent{messagesComponent: shared, Versions: versions}, config)
+	if config.DecimalMode == decimalMessage {
+		genVersionSync(DecimalProtoTemplate, sharedFuncs, path.Join(config.PbRoot, decimalProtoFile), shared, config)
+		genVersionSync(DecimalConversionGoTemplate, sharedFuncs, path.Join(config.GoRoot, decimalConversionFile), shared, config)
+	}
+	if config.TimeMode == timeWellKnown {
+		genVersionSync(TimeConversionGoTemplate, sharedFuncs, path.Join(config.GoRoot, timeConversionFile), shared, config)
+	}
+
+	for _, v := range versions {
+		c := v.component(config)
+		if config.Verbose {
+			log.Printf("Generating %s with %d messages and %d version specific enums", v.Name, len(c.Messages), len(v.Enums))
+		}
+
+		if c.HasEnums {
+			genVersionSync(VersionEnumProtoTemplate, v.funcs(), path.Join(config.PbRoot, v.Name, "fix.enum.proto"), c, config)
+			genVersionSync(EnumConversionGoTemplate, v.funcs(), path.Join(config.GoRoot, v.Name, "fix.enum.conversion.go"), c, config)
+		}
+		if config.SplitByMessage {
+			imports := []string{"fix.enum.proto"}
+			if config.DecimalMode == decimalMessage {
+				imports = append(imports, decimalProtoFile)
+			}
+			if config.TimeMode == timeWellKnown {
+				imports = append(imports, timestampProtoFile)
+			}
+			if c.HasEnums {
+				imports = append(imports, path.Join(v.Name, "fix.enum.proto"))
+			}
+			genSplitMessages(c.messagesComponent, v.Name, v.Name, imports, config,
+				func(t *template.Template, fileOut string, data interface{}) {
+					genVersionSync(t, v.funcs(), fileOut, data, config)
+				})
+		} else {
+			genVersionSync(VersionMessageProtoTemplate, v.funcs(), path.Join(config.PbRoot, v.Name, "fix.message.proto"), c, config)
+		}
+		genVersionSync(VersionMessageConversionGoTemplate, v.funcs(), path.Join(config.GoRoot, v.Name, "fix.message.conversion.go"), c, config)
+	}
+}
+
+// genVersionSync executes a clone of t with funcs overriding its template functions
+func genVersionSync(t *template.Template, funcs template.FuncMap, fileOut string, data interface{}, config *Config) {
+	clone, err := t.Clone()
+	if err != nil {
+		errors <- fmt.Errorf("template clone failed for %s: %w", fileOut, err)
+		return
+	}
+
+	var writer bytes.Buffer
+	if err := clone.Funcs(funcs).Execute(&writer, data); err != nil {
+		errors <- fmt.Errorf("template execution failed for %s: %w", fileOut, err)
+		return
+	}
+
+	if config.Verbose {
+		log.Printf("Generating file: %s", fileOut)
+	}
+
+	if config.DryRun {
+		if config.Verbose {
+			log.Printf("DRY RUN: Would write %d bytes to %s", writer.Len(), fileOut)
+		}
+		return
+	}
+
+	if err := writeGenerated(fileOut, writer.String()); err != nil {
+		errors <- fmt.Errorf("failed to write %s: %w", fileOut, err)
+		return
+	}
+
+	if config.Verbose {
+		log.Printf("Successfully wrote %s (%d bytes)", fileOut, writer.Len())
+	}
+}
@@ -0,0 +1,90 @@
+// Command generate-sbe generates codecs between the messages of an SBE (Simple Binary Encoding) schema and
+// quickfix.Message. The fields of the SBE messages are mapped to the FIX fields of the same id, or else of the same
+// name, in the FIX data dictionary, and each SBE message to the FIX message of its semanticType, or else of its name.
+//
+// Two files are written to the -out directory, codec.generated.go holding Decode, Encode and the mapping of each
+// message, and runtime.generated.go holding the reader and writer of SBE buffers they are built on.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/quickfixgo/quickfix/cmd/internal/codegen"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+var (
+	pkg               = flag.String("pkg", "sbe", "Package of the generated Go files")
+	out               = flag.String("out", ".", "Directory the generated Go files are written to")
+	verbose           = flag.Bool("verbose", false, "Enable verbose output")
+	postProcess       = flag.String("post-process", "", "Command each generated Go file is piped through before it is written, run with the path of the file as last argument")
+	postProcessPlugin = flag.String("post-process-plugin", "", "Go plugin exporting PostProcess func(filename string, content []byte) ([]byte, error), called with each generated Go file before it is written")
+)
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "usage: %v [flags] <path to SBE schema> <path to data dictionary>\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+// generate executes tmpl with schema and writes the formatted result to the file of the template name
+func generate(tmpl *template.Template, schema *Schema, postProcess codegen.PostProcessFunc) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, schema); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", tmpl.Name(), err)
+	}
+
+	filename := filepath.Join(*out, tmpl.Name())
+	if *verbose {
+		log.Printf("Writing %s", filename)
+	}
+	return postProcess.Write(filename, string(src))
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	dict, err := datadictionary.Parse(flag.Arg(1))
+	if err != nil {
+		log.Fatalf("Data dictionary parsing error: %v", err)
+	}
+
+	schema, err := ParseSchema(flag.Arg(0), dict, *pkg)
+	if err != nil {
+		log.Fatalf("SBE schema error: %v", err)
+	}
+	if *verbose {
+		log.Printf("Generating %d messages of schema %d version %d", len(schema.Messages), schema.ID, schema.Version)
+	}
+
+	postProcessFunc, err := codegen.LoadPostProcess(*postProcessPlugin, *postProcess)
+	if err != nil {
+		log.Fatalf("Post-process error: %v", err)
+	}
+
+	if err = os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatalf("Directory creation error: %v", err)
+	}
+
+	for _, tmpl := range []*template.Template{CodecTemplate, RuntimeTemplate} {
+		if err = generate(tmpl, schema, postProcessFunc); err != nil {
+			log.Fatalf("Generation error: %v", err)
+		}
+	}
+}
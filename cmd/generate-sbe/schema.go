@@ -0,0 +1,572 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// xmlSchema is the subset of an SBE messageSchema the codecs are generated from
+type xmlSchema struct {
+	Package    string       `xml:"package,attr"`
+	ID         int          `xml:"id,attr"`
+	Version    int          `xml:"version,attr"`
+	ByteOrder  string       `xml:"byteOrder,attr"`
+	HeaderType string       `xml:"headerType,attr"`
+	Types      []xmlTypes   `xml:"types"`
+	Messages   []xmlMessage `xml:"message"`
+}
+
+type xmlTypes struct {
+	Types      []xmlType      `xml:"type"`
+	Composites []xmlComposite `xml:"composite"`
+	Enums      []xmlEnum      `xml:"enum"`
+}
+
+type xmlType struct {
+	Name          string `xml:"name,attr"`
+	PrimitiveType string `xml:"primitiveType,attr"`
+	Length        int    `xml:"length,attr"`
+	Presence      string `xml:"presence,attr"`
+	NullValue     string `xml:"nullValue,attr"`
+	ValueRef      string `xml:"valueRef,attr"`
+	Value         string `xml:",chardata"`
+}
+
+type xmlComposite struct {
+	Name  string    `xml:"name,attr"`
+	Types []xmlType `xml:"type"`
+}
+
+type xmlEnum struct {
+	Name         string `xml:"name,attr"`
+	EncodingType string `xml:"encodingType,attr"`
+}
+
+type xmlMessage struct {
+	Name         string `xml:"name,attr"`
+	ID           int    `xml:"id,attr"`
+	SemanticType string `xml:"semanticType,attr"`
+	BlockLength  int    `xml:"blockLength,attr"`
+	xmlBlock
+}
+
+type xmlBlock struct {
+	Fields []xmlField `xml:"field"`
+	Groups []xmlGroup `xml:"group"`
+	Data   []xmlData  `xml:"data"`
+}
+
+type xmlField struct {
+	Name     string `xml:"name,attr"`
+	ID       int    `xml:"id,attr"`
+	Type     string `xml:"type,attr"`
+	Offset   *int   `xml:"offset,attr"`
+	Presence string `xml:"presence,attr"`
+}
+
+type xmlGroup struct {
+	Name          string `xml:"name,attr"`
+	ID            int    `xml:"id,attr"`
+	DimensionType string `xml:"dimensionType,attr"`
+	BlockLength   int    `xml:"blockLength,attr"`
+	xmlBlock
+}
+
+type xmlData struct {
+	Name string `xml:"name,attr"`
+	ID   int    `xml:"id,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// primitiveSizes are the sizes in bytes of the SBE primitive types
+var primitiveSizes = map[string]int{
+	"char": 1, "int8": 1, "uint8": 1, "int16": 2, "uint16": 2, "int32": 4, "uint32": 4, "int64": 8, "uint64": 8,
+	"float": 4, "double": 8,
+}
+
+// timeUnits are the SBE time units, as a Go duration and the precision of the FIX UTCTimestamp decoded
+var timeUnits = map[string][2]string{
+	"nanosecond":  {"time.Nanosecond", "quickfix.Nanos"},
+	"microsecond": {"time.Microsecond", "quickfix.Micros"},
+	"millisecond": {"time.Millisecond", "quickfix.Millis"},
+	"second":      {"time.Second", "quickfix.Seconds"},
+}
+
+// Schema is an SBE schema resolved against a FIX data dictionary
+type Schema struct {
+	Package   string
+	ID        int
+	Version   int
+	ByteOrder string
+	Header    Header
+	Messages  []*Message
+
+	// Blocks are the messages and the repeating groups, nested ones included
+	Blocks []*Block
+}
+
+// Header is the layout of the message header composite
+type Header struct {
+	Size                                       int
+	BlockLength, TemplateID, SchemaID, Version Member
+}
+
+// Member is a primitive member of a composite
+type Member struct {
+	Offset int
+	Size   int
+}
+
+// Message is an SBE message, mapped to the FIX message of MsgType
+type Message struct {
+	*Block
+	TemplateID int
+	MsgType    string
+}
+
+// Block is the root block of a message or of a repeating group entry, followed by its groups and var data
+type Block struct {
+	GoName      string
+	BlockLength int
+	Fields      []Field
+	Groups      []*Group
+	Data        []Data
+}
+
+// Group is a repeating group, mapped to the FIX repeating group of its NumInGroup tag
+type Group struct {
+	*Block
+	Tag       int
+	Dimension Dimension
+}
+
+// Dimension is the layout of the dimension composite of a repeating group
+type Dimension struct {
+	Size        int
+	BlockLength Member
+	NumInGroup  Member
+}
+
+// Data is a var data field, mapped to the FIX data field of Tag
+type Data struct {
+	Tag        int
+	LengthSize int
+}
+
+// Field is a field of a block, mapped to the FIX field of Tag
+type Field struct {
+	Name     string
+	Tag      int
+	Offset   int
+	Kind     string
+	Size     int
+	Optional bool
+	Null     string
+
+	// Set for decimals, the exponent being Exponent if constant, otherwise ExponentMember, offset from the mantissa
+	Exponent       string
+	ExponentMember Member
+
+	// Set for timestamps
+	Unit, Precision string
+
+	// Set for constants
+	Value string
+}
+
+// Field kinds
+const (
+	kindString    = "String"
+	kindInt       = "Int"
+	kindUint      = "Uint"
+	kindFloat     = "Float"
+	kindDecimal   = "Decimal"
+	kindTimestamp = "Timestamp"
+	kindConstant  = "Constant"
+)
+
+// resolver resolves the types of an SBE schema and the tags and message types of a FIX data dictionary
+type resolver struct {
+	types      map[string]xmlType
+	composites map[string]xmlComposite
+	enums      map[string]xmlEnum
+	dict       *datadictionary.DataDictionary
+	schema     *Schema
+}
+
+// ParseSchema parses the SBE schema in file, mapping its messages to the messages of dict
+func ParseSchema(file string, dict *datadictionary.DataDictionary, pkg string) (*Schema, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc xmlSchema
+	if err = xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %v: %w", file, err)
+	}
+
+	r := &resolver{
+		types:      make(map[string]xmlType),
+		composites: make(map[string]xmlComposite),
+		enums:      make(map[string]xmlEnum),
+		dict:       dict,
+		schema:     &Schema{Package: pkg, ID: doc.ID, Version: doc.Version},
+	}
+	for _, types := range doc.Types {
+		for _, t := range types.Types {
+			r.types[t.Name] = t
+		}
+		for _, c := range types.Composites {
+			r.composites[c.Name] = c
+		}
+		for _, e := range types.Enums {
+			r.enums[e.Name] = e
+		}
+	}
+
+	switch doc.ByteOrder {
+	case "", "littleEndian":
+		r.schema.ByteOrder = "LittleEndian"
+	case "bigEndian":
+		r.schema.ByteOrder = "BigEndian"
+	default:
+		return nil, fmt.Errorf("invalid byteOrder %q", doc.ByteOrder)
+	}
+
+	headerType := doc.HeaderType
+	if headerType == "" {
+		headerType = "messageHeader"
+	}
+	if err = r.resolveHeader(headerType); err != nil {
+		return nil, err
+	}
+
+	msgTypes := make(map[string]string)
+	for _, m := range doc.Messages {
+		msg, err := r.resolveMessage(m)
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %w", m.Name, err)
+		}
+		if other, ok := msgTypes[msg.MsgType]; ok {
+			return nil, fmt.Errorf("messages %v and %v both map to MsgType %v", other, m.Name, msg.MsgType)
+		}
+		msgTypes[msg.MsgType] = m.Name
+		r.schema.Messages = append(r.schema.Messages, msg)
+	}
+	return r.schema, nil
+}
+
+func (r *resolver) resolveHeader(name string) error {
+	members, size, err := r.members(name, "blockLength", "templateId", "schemaId", "version")
+	if err != nil {
+		return fmt.Errorf("header: %w", err)
+	}
+	r.schema.Header = Header{Size: size, BlockLength: members[0], TemplateID: members[1], SchemaID: members[2], Version: members[3]}
+	return nil
+}
+
+// members returns the named members of composite name, and the size of the composite
+func (r *resolver) members(name string, names ...string) ([]Member, int, error) {
+	c, ok := r.composites[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown composite %q", name)
+	}
+
+	found := make(map[string]Member)
+	offset := 0
+	for _, t := range c.Types {
+		size, err := typeSize(t)
+		if err != nil {
+			return nil, 0, fmt.Errorf("composite %v: %w", name, err)
+		}
+		found[t.Name] = Member{Offset: offset, Size: size}
+		offset += size
+	}
+
+	members := make([]Member, len(names))
+	for i, n := range names {
+		m, ok := found[n]
+		if !ok {
+			return nil, 0, fmt.Errorf("composite %v has no member %v", name, n)
+		}
+		members[i] = m
+	}
+	return members, offset, nil
+}
+
+func typeSize(t xmlType) (int, error) {
+	if t.Presence == "constant" {
+		return 0, nil
+	}
+	size, ok := primitiveSizes[t.PrimitiveType]
+	if !ok {
+		return 0, fmt.Errorf("type %v: unsupported primitiveType %q", t.Name, t.PrimitiveType)
+	}
+	if t.Length > 1 {
+		size *= t.Length
+	}
+	return size, nil
+}
+
+func (r *resolver) resolveMessage(m xmlMessage) (*Message, error) {
+	msgType := m.SemanticType
+	if msgType == "" {
+		for mt, def := range r.dict.Messages {
+			if def.Name == m.Name {
+				msgType = mt
+			}
+		}
+	}
+	if _, ok := r.dict.Messages[msgType]; !ok {
+		return nil, fmt.Errorf("no FIX message of MsgType %q, set semanticType to the MsgType", msgType)
+	}
+
+	block, err := r.resolveBlock(goName(m.Name), m.BlockLength, m.xmlBlock)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Block: block, TemplateID: m.ID, MsgType: msgType}, nil
+}
+
+func (r *resolver) resolveBlock(name string, blockLength int, b xmlBlock) (*Block, error) {
+	block := &Block{GoName: name}
+	r.schema.Blocks = append(r.schema.Blocks, block)
+
+	offset := 0
+	for _, f := range b.Fields {
+		if f.Offset != nil {
+			offset = *f.Offset
+		}
+		field, size, err := r.resolveField(f, offset)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", f.Name, err)
+		}
+		block.Fields = append(block.Fields, field)
+		offset += size
+	}
+	block.BlockLength = max(blockLength, offset)
+
+	for _, g := range b.Groups {
+		tag, err := r.tag(g.Name, g.ID)
+		if err != nil {
+			return nil, fmt.Errorf("group %v: %w", g.Name, err)
+		}
+		dimensionType := g.DimensionType
+		if dimensionType == "" {
+			dimensionType = "groupSizeEncoding"
+		}
+		members, size, err := r.members(dimensionType, "blockLength", "numInGroup")
+		if err != nil {
+			return nil, fmt.Errorf("group %v: %w", g.Name, err)
+		}
+		entry, err := r.resolveBlock(name+goName(g.Name), g.BlockLength, g.xmlBlock)
+		if err != nil {
+			return nil, fmt.Errorf("group %v: %w", g.Name, err)
+		}
+		block.Groups = append(block.Groups, &Group{
+			Block:     entry,
+			Tag:       tag,
+			Dimension: Dimension{Size: size, BlockLength: members[0], NumInGroup: members[1]},
+		})
+	}
+
+	for _, d := range b.Data {
+		tag, err := r.tag(d.Name, d.ID)
+		if err != nil {
+			return nil, fmt.Errorf("data %v: %w", d.Name, err)
+		}
+		members, _, err := r.members(d.Type, "length", "varData")
+		if err != nil {
+			return nil, fmt.Errorf("data %v: %w", d.Name, err)
+		}
+		block.Data = append(block.Data, Data{Tag: tag, LengthSize: members[0].Size})
+	}
+	return block, nil
+}
+
+// tag returns the FIX tag of an SBE field, its id or else the tag of the FIX field of the same name
+func (r *resolver) tag(name string, id int) (int, error) {
+	if id == 0 {
+		ft, ok := r.dict.FieldTypeByName[name]
+		if !ok {
+			return 0, fmt.Errorf("no id and no FIX field named %v", name)
+		}
+		return ft.Tag(), nil
+	}
+	if _, ok := r.dict.FieldTypeByTag[id]; !ok {
+		return 0, fmt.Errorf("id %v is not a FIX tag", id)
+	}
+	return id, nil
+}
+
+// resolveField resolves a field at offset in its block, returning the field and its size in the block
+func (r *resolver) resolveField(f xmlField, offset int) (field Field, size int, err error) {
+	if field.Tag, err = r.tag(f.Name, f.ID); err != nil {
+		return
+	}
+	field.Name = f.Name
+	field.Offset = offset
+	field.Optional = f.Presence == "optional"
+
+	if c, ok := r.composites[f.Type]; ok {
+		if _, size, err = r.members(c.Name); err != nil {
+			return
+		}
+		field, err = r.resolveComposite(field, c)
+		return
+	}
+	if e, ok := r.enums[f.Type]; ok {
+		t, ok := r.types[e.EncodingType]
+		if !ok {
+			t = xmlType{PrimitiveType: e.EncodingType}
+		}
+		t.Name = e.Name
+		field, err = resolvePrimitive(field, t)
+		return field, field.Size, err
+	}
+	t, ok := r.types[f.Type]
+	if !ok {
+		if _, ok = primitiveSizes[f.Type]; !ok {
+			return field, 0, fmt.Errorf("unknown type %q", f.Type)
+		}
+		t = xmlType{Name: f.Type, PrimitiveType: f.Type}
+	}
+	if t.Presence == "optional" {
+		field.Optional = true
+	}
+	if size, err = typeSize(t); err != nil {
+		return
+	}
+	field, err = resolvePrimitive(field, t)
+	return
+}
+
+func resolvePrimitive(field Field, t xmlType) (Field, error) {
+	size, ok := primitiveSizes[t.PrimitiveType]
+	if !ok {
+		return field, fmt.Errorf("type %v: unsupported primitiveType %q", t.Name, t.PrimitiveType)
+	}
+	field.Size = size
+
+	switch {
+	case t.Presence == "constant":
+		field.Kind = kindConstant
+		field.Value = strings.TrimSpace(t.Value)
+		return field, nil
+	case t.PrimitiveType == "char":
+		field.Kind = kindString
+		field.Size = max(t.Length, 1)
+		return field, nil
+	case t.PrimitiveType == "float" || t.PrimitiveType == "double":
+		field.Kind = kindFloat
+		return field, nil
+	case t.Length > 1:
+		return field, fmt.Errorf("type %v: arrays of %v are not supported", t.Name, t.PrimitiveType)
+	case strings.HasPrefix(t.PrimitiveType, "uint"):
+		field.Kind = kindUint
+		field.Null = fmt.Sprintf("math.MaxUint%d", size*8)
+	default:
+		field.Kind = kindInt
+		field.Null = fmt.Sprintf("math.MinInt%d", size*8)
+	}
+	if t.NullValue != "" {
+		if _, err := strconv.ParseInt(t.NullValue, 10, 64); err != nil {
+			if _, err = strconv.ParseUint(t.NullValue, 10, 64); err != nil {
+				return field, fmt.Errorf("type %v: invalid nullValue %q", t.Name, t.NullValue)
+			}
+		}
+		field.Null = t.NullValue
+	}
+	return field, nil
+}
+
+// resolveComposite resolves a field of a decimal composite, made of a mantissa and an exponent, or of a timestamp
+// composite, made of a time and a constant unit
+func (r *resolver) resolveComposite(field Field, c xmlComposite) (Field, error) {
+	members := make(map[string]xmlType)
+	for _, t := range c.Types {
+		members[t.Name] = t
+	}
+
+	switch {
+	case hasMembers(members, "mantissa", "exponent"):
+		offsets, _, err := r.members(c.Name, "mantissa", "exponent")
+		if err != nil {
+			return field, err
+		}
+		mantissa, err := resolvePrimitive(Field{}, members["mantissa"])
+		if err != nil || mantissa.Kind != kindInt {
+			return field, fmt.Errorf("composite %v: the mantissa must be a signed integer", c.Name)
+		}
+		field.Kind = kindDecimal
+		field.Offset += offsets[0].Offset
+		field.Size = offsets[0].Size
+		field.Null = mantissa.Null
+		if exponent := members["exponent"]; exponent.Presence == "constant" {
+			field.Exponent = strings.TrimSpace(exponent.Value)
+			if _, err := strconv.Atoi(field.Exponent); err != nil {
+				return field, fmt.Errorf("composite %v: invalid constant exponent %q", c.Name, field.Exponent)
+			}
+		} else {
+			field.ExponentMember = Member{Offset: offsets[1].Offset - offsets[0].Offset, Size: offsets[1].Size}
+		}
+		return field, nil
+
+	case hasMembers(members, "time", "unit"):
+		offsets, _, err := r.members(c.Name, "time")
+		if err != nil {
+			return field, err
+		}
+		unit := members["unit"]
+		if unit.Presence != "constant" {
+			return field, fmt.Errorf("composite %v: the unit must be constant", c.Name)
+		}
+		name := strings.TrimSpace(unit.Value)
+		if _, ref, ok := strings.Cut(unit.ValueRef, "."); ok {
+			name = ref
+		}
+		units, ok := timeUnits[name]
+		if !ok {
+			return field, fmt.Errorf("composite %v: unsupported unit %q", c.Name, name)
+		}
+		field.Kind = kindTimestamp
+		field.Offset += offsets[0].Offset
+		field.Size = offsets[0].Size
+		field.Unit, field.Precision = units[0], units[1]
+		return field, nil
+	}
+	return field, fmt.Errorf("composite %v is neither a decimal nor a timestamp", c.Name)
+}
+
+func hasMembers(members map[string]xmlType, names ...string) bool {
+	for _, name := range names {
+		if _, ok := members[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// goName returns name as an exported Go identifier
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range name {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+			continue
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
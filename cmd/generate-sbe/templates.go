@@ -0,0 +1,547 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+)
+
+var templateFuncs = template.FuncMap{
+	"decodeField": decodeField,
+	"encodeField": encodeField,
+}
+
+// decodeField returns the statement decoding f from the block at pos into the FieldMap fm
+func decodeField(f Field) string {
+	switch f.Kind {
+	case kindConstant:
+		return fmt.Sprintf("fm.SetString(%d, %s)", f.Tag, strconv.Quote(f.Value))
+	case kindString:
+		return fmt.Sprintf("decodeString(r, pos+%d, %d, %d, fm)", f.Offset, f.Size, f.Tag)
+	case kindInt:
+		return fmt.Sprintf("decodeInt(r, pos+%d, %d, %s, %v, %d, fm)", f.Offset, f.Size, f.Null, f.Optional, f.Tag)
+	case kindUint:
+		return fmt.Sprintf("decodeUint(r, pos+%d, %d, %s, %v, %d, fm)", f.Offset, f.Size, f.Null, f.Optional, f.Tag)
+	case kindFloat:
+		return fmt.Sprintf("decodeFloat(r, pos+%d, %d, %v, %d, fm)", f.Offset, f.Size, f.Optional, f.Tag)
+	case kindDecimal:
+		return fmt.Sprintf("decodeDecimal(r, pos+%d, %d, %s, %s, %v, %d, fm)", f.Offset, f.Size, exponent(f), f.Null,
+			f.Optional, f.Tag)
+	case kindTimestamp:
+		return fmt.Sprintf("decodeTimestamp(r, pos+%d, %d, %s, %s, %v, %d, fm)", f.Offset, f.Size, f.Unit, f.Precision,
+			f.Optional, f.Tag)
+	}
+	panic("unknown field kind " + f.Kind)
+}
+
+// encodeField returns the statement encoding f from the FieldMap fm into the block at pos, returning on error
+func encodeField(f Field) string {
+	var call string
+	switch f.Kind {
+	case kindConstant:
+		return ""
+	case kindString:
+		call = fmt.Sprintf("encodeString(w, pos+%d, %d, %d, fm)", f.Offset, f.Size, f.Tag)
+	case kindInt:
+		call = fmt.Sprintf("encodeInt(w, pos+%d, %d, %s, %v, %d, fm)", f.Offset, f.Size, f.Null, f.Optional, f.Tag)
+	case kindUint:
+		call = fmt.Sprintf("encodeUint(w, pos+%d, %d, %s, %v, %d, fm)", f.Offset, f.Size, f.Null, f.Optional, f.Tag)
+	case kindFloat:
+		call = fmt.Sprintf("encodeFloat(w, pos+%d, %d, %v, %d, fm)", f.Offset, f.Size, f.Optional, f.Tag)
+	case kindDecimal:
+		call = fmt.Sprintf("encodeDecimal(w, pos+%d, %d, %s, %s, %v, %d, fm)", f.Offset, f.Size, exponent(f), f.Null,
+			f.Optional, f.Tag)
+	case kindTimestamp:
+		call = fmt.Sprintf("encodeTimestamp(w, pos+%d, %d, %s, %v, %d, fm)", f.Offset, f.Size, f.Unit, f.Optional,
+			f.Tag)
+	default:
+		panic("unknown field kind " + f.Kind)
+	}
+	return fmt.Sprintf("if err := %s; err != nil {\n\t\treturn err\n\t}", call)
+}
+
+// exponent returns the exponent literal of a decimal field
+func exponent(f Field) string {
+	if f.Exponent != "" {
+		return fmt.Sprintf("exponent{value: %s, constant: true}", f.Exponent)
+	}
+	return fmt.Sprintf("exponent{offset: %d, size: %d}", f.ExponentMember.Offset, f.ExponentMember.Size)
+}
+
+// CodecTemplate generates the codecs of the messages of a schema
+var CodecTemplate = template.Must(template.New("codec.generated.go").Funcs(templateFuncs).Parse(`// Code generated by generate-sbe. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// The id and version of the SBE schema the codecs are generated from.
+const (
+	SchemaID      = {{.ID}}
+	SchemaVersion = {{.Version}}
+)
+
+// The template ids of the messages.
+const (
+{{- range .Messages}}
+	{{.GoName}}TemplateID = {{.TemplateID}}
+{{- end}}
+)
+
+var byteOrder binary.ByteOrder = binary.{{.ByteOrder}}
+
+// Decode decodes an SBE message into a FIX message, setting its MsgType and body.
+func Decode(buf []byte) (*quickfix.Message, error) {
+	r := &reader{buf: buf}
+	blockLength := int(r.uint({{.Header.BlockLength.Offset}}, {{.Header.BlockLength.Size}}))
+	templateID := r.uint({{.Header.TemplateID.Offset}}, {{.Header.TemplateID.Size}})
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	msg := quickfix.NewMessage()
+	switch templateID {
+{{- range .Messages}}
+	case {{.GoName}}TemplateID:
+		msg.Header.SetString(tagMsgType, "{{.MsgType}}")
+		decode{{.GoName}}(r, {{$.Header.Size}}, blockLength, &msg.Body.FieldMap)
+{{- end}}
+	default:
+		return nil, fmt.Errorf("unknown template id %v", templateID)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return msg, nil
+}
+
+// Encode encodes the body of a FIX message into the SBE message of its MsgType.
+func Encode(msg *quickfix.Message) ([]byte, error) {
+	msgType, rejectErr := msg.MsgType()
+	if rejectErr != nil {
+		return nil, rejectErr
+	}
+
+	w := &writer{}
+	pos := w.reserve({{.Header.Size}})
+	var err error
+	switch msgType {
+{{- range .Messages}}
+	case "{{.MsgType}}":
+		w.putUint(pos+{{$.Header.BlockLength.Offset}}, {{$.Header.BlockLength.Size}}, {{.BlockLength}})
+		w.putUint(pos+{{$.Header.TemplateID.Offset}}, {{$.Header.TemplateID.Size}}, {{.GoName}}TemplateID)
+		w.putUint(pos+{{$.Header.SchemaID.Offset}}, {{$.Header.SchemaID.Size}}, SchemaID)
+		w.putUint(pos+{{$.Header.Version.Offset}}, {{$.Header.Version.Size}}, SchemaVersion)
+		err = encode{{.GoName}}(w, &msg.Body.FieldMap)
+{{- end}}
+	default:
+		return nil, fmt.Errorf("no SBE message for MsgType %v", msgType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+{{range .Blocks}}
+func decode{{.GoName}}(r *reader, pos, blockLength int, fm *quickfix.FieldMap) int {
+{{- range .Fields}}
+	{{decodeField .}}
+{{- end}}
+	pos += blockLength
+{{- range .Groups}}
+	pos = decodeGroup(r, pos, dimension{{.GoName}}, quickfix.NewRepeatingGroup({{.Tag}}, {{.GoName}}Template()), fm, decode{{.GoName}})
+{{- end}}
+{{- range .Data}}
+	pos = decodeData(r, pos, {{.LengthSize}}, {{.Tag}}, fm)
+{{- end}}
+	return pos
+}
+
+func encode{{.GoName}}(w *writer, fm *quickfix.FieldMap) error {
+{{- if .Fields}}
+	pos := w.reserve({{.BlockLength}})
+{{- range .Fields}}
+{{- with encodeField .}}
+	{{.}}
+{{- end}}
+{{- end}}
+{{- else}}
+	w.reserve({{.BlockLength}})
+{{- end}}
+{{- range .Groups}}
+	if err := encodeGroup(w, dimension{{.GoName}}, {{.BlockLength}}, quickfix.NewRepeatingGroup({{.Tag}}, {{.GoName}}Template()), fm, encode{{.GoName}}); err != nil {
+		return err
+	}
+{{- end}}
+{{- range .Data}}
+	if err := encodeData(w, {{.LengthSize}}, {{.Tag}}, fm); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+{{- range .Groups}}
+
+var dimension{{.GoName}} = dimension{size: {{.Dimension.Size}}, blockLength: member{ {{- .Dimension.BlockLength.Offset}}, {{.Dimension.BlockLength.Size -}} }, numInGroup: member{ {{- .Dimension.NumInGroup.Offset}}, {{.Dimension.NumInGroup.Size -}} }}
+
+// {{.GoName}}Template returns the template of the FIX repeating group of the {{.GoName}} entries.
+func {{.GoName}}Template() quickfix.GroupTemplate {
+	return quickfix.GroupTemplate{
+{{- range .Fields}}
+		quickfix.GroupElement({{.Tag}}),
+{{- end}}
+{{- range .Groups}}
+		quickfix.NewRepeatingGroup({{.Tag}}, {{.GoName}}Template()),
+{{- end}}
+{{- range .Data}}
+		quickfix.GroupElement({{.Tag}}),
+{{- end}}
+	}
+}
+{{- end}}
+{{end}}
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = math.MaxInt8
+	_ = time.Second
+)
+`))
+
+// RuntimeTemplate generates the reader and writer of SBE buffers the codecs are built on
+var RuntimeTemplate = template.Must(template.New("runtime.generated.go").Parse(`// Code generated by generate-sbe. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+const tagMsgType quickfix.Tag = 35
+
+// ErrShortBuffer is returned when decoding a buffer shorter than the message it holds.
+var ErrShortBuffer = errors.New("sbe: buffer too short")
+
+// member is a primitive member of a composite, at offset in the composite.
+type member struct {
+	offset, size int
+}
+
+// dimension is the layout of the dimension composite of a repeating group.
+type dimension struct {
+	size                    int
+	blockLength, numInGroup member
+}
+
+// exponent is the exponent of a decimal, constant or encoded after the mantissa.
+type exponent struct {
+	value        int
+	constant     bool
+	offset, size int
+}
+
+type reader struct {
+	buf []byte
+	err error
+}
+
+func (r *reader) bytes(off, n int) []byte {
+	if r.err == nil && (off < 0 || n < 0 || off+n > len(r.buf)) {
+		r.err = ErrShortBuffer
+	}
+	if r.err != nil {
+		return make([]byte, max(n, 0))
+	}
+	return r.buf[off : off+n]
+}
+
+func (r *reader) uint(off, size int) uint64 {
+	b := r.bytes(off, size)
+	switch size {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(byteOrder.Uint16(b))
+	case 4:
+		return uint64(byteOrder.Uint32(b))
+	default:
+		return byteOrder.Uint64(b)
+	}
+}
+
+func (r *reader) int(off, size int) int64 {
+	shift := 64 - 8*size
+	return int64(r.uint(off, size)<<shift) >> shift
+}
+
+type writer struct {
+	buf []byte
+}
+
+// reserve appends n zero bytes, returning their offset.
+func (w *writer) reserve(n int) int {
+	off := len(w.buf)
+	w.buf = append(w.buf, make([]byte, n)...)
+	return off
+}
+
+func (w *writer) putUint(off, size int, v uint64) {
+	b := w.buf[off : off+size]
+	switch size {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		byteOrder.PutUint16(b, uint16(v))
+	case 4:
+		byteOrder.PutUint32(b, uint32(v))
+	default:
+		byteOrder.PutUint64(b, v)
+	}
+}
+
+// fieldValue returns the value of tag in fm, nil if absent.
+func fieldValue(fm *quickfix.FieldMap, tag quickfix.Tag) []byte {
+	if !fm.Has(tag) {
+		return nil
+	}
+	v, _ := fm.GetBytes(tag)
+	return v
+}
+
+func missing(tag quickfix.Tag) error {
+	return fmt.Errorf("tag %v: required field missing", tag)
+}
+
+func fitsInt(v int64, size int) bool {
+	shift := 64 - 8*size
+	return v<<shift>>shift == v
+}
+
+func decodeString(r *reader, off, n int, tag quickfix.Tag, fm *quickfix.FieldMap) {
+	b := r.bytes(off, n)
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	if len(b) > 0 {
+		fm.SetBytes(tag, b)
+	}
+}
+
+func encodeString(w *writer, off, n int, tag quickfix.Tag, fm *quickfix.FieldMap) error {
+	v := fieldValue(fm, tag)
+	if len(v) > n {
+		return fmt.Errorf("tag %v: %q longer than %v", tag, v, n)
+	}
+	copy(w.buf[off:off+n], v)
+	return nil
+}
+
+func decodeInt(r *reader, off, size int, null int64, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) {
+	if v := r.int(off, size); !optional || v != null {
+		fm.SetBytes(tag, strconv.AppendInt(nil, v, 10))
+	}
+}
+
+func encodeInt(w *writer, off, size int, null int64, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) error {
+	v := fieldValue(fm, tag)
+	if v == nil {
+		if !optional {
+			return missing(tag)
+		}
+		w.putUint(off, size, uint64(null))
+		return nil
+	}
+	i, err := strconv.ParseInt(string(v), 10, size*8)
+	if err != nil {
+		return fmt.Errorf("tag %v: %w", tag, err)
+	}
+	w.putUint(off, size, uint64(i))
+	return nil
+}
+
+func decodeUint(r *reader, off, size int, null uint64, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) {
+	if v := r.uint(off, size); !optional || v != null {
+		fm.SetBytes(tag, strconv.AppendUint(nil, v, 10))
+	}
+}
+
+func encodeUint(w *writer, off, size int, null uint64, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) error {
+	v := fieldValue(fm, tag)
+	if v == nil {
+		if !optional {
+			return missing(tag)
+		}
+		w.putUint(off, size, null)
+		return nil
+	}
+	u, err := strconv.ParseUint(string(v), 10, size*8)
+	if err != nil {
+		return fmt.Errorf("tag %v: %w", tag, err)
+	}
+	w.putUint(off, size, u)
+	return nil
+}
+
+func decodeFloat(r *reader, off, size int, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) {
+	v := math.Float64frombits(r.uint(off, size))
+	if size == 4 {
+		v = float64(math.Float32frombits(uint32(r.uint(off, size))))
+	}
+	if !optional || !math.IsNaN(v) {
+		fm.SetBytes(tag, strconv.AppendFloat(nil, v, 'f', -1, size*8))
+	}
+}
+
+func encodeFloat(w *writer, off, size int, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) error {
+	v := fieldValue(fm, tag)
+	f := math.NaN()
+	if v == nil {
+		if !optional {
+			return missing(tag)
+		}
+	} else {
+		var err error
+		if f, err = strconv.ParseFloat(string(v), size*8); err != nil {
+			return fmt.Errorf("tag %v: %w", tag, err)
+		}
+	}
+	if size == 4 {
+		w.putUint(off, size, uint64(math.Float32bits(float32(f))))
+	} else {
+		w.putUint(off, size, math.Float64bits(f))
+	}
+	return nil
+}
+
+func decodeDecimal(r *reader, off, size int, exp exponent, null int64, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) {
+	mantissa := r.int(off, size)
+	if optional && mantissa == null {
+		return
+	}
+	e := exp.value
+	if !exp.constant {
+		e = int(r.int(off+exp.offset, exp.size))
+	}
+	fm.SetString(tag, decimal.New(mantissa, int32(e)).String())
+}
+
+func encodeDecimal(w *writer, off, size int, exp exponent, null int64, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) error {
+	v := fieldValue(fm, tag)
+	if v == nil {
+		if !optional {
+			return missing(tag)
+		}
+		w.putUint(off, size, uint64(null))
+		return nil
+	}
+	d, err := decimal.NewFromString(string(v))
+	if err != nil {
+		return fmt.Errorf("tag %v: %w", tag, err)
+	}
+
+	e := exp.value
+	if !exp.constant {
+		e = min(int(d.Exponent()), 0)
+		if !fitsInt(int64(e), exp.size) {
+			return fmt.Errorf("tag %v: %v has too many decimals", tag, d)
+		}
+		w.putUint(off+exp.offset, exp.size, uint64(e))
+	}
+	shifted := d.Shift(int32(-e))
+	if !shifted.IsInteger() || !shifted.Coefficient().IsInt64() || !fitsInt(shifted.IntPart(), size) {
+		return fmt.Errorf("tag %v: %v does not fit a mantissa of %v bytes and exponent %v", tag, d, size, e)
+	}
+	w.putUint(off, size, uint64(shifted.IntPart()))
+	return nil
+}
+
+func decodeTimestamp(r *reader, off, size int, unit time.Duration, precision quickfix.TimestampPrecision, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) {
+	v := r.uint(off, size)
+	if optional && v == math.MaxUint64 {
+		return
+	}
+	t := time.Unix(0, 0).Add(time.Duration(v) * unit).UTC()
+	fm.SetField(tag, quickfix.FIXUTCTimestamp{Time: t, Precision: precision})
+}
+
+func encodeTimestamp(w *writer, off, size int, unit time.Duration, optional bool, tag quickfix.Tag, fm *quickfix.FieldMap) error {
+	if !fm.Has(tag) {
+		if !optional {
+			return missing(tag)
+		}
+		w.putUint(off, size, math.MaxUint64)
+		return nil
+	}
+	var t quickfix.FIXUTCTimestamp
+	if err := fm.GetField(tag, &t); err != nil {
+		return err
+	}
+	w.putUint(off, size, uint64(t.Time.Sub(time.Unix(0, 0))/unit))
+	return nil
+}
+
+func decodeGroup(r *reader, pos int, dim dimension, group *quickfix.RepeatingGroup, fm *quickfix.FieldMap,
+	decodeEntry func(*reader, int, int, *quickfix.FieldMap) int) int {
+	blockLength := int(r.uint(pos+dim.blockLength.offset, dim.blockLength.size))
+	numInGroup := int(r.uint(pos+dim.numInGroup.offset, dim.numInGroup.size))
+	pos += dim.size
+	for i := 0; i < numInGroup && r.err == nil; i++ {
+		pos = decodeEntry(r, pos, blockLength, &group.Add().FieldMap)
+	}
+	if numInGroup > 0 {
+		fm.SetGroup(group)
+	}
+	return pos
+}
+
+func encodeGroup(w *writer, dim dimension, blockLength int, group *quickfix.RepeatingGroup, fm *quickfix.FieldMap,
+	encodeEntry func(*writer, *quickfix.FieldMap) error) error {
+	if fm.Has(group.Tag()) {
+		if err := fm.GetGroup(group); err != nil {
+			return err
+		}
+	}
+	pos := w.reserve(dim.size)
+	w.putUint(pos+dim.blockLength.offset, dim.blockLength.size, uint64(blockLength))
+	w.putUint(pos+dim.numInGroup.offset, dim.numInGroup.size, uint64(group.Len()))
+	for i := 0; i < group.Len(); i++ {
+		if err := encodeEntry(w, &group.Get(i).FieldMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeData(r *reader, pos, lengthSize int, tag quickfix.Tag, fm *quickfix.FieldMap) int {
+	n := int(r.uint(pos, lengthSize))
+	pos += lengthSize
+	if b := r.bytes(pos, n); n > 0 && r.err == nil {
+		fm.SetBytes(tag, b)
+	}
+	return pos + n
+}
+
+func encodeData(w *writer, lengthSize int, tag quickfix.Tag, fm *quickfix.FieldMap) error {
+	v := fieldValue(fm, tag)
+	if lengthSize < 8 && uint64(len(v)) >= 1<<(8*lengthSize) {
+		return fmt.Errorf("tag %v: %v bytes do not fit a length of %v bytes", tag, len(v), lengthSize)
+	}
+	w.putUint(w.reserve(lengthSize), lengthSize, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+	return nil
+}
+`))
@@ -0,0 +1,109 @@
+// Package codegen writes the files emitted by the code generators, post-processed by the command or Go plugin the
+// user configured, so that generate-pb and generate-sbe share the -post-process and -post-process-plugin flags.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"plugin"
+	"strings"
+)
+
+// PluginSymbol is the function a post-process plugin exports
+const PluginSymbol = "PostProcess"
+
+// PostProcessFunc is the type of the PostProcess function exported by a post-process plugin. It receives the path
+// and content of each generated file and returns the content to write instead.
+type PostProcessFunc func(filename string, content []byte) ([]byte, error)
+
+// LoadPostProcess returns the post-processing of the Go plugin pluginPath and of the command, the command piped
+// after the plugin if both are set. Returns nil if neither is set.
+func LoadPostProcess(pluginPath, command string) (PostProcessFunc, error) {
+	var steps []PostProcessFunc
+	if pluginPath != "" {
+		p, err := plugin.Open(pluginPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load post-process plugin: %w", err)
+		}
+		symbol, err := p.Lookup(PluginSymbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load post-process plugin: %w", err)
+		}
+		switch f := symbol.(type) {
+		case func(string, []byte) ([]byte, error):
+			steps = append(steps, f)
+		case *PostProcessFunc:
+			steps = append(steps, *f)
+		default:
+			return nil, fmt.Errorf("post-process plugin %s is %T, not func(string, []byte) ([]byte, error)",
+				PluginSymbol, symbol)
+		}
+	}
+	if command != "" {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("empty -post-process command")
+		}
+		steps = append(steps, postProcessCommand(args))
+	}
+
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	return func(filename string, content []byte) ([]byte, error) {
+		var err error
+		for _, step := range steps {
+			if content, err = step(filename, content); err != nil {
+				return nil, err
+			}
+		}
+		return content, nil
+	}, nil
+}
+
+// postProcessCommand returns the post-processing of the command args, run for each generated file with the path of
+// the file as last argument. The content is piped through the command, from its stdin to its stdout.
+func postProcessCommand(args []string) PostProcessFunc {
+	return func(filename string, content []byte) ([]byte, error) {
+		cmd := exec.Command(args[0], append(args[1:], filename)...)
+		cmd.Stdin = bytes.NewReader(content)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("post-process of %s failed: %w\nOutput: %s", filename, err, stderr.String())
+		}
+		return stdout.Bytes(), nil
+	}
+}
+
+// Write writes a generated file, post-processed with p unless p is nil
+func (p PostProcessFunc) Write(filename, content string) error {
+	if p == nil {
+		return WriteFile(filename, content)
+	}
+
+	processed, err := p(filename, []byte(content))
+	if err != nil {
+		return err
+	}
+	return WriteFile(filename, string(processed))
+}
+
+// WriteFile writes content to a file
+func WriteFile(filename, content string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to close file %s: %v\n", filename, closeErr)
+		}
+	}()
+
+	_, err = file.WriteString(content)
+	return err
+}
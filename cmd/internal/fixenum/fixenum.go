@@ -0,0 +1,101 @@
+// Package fixenum merges the enum values of the fields of several FIX specifications and names the constants
+// generate-fix emits for them in the enum package, so that the generators referring to the enum package agree on
+// its symbols.
+package fixenum
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// FieldTypes are the field types of several specifications by name, with their enum values merged.
+type FieldTypes map[string]*datadictionary.FieldType
+
+// Merge merges the field types of specs, in command-line order. A field defined by several specs takes the
+// definition of the last one, which gains the enum values of the previous ones unless it defines the same value or
+// description, as the enum package has a single constant per description. Older values are considered in value
+// order, so that the value kept among colliding ones does not depend on map iteration.
+//
+// The field types of specs are merged in place: the generators read the enum values of a field from the last spec
+// defining it.
+func Merge(specs []*datadictionary.DataDictionary) FieldTypes {
+	fieldTypes := make(FieldTypes)
+	for _, spec := range specs {
+		tags := make([]int, 0, len(spec.FieldTypeByTag))
+		for tag := range spec.FieldTypeByTag {
+			tags = append(tags, tag)
+		}
+		sort.Ints(tags)
+
+		for _, tag := range tags {
+			field := spec.FieldTypeByTag[tag]
+			if oldField, ok := fieldTypes[field.Name()]; ok {
+				mergeEnums(field, oldField)
+			}
+			fieldTypes[field.Name()] = field
+		}
+	}
+	return fieldTypes
+}
+
+// mergeEnums adds the enum values of oldField to field, keeping the newer value of a description.
+func mergeEnums(field, oldField *datadictionary.FieldType) {
+	if len(oldField.Enums) == 0 {
+		return
+	}
+	if field.Enums == nil {
+		field.Enums = make(map[string]datadictionary.Enum)
+	}
+
+	descriptions := make(map[string]bool, len(field.Enums))
+	for _, enum := range field.Enums {
+		descriptions[enum.Description] = true
+	}
+
+	for _, value := range sortedValues(oldField) {
+		enum := oldField.Enums[value]
+		if _, ok := field.Enums[value]; ok || descriptions[enum.Description] {
+			continue
+		}
+		field.Enums[value] = enum
+		descriptions[enum.Description] = true
+	}
+}
+
+func sortedValues(field *datadictionary.FieldType) []string {
+	values := make([]string, 0, len(field.Enums))
+	for value := range field.Enums {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// Sorted returns the field types sorted by name.
+func (f FieldTypes) Sorted() []*datadictionary.FieldType {
+	sorted := make([]*datadictionary.FieldType, 0, len(f))
+	for _, fieldType := range f {
+		sorted = append(sorted, fieldType)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+	return sorted
+}
+
+// ConstName returns the name of the enum package constant of an enum value of field.
+func ConstName(field string, enum datadictionary.Enum) string {
+	return field + "_" + enum.Description
+}
+
+// Symbol returns the Go expression of the enum package for value of field: the constant of the value if the merged
+// field types define it, else a conversion of the value, such as for a value of an older spec whose description
+// was taken by a newer value.
+func (f FieldTypes) Symbol(field, value string) string {
+	if fieldType, ok := f[field]; ok {
+		if enum, ok := fieldType.Enums[value]; ok {
+			return "enum." + ConstName(field, enum)
+		}
+	}
+	return fmt.Sprintf("enum.%s(%q)", field, value)
+}
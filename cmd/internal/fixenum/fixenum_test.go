@@ -0,0 +1,105 @@
+package fixenum
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func enumField(name string, tag int, enums map[string]string) *datadictionary.FieldType {
+	field := datadictionary.NewFieldType(name, tag, "CHAR")
+	field.Enums = make(map[string]datadictionary.Enum)
+	for value, description := range enums {
+		field.Enums[value] = datadictionary.Enum{Value: value, Description: description}
+	}
+	return field
+}
+
+func spec(fields ...*datadictionary.FieldType) *datadictionary.DataDictionary {
+	dict := &datadictionary.DataDictionary{FieldTypeByTag: make(map[int]*datadictionary.FieldType)}
+	for _, field := range fields {
+		dict.FieldTypeByTag[field.Tag()] = field
+	}
+	return dict
+}
+
+func TestMergeCollisions(t *testing.T) {
+	older := spec(enumField("Side", 54, map[string]string{"1": "BUY", "A": "CROSS_SHORT_EXXMPT", "X": "LEND", "Y": "LEND"}))
+	newer := spec(enumField("Side", 54, map[string]string{"1": "BUY", "A": "CROSS_SHORT_EXEMPT", "F": "LEND"}))
+
+	fieldTypes := Merge([]*datadictionary.DataDictionary{older, newer})
+	side := fieldTypes["Side"]
+	require.Same(t, newer.FieldTypeByTag[54], side)
+	assert.Len(t, side.Enums, 3)
+
+	assert.Equal(t, "enum.Side_BUY", fieldTypes.Symbol("Side", "1"))
+	assert.Equal(t, "enum.Side_CROSS_SHORT_EXEMPT", fieldTypes.Symbol("Side", "A"))
+	assert.Equal(t, "enum.Side_LEND", fieldTypes.Symbol("Side", "F"))
+	assert.Equal(t, `enum.Side("X")`, fieldTypes.Symbol("Side", "X"))
+	assert.Equal(t, `enum.OrdType("1")`, fieldTypes.Symbol("OrdType", "1"))
+}
+
+func TestMergeKeepsFirstOlderValueOfDescription(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		older := spec(enumField("Side", 54, map[string]string{"X": "LEND", "Y": "LEND", "Z": "LEND"}))
+		newer := spec(enumField("Side", 54, map[string]string{"1": "BUY"}))
+
+		fieldTypes := Merge([]*datadictionary.DataDictionary{older, newer})
+		require.Equal(t, "enum.Side_LEND", fieldTypes.Symbol("Side", "X"))
+		require.Equal(t, `enum.Side("Y")`, fieldTypes.Symbol("Side", "Y"))
+	}
+}
+
+func TestSymbolsGolden(t *testing.T) {
+	files := []string{"FIX40.xml", "FIX41.xml", "FIX42.xml", "FIX43.xml", "FIX44.xml", "FIXT11.xml", "FIX50.xml",
+		"FIX50SP1.xml", "FIX50SP2.xml"}
+
+	var specs []*datadictionary.DataDictionary
+	values := make(map[string]map[string][]string)
+	for _, file := range files {
+		dict, err := datadictionary.Parse(filepath.Join("..", "..", "..", "spec", file))
+		require.NoError(t, err)
+		specs = append(specs, dict)
+
+		// The values of each spec, as Merge adds older ones to the field types.
+		values[file] = make(map[string][]string)
+		for _, name := range []string{"Side", "ExecType", "MsgType"} {
+			if field, ok := dict.FieldTypeByName[name]; ok {
+				values[file][name] = sortedValues(field)
+			}
+		}
+	}
+
+	fieldTypes := Merge(specs)
+
+	var b strings.Builder
+	for _, file := range files {
+		for _, name := range []string{"Side", "ExecType", "MsgType"} {
+			for _, value := range values[file][name] {
+				fmt.Fprintf(&b, "%v %v %v %v\n", file, name, value, fieldTypes.Symbol(name, value))
+			}
+		}
+	}
+
+	golden := filepath.Join("testdata", "symbols.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, []byte(b.String()), 0o644))
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), b.String())
+
+	sorted := fieldTypes.Sorted()
+	assert.True(t, sort.SliceIsSorted(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() }))
+}
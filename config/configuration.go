@@ -22,6 +22,10 @@ A settings file is set up with two types of headings, a
 
 [SESSION] tells QuickFIX/Go that a new Session is being defined.
 
+[PROFILE:name] defines a named set of settings that sessions inherit with Profile=name.
+Settings are resolved in order: [DEFAULT], then the profile, then the session itself.
+A profile may inherit from another profile, allowing shared values per group of sessions.
+
 If you do not provide a setting that QuickFIX/Go needs, it will throw an error
 telling you what setting is missing or improperly formatted.
 
@@ -71,6 +75,36 @@ telling you what setting is missing or improperly formatted.
 	SocketConnectPort2=2932
 	SocketConnectHost2=12.12.12.12
 	DataDictionary=somewhere/FIX42.xml
+
+# Sample Configuration Settings File With Profiles:
+
+	[DEFAULT]
+	ConnectionType=initiator
+	SenderCompID=TW
+
+	[PROFILE:retail]
+	HeartBtInt=30
+	StartTime=12:00:00
+	EndTime=23:00:00
+
+	# inherit everything from retail, overriding HeartBtInt
+	[PROFILE:retail-fast]
+	Profile=retail
+	HeartBtInt=10
+
+	[SESSION]
+	Profile=retail
+	BeginString=FIX.4.2
+	TargetCompID=ARCA
+	SocketConnectPort=9823
+	SocketConnectHost=123.123.123.123
+
+	[SESSION]
+	Profile=retail-fast
+	BeginString=FIX.4.4
+	TargetCompID=ISLD
+	SocketConnectPort=8323
+	SocketConnectHost=23.23.23.23
 */
 package config
 
@@ -162,6 +196,30 @@ const (
 	//  - A case-sensitive alpha-numeric string.
 	SessionQualifier string = "SessionQualifier"
 
+	// Profile names the [PROFILE:name] section whose settings this session or profile inherits.
+	// Set in [DEFAULT] it applies to all sessions that do not name a profile of their own.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - The name of a profile declared in the settings.
+	Profile string = "Profile"
+
+	// SessionLabels attaches arbitrary labels to the session, e.g. the environment, venue or tenant.
+	// Labels are passed to the session's Log and MessageStore when they implement quickfix.Labeler,
+	// so that multi-environment deployments can slice log entries, events, metrics and store metadata.
+	// They are also the session's default routing metadata, looked up by quickfix.SendToRoute.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - Comma delimited list of key=value pairs (e.g. "Env=UAT,Venue=XNAS,Tenant=acme").
+	SessionLabels string = "SessionLabels"
+
 	// DefaultApplVerID specifies the default application version ID for the session.
 	// This can either be the ApplVerID enum (see the ApplVerID field) or the BeginString for the default version.
 	//
@@ -246,6 +304,20 @@ const (
 	//  - Comma delimited list of days of the week in English, or 3 letter abbreviation (e.g. "Monday,Tuesday,Wednesday" or "Mon,Tue,Wed" would both be valid values).
 	Weekdays string = "Weekdays"
 
+	// SessionWindows is for daily sessions active in several windows each day, such as a morning and an afternoon
+	// trading session. The windows of a day are a single session: sequence numbers are reset once a day, not between
+	// windows. Can be used in combination with Weekdays and TimeZone.
+	// Incompatible with StartTime, EndTime, StartDay and EndDay.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - Comma delimited list of windows in the format HH:MM:SS-HH:MM:SS, time is represented in time zone configured by TimeZone.
+	//    Windows must not overlap nor cross midnight (e.g. "09:00:00-11:30:00,13:00:00-15:00:00").
+	SessionWindows string = "SessionWindows"
+
 	// TimeZone sets the time zone for this session; if specified, StartTime, EndTime, and ResetSeqTime will be converted from this zone to UTC.
 	// Times in messages will still be set to UTC as this is required by FIX specifications.
 	//
@@ -339,6 +411,8 @@ const (
 	// This setting should only be used with FIX transport versions older than FIXT.1.1.
 	// See TransportDataDictionary and AppDataDictionary for FIXT.1.1 messages.
 	// Value must be a path to a valid XML data dictionary file.
+	// This and the other dictionary settings also accept a FIX Orchestra repository file, whose base scenario is
+	// used, including the rules of its conditionally required and forbidden fields.
 	//
 	// QuickFIX/Go repo contains the following standard dictionaries in the spec/ directory:
 	//  - FIX44.xml
@@ -353,6 +427,7 @@ const (
 	//
 	// Valid Values:
 	//  - A filepath to a XML file with read access.
+	//  - A dictionary set in memory with SessionSettings.SetDataDictionary, instead of a value.
 	DataDictionary string = "DataDictionary"
 
 	// TransportDataDictionary is the path to an XML definition file for validating admin (transport) messages.
@@ -369,6 +444,7 @@ const (
 	//
 	// Valid Values:
 	//  - A filepath to a XML file with read access.
+	//  - A dictionary set in memory with SessionSettings.SetDataDictionary, instead of a value.
 	TransportDataDictionary string = "TransportDataDictionary"
 
 	// AppDataDictionary is the path to an XML definition file for validating application messages.
@@ -387,6 +463,11 @@ const (
 	//  # Use BeginString suffix for app version
 	//  AppDataDictionary.FIX.4.4=FIX44.xml
 	//
+	// The suffix is either the BeginString of the version or its ApplVerID, such as AppDataDictionary.6. Inbound
+	// application messages are parsed and validated with the dictionary of their ApplVerID(1128), or else of the
+	// DefaultApplVerID of the counterparty's Logon, falling back to the dictionary without suffix for an ApplVerID
+	// without one. The suffixed settings require AppDataDictionary, the dictionary of DefaultApplVerID.
+	//
 	// QuickFIX/Go repo contains the following standard dictionaries in the spec/ directory
 	//  - FIX50SP2.xml
 	//  - FIX50SP1.xml
@@ -403,8 +484,50 @@ const (
 	//
 	// Valid Values:
 	//  - A filepath to a XML file with read access.
+	//  - A dictionary set in memory with SessionSettings.SetDataDictionary, instead of a value.
 	AppDataDictionary string = "AppDataDictionary"
 
+	// OutboundDataDictionary is the path to an XML definition file used to validate outgoing application messages,
+	// typically stricter than the dictionary used for inbound messages so that fields a counterparty prohibits
+	// are never sent. Messages failing validation are not sent and the send returns a quickfix.ErrValidation.
+	// Validation follows the session's validation settings, e.g. ValidateFieldsOutOfOrder.
+	// For FIXT.1.1 (or newer) sessions it takes the place of AppDataDictionary and requires TransportDataDictionary.
+	//
+	// Required: No
+	//
+	// Default: Outgoing messages are not validated
+	//
+	// Valid Values:
+	//  - A filepath to a XML file with read access.
+	//  - A dictionary set in memory with SessionSettings.SetDataDictionary, instead of a value.
+	OutboundDataDictionary string = "OutboundDataDictionary"
+
+	// ValidateOutgoingAdminMessages if set to Y, validates the admin messages sent by the session, such as a Logon
+	// carrying custom tags set in ToAdmin, against OutboundDataDictionary if set, or else against DataDictionary or
+	// TransportDataDictionary. Messages failing validation are not sent, are logged as events and are reported to an
+	// Application implementing quickfix.OutboundAdminValidationHandler.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ValidateOutgoingAdminMessages string = "ValidateOutgoingAdminMessages"
+
+	// AllowInboundInjection if set to Y, allows quickfix.Session.InjectInbound to process synthetic messages as if
+	// received from the counterparty, for drills in test environments. Not meant to be set in production.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	AllowInboundInjection string = "AllowInboundInjection"
+
 	// RejectInvalidMessage is set by detault to Y, meaning that on reception of a message
 	// that fails data dictionary validation, a reject will be issued to the counter-party in responnse.
 	//
@@ -447,74 +570,538 @@ const (
 	//
 	// Required: No
 	//
-	// Default: Y
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ValidateFieldsOutOfOrder string = "ValidateFieldsOutOfOrder"
+
+	// ValidateFieldsHaveValues if set to N, fields without values (i.e. |11=| for an empty ClOrdID)
+	// will not be rejected, even if RejectInvalidMessage is set to N.
+	// Useful for connecting to systems that improperly send empty tags.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ValidateFieldsHaveValues string = "ValidateFieldsHaveValues"
+
+	// CheckLatency if set to Y, messages must be received from the counter-party within a defined number of seconds.
+	// It is useful to turn this off if a system uses localtime for it's timestamps instead of GMT.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	CheckLatency string = "CheckLatency"
+
+	// MaxLatency if CheckLatency is set to Y, this defines the number of seconds latency allowed for a message to be processed.
+	// Value must be positive integer.
+	//
+	// Required: No
+	//
+	// Default: 120
+	//
+	// Valid Values:
+	//  - Any positive integer
+	MaxLatency string = "MaxLatency"
+
+	// PossDupOrigSendingTimeCheck sets how OrigSendingTime is validated on messages received with PossDupFlag=Y,
+	// both for replayed admin and application messages.
+	// Per the FIX specification, OrigSendingTime is required on such messages and must not be later than SendingTime.
+	//  - REJECT rejects messages missing OrigSendingTime, and rejects and logs out on messages with OrigSendingTime later than SendingTime.
+	//  - WARN logs an event for such messages and processes them.
+	//  - ACCEPT does not validate OrigSendingTime.
+	//
+	// When not set, only the PossDup messages with a MsgSeqNum lower than expected are validated, as with REJECT,
+	// while the ones in sequence are processed without validating OrigSendingTime.
+	//
+	// Required: No
+	//
+	// Default: validate the PossDup messages with a MsgSeqNum too low only
+	//
+	// Valid Values:
+	//  - REJECT
+	//  - WARN
+	//  - ACCEPT
+	PossDupOrigSendingTimeCheck string = "PossDupOrigSendingTimeCheck"
+
+	// AckTimeout enables tracking of outbound order messages awaiting an application level response from the counterparty:
+	// an ExecutionReport for NewOrderSingle, an ExecutionReport or OrderCancelReject for OrderCancelRequest and
+	// OrderCancelReplaceRequest. Responses are correlated on ClOrdID, session and business level rejects also count as a response.
+	// Messages not acknowledged within the timeout are passed to the Application's OnUnacknowledged callback,
+	// if it implements quickfix.UnacknowledgedHandler.
+	// Value can either be a duration string or a number of seconds.
+	//
+	// Required: No
+	//
+	// Default: Disabled
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "500ms"
+	AckTimeout string = "AckTimeout"
+
+	// SeqNumCheckpointInterval enables periodic sequence number checkpoints: a Heartbeat carrying LastMsgSeqNumProcessed(369)
+	// is sent at this interval while logged on, and the LastMsgSeqNumProcessed of every message received is compared
+	// with the last message sent. A drift beyond SeqNumDriftThreshold, or a counterparty claiming to have processed messages
+	// never sent, is logged and passed to the Application's OnSeqNumDrift callback if it implements quickfix.SeqNumDriftHandler.
+	// Value can either be a duration string or a number of seconds.
+	//
+	// Required: No
+	//
+	// Default: Disabled
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "5m"
+	SeqNumCheckpointInterval string = "SeqNumCheckpointInterval"
+
+	// SeqNumDriftThreshold sets how many sent messages the counterparty's LastMsgSeqNumProcessed may lag behind before
+	// a drift is reported, allowing for messages in flight. Requires SeqNumCheckpointInterval.
+	//
+	// Required: No
+	//
+	// Default: 20
+	//
+	// Valid Values:
+	//  - Any non-negative integer
+	SeqNumDriftThreshold string = "SeqNumDriftThreshold"
+
+	// TestRequestInterval sends a TestRequest at this interval while logged on, unless one is outstanding, so that the
+	// round-trip time to the counterparty is measured even when it is not silent, see HeartbeatRTTThreshold and
+	// Session.LastHeartbeatRTT. Value can either be a duration string or a number of seconds.
+	//
+	// Required: No
+	//
+	// Default: Disabled, TestRequests are only sent when the counterparty is silent
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "30s"
+	TestRequestInterval string = "TestRequestInterval"
+
+	// HeartbeatRTTThreshold sets the round-trip time between a TestRequest and the Heartbeat answering it beyond which
+	// the counterparty link is considered degraded: a longer round-trip time is logged and passed to the Application's
+	// OnHeartbeatRTTExceeded callback if it implements quickfix.HeartbeatRTTHandler. Value can either be a duration
+	// string or a number of seconds.
+	//
+	// Required: No
+	//
+	// Default: Disabled
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "500ms"
+	HeartbeatRTTThreshold string = "HeartbeatRTTThreshold"
+
+	// SeqNumPublishInterval sets how often the sequence numbers of the session are published to the SeqNumPublisher
+	// set with quickfix.WithSeqNumPublisher, bounding how stale the position seen by failover tooling can be.
+	// Value can either be a duration string or a number of seconds. Has no effect without a SeqNumPublisher.
+	//
+	// Required: No
+	//
+	// Default: Disabled
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "500ms"
+	SeqNumPublishInterval string = "SeqNumPublishInterval"
+
+	// SeqNumPublishMessageCount publishes the sequence numbers of the session to the SeqNumPublisher set with
+	// quickfix.WithSeqNumPublisher once this many messages were sent and received since the last publication.
+	// Can be combined with SeqNumPublishInterval. Has no effect without a SeqNumPublisher.
+	//
+	// Required: No
+	//
+	// Default: Disabled
+	//
+	// Valid Values:
+	//  - A positive integer
+	SeqNumPublishMessageCount string = "SeqNumPublishMessageCount"
+
+	// SnapshotRate limits the number of messages per second sent by the SnapshotProviders registered with
+	// quickfix.RegisterSnapshotProvider, which replay application state after each logon.
+	//
+	// Required: No
+	//
+	// Default: 100
+	//
+	// Valid Values:
+	//  - Any positive integer
+	SnapshotRate string = "SnapshotRate"
+
+	// StatsHistoryMinutes is the number of minutes of per minute counters kept in memory for each session, such as
+	// the messages sent and received, rejects and disconnects, see quickfix.GetStatsHistory and
+	// quickfix.StatsHistoryHandler.
+	//
+	// Required: No
+	//
+	// Default: 60
+	//
+	// Valid Values:
+	//  - Any non-negative integer, 0 keeps no history
+	StatsHistoryMinutes string = "StatsHistoryMinutes"
+
+	// MaxMessagesPerSecond limits the rate of the outbound application messages, e.g. to stay within the order
+	// throttle of an exchange. Administrative messages are never throttled, but wait behind the throttled
+	// messages sent before them to keep the order of sequence numbers.
+	//
+	// Required: No
+	//
+	// Default: No limit
+	//
+	// Valid Values:
+	//  - Any positive integer
+	MaxMessagesPerSecond string = "MaxMessagesPerSecond"
+
+	// BurstSize is the number of application messages sent at once, without waiting, before MaxMessagesPerSecond
+	// applies. Only used with MaxMessagesPerSecond.
+	//
+	// Required: No
+	//
+	// Default: MaxMessagesPerSecond
+	//
+	// Valid Values:
+	//  - Any positive integer
+	BurstSize string = "BurstSize"
+
+	// ThrottlePolicy determines what happens to the outbound application messages exceeding MaxMessagesPerSecond.
+	// A dropped message already has its sequence number, it is replaced by a SequenceReset-GapFill.
+	//
+	// Required: No
+	//
+	// Default: QUEUE
+	//
+	// Valid Values:
+	//  - QUEUE (messages wait in the send queue until the rate allows them)
+	//  - DROP (messages are dropped)
+	//  - CALLBACK (the Application decides with OnOutboundThrottled if it implements quickfix.OutboundThrottledHandler,
+	//    messages wait otherwise)
+	ThrottlePolicy string = "ThrottlePolicy"
+
+	// ApprovalMsgTypes holds the outbound application messages of these MsgTypes, sent with quickfix.Send or
+	// quickfix.SendToTarget, until they are approved with quickfix.ApproveMessage or discarded with quickfix.RejectMessage.
+	// Held messages are listed by quickfix.GetHeldMessages and kept in memory only. Every decision is logged and passed
+	// to the Application's OnApprovalAudit callback if it implements quickfix.ApprovalAuditor.
+	//
+	// Required: No
+	//
+	// Default: None
+	//
+	// Valid Values:
+	//  - A comma delimited list of MsgTypes, such as D,F,G
+	//  - * for all application messages
+	ApprovalMsgTypes string = "ApprovalMsgTypes"
+
+	// ApprovalTimeout discards the messages held by ApprovalMsgTypes that are not approved in time. Requires ApprovalMsgTypes.
+	// Value can either be a duration string or a number of seconds.
+	//
+	// Required: No
+	//
+	// Default: Held messages never expire
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "2m"
+	ApprovalTimeout string = "ApprovalTimeout"
+
+	// DropCopy makes the session a drop copy session, as offered by exchanges: it receives application messages,
+	// validated and sequenced as usual, but the application never sends any. Sending an application message fails
+	// with quickfix.ErrDropCopy, while the BusinessMessageRejects of the session itself are still sent, and
+	// ResendRequests are answered with a SequenceReset-GapFill over the whole range.
+	// Cannot be combined with ApprovalMsgTypes.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	DropCopy string = "DropCopy"
+
+	// HandshakeTimeout is how long a session waits, after logon, for the quickfix.HandshakePolicy set with
+	// quickfix.SetHandshakePolicy to complete before logging out. Only used with a HandshakePolicy.
+	// Value can either be a duration string or a number of seconds.
+	//
+	// Required: No
+	//
+	// Default: 10
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "30s"
+	HandshakeTimeout string = "HandshakeTimeout"
+
+	// BeginStringMismatchPolicy sets how a logged on session handles a message whose BeginString does not match the session.
+	// The received BeginString is included in the logged event.
+	//  - LOGOUT sends a Logout and disconnects.
+	//  - DISCONNECT disconnects immediately, without sending a Logout.
+	//  - REJECT rejects the message and continues the session.
+	//  - CALLBACK lets the Application decide, it must implement quickfix.BeginStringMismatchHandler.
+	//
+	// Required: No
+	//
+	// Default: LOGOUT
+	//
+	// Valid Values:
+	//  - LOGOUT
+	//  - DISCONNECT
+	//  - REJECT
+	//  - CALLBACK
+	BeginStringMismatchPolicy string = "BeginStringMismatchPolicy"
+
+	// OutboundHeaderFields stamps these header fields on every outbound message, admin and application, unless the
+	// message already has the field. Fields can also be stamped at runtime with quickfix.StampHeaderField. Fields managed
+	// by the session, such as MsgSeqNum or SenderCompID, cannot be stamped.
+	//
+	// Required: No
+	//
+	// Default: None
+	//
+	// Valid Values:
+	//  - A comma delimited list of tag=value pairs, such as 115=BROKER,128=VENUE
+	OutboundHeaderFields string = "OutboundHeaderFields"
+
+	// OutboundBodyFields stamps these body fields on every outbound message, admin and application, unless the message
+	// already has the field. Fields can also be stamped at runtime with quickfix.StampBodyField.
+	//
+	// Required: No
+	//
+	// Default: None
+	//
+	// Valid Values:
+	//  - A comma delimited list of tag=value pairs, such as 20001=DESK1
+	OutboundBodyFields string = "OutboundBodyFields"
+
+	// CompressedDataFields compresses these data fields of outbound application messages with zlib, and decompresses
+	// them in inbound application messages, so that large payloads such as allocation instructions in XML shrink on the
+	// wire. Each data field is given with the length field preceding it, which carries the compressed length on the wire
+	// and the decompressed length in the message passed to FromApp. Both counterparties must agree on the fields.
+	// Only body fields outside of repeating groups are compressed. An inbound field that cannot be decompressed is
+	// rejected with SessionRejectReason "Incorrect data format for value".
+	//
+	// Required: No
+	//
+	// Default: None
+	//
+	// Valid Values:
+	//  - A comma delimited list of length:data tag pairs, such as 354:355,95:96
+	CompressedDataFields string = "CompressedDataFields"
+
+	// DataCompressionLevel sets the zlib compression level of CompressedDataFields.
+	//
+	// Required: No
+	//
+	// Default: -1 (zlib default compression)
+	//
+	// Valid Values:
+	//  - An integer from -2 (Huffman only) to 9 (best compression)
+	DataCompressionLevel string = "DataCompressionLevel"
+
+	// EncryptedFields encrypts the values of these fields of outbound application messages, and decrypts them in
+	// inbound application messages, for counterparties requiring fields such as account identifiers to be protected
+	// on top of TLS. Values are encrypted with the Crypter set with WithFieldCrypter and the key FieldEncryptionKeyID
+	// shared with the counterparty, and sent base64 encoded. Only body fields outside of repeating groups are
+	// encrypted. An inbound field that cannot be decrypted is rejected with SessionRejectReason "Incorrect data
+	// format for value".
+	//
+	// Required: No
+	//
+	// Default: None
+	//
+	// Valid Values:
+	//  - A comma delimited list of body tags, such as 1,79
+	EncryptedFields string = "EncryptedFields"
+
+	// FieldEncryptionKeyID is the id of the key, known to the Crypter, encrypting EncryptedFields.
+	//
+	// Required: If EncryptedFields is set
+	//
+	// Default: None
+	//
+	// Valid Values:
+	//  - A key id of the Crypter
+	FieldEncryptionKeyID string = "FieldEncryptionKeyID"
+
+	// RawMessageDecoder decodes the inbound messages of the session, received in FAST or another encoding, into
+	// tag=value messages before they are parsed, with the quickfix.RawMessageDecoder registered under this name with
+	// WithRawMessageDecoder. An acceptor reads the Logon opening a connection as tag=value and decodes the messages
+	// after it. Outbound messages are sent as tag=value.
+	//
+	// Required: No
+	//
+	// Default: None
+	//
+	// Valid Values:
+	//  - The name of a RawMessageDecoder registered with WithRawMessageDecoder
+	RawMessageDecoder string = "RawMessageDecoder"
+
+	// DeadLetterQueue keeps the inbound application messages rejected by FromApp, with the reject reason, in the
+	// dead-letter area of the MessageStore, so that they can be listed, retried or exported once the handler is fixed.
+	// Dead letters are kept when the store is reset. The MessageStore must implement quickfix.DeadLetterStore, as the
+	// memory and file stores do, encrypted or not; the SQL, MongoDB and Redis stores do not.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	DeadLetterQueue string = "DeadLetterQueue"
+
+	// AuditChain records every inbound message, as received, in an audit hash chain kept by the MessageStore: each
+	// record carries the SHA-256 of the previous record's hash followed by the message, so that altering, removing or
+	// reordering a record is detected by quickfix.VerifyAuditChain. The chain is kept when the store is reset and
+	// continues across restarts. The MessageStore must implement quickfix.AuditStore, as the memory and file stores do,
+	// encrypted or not; the SQL, MongoDB and Redis stores do not.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	AuditChain string = "AuditChain"
+
+	// CrashDumpPath sets the directory diagnostic bundles are written to when the session hits a fatal error or panics.
+	// Each bundle is a directory holding the session state and sequence numbers, the last inbound and outbound raw messages,
+	// and a goroutine dump. The bundle path is logged as a session event.
+	//
+	// Required: No
+	//
+	// Default: Disabled
+	//
+	// Valid Values:
+	//  - A valid directory path
+	CrashDumpPath string = "CrashDumpPath"
+
+	// CrashDumpMessageCount sets the number of most recent inbound and outbound messages kept for crash dumps.
+	// Only used with CrashDumpPath.
+	//
+	// Required: No
+	//
+	// Default: 100
+	//
+	// Valid Values:
+	//  - Any positive integer
+	CrashDumpMessageCount string = "CrashDumpMessageCount"
+
+	// InChanCapacity sets the maximum number of messages that can be buffered in the channel for incoming FIX messages.
+	//
+	// Required: No
+	//
+	// Default: 1
+	//
+	// Valid Values:
+	//  - A positive integer, or zero for an unbuffered channel
+	InChanCapacity string = "InChanCapacity"
+
+	// InBatchSize sets the maximum number of messages delivered together to the session, when they have been
+	// received together, e.g. coalesced into one TCP segment by the counterparty. The channel sized by
+	// InChanCapacity then buffers batches of messages. Batches reduce the overhead of sessions receiving many small
+	// messages, such as market data.
+	//
+	// Required: No
+	//
+	// Default: 1
+	//
+	// Valid Values:
+	//  - A positive integer
+	InBatchSize string = "InBatchSize"
+
+	// MaxQueuedMessages limits the number of application messages queued for send, e.g. while the session is logged
+	// off. Sending a message once the queue is full fails with quickfix.ErrQueueFull, or waits for the queue to have
+	// room with quickfix.SendWithContext. Admin messages are always queued.
+	//
+	// Required: No
+	//
+	// Default: 0
+	//
+	// Valid Values:
+	//  - A positive integer, or zero for an unbounded queue
+	MaxQueuedMessages string = "MaxQueuedMessages"
+
+	// ReuseIncomingMessages determines if incoming messages are parsed into messages taken from a pool, and returned
+	// to it once processed, reducing allocations. The application must not use a message passed to FromAdmin or
+	// FromApp after the callback returns; Message.CopyInto copies a message to be kept.
+	//
+	// Required: No
+	//
+	// Default: N
 	//
 	// Valid Values:
 	//  - Y
 	//  - N
-	ValidateFieldsOutOfOrder string = "ValidateFieldsOutOfOrder"
+	ReuseIncomingMessages string = "ReuseIncomingMessages"
+)
 
-	// ValidateFieldsHaveValues if set to N, fields without values (i.e. |11=| for an empty ClOrdID)
-	// will not be rejected, even if RejectInvalidMessage is set to N.
-	// Useful for connecting to systems that improperly send empty tags.
+const (
+	// Initiator-only settings.
+
+	// ReconnectInterval defines the time between reconnection attempts in seconds.
+	// Only used for initiators.
+	// Value must be positive integer.
 	//
 	// Required: No
 	//
-	// Default: Y
+	// Default: 30
 	//
 	// Valid Values:
-	//  - Y
-	//  - N
-	ValidateFieldsHaveValues string = "ValidateFieldsHaveValues"
+	//  - Any positive integer
+	ReconnectInterval string = "ReconnectInterval"
 
-	// CheckLatency if set to Y, messages must be received from the counter-party within a defined number of seconds.
-	// It is useful to turn this off if a system uses localtime for it's timestamps instead of GMT.
+	// ReconnectBackoffBase backs off reconnections exponentially in place of ReconnectInterval: the first reconnection
+	// waits this long, each following one twice as long as the one before, up to ReconnectBackoffMax. A successful
+	// logon resets the backoff. Value can either be a duration string or a number of seconds.
+	// Only used for initiators.
 	//
 	// Required: No
 	//
-	// Default: Y
+	// Default: Reconnections wait ReconnectInterval
 	//
 	// Valid Values:
-	//  - Y
-	//  - N
-	CheckLatency string = "CheckLatency"
+	//  - A positive integer number of seconds, or a positive duration string such as "500ms"
+	ReconnectBackoffBase string = "ReconnectBackoffBase"
 
-	// MaxLatency if CheckLatency is set to Y, this defines the number of seconds latency allowed for a message to be processed.
-	// Value must be positive integer.
+	// ReconnectBackoffMax caps the delay of the reconnections backed off with ReconnectBackoffBase. Value can either be
+	// a duration string or a number of seconds.
+	// Only used for initiators.
 	//
 	// Required: No
 	//
-	// Default: 120
+	// Default: 5m
 	//
 	// Valid Values:
-	//  - Any positive integer
-	MaxLatency string = "MaxLatency"
+	//  - An integer number of seconds, or a duration string, not less than ReconnectBackoffBase
+	ReconnectBackoffMax string = "ReconnectBackoffMax"
 
-	// InChanCapacity sets the maximum number of messages that can be buffered in the channel for incoming FIX messages.
+	// ReconnectJitter randomizes the delay of each reconnection by up to this percentage of it either way, so that
+	// initiators disconnected at once do not reconnect at once.
+	// Only used for initiators.
 	//
 	// Required: No
 	//
-	// Default: 1
+	// Default: 0
 	//
 	// Valid Values:
-	//  - A positive integer, or zero for an unbuffered channel
-	InChanCapacity string = "InChanCapacity"
-)
+	//  - An integer from 0 to 100
+	ReconnectJitter string = "ReconnectJitter"
 
-const (
-	// Initiator-only settings.
-
-	// ReconnectInterval defines the time between reconnection attempts in seconds.
+	// ReconnectGroup names the group of sessions sharing the connection attempt rate of a quickfix.ReconnectCoordinator,
+	// e.g. the sessions to the same venue. Has no effect without a ReconnectCoordinator.
 	// Only used for initiators.
-	// Value must be positive integer.
 	//
 	// Required: No
 	//
-	// Default: 30
+	// Default: The host of the SocketConnectHost connected to
 	//
 	// Valid Values:
-	//  - Any positive integer
-	ReconnectInterval string = "ReconnectInterval"
+	//  - Any string
+	ReconnectGroup string = "ReconnectGroup"
 
 	// LogoutTimeout defines the number of seconds to wait for a logout response before disconnecting.
 	// Only used for initiators.
@@ -540,6 +1127,33 @@ const (
 	//  - Any positive integer
 	LogonTimeout string = "LogonTimeout"
 
+	// MaxLogonRejectRetries limits how many times an initiator reconnects after consecutive logon rejections,
+	// that is a Logout received in reply to its Logon, typically for invalid credentials. Once exceeded the initiator
+	// stops reconnecting, or waits LogonRejectLockout if set. Transport failures and logon timeouts do not count,
+	// and a successful logon resets the count. The Application is notified of each rejection with its Text
+	// if it implements quickfix.LogonRejectedHandler.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: Unlimited
+	//
+	// Valid Values:
+	//  - Any non-negative integer
+	MaxLogonRejectRetries string = "MaxLogonRejectRetries"
+
+	// LogonRejectLockout sets how long an initiator waits before reconnecting once MaxLogonRejectRetries is exceeded,
+	// after which the count of rejections starts over. Value can either be a duration string or a number of seconds.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: Reconnecting stops
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "15m"
+	LogonRejectLockout string = "LogonRejectLockout"
+
 	// HeartBtInt sets the FIX session heartbeat interval in seconds.
 	// Only used for initiators (unless acceptor sets HeartBtIntOverride to Y).
 	// Value must be positive integer.
@@ -552,6 +1166,18 @@ const (
 	//  - Any positive integer
 	HeartBtInt string = "HeartBtInt"
 
+	// AlwaysSendHeartbeats if set to Y, sends a Heartbeat every HeartBtInt regardless of the other messages sent, for
+	// venues expecting them. By default a Heartbeat is only sent when no other message was sent for HeartBtInt.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	AlwaysSendHeartbeats string = "AlwaysSendHeartbeats"
+
 	// SocketConnectHost sets the host to attempt to connect to.
 	// In config files you can also set SocketConnectHost<n> where n is a positive integer.
 	// This allows for alternate socket hosts for connecting to a session for failover.
@@ -578,6 +1204,37 @@ const (
 	//  - A positive integer
 	SocketConnectPort string = "SocketConnectPort"
 
+	// SocketConnectFailover sets the order in which an initiator tries the endpoints of SocketConnectHost and
+	// SocketConnectHost<n>.
+	//  - ROUND_ROBIN moves on to the following endpoint after each connection, whether it failed or was disconnected.
+	//  - PRIORITY tries the endpoints in order, moving on to the following endpoint when a connection fails, and fails
+	//    back to SocketConnectHost once a connection is disconnected.
+	// The Application is notified of each endpoint connected to if it implements quickfix.ConnectEndpointHandler.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: ROUND_ROBIN
+	//
+	// Valid Values:
+	//  - ROUND_ROBIN
+	//  - PRIORITY
+	SocketConnectFailover string = "SocketConnectFailover"
+
+	// SocketConnectBackoffMax backs off an endpoint after a failed connection, so that it is skipped by the following
+	// attempts in favor of the other endpoints, for ReconnectInterval doubled with each consecutive failure of the
+	// endpoint, up to this value. A successful connection resets the backoff of the endpoint. Value can either be a
+	// duration string or a number of seconds.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: Endpoints are not backed off
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "5m"
+	SocketConnectBackoffMax string = "SocketConnectBackoffMax"
+
 	// SocketTimeout sets the duration of timeout for TLS handshake.
 	// Only used for initiators.
 	//
@@ -593,6 +1250,73 @@ const (
 	//  - A valid go time.Duration
 	SocketTimeout string = "SocketTimeout"
 
+	// SocketAddressFamily restricts the addresses of SocketConnectHost connected to, when it has both A and AAAA records.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: any
+	//
+	// Valid Values:
+	//  - any (IPv6 and IPv4 addresses are raced, see SocketFallbackDelay)
+	//  - ipv4
+	//  - ipv6
+	SocketAddressFamily string = "SocketAddressFamily"
+
+	// SocketFallbackDelay sets how long to wait for the IPv6 connection attempts before racing the IPv4 addresses of
+	// SocketConnectHost, in the manner of RFC 8305 "Happy Eyeballs". The IPv4 attempts also start as soon as the IPv6
+	// ones have failed. A negative value starts both at once.
+	// Only used for initiators.
+	//
+	// Example Values:
+	//  - SocketFallbackDelay=250ms
+	//
+	// Required: No
+	//
+	// Default: 300ms
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	SocketFallbackDelay string = "SocketFallbackDelay"
+
+	// SocketConnectTimeoutIPv4 sets the timeout of each connection attempt to an IPv4 address, overriding SocketTimeout.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: SocketTimeout
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	SocketConnectTimeoutIPv4 string = "SocketConnectTimeoutIPv4"
+
+	// SocketConnectTimeoutIPv6 sets the timeout of each connection attempt to an IPv6 address, overriding SocketTimeout.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: SocketTimeout
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	SocketConnectTimeoutIPv6 string = "SocketConnectTimeoutIPv6"
+
+	// SocketLocalHost binds the connections to a local source address. An IPv4 and an IPv6 address may be given,
+	// separated by a comma, each used for the connections to the addresses of its family.
+	// Only used for initiators.
+	//
+	// Example Values:
+	//  - SocketLocalHost=10.0.0.5
+	//  - SocketLocalHost=10.0.0.5,2001:db8::5
+	//
+	// Required: No
+	//
+	// Default: Chosen by the operating system
+	//
+	// Valid Values:
+	//  - An IPv4 address, an IPv6 address, or one of each separated by a comma
+	SocketLocalHost string = "SocketLocalHost"
+
 	// ProxyType sets the type of proxy server to connect to.
 	// Only used for initiators.
 	//
@@ -850,8 +1574,31 @@ const (
 	//  - TLS10
 	//  - TLS11
 	//  - TLS12
+	//  - TLS13
 	SocketMinimumTLSVersion string = "SocketMinimumTLSVersion"
 
+	// SocketTLSCipherSuites restricts the cipher suites offered for TLS 1.0-1.2 connections.
+	// Only suites considered secure by crypto/tls are accepted, and each must support a version at or above
+	// SocketMinimumTLSVersion. TLS 1.3 suites are not configurable, so this cannot be combined with a minimum of TLS13.
+	//
+	// Required: No
+	//
+	// Default: Go's default cipher suites
+	//
+	// Valid Values:
+	//  - Comma separated crypto/tls cipher suite names, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+	SocketTLSCipherSuites string = "SocketTLSCipherSuites"
+
+	// SocketALPNProtocols sets the application protocols advertised via ALPN, in order of preference.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - Comma separated protocol names of at most 255 bytes each, e.g. fix
+	SocketALPNProtocols string = "SocketALPNProtocols"
+
 	// SocketUseSSL if set to Y, an initiator will use TLS even if client certificates are not present.
 	// It is set to N by default, meaning TLS will not be used if SocketPrivateKeyFile or SocketCertificateFile are not supplied.
 	//
@@ -884,6 +1631,21 @@ const (
 	//  - A valid path
 	FileLogPath string = "FileLogPath"
 
+	// PcapLogPath sets the directory path in which to write pcapng capture files of session traffic.
+	// Each FIX message is written as a synthetic IPv4/TCP packet carrying the plaintext (pre-TLS) message,
+	// with the decoded message header attached as a packet comment, so captures can be opened in Wireshark.
+	// This will create the directory path if it does not already exist.
+	// PcapLogPath is only relevant if also using pcap.NewLogFactory(..) in code
+	// when creating your LogFactory for your initiator or acceptor.
+	//
+	// Required: Only if using pcap capture as your Log
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A valid path
+	PcapLogPath string = "PcapLogPath"
+
 	// SQLLogDriver sets the name of the database driver to use for application logs (see https://go.dev/wiki/SQLDrivers for the list of available drivers).
 	// SQLLogDriver is only relevant if also using sql.NewLogFactory(..) in code
 	// when creating your LogFactory for your initiator or acceptor.
@@ -986,6 +1748,7 @@ const (
 	// This will force QuickFIX/Go to always send GapFills instead of resending messages.
 	// Use this if you know you never want to resend a message.
 	// This is useful for market data streams when logging all incoming messages is not important.
+	// See also StorePolicy.
 	//
 	// Required: No
 	//
@@ -996,6 +1759,21 @@ const (
 	//  - N
 	PersistMessages string = "PersistMessages"
 
+	// StorePolicy determines what the MessageStore of the session persists. TRANSIENT suits secondary quote or
+	// market data stream sessions, whose messages are meaningless once stale: the MessageStore of the session discards
+	// outbound messages instead of saving them, sparing the disk I/O, and ResendRequests are always answered with a
+	// SequenceReset-GapFill. The sequence numbers are still persisted by the configured store, so that the session
+	// resumes its sequence after a restart.
+	//
+	// Required: No
+	//
+	// Default: PERSISTENT
+	//
+	// Valid Values:
+	//  - PERSISTENT (outbound messages are saved, unless PersistMessages is N)
+	//  - TRANSIENT (outbound messages are not saved, incompatible with PersistMessages=Y)
+	StorePolicy string = "StorePolicy"
+
 	// FileStorePath sets the directory path in which to write sequence number and message files.
 	// This will create the directory path if it does not already exist.
 	// FileStorePath is only relevant if also using file.NewStoreFactory(..) in code
@@ -1023,6 +1801,58 @@ const (
 	//  - N
 	FileStoreSync string = "FileStoreSync"
 
+	// FileStoreSyncInterval makes the FileStore buffer the messages saved and write them in batches, synced at once if
+	// FileStoreSync is set, at most this long after the first message of the batch. Sequence numbers are still
+	// written on every change, and synced with the batch, so they never fall behind the messages sent. This weakens
+	// durability: the sequence numbers are persisted ahead of the buffered messages, so a crash, even of the process
+	// alone, loses up to FileStoreMaxBatch messages sent within the last FileStoreSyncInterval while the sequence
+	// numbers account for them, and those messages are gap filled when resent. Buffered messages are written before
+	// a resend reads from the store, and can be written at any time with the store's Flush method, see
+	// quickfix.FlushStore.
+	// Value can either be a duration string or a number of seconds.
+	// FileStoreSyncInterval is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: Disabled, every message is written and synced when saved
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "10ms"
+	FileStoreSyncInterval string = "FileStoreSyncInterval"
+
+	// FileStoreMaxBatch sets how many messages the FileStore buffers before writing them, ahead of
+	// FileStoreSyncInterval. Requires FileStoreSyncInterval. It is also the most messages a crash can lose from the
+	// store while their sequence numbers are kept, see FileStoreSyncInterval.
+	// FileStoreMaxBatch is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 1000
+	//
+	// Valid Values:
+	//  - A positive integer
+	FileStoreMaxBatch string = "FileStoreMaxBatch"
+
+	// FileStorePartitionByDate controls whether the FileStore writes messages to one body and header file per trading date.
+	// The trading date is the date the session window started on, in the session TimeZone (UTC if unset): a window
+	// starting at StartTime, or at the start of the first of SessionWindows, runs until the next day's start, so that a
+	// 17:00 to 17:00 session keeps one partition. Sessions without StartTime use the calendar date.
+	// Resetting the store then removes whole partition files instead of rewriting large files,
+	// and resends spanning the partition boundary read transparently across partitions.
+	// FileStorePartitionByDate is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	FileStorePartitionByDate string = "FileStorePartitionByDate"
+
 	// SQLStoreDriver sets the name of the database driver to use for message storage (see https://go.dev/wiki/SQLDrivers for the list of available drivers).
 	// SQLStoreDriver is only relevant if also using sql.NewStoreFactory(..) in code
 	// when creating your MessageStoreFactory for your initiator or acceptor.
@@ -1093,6 +1923,63 @@ const (
 	//	- A valid string
 	SQLStoreSessionsTableName = "SQLStoreSessionsTableName"
 
+	// SQLStorePartitionByDate controls whether the SQLStore writes messages to one table per session and trading date.
+	// The trading date is the date the session window started on, in the session TimeZone (UTC if unset): a window
+	// starting at StartTime, or at the start of the first of SessionWindows, runs until the next day's start, so that a
+	// 17:00 to 17:00 session keeps one partition. Sessions without StartTime use the calendar date.
+	// Partition tables are named after the messages table and created on demand, and are tracked in a
+	// "<messages table>_partitions" table. Resetting the store drops the session's partition tables
+	// instead of issuing a DELETE, and resends spanning the partition boundary read transparently across partitions.
+	// Requires a database supporting CREATE TABLE IF NOT EXISTS, and INSERT ... ON CONFLICT DO NOTHING or, for mysql,
+	// INSERT IGNORE, e.g. sqlite3 3.24 or later, postgres 9.5 or later, or mysql.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SQLStorePartitionByDate = "SQLStorePartitionByDate"
+
+	// SQLStoreMigrate controls whether the SQLStore creates or upgrades its tables when the store is created, see
+	// sql.Migrate. Applied migrations are recorded in a "<sessions table>_migrations" table. Tables created
+	// beforehand from the scripts in _sql are left as they are.
+	// Supported for the postgres, pgx, mysql and sqlite3 drivers.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SQLStoreMigrate = "SQLStoreMigrate"
+
+	// SQLStoreBatchSize sets the number of outgoing messages the SQLStore writes to the database in a single
+	// transaction. Messages are written once the batch is full or SQLStoreBatchInterval after the first message of
+	// the batch, and before any resend lookup. Outgoing messages and the next sender MsgSeqNum of an unwritten batch
+	// are lost if the process exits abnormally, so that the session may reuse sequence numbers on restart.
+	//
+	// Required: No
+	//
+	// Default: 1 (every message is written immediately)
+	//
+	// Valid Values:
+	//  - A positive integer
+	SQLStoreBatchSize = "SQLStoreBatchSize"
+
+	// SQLStoreBatchInterval sets how long an outgoing message may wait for its batch to fill before it is written.
+	// Only relevant if SQLStoreBatchSize is greater than 1.
+	//
+	// Required: No
+	//
+	// Default: 10ms
+	//
+	// Valid Values:
+	//  - A positive go time.Duration
+	SQLStoreBatchInterval = "SQLStoreBatchInterval"
+
 	// MongoStoreConnection sets the MongoDB connection URL to use for message storage.
 	//
 	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
@@ -1138,6 +2025,73 @@ const (
 	// Valid Values:
 	//  - A string corresponding to a MongoDB replica set
 	MongoStoreReplicaSet string = "MongoStoreReplicaSet"
+
+	// RedisStoreAddress sets the host:port of the Redis server to use for message storage. Sessions sharing a Redis
+	// server and key prefix share their state, so that another engine instance can take over a session.
+	//
+	// RedisStoreAddress is only relevant if also using redis.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using Redis as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A host:port address, such as localhost:6379
+	RedisStoreAddress string = "RedisStoreAddress"
+
+	// RedisStoreUsername sets the username to authenticate with, for servers using access control lists. Requires
+	// RedisStorePassword.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string corresponding to a Redis user
+	RedisStoreUsername string = "RedisStoreUsername"
+
+	// RedisStorePassword sets the password to authenticate with.
+	//
+	// Required: No
+	//
+	// Default: No authentication
+	//
+	// Valid Values:
+	//  - A string corresponding to the password of the Redis server or user
+	RedisStorePassword string = "RedisStorePassword"
+
+	// RedisStoreDB sets the logical database of the Redis server to use for message storage.
+	//
+	// Required: No
+	//
+	// Default: 0
+	//
+	// Valid Values:
+	//  - A non-negative integer
+	RedisStoreDB string = "RedisStoreDB"
+
+	// RedisStoreKeyPrefix sets the prefix of the keys holding the session state and messages, <prefix>:<SessionID>:session
+	// and <prefix>:<SessionID>:messages.
+	//
+	// Required: No
+	//
+	// Default: quickfix
+	//
+	// Valid Values:
+	//  - A non-empty string
+	RedisStoreKeyPrefix string = "RedisStoreKeyPrefix"
+
+	// RedisStoreTTL sets how long the keys of a session are kept after the last write. Expired sessions start over
+	// with sequence numbers reset to 1. Value can either be a duration string or a number of seconds.
+	//
+	// Required: No
+	//
+	// Default: Keys never expire
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds, or a positive duration string such as "72h"
+	RedisStoreTTL string = "RedisStoreTTL"
 )
 
 const (
@@ -1158,6 +2112,18 @@ const (
 	//  - A positive integer
 	ResendRequestChunkSize string = "ResendRequestChunkSize"
 
+	// ResendRequestTimeout is how long a range requested with a ResendRequest may go unanswered. Until then, gaps
+	// within the range are not requested again; once it has elapsed, the range is requested again.
+	// Value can either be a duration string or a number of seconds, 0 never requesting a range again.
+	//
+	// Required: No
+	//
+	// Default: 0 (disabled)
+	//
+	// Valid Values:
+	//  - A non-negative integer number of seconds, or a non-negative duration string such as "500ms"
+	ResendRequestTimeout string = "ResendRequestTimeout"
+
 	// EnableLastMsgSeqNumProcessed tells the FIX engine to add the last message sequence number processed
 	// to outgoing message headers (using optional tag 369).
 	//
@@ -30,7 +30,9 @@ func writeLoop(connection io.Writer, messageOut chan []byte, log Log) {
 	}
 }
 
-func readLoop(parser *parser, msgIn chan fixIn, log Log) {
+// readLoop delivers the messages read by parser to msgIn, in batches of the messages received together, up to
+// maxBatch messages.
+func readLoop(parser *parser, msgIn chan []fixIn, maxBatch int, log Log) {
 	defer close(msgIn)
 
 	for {
@@ -39,6 +41,15 @@ func readLoop(parser *parser, msgIn chan fixIn, log Log) {
 			log.OnEvent(err.Error())
 			return
 		}
-		msgIn <- fixIn{msg, parser.lastRead}
+
+		batch := []fixIn{{msg, parser.lastRead}}
+		for len(batch) < maxBatch {
+			if msg, ok := parser.readBuffered(); ok {
+				batch = append(batch, fixIn{msg, parser.lastRead})
+			} else {
+				break
+			}
+		}
+		msgIn <- batch
 	}
 }
@@ -17,6 +17,7 @@ package quickfix
 
 import (
 	"bytes"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -41,11 +42,11 @@ func TestWriteLoop(t *testing.T) {
 }
 
 func TestReadLoop(t *testing.T) {
-	msgIn := make(chan fixIn)
+	msgIn := make(chan []fixIn)
 	stream := "hello8=FIX.4.09=5blah10=103garbage8=FIX.4.09=4foo10=103"
 
 	parser := newParser(strings.NewReader(stream))
-	go readLoop(parser, msgIn, nullLog{})
+	go readLoop(parser, msgIn, 1, nullLog{})
 
 	var tests = []struct {
 		expectedMsg   string
@@ -57,7 +58,7 @@ func TestReadLoop(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		msg, ok := <-msgIn
+		batch, ok := <-msgIn
 		switch {
 		case !ok && !test.channelClosed:
 			t.Error("Channel unexpectedly closed")
@@ -66,8 +67,37 @@ func TestReadLoop(t *testing.T) {
 			continue
 		}
 
-		if msg.bytes.String() != test.expectedMsg {
-			t.Errorf("Expected %v got %v", test.expectedMsg, msg.bytes.String())
+		if len(batch) != 1 {
+			t.Fatalf("Expected a batch of 1 message, got %v", len(batch))
 		}
+		if batch[0].bytes.String() != test.expectedMsg {
+			t.Errorf("Expected %v got %v", test.expectedMsg, batch[0].bytes.String())
+		}
+	}
+}
+
+func TestReadLoopBatch(t *testing.T) {
+	msgIn := make(chan []fixIn)
+	stream := "8=FIX.4.0\x019=4\x01one\x0110=103\x018=FIX.4.0\x019=4\x01two\x0110=103\x01" +
+		"8=FIX.4.0\x019=6\x01three\x0110=103\x018=FIX.4.0\x019=5\x01fo"
+
+	parser := newParser(strings.NewReader(stream))
+	go readLoop(parser, msgIn, 2, nullLog{})
+
+	var batches [][]string
+	for batch := range msgIn {
+		var msgs []string
+		for _, in := range batch {
+			msgs = append(msgs, in.bytes.String())
+		}
+		batches = append(batches, msgs)
+	}
+
+	expected := [][]string{
+		{"8=FIX.4.0\x019=4\x01one\x0110=103\x01", "8=FIX.4.0\x019=4\x01two\x0110=103\x01"},
+		{"8=FIX.4.0\x019=6\x01three\x0110=103\x01"},
+	}
+	if !reflect.DeepEqual(expected, batches) {
+		t.Errorf("Expected %q got %q", expected, batches)
 	}
 }
@@ -0,0 +1,194 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+)
+
+// messageRing holds the most recent raw messages.
+type messageRing struct {
+	msgs [][]byte
+	next int
+}
+
+func (r *messageRing) add(msg []byte) {
+	msg = append([]byte(nil), msg...)
+	if len(r.msgs) < cap(r.msgs) {
+		r.msgs = append(r.msgs, msg)
+		return
+	}
+	r.msgs[r.next] = msg
+	r.next = (r.next + 1) % len(r.msgs)
+}
+
+// all returns the messages oldest first.
+func (r *messageRing) all() [][]byte {
+	return append(append([][]byte(nil), r.msgs[r.next:]...), r.msgs[:r.next]...)
+}
+
+// crashDump records the recent traffic of a session for diagnostic bundles, see CrashDumpPath.
+type crashDump struct {
+	sync.Mutex
+	inbound, outbound messageRing
+}
+
+func newCrashDump(messageCount int) *crashDump {
+	return &crashDump{
+		inbound:  messageRing{msgs: make([][]byte, 0, messageCount)},
+		outbound: messageRing{msgs: make([][]byte, 0, messageCount)},
+	}
+}
+
+func (d *crashDump) incoming(msg []byte) {
+	if d == nil {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	d.inbound.add(msg)
+}
+
+func (d *crashDump) outgoing(msg []byte) {
+	if d == nil {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	d.outbound.add(msg)
+}
+
+func (d *crashDump) messages() (inbound, outbound [][]byte) {
+	d.Lock()
+	defer d.Unlock()
+	return d.inbound.all(), d.outbound.all()
+}
+
+// recoverWithCrashDump writes a crash dump for a panic in the session goroutine, then resumes panicking.
+func (s *Session) recoverWithCrashDump() {
+	if s.crashDump == nil {
+		return
+	}
+	if r := recover(); r != nil {
+		s.writeCrashDump(fmt.Sprintf("panic: %v\n\n%s", r, debug.Stack()))
+		panic(r)
+	}
+}
+
+// writeCrashDump writes a diagnostic bundle if CrashDumpPath is set and logs its path.
+func (s *Session) writeCrashDump(reason string) {
+	if s.crashDump == nil {
+		return
+	}
+
+	path, err := s.writeCrashDumpBundle(reason, time.Now())
+	if err != nil {
+		s.log.OnEventf("Failed to write crash dump: %v", err)
+		return
+	}
+	s.log.OnEventf("Crash dump written to %v", path)
+}
+
+func (s *Session) writeCrashDumpBundle(reason string, now time.Time) (string, error) {
+	path := filepath.Join(s.CrashDumpPath,
+		fmt.Sprintf("%s-%s", crashDumpPrefix(s.sessionID), now.UTC().Format("20060102-150405.000000000")))
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	var state bytes.Buffer
+	fmt.Fprintf(&state, "Time: %v\n", now.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&state, "SessionID: %v\n", s.sessionID)
+	if len(s.SessionLabels) > 0 {
+		fmt.Fprintf(&state, "Labels: %v\n", FormatLabels(s.SessionLabels))
+	}
+	if s.State != nil {
+		fmt.Fprintf(&state, "State: %v\n", s.State)
+	}
+	status := s.status()
+	fmt.Fprintf(&state, "NextSenderMsgSeqNum: %v\n", status.NextSenderMsgSeqNum)
+	fmt.Fprintf(&state, "NextTargetMsgSeqNum: %v\n", status.NextTargetMsgSeqNum)
+	if len(status.OutstandingResendRanges) > 0 {
+		fmt.Fprintf(&state, "OutstandingResendRanges: %v\n", status.OutstandingResendRanges)
+	}
+	fmt.Fprintf(&state, "Reason: %v\n", reason)
+
+	inbound, outbound := s.crashDump.messages()
+
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return "", err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"session.txt", state.Bytes()},
+		{"inbound.log", joinMessages(inbound)},
+		{"outbound.log", joinMessages(outbound)},
+		{"goroutines.txt", goroutines.Bytes()},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(path, f.name), f.data, 0640); err != nil {
+			return "", err
+		}
+	}
+
+	return path, nil
+}
+
+// joinMessages writes one raw message per line.
+func joinMessages(msgs [][]byte) []byte {
+	var b bytes.Buffer
+	for _, msg := range msgs {
+		b.Write(msg)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+func crashDumpPrefix(s SessionID) string {
+	sender := []string{s.SenderCompID}
+	if s.SenderSubID != "" {
+		sender = append(sender, s.SenderSubID)
+	}
+	if s.SenderLocationID != "" {
+		sender = append(sender, s.SenderLocationID)
+	}
+
+	target := []string{s.TargetCompID}
+	if s.TargetSubID != "" {
+		target = append(target, s.TargetSubID)
+	}
+	if s.TargetLocationID != "" {
+		target = append(target, s.TargetLocationID)
+	}
+
+	fname := []string{s.BeginString, strings.Join(sender, "_"), strings.Join(target, "_")}
+	if s.Qualifier != "" {
+		fname = append(fname, s.Qualifier)
+	}
+	return strings.Join(fname, "-")
+}
@@ -0,0 +1,127 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMessageRing(t *testing.T) {
+	r := messageRing{msgs: make([][]byte, 0, 3)}
+	assert.Empty(t, r.all())
+
+	r.add([]byte("1"))
+	r.add([]byte("2"))
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, r.all())
+
+	r.add([]byte("3"))
+	r.add([]byte("4"))
+	r.add([]byte("5"))
+	assert.Equal(t, [][]byte{[]byte("3"), []byte("4"), []byte("5")}, r.all())
+}
+
+func TestMessageRingCopies(t *testing.T) {
+	r := messageRing{msgs: make([][]byte, 0, 1)}
+	msg := []byte("1")
+	r.add(msg)
+	msg[0] = '2'
+
+	assert.Equal(t, [][]byte{[]byte("1")}, r.all())
+}
+
+type CrashDumpTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestCrashDumpTestSuite(t *testing.T) {
+	suite.Run(t, new(CrashDumpTestSuite))
+}
+
+func (s *CrashDumpTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.CrashDumpPath = s.T().TempDir()
+	s.Session.crashDump = newCrashDump(2)
+}
+
+func (s *CrashDumpTestSuite) bundle() string {
+	entries, err := os.ReadDir(s.Session.CrashDumpPath)
+	s.Require().Nil(err)
+	s.Require().Len(entries, 1)
+	return filepath.Join(s.Session.CrashDumpPath, entries[0].Name())
+}
+
+func (s *CrashDumpTestSuite) readFile(bundle, name string) string {
+	b, err := os.ReadFile(filepath.Join(bundle, name))
+	s.Require().Nil(err)
+	return string(b)
+}
+
+func (s *CrashDumpTestSuite) TestHandleStateError() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("OnLogout")
+	for i := 0; i < 3; i++ {
+		s.Incoming(s.Session, fixIn{bytes: bytes.NewBuffer(s.NewOrderSingle().Build()), receiveTime: time.Now()})
+	}
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+
+	s.Session.setState(s.Session, handleStateError(s.Session, errors.New("store unavailable")))
+
+	bundle := s.bundle()
+	s.Contains(filepath.Base(bundle), "FIX.4.2-ISLD-TW-")
+
+	state := s.readFile(bundle, "session.txt")
+	s.Contains(state, "SessionID: FIX.4.2:ISLD->TW")
+	s.Contains(state, "State: In Session")
+	s.Contains(state, "NextTargetMsgSeqNum: 4")
+	s.Contains(state, "Reason: store unavailable")
+
+	inbound := s.readFile(bundle, "inbound.log")
+	s.Equal(2, bytes.Count([]byte(inbound), []byte("\n")), "only the last messages are kept")
+	s.Contains(inbound, "\x0134=2\x01")
+	s.Contains(inbound, "\x0134=3\x01")
+
+	s.Contains(s.readFile(bundle, "outbound.log"), "35=D")
+	s.Contains(s.readFile(bundle, "goroutines.txt"), "goroutine")
+}
+
+func (s *CrashDumpTestSuite) TestPanic() {
+	s.Session.State = nil
+	s.Panics(func() {
+		defer s.Session.recoverWithCrashDump()
+		panic("boom")
+	})
+
+	s.Contains(s.readFile(s.bundle(), "session.txt"), "Reason: panic: boom")
+}
+
+func (s *CrashDumpTestSuite) TestDisabled() {
+	s.Session.crashDump = nil
+	s.Session.writeCrashDump("reason")
+
+	entries, err := os.ReadDir(s.Session.CrashDumpPath)
+	s.Nil(err)
+	s.Empty(entries)
+}
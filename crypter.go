@@ -0,0 +1,342 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Crypter protects sensitive material with keys held by a key management service, such as AWS KMS or
+// keys mounted from a Kubernetes secret. See the crypter/keydir package for an implementation.
+type Crypter interface {
+	// Encrypt encrypts plaintext with the key identified by keyID.
+	Encrypt(keyID string, plaintext []byte) ([]byte, error)
+
+	// Decrypt decrypts ciphertext produced by Encrypt with the same keyID.
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// encryptedSettingPrefix marks a setting value encrypted with a Crypter, formatted as ENC:<keyID>:<base64 ciphertext>.
+const encryptedSettingPrefix = "ENC:"
+
+// EncryptSetting returns value encrypted with crypter in the form expected by Settings.Decrypt.
+func EncryptSetting(crypter Crypter, keyID, value string) (string, error) {
+	ciphertext, err := crypter.Encrypt(keyID, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	return encryptedSettingPrefix + keyID + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts, in place, every global, profile and session setting of the form ENC:<keyID>:<base64 ciphertext>.
+// It is typically called right after ParseSettings so that secrets such as passwords and private keys can be kept
+// encrypted in configuration files.
+func (s *Settings) Decrypt(crypter Crypter) error {
+	s.lazyInit()
+
+	all := []*SessionSettings{s.globalSettings}
+	for _, settings := range s.profiles {
+		all = append(all, settings)
+	}
+	for _, settings := range s.sessionSettings {
+		all = append(all, settings)
+	}
+
+	for _, settings := range all {
+		if err := settings.decrypt(crypter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SessionSettings) decrypt(crypter Crypter) error {
+	for setting, value := range s.settings {
+		if !strings.HasPrefix(string(value), encryptedSettingPrefix) {
+			continue
+		}
+
+		keyID, encoded, ok := strings.Cut(strings.TrimPrefix(string(value), encryptedSettingPrefix), ":")
+		if !ok {
+			return IncorrectFormatForSetting{Setting: setting, Value: value, Err: fmt.Errorf("missing key id")}
+		}
+
+		ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return IncorrectFormatForSetting{Setting: setting, Value: value, Err: err}
+		}
+
+		plaintext, err := crypter.Decrypt(keyID, ciphertext)
+		if err != nil {
+			return fmt.Errorf("decrypting %v: %w", setting, err)
+		}
+		s.settings[setting] = plaintext
+	}
+	return nil
+}
+
+type encryptedStoreFactory struct {
+	factory MessageStoreFactory
+	crypter Crypter
+	keyID   string
+}
+
+// NewEncryptedMessageStoreFactory returns a MessageStoreFactory whose stores encrypt the messages saved
+// to the stores created by factory. Sequence numbers and creation times are stored as is. The dead letters kept for
+// DeadLetterQueue and the audit records kept for AuditChain are encrypted too, when the stores of factory implement
+// DeadLetterStore and AuditStore.
+func NewEncryptedMessageStoreFactory(factory MessageStoreFactory, crypter Crypter, keyID string) MessageStoreFactory {
+	return encryptedStoreFactory{factory: factory, crypter: crypter, keyID: keyID}
+}
+
+func (f encryptedStoreFactory) Create(sessionID SessionID) (MessageStore, error) {
+	store, err := f.factory.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := &encryptedStore{MessageStore: store, crypter: f.crypter, keyID: f.keyID}
+	if epochStore, ok := store.(EpochStore); ok {
+		return &encryptedEpochStore{encryptedStore: encrypted, EpochStore: epochStore}, nil
+	}
+	return encrypted, nil
+}
+
+type encryptedStore struct {
+	MessageStore
+	crypter Crypter
+	keyID   string
+}
+
+func (s *encryptedStore) SetLabels(labels map[string]string) {
+	setLabels(s.MessageStore, labels)
+}
+
+// Flush implements FlushStore, flushing the wrapped store if it buffers writes.
+func (s *encryptedStore) Flush() error {
+	if flushStore, ok := s.MessageStore.(FlushStore); ok {
+		return flushStore.Flush()
+	}
+	return nil
+}
+
+func (s *encryptedStore) SaveMessage(seqNum int, msg []byte) error {
+	ciphertext, err := s.crypter.Encrypt(s.keyID, msg)
+	if err != nil {
+		return err
+	}
+	return s.MessageStore.SaveMessage(seqNum, ciphertext)
+}
+
+func (s *encryptedStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	ciphertext, err := s.crypter.Encrypt(s.keyID, msg)
+	if err != nil {
+		return err
+	}
+	return s.MessageStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, ciphertext)
+}
+
+func (s *encryptedStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := s.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+func (s *encryptedStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	return s.MessageStore.IterateMessages(beginSeqNum, endSeqNum, func(ciphertext []byte) error {
+		msg, err := s.crypter.Decrypt(s.keyID, ciphertext)
+		if err != nil {
+			return err
+		}
+		return cb(msg)
+	})
+}
+
+// deadLetterStore implements deadLetterStoreProvider, encrypting the raw messages of the dead letters kept by the
+// wrapped store.
+func (s *encryptedStore) deadLetterStore() (DeadLetterStore, bool) {
+	store, ok := deadLetterStoreOf(s.MessageStore)
+	if !ok {
+		return nil, false
+	}
+	return encryptedDeadLetterStore{DeadLetterStore: store, crypter: s.crypter, keyID: s.keyID}, true
+}
+
+type encryptedDeadLetterStore struct {
+	DeadLetterStore
+	crypter Crypter
+	keyID   string
+}
+
+func (s encryptedDeadLetterStore) SaveDeadLetter(letter DeadLetter) (int, error) {
+	rawMessage, err := encryptString(s.crypter, s.keyID, letter.RawMessage)
+	if err != nil {
+		return 0, err
+	}
+	letter.RawMessage = rawMessage
+	return s.DeadLetterStore.SaveDeadLetter(letter)
+}
+
+func (s encryptedDeadLetterStore) DeadLetters() ([]DeadLetter, error) {
+	letters, err := s.DeadLetterStore.DeadLetters()
+	if err != nil {
+		return nil, err
+	}
+	for i := range letters {
+		if letters[i].RawMessage, err = decryptString(s.crypter, s.keyID, letters[i].RawMessage); err != nil {
+			return nil, err
+		}
+	}
+	return letters, nil
+}
+
+// auditStore implements auditStoreProvider, encrypting the raw messages of the audit records kept by the wrapped
+// store. The hashes are kept as is, so that the chain verifies against the decrypted messages.
+func (s *encryptedStore) auditStore() (AuditStore, bool) {
+	store, ok := auditStoreOf(s.MessageStore)
+	if !ok {
+		return nil, false
+	}
+	return encryptedAuditStore{AuditStore: store, crypter: s.crypter, keyID: s.keyID}, true
+}
+
+type encryptedAuditStore struct {
+	AuditStore
+	crypter Crypter
+	keyID   string
+}
+
+func (s encryptedAuditStore) SaveAuditRecord(record AuditRecord) error {
+	rawMessage, err := encryptString(s.crypter, s.keyID, record.RawMessage)
+	if err != nil {
+		return err
+	}
+	record.RawMessage = rawMessage
+	return s.AuditStore.SaveAuditRecord(record)
+}
+
+func (s encryptedAuditStore) AuditRecords() ([]AuditRecord, error) {
+	records, err := s.AuditStore.AuditRecords()
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].RawMessage, err = decryptString(s.crypter, s.keyID, records[i].RawMessage); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// encryptString returns plaintext encrypted and base64 encoded, so that it is safely kept as text by any store.
+func encryptString(crypter Crypter, keyID, plaintext string) (string, error) {
+	ciphertext, err := crypter.Encrypt(keyID, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func decryptString(crypter Crypter, keyID, encoded string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := crypter.Decrypt(keyID, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// encryptedEpochStore preserves the EpochStore implementation of the wrapped store.
+type encryptedEpochStore struct {
+	*encryptedStore
+	EpochStore
+}
+
+type encryptedLogFactory struct {
+	factory LogFactory
+	crypter Crypter
+	keyID   string
+}
+
+// NewEncryptedLogFactory returns a LogFactory whose logs encrypt the messages written to the logs created by factory.
+// Messages are logged as base64 ciphertext, events are logged as is.
+func NewEncryptedLogFactory(factory LogFactory, crypter Crypter, keyID string) LogFactory {
+	return encryptedLogFactory{factory: factory, crypter: crypter, keyID: keyID}
+}
+
+func (f encryptedLogFactory) Create() (Log, error) {
+	log, err := f.factory.Create()
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedLog{Log: log, crypter: f.crypter, keyID: f.keyID}, nil
+}
+
+func (f encryptedLogFactory) CreateSessionLog(sessionID SessionID) (Log, error) {
+	log, err := f.factory.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedLog{Log: log, crypter: f.crypter, keyID: f.keyID}, nil
+}
+
+type encryptedLog struct {
+	Log
+	crypter Crypter
+	keyID   string
+}
+
+func (l *encryptedLog) SetLabels(labels map[string]string) {
+	setLabels(l.Log, labels)
+}
+
+// Close implements io.Closer, closing the wrapped log if it holds resources.
+func (l *encryptedLog) Close() error {
+	return closeLog(l.Log)
+}
+
+func (l *encryptedLog) OnIncoming(msg []byte) {
+	if encrypted, ok := l.encrypt(msg); ok {
+		l.Log.OnIncoming(encrypted)
+	}
+}
+
+func (l *encryptedLog) OnOutgoing(msg []byte) {
+	if encrypted, ok := l.encrypt(msg); ok {
+		l.Log.OnOutgoing(encrypted)
+	}
+}
+
+// encrypt returns msg encrypted and base64 encoded. Failures are logged as events, never the message itself.
+func (l *encryptedLog) encrypt(msg []byte) ([]byte, bool) {
+	ciphertext, err := l.crypter.Encrypt(l.keyID, msg)
+	if err != nil {
+		l.Log.OnEventf("Failed to encrypt message: %v", err)
+		return nil, false
+	}
+
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(ciphertext)))
+	base64.StdEncoding.Encode(encoded, ciphertext)
+	return encoded, true
+}
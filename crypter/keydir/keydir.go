@@ -0,0 +1,110 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package keydir provides a quickfix.Crypter using AES-256-GCM keys read from a directory, one key per file
+// named after its key id, as when a Kubernetes secret is mounted as a volume.
+//
+// A Crypter backed by AWS KMS implements the same interface by calling the KMS Encrypt and Decrypt APIs with
+// the key id as KeyId, or by using KMS generated data keys to encrypt locally.
+package keydir
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type crypter struct {
+	dir  string
+	mu   sync.Mutex
+	aead map[string]cipher.AEAD
+}
+
+// NewCrypter returns a quickfix.Crypter reading keys from dir. Each file holds a 32 byte key, either raw or hex
+// encoded. Keys are read on first use, so keys added to a mounted secret are picked up without a restart.
+func NewCrypter(dir string) quickfix.Crypter {
+	return &crypter{dir: dir, aead: make(map[string]cipher.AEAD)}
+}
+
+func (c *crypter) key(keyID string) (cipher.AEAD, error) {
+	if keyID == "" || keyID != filepath.Base(keyID) || strings.HasPrefix(keyID, ".") {
+		return nil, fmt.Errorf("invalid key id %q", keyID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if aead, ok := c.aead[keyID]; ok {
+		return aead, nil
+	}
+
+	key, err := os.ReadFile(filepath.Join(c.dir, keyID))
+	if err != nil {
+		return nil, fmt.Errorf("reading key %v: %w", keyID, err)
+	}
+
+	if decoded, err := hex.DecodeString(strings.TrimSpace(string(key))); err == nil {
+		key = decoded
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key %v must be 32 bytes", keyID)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	c.aead[keyID] = aead
+	return aead, nil
+}
+
+// Encrypt returns the random nonce followed by the sealed plaintext.
+func (c *crypter) Encrypt(keyID string, plaintext []byte) ([]byte, error) {
+	aead, err := c.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(keyID)), nil
+}
+
+func (c *crypter) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	aead, err := c.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, []byte(keyID))
+}
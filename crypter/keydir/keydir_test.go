@@ -0,0 +1,58 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package keydir
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrypter(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "raw"), bytes.Repeat([]byte{1}, 32), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hex"), []byte(hex.EncodeToString(bytes.Repeat([]byte{2}, 32))+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "short"), []byte("short"), 0o600))
+
+	c := NewCrypter(dir)
+	for _, keyID := range []string{"raw", "hex"} {
+		ciphertext, err := c.Encrypt(keyID, []byte("secret"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(ciphertext), "secret")
+
+		plaintext, err := c.Decrypt(keyID, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(plaintext))
+	}
+
+	ciphertext, err := c.Encrypt("raw", []byte("secret"))
+	require.NoError(t, err)
+	_, err = c.Decrypt("hex", ciphertext)
+	assert.Error(t, err, "wrong key")
+
+	_, err = c.Decrypt("raw", ciphertext[:4])
+	assert.Error(t, err, "truncated")
+
+	for _, keyID := range []string{"short", "missing", "", "../raw", ".hidden"} {
+		_, err = c.Encrypt(keyID, []byte("secret"))
+		assert.Error(t, err, keyID)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// xorCrypter is a reversible stand in for a real Crypter.
+type xorCrypter struct{}
+
+func (xorCrypter) Encrypt(keyID string, plaintext []byte) ([]byte, error) {
+	if keyID != "key" {
+		return nil, errors.New("unknown key")
+	}
+	out := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		out[i] = b ^ 0x5a
+	}
+	return out, nil
+}
+
+func (c xorCrypter) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	return c.Encrypt(keyID, ciphertext)
+}
+
+func TestSettingsDecrypt(t *testing.T) {
+	password, err := EncryptSetting(xorCrypter{}, "key", "s3cret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(password, "ENC:key:"))
+
+	s, err := ParseSettings(strings.NewReader(`
+[DEFAULT]
+SocketPrivateKeyBytes=` + password + `
+
+[PROFILE:p]
+HeartBtInt=30
+
+[SESSION]
+Profile=p
+BeginString=FIX.4.2
+SenderCompID=TW
+TargetCompID=ISLD
+Password=` + password))
+	require.NoError(t, err)
+	require.NoError(t, s.Decrypt(xorCrypter{}))
+
+	for _, settings := range s.SessionSettings() {
+		val, err := settings.Setting("Password")
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cret", val)
+
+		val, err = settings.Setting(config.SocketPrivateKeyBytes)
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cret", val)
+
+		val, err = settings.Setting(config.HeartBtInt)
+		assert.NoError(t, err)
+		assert.Equal(t, "30", val)
+	}
+}
+
+func TestSettingsDecryptErrors(t *testing.T) {
+	for _, value := range []string{"ENC:nokey", "ENC:key:not base64!", "ENC:other:" + base64.StdEncoding.EncodeToString([]byte("x"))} {
+		s := NewSettings()
+		s.GlobalSettings().Set("Password", value)
+		assert.Error(t, s.Decrypt(xorCrypter{}), value)
+	}
+}
+
+func TestEncryptedMessageStore(t *testing.T) {
+	sessionID := SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ISLD"}
+	inner, err := NewMemoryStoreFactory().Create(sessionID)
+	require.NoError(t, err)
+
+	store, err := NewEncryptedMessageStoreFactory(staticStoreFactory{inner}, xorCrypter{}, "key").Create(sessionID)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveMessage(1, []byte("msg1")))
+	require.NoError(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("msg2")))
+	assert.Equal(t, 2, store.NextSenderMsgSeqNum())
+
+	msgs, err := store.GetMessages(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("msg1"), []byte("msg2")}, msgs)
+
+	raw, err := inner.GetMessages(1, 2)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw[0]), "msg1")
+}
+
+func TestEncryptedDeadLetterStore(t *testing.T) {
+	sessionID := SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ISLD"}
+	inner, err := NewMemoryStoreFactory().Create(sessionID)
+	require.NoError(t, err)
+
+	store, err := NewEncryptedMessageStoreFactory(staticStoreFactory{inner}, xorCrypter{}, "key").Create(sessionID)
+	require.NoError(t, err)
+	deadLetters, ok := deadLetterStoreOf(store)
+	require.True(t, ok, "the DeadLetterStore of the wrapped store is kept")
+
+	_, err = deadLetters.SaveDeadLetter(DeadLetter{MsgType: "D", RawMessage: "msg1"})
+	require.NoError(t, err)
+	letters, err := deadLetters.DeadLetters()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, "msg1", letters[0].RawMessage)
+
+	raw, err := inner.(DeadLetterStore).DeadLetters()
+	require.NoError(t, err)
+	assert.NotContains(t, raw[0].RawMessage, "msg1")
+
+	store, err = NewEncryptedMessageStoreFactory(staticStoreFactory{&transientStore{MessageStore: inner}}, xorCrypter{}, "key").Create(sessionID)
+	require.NoError(t, err)
+	_, ok = deadLetterStoreOf(store)
+	assert.False(t, ok)
+}
+
+func TestEncryptedAuditStore(t *testing.T) {
+	sessionID := SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ISLD"}
+	inner, err := NewMemoryStoreFactory().Create(sessionID)
+	require.NoError(t, err)
+
+	store, err := NewEncryptedMessageStoreFactory(staticStoreFactory{inner}, xorCrypter{}, "key").Create(sessionID)
+	require.NoError(t, err)
+	auditStore, ok := auditStoreOf(store)
+	require.True(t, ok, "the AuditStore of the wrapped store is kept")
+
+	require.NoError(t, auditStore.SaveAuditRecord(AuditRecord{MsgSeqNum: 1, RawMessage: "msg1", Hash: auditHash("", []byte("msg1"))}))
+	records, err := auditStore.AuditRecords()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "msg1", records[0].RawMessage)
+	assert.NoError(t, VerifyAuditRecords(records))
+
+	raw, err := inner.(AuditStore).AuditRecords()
+	require.NoError(t, err)
+	assert.NotContains(t, raw[0].RawMessage, "msg1")
+}
+
+type staticStoreFactory struct{ store MessageStore }
+
+func (f staticStoreFactory) Create(SessionID) (MessageStore, error) { return f.store, nil }
+
+type recordingLog struct {
+	nullLog
+	incoming, outgoing [][]byte
+}
+
+func (l *recordingLog) OnIncoming(msg []byte) { l.incoming = append(l.incoming, msg) }
+func (l *recordingLog) OnOutgoing(msg []byte) { l.outgoing = append(l.outgoing, msg) }
+
+type staticLogFactory struct{ log Log }
+
+func (f staticLogFactory) Create() (Log, error)                    { return f.log, nil }
+func (f staticLogFactory) CreateSessionLog(SessionID) (Log, error) { return f.log, nil }
+
+func TestEncryptedLog(t *testing.T) {
+	inner := &recordingLog{}
+	log, err := NewEncryptedLogFactory(staticLogFactory{inner}, xorCrypter{}, "key").CreateSessionLog(SessionID{})
+	require.NoError(t, err)
+
+	log.OnIncoming([]byte("in"))
+	log.OnOutgoing([]byte("out"))
+
+	require.Len(t, inner.incoming, 1)
+	ciphertext, err := base64.StdEncoding.DecodeString(string(inner.incoming[0]))
+	require.NoError(t, err)
+	plaintext, err := xorCrypter{}.Decrypt("key", ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "in", string(plaintext))
+	require.Len(t, inner.outgoing, 1)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "iter"
+
+// Cursor walks the fields of a Message in order, header first and trailer last. Within each of them the fields of a
+// parsed message come in the order they were received, as with FieldMap.Iterate. A Cursor is positioned before the
+// first field, so that Next must be called before Tag and Value.
+type Cursor struct {
+	fields []TagValue
+	pos    int
+}
+
+// Cursor returns a Cursor over the fields of the Message.
+func (m *Message) Cursor() *Cursor {
+	c := &Cursor{pos: -1}
+	c.fields = append(c.fields, m.Header.orderedFields()...)
+	c.fields = append(c.fields, m.Body.orderedFields()...)
+	c.fields = append(c.fields, m.Trailer.orderedFields()...)
+	return c
+}
+
+// Next advances the Cursor to the next field, returning false once past the last field.
+func (c *Cursor) Next() bool {
+	if c.pos < len(c.fields) {
+		c.pos++
+	}
+	return c.pos < len(c.fields)
+}
+
+// Tag returns the tag of the current field.
+func (c *Cursor) Tag() Tag {
+	return c.fields[c.pos].tag
+}
+
+// Value returns the value of the current field.
+func (c *Cursor) Value() []byte {
+	return c.fields[c.pos].value
+}
+
+// Fields returns an iterator over the tags and values of the fields of the Message, header first and trailer last,
+// each in the order of FieldMap.All. Unlike Cursor, it builds no intermediate slice of fields.
+func (m *Message) Fields() iter.Seq2[Tag, []byte] {
+	return func(yield func(Tag, []byte) bool) {
+		for _, fieldMap := range [...]*FieldMap{&m.Header.FieldMap, &m.Body.FieldMap, &m.Trailer.FieldMap} {
+			for tag, value := range fieldMap.All() {
+				if !yield(tag, value) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxDecompressedDataSize bounds the decompressed size of a data field, guarding against decompression bombs.
+const maxDecompressedDataSize = 16 << 20
+
+// dataFieldPair is a data field and the length field preceding it, such as EncodedTextLen(354) and EncodedText(355).
+type dataFieldPair struct {
+	lengthTag Tag
+	dataTag   Tag
+}
+
+// dataCompression compresses the configured data fields of outgoing application messages with zlib, and
+// decompresses them in incoming application messages. Only body fields outside of repeating groups are compressed.
+type dataCompression struct {
+	pairs []dataFieldPair
+	level int
+
+	// lengthTags maps the length tags to the data tags, for parsing.
+	lengthTags map[Tag]Tag
+}
+
+func newDataCompression(pairs []dataFieldPair, level int) *dataCompression {
+	c := &dataCompression{pairs: pairs, level: level, lengthTags: make(map[Tag]Tag, len(pairs))}
+	for _, pair := range pairs {
+		c.lengthTags[pair.lengthTag] = pair.dataTag
+	}
+	return c
+}
+
+// dataFields returns the length tags of the data fields to read by length when parsing, nil if c is nil.
+func (c *dataCompression) dataFields() map[Tag]Tag {
+	if c == nil {
+		return nil
+	}
+	return c.lengthTags
+}
+
+// compress replaces the value of the configured data fields of msg with its zlib compressed form.
+func (c *dataCompression) compress(msg *Message) error {
+	if c == nil {
+		return nil
+	}
+
+	for _, pair := range c.pairs {
+		if !msg.Body.Has(pair.dataTag) {
+			continue
+		}
+		data, rejectErr := msg.Body.GetBytes(pair.dataTag)
+		if rejectErr != nil {
+			return rejectErr
+		}
+
+		var buf bytes.Buffer
+		w, err := zlib.NewWriterLevel(&buf, c.level)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(data); err != nil {
+			return err
+		}
+		if err = w.Close(); err != nil {
+			return err
+		}
+
+		msg.Body.SetInt(pair.lengthTag, buf.Len())
+		msg.Body.SetBytes(pair.dataTag, buf.Bytes())
+	}
+	return nil
+}
+
+// decompress restores the value of the configured data fields of msg, and sets their length fields to the
+// decompressed length.
+func (c *dataCompression) decompress(msg *Message) MessageRejectError {
+	if c == nil {
+		return nil
+	}
+
+	for _, pair := range c.pairs {
+		if !msg.Body.Has(pair.dataTag) {
+			continue
+		}
+		data, err := msg.Body.GetBytes(pair.dataTag)
+		if err != nil {
+			return err
+		}
+
+		r, zErr := zlib.NewReader(bytes.NewReader(data))
+		if zErr != nil {
+			return IncorrectDataFormatForValue(pair.dataTag)
+		}
+		decompressed, zErr := io.ReadAll(io.LimitReader(r, maxDecompressedDataSize+1))
+		if zErr != nil || len(decompressed) > maxDecompressedDataSize {
+			return IncorrectDataFormatForValue(pair.dataTag)
+		}
+
+		msg.Body.SetInt(pair.lengthTag, len(decompressed))
+		msg.Body.SetBytes(pair.dataTag, decompressed)
+	}
+	return nil
+}
+
+// parseDataFieldPairs parses a comma delimited list of length:data tag pairs.
+func parseDataFieldPairs(value string) ([]dataFieldPair, error) {
+	var pairs []dataFieldPair
+	for _, pairStr := range strings.Split(value, ",") {
+		if pairStr = strings.TrimSpace(pairStr); pairStr == "" {
+			continue
+		}
+
+		lengthStr, dataStr, ok := strings.Cut(pairStr, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a length:data tag pair", pairStr)
+		}
+		lengthTag, err := strconv.Atoi(strings.TrimSpace(lengthStr))
+		if err != nil || lengthTag <= 0 {
+			return nil, fmt.Errorf("invalid length tag in %q", pairStr)
+		}
+		dataTag, err := strconv.Atoi(strings.TrimSpace(dataStr))
+		if err != nil || dataTag <= 0 || dataTag == lengthTag {
+			return nil, fmt.Errorf("invalid data tag in %q", pairStr)
+		}
+		if Tag(lengthTag).IsHeader() || Tag(dataTag).IsHeader() || Tag(lengthTag).IsTrailer() || Tag(dataTag).IsTrailer() {
+			return nil, fmt.Errorf("%q is not a body field pair", pairStr)
+		}
+		pairs = append(pairs, dataFieldPair{lengthTag: Tag(lengthTag), dataTag: Tag(dataTag)})
+	}
+
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no length:data tag pair")
+	}
+	return pairs, nil
+}
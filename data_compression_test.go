@@ -0,0 +1,133 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"compress/zlib"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	tagEncodedTextLen Tag = 354
+	tagEncodedText    Tag = 355
+)
+
+type DataCompressionTestSuite struct {
+	SessionSuiteRig
+	payload string
+}
+
+func TestDataCompressionTestSuite(t *testing.T) {
+	suite.Run(t, new(DataCompressionTestSuite))
+}
+
+func (s *DataCompressionTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.compression = newDataCompression([]dataFieldPair{{lengthTag: tagEncodedTextLen, dataTag: tagEncodedText}}, zlib.BestCompression)
+	s.payload = "<AllocInstrctn>" + strings.Repeat(`<Alloc Acct="ACC1" Qty="100"/>`, 200) + "</AllocInstrctn>"
+}
+
+func (s *DataCompressionTestSuite) TestOutboundCompressed() {
+	msg := s.NewOrderSingle()
+	msg.Body.SetInt(tagEncodedTextLen, len(s.payload))
+	msg.Body.SetString(tagEncodedText, s.payload)
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(msg))
+
+	msgBytes, ok := s.Receiver.LastMessage()
+	s.Require().True(ok)
+	s.Require().NotNil(msgBytes)
+	s.Less(len(msgBytes), len(s.payload)/4)
+
+	// The compressed value may hold the field delimiter, it is read by its length.
+	sent := NewMessage()
+	s.Require().Nil(s.Session.ParseMessage(sent, bytes.NewBuffer(msgBytes)))
+	compressed, err := sent.Body.GetBytes(tagEncodedText)
+	s.Require().Nil(err)
+	s.FieldEquals(tagEncodedTextLen, len(compressed), sent.Body)
+
+	s.Nil(s.Session.compression.decompress(sent))
+	s.FieldEquals(tagEncodedText, s.payload, sent.Body)
+	s.FieldEquals(tagEncodedTextLen, len(s.payload), sent.Body)
+}
+
+func (s *DataCompressionTestSuite) TestAdminMessagesNotCompressed() {
+	msg := s.Heartbeat()
+	msg.Body.SetString(tagEncodedText, "plain")
+
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(s.Session.send(msg))
+	s.LastToAdminMessageSent()
+	s.FieldEquals(tagEncodedText, "plain", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *DataCompressionTestSuite) TestInboundDecompressed() {
+	msg := s.NewOrderSingle()
+	msg.Body.SetInt(tagEncodedTextLen, len(s.payload))
+	msg.Body.SetString(tagEncodedText, s.payload)
+	s.Require().Nil(s.Session.compression.compress(msg))
+
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, msg)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.FieldEquals(tagEncodedText, s.payload, msg.Body)
+	s.FieldEquals(tagEncodedTextLen, len(s.payload), msg.Body)
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *DataCompressionTestSuite) TestInboundCorruptRejected() {
+	msg := s.NewOrderSingle()
+	msg.Body.SetInt(tagEncodedTextLen, 3)
+	msg.Body.SetString(tagEncodedText, "bad")
+
+	s.MockApp.On("ToAdmin")
+	s.Session.fixMsgIn(s.Session, msg)
+
+	s.MockApp.AssertNotCalled(s.T(), "FromApp")
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeReject), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagSessionRejectReason, rejectReasonIncorrectDataFormatForValue, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagRefTagID, int(tagEncodedText), s.MockApp.lastToAdmin.Body)
+}
+
+func (s *DataCompressionTestSuite) TestDataFieldMustFollowLength() {
+	raw := "8=FIX.4.2\x019=32\x0135=D\x0149=TW\x0156=ISLD\x01354=3\x0158=abc\x0110=000\x01"
+	msg := NewMessage()
+	s.NotNil(parseMessageWithDataFields(msg, bytes.NewBufferString(raw), nil, nil, s.Session.compression.dataFields()))
+}
+
+func TestParseDataFieldPairs(t *testing.T) {
+	pairs, err := parseDataFieldPairs("354:355, 95:96")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || pairs[0] != (dataFieldPair{354, 355}) || pairs[1] != (dataFieldPair{95, 96}) {
+		t.Errorf("unexpected pairs %v", pairs)
+	}
+
+	for _, value := range []string{"", "355", "x:355", "354:354", "212:213", "93:89"} {
+		if _, err := parseDataFieldPairs(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
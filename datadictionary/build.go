@@ -6,6 +6,12 @@ import (
 	"strconv"
 )
 
+// Build builds a datadictionary instance from an XMLDoc, either unmarshalled from a QuickFIX dictionary or
+// constructed in memory, see DictionaryBuilder.
+func Build(doc *XMLDoc) (*DataDictionary, error) {
+	return new(builder).build(doc)
+}
+
 type builder struct {
 	doc             *XMLDoc
 	dict            *DataDictionary
@@ -0,0 +1,92 @@
+package datadictionary
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultCache is the Cache shared by all sessions of the process.
+var DefaultCache = NewCache()
+
+// Cache shares parsed DataDictionaries between their users, keyed by path and content hash, so that a dictionary
+// referenced by many sessions is parsed once. A DataDictionary obtained from a Cache must not be modified.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]*CacheEntry
+}
+
+type cacheKey struct {
+	path string
+	hash [sha256.Size]byte
+}
+
+// CacheEntry is a DataDictionary being parsed, or parsed, by a Cache.
+type CacheEntry struct {
+	ready chan struct{}
+	dict  *DataDictionary
+	err   error
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{entries: make(map[cacheKey]*CacheEntry)}
+}
+
+// Load returns the entry for the dictionary at path, starting to parse it in the background if the file content
+// has not been seen before. Failure to read the file is reported by the returned entry.
+func (c *Cache) Load(path string) *CacheEntry {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		e := &CacheEntry{ready: make(chan struct{}), err: errors.Wrapf(err, "problem opening file: %v", path)}
+		close(e.ready)
+		return e
+	}
+
+	key := cacheKey{path: path, hash: sha256.Sum256(src)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		return e
+	}
+
+	e := &CacheEntry{ready: make(chan struct{})}
+	c.entries[key] = e
+	go func() {
+		defer close(e.ready)
+		if e.dict, e.err = ParseSrc(bytes.NewReader(src)); e.err != nil {
+			// Failures are not cached so a fixed file can be retried.
+			c.mu.Lock()
+			delete(c.entries, key)
+			c.mu.Unlock()
+		}
+	}()
+	return e
+}
+
+// Parse returns the dictionary at path, parsing it only if the file content has not been seen before.
+func (c *Cache) Parse(path string) (*DataDictionary, error) {
+	return c.Load(path).Get()
+}
+
+// Purge drops all entries. Dictionaries already handed out remain valid.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]*CacheEntry)
+}
+
+// Ready is closed once parsing has finished, successfully or not.
+func (e *CacheEntry) Ready() <-chan struct{} {
+	return e.ready
+}
+
+// Get waits for parsing to finish and returns its result.
+func (e *CacheEntry) Get() (*DataDictionary, error) {
+	<-e.ready
+	return e.dict, e.err
+}
@@ -0,0 +1,70 @@
+package datadictionary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheParseShared(t *testing.T) {
+	c := NewCache()
+
+	d1, err := c.Parse("../spec/FIX44.xml")
+	require.NoError(t, err)
+	d2, err := c.Parse("../spec/FIX44.xml")
+	require.NoError(t, err)
+	assert.Same(t, d1, d2)
+
+	d3, err := c.Parse("../spec/FIX42.xml")
+	require.NoError(t, err)
+	assert.NotSame(t, d1, d3)
+
+	c.Purge()
+	d4, err := c.Parse("../spec/FIX44.xml")
+	require.NoError(t, err)
+	assert.NotSame(t, d1, d4)
+}
+
+func TestCacheParseChangedContent(t *testing.T) {
+	src, err := os.ReadFile("../spec/FIX44.xml")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "dict.xml")
+	require.NoError(t, os.WriteFile(path, src, 0o600))
+
+	c := NewCache()
+	d1, err := c.Parse(path)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, append(src, '\n'), 0o600))
+	d2, err := c.Parse(path)
+	require.NoError(t, err)
+	assert.NotSame(t, d1, d2)
+}
+
+func TestCacheLoad(t *testing.T) {
+	c := NewCache()
+
+	e := c.Load("../spec/FIX44.xml")
+	<-e.Ready()
+	d, err := e.Get()
+	assert.NoError(t, err)
+	assert.NotNil(t, d)
+	assert.Same(t, e, c.Load("../spec/FIX44.xml"))
+}
+
+func TestCacheLoadErrors(t *testing.T) {
+	c := NewCache()
+
+	_, err := c.Load("../spec/bogus.xml").Get()
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "dict.xml")
+	require.NoError(t, os.WriteFile(path, []byte("not xml"), 0o600))
+	e := c.Load(path)
+	_, err = e.Get()
+	assert.Error(t, err)
+	assert.NotSame(t, e, c.Load(path), "failures are not cached")
+}
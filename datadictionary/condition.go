@@ -0,0 +1,437 @@
+package datadictionary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Presence of a field when the expression of a Condition holds.
+const (
+	PresenceRequired  = "required"
+	PresenceForbidden = "forbidden"
+)
+
+// Condition is a field required or forbidden when an expression on the other fields of the message holds, such as
+// the when-condition of a FIX Orchestra rule.
+//
+// Expressions support field names, string and number literals, code names of the codeset of a compared field
+// prefixed by ^, the operators == != < <= > >= in {...}, exists, && || ! and their word forms and, or, not, and
+// parentheses. A comparison with a missing field does not hold.
+type Condition struct {
+	Tag      int
+	Presence string
+	When     string
+
+	expr exprNode
+}
+
+// NewCondition returns the Condition of the field tag, compiling when against the fields of dict.
+func NewCondition(dict *DataDictionary, tag int, presence, when string) (*Condition, error) {
+	if presence != PresenceRequired && presence != PresenceForbidden {
+		return nil, fmt.Errorf("invalid presence %q", presence)
+	}
+
+	p := exprParser{dict: dict}
+	if err := p.tokenize(when); err != nil {
+		return nil, fmt.Errorf("condition %q: %w", when, err)
+	}
+	expr, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", when, err)
+	}
+
+	return &Condition{Tag: tag, Presence: presence, When: when, expr: expr}, nil
+}
+
+// Holds returns whether the expression of the Condition holds, value returning the value of a field of the message.
+func (c *Condition) Holds(value func(tag int) (string, bool)) bool {
+	return c.expr.eval(value).truth()
+}
+
+type exprValue struct {
+	present bool
+	isBool  bool
+	b       bool
+	s       string
+}
+
+func (v exprValue) truth() bool {
+	if v.isBool {
+		return v.b
+	}
+	return v.present && (v.s == "Y" || v.s == "true")
+}
+
+func boolValue(b bool) exprValue { return exprValue{present: true, isBool: true, b: b} }
+
+type exprNode interface {
+	eval(value func(tag int) (string, bool)) exprValue
+}
+
+type fieldNode struct{ field *FieldType }
+
+func (n fieldNode) eval(value func(tag int) (string, bool)) exprValue {
+	s, ok := value(n.field.Tag())
+	return exprValue{present: ok, s: s}
+}
+
+type literalNode struct{ s string }
+
+func (n literalNode) eval(func(int) (string, bool)) exprValue {
+	return exprValue{present: true, s: n.s}
+}
+
+// codeNode is a code name, resolved to a literalNode by the comparison with a field.
+type codeNode struct{ name string }
+
+func (n codeNode) eval(func(int) (string, bool)) exprValue { return exprValue{} }
+
+type existsNode struct{ field *FieldType }
+
+func (n existsNode) eval(value func(tag int) (string, bool)) exprValue {
+	_, ok := value(n.field.Tag())
+	return boolValue(ok)
+}
+
+type notNode struct{ x exprNode }
+
+func (n notNode) eval(value func(tag int) (string, bool)) exprValue {
+	return boolValue(!n.x.eval(value).truth())
+}
+
+type logicalNode struct {
+	and  bool
+	l, r exprNode
+}
+
+func (n logicalNode) eval(value func(tag int) (string, bool)) exprValue {
+	if n.and {
+		return boolValue(n.l.eval(value).truth() && n.r.eval(value).truth())
+	}
+	return boolValue(n.l.eval(value).truth() || n.r.eval(value).truth())
+}
+
+type compareNode struct {
+	op   string
+	l, r exprNode
+}
+
+func (n compareNode) eval(value func(tag int) (string, bool)) exprValue {
+	l, r := n.l.eval(value), n.r.eval(value)
+	if !l.present || !r.present || l.isBool || r.isBool {
+		return boolValue(false)
+	}
+	return boolValue(compare(n.op, l.s, r.s))
+}
+
+type inNode struct {
+	x    exprNode
+	list []exprNode
+}
+
+func (n inNode) eval(value func(tag int) (string, bool)) exprValue {
+	x := n.x.eval(value)
+	if !x.present {
+		return boolValue(false)
+	}
+	for _, item := range n.list {
+		if v := item.eval(value); v.present && compare("==", x.s, v.s) {
+			return boolValue(true)
+		}
+	}
+	return boolValue(false)
+}
+
+// compare compares l and r as numbers if both are numbers, else as strings.
+func compare(op, l, r string) bool {
+	c := strings.Compare(l, r)
+	if lf, err := strconv.ParseFloat(l, 64); err == nil {
+		if rf, err := strconv.ParseFloat(r, 64); err == nil {
+			switch {
+			case lf < rf:
+				c = -1
+			case lf > rf:
+				c = 1
+			default:
+				c = 0
+			}
+		}
+	}
+
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenCode
+	tokenString
+	tokenNumber
+	tokenOp
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+}
+
+type exprParser struct {
+	dict   *DataDictionary
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) tokenize(s string) error {
+	isIdent := func(r byte) bool {
+		return r == '_' || r == '.' || unicode.IsLetter(rune(r)) || unicode.IsDigit(rune(r))
+	}
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return fmt.Errorf("unterminated string")
+			}
+			p.tokens = append(p.tokens, exprToken{tokenString, s[i+1 : i+1+end]})
+			i += end + 2
+
+		case c == '^':
+			j := i + 1
+			for j < len(s) && isIdent(s[j]) {
+				j++
+			}
+			p.tokens = append(p.tokens, exprToken{tokenCode, s[i+1 : j]})
+			i = j
+
+		case c == '-' || unicode.IsDigit(rune(c)):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			p.tokens = append(p.tokens, exprToken{tokenNumber, s[i:j]})
+			i = j
+
+		case isIdent(c):
+			j := i
+			for j < len(s) && isIdent(s[j]) {
+				j++
+			}
+			p.tokens = append(p.tokens, exprToken{tokenIdent, s[i:j]})
+			i = j
+
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "{", "}", ","} {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("unexpected %q", c)
+			}
+			p.tokens = append(p.tokens, exprToken{tokenOp, op})
+			i += len(op)
+		}
+	}
+	return nil
+}
+
+func (p *exprParser) peek() (exprToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return exprToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// accept consumes the next token if it is one of the operators or keywords.
+func (p *exprParser) accept(texts ...string) (string, bool) {
+	t, ok := p.peek()
+	if !ok || (t.kind != tokenOp && t.kind != tokenIdent) {
+		return "", false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		return fmt.Errorf("expected %q", text)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	l, err := p.parseAnd()
+	for err == nil {
+		if _, ok := p.accept("||", "or"); !ok {
+			break
+		}
+		var r exprNode
+		if r, err = p.parseAnd(); err == nil {
+			l = logicalNode{l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	l, err := p.parseNot()
+	for err == nil {
+		if _, ok := p.accept("&&", "and"); !ok {
+			break
+		}
+		var r exprNode
+		if r, err = p.parseNot(); err == nil {
+			l = logicalNode{and: true, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		x, err := p.parseNot()
+		return notNode{x}, err
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("in"); ok {
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+		n := inNode{x: l}
+		for {
+			item, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			if item, err = p.resolveCode(l, item); err != nil {
+				return nil, err
+			}
+			n.list = append(n.list, item)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		return n, p.expect("}")
+	}
+
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return l, nil
+	}
+	r, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if r, err = p.resolveCode(l, r); err != nil {
+		return nil, err
+	}
+	if l, err = p.resolveCode(r, l); err != nil {
+		return nil, err
+	}
+	return compareNode{op: op, l: l, r: r}, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end")
+	}
+
+	switch t.kind {
+	case tokenString, tokenNumber:
+		p.pos++
+		return literalNode{t.text}, nil
+
+	case tokenCode:
+		p.pos++
+		return codeNode{t.text}, nil
+
+	case tokenIdent:
+		if _, ok := p.accept("exists"); ok {
+			field, err := p.parseField()
+			return existsNode{field}, err
+		}
+		field, err := p.parseField()
+		return fieldNode{field}, err
+	}
+
+	if _, ok := p.accept("("); ok {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *exprParser) parseField() (*FieldType, error) {
+	t, ok := p.peek()
+	if !ok || t.kind != tokenIdent {
+		return nil, fmt.Errorf("expected field name")
+	}
+	p.pos++
+
+	field, ok := p.dict.FieldTypeByName[t.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %v", t.text)
+	}
+	return field, nil
+}
+
+// resolveCode resolves a code name compared with a field to the value of the code.
+func (p *exprParser) resolveCode(field, code exprNode) (exprNode, error) {
+	c, ok := code.(codeNode)
+	if !ok {
+		return code, nil
+	}
+	f, ok := field.(fieldNode)
+	if !ok {
+		return nil, fmt.Errorf("code ^%v not compared with a field", c.name)
+	}
+
+	for value, enum := range f.field.Enums {
+		if strings.EqualFold(enum.Description, c.name) {
+			return literalNode{value}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown code ^%v of field %v", c.name, f.field.Name())
+}
@@ -2,8 +2,10 @@
 package datadictionary
 
 import (
+	"bytes"
 	"encoding/xml"
 	"io"
+	"io/fs"
 	"os"
 
 	"github.com/pkg/errors"
@@ -246,6 +248,10 @@ type MessageDef struct {
 
 	RequiredTags TagSet
 	Tags         TagSet
+
+	// Conditions are the conditionally required or forbidden fields of the body, defined by the rules of a
+	// FIX Orchestra repository.
+	Conditions []*Condition
 }
 
 // RequiredParts returns those parts that are required for this Message.
@@ -311,10 +317,19 @@ func Parse(path string) (*DataDictionary, error) {
 	return ParseSrc(xmlFile)
 }
 
-// ParseSrc loads and build a datadictionary instance from an xml source.
+// ParseSrc loads and build a datadictionary instance from an xml source, either a QuickFIX dictionary or a FIX
+// Orchestra repository parsed with ParseOrchestraSrc for BaseScenario.
 func ParseSrc(xmlSrc io.Reader) (*DataDictionary, error) {
+	src, err := io.ReadAll(xmlSrc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading XML file")
+	}
+	if isOrchestra(src) {
+		return ParseOrchestraSrc(bytes.NewReader(src), BaseScenario)
+	}
+
 	doc := new(XMLDoc)
-	decoder := xml.NewDecoder(xmlSrc)
+	decoder := xml.NewDecoder(bytes.NewReader(src))
 	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
 		return input, nil
 	}
@@ -323,11 +338,16 @@ func ParseSrc(xmlSrc io.Reader) (*DataDictionary, error) {
 		return nil, errors.Wrapf(err, "problem parsing XML file")
 	}
 
-	b := new(builder)
-	dict, err := b.build(doc)
+	return Build(doc)
+}
+
+// ParseFS loads and build a datadictionary instance from the xml file path of fsys, such as an embed.FS.
+func ParseFS(fsys fs.FS, path string) (*DataDictionary, error) {
+	xmlFile, err := fsys.Open(path)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "problem opening file: %v", path)
 	}
+	defer xmlFile.Close()
 
-	return dict, nil
+	return ParseSrc(xmlFile)
 }
@@ -0,0 +1,89 @@
+package datadictionary
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// DictionaryBuilder constructs a DataDictionary in memory, for dictionaries defined in Go code rather than in an
+// XML file. Members refer to fields and components by name, as in a QuickFIX dictionary, and are resolved by Build.
+type DictionaryBuilder struct {
+	doc XMLDoc
+}
+
+// NewDictionaryBuilder returns a DictionaryBuilder of the FIX version fixType.major.minor, fixType being FIX or FIXT.
+func NewDictionaryBuilder(fixType string, major, minor, servicePack int) *DictionaryBuilder {
+	return &DictionaryBuilder{doc: XMLDoc{
+		Type:        fixType,
+		Major:       strconv.Itoa(major),
+		Minor:       strconv.Itoa(minor),
+		ServicePack: servicePack,
+		Header:      &XMLComponent{},
+		Trailer:     &XMLComponent{},
+	}}
+}
+
+// Field defines the field tag with its name, FIX type and enum values, if any.
+func (b *DictionaryBuilder) Field(tag int, name, fixType string, enums ...Enum) *DictionaryBuilder {
+	field := &XMLField{Number: tag, Name: name, Type: fixType}
+	for _, enum := range enums {
+		field.Values = append(field.Values, &XMLValue{Enum: enum.Value, Description: enum.Description})
+	}
+	b.doc.Fields = append(b.doc.Fields, field)
+	return b
+}
+
+// Header adds members to the header.
+func (b *DictionaryBuilder) Header(members ...*XMLComponentMember) *DictionaryBuilder {
+	b.doc.Header.Members = append(b.doc.Header.Members, members...)
+	return b
+}
+
+// Trailer adds members to the trailer.
+func (b *DictionaryBuilder) Trailer(members ...*XMLComponentMember) *DictionaryBuilder {
+	b.doc.Trailer.Members = append(b.doc.Trailer.Members, members...)
+	return b
+}
+
+// Component defines the component name with its members.
+func (b *DictionaryBuilder) Component(name string, members ...*XMLComponentMember) *DictionaryBuilder {
+	b.doc.Components = append(b.doc.Components, &XMLComponent{Name: name, Members: members})
+	return b
+}
+
+// Message defines the message name of MsgType msgType with its members.
+func (b *DictionaryBuilder) Message(name, msgType string, members ...*XMLComponentMember) *DictionaryBuilder {
+	b.doc.Messages = append(b.doc.Messages, &XMLComponent{Name: name, MsgType: msgType, Members: members})
+	return b
+}
+
+// Build returns the DataDictionary defined so far. Returns an error if a member refers to an undefined field or
+// component.
+func (b *DictionaryBuilder) Build() (*DataDictionary, error) {
+	return Build(&b.doc)
+}
+
+// FieldMember returns a member referring to the field name.
+func FieldMember(name string, required bool) *XMLComponentMember {
+	return newMember("field", name, required)
+}
+
+// ComponentMember returns a member referring to the component name.
+func ComponentMember(name string, required bool) *XMLComponentMember {
+	return newMember("component", name, required)
+}
+
+// GroupMember returns a repeating group member, counted by the field name.
+func GroupMember(name string, required bool, members ...*XMLComponentMember) *XMLComponentMember {
+	member := newMember("group", name, required)
+	member.Members = members
+	return member
+}
+
+func newMember(kind, name string, required bool) *XMLComponentMember {
+	member := &XMLComponentMember{XMLName: xml.Name{Local: kind}, Name: name, Required: "N"}
+	if required {
+		member.Required = "Y"
+	}
+	return member
+}
@@ -0,0 +1,56 @@
+package datadictionary
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDictionaryBuilder(t *testing.T) {
+	dict, err := NewDictionaryBuilder("FIX", 4, 4, 0).
+		Field(8, "BeginString", "STRING").
+		Field(35, "MsgType", "STRING", Enum{Value: "D", Description: "ORDER_SINGLE"}).
+		Field(10, "CheckSum", "STRING").
+		Field(11, "ClOrdID", "STRING").
+		Field(453, "NoPartyIDs", "NUMINGROUP").
+		Field(448, "PartyID", "STRING").
+		Field(5001, "VenueTag", "STRING").
+		Header(FieldMember("BeginString", true), FieldMember("MsgType", true)).
+		Trailer(FieldMember("CheckSum", true)).
+		Component("Parties", GroupMember("NoPartyIDs", false, FieldMember("PartyID", true))).
+		Message("NewOrderSingle", "D", FieldMember("ClOrdID", true), ComponentMember("Parties", false),
+			FieldMember("VenueTag", false)).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, dict.Major)
+	assert.Equal(t, "ORDER_SINGLE", dict.FieldTypeByTag[35].Enums["D"].Description)
+	assert.Contains(t, dict.Header.RequiredTags, 35)
+	assert.Contains(t, dict.Trailer.RequiredTags, 10)
+
+	order := dict.Messages["D"]
+	require.NotNil(t, order)
+	assert.Equal(t, "NewOrderSingle", order.Name)
+	assert.Contains(t, order.RequiredTags, 11)
+	assert.NotContains(t, order.RequiredTags, 5001)
+	assert.True(t, order.Fields[453].IsGroup())
+	assert.Contains(t, order.Tags, 448)
+}
+
+func TestDictionaryBuilderUnknownField(t *testing.T) {
+	_, err := NewDictionaryBuilder("FIX", 4, 4, 0).
+		Message("NewOrderSingle", "D", FieldMember("ClOrdID", true)).
+		Build()
+	assert.EqualError(t, err, "unknown field ClOrdID")
+}
+
+func TestParseFS(t *testing.T) {
+	dict, err := ParseFS(os.DirFS("../spec"), "FIX44.xml")
+	require.NoError(t, err)
+	assert.Equal(t, 4, dict.Minor)
+
+	_, err = ParseFS(os.DirFS("../spec"), "bogus.xml")
+	assert.Error(t, err)
+}
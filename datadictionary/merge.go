@@ -0,0 +1,267 @@
+package datadictionary
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Conflict is a definition of a custom DataDictionary given up by Merge because it contradicts the definition of
+// the base DataDictionary, which is kept.
+type Conflict struct {
+	// Path locates the definition, e.g. "field 5001", "enum 40=Z" or "message D/group 453".
+	Path   string
+	Reason string
+}
+
+func (c Conflict) String() string {
+	return c.Path + ": " + c.Reason
+}
+
+// Merge returns the DataDictionary of base overlaid with the definitions of custom, such as the custom fields,
+// enum values and repeating group members of a venue on top of a standard FIX specification. Neither base nor
+// custom is modified.
+//
+// Fields, components and messages missing from base are added. The enum values of a field are merged. The parts
+// of a component, message, header, trailer or repeating group defined by both are those of base followed by the
+// parts of custom not in base, so that custom only needs to list the members it adds. As custom is parsed on its
+// own, it must still define the fields and components its members refer to.
+//
+// Definitions of custom contradicting those of base, such as a tag with another name or type, a differing enum
+// description or a field declared as a repeating group by only one of them, are dropped and reported as conflicts.
+func Merge(base, custom *DataDictionary) (*DataDictionary, []Conflict) {
+	m := merger{
+		base:   base,
+		custom: custom,
+		dict: &DataDictionary{
+			FIXType:         base.FIXType,
+			Major:           base.Major,
+			Minor:           base.Minor,
+			ServicePack:     base.ServicePack,
+			FieldTypeByTag:  make(map[int]*FieldType),
+			FieldTypeByName: make(map[string]*FieldType),
+			Messages:        make(map[string]*MessageDef),
+			ComponentTypes:  make(map[string]*ComponentType),
+		},
+	}
+
+	if custom.FIXType != base.FIXType || custom.Major != base.Major || custom.Minor != base.Minor ||
+		custom.ServicePack != base.ServicePack {
+		m.conflict("version", "%v.%v.%v SP%v merged into %v.%v.%v SP%v", custom.FIXType, custom.Major,
+			custom.Minor, custom.ServicePack, base.FIXType, base.Major, base.Minor, base.ServicePack)
+	}
+
+	m.mergeFieldTypes()
+
+	for name := range base.ComponentTypes {
+		m.componentType(name)
+	}
+	for name := range custom.ComponentTypes {
+		m.componentType(name)
+	}
+
+	for msgType, msg := range base.Messages {
+		m.dict.Messages[msgType] = m.mergeMessageDef("message "+msgType, msg, custom.Messages[msgType])
+	}
+	for msgType, msg := range custom.Messages {
+		if _, ok := base.Messages[msgType]; !ok {
+			m.dict.Messages[msgType] = m.mergeMessageDef("message "+msgType, nil, msg)
+		}
+	}
+
+	m.dict.Header = m.mergeMessageDef("header", base.Header, custom.Header)
+	m.dict.Trailer = m.mergeMessageDef("trailer", base.Trailer, custom.Trailer)
+
+	sort.SliceStable(m.conflicts, func(i, j int) bool { return m.conflicts[i].Path < m.conflicts[j].Path })
+	return m.dict, m.conflicts
+}
+
+type merger struct {
+	base, custom *DataDictionary
+	dict         *DataDictionary
+	conflicts    []Conflict
+}
+
+func (m *merger) conflict(path, format string, args ...interface{}) {
+	m.conflicts = append(m.conflicts, Conflict{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+// mergeFieldTypes copies the field types of base, adding the enum values of custom, then the field types of
+// custom missing from base.
+func (m *merger) mergeFieldTypes() {
+	for tag, baseType := range m.base.FieldTypeByTag {
+		fieldType := copyFieldType(baseType)
+		m.dict.FieldTypeByTag[tag] = fieldType
+		m.dict.FieldTypeByName[fieldType.Name()] = fieldType
+	}
+
+	for tag, customType := range m.custom.FieldTypeByTag {
+		path := fmt.Sprintf("field %v", tag)
+		fieldType, ok := m.dict.FieldTypeByTag[tag]
+		if !ok {
+			if other, ok := m.dict.FieldTypeByName[customType.Name()]; ok {
+				m.conflict(path, "name %v is the name of field %v", customType.Name(), other.Tag())
+				continue
+			}
+			fieldType = copyFieldType(customType)
+			m.dict.FieldTypeByTag[tag] = fieldType
+			m.dict.FieldTypeByName[fieldType.Name()] = fieldType
+			continue
+		}
+
+		if customType.Name() != fieldType.Name() {
+			m.conflict(path, "name %v differs from %v", customType.Name(), fieldType.Name())
+			continue
+		}
+		if customType.Type != fieldType.Type {
+			m.conflict(path, "type %v differs from %v", customType.Type, fieldType.Type)
+			continue
+		}
+
+		for value, enum := range customType.Enums {
+			if existing, ok := fieldType.Enums[value]; ok {
+				if existing.Description != enum.Description {
+					m.conflict(fmt.Sprintf("enum %v=%v", tag, value), "description %v differs from %v",
+						enum.Description, existing.Description)
+				}
+				continue
+			}
+			if fieldType.Enums == nil {
+				fieldType.Enums = make(map[string]Enum)
+			}
+			fieldType.Enums[value] = enum
+		}
+	}
+}
+
+func copyFieldType(fieldType *FieldType) *FieldType {
+	c := NewFieldType(fieldType.Name(), fieldType.Tag(), fieldType.Type)
+	if fieldType.Enums != nil {
+		c.Enums = make(map[string]Enum, len(fieldType.Enums))
+		for value, enum := range fieldType.Enums {
+			c.Enums[value] = enum
+		}
+	}
+	return c
+}
+
+// componentType returns the merged component type name, merging it on first use.
+func (m *merger) componentType(name string) *ComponentType {
+	if comp, ok := m.dict.ComponentTypes[name]; ok {
+		return comp
+	}
+
+	var baseParts, customParts []MessagePart
+	if comp, ok := m.base.ComponentTypes[name]; ok {
+		baseParts = comp.Parts()
+	}
+	if comp, ok := m.custom.ComponentTypes[name]; ok {
+		customParts = comp.Parts()
+	}
+
+	comp := NewComponentType(name, m.mergeParts("component "+name, baseParts, customParts))
+	m.dict.ComponentTypes[name] = comp
+	return comp
+}
+
+// mergeMessageDef merges the message definitions of base and custom, either of which may be nil.
+func (m *merger) mergeMessageDef(path string, base, custom *MessageDef) *MessageDef {
+	switch {
+	case base == nil && custom == nil:
+		return nil
+	case base == nil:
+		msg := NewMessageDef(custom.Name, custom.MsgType, m.mergeParts(path, nil, custom.Parts))
+		msg.Conditions = custom.Conditions
+		return msg
+	case custom == nil:
+		msg := NewMessageDef(base.Name, base.MsgType, m.mergeParts(path, base.Parts, nil))
+		msg.Conditions = base.Conditions
+		return msg
+	}
+
+	if custom.Name != base.Name {
+		m.conflict(path, "name %v differs from %v", custom.Name, base.Name)
+	}
+	msg := NewMessageDef(base.Name, base.MsgType, m.mergeParts(path, base.Parts, custom.Parts))
+	msg.Conditions = append(base.Conditions[:len(base.Conditions):len(base.Conditions)], custom.Conditions...)
+	return msg
+}
+
+// mergeParts returns the parts of base followed by the parts of custom not in base, rebuilt with the merged field
+// and component types.
+func (m *merger) mergeParts(path string, base, custom []MessagePart) []MessagePart {
+	customFields := make(map[int]*FieldDef)
+	for _, part := range custom {
+		if p, ok := part.(*FieldDef); ok {
+			customFields[p.Tag()] = p
+		}
+	}
+
+	var parts []MessagePart
+	tags := make(map[int]bool)
+	components := make(map[string]bool)
+	add := func(part MessagePart) {
+		switch p := part.(type) {
+		case *FieldDef:
+			tags[p.Tag()] = true
+		case Component:
+			components[p.Name()] = true
+			for _, f := range p.Fields() {
+				tags[f.Tag()] = true
+			}
+		}
+		parts = append(parts, part)
+	}
+
+	for _, part := range base {
+		switch p := part.(type) {
+		case *FieldDef:
+			customField := customFields[p.Tag()]
+			if customField != nil && customField.IsGroup() != p.IsGroup() {
+				m.conflict(fmt.Sprintf("%v/field %v", path, p.Tag()), "repeating group in only one dictionary")
+				customField = nil
+			}
+			add(m.mergeFieldDef(path, p, customField))
+		case Component:
+			add(Component{ComponentType: m.componentType(p.Name()), required: p.required})
+		}
+	}
+
+	for _, part := range custom {
+		switch p := part.(type) {
+		case *FieldDef:
+			// Fields dropped by mergeFieldTypes have no merged type.
+			if !tags[p.Tag()] && m.dict.FieldTypeByTag[p.Tag()] != nil {
+				add(m.mergeFieldDef(path, nil, p))
+			}
+		case Component:
+			if !components[p.Name()] {
+				add(Component{ComponentType: m.componentType(p.Name()), required: p.required})
+			}
+		}
+	}
+
+	return parts
+}
+
+// mergeFieldDef merges the field definitions of base and custom, either of which may be nil.
+func (m *merger) mergeFieldDef(path string, base, custom *FieldDef) *FieldDef {
+	def := base
+	if def == nil {
+		def = custom
+	}
+	fieldType := m.dict.FieldTypeByTag[def.Tag()]
+
+	if !def.IsGroup() {
+		return NewFieldDef(fieldType, def.required)
+	}
+
+	var baseParts, customParts []MessagePart
+	if base != nil {
+		baseParts = base.Parts
+	}
+	if custom != nil {
+		customParts = custom.Parts
+	}
+	groupPath := fmt.Sprintf("%v/group %v", path, def.Tag())
+	return NewGroupFieldDef(fieldType, def.required, m.mergeParts(groupPath, baseParts, customParts))
+}
@@ -0,0 +1,107 @@
+package datadictionary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const customDictionary = `<fix type="FIX" major="4" minor="4" servicepack="0">
+  <header/>
+  <trailer/>
+  <messages>
+    <message name="NewOrderSingle" msgtype="D" msgcat="app">
+      <field name="VenueTag" required="N"/>
+      <component name="Parties" required="N"/>
+    </message>
+    <message name="VenueStatus" msgtype="U1" msgcat="app">
+      <field name="VenueTag" required="Y"/>
+    </message>
+  </messages>
+  <components>
+    <component name="Parties">
+      <group name="NoPartyIDs" required="N">
+        <field name="PartyID" required="N"/>
+        <field name="PartyVenueCode" required="N"/>
+      </group>
+    </component>
+  </components>
+  <fields>
+    <field number="5001" name="VenueTag" type="STRING"/>
+    <field number="5002" name="PartyVenueCode" type="STRING"/>
+    <field number="5003" name="Side" type="STRING"/>
+    <field number="40" name="OrdType" type="CHAR">
+      <value enum="1" description="MKT"/>
+      <value enum="Z" description="VENUE_PEG"/>
+    </field>
+    <field number="54" name="Side" type="INT"/>
+    <field number="453" name="NoPartyIDs" type="NUMINGROUP"/>
+    <field number="448" name="PartyID" type="STRING"/>
+  </fields>
+</fix>`
+
+func TestMerge(t *testing.T) {
+	base, err := Parse("../spec/FIX44.xml")
+	require.NoError(t, err)
+	custom, err := ParseSrc(strings.NewReader(customDictionary))
+	require.NoError(t, err)
+
+	merged, conflicts := Merge(base, custom)
+
+	assert.Equal(t, "STRING", merged.FieldTypeByTag[5001].Type)
+	assert.Same(t, merged.FieldTypeByTag[5001], merged.FieldTypeByName["VenueTag"])
+
+	ordType := merged.FieldTypeByTag[40]
+	assert.Equal(t, "VENUE_PEG", ordType.Enums["Z"].Description)
+	assert.Equal(t, "MARKET", ordType.Enums["1"].Description)
+	assert.NotContains(t, base.FieldTypeByTag[40].Enums, "Z", "base is not modified")
+
+	assert.Equal(t, "CHAR", merged.FieldTypeByTag[54].Type)
+	assert.NotContains(t, merged.FieldTypeByTag, 5003)
+
+	order := merged.Messages["D"]
+	require.NotNil(t, order)
+	assert.Contains(t, order.Tags, 5001)
+	assert.Contains(t, order.Tags, 5002)
+	assert.Contains(t, order.RequiredTags, 11)
+	assert.Equal(t, len(base.Messages["D"].Parts)+1, len(order.Parts))
+	assert.Equal(t, "VenueTag", order.Parts[len(order.Parts)-1].Name())
+	assert.NotContains(t, base.Messages["D"].Tags, 5002)
+
+	parties := merged.ComponentTypes["Parties"]
+	require.Len(t, parties.Fields(), 1)
+	group := parties.Fields()[0]
+	require.Len(t, group.Fields, len(base.ComponentTypes["Parties"].Fields()[0].Fields)+1)
+	assert.Equal(t, 5002, group.Fields[len(group.Fields)-1].Tag())
+	assert.Contains(t, merged.Messages["8"].Tags, 5002, "components are shared by the messages of base")
+
+	status := merged.Messages["U1"]
+	require.NotNil(t, status)
+	assert.Contains(t, status.RequiredTags, 5001)
+
+	assert.Equal(t, base.Header.Tags, merged.Header.Tags)
+	assert.Equal(t, base.Trailer.Tags, merged.Trailer.Tags)
+
+	var reported []string
+	for _, c := range conflicts {
+		reported = append(reported, c.String())
+	}
+	assert.Equal(t, []string{
+		"enum 40=1: description MKT differs from MARKET",
+		"field 5003: name Side is the name of field 54",
+		"field 54: type INT differs from CHAR",
+	}, reported)
+}
+
+func TestMergeVersionConflict(t *testing.T) {
+	base, err := Parse("../spec/FIX44.xml")
+	require.NoError(t, err)
+	custom, err := Parse("../spec/FIX42.xml")
+	require.NoError(t, err)
+
+	merged, conflicts := Merge(base, custom)
+	assert.Equal(t, 4, merged.Minor)
+	assert.Contains(t, conflicts, Conflict{Path: "version", Reason: "FIX.4.2 SP0 merged into FIX.4.4 SP0"})
+}
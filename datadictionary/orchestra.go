@@ -0,0 +1,429 @@
+package datadictionary
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BaseScenario is the default scenario of the elements of a FIX Orchestra repository.
+const BaseScenario = "base"
+
+// orchestraRepository is the unmarshalled root of a FIX Orchestra repository.
+type orchestraRepository struct {
+	Name       string                `xml:"name,attr"`
+	Version    string                `xml:"version,attr"`
+	CodeSets   []*orchestraCodeSet   `xml:"codeSets>codeSet"`
+	Fields     []*orchestraField     `xml:"fields>field"`
+	Components []*orchestraComponent `xml:"components>component"`
+	Groups     []*orchestraComponent `xml:"groups>group"`
+	Messages   []*orchestraMessage   `xml:"messages>message"`
+}
+
+type orchestraCodeSet struct {
+	ID       int              `xml:"id,attr"`
+	Name     string           `xml:"name,attr"`
+	Type     string           `xml:"type,attr"`
+	Scenario string           `xml:"scenario,attr"`
+	Codes    []*orchestraCode `xml:"code"`
+}
+
+type orchestraCode struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type orchestraField struct {
+	ID       int    `xml:"id,attr"`
+	Name     string `xml:"name,attr"`
+	Type     string `xml:"type,attr"`
+	Scenario string `xml:"scenario,attr"`
+}
+
+// orchestraComponent is a component, or a group with its NumInGroup field.
+type orchestraComponent struct {
+	ID         int             `xml:"id,attr"`
+	Name       string          `xml:"name,attr"`
+	Scenario   string          `xml:"scenario,attr"`
+	NumInGroup *orchestraRef   `xml:"numInGroup"`
+	Refs       []*orchestraRef `xml:",any"`
+}
+
+type orchestraMessage struct {
+	Name      string `xml:"name,attr"`
+	MsgType   string `xml:"msgType,attr"`
+	Category  string `xml:"category,attr"`
+	Scenario  string `xml:"scenario,attr"`
+	Structure struct {
+		Refs []*orchestraRef `xml:",any"`
+	} `xml:"structure"`
+}
+
+// orchestraRef is a fieldRef, componentRef or groupRef.
+type orchestraRef struct {
+	XMLName  xml.Name
+	ID       int              `xml:"id,attr"`
+	Scenario string           `xml:"scenario,attr"`
+	Presence string           `xml:"presence,attr"`
+	Rules    []*orchestraRule `xml:"rule"`
+}
+
+type orchestraRule struct {
+	Name     string `xml:"name,attr"`
+	Presence string `xml:"presence,attr"`
+	When     string `xml:"when"`
+}
+
+type scenarioKey struct {
+	id       int
+	scenario string
+}
+
+func scenarioOf(scenario string) string {
+	if scenario == "" {
+		return BaseScenario
+	}
+	return scenario
+}
+
+// orchestraConverter converts a FIX Orchestra repository into an XMLDoc, collecting the conditional rules of the
+// fields of the messages.
+type orchestraConverter struct {
+	repository *orchestraRepository
+	scenario   string
+
+	codeSets   map[string]*orchestraCodeSet
+	fields     map[scenarioKey]*orchestraField
+	components map[scenarioKey]*orchestraComponent
+	groups     map[scenarioKey]*orchestraComponent
+
+	doc           *XMLDoc
+	xmlComponents map[string]bool
+	conditions    map[string][]orchestraCondition
+}
+
+type orchestraCondition struct {
+	tag      int
+	presence string
+	when     string
+}
+
+// ParseOrchestra loads and builds a datadictionary instance from a FIX Orchestra repository file, using the
+// messages, components and groups of scenario, or those of BaseScenario if scenario is empty or not defined.
+func ParseOrchestra(path, scenario string) (*DataDictionary, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening file: %v", path)
+	}
+	defer src.Close()
+
+	return ParseOrchestraSrc(src, scenario)
+}
+
+// ParseOrchestraSrc loads and builds a datadictionary instance from a FIX Orchestra repository source, see
+// ParseOrchestra.
+func ParseOrchestraSrc(src io.Reader, scenario string) (*DataDictionary, error) {
+	repository := new(orchestraRepository)
+	decoder := xml.NewDecoder(src)
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	if err := decoder.Decode(repository); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing XML file")
+	}
+
+	c := orchestraConverter{repository: repository, scenario: scenarioOf(scenario)}
+	doc, err := c.convert()
+	if err != nil {
+		return nil, err
+	}
+
+	b := new(builder)
+	dict, err := b.build(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range doc.Messages {
+		for _, cond := range c.conditions[m.Name] {
+			condition, err := NewCondition(dict, cond.tag, cond.presence, cond.when)
+			if err != nil {
+				return nil, errors.Wrapf(err, "message %v", m.Name)
+			}
+			msgDef := dict.Messages[m.MsgType]
+			msgDef.Conditions = append(msgDef.Conditions, condition)
+		}
+	}
+
+	return dict, nil
+}
+
+// isOrchestra returns whether src is a FIX Orchestra repository, by the name of its root element.
+func isOrchestra(src []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(src))
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "repository"
+		}
+	}
+}
+
+// parseVersion returns the type, major, minor and service pack of a version such as FIX.4.4, FIX.5.0SP2 or FIXT.1.1.
+func parseVersion(version string) (fixType, major, minor string, servicePack int, err error) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) != 3 {
+		return "", "", "", 0, fmt.Errorf("invalid repository version %q", version)
+	}
+
+	fixType, major, minor = parts[0], parts[1], parts[2]
+	if i := strings.Index(minor, "SP"); i >= 0 {
+		if servicePack, err = strconv.Atoi(minor[i+2:]); err != nil {
+			return "", "", "", 0, fmt.Errorf("invalid repository version %q", version)
+		}
+		minor = minor[:i]
+	}
+	return
+}
+
+func (c *orchestraConverter) convert() (*XMLDoc, error) {
+	version := c.repository.Version
+	if version == "" {
+		version = c.repository.Name
+	}
+
+	c.doc = new(XMLDoc)
+	var err error
+	if c.doc.Type, c.doc.Major, c.doc.Minor, c.doc.ServicePack, err = parseVersion(version); err != nil {
+		return nil, err
+	}
+
+	c.codeSets = make(map[string]*orchestraCodeSet)
+	for _, codeSet := range c.repository.CodeSets {
+		existing, scenario := c.codeSets[codeSet.Name], scenarioOf(codeSet.Scenario)
+		if existing == nil || scenario == c.scenario || (scenario == BaseScenario && scenarioOf(existing.Scenario) != c.scenario) {
+			c.codeSets[codeSet.Name] = codeSet
+		}
+	}
+
+	c.fields = make(map[scenarioKey]*orchestraField)
+	for _, field := range c.repository.Fields {
+		c.fields[scenarioKey{field.ID, scenarioOf(field.Scenario)}] = field
+	}
+	c.components = make(map[scenarioKey]*orchestraComponent)
+	for _, comp := range c.repository.Components {
+		c.components[scenarioKey{comp.ID, scenarioOf(comp.Scenario)}] = comp
+	}
+	c.groups = make(map[scenarioKey]*orchestraComponent)
+	for _, group := range c.repository.Groups {
+		c.groups[scenarioKey{group.ID, scenarioOf(group.Scenario)}] = group
+	}
+
+	for _, field := range c.repository.Fields {
+		if scenarioOf(field.Scenario) != BaseScenario {
+			continue
+		}
+		c.doc.Fields = append(c.doc.Fields, c.convertField(field))
+	}
+
+	c.xmlComponents = make(map[string]bool)
+	c.conditions = make(map[string][]orchestraCondition)
+
+	// Each message type is defined by its message of the scenario, or else of BaseScenario.
+	messages := make(map[string]*orchestraMessage)
+	var msgTypes []string
+	for _, m := range c.repository.Messages {
+		scenario := scenarioOf(m.Scenario)
+		if scenario != c.scenario && scenario != BaseScenario {
+			continue
+		}
+		if _, seen := messages[m.MsgType]; !seen {
+			msgTypes = append(msgTypes, m.MsgType)
+		} else if scenario != c.scenario {
+			continue
+		}
+		messages[m.MsgType] = m
+	}
+
+	for _, msgType := range msgTypes {
+		m := messages[msgType]
+		msgCat := "app"
+		if m.Category == "Session" {
+			msgCat = "admin"
+		}
+		xmlMessage := &XMLComponent{Name: m.Name, MsgType: m.MsgType, MsgCat: msgCat}
+		if xmlMessage.Members, err = c.convertRefs(m.Name, m.Structure.Refs, true); err != nil {
+			return nil, err
+		}
+		c.doc.Messages = append(c.doc.Messages, xmlMessage)
+	}
+
+	return c.doc, nil
+}
+
+func (c *orchestraConverter) convertField(field *orchestraField) *XMLField {
+	xmlField := &XMLField{Number: field.ID, Name: field.Name, Type: strings.ToUpper(field.Type)}
+	if codeSet, ok := c.codeSets[field.Type]; ok {
+		xmlField.Type = strings.ToUpper(codeSet.Type)
+		for _, code := range codeSet.Codes {
+			xmlField.Values = append(xmlField.Values, &XMLValue{Enum: code.Value, Description: code.Name})
+		}
+	}
+	return xmlField
+}
+
+func (c *orchestraConverter) lookupField(ref *orchestraRef) (*orchestraField, error) {
+	if field, ok := c.fields[scenarioKey{ref.ID, BaseScenario}]; ok {
+		return field, nil
+	}
+	return nil, fmt.Errorf("unknown field %d", ref.ID)
+}
+
+// lookup returns the component or group of the scenario of ref, falling back to the converter scenario then
+// BaseScenario.
+func (c *orchestraConverter) lookup(index map[scenarioKey]*orchestraComponent, ref *orchestraRef) (*orchestraComponent, error) {
+	for _, scenario := range []string{scenarioOf(ref.Scenario), c.scenario, BaseScenario} {
+		if comp, ok := index[scenarioKey{ref.ID, scenario}]; ok {
+			return comp, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown %v %d", ref.XMLName.Local, ref.ID)
+}
+
+// convertRefs converts the refs of a message, or a component when message is empty. The conditional rules of
+// fields outside groups are collected for the message.
+func (c *orchestraConverter) convertRefs(message string, refs []*orchestraRef, topLevel bool) ([]*XMLComponentMember, error) {
+	var members []*XMLComponentMember
+	for _, ref := range refs {
+		if ref.Presence == "forbidden" {
+			continue
+		}
+		required := "N"
+		if ref.Presence == "required" {
+			required = "Y"
+		}
+
+		switch ref.XMLName.Local {
+		case "fieldRef":
+			field, err := c.lookupField(ref)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, &XMLComponentMember{XMLName: xml.Name{Local: "field"}, Name: field.Name, Required: required})
+
+			if message != "" {
+				for _, rule := range ref.Rules {
+					if rule.When == "" || (rule.Presence != "required" && rule.Presence != "forbidden") {
+						continue
+					}
+					c.conditions[message] = append(c.conditions[message], orchestraCondition{tag: field.ID, presence: rule.Presence, when: strings.TrimSpace(rule.When)})
+				}
+			}
+
+		case "componentRef":
+			comp, err := c.lookup(c.components, ref)
+			if err != nil {
+				return nil, err
+			}
+
+			// The standard header and trailer are not part of the body.
+			if topLevel && (comp.Name == "StandardHeader" || comp.Name == "StandardTrailer") {
+				if err := c.convertHeaderTrailer(comp); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if err := c.convertComponent(message, comp); err != nil {
+				return nil, err
+			}
+			members = append(members, &XMLComponentMember{XMLName: xml.Name{Local: "component"}, Name: comp.Name, Required: required})
+
+		case "groupRef":
+			group, err := c.lookup(c.groups, ref)
+			if err != nil {
+				return nil, err
+			}
+			if err := c.convertGroup(group); err != nil {
+				return nil, err
+			}
+			members = append(members, &XMLComponentMember{XMLName: xml.Name{Local: "component"}, Name: group.Name, Required: required})
+		}
+	}
+	return members, nil
+}
+
+func (c *orchestraConverter) convertHeaderTrailer(comp *orchestraComponent) error {
+	if (comp.Name == "StandardHeader" && c.doc.Header != nil) || (comp.Name == "StandardTrailer" && c.doc.Trailer != nil) {
+		return nil
+	}
+
+	members, err := c.convertRefs("", comp.Refs, false)
+	if err != nil {
+		return err
+	}
+
+	xmlComponent := &XMLComponent{Name: comp.Name, Members: members}
+	if comp.Name == "StandardHeader" {
+		c.doc.Header = xmlComponent
+	} else {
+		c.doc.Trailer = xmlComponent
+	}
+	return nil
+}
+
+// convertComponent adds comp to the components of the XMLDoc. Conditions of its fields apply to message.
+func (c *orchestraConverter) convertComponent(message string, comp *orchestraComponent) error {
+	members, err := c.convertRefs(message, comp.Refs, false)
+	if err != nil {
+		return err
+	}
+	if !c.xmlComponents[comp.Name] {
+		c.xmlComponents[comp.Name] = true
+		c.doc.Components = append(c.doc.Components, &XMLComponent{Name: comp.Name, Members: members})
+	}
+	return nil
+}
+
+// convertGroup adds a component named after group, made of its repeating group, to the components of the XMLDoc.
+func (c *orchestraConverter) convertGroup(group *orchestraComponent) error {
+	if c.xmlComponents[group.Name] {
+		return nil
+	}
+	if group.NumInGroup == nil {
+		return fmt.Errorf("group %v has no numInGroup", group.Name)
+	}
+	c.xmlComponents[group.Name] = true
+
+	numInGroup, err := c.lookupField(group.NumInGroup)
+	if err != nil {
+		return err
+	}
+
+	members, err := c.convertRefs("", group.Refs, false)
+	if err != nil {
+		return err
+	}
+
+	c.doc.Components = append(c.doc.Components, &XMLComponent{
+		Name: group.Name,
+		Members: []*XMLComponentMember{
+			{XMLName: xml.Name{Local: "group"}, Name: numInGroup.Name, Required: "N", Members: members},
+		},
+	})
+	return nil
+}
@@ -0,0 +1,103 @@
+package datadictionary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func values(fields map[int]string) func(tag int) (string, bool) {
+	return func(tag int) (string, bool) {
+		v, ok := fields[tag]
+		return v, ok
+	}
+}
+
+func TestParseOrchestra(t *testing.T) {
+	dict, err := Parse("../_test_data/orchestra44.xml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "FIX", dict.FIXType)
+	assert.Equal(t, 4, dict.Major)
+	assert.Equal(t, 4, dict.Minor)
+	assert.Contains(t, dict.Header.RequiredTags, 49)
+	assert.Contains(t, dict.Trailer.RequiredTags, 10)
+
+	side := dict.FieldTypeByTag[54]
+	assert.Equal(t, "CHAR", side.Type)
+	assert.Equal(t, "Buy", side.Enums["1"].Description)
+	assert.Equal(t, "SEQNUM", dict.FieldTypeByTag[34].Type)
+
+	order := dict.Messages["D"]
+	require.NotNil(t, order)
+	for _, tag := range []int{11, 54, 55, 60, 40} {
+		assert.Contains(t, order.RequiredTags, tag)
+	}
+	assert.NotContains(t, order.RequiredTags, 44)
+	assert.NotContains(t, order.Tags, 8, "header fields are not in the body")
+	assert.True(t, order.Fields[453].IsGroup())
+	assert.Contains(t, order.Tags, 448)
+
+	require.Len(t, order.Conditions, 3)
+	assert.Equal(t, 44, order.Conditions[0].Tag)
+	assert.Equal(t, PresenceRequired, order.Conditions[0].Presence)
+	assert.Equal(t, PresenceForbidden, order.Conditions[1].Presence)
+	assert.Equal(t, 99, order.Conditions[2].Tag)
+
+	assert.True(t, order.Conditions[0].Holds(values(map[int]string{40: "2"})))
+	assert.False(t, order.Conditions[0].Holds(values(map[int]string{40: "1"})))
+	assert.True(t, order.Conditions[1].Holds(values(map[int]string{40: "1"})))
+	assert.True(t, order.Conditions[2].Holds(values(map[int]string{40: "4"})))
+	assert.False(t, order.Conditions[2].Holds(values(map[int]string{})))
+
+	assert.Equal(t, "Heartbeat", dict.Messages["0"].Name)
+}
+
+func TestParseOrchestraScenario(t *testing.T) {
+	dict, err := ParseOrchestra("../_test_data/orchestra44.xml", "MarketOnly")
+	require.NoError(t, err)
+
+	order := dict.Messages["D"]
+	assert.Contains(t, order.RequiredTags, 38)
+	assert.NotContains(t, order.Tags, 44)
+	assert.Empty(t, order.Conditions)
+
+	dict, err = ParseOrchestra("../_test_data/orchestra44.xml", "Unknown")
+	require.NoError(t, err)
+	assert.NotContains(t, dict.Messages["D"].RequiredTags, 38)
+}
+
+func TestCondition(t *testing.T) {
+	dict, err := Parse("../_test_data/orchestra44.xml")
+	require.NoError(t, err)
+
+	var tests = []struct {
+		when   string
+		fields map[int]string
+		holds  bool
+	}{
+		{`OrderQty > 100`, map[int]string{38: "150"}, true},
+		{`OrderQty > 100`, map[int]string{38: "20"}, false},
+		{`OrderQty >= 100 and Side == ^Sell`, map[int]string{38: "100", 54: "2"}, true},
+		{`not exists Text`, map[int]string{58: "x"}, false},
+		{`!(Side == "1") || Symbol != 'IBM'`, map[int]string{54: "1", 55: "MSFT"}, true},
+		{`Price < StopPx`, map[int]string{44: "9.5", 99: "10"}, true},
+		{`Price < StopPx`, map[int]string{44: "9.5"}, false},
+		{`Symbol == "IBM"`, map[int]string{}, false},
+	}
+
+	for _, test := range tests {
+		c, err := NewCondition(dict, 44, PresenceRequired, test.when)
+		require.NoError(t, err, test.when)
+		assert.Equal(t, test.holds, c.Holds(values(test.fields)), test.when)
+	}
+
+	for _, when := range []string{`Unknown == 1`, `Side == ^Unknown`, `(Side == "1"`, `Side ==`, `"a" == ^Buy`, `Side = 1`} {
+		_, err := NewCondition(dict, 44, PresenceRequired, when)
+		assert.Error(t, err, when)
+	}
+
+	_, err = NewCondition(dict, 44, "optional", `Side == "1"`)
+	assert.Error(t, err)
+}
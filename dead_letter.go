@@ -0,0 +1,191 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrDeadLetterNotFound is returned when retrying or deleting a dead letter that does not exist.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// errDeadLetterQueueDisabled is returned by the dead letter API of a session without DeadLetterQueue.
+var errDeadLetterQueueDisabled = errors.New("DeadLetterQueue is not enabled for the session")
+
+// DeadLetter is an inbound application message rejected by FromApp, see DeadLetterQueue.
+type DeadLetter struct {
+	ID        int    `json:"id"`
+	MsgType   string `json:"msg_type"`
+	MsgSeqNum int    `json:"msg_seq_num"`
+
+	// RawMessage is the message as received.
+	RawMessage string `json:"raw_message"`
+
+	// Reason is the text of the reject, RejectReason its SessionRejectReason or BusinessRejectReason.
+	Reason       string `json:"reason"`
+	RejectReason int    `json:"reject_reason"`
+
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// DeadLetterStore is an optional interface implemented by a MessageStore to keep dead letters, see DeadLetterQueue.
+// Dead letters are kept when the store is reset.
+type DeadLetterStore interface {
+	// SaveDeadLetter stores letter, ignoring its ID, and returns the ID assigned, unique among the stored dead letters.
+	SaveDeadLetter(letter DeadLetter) (int, error)
+
+	// DeadLetters returns the stored dead letters, oldest first.
+	DeadLetters() ([]DeadLetter, error)
+
+	// DeleteDeadLetter removes the dead letter id, returning ErrDeadLetterNotFound if there is none.
+	DeleteDeadLetter(id int) error
+}
+
+// GetDeadLetters returns the dead letters of the Session matching the Session id, oldest first.
+func GetDeadLetters(sessionID SessionID) ([]DeadLetter, error) {
+	store, err := lookupDeadLetterStore(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return store.DeadLetters()
+}
+
+// RetryDeadLetter passes the dead letter id of the Session matching the Session id to FromApp again, on the
+// calling goroutine. The dead letter is deleted if FromApp accepts it, and kept if it is rejected again, in which
+// case the reject is returned.
+func RetryDeadLetter(sessionID SessionID, id int) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if session.deadLetters == nil {
+		return errDeadLetterQueueDisabled
+	}
+
+	letter, err := findDeadLetter(session.deadLetters, id)
+	if err != nil {
+		return err
+	}
+
+	msg := NewMessage()
+	if err := session.ParseMessage(msg, bytes.NewBufferString(letter.RawMessage)); err != nil {
+		return err
+	}
+	if reject := session.compression.decompress(msg); reject != nil {
+		return reject
+	}
+	msg.ReceiveTime = letter.ReceivedAt
+
+	if reject := session.fromApp(msg); reject != nil {
+		return reject
+	}
+	session.log.OnEventf("Retried dead letter %d", id)
+	return session.deadLetters.DeleteDeadLetter(id)
+}
+
+// DeleteDeadLetter discards the dead letter id of the Session matching the Session id.
+func DeleteDeadLetter(sessionID SessionID, id int) error {
+	store, err := lookupDeadLetterStore(sessionID)
+	if err != nil {
+		return err
+	}
+	return store.DeleteDeadLetter(id)
+}
+
+// ExportDeadLetters writes the dead letters of the Session matching the Session id to w as JSON, one per line.
+func ExportDeadLetters(sessionID SessionID, w io.Writer) error {
+	letters, err := GetDeadLetters(sessionID)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	for _, letter := range letters {
+		if err := enc.Encode(letter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deadLetterStoreProvider is implemented by a MessageStore wrapping another, such as the stores of
+// NewEncryptedMessageStoreFactory, to provide the DeadLetterStore of the wrapped store when it has one.
+type deadLetterStoreProvider interface {
+	deadLetterStore() (DeadLetterStore, bool)
+}
+
+// deadLetterStoreOf returns the DeadLetterStore of store, if any.
+func deadLetterStoreOf(store MessageStore) (DeadLetterStore, bool) {
+	if provider, ok := store.(deadLetterStoreProvider); ok {
+		return provider.deadLetterStore()
+	}
+	deadLetters, ok := store.(DeadLetterStore)
+	return deadLetters, ok
+}
+
+func lookupDeadLetterStore(sessionID SessionID) (DeadLetterStore, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if session.deadLetters == nil {
+		return nil, errDeadLetterQueueDisabled
+	}
+	return session.deadLetters, nil
+}
+
+func findDeadLetter(store DeadLetterStore, id int) (DeadLetter, error) {
+	letters, err := store.DeadLetters()
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	for _, letter := range letters {
+		if letter.ID == id {
+			return letter, nil
+		}
+	}
+	return DeadLetter{}, ErrDeadLetterNotFound
+}
+
+// saveDeadLetter keeps msg, rejected by FromApp with reject, when DeadLetterQueue is enabled.
+func (s *Session) saveDeadLetter(msg *Message, reject MessageRejectError) {
+	if s.deadLetters == nil {
+		return
+	}
+
+	letter := DeadLetter{
+		RawMessage:   string(msg.Bytes()),
+		Reason:       reject.Error(),
+		RejectReason: reject.RejectReason(),
+		ReceivedAt:   msg.ReceiveTime,
+	}
+	letter.MsgType, _ = msg.Header.GetString(tagMsgType)
+	letter.MsgSeqNum, _ = msg.Header.GetInt(tagMsgSeqNum)
+	if letter.ReceivedAt.IsZero() {
+		letter.ReceivedAt = s.now()
+	}
+
+	id, err := s.deadLetters.SaveDeadLetter(letter)
+	if err != nil {
+		s.log.OnEventf("Failed to save dead letter: %v", err)
+		return
+	}
+	s.log.OnEventf("Saved dead letter %d: %v", id, letter.Reason)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DeadLetterTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestDeadLetterTestSuite(t *testing.T) {
+	suite.Run(t, new(DeadLetterTestSuite))
+}
+
+func (s *DeadLetterTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.deadLetters = &s.MockStore
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *DeadLetterTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+// rejectOrder has FromApp reject a NewOrderSingle, received with ClOrdID clOrdID.
+func (s *DeadLetterTestSuite) rejectOrder(clOrdID string) *Message {
+	msg := s.NewOrderSingle()
+	msg.Body.SetString(tagClOrdID, clOrdID)
+	msg.ReceiveTime = time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	s.MockApp.On("FromApp").Return(UnsupportedMessageType()).Once()
+	s.MockApp.On("ToApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, msg)
+	return msg
+}
+
+func (s *DeadLetterTestSuite) TestRejectedMessageSaved() {
+	msg := s.rejectOrder("order1")
+	s.NextTargetMsgSeqNum(2)
+
+	letters, err := GetDeadLetters(s.sessionID)
+	s.Require().Nil(err)
+	s.Require().Len(letters, 1)
+	s.Equal("D", letters[0].MsgType)
+	s.Equal(1, letters[0].MsgSeqNum)
+	s.Equal(string(msg.Bytes()), letters[0].RawMessage)
+	s.Equal("Unsupported Message Type", letters[0].Reason)
+	s.Equal(rejectReasonUnsupportedMessageType, letters[0].RejectReason)
+	s.Equal(msg.ReceiveTime, letters[0].ReceivedAt)
+}
+
+func (s *DeadLetterTestSuite) TestAcceptedMessageNotSaved() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	letters, err := GetDeadLetters(s.sessionID)
+	s.Require().Nil(err)
+	s.Empty(letters)
+}
+
+func (s *DeadLetterTestSuite) TestRetry() {
+	s.rejectOrder("order1")
+	letters, err := GetDeadLetters(s.sessionID)
+	s.Require().Nil(err)
+	id := letters[0].ID
+
+	// Rejected again, the dead letter is kept.
+	s.MockApp.On("FromApp").Return(UnsupportedMessageType()).Once()
+	s.Equal(UnsupportedMessageType(), RetryDeadLetter(s.sessionID, id))
+	letters, err = GetDeadLetters(s.sessionID)
+	s.Require().Nil(err)
+	s.Len(letters, 1)
+
+	s.MockApp.On("FromApp").Return(nil).Once()
+	s.Nil(RetryDeadLetter(s.sessionID, id))
+	letters, err = GetDeadLetters(s.sessionID)
+	s.Require().Nil(err)
+	s.Empty(letters)
+
+	s.Equal(ErrDeadLetterNotFound, RetryDeadLetter(s.sessionID, id))
+}
+
+func (s *DeadLetterTestSuite) TestDelete() {
+	s.rejectOrder("order1")
+	letters, err := GetDeadLetters(s.sessionID)
+	s.Require().Nil(err)
+
+	s.Nil(DeleteDeadLetter(s.sessionID, letters[0].ID))
+	s.Equal(ErrDeadLetterNotFound, DeleteDeadLetter(s.sessionID, letters[0].ID))
+}
+
+func (s *DeadLetterTestSuite) TestExport() {
+	s.rejectOrder("order1")
+	s.MessageFactory.SetNextSeqNum(2)
+	s.rejectOrder("order2")
+
+	var buf bytes.Buffer
+	s.Require().Nil(ExportDeadLetters(s.sessionID, &buf))
+
+	dec := json.NewDecoder(&buf)
+	var exported []DeadLetter
+	for dec.More() {
+		var letter DeadLetter
+		s.Require().Nil(dec.Decode(&letter))
+		exported = append(exported, letter)
+	}
+	s.Require().Len(exported, 2)
+	s.Contains(exported[0].RawMessage, "11=order1")
+	s.Contains(exported[1].RawMessage, "11=order2")
+}
+
+func (s *DeadLetterTestSuite) TestDisabled() {
+	s.Session.deadLetters = nil
+	s.rejectOrder("order1")
+
+	_, err := GetDeadLetters(s.sessionID)
+	s.Equal(errDeadLetterQueueDisabled, err)
+	s.Equal(errDeadLetterQueueDisabled, RetryDeadLetter(s.sessionID, 1))
+	s.Equal(errDeadLetterQueueDisabled, DeleteDeadLetter(s.sessionID, 1))
+	s.Empty(s.MockStore.deadLetters)
+}
+
+func (s *DeadLetterTestSuite) TestUnknownSession() {
+	other := SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}
+	_, err := GetDeadLetters(other)
+	s.Equal(ErrSessionNotFound, err)
+	s.Equal(ErrSessionNotFound, RetryDeadLetter(other, 1))
+}
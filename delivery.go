@@ -0,0 +1,276 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// SendAndWaitOption configures SendAndWait.
+type SendAndWaitOption func(*sendAndWaitOptions)
+
+type sendAndWaitOptions struct {
+	ack bool
+}
+
+// WaitForAck makes SendAndWait wait for the counterparty to acknowledge the message rather than for the message to
+// be written to the connection. Only a TestRequest is acknowledged, by the Heartbeat echoing its TestReqID.
+func WaitForAck() SendAndWaitOption {
+	return func(o *sendAndWaitOptions) { o.ack = true }
+}
+
+// SendAndWait is Send returning once the message has been written to the connection of the Session, or
+// acknowledged by the counterparty with WaitForAck, so that callers know the message was delivered without polling
+// the MessageStore. A message queued while the Session is logged off is written once it logs on. Waits for the
+// send queue to have room like SendWithContext. Returns the error of ctx if it is done first, the message then
+// remaining queued, and ErrNotDelivered if the message is dropped from the send queue, or the Session disconnects
+// or resets its sequence numbers, before then.
+func SendAndWait(ctx context.Context, m Messagable, opts ...SendAndWaitOption) error {
+	return defaultEngine.SendAndWait(ctx, m, opts...)
+}
+
+// SendAndWait is Send returning once the message has been written to the connection of the Session, or
+// acknowledged by the counterparty with WaitForAck, so that callers know the message was delivered without polling
+// the MessageStore. A message queued while the Session is logged off is written once it logs on. Waits for the
+// send queue to have room like SendWithContext. Returns the error of ctx if it is done first, the message then
+// remaining queued, and ErrNotDelivered if the message is dropped from the send queue, or the Session disconnects
+// or resets its sequence numbers, before then.
+func (e *Engine) SendAndWait(ctx context.Context, m Messagable, opts ...SendAndWaitOption) error {
+	var o sendAndWaitOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	msg := m.ToMessage()
+	sessionID, err := headerSessionID(msg)
+	if err != nil {
+		return err
+	}
+
+	session, ok := e.registry.lookup(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if err := session.checkCanSend(); err != nil {
+		return err
+	}
+
+	session.recordSend(msg)
+	if session.requiresApproval(msg) {
+		msgType, _ := msg.Header.GetString(tagMsgType)
+		return ErrValidation{Details: fmt.Sprintf("MsgType %s requiring approval", msgType)}
+	}
+	return session.sendAndWait(ctx, msg, o)
+}
+
+// delivery is awaited by a SendAndWait call. done is closed once the message is delivered, or err is set.
+type delivery struct {
+	done chan struct{}
+	err  error
+}
+
+func newDelivery() *delivery {
+	return &delivery{done: make(chan struct{})}
+}
+
+// deliveries holds the deliveries awaited by SendAndWait, resolved once their messages are sent or acknowledged.
+type deliveries struct {
+	sync.Mutex
+	waiting atomic.Int32
+	sent    map[int]*delivery
+	acks    map[string]*delivery
+}
+
+func (d *deliveries) waitSent(seqNum int) *delivery {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.sent == nil {
+		d.sent = make(map[int]*delivery)
+	}
+	waiter := newDelivery()
+	d.sent[seqNum] = waiter
+	d.waiting.Add(1)
+	return waiter
+}
+
+func (d *deliveries) waitAck(testReqID string) *delivery {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.acks == nil {
+		d.acks = make(map[string]*delivery)
+	}
+	waiter := newDelivery()
+	d.acks[testReqID] = waiter
+	d.waiting.Add(1)
+	return waiter
+}
+
+// onSent resolves the delivery awaiting the message seqNum, if any.
+func (d *deliveries) onSent(seqNum int) {
+	d.Lock()
+	defer d.Unlock()
+
+	if waiter, ok := d.sent[seqNum]; ok {
+		close(waiter.done)
+		d.cancelSent(seqNum)
+	}
+}
+
+// onAck resolves the delivery awaiting the TestRequest testReqID, if any.
+func (d *deliveries) onAck(testReqID string) {
+	d.Lock()
+	defer d.Unlock()
+
+	if waiter, ok := d.acks[testReqID]; ok {
+		close(waiter.done)
+		d.cancelAck(testReqID)
+	}
+}
+
+// fail fails the deliveries awaiting messages to be sent with err, and those awaiting acknowledgements if acks.
+func (d *deliveries) fail(err error, acks bool) {
+	if d.waiting.Load() == 0 {
+		return
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	for seqNum, waiter := range d.sent {
+		waiter.err = err
+		close(waiter.done)
+		d.cancelSent(seqNum)
+	}
+	if !acks {
+		return
+	}
+	for testReqID, waiter := range d.acks {
+		waiter.err = err
+		close(waiter.done)
+		d.cancelAck(testReqID)
+	}
+}
+
+// cancelSent stops awaiting the message seqNum. The caller holds the lock.
+func (d *deliveries) cancelSent(seqNum int) {
+	if _, ok := d.sent[seqNum]; ok {
+		delete(d.sent, seqNum)
+		d.waiting.Add(-1)
+	}
+}
+
+// cancelAck stops awaiting the TestRequest testReqID. The caller holds the lock.
+func (d *deliveries) cancelAck(testReqID string) {
+	if _, ok := d.acks[testReqID]; ok {
+		delete(d.acks, testReqID)
+		d.waiting.Add(-1)
+	}
+}
+
+// sendAndWait queues msg for send, waiting for the send queue to have room, then waits for msg to be sent, or
+// acknowledged with opts.ack.
+func (s *Session) sendAndWait(ctx context.Context, msg *Message, opts sendAndWaitOptions) error {
+	var testReqID string
+	if opts.ack {
+		msgType, _ := msg.Header.GetBytes(tagMsgType)
+		if !bytes.Equal(msgType, msgTypeTestRequest) {
+			return ErrValidation{Details: fmt.Sprintf("no acknowledgement of MsgType %s", msgType)}
+		}
+
+		var err error
+		if testReqID, err = msg.Body.GetString(tagTestReqID); err != nil {
+			return ErrValidation{Details: "missing TestReqID", Err: err}
+		}
+	}
+
+	if err := s.lockQueueRoom(ctx, msg); err != nil {
+		return err
+	}
+	if err := s.queueForSendLocked(msg); err != nil {
+		s.sendMutex.Unlock()
+		return err
+	}
+	seqNum, _ := msg.Header.GetInt(tagMsgSeqNum)
+
+	var waiter *delivery
+	if opts.ack {
+		waiter = s.deliveries.waitAck(testReqID)
+	} else {
+		waiter = s.deliveries.waitSent(seqNum)
+	}
+	s.sendMutex.Unlock()
+
+	select {
+	case <-waiter.done:
+		return waiter.err
+	case <-ctx.Done():
+		s.deliveries.Lock()
+		defer s.deliveries.Unlock()
+		select {
+		case <-waiter.done:
+			// Resolved in between.
+			return waiter.err
+		default:
+		}
+		if opts.ack {
+			s.deliveries.cancelAck(testReqID)
+		} else {
+			s.deliveries.cancelSent(seqNum)
+		}
+		return ctx.Err()
+	}
+}
+
+// delivered resolves the SendAndWait call awaiting msg, sent by the Session.
+func (s *Session) delivered(msg []byte) {
+	if s.deliveries.waiting.Load() == 0 {
+		return
+	}
+	if seqNum, ok := queuedMsgSeqNum(msg); ok {
+		s.deliveries.onSent(seqNum)
+	}
+}
+
+// acknowledged resolves the SendAndWait call awaiting the TestRequest answered by msg, received by the Session.
+func (s *Session) acknowledged(msg *Message) {
+	if s.deliveries.waiting.Load() == 0 || !msg.IsMsgTypeOf(string(msgTypeHeartbeat)) {
+		return
+	}
+	if testReqID, err := msg.Body.GetString(tagTestReqID); err == nil {
+		s.deliveries.onAck(testReqID)
+	}
+}
+
+// queuedMsgSeqNum returns the MsgSeqNum of a message queued for send.
+func queuedMsgSeqNum(msgBytes []byte) (int, bool) {
+	i := bytes.Index(msgBytes, []byte("\00134="))
+	if i < 0 {
+		return 0, false
+	}
+	value := msgBytes[i+4:]
+	if end := bytes.IndexByte(value, '\001'); end >= 0 {
+		value = value[:end]
+	}
+	seqNum, err := strconv.Atoi(string(value))
+	return seqNum, err == nil
+}
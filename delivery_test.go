@@ -0,0 +1,182 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DeliveryTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestDeliveryTestSuite(t *testing.T) {
+	suite.Run(t, new(DeliveryTestSuite))
+}
+
+func (s *DeliveryTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *DeliveryTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *DeliveryTestSuite) message(msgType string) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagBeginString, FIXString(s.sessionID.BeginString))
+	msg.Header.SetField(tagSenderCompID, FIXString(s.sessionID.SenderCompID))
+	msg.Header.SetField(tagTargetCompID, FIXString(s.sessionID.TargetCompID))
+	msg.Header.SetField(tagMsgType, FIXString(msgType))
+	return msg
+}
+
+func (s *DeliveryTestSuite) sendAndWait(ctx context.Context, msg *Message, opts ...SendAndWaitOption) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- SendAndWait(ctx, msg, opts...)
+	}()
+	s.Require().Eventually(func() bool { return s.Session.deliveries.waiting.Load() == 1 }, time.Second, time.Millisecond)
+	return done
+}
+
+func (s *DeliveryTestSuite) sendQueued() {
+	s.Session.sendMutex.Lock()
+	defer s.Session.sendMutex.Unlock()
+	s.Session.sendQueued(true)
+}
+
+func (s *DeliveryTestSuite) waitDone(done <-chan error) error {
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Second):
+		s.FailNow("SendAndWait did not return")
+		return nil
+	}
+}
+
+func (s *DeliveryTestSuite) TestSendAndWait() {
+	s.MockApp.On("ToApp").Return(nil)
+	done := s.sendAndWait(context.Background(), s.message("D"))
+	s.Len(done, 0)
+
+	s.sendQueued()
+	s.Nil(s.waitDone(done))
+	s.LastToAppMessageSent()
+	s.Zero(s.Session.deliveries.waiting.Load())
+}
+
+func (s *DeliveryTestSuite) TestSendAndWaitCanceled() {
+	s.MockApp.On("ToApp").Return(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := s.sendAndWait(ctx, s.message("D"))
+
+	cancel()
+	s.True(errors.Is(s.waitDone(done), context.Canceled))
+	s.Zero(s.Session.deliveries.waiting.Load())
+
+	// The message remains queued.
+	s.Equal(1, s.Session.QueueDepth())
+	s.NextSenderMsgSeqNum(2)
+}
+
+func (s *DeliveryTestSuite) TestSendAndWaitForAck() {
+	s.MockApp.On("ToAdmin")
+	testRequest := s.message("1")
+	testRequest.Body.SetField(tagTestReqID, FIXString("ping"))
+	done := s.sendAndWait(context.Background(), testRequest, WaitForAck())
+
+	s.sendQueued()
+	s.LastToAdminMessageSent()
+	s.Len(done, 0, "the TestRequest is not acknowledged yet")
+
+	heartbeat := s.Heartbeat()
+	heartbeat.Body.SetField(tagTestReqID, FIXString("other"))
+	s.Session.acknowledged(heartbeat)
+	s.Len(done, 0)
+
+	heartbeat.Body.SetField(tagTestReqID, FIXString("ping"))
+	s.Session.acknowledged(heartbeat)
+	s.Nil(s.waitDone(done))
+	s.Zero(s.Session.deliveries.waiting.Load())
+}
+
+func (s *DeliveryTestSuite) TestSendAndWaitDropped() {
+	s.MockApp.On("ToApp").Return(nil)
+	done := s.sendAndWait(context.Background(), s.message("D"))
+
+	s.Session.sendMutex.Lock()
+	s.Session.dropQueued()
+	s.Session.sendMutex.Unlock()
+	s.Equal(ErrNotDelivered, s.waitDone(done))
+	s.Zero(s.Session.deliveries.waiting.Load())
+}
+
+func (s *DeliveryTestSuite) TestSendAndWaitReset() {
+	s.MockApp.On("ToApp").Return(nil)
+	done := s.sendAndWait(context.Background(), s.message("D"))
+
+	s.Require().Nil(s.Session.dropAndReset())
+	s.Equal(ErrNotDelivered, s.waitDone(done))
+
+	// A message sent after the reset reuses the sequence number of the failed one.
+	done = s.sendAndWait(context.Background(), s.message("D"))
+	s.sendQueued()
+	s.Nil(s.waitDone(done))
+}
+
+func (s *DeliveryTestSuite) TestSendAndWaitForAckDisconnected() {
+	s.MockApp.On("ToAdmin")
+	testRequest := s.message("1")
+	testRequest.Body.SetField(tagTestReqID, FIXString("ping"))
+	done := s.sendAndWait(context.Background(), testRequest, WaitForAck())
+	s.sendQueued()
+
+	s.Session.onDisconnect()
+	s.Equal(ErrNotDelivered, s.waitDone(done))
+	s.Zero(s.Session.deliveries.waiting.Load())
+}
+
+func (s *DeliveryTestSuite) TestSendAndWaitForAckInvalid() {
+	var validation ErrValidation
+	s.True(errors.As(SendAndWait(context.Background(), s.message("D"), WaitForAck()), &validation))
+	s.True(errors.As(SendAndWait(context.Background(), s.message("1"), WaitForAck()), &validation), "missing TestReqID")
+	s.Equal(0, s.Session.QueueDepth())
+}
+
+func (s *DeliveryTestSuite) TestSendAndWaitRequiresApproval() {
+	s.Session.ApprovalMsgTypes = map[string]bool{"D": true}
+	var validation ErrValidation
+	s.True(errors.As(SendAndWait(context.Background(), s.message("D")), &validation))
+}
+
+func TestQueuedMsgSeqNum(t *testing.T) {
+	seqNum, ok := queuedMsgSeqNum([]byte("8=FIX.4.2\x019=5\x0135=0\x0134=42\x0110=000\x01"))
+	if !ok || seqNum != 42 {
+		t.Errorf("expected MsgSeqNum 42, got %v %v", seqNum, ok)
+	}
+	if _, ok := queuedMsgSeqNum([]byte("8=FIX.4.2\x019=5\x0135=0\x0110=000\x01")); ok {
+		t.Error("expected no MsgSeqNum")
+	}
+}
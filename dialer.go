@@ -16,8 +16,11 @@
 package quickfix
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"golang.org/x/net/proxy"
@@ -42,6 +45,15 @@ func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, er
 	}
 	dialer = stdDialer
 
+	var forward proxy.Dialer = stdDialer
+	if hasDualStackSettings(settings) {
+		var dualStack *dualStackDialer
+		if dualStack, err = loadDualStackDialer(settings, stdDialer); err != nil {
+			return
+		}
+		dialer, forward = dualStack, dualStack
+	}
+
 	if !settings.HasSetting(config.ProxyType) {
 		return
 	}
@@ -75,7 +87,7 @@ func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, er
 
 		var proxyDialer proxy.Dialer
 
-		proxyDialer, err = proxy.SOCKS5("tcp", fmt.Sprintf("%s:%d", proxyHost, proxyPort), proxyAuth, stdDialer)
+		proxyDialer, err = proxy.SOCKS5("tcp", fmt.Sprintf("%s:%d", proxyHost, proxyPort), proxyAuth, forward)
 		if err != nil {
 			return
 		}
@@ -93,3 +105,221 @@ func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, er
 
 	return
 }
+
+func hasDualStackSettings(settings *SessionSettings) bool {
+	for _, setting := range []string{
+		config.SocketAddressFamily, config.SocketFallbackDelay, config.SocketConnectTimeoutIPv4,
+		config.SocketConnectTimeoutIPv6, config.SocketLocalHost,
+	} {
+		if settings.HasSetting(setting) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadDualStackDialer(settings *SessionSettings, stdDialer *net.Dialer) (d *dualStackDialer, err error) {
+	d = &dualStackDialer{
+		dialer:        stdDialer,
+		fallbackDelay: 300 * time.Millisecond,
+		timeout4:      stdDialer.Timeout,
+		timeout6:      stdDialer.Timeout,
+		lookup:        net.DefaultResolver.LookupIPAddr,
+	}
+
+	if settings.HasSetting(config.SocketAddressFamily) {
+		if d.family, err = settings.Setting(config.SocketAddressFamily); err != nil {
+			return
+		}
+		switch d.family {
+		case "any", "ipv4", "ipv6":
+		default:
+			return nil, fmt.Errorf("unsupported address family %s", d.family)
+		}
+	}
+
+	if settings.HasSetting(config.SocketFallbackDelay) {
+		if d.fallbackDelay, err = settings.DurationSetting(config.SocketFallbackDelay); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketConnectTimeoutIPv4) {
+		if d.timeout4, err = settings.DurationSetting(config.SocketConnectTimeoutIPv4); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketConnectTimeoutIPv6) {
+		if d.timeout6, err = settings.DurationSetting(config.SocketConnectTimeoutIPv6); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketLocalHost) {
+		var localHost string
+		if localHost, err = settings.Setting(config.SocketLocalHost); err != nil {
+			return
+		}
+		for _, host := range strings.Split(localHost, ",") {
+			ip := net.ParseIP(strings.TrimSpace(host))
+			switch {
+			case ip == nil:
+				return nil, fmt.Errorf("invalid local address %q", host)
+			case ip.To4() != nil && d.local4 == nil:
+				d.local4 = ip
+			case ip.To4() == nil && d.local6 == nil:
+				d.local6 = ip
+			default:
+				return nil, fmt.Errorf("more than one local address for the family of %v", ip)
+			}
+		}
+	}
+
+	return
+}
+
+// dualStackDialer connects to hosts with both IPv6 and IPv4 addresses by racing the two families, the IPv4
+// attempts starting after fallbackDelay or once the IPv6 ones have failed, in the manner of RFC 8305.
+type dualStackDialer struct {
+	dialer *net.Dialer
+
+	// family is "any", "ipv4" or "ipv6", empty for any.
+	family             string
+	fallbackDelay      time.Duration
+	timeout4, timeout6 time.Duration
+	local4, local6     net.IP
+
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+func (d *dualStackDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *dualStackDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var ipv6, ipv4 []net.IP
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			if d.family != "ipv6" && network != "tcp6" {
+				ipv4 = append(ipv4, addr.IP)
+			}
+		} else if d.family != "ipv4" && network != "tcp4" {
+			ipv6 = append(ipv6, addr.IP)
+		}
+	}
+
+	primaries, fallbacks := ipv6, ipv4
+	if len(primaries) == 0 {
+		primaries, fallbacks = ipv4, nil
+	}
+	if len(primaries) == 0 {
+		return nil, fmt.Errorf("no suitable address for %v", host)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult)
+	pending := 1
+	go d.dialSerial(ctx, primaries, port, results)
+
+	startFallback := func() {
+		if fallbacks != nil {
+			pending++
+			go d.dialSerial(ctx, fallbacks, port, results)
+			fallbacks = nil
+		}
+	}
+
+	var fallbackTimer <-chan time.Time
+	if fallbacks != nil {
+		if d.fallbackDelay < 0 {
+			startFallback()
+		} else {
+			timer := time.NewTimer(d.fallbackDelay)
+			defer timer.Stop()
+			fallbackTimer = timer.C
+		}
+	}
+
+	var firstErr error
+	for {
+		select {
+		case <-fallbackTimer:
+			startFallback()
+
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				go closeLateConns(results, pending)
+				return result.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			if fallbacks != nil {
+				startFallback()
+			} else if pending == 0 {
+				return nil, firstErr
+			}
+		}
+	}
+}
+
+// dialSerial connects to the addresses of a family in turn, sending the first connection or the last error.
+func (d *dualStackDialer) dialSerial(ctx context.Context, ips []net.IP, port string, results chan<- dialResult) {
+	var err error
+	for _, ip := range ips {
+		dialer := *d.dialer
+		if ip.To4() != nil {
+			dialer.Timeout = d.timeout4
+			if d.local4 != nil {
+				dialer.LocalAddr = &net.TCPAddr{IP: d.local4}
+			}
+		} else {
+			dialer.Timeout = d.timeout6
+			if d.local6 != nil {
+				dialer.LocalAddr = &net.TCPAddr{IP: d.local6}
+			}
+		}
+
+		var conn net.Conn
+		if conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), port)); err == nil {
+			results <- dialResult{conn: conn}
+			return
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = errors.New("no address to connect to")
+	}
+	results <- dialResult{err: err}
+}
+
+// closeLateConns closes the connections established after the race was won.
+func closeLateConns(results <-chan dialResult, pending int) {
+	for ; pending > 0; pending-- {
+		if result := <-results; result.conn != nil {
+			_ = result.conn.Close()
+		}
+	}
+}
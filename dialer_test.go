@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"context"
 	"net"
 	"testing"
 	"time"
@@ -90,3 +91,150 @@ func (s *DialerTestSuite) TestLoadDialerSocksProxyInvalidPort() {
 	_, err := loadDialerConfig(s.settings.GlobalSettings())
 	s.Require().NotNil(err)
 }
+
+func (s *DialerTestSuite) TestLoadDialerDualStack() {
+	s.settings.GlobalSettings().Set(config.SocketTimeout, "10s")
+	s.settings.GlobalSettings().Set(config.SocketAddressFamily, "ipv6")
+	s.settings.GlobalSettings().Set(config.SocketFallbackDelay, "250ms")
+	s.settings.GlobalSettings().Set(config.SocketConnectTimeoutIPv4, "2s")
+	s.settings.GlobalSettings().Set(config.SocketLocalHost, "10.0.0.5, 2001:db8::5")
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	dualStack, ok := dialer.(*dualStackDialer)
+	s.Require().True(ok)
+	s.Equal("ipv6", dualStack.family)
+	s.Equal(250*time.Millisecond, dualStack.fallbackDelay)
+	s.Equal(2*time.Second, dualStack.timeout4)
+	s.Equal(10*time.Second, dualStack.timeout6)
+	s.Equal("10.0.0.5", dualStack.local4.String())
+	s.Equal("2001:db8::5", dualStack.local6.String())
+}
+
+func (s *DialerTestSuite) TestLoadDialerDualStackInvalid() {
+	for setting, value := range map[string]string{
+		config.SocketAddressFamily: "ipx",
+		config.SocketFallbackDelay: "soon",
+		config.SocketLocalHost:     "10.0.0.5,10.0.0.6",
+	} {
+		settings := NewSettings()
+		settings.GlobalSettings().Set(setting, value)
+		_, err := loadDialerConfig(settings.GlobalSettings())
+		s.NotNil(err, setting)
+	}
+
+	s.settings.GlobalSettings().Set(config.SocketLocalHost, "localhost")
+	_, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *DialerTestSuite) TestLoadDialerDualStackSocksProxy() {
+	s.settings.GlobalSettings().Set(config.SocketLocalHost, "127.0.0.1")
+	s.settings.GlobalSettings().Set(config.ProxyType, "socks")
+	s.settings.GlobalSettings().Set(config.ProxyHost, "localhost")
+	s.settings.GlobalSettings().Set(config.ProxyPort, "31337")
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	_, ok := dialer.(*dualStackDialer)
+	s.False(ok)
+}
+
+// listen returns a listener on the loopback address, accepting connections in the background.
+func (s *DialerTestSuite) listen(address string) net.Listener {
+	ln, err := net.Listen("tcp", address)
+	s.Require().Nil(err)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	return ln
+}
+
+func (s *DialerTestSuite) dualStackDialer(ips ...string) *dualStackDialer {
+	s.settings.GlobalSettings().Set(config.SocketFallbackDelay, "1h")
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	dualStack := dialer.(*dualStackDialer)
+	dualStack.lookup = func(context.Context, string) (addrs []net.IPAddr, err error) {
+		for _, ip := range ips {
+			addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+		}
+		return
+	}
+	return dualStack
+}
+
+func (s *DialerTestSuite) TestDualStackPrefersIPv6() {
+	ln6 := s.listen("[::1]:0")
+	defer ln6.Close()
+	_, port, _ := net.SplitHostPort(ln6.Addr().String())
+	ln4 := s.listen("127.0.0.1:" + port)
+	defer ln4.Close()
+
+	conn, err := s.dualStackDialer("127.0.0.1", "::1").DialContext(context.Background(), "tcp", net.JoinHostPort("venue", port))
+	s.Require().Nil(err)
+	defer conn.Close()
+	s.Equal("::1", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+}
+
+func (s *DialerTestSuite) TestDualStackFallsBackOnIPv6Failure() {
+	ln4 := s.listen("127.0.0.1:0")
+	defer ln4.Close()
+	_, port, _ := net.SplitHostPort(ln4.Addr().String())
+
+	// Nothing listens on the IPv6 address, the IPv4 attempt starts without waiting for the fallback delay.
+	start := time.Now()
+	conn, err := s.dualStackDialer("::1", "127.0.0.1").DialContext(context.Background(), "tcp", net.JoinHostPort("venue", port))
+	s.Require().Nil(err)
+	defer conn.Close()
+	s.Equal("127.0.0.1", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+	s.Less(time.Since(start), time.Minute)
+}
+
+func (s *DialerTestSuite) TestDualStackAddressFamily() {
+	ln6 := s.listen("[::1]:0")
+	defer ln6.Close()
+	_, port, _ := net.SplitHostPort(ln6.Addr().String())
+	ln4 := s.listen("127.0.0.1:" + port)
+	defer ln4.Close()
+
+	s.settings.GlobalSettings().Set(config.SocketAddressFamily, "ipv4")
+	conn, err := s.dualStackDialer("::1", "127.0.0.1").DialContext(context.Background(), "tcp", net.JoinHostPort("venue", port))
+	s.Require().Nil(err)
+	defer conn.Close()
+	s.Equal("127.0.0.1", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+
+	_, err = s.dualStackDialer("::1").DialContext(context.Background(), "tcp", net.JoinHostPort("venue", port))
+	s.NotNil(err)
+}
+
+func (s *DialerTestSuite) TestDualStackLocalHost() {
+	ln6 := s.listen("[::1]:0")
+	defer ln6.Close()
+	_, port, _ := net.SplitHostPort(ln6.Addr().String())
+	ln4 := s.listen("127.0.0.1:" + port)
+	defer ln4.Close()
+
+	s.settings.GlobalSettings().Set(config.SocketLocalHost, "127.0.0.1,::1")
+	dialer := s.dualStackDialer("::1")
+	conn, err := dialer.DialContext(context.Background(), "tcp", net.JoinHostPort("venue", port))
+	s.Require().Nil(err)
+	s.Equal("::1", conn.LocalAddr().(*net.TCPAddr).IP.String())
+	conn.Close()
+
+	dialer.family = "ipv4"
+	dialer.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+	conn, err = dialer.DialContext(context.Background(), "tcp", net.JoinHostPort("venue", port))
+	s.Require().Nil(err)
+	s.Equal("127.0.0.1", conn.LocalAddr().(*net.TCPAddr).IP.String())
+	conn.Close()
+}
@@ -0,0 +1,66 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"net"
+	"time"
+)
+
+// duplicateConnectionText is the Text of the Logout sent on a duplicate connection.
+const duplicateConnectionText = "Duplicate connection: session is already connected"
+
+// duplicateConnectionWriteTimeout bounds writing the Logout to a duplicate connection.
+const duplicateConnectionWriteTimeout = 5 * time.Second
+
+// alreadyConnectedError is returned by Session.connect when the session is connected. It carries the Logout to send
+// on the rejected connection.
+type alreadyConnectedError struct {
+	logout []byte
+}
+
+func (alreadyConnectedError) Error() string { return "Already connected" }
+
+// buildDuplicateConnectionLogout builds the Logout for a connection rejected because the session is connected. The
+// Logout is not part of the session's sequence: it carries the next sender MsgSeqNum without consuming it and is
+// neither persisted nor passed to ToAdmin, so that the established connection is unaffected.
+func (s *Session) buildDuplicateConnectionLogout() []byte {
+	logout := s.buildLogout(duplicateConnectionText)
+	s.fillDefaultHeader(logout, nil)
+	logout.Header.SetInt(tagMsgSeqNum, s.store.NextSenderMsgSeqNum())
+	return logout.Build()
+}
+
+// rejectDuplicateConnection sends the Logout of err on netConn, a second connection for an already connected
+// session. sessID is the key of the session in the acceptor, without the Qualifier of a static session, under which
+// the address of the established connection is kept. The connection is closed by the caller.
+func (a *Acceptor) rejectDuplicateConnection(sessID SessionID, session *Session, netConn net.Conn, err alreadyConnectedError) {
+	connectedAddr, _ := a.RemoteAddr(sessID)
+	session.log.OnEventf("Rejected duplicate connection from %v, session is connected from %v",
+		netConn.RemoteAddr(), connectedAddr)
+	a.globalLog.OnEventf("Rejected duplicate connection for Session %v from %v, session is connected from %v",
+		session.sessionID, netConn.RemoteAddr(), connectedAddr)
+
+	if err := netConn.SetWriteDeadline(time.Now().Add(duplicateConnectionWriteTimeout)); err != nil {
+		a.globalLog.OnEvent(err.Error())
+		return
+	}
+	if _, err := netConn.Write(err.logout); err != nil {
+		a.globalLog.OnEventf("Unable to send Logout on duplicate connection for Session %v: %v", session.sessionID, err)
+		return
+	}
+	session.log.OnOutgoing(err.logout)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DuplicateConnectionTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestDuplicateConnectionTestSuite(t *testing.T) {
+	suite.Run(t, new(DuplicateConnectionTestSuite))
+}
+
+func (s *DuplicateConnectionTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+}
+
+func (s *DuplicateConnectionTestSuite) connectErr() error {
+	rep := make(chan error, 1)
+	s.Session.onAdmin(connect{messageOut: make(chan []byte), err: rep})
+	return <-rep
+}
+
+func (s *DuplicateConnectionTestSuite) TestAlreadyConnectedBuildsLogout() {
+	s.IncrNextSenderMsgSeqNum()
+
+	err := s.connectErr()
+	var alreadyConnected alreadyConnectedError
+	s.Require().True(errors.As(err, &alreadyConnected))
+	s.Equal("Already connected", err.Error())
+
+	logout := NewMessage()
+	s.Require().Nil(ParseMessage(logout, bytes.NewBuffer(alreadyConnected.logout)))
+	s.MessageType(string(msgTypeLogout), logout)
+	s.FieldEquals(tagText, duplicateConnectionText, logout.Body)
+	s.FieldEquals(tagMsgSeqNum, 2, logout.Header)
+	s.FieldEquals(tagSenderCompID, s.sessionID.SenderCompID, logout.Header)
+	s.FieldEquals(tagTargetCompID, s.sessionID.TargetCompID, logout.Header)
+
+	// The established connection is unaffected.
+	s.NextSenderMsgSeqNum(2)
+	s.State(inSession{})
+	s.NoMessageSent()
+}
+
+func (s *DuplicateConnectionTestSuite) TestRejectDuplicateConnectionSendsLogout() {
+	logger, err := NewNullLogFactory().Create()
+	s.Require().Nil(err)
+	acceptor := &Acceptor{globalLog: logger}
+	acceptor.sessionAddr.Store(s.sessionID, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4000})
+
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	alreadyConnected, ok := s.connectErr().(alreadyConnectedError)
+	s.Require().True(ok)
+	go acceptor.rejectDuplicateConnection(s.sessionID, s.Session, local, alreadyConnected)
+
+	msgBytes, err := newParser(bufio.NewReader(remote)).ReadMessage()
+	s.Require().Nil(err)
+	s.Equal(alreadyConnected.logout, msgBytes.Bytes())
+
+	// The address of the established connection is kept.
+	addr, ok := acceptor.RemoteAddr(s.sessionID)
+	s.True(ok)
+	s.Equal("10.0.0.1:4000", addr.String())
+}
+
+// eventsLog keeps the events logged.
+type eventsLog struct {
+	nullLog
+	events []string
+}
+
+func (l *eventsLog) OnEventf(format string, a ...interface{}) {
+	l.events = append(l.events, fmt.Sprintf(format, a...))
+}
+
+func (s *DuplicateConnectionTestSuite) TestRejectDuplicateConnectionQualifiedSession() {
+	globalLog := &eventsLog{}
+	acceptor := &Acceptor{globalLog: globalLog}
+	s.Session.sessionID.Qualifier = "q"
+	sessID := s.Session.sessionID
+	sessID.Qualifier = ""
+	acceptor.sessionAddr.Store(sessID, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4000})
+
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	alreadyConnected, ok := s.connectErr().(alreadyConnectedError)
+	s.Require().True(ok)
+	go acceptor.rejectDuplicateConnection(sessID, s.Session, local, alreadyConnected)
+
+	_, err := newParser(bufio.NewReader(remote)).ReadMessage()
+	s.Require().Nil(err)
+	s.Require().NotEmpty(globalLog.events)
+	s.Contains(globalLog.events[0], "session is connected from 10.0.0.1:4000",
+		"the address is kept without the Qualifier of the session")
+}
@@ -0,0 +1,257 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// EgressSchedulerOptions configure an EgressScheduler.
+type EgressSchedulerOptions struct {
+	// Tick is the scheduling period, 10ms if zero.
+	Tick time.Duration
+
+	// BytesPerTick is the number of bytes the sessions may write per Tick altogether, the capacity of the shared
+	// network interface.
+	BytesPerTick int
+}
+
+// EgressScheduler shares the egress bandwidth of a host between sessions, so that a session writing a lot, such as
+// during a massive resend, does not starve the others.
+//
+// Each Tick, the BytesPerTick budget is divided between the sessions waiting to write in proportion to their weight.
+// The budget not needed by waiting sessions is available to the first sessions writing during the Tick, so that
+// an uncontended session writes without delay. A message larger than the share of its session is written once the
+// session has accumulated enough budget.
+type EgressScheduler struct {
+	opts EgressSchedulerOptions
+
+	mu      sync.Mutex
+	spare   int
+	writers map[SessionID]*egressWriter
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// NewEgressScheduler returns a running EgressScheduler, see Add and Stop.
+func NewEgressScheduler(opts EgressSchedulerOptions) (*EgressScheduler, error) {
+	if opts.BytesPerTick <= 0 {
+		return nil, errors.New("BytesPerTick must be greater than zero")
+	}
+	if opts.Tick == 0 {
+		opts.Tick = 10 * time.Millisecond
+	}
+	if opts.Tick < 0 {
+		return nil, errors.New("Tick must be greater than zero")
+	}
+
+	s := &EgressScheduler{
+		opts:    opts,
+		spare:   opts.BytesPerTick,
+		writers: make(map[SessionID]*egressWriter),
+		stop:    make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Add schedules the writes of the Session matching the Session id with weight, from its next connection.
+func (s *EgressScheduler) Add(sessionID SessionID, weight int) error {
+	if weight <= 0 {
+		return errors.New("weight must be greater than zero")
+	}
+
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.egress.Lock()
+	defer session.egress.Unlock()
+	session.egress.scheduler = s
+	session.egress.weight = weight
+	return nil
+}
+
+// Remove stops scheduling the writes of the Session matching the Session id, from its next connection.
+func (s *EgressScheduler) Remove(sessionID SessionID) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.egress.Lock()
+	if session.egress.scheduler == s {
+		session.egress.scheduler = nil
+	}
+	session.egress.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w, ok := s.writers[sessionID]; ok {
+		w.release()
+		delete(s.writers, sessionID)
+	}
+	return nil
+}
+
+// Stop stops the EgressScheduler, the writes of its sessions no longer being delayed.
+func (s *EgressScheduler) Stop() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+func (s *EgressScheduler) run() {
+	ticker := time.NewTicker(s.opts.Tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.tick()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// tick divides the budget of the Tick between the waiting writers.
+func (s *EgressScheduler) tick() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spare = s.opts.BytesPerTick
+
+	var totalWeight int
+	for _, w := range s.writers {
+		if w.need > 0 {
+			totalWeight += w.weight
+		} else {
+			w.credit = 0
+		}
+	}
+	if totalWeight == 0 {
+		return
+	}
+
+	for _, w := range s.writers {
+		if w.need == 0 {
+			continue
+		}
+
+		share := s.opts.BytesPerTick * w.weight / totalWeight
+		if share == 0 {
+			share = 1
+		}
+		w.credit += share
+		s.spare -= share
+
+		if w.credit >= w.need {
+			w.credit -= w.need
+			w.release()
+		}
+	}
+	if s.spare < 0 {
+		s.spare = 0
+	}
+}
+
+// writer returns the io.Writer writing to conn on behalf of the session.
+func (s *EgressScheduler) writer(sessionID SessionID, weight int, conn io.Writer) io.Writer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if previous, ok := s.writers[sessionID]; ok {
+		previous.release()
+	}
+	w := &egressWriter{scheduler: s, weight: weight, conn: conn}
+	s.writers[sessionID] = w
+	return w
+}
+
+// egressWriter writes to the connection of a session once the EgressScheduler grants it the budget.
+type egressWriter struct {
+	scheduler *EgressScheduler
+	weight    int
+	conn      io.Writer
+
+	// Guarded by the mutex of the scheduler.
+	credit int
+	need   int
+	ready  chan struct{}
+}
+
+func (w *egressWriter) Write(p []byte) (int, error) {
+	s := w.scheduler
+
+	s.mu.Lock()
+	if w.credit < len(p) {
+		take := len(p) - w.credit
+		if take > s.spare {
+			take = s.spare
+		}
+		s.spare -= take
+		w.credit += take
+	}
+
+	if w.credit >= len(p) {
+		w.credit -= len(p)
+		s.mu.Unlock()
+		return w.conn.Write(p)
+	}
+
+	w.need = len(p)
+	w.ready = make(chan struct{})
+	ready := w.ready
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+	case <-s.stop:
+	}
+	return w.conn.Write(p)
+}
+
+// release wakes up the writer waiting for budget. The mutex of the scheduler must be held.
+func (w *egressWriter) release() {
+	if w.need == 0 {
+		return
+	}
+	w.need = 0
+	close(w.ready)
+}
+
+// sessionEgress is the EgressScheduler of a session, if any.
+type sessionEgress struct {
+	sync.Mutex
+	scheduler *EgressScheduler
+	weight    int
+}
+
+// egressWriter returns the io.Writer messages are written to conn with, scheduled if the session has an
+// EgressScheduler.
+func (s *Session) egressWriter(conn io.Writer) io.Writer {
+	s.egress.Lock()
+	scheduler, weight := s.egress.scheduler, s.egress.weight
+	s.egress.Unlock()
+
+	if scheduler == nil {
+		return conn
+	}
+	return scheduler.writer(s.sessionID, weight, conn)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EgressSchedulerTestSuite struct {
+	SessionSuiteRig
+	scheduler *EgressScheduler
+}
+
+func TestEgressSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(EgressSchedulerTestSuite))
+}
+
+func (s *EgressSchedulerTestSuite) SetupTest() {
+	s.Init()
+	s.Require().Nil(registerSession(s.Session))
+
+	var err error
+	// Ticks are driven by the tests.
+	s.scheduler, err = NewEgressScheduler(EgressSchedulerOptions{Tick: time.Hour, BytesPerTick: 40})
+	s.Require().Nil(err)
+}
+
+func (s *EgressSchedulerTestSuite) TearDownTest() {
+	s.scheduler.Stop()
+	_ = UnregisterSession(s.sessionID)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
+
+// write writes n bytes with w in the background, returning a channel closed once written.
+func (s *EgressSchedulerTestSuite) write(w io.Writer, n int) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := w.Write([]byte(strings.Repeat("x", n)))
+		s.Nil(err)
+	}()
+	return done
+}
+
+func (s *EgressSchedulerTestSuite) waiting(sessionID SessionID) bool {
+	s.scheduler.mu.Lock()
+	defer s.scheduler.mu.Unlock()
+	return s.scheduler.writers[sessionID].need > 0
+}
+
+func (s *EgressSchedulerTestSuite) written(done chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	case <-time.After(10 * time.Millisecond):
+		return false
+	}
+}
+
+func (s *EgressSchedulerTestSuite) TestInvalidOptions() {
+	_, err := NewEgressScheduler(EgressSchedulerOptions{})
+	s.NotNil(err)
+
+	_, err = NewEgressScheduler(EgressSchedulerOptions{Tick: -time.Second, BytesPerTick: 10})
+	s.NotNil(err)
+}
+
+func (s *EgressSchedulerTestSuite) TestAdd() {
+	var conn bytes.Buffer
+	s.Equal(&conn, s.Session.egressWriter(&conn))
+
+	s.NotNil(s.scheduler.Add(s.sessionID, 0))
+	s.Equal(ErrSessionNotFound, s.scheduler.Add(SessionID{BeginString: "FIX.4.4", SenderCompID: "x", TargetCompID: "y"}, 1))
+
+	s.Require().Nil(s.scheduler.Add(s.sessionID, 2))
+	w, ok := s.Session.egressWriter(&conn).(*egressWriter)
+	s.Require().True(ok)
+	s.Equal(2, w.weight)
+
+	s.Require().Nil(s.scheduler.Remove(s.sessionID))
+	s.Equal(&conn, s.Session.egressWriter(&conn))
+}
+
+func (s *EgressSchedulerTestSuite) TestUncontendedWriteUsesSpareBudget() {
+	var conn syncBuffer
+	w := s.scheduler.writer(s.sessionID, 1, &conn)
+
+	s.True(s.written(s.write(w, 30)))
+	s.Equal(30, conn.Len())
+
+	// Only 10 bytes are left in the Tick.
+	done := s.write(w, 30)
+	s.False(s.written(done))
+
+	s.scheduler.tick()
+	s.True(s.written(done))
+	s.Equal(60, conn.Len())
+}
+
+func (s *EgressSchedulerTestSuite) TestWeightedShares() {
+	heavyID := SessionID{BeginString: "FIX.4.2", SenderCompID: "heavy", TargetCompID: "TW"}
+	lightID := SessionID{BeginString: "FIX.4.2", SenderCompID: "light", TargetCompID: "TW"}
+	var heavyConn, lightConn syncBuffer
+	heavy := s.scheduler.writer(heavyID, 3, &heavyConn)
+	light := s.scheduler.writer(lightID, 1, &lightConn)
+
+	// Exhausts the budget of the Tick.
+	s.True(s.written(s.write(heavy, 40)))
+
+	heavyDone := s.write(heavy, 30)
+	lightDone := s.write(light, 30)
+	s.Eventually(func() bool { return s.waiting(heavyID) && s.waiting(lightID) }, time.Second, time.Millisecond)
+
+	// Shares of 30 and 10 bytes.
+	s.scheduler.tick()
+	s.True(s.written(heavyDone))
+	s.False(s.written(lightDone))
+
+	// The heavy session no longer waiting, the light one gets the whole budget.
+	s.scheduler.tick()
+	s.True(s.written(lightDone))
+	s.Equal(70, heavyConn.Len())
+	s.Equal(30, lightConn.Len())
+}
+
+func (s *EgressSchedulerTestSuite) TestStopReleasesWriters() {
+	var conn syncBuffer
+	w := s.scheduler.writer(s.sessionID, 1, &conn)
+
+	done := s.write(w, 100)
+	s.False(s.written(done))
+
+	s.scheduler.Stop()
+	s.True(s.written(done))
+	s.Equal(100, conn.Len())
+}
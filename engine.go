@@ -0,0 +1,59 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// Engine owns a registry of sessions along with the stores, logs and dialer of the Acceptors and Initiators it
+// creates, so that independent engines run side by side in one process without seeing each other's sessions.
+//
+// The package-level functions, such as SendToTarget, GetSession and FindSessionIDs, and the Acceptors and
+// Initiators created with NewAcceptor and NewInitiator use the default Engine, see DefaultEngine. Functions taking
+// a SessionID that have no Engine method, such as RegisterRiskChecker or GetSessionStatus, look the session up in
+// the registry of the Engine owning it, searching the default Engine first when engines share a SessionID.
+type Engine struct {
+	registry *registry
+	opts     []EngineOption
+}
+
+var defaultEngine = NewEngine()
+
+// NewEngine creates an Engine with an empty registry of sessions. The options, e.g. WithStoreFactory,
+// WithLogFactory and WithDialer, apply to every Acceptor and Initiator of the Engine, before their own options.
+func NewEngine(opts ...EngineOption) *Engine {
+	return &Engine{registry: newRegistry(), opts: opts}
+}
+
+// DefaultEngine returns the Engine used by the package-level functions and by NewAcceptor and NewInitiator.
+func DefaultEngine() *Engine {
+	return defaultEngine
+}
+
+// NewAcceptor creates an Acceptor registering its sessions with the Engine.
+func (e *Engine) NewAcceptor(app Application, settings *Settings, opts ...EngineOption) (*Acceptor, error) {
+	return NewAcceptor(app, nil, settings, nil, e.options(opts)...)
+}
+
+// NewInitiator creates an Initiator registering its sessions with the Engine.
+func (e *Engine) NewInitiator(app Application, settings *Settings, opts ...EngineOption) (*Initiator, error) {
+	return NewInitiator(app, nil, settings, nil, e.options(opts)...)
+}
+
+// options returns the options of the Engine followed by opts.
+func (e *Engine) options(opts []EngineOption) []EngineOption {
+	all := make([]EngineOption, 0, len(e.opts)+len(opts)+1)
+	all = append(all, e.opts...)
+	all = append(all, opts...)
+	return append(all, func(o *engineOptions) { o.registry = e.registry })
+}
@@ -0,0 +1,113 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// Clock is the source of the current time of the sessions, see WithClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// EngineOption configures the components of an Acceptor or Initiator, see NewAcceptor and NewInitiator.
+// Options that do not apply to the engine being created are ignored.
+type EngineOption func(*engineOptions)
+
+type engineOptions struct {
+	logFactory      LogFactory
+	storeFactory    MessageStoreFactory
+	metrics         MetricsCollector
+	clock           Clock
+	tracer          CallbackTracer
+	dialer          proxy.ContextDialer
+	listenerFactory NewListenerCallback
+	seqNumPublisher SeqNumPublisher
+	recorder        Recorder
+	holidays        HolidayCalendar
+	registry        *registry
+
+	reconnectCoordinator *ReconnectCoordinator
+	fieldCrypter         Crypter
+	rawDecoders          map[string]RawMessageDecoder
+}
+
+func newEngineOptions(storeFactory MessageStoreFactory, logFactory LogFactory, opts []EngineOption) engineOptions {
+	o := engineOptions{storeFactory: storeFactory, logFactory: logFactory}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.storeFactory == nil {
+		o.storeFactory = NewMemoryStoreFactory()
+	}
+	if o.logFactory == nil {
+		o.logFactory = NewNullLogFactory()
+	}
+	if o.registry == nil {
+		o.registry = defaultEngine.registry
+	}
+	return o
+}
+
+// WithLogFactory sets the LogFactory of the engine, replacing the one passed to the constructor.
+func WithLogFactory(logFactory LogFactory) EngineOption {
+	return func(o *engineOptions) { o.logFactory = logFactory }
+}
+
+// WithStoreFactory sets the MessageStoreFactory of the engine, replacing the one passed to the constructor.
+func WithStoreFactory(storeFactory MessageStoreFactory) EngineOption {
+	return func(o *engineOptions) { o.storeFactory = storeFactory }
+}
+
+// WithMetrics sets the MetricsCollector of the sessions of the engine, instead of the one set with
+// SetMetricsCollector.
+func WithMetrics(collector MetricsCollector) EngineOption {
+	return func(o *engineOptions) { o.metrics = collector }
+}
+
+// WithClock sets the source of the current time of the sessions of the engine, used for SendingTime, session
+// schedules and timeouts. Defaults to the system clock.
+func WithClock(clock Clock) EngineOption {
+	return func(o *engineOptions) { o.clock = clock }
+}
+
+// WithCallbackTracer sets the CallbackTracer starting spans around the ContextApplication callbacks of the sessions
+// of the engine.
+func WithCallbackTracer(tracer CallbackTracer) EngineOption {
+	return func(o *engineOptions) { o.tracer = tracer }
+}
+
+// WithDialer sets the dialer of an Initiator, used instead of the dialer configured by the settings of each session.
+func WithDialer(dialer proxy.ContextDialer) EngineOption {
+	return func(o *engineOptions) { o.dialer = dialer }
+}
+
+// WithListenerFactory sets the callback creating the listeners of an Acceptor, see SetNewListenerCallback.
+func WithListenerFactory(listenerFactory NewListenerCallback) EngineOption {
+	return func(o *engineOptions) { o.listenerFactory = listenerFactory }
+}
+
+// now returns the current time of the session's Clock.
+func (s *Session) now() time.Time {
+	if s.clock != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
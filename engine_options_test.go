@@ -0,0 +1,146 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+type countingStoreFactory struct {
+	MessageStoreFactory
+	created int
+}
+
+func (f *countingStoreFactory) Create(sessionID SessionID) (MessageStore, error) {
+	f.created++
+	return f.MessageStoreFactory.Create(sessionID)
+}
+
+type dialerFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+func (f dialerFunc) Dial(network, address string) (net.Conn, error) {
+	return f(context.Background(), network, address)
+}
+
+func (f dialerFunc) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return f(ctx, network, address)
+}
+
+func engineOptionsSettings(t *testing.T, senderCompID string, initiator bool) *Settings {
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, senderCompID)
+	sessionSettings.Set(config.TargetCompID, "target")
+	if initiator {
+		sessionSettings.Set(config.HeartBtInt, "30")
+		sessionSettings.Set(config.SocketConnectHost, "venue")
+		sessionSettings.Set(config.SocketConnectPort, "5001")
+	} else {
+		sessionSettings.Set(config.SocketAcceptPort, "0")
+	}
+
+	settings := NewSettings()
+	_, err := settings.AddSession(sessionSettings)
+	require.NoError(t, err)
+	return settings
+}
+
+func TestNewAcceptorOptions(t *testing.T) {
+	settings := engineOptionsSettings(t, "options-acceptor", false)
+	collector := newRecordingMetrics()
+	clock := fixedClock{time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)}
+
+	var listenerAddress string
+	listenerFactory := func(address string, _ *tls.Config) (net.Listener, error) {
+		listenerAddress = address
+		return net.Listen("tcp", "127.0.0.1:0")
+	}
+
+	acceptor, err := NewAcceptor(&MockApp{}, nil, settings, nil,
+		WithMetrics(collector), WithClock(clock), WithListenerFactory(listenerFactory))
+	require.NoError(t, err)
+
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "options-acceptor", TargetCompID: "target"}
+	session := acceptor.sessions[sessionID]
+	require.NotNil(t, session)
+	assert.Same(t, collector, session.metricsCollector)
+	assert.Equal(t, clock.t, session.now())
+
+	require.NoError(t, acceptor.Start())
+	acceptor.Stop()
+	assert.Equal(t, ":0", listenerAddress)
+}
+
+func TestNewAcceptorFactoryOptions(t *testing.T) {
+	settings := engineOptionsSettings(t, "options-factories", false)
+	storeFactory := &countingStoreFactory{MessageStoreFactory: NewMemoryStoreFactory()}
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, nil,
+		WithStoreFactory(storeFactory), WithLogFactory(NewNullLogFactory()))
+	require.NoError(t, err)
+	defer func() {
+		for sessionID := range acceptor.sessions {
+			_ = UnregisterSession(sessionID)
+		}
+	}()
+
+	assert.Equal(t, 1, storeFactory.created)
+	assert.Same(t, storeFactory, acceptor.storeFactory)
+}
+
+func TestNewInitiatorWithDialer(t *testing.T) {
+	settings := engineOptionsSettings(t, "options-initiator", true)
+
+	dialed := make(chan string, 1)
+	dialer := dialerFunc(func(_ context.Context, _, address string) (net.Conn, error) {
+		select {
+		case dialed <- address:
+		default:
+		}
+		return nil, errors.New("refused")
+	})
+
+	initiator, err := NewInitiator(&MockApp{}, nil, settings, nil, WithDialer(dialer))
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	select {
+	case address := <-dialed:
+		assert.Equal(t, "venue:5001", address)
+	case <-time.After(5 * time.Second):
+		t.Fatal("dialer not used")
+	}
+}
+
+func TestSessionNowDefaultsToSystemClock(t *testing.T) {
+	session := &Session{}
+	assert.WithinDuration(t, time.Now(), session.now(), time.Second)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnginesAreIsolated(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "engine", TargetCompID: "target"}
+
+	storeFactory := &countingStoreFactory{MessageStoreFactory: NewMemoryStoreFactory()}
+	first := NewEngine(WithStoreFactory(storeFactory))
+	second := NewEngine()
+
+	firstAcceptor, err := first.NewAcceptor(&MockApp{}, engineOptionsSettings(t, "engine", false))
+	require.NoError(t, err)
+	secondAcceptor, err := second.NewAcceptor(&MockApp{}, engineOptionsSettings(t, "engine", false))
+	require.NoError(t, err, "the same SessionID registers with each engine")
+	assert.Equal(t, 1, storeFactory.created, "the options of an engine apply to its acceptors only")
+
+	firstSession, err := first.GetSession(sessionID)
+	require.NoError(t, err)
+	assert.Same(t, firstAcceptor.sessions[sessionID], firstSession)
+	secondSession, err := second.GetSession(sessionID)
+	require.NoError(t, err)
+	assert.Same(t, secondAcceptor.sessions[sessionID], secondSession)
+
+	_, err = GetSession(sessionID)
+	assert.Equal(t, ErrSessionNotFound, err, "the default engine does not see the sessions of other engines")
+	assert.Empty(t, FindSessionIDs(map[string]string{}))
+
+	assert.Equal(t, []SessionID{sessionID}, first.FindSessionIDs(nil))
+	require.NoError(t, first.SetRoutingMetadata(sessionID, map[string]string{"venue": "first"}))
+	found, err := first.LookupRoute(map[string]string{"venue": "first"})
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, found)
+	_, err = second.LookupRoute(map[string]string{"venue": "first"})
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	require.NoError(t, first.SetNextSenderMsgSeqNum(sessionID, 10))
+	next, err := first.GetExpectedSenderNum(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, next)
+	next, err = second.GetExpectedSenderNum(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, next)
+
+	require.NoError(t, firstAcceptor.Start())
+	firstAcceptor.Stop()
+	_, err = first.GetSession(sessionID)
+	assert.Equal(t, ErrSessionNotFound, err, "stopping unregisters the sessions from their engine")
+	_, err = second.GetSession(sessionID)
+	assert.NoError(t, err)
+	status, err := GetSessionStatus(sessionID)
+	require.NoError(t, err, "functions without an Engine method find the sessions of other engines")
+	assert.Equal(t, 1, status.NextSenderMsgSeqNum)
+
+	require.NoError(t, second.UnregisterSession(sessionID))
+	_, err = GetSessionStatus(sessionID)
+	assert.Equal(t, ErrSessionNotFound, err)
+}
+
+func TestDefaultEngine(t *testing.T) {
+	settings := engineOptionsSettings(t, "default-engine", false)
+	acceptor, err := NewAcceptor(&MockApp{}, nil, settings, nil)
+	require.NoError(t, err)
+
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "default-engine", TargetCompID: "target"}
+	session, err := DefaultEngine().GetSession(sessionID)
+	require.NoError(t, err)
+	assert.Same(t, acceptor.sessions[sessionID], session)
+
+	require.NoError(t, UnregisterSession(sessionID))
+}
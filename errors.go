@@ -23,6 +23,55 @@ import (
 // ErrDoNotSend is a convenience error to indicate a DoNotSend in ToApp.
 var ErrDoNotSend = errors.New("Do Not Send")
 
+// Errors returned when a message cannot be sent, use errors.Is to test for them.
+var (
+	// ErrSessionNotFound indicates that no Session is registered for the SessionID.
+	ErrSessionNotFound = errors.New("Unknown Session")
+
+	// ErrNotLoggedOn indicates that the Session cannot process a request because it is not logged on.
+	ErrNotLoggedOn = errors.New("Session not logged on")
+
+	// ErrNotSessionTime indicates that the Session cannot send messages because it is outside of the configured
+	// session time. Within session time, messages sent while the Session is logged off are queued until logon.
+	ErrNotSessionTime = errors.New("Not session time")
+
+	// ErrNotDelivered indicates that SendAndWait gave up on a message dropped from the send queue, or queued when the
+	// Session disconnected or reset its sequence numbers. A message dropped once persisted may still be resent in
+	// answer to a ResendRequest.
+	ErrNotDelivered = errors.New("Message not delivered")
+
+	// ErrQueueFull indicates that the Session's send queue is at capacity.
+	ErrQueueFull = errors.New("Send queue full")
+
+	// ErrSessionDraining indicates that the Session is stopping and no longer accepts messages.
+	ErrSessionDraining = errors.New("Session draining")
+
+	// ErrHandshakePending indicates that the Session is logged on but its HandshakePolicy has not completed yet.
+	ErrHandshakePending = errors.New("Session handshake pending")
+
+	// ErrDropCopy indicates that the Session is a DropCopy session, which does not send application messages.
+	ErrDropCopy = errors.New("Drop copy session")
+
+	// ErrAmbiguousRoute indicates that the routing metadata passed to SendToRoute match several Sessions.
+	ErrAmbiguousRoute = errors.New("Ambiguous route")
+)
+
+// ErrValidation indicates that an outgoing message failed validation, use errors.As to retrieve the details.
+type ErrValidation struct {
+	Details string
+	Err     error
+}
+
+func (e ErrValidation) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Invalid message: %s: %s", e.Details, e.Err.Error())
+	}
+	return fmt.Sprintf("Invalid message: %s", e.Details)
+}
+
+// Unwrap returns the underlying error, if any.
+func (e ErrValidation) Unwrap() error { return e.Err }
+
 // rejectReason enum values.
 const (
 	rejectReasonInvalidTagNumber                          = 0
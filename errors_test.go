@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -295,3 +296,22 @@ func TestInvalidTagNumber(t *testing.T) {
 		t.Error("Expected IsBusinessReject to be false\n")
 	}
 }
+
+func TestErrValidation(t *testing.T) {
+	cause := errors.New("tag not found")
+	var err error = fmt.Errorf("send failed: %w", ErrValidation{Details: "missing MsgType", Err: cause})
+
+	var validationErr ErrValidation
+	if !errors.As(err, &validationErr) {
+		t.Fatal("expected errors.As to find ErrValidation")
+	}
+	if validationErr.Details != "missing MsgType" {
+		t.Errorf("expected: missing MsgType, got: %s\n", validationErr.Details)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected errors.Is to find the underlying error")
+	}
+	if errors.Is(err, ErrQueueFull) {
+		t.Error("did not expect errors.Is to match ErrQueueFull")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "time"
+
+// ConnectEndpointHandler is an optional interface implemented by an Application to be notified of the endpoint an
+// initiator is connected to, among the SocketConnectHost<n> of the session.
+type ConnectEndpointHandler interface {
+	// OnConnectEndpoint is called once connected to address, the endpoint of SocketConnectHost<index>, or of
+	// SocketConnectHost for index 0.
+	OnConnectEndpoint(address string, index int, sessionID SessionID)
+}
+
+// connectEndpoints selects the endpoint of each connection attempt of an initiator, see SocketConnectFailover,
+// and keeps the backoff state of each endpoint, see SocketConnectBackoffMax.
+type connectEndpoints struct {
+	addresses  []string
+	priority   bool
+	interval   time.Duration
+	maxBackoff time.Duration
+
+	// next is the endpoint tried first by the next connection attempt.
+	next     int
+	failures []int
+	retryAt  []time.Time
+}
+
+func newConnectEndpoints(session *Session) *connectEndpoints {
+	return &connectEndpoints{
+		addresses:  session.SocketConnectAddress,
+		priority:   session.SocketConnectPriority,
+		interval:   session.ReconnectInterval,
+		maxBackoff: session.SocketConnectBackoffMax,
+		failures:   make([]int, len(session.SocketConnectAddress)),
+		retryAt:    make([]time.Time, len(session.SocketConnectAddress)),
+	}
+}
+
+// pick returns the endpoint to connect to at now, the first one from next not backed off. If all are backed off,
+// it returns the one backed off the least along with how long to wait before connecting to it.
+func (e *connectEndpoints) pick(now time.Time) (int, time.Duration) {
+	earliest := e.next
+	for n := 0; n < len(e.addresses); n++ {
+		i := (e.next + n) % len(e.addresses)
+		if !e.retryAt[i].After(now) {
+			return i, 0
+		}
+		if e.retryAt[i].Before(e.retryAt[earliest]) {
+			earliest = i
+		}
+	}
+	return earliest, e.retryAt[earliest].Sub(now)
+}
+
+// failed records a failed connection attempt to endpoint i at now. The endpoint is backed off for ReconnectInterval,
+// doubled with each consecutive failure up to SocketConnectBackoffMax, if set.
+func (e *connectEndpoints) failed(i int, now time.Time) {
+	e.next = (i + 1) % len(e.addresses)
+	e.failures[i]++
+	if e.maxBackoff <= 0 {
+		return
+	}
+
+	backoff := e.maxBackoff
+	if shift := e.failures[i] - 1; shift < 32 && e.interval<<shift > 0 && e.interval<<shift < e.maxBackoff {
+		backoff = e.interval << shift
+	}
+	e.retryAt[i] = now.Add(backoff)
+}
+
+// connected records a connection to endpoint i. Once disconnected, the next attempt fails back to the first
+// endpoint with SocketConnectFailover set to PRIORITY, or else moves on to the following endpoint.
+func (e *connectEndpoints) connected(i int) {
+	e.failures[i] = 0
+	e.retryAt[i] = time.Time{}
+	if e.priority {
+		e.next = 0
+	} else {
+		e.next = (i + 1) % len(e.addresses)
+	}
+}
+
+// onConnectEndpoint notifies the Application of the endpoint connected to, if it implements ConnectEndpointHandler.
+func (s *Session) onConnectEndpoint(address string, index int) {
+	s.log.OnEventf("Connected to endpoint %v: %v", index, address)
+	if handler, ok := s.application.(ConnectEndpointHandler); ok {
+		handler.OnConnectEndpoint(address, index, s.sessionID)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConnectEndpoints(priority bool, maxBackoff time.Duration) *connectEndpoints {
+	return newConnectEndpoints(&Session{SessionSettings: internal.SessionSettings{
+		SocketConnectAddress:    []string{"a:1", "b:2", "c:3"},
+		SocketConnectPriority:   priority,
+		ReconnectInterval:       time.Second,
+		SocketConnectBackoffMax: maxBackoff,
+	}})
+}
+
+func TestConnectEndpointsRoundRobin(t *testing.T) {
+	e := newTestConnectEndpoints(false, 0)
+	now := time.Now()
+
+	for _, expected := range []int{0, 1, 2, 0} {
+		i, wait := e.pick(now)
+		assert.Equal(t, expected, i)
+		assert.Zero(t, wait)
+		e.failed(i, now)
+	}
+
+	i, _ := e.pick(now)
+	e.connected(i)
+	i, _ = e.pick(now)
+	assert.Equal(t, 2, i, "disconnected endpoint is followed by the next")
+}
+
+func TestConnectEndpointsPriority(t *testing.T) {
+	e := newTestConnectEndpoints(true, 0)
+	now := time.Now()
+
+	for _, expected := range []int{0, 1} {
+		i, _ := e.pick(now)
+		assert.Equal(t, expected, i)
+		e.failed(i, now)
+	}
+
+	i, _ := e.pick(now)
+	assert.Equal(t, 2, i)
+	e.connected(i)
+
+	i, _ = e.pick(now)
+	assert.Equal(t, 0, i, "fails back to the first endpoint once disconnected")
+}
+
+func TestConnectEndpointsBackoff(t *testing.T) {
+	e := newTestConnectEndpoints(true, 3*time.Second)
+	now := time.Now()
+
+	e.failed(0, now)
+	i, wait := e.pick(now)
+	assert.Equal(t, 1, i)
+	assert.Zero(t, wait)
+
+	e.failed(1, now)
+	e.failed(2, now)
+	i, wait = e.pick(now)
+	assert.Equal(t, 0, i, "all backed off, the least backed off is picked")
+	assert.Equal(t, time.Second, wait)
+
+	now = now.Add(time.Second)
+	e.failed(0, now)
+	assert.Equal(t, now.Add(2*time.Second), e.retryAt[0])
+	e.failed(0, now)
+	assert.Equal(t, now.Add(3*time.Second), e.retryAt[0], "backoff is capped")
+
+	e.connected(0)
+	i, wait = e.pick(now)
+	assert.Equal(t, 0, i)
+	assert.Zero(t, wait)
+}
+
+type connectEndpointApp struct {
+	*MockApp
+	endpoints chan int
+}
+
+func (a connectEndpointApp) OnConnectEndpoint(_ string, index int, _ SessionID) {
+	select {
+	case a.endpoints <- index:
+	default:
+	}
+}
+
+func TestInitiatorFailover(t *testing.T) {
+	conns := make(chan net.Conn, 1)
+	dialer := dialerFunc(func(_ context.Context, _, address string) (net.Conn, error) {
+		if address == "primary:5001" {
+			return nil, errors.New("connection refused")
+		}
+		client, server := net.Pipe()
+		conns <- server
+		return client, nil
+	})
+
+	app := connectEndpointApp{MockApp: &MockApp{}, endpoints: make(chan int, 1)}
+	app.On("ToAdmin")
+	app.On("OnLogout").Maybe()
+
+	settings := NewSettings()
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "sender")
+	sessionSettings.Set(config.TargetCompID, "target")
+	sessionSettings.Set(config.HeartBtInt, "30")
+	sessionSettings.Set(config.ReconnectInterval, "10ms")
+	sessionSettings.Set(config.SocketConnectHost, "primary")
+	sessionSettings.Set(config.SocketConnectPort, "5001")
+	sessionSettings.Set(config.SocketConnectHost+"1", "backup")
+	sessionSettings.Set(config.SocketConnectPort+"1", "5002")
+	sessionSettings.Set(config.SocketConnectFailover, "PRIORITY")
+	_, err := settings.AddSession(sessionSettings)
+	require.NoError(t, err)
+
+	initiator, err := NewInitiator(app, nil, settings, nil, WithDialer(dialer))
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	select {
+	case index := <-app.endpoints:
+		assert.Equal(t, 1, index)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not failed over to the backup endpoint")
+	}
+
+	conn := <-conns
+	logon := make([]byte, 1024)
+	n, err := conn.Read(logon)
+	require.NoError(t, err)
+	assert.Contains(t, string(logon[:n]), "\x0135=A\x01")
+	_ = conn.Close()
+}
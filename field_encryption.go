@@ -0,0 +1,114 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithFieldCrypter sets the Crypter encrypting the EncryptedFields of the sessions of the engine with the key
+// shared with the counterparty.
+func WithFieldCrypter(crypter Crypter) EngineOption {
+	return func(o *engineOptions) { o.fieldCrypter = crypter }
+}
+
+// fieldEncryption encrypts the configured fields of outgoing application messages, and decrypts them in incoming
+// application messages. Encrypted values are base64 encoded so that they remain valid FIX values. Only body fields
+// outside of repeating groups are encrypted.
+type fieldEncryption struct {
+	crypter Crypter
+	keyID   string
+	tags    []Tag
+}
+
+// encrypt replaces the value of the configured fields of msg with its encrypted form.
+func (e *fieldEncryption) encrypt(msg *Message) error {
+	if e == nil {
+		return nil
+	}
+
+	for _, tag := range e.tags {
+		if !msg.Body.Has(tag) {
+			continue
+		}
+		value, rejectErr := msg.Body.GetBytes(tag)
+		if rejectErr != nil {
+			return rejectErr
+		}
+
+		ciphertext, err := e.crypter.Encrypt(e.keyID, value)
+		if err != nil {
+			return fmt.Errorf("encrypting tag %v: %w", tag, err)
+		}
+		msg.Body.SetString(tag, base64.StdEncoding.EncodeToString(ciphertext))
+	}
+	return nil
+}
+
+// decrypt restores the value of the configured fields of msg.
+func (e *fieldEncryption) decrypt(msg *Message) MessageRejectError {
+	if e == nil {
+		return nil
+	}
+
+	for _, tag := range e.tags {
+		if !msg.Body.Has(tag) {
+			continue
+		}
+		value, err := msg.Body.GetBytes(tag)
+		if err != nil {
+			return err
+		}
+
+		ciphertext, dErr := base64.StdEncoding.DecodeString(string(value))
+		if dErr != nil {
+			return IncorrectDataFormatForValue(tag)
+		}
+		plaintext, dErr := e.crypter.Decrypt(e.keyID, ciphertext)
+		if dErr != nil {
+			return IncorrectDataFormatForValue(tag)
+		}
+		msg.Body.SetBytes(tag, plaintext)
+	}
+	return nil
+}
+
+// parseEncryptedFields parses a comma delimited list of body tags.
+func parseEncryptedFields(value string) ([]Tag, error) {
+	var tags []Tag
+	for _, tagStr := range strings.Split(value, ",") {
+		if tagStr = strings.TrimSpace(tagStr); tagStr == "" {
+			continue
+		}
+
+		tag, err := strconv.Atoi(tagStr)
+		if err != nil || tag <= 0 {
+			return nil, fmt.Errorf("invalid tag %q", tagStr)
+		}
+		if Tag(tag).IsHeader() || Tag(tag).IsTrailer() {
+			return nil, fmt.Errorf("%v is not a body field", tag)
+		}
+		tags = append(tags, Tag(tag))
+	}
+
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tag")
+	}
+	return tags, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const tagAccount Tag = 1
+
+type FieldEncryptionTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestFieldEncryptionTestSuite(t *testing.T) {
+	suite.Run(t, new(FieldEncryptionTestSuite))
+}
+
+func (s *FieldEncryptionTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.fieldEncryption = &fieldEncryption{crypter: xorCrypter{}, keyID: "key", tags: []Tag{tagAccount}}
+}
+
+func (s *FieldEncryptionTestSuite) TestOutboundEncrypted() {
+	msg := s.NewOrderSingle()
+	msg.Body.SetString(tagAccount, "ACC1")
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(msg))
+	s.LastToAppMessageSent()
+
+	ciphertext, err := xorCrypter{}.Encrypt("key", []byte("ACC1"))
+	s.Require().NoError(err)
+	s.FieldEquals(tagAccount, base64.StdEncoding.EncodeToString(ciphertext), s.MockApp.lastToApp.Body)
+}
+
+func (s *FieldEncryptionTestSuite) TestAdminMessagesNotEncrypted() {
+	msg := s.Heartbeat()
+	msg.Body.SetString(tagAccount, "ACC1")
+
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(s.Session.send(msg))
+	s.LastToAdminMessageSent()
+	s.FieldEquals(tagAccount, "ACC1", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *FieldEncryptionTestSuite) TestInboundDecrypted() {
+	msg := s.NewOrderSingle()
+	msg.Body.SetString(tagAccount, "ACC1")
+	s.Require().Nil(s.Session.fieldEncryption.encrypt(msg))
+
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, msg)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.FieldEquals(tagAccount, "ACC1", msg.Body)
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *FieldEncryptionTestSuite) TestInboundUndecryptableRejected() {
+	msg := s.NewOrderSingle()
+	msg.Body.SetString(tagAccount, "not base64!")
+
+	s.MockApp.On("ToAdmin")
+	s.Session.fixMsgIn(s.Session, msg)
+
+	s.MockApp.AssertNotCalled(s.T(), "FromApp")
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeReject), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagSessionRejectReason, rejectReasonIncorrectDataFormatForValue, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagRefTagID, int(tagAccount), s.MockApp.lastToAdmin.Body)
+}
@@ -17,9 +17,15 @@ package quickfix
 
 import (
 	"bytes"
-	"sort"
+	"iter"
+	"slices"
 	"sync"
 	"time"
+
+	"github.com/quagmt/udecimal"
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
 )
 
 // field stores a slice of TagValues.
@@ -51,11 +57,56 @@ func (t tagSort) Len() int           { return len(t.tags) }
 func (t tagSort) Swap(i, j int)      { t.tags[i], t.tags[j] = t.tags[j], t.tags[i] }
 func (t tagSort) Less(i, j int) bool { return t.compare(t.tags[i], t.tags[j]) }
 
+// sorted sorts the tags in place, unless they already are. The tags are not passed to sort as a sort.Interface, which
+// would move them to the heap.
+func (t tagSort) sorted() []Tag {
+	cmp := func(i, j Tag) int {
+		switch {
+		case t.compare(i, j):
+			return -1
+		case t.compare(j, i):
+			return 1
+		}
+		return 0
+	}
+	if !slices.IsSortedFunc(t.tags, cmp) {
+		slices.SortFunc(t.tags, cmp)
+	}
+	return t.tags
+}
+
 // FieldMap is a collection of fix fields that make up a fix message.
 type FieldMap struct {
 	tagLookup map[Tag]field
 	tagSort
 	rwLock *sync.RWMutex
+
+	// parsed holds all the fields of the parsed message the FieldMap is a section of, until the FieldMap is modified.
+	// The fields of the section are picked from them, in the order they were received, only when iterated.
+	parsed        []TagValue
+	parsedSection fieldSection
+	parsedDict    *datadictionary.DataDictionary
+}
+
+// fieldSection is the section of a message held by a FieldMap.
+type fieldSection uint8
+
+const (
+	sectionHeader fieldSection = iota + 1
+	sectionBody
+	sectionTrailer
+)
+
+// holds reports whether the field tag of a message parsed with the transport data dictionary dict was put in the
+// section by the parser.
+func (s fieldSection) holds(tag Tag, dict *datadictionary.DataDictionary) bool {
+	switch {
+	case isHeaderField(tag, dict):
+		return s == sectionHeader
+	case isTrailerField(tag, dict):
+		return s == sectionTrailer
+	}
+	return s == sectionBody
 }
 
 // ascending tags.
@@ -154,11 +205,17 @@ func (m FieldMap) getBytesNoLock(tag Tag) ([]byte, MessageRejectError) {
 
 // GetBool is a GetField wrapper for bool fields.
 func (m FieldMap) GetBool(tag Tag) (bool, MessageRejectError) {
-	var val FIXBoolean
-	if err := m.GetField(tag, &val); err != nil {
+	bytes, err := m.GetBytes(tag)
+	if err != nil {
 		return false, err
 	}
-	return bool(val), nil
+
+	var val FIXBoolean
+	if val.Read(bytes) != nil {
+		err = IncorrectDataFormatForValue(tag)
+	}
+
+	return bool(val), err
 }
 
 // GetInt is a GetField wrapper for int fields.
@@ -196,7 +253,7 @@ func (m FieldMap) GetTime(tag Tag) (t time.Time, err MessageRejectError) {
 	m.rwLock.RLock()
 	defer m.rwLock.RUnlock()
 
-	bytes, err := m.GetBytes(tag)
+	bytes, err := m.getBytesNoLock(tag)
 	if err != nil {
 		return
 	}
@@ -209,22 +266,67 @@ func (m FieldMap) GetTime(tag Tag) (t time.Time, err MessageRejectError) {
 	return val.Time, err
 }
 
+// GetFloat is a GetField wrapper for float fields.
+func (m FieldMap) GetFloat(tag Tag) (float64, MessageRejectError) {
+	bytes, err := m.GetBytes(tag)
+	if err != nil {
+		return 0, err
+	}
+
+	var val FIXFloat
+	if val.Read(bytes) != nil {
+		err = IncorrectDataFormatForValue(tag)
+	}
+
+	return float64(val), err
+}
+
+// GetDecimal is a GetField wrapper for decimal fields.
+func (m FieldMap) GetDecimal(tag Tag) (decimal.Decimal, MessageRejectError) {
+	bytes, err := m.GetBytes(tag)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	var val FIXDecimal
+	if val.Read(bytes) != nil {
+		err = IncorrectDataFormatForValue(tag)
+	}
+
+	return val.Decimal, err
+}
+
+// GetUDecimal is a GetField wrapper for udecimal fields.
+func (m FieldMap) GetUDecimal(tag Tag) (udecimal.Decimal, MessageRejectError) {
+	bytes, err := m.GetBytes(tag)
+	if err != nil {
+		return udecimal.Decimal{}, err
+	}
+
+	var val FIXUDecimal
+	if val.Read(bytes) != nil {
+		err = IncorrectDataFormatForValue(tag)
+	}
+
+	return val.Decimal, err
+}
+
 // GetString is a GetField wrapper for string fields.
 func (m FieldMap) GetString(tag Tag) (string, MessageRejectError) {
-	var val FIXString
-	if err := m.GetField(tag, &val); err != nil {
+	bytes, err := m.GetBytes(tag)
+	if err != nil {
 		return "", err
 	}
-	return string(val), nil
+	return string(bytes), nil
 }
 
 // GetString is a GetField wrapper for string fields.
 func (m FieldMap) getStringNoLock(tag Tag) (string, MessageRejectError) {
-	var val FIXString
-	if err := m.getFieldNoLock(tag, &val); err != nil {
+	bytes, err := m.getBytesNoLock(tag)
+	if err != nil {
 		return "", err
 	}
-	return string(val), nil
+	return string(bytes), nil
 }
 
 // GetGroup is a Get function specific to Group Fields.
@@ -266,8 +368,8 @@ func (m *FieldMap) SetBool(tag Tag, value bool) *FieldMap {
 
 // SetInt is a SetField wrapper for int fields.
 func (m *FieldMap) SetInt(tag Tag, value int) *FieldMap {
-	v := FIXInt(value)
-	return m.SetBytes(tag, v.Write())
+	var buf [20]byte
+	return m.SetBytes(tag, appendInt(buf[:0], value))
 }
 
 // SetString is a SetField wrapper for string fields.
@@ -281,6 +383,7 @@ func (m *FieldMap) Remove(tag Tag) {
 	defer m.rwLock.Unlock()
 
 	delete(m.tagLookup, tag)
+	m.parsed = nil
 }
 
 // Clear purges all fields from field map.
@@ -289,6 +392,7 @@ func (m *FieldMap) Clear() {
 	defer m.rwLock.Unlock()
 
 	m.tags = m.tags[0:0]
+	m.parsed = nil
 	for k := range m.tagLookup {
 		delete(m.tagLookup, k)
 	}
@@ -296,6 +400,7 @@ func (m *FieldMap) Clear() {
 
 func (m *FieldMap) clearNoLock() {
 	m.tags = m.tags[0:0]
+	m.parsed = nil
 	for k := range m.tagLookup {
 		delete(m.tagLookup, k)
 	}
@@ -315,6 +420,33 @@ func (m *FieldMap) CopyInto(to *FieldMap) {
 	to.tags = make([]Tag, len(m.tags))
 	copy(to.tags, m.tags)
 	to.compare = m.compare
+	to.parsed = nil
+	if len(m.parsed) > 0 {
+		received := m.receivedFields()
+		to.parsed = make([]TagValue, len(received))
+		for i := range received {
+			to.parsed[i].init(received[i].tag, received[i].value)
+		}
+		to.parsedSection, to.parsedDict = m.parsedSection, m.parsedDict
+	}
+}
+
+// setParsed sets the fields of the message parsed with the transport data dictionary dict, the FieldMap holding
+// its section.
+func (m *FieldMap) setParsed(fields []TagValue, section fieldSection, dict *datadictionary.DataDictionary) {
+	m.parsed, m.parsedSection, m.parsedDict = fields, section, dict
+}
+
+// receivedFields returns the fields of the section of the parsed message in the order they were received. The
+// caller holds the lock.
+func (m *FieldMap) receivedFields() []TagValue {
+	fields := make([]TagValue, 0, len(m.parsed))
+	for _, tv := range m.parsed {
+		if m.parsedSection.holds(tv.tag, m.parsedDict) {
+			fields = append(fields, tv)
+		}
+	}
+	return fields
 }
 
 func (m *FieldMap) add(f field) {
@@ -330,6 +462,7 @@ func (m *FieldMap) getOrCreate(tag Tag) field {
 	m.rwLock.Lock()
 	defer m.rwLock.Unlock()
 
+	m.parsed = nil
 	if f, ok := m.tagLookup[tag]; ok {
 		f = f[:1]
 		return f
@@ -358,12 +491,13 @@ func (m *FieldMap) SetGroup(field FieldGroupWriter) *FieldMap {
 		m.tags = append(m.tags, field.Tag())
 	}
 	m.tagLookup[field.Tag()] = field.Write()
+	m.parsed = nil
 	return m
 }
 
+// sortedTags sorts the tags in place, unless they already are, i.e. only after a tag was added since the last call.
 func (m *FieldMap) sortedTags() []Tag {
-	sort.Sort(m)
-	return m.tags
+	return m.tagSort.sorted()
 }
 
 func (m FieldMap) write(buffer *bytes.Buffer) {
@@ -377,6 +511,68 @@ func (m FieldMap) write(buffer *bytes.Buffer) {
 	}
 }
 
+// Iterate calls fn with the tag and value of each field of the FieldMap until fn returns false. The fields of a
+// parsed message come in the order they were received, repeated tags and the NumInGroup and delimiter fields of
+// repeating groups included, whether or not a data dictionary defines the groups. Once the FieldMap is modified, the
+// fields come in the order they are written.
+func (m FieldMap) Iterate(fn func(tag Tag, value []byte) bool) {
+	m.iterate(fn)
+}
+
+// All returns an iterator over the tags and values of the fields of the FieldMap, in the order of Iterate. Values
+// are not copied, and must not be modified nor retained past the next change to the FieldMap.
+//
+// Iterating allocates nothing besides the iterator returned by All. The fields of a parsed message are read from it
+// as they were received, the others in the order of their tags, which are sorted in place by the first iteration or
+// write after a field was added, later ones only checking that they still are.
+func (m FieldMap) All() iter.Seq2[Tag, []byte] {
+	return m.iterate
+}
+
+// iterate calls yield with the fields of the FieldMap in the order of Iterate until yield returns false.
+func (m *FieldMap) iterate(yield func(Tag, []byte) bool) {
+	m.rwLock.Lock()
+	parsed, section, dict := m.parsed, m.parsedSection, m.parsedDict
+	var tags []Tag
+	if len(parsed) == 0 {
+		tags = m.sortedTags()
+	}
+	m.rwLock.Unlock()
+
+	for _, tv := range parsed {
+		if section.holds(tv.tag, dict) && !yield(tv.tag, tv.value) {
+			return
+		}
+	}
+
+	for _, tag := range tags {
+		m.rwLock.RLock()
+		f := m.tagLookup[tag]
+		m.rwLock.RUnlock()
+
+		for _, tv := range f {
+			if !yield(tv.tag, tv.value) {
+				return
+			}
+		}
+	}
+}
+
+func (m FieldMap) orderedFields() []TagValue {
+	m.rwLock.Lock()
+	defer m.rwLock.Unlock()
+
+	if len(m.parsed) > 0 {
+		return m.receivedFields()
+	}
+
+	var fields []TagValue
+	for _, tag := range m.sortedTags() {
+		fields = append(fields, m.tagLookup[tag]...)
+	}
+	return fields
+}
+
 func (m FieldMap) total() int {
 	m.rwLock.RLock()
 	defer m.rwLock.RUnlock()
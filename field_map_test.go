@@ -141,6 +141,97 @@ func TestFieldMap_BoolTypedSetAndGet(t *testing.T) {
 	assert.Equal(t, "N", s)
 }
 
+func TestFieldMap_NumericTypedGet(t *testing.T) {
+	var fMap FieldMap
+	fMap.init()
+
+	fMap.SetString(1, "123.4500")
+	fMap.SetString(2, "abc")
+
+	f, err := fMap.GetFloat(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 123.45, f)
+
+	d, err := fMap.GetDecimal(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "123.45", d.String())
+
+	u, err := fMap.GetUDecimal(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "123.45", u.String())
+
+	_, err = fMap.GetFloat(2)
+	assert.NotNil(t, err, "Type mismatch should occur error")
+	_, err = fMap.GetDecimal(2)
+	assert.NotNil(t, err, "Type mismatch should occur error")
+	_, err = fMap.GetUDecimal(2)
+	assert.NotNil(t, err, "Type mismatch should occur error")
+	_, err = fMap.GetBool(2)
+	assert.NotNil(t, err, "Type mismatch should occur error")
+
+	_, err = fMap.GetDecimal(44)
+	assert.NotNil(t, err, "Missing field should occur error")
+}
+
+type benchmarkField struct {
+	FieldValue
+	tag Tag
+}
+
+func (f benchmarkField) Tag() Tag { return f.tag }
+
+// BenchmarkFieldMapCrackExecutionReport reads the fields an application typically cracks from an ExecutionReport,
+// parsing into field values as the default generated getters do, and with the typed getters of -typed-getters.
+func BenchmarkFieldMapCrackExecutionReport(b *testing.B) {
+	var fMap FieldMap
+	fMap.init()
+	fMap.SetString(11, "ORDER-1")
+	fMap.SetString(17, "EXEC-1")
+	fMap.SetString(39, "1")
+	fMap.SetString(31, "101.25")
+	fMap.SetString(32, "100")
+	fMap.SetString(14, "300")
+	fMap.SetString(60, "20240102-10:11:12.123")
+
+	b.Run("FieldParser", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, tag := range []Tag{11, 17, 39} {
+				if err := fMap.Get(benchmarkField{FieldValue: new(FIXString), tag: tag}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			for _, tag := range []Tag{31, 32, 14} {
+				if err := fMap.Get(benchmarkField{FieldValue: new(FIXDecimal), tag: tag}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if err := fMap.Get(benchmarkField{FieldValue: new(FIXUTCTimestamp), tag: 60}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("TypedGetters", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, tag := range []Tag{11, 17, 39} {
+				if _, err := fMap.GetString(tag); err != nil {
+					b.Fatal(err)
+				}
+			}
+			for _, tag := range []Tag{31, 32, 14} {
+				if _, err := fMap.GetDecimal(tag); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if _, err := fMap.GetTime(60); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 func TestFieldMap_CopyInto(t *testing.T) {
 	var fMapA FieldMap
 	fMapA.initWithOrdering(headerFieldOrdering)
@@ -202,3 +293,59 @@ func TestFieldMap_Remove(t *testing.T) {
 	assert.False(t, fMap.Has(1))
 	assert.True(t, fMap.Has(2))
 }
+
+func TestFieldMap_Iterate(t *testing.T) {
+	var fMap FieldMap
+	fMap.init()
+
+	fMap.SetField(2, FIXString("world"))
+	fMap.SetField(1, FIXString("hello"))
+	fMap.SetField(3, FIXString("!"))
+
+	var tags []Tag
+	var values []string
+	fMap.Iterate(func(tag Tag, value []byte) bool {
+		tags = append(tags, tag)
+		values = append(values, string(value))
+		return tag < 2
+	})
+	assert.Equal(t, []Tag{1, 2}, tags)
+	assert.Equal(t, []string{"hello", "world"}, values)
+}
+
+func TestFieldMap_All(t *testing.T) {
+	var fMap FieldMap
+	fMap.init()
+
+	fMap.SetField(2, FIXString("world"))
+	fMap.SetField(1, FIXString("hello"))
+
+	var tags []Tag
+	for tag, value := range fMap.All() {
+		tags = append(tags, tag)
+		fMap.SetBytes(tag, append([]byte("re"), value...))
+	}
+	assert.Equal(t, []Tag{1, 2}, tags)
+
+	s, err := fMap.GetString(2)
+	assert.Nil(t, err)
+	assert.Equal(t, "reworld", s)
+}
+
+// BenchmarkFieldMapIterate iterates the fields of a FieldMap built by the application, in tag order.
+func BenchmarkFieldMapIterate(b *testing.B) {
+	var fMap FieldMap
+	fMap.init()
+	for _, tag := range []Tag{60, 11, 17, 39, 31, 32, 14} {
+		fMap.SetString(tag, "value")
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		fMap.Iterate(func(Tag, []byte) bool {
+			count++
+			return true
+		})
+	}
+}
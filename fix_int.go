@@ -17,7 +17,6 @@ package quickfix
 
 import (
 	"errors"
-	"strconv"
 )
 
 const (
@@ -74,5 +73,5 @@ func (f *FIXInt) Read(bytes []byte) error {
 }
 
 func (f FIXInt) Write() []byte {
-	return strconv.AppendInt(nil, int64(f), 10)
+	return appendInt(nil, int(f))
 }
@@ -0,0 +1,107 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// smallIntCount bounds the ints formatted from smallInts, covering the tags of the FIX specifications and the
+// sequence numbers of a session's first messages.
+const smallIntCount = 10000
+
+// smallInts holds the decimal digits of the ints below smallIntCount, four per int, padded with leading zeros.
+var smallInts [smallIntCount * 4]byte
+
+func init() {
+	for i := 0; i < smallIntCount; i++ {
+		n := i
+		for j := 3; j >= 0; j-- {
+			smallInts[i*4+j] = byte(ascii0 + n%10)
+			n /= 10
+		}
+	}
+}
+
+// appendInt appends the decimal representation of v to dst, like strconv.AppendInt.
+func appendInt(dst []byte, v int) []byte {
+	if v < 0 || v >= smallIntCount {
+		return strconv.AppendInt(dst, int64(v), 10)
+	}
+
+	width := 4
+	switch {
+	case v < 10:
+		width = 1
+	case v < 100:
+		width = 2
+	case v < 1000:
+		width = 3
+	}
+	return append(dst, smallInts[v*4+4-width:v*4+4]...)
+}
+
+// appendCheckSum appends the three digit representation of a CheckSum value to dst.
+func appendCheckSum(dst []byte, checkSum int) []byte {
+	return append(dst, smallInts[checkSum*4+1:checkSum*4+4]...)
+}
+
+// timestampCache formats the SendingTime of the messages of a session, reusing the date and time of day formatted
+// for the second of the previous message.
+type timestampCache struct {
+	mu     sync.Mutex
+	valid  bool
+	second int64
+	prefix [len(utcTimestampSecondsFormat)]byte
+}
+
+// append appends t in UTC to dst, formatted with precision.
+func (c *timestampCache) append(dst []byte, t time.Time, precision TimestampPrecision) []byte {
+	t = t.UTC()
+
+	c.mu.Lock()
+	if second := t.Unix(); !c.valid || second != c.second {
+		t.AppendFormat(c.prefix[:0], utcTimestampSecondsFormat)
+		c.second = second
+		c.valid = true
+	}
+	dst = append(dst, c.prefix[:]...)
+	c.mu.Unlock()
+
+	switch precision {
+	case Seconds:
+		return dst
+	case Micros:
+		return appendFraction(dst, t.Nanosecond()/int(time.Microsecond), 6)
+	case Nanos:
+		return appendFraction(dst, t.Nanosecond(), 9)
+	}
+	return appendFraction(dst, t.Nanosecond()/int(time.Millisecond), 3)
+}
+
+// appendFraction appends the fraction of second v to dst, with digits digits.
+func appendFraction(dst []byte, v, digits int) []byte {
+	var buf [9]byte
+	for i := digits - 1; i >= 0; i-- {
+		buf[i] = byte(ascii0 + v%10)
+		v /= 10
+	}
+
+	dst = append(dst, '.')
+	return append(dst, buf[:digits]...)
+}
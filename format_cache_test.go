@@ -0,0 +1,86 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendInt(t *testing.T) {
+	for _, v := range []int{0, 7, 10, 99, 100, 999, 1000, 9999, 10000, 123456789, -1, -10000} {
+		assert.Equal(t, strconv.Itoa(v), string(appendInt([]byte("x"), v))[1:])
+	}
+}
+
+func TestAppendCheckSum(t *testing.T) {
+	assert.Equal(t, "007", string(appendCheckSum(nil, 7)))
+	assert.Equal(t, "042", string(appendCheckSum(nil, 42)))
+	assert.Equal(t, "255", string(appendCheckSum(nil, 255)))
+}
+
+func TestTimestampCache(t *testing.T) {
+	var c timestampCache
+	local := time.FixedZone("UTC+2", 2*60*60)
+
+	for _, tm := range []time.Time{
+		time.Unix(0, 0),
+		time.Date(2026, 10, 16, 9, 30, 1, 5_006_007, local),
+		time.Date(2026, 10, 16, 9, 30, 1, 999_999_999, local),
+		time.Date(2026, 10, 16, 9, 30, 2, 0, local),
+	} {
+		for _, precision := range []TimestampPrecision{Seconds, Millis, Micros, Nanos} {
+			expected := FIXUTCTimestamp{Time: tm, Precision: precision}.Write()
+			assert.Equal(t, string(expected), string(c.append(nil, tm, precision)))
+		}
+	}
+}
+
+func TestSessionInsertSendingTime(t *testing.T) {
+	sendingTime := time.Date(2026, 10, 16, 9, 30, 1, 5_006_007, time.UTC)
+	s := &Session{
+		sessionID:          SessionID{BeginString: BeginStringFIX44},
+		clock:              fixedClock{sendingTime},
+		timestampPrecision: Micros,
+	}
+
+	msg := NewMessage()
+	s.insertSendingTime(msg)
+	value, err := msg.Header.GetString(tagSendingTime)
+	assert.NoError(t, err)
+	assert.Equal(t, "20261016-09:30:01.005006", value)
+
+	s.sessionID.BeginString = BeginStringFIX41
+	s.insertSendingTime(msg)
+	value, err = msg.Header.GetString(tagSendingTime)
+	assert.NoError(t, err)
+	assert.Equal(t, "20261016-09:30:01", value)
+}
+
+func BenchmarkSessionHeartbeatHeader(b *testing.B) {
+	s := &Session{sessionID: SessionID{BeginString: BeginStringFIX44, SenderCompID: "SENDER", TargetCompID: "TARGET"}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := NewMessage()
+		msg.Header.SetField(tagMsgType, FIXString("0"))
+		s.insertSendingTime(msg)
+		msg.Header.SetInt(tagMsgSeqNum, i)
+		_ = msg.Build()
+	}
+}
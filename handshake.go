@@ -0,0 +1,179 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// HandshakePolicy is a proprietary handshake required by a counterparty after logon, such as a
+// UserRequest/UserResponse exchange, before application messages may flow. See SetHandshakePolicy.
+//
+// While the handshake is pending the session is logged on but the application cannot send, getting
+// ErrHandshakePending, and incoming application messages go to the HandshakePolicy instead of FromApp.
+// OnLogon is called once the handshake completes. The session logs out if the handshake fails or does not
+// complete within HandshakeTimeout.
+type HandshakePolicy interface {
+	// StartHandshake is called after each logon. Handshake messages are sent with send. Returning done completes
+	// the handshake immediately, returning an error fails it.
+	StartHandshake(sessionID SessionID, send func(msg Messagable) error) (done bool, err error)
+
+	// OnHandshakeMessage is called with each application message received while the handshake is pending.
+	// Returning done completes the handshake, returning an error fails it.
+	OnHandshakeMessage(msg *Message, sessionID SessionID) (done bool, err error)
+}
+
+// handshake is the HandshakePolicy of a session and the state of its current handshake.
+type handshake struct {
+	mu     sync.Mutex
+	policy HandshakePolicy
+
+	// pending is read by the application goroutines sending messages.
+	pending  atomic.Bool
+	err      error
+	deadline time.Time
+}
+
+func (h *handshake) get() HandshakePolicy {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.policy
+}
+
+func (h *handshake) reset() {
+	h.pending.Store(false)
+	h.err = nil
+}
+
+// SetHandshakePolicy sets the HandshakePolicy of the Session matching the Session id, taking effect at the next
+// logon. A nil policy removes it.
+func SetHandshakePolicy(sessionID SessionID, policy HandshakePolicy) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.handshake.mu.Lock()
+	defer session.handshake.mu.Unlock()
+	session.handshake.policy = policy
+	return nil
+}
+
+// handshakeState is logged on, waiting for the HandshakePolicy to complete.
+type handshakeState struct{ inSession }
+
+func (state handshakeState) String() string { return "Handshake" }
+
+func (state handshakeState) FixMsgIn(session *Session, msg *Message) sessionState {
+	nextState := state.inSession.FixMsgIn(session, msg)
+	if _, ok := nextState.(inSession); !ok {
+		return nextState
+	}
+	return session.handshakeState()
+}
+
+func (state handshakeState) Timeout(session *Session, event internal.Event) (nextState sessionState) {
+	nextState = state.inSession.Timeout(session, event)
+	if _, ok := nextState.(inSession); ok {
+		return state
+	}
+	return nextState
+}
+
+type handshakeError string
+
+func (e handshakeError) Error() string { return string(e) }
+
+const errHandshakeTimeout = handshakeError("Timed out waiting for handshake")
+
+// startHandshake starts the handshake of the HandshakePolicy after logon, if any, returning the next state.
+func (s *Session) startHandshake() sessionState {
+	s.handshake.reset()
+	policy := s.handshake.get()
+	if policy == nil {
+		return inSession{}
+	}
+
+	s.log.OnEvent("Starting handshake")
+	s.handshake.pending.Store(true)
+	done, err := policy.StartHandshake(s.sessionID, func(msg Messagable) error {
+		return s.queueForSend(msg.ToMessage())
+	})
+	s.onHandshakeResult(done, err)
+	if s.handshake.pending.Load() {
+		s.handshake.deadline = s.now().Add(s.HandshakeTimeout)
+		time.AfterFunc(s.HandshakeTimeout, func() { s.sessionEvent <- internal.HandshakeTimeout })
+	}
+	return s.handshakeState()
+}
+
+// checkHandshakeTimeout fails the pending handshake once HandshakeTimeout has elapsed, whatever the logged on state.
+func (s *Session) checkHandshakeTimeout(now time.Time) (sessionState, bool) {
+	if !s.handshake.pending.Load() || !s.IsLoggedOn() || now.Before(s.handshake.deadline) {
+		return nil, false
+	}
+	if s.handshake.err == nil {
+		s.handshake.err = errHandshakeTimeout
+	}
+	return s.handshakeState(), true
+}
+
+// handshakeMessage passes an application message received while the handshake is pending to the HandshakePolicy.
+func (s *Session) handshakeMessage(msg *Message) {
+	policy := s.handshake.get()
+	if policy == nil {
+		s.onHandshakeResult(true, nil)
+		return
+	}
+	s.onHandshakeResult(policy.OnHandshakeMessage(msg, s.sessionID))
+}
+
+func (s *Session) onHandshakeResult(done bool, err error) {
+	if !s.handshake.pending.Load() {
+		return
+	}
+
+	switch {
+	case err != nil:
+		s.handshake.err = err
+	case done:
+		s.handshake.pending.Store(false)
+		s.log.OnEvent("Handshake complete")
+		s.application.OnLogon(s.sessionID)
+		s.notifyLogon()
+	}
+}
+
+// handshakeState returns the state following the handshake: in session once complete, logging out on failure.
+func (s *Session) handshakeState() sessionState {
+	if err := s.handshake.err; err != nil {
+		s.handshake.reset()
+		s.log.OnEventf("Handshake failed: %v", err)
+		if err := s.initiateLogout(err.Error()); err != nil {
+			return handleStateError(s, err)
+		}
+		return logoutState{}
+	}
+
+	if s.handshake.pending.Load() {
+		return handshakeState{}
+	}
+	return inSession{}
+}
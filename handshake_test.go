@@ -0,0 +1,173 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type mockHandshakePolicy struct {
+	start     func(send func(msg Messagable) error) (bool, error)
+	onMessage func(msg *Message) (bool, error)
+}
+
+func (p *mockHandshakePolicy) StartHandshake(_ SessionID, send func(msg Messagable) error) (bool, error) {
+	return p.start(send)
+}
+
+func (p *mockHandshakePolicy) OnHandshakeMessage(msg *Message, _ SessionID) (bool, error) {
+	return p.onMessage(msg)
+}
+
+type HandshakeTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestHandshakeTestSuite(t *testing.T) {
+	suite.Run(t, new(HandshakeTestSuite))
+}
+
+func (s *HandshakeTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = logonState{}
+	s.Session.HandshakeTimeout = time.Minute
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *HandshakeTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *HandshakeTestSuite) logon(policy HandshakePolicy) {
+	s.Require().Nil(SetHandshakePolicy(s.sessionID, policy))
+
+	s.IncrNextSenderMsgSeqNum()
+	s.MessageFactory.seqNum = 1
+	s.IncrNextTargetMsgSeqNum()
+
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.MockApp.On("ToApp").Return(nil)
+	s.fixMsgIn(s.Session, logon)
+}
+
+func (s *HandshakeTestSuite) TestSetHandshakePolicySessionNotFound() {
+	s.Equal(ErrSessionNotFound, SetHandshakePolicy(SessionID{BeginString: "FIX.4.4", SenderCompID: "x", TargetCompID: "y"}, nil))
+}
+
+func (s *HandshakeTestSuite) TestCompletesOnMessage() {
+	var received *Message
+	s.logon(&mockHandshakePolicy{
+		start: func(send func(msg Messagable) error) (bool, error) {
+			msg := NewMessage()
+			msg.Header.SetField(tagMsgType, FIXString("BE"))
+			return false, send(msg)
+		},
+		onMessage: func(msg *Message) (bool, error) {
+			received = msg
+			return true, nil
+		},
+	})
+
+	s.State(handshakeState{})
+	s.MockApp.AssertNotCalled(s.T(), "OnLogon")
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 1)
+	s.True(s.Session.IsLoggedOn())
+	s.Equal(ErrHandshakePending, s.Session.checkCanSend())
+
+	s.MockApp.On("OnLogon")
+	msg := s.NewOrderSingle()
+	s.fixMsgIn(s.Session, msg)
+
+	s.State(inSession{})
+	s.MockApp.AssertNumberOfCalls(s.T(), "OnLogon", 1)
+	s.MockApp.AssertNotCalled(s.T(), "FromApp")
+	s.Equal(msg, received)
+	s.Nil(s.Session.checkCanSend())
+}
+
+func (s *HandshakeTestSuite) TestCompletesOnStart() {
+	s.MockApp.On("OnLogon")
+	s.logon(&mockHandshakePolicy{
+		start: func(func(msg Messagable) error) (bool, error) { return true, nil },
+	})
+
+	s.State(inSession{})
+	s.MockApp.AssertNumberOfCalls(s.T(), "OnLogon", 1)
+	s.Nil(s.Session.checkCanSend())
+}
+
+func (s *HandshakeTestSuite) TestFailsOnMessage() {
+	s.logon(&mockHandshakePolicy{
+		start:     func(func(msg Messagable) error) (bool, error) { return false, nil },
+		onMessage: func(*Message) (bool, error) { return false, errors.New("bad credentials") },
+	})
+	s.State(handshakeState{})
+
+	s.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.State(logoutState{})
+	s.MockApp.AssertNotCalled(s.T(), "OnLogon")
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "bad credentials", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *HandshakeTestSuite) TestTimeout() {
+	s.logon(&mockHandshakePolicy{
+		start: func(func(msg Messagable) error) (bool, error) { return false, nil },
+	})
+	s.State(handshakeState{})
+
+	s.Session.Timeout(s.Session, internal.HandshakeTimeout)
+	s.State(handshakeState{})
+
+	s.Session.handshake.deadline = time.Now()
+	s.Session.Timeout(s.Session, internal.HandshakeTimeout)
+
+	s.State(logoutState{})
+	s.MockApp.AssertNotCalled(s.T(), "OnLogon")
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, string(errHandshakeTimeout), s.MockApp.lastToAdmin.Body)
+}
+
+func (s *HandshakeTestSuite) TestHeartbeatKeepsHandshakeState() {
+	s.logon(&mockHandshakePolicy{
+		start: func(func(msg Messagable) error) (bool, error) { return false, nil },
+	})
+
+	s.fixMsgIn(s.Session, s.Heartbeat())
+	s.State(handshakeState{})
+}
+
+func (s *HandshakeTestSuite) TestDisconnectResetsHandshake() {
+	s.logon(&mockHandshakePolicy{
+		start: func(func(msg Messagable) error) (bool, error) { return false, nil },
+	})
+
+	s.MockApp.On("OnLogout")
+	s.Session.Disconnected(s.Session)
+	s.False(s.Session.handshake.pending.Load())
+	s.MockApp.AssertNumberOfCalls(s.T(), "OnLogout", 1)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// HealthOptions configure the criteria of a HealthCheck.
+type HealthOptions struct {
+	// Sessions are the sessions reported, all the registered sessions if empty.
+	Sessions []SessionID
+
+	// RequireLoggedOn are the sessions that must be logged on for readiness.
+	RequireLoggedOn []SessionID
+
+	// MaxSinceLastReceived is the longest a logged on session may go without receiving a message, heartbeats
+	// included, before it fails liveness. A peer is typically declared dead past 1.2 times HeartBtInt. Zero disables
+	// the check.
+	MaxSinceLastReceived time.Duration
+}
+
+// SessionHealth is the health of a session in a HealthReport.
+type SessionHealth struct {
+	SessionID string `json:"session_id"`
+	LoggedOn  bool   `json:"logged_on"`
+
+	// LastReceived is the time the last message was received, nil if none was received since startup.
+	LastReceived *time.Time `json:"last_received,omitempty"`
+
+	Ready bool `json:"ready"`
+	Live  bool `json:"live"`
+
+	// Reason explains why the session is not ready or not live.
+	Reason string `json:"reason,omitempty"`
+}
+
+// HealthReport is the health of the sessions of a HealthCheck, as served by its handlers.
+type HealthReport struct {
+	Ready    bool            `json:"ready"`
+	Live     bool            `json:"live"`
+	Sessions []SessionHealth `json:"sessions"`
+}
+
+// HealthCheck reports the FIX connectivity of sessions, for Kubernetes readiness and liveness probes.
+type HealthCheck struct {
+	opts HealthOptions
+	now  func() time.Time
+}
+
+// NewHealthCheck returns a HealthCheck applying the criteria of opts.
+func NewHealthCheck(opts HealthOptions) *HealthCheck {
+	return &HealthCheck{opts: opts, now: time.Now}
+}
+
+// Report returns the current health of the sessions. A session is ready unless it must be logged on and is not,
+// and live unless it is logged on but silent for longer than MaxSinceLastReceived. A session that is not live is
+// not ready either.
+func (h *HealthCheck) Report() HealthReport {
+	required := make(map[SessionID]bool)
+	for _, sessionID := range h.opts.RequireLoggedOn {
+		required[sessionID] = true
+	}
+
+	sessionIDs := append([]SessionID(nil), h.opts.Sessions...)
+	if len(sessionIDs) == 0 {
+		sessionIDs = FindSessionIDs(nil)
+	}
+	for _, sessionID := range h.opts.RequireLoggedOn {
+		if !containsSessionID(sessionIDs, sessionID) {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+
+	report := HealthReport{Ready: true, Live: true}
+	now := h.now()
+	for _, sessionID := range sessionIDs {
+		health := h.sessionHealth(sessionID, required[sessionID], now)
+		report.Ready = report.Ready && health.Ready
+		report.Live = report.Live && health.Live
+		report.Sessions = append(report.Sessions, health)
+	}
+	return report
+}
+
+func (h *HealthCheck) sessionHealth(sessionID SessionID, requireLoggedOn bool, now time.Time) SessionHealth {
+	health := SessionHealth{SessionID: sessionID.String(), Ready: true, Live: true}
+
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		health.Ready = !requireLoggedOn
+		health.Reason = "unknown session"
+		return health
+	}
+
+	health.LoggedOn = session.health.loggedOn.Load()
+	if nanos := session.health.lastReceived.Load(); nanos != 0 {
+		lastReceived := time.Unix(0, nanos)
+		health.LastReceived = &lastReceived
+	}
+
+	switch {
+	case health.LoggedOn && h.opts.MaxSinceLastReceived > 0 && health.LastReceived != nil &&
+		now.Sub(*health.LastReceived) > h.opts.MaxSinceLastReceived:
+		health.Ready, health.Live = false, false
+		health.Reason = "no message received for " + now.Sub(*health.LastReceived).Truncate(time.Second).String()
+	case requireLoggedOn && !health.LoggedOn:
+		health.Ready = false
+		health.Reason = "not logged on"
+	}
+	return health
+}
+
+// ReadinessHandler returns a handler serving the HealthReport as JSON, with status 200 if ready and 503 otherwise.
+func (h *HealthCheck) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		report := h.Report()
+		writeHealthReport(w, report, report.Ready)
+	})
+}
+
+// LivenessHandler returns a handler serving the HealthReport as JSON, with status 200 if live and 503 otherwise.
+func (h *HealthCheck) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		report := h.Report()
+		writeHealthReport(w, report, report.Live)
+	})
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func containsSessionID(sessionIDs []SessionID, sessionID SessionID) bool {
+	for _, id := range sessionIDs {
+		if id == sessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionHealth is the state of a session read by HealthCheck from outside the session goroutine.
+type sessionHealth struct {
+	loggedOn atomic.Bool
+
+	// lastReceived is the time the last message was received, in Unix nanoseconds.
+	lastReceived atomic.Int64
+
+	// lastHeartbeatRTT is the round-trip time of the last TestRequest answered, see Session.LastHeartbeatRTT.
+	lastHeartbeatRTT atomic.Int64
+}
@@ -0,0 +1,128 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthCheckTestSuite struct {
+	SessionSuiteRig
+	now time.Time
+}
+
+func TestHealthCheckTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthCheckTestSuite))
+}
+
+func (s *HealthCheckTestSuite) SetupTest() {
+	s.Init()
+	s.now = time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *HealthCheckTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *HealthCheckTestSuite) healthCheck(opts HealthOptions) *HealthCheck {
+	h := NewHealthCheck(opts)
+	h.now = func() time.Time { return s.now }
+	return h
+}
+
+// serve returns the status and report served by handler.
+func (s *HealthCheckTestSuite) serve(handler http.Handler) (int, HealthReport) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	s.Equal("application/json", rec.Header().Get("Content-Type"))
+
+	var report HealthReport
+	s.Require().Nil(json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+func (s *HealthCheckTestSuite) TestRequireLoggedOn() {
+	h := s.healthCheck(HealthOptions{RequireLoggedOn: []SessionID{s.sessionID}})
+
+	code, report := s.serve(h.ReadinessHandler())
+	s.Equal(http.StatusServiceUnavailable, code)
+	s.False(report.Ready)
+	s.Require().Len(report.Sessions, 1)
+	s.Equal(s.sessionID.String(), report.Sessions[0].SessionID)
+	s.Equal("not logged on", report.Sessions[0].Reason)
+
+	code, report = s.serve(h.LivenessHandler())
+	s.Equal(http.StatusOK, code)
+	s.True(report.Live)
+
+	s.Session.notifyLogon()
+	code, report = s.serve(h.ReadinessHandler())
+	s.Equal(http.StatusOK, code)
+	s.True(report.Sessions[0].LoggedOn)
+
+	s.Session.notifyDisconnect()
+	code, _ = s.serve(h.ReadinessHandler())
+	s.Equal(http.StatusServiceUnavailable, code)
+}
+
+func (s *HealthCheckTestSuite) TestMaxSinceLastReceived() {
+	h := s.healthCheck(HealthOptions{MaxSinceLastReceived: 36 * time.Second})
+	s.Session.notifyLogon()
+	s.Session.health.lastReceived.Store(s.now.Add(-30 * time.Second).UnixNano())
+
+	code, report := s.serve(h.LivenessHandler())
+	s.Equal(http.StatusOK, code)
+	s.Require().NotNil(report.Sessions[0].LastReceived)
+	s.True(report.Sessions[0].LastReceived.Equal(s.now.Add(-30 * time.Second)))
+
+	s.now = s.now.Add(10 * time.Second)
+	code, report = s.serve(h.LivenessHandler())
+	s.Equal(http.StatusServiceUnavailable, code)
+	s.False(report.Live)
+	s.False(report.Ready, "a session that is not live is not ready")
+	s.Equal("no message received for 40s", report.Sessions[0].Reason)
+
+	s.Session.notifyLogout()
+	code, _ = s.serve(h.LivenessHandler())
+	s.Equal(http.StatusOK, code, "silence is expected once logged out")
+}
+
+func (s *HealthCheckTestSuite) TestUnknownSession() {
+	unknown := SessionID{BeginString: "FIX.4.2", SenderCompID: "unknown", TargetCompID: "TW"}
+
+	report := s.healthCheck(HealthOptions{Sessions: []SessionID{unknown}}).Report()
+	s.True(report.Ready)
+	s.Equal("unknown session", report.Sessions[0].Reason)
+
+	report = s.healthCheck(HealthOptions{Sessions: []SessionID{s.sessionID}, RequireLoggedOn: []SessionID{unknown}}).Report()
+	s.False(report.Ready)
+	s.Len(report.Sessions, 2)
+}
+
+func (s *HealthCheckTestSuite) TestIncomingUpdatesLastReceived() {
+	s.Session.State = inSession{}
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.incomingBatch([]fixIn{{bytes: bytes.NewBuffer(s.NewOrderSingle().Build())}})
+	s.NotZero(s.Session.health.lastReceived.Load())
+}
@@ -0,0 +1,68 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "time"
+
+// HeartbeatRTTHandler is an optional interface implemented by an Application to be alerted when the counterparty is
+// slow to answer TestRequests, see HeartbeatRTTThreshold.
+type HeartbeatRTTHandler interface {
+	// OnHeartbeatRTTExceeded is called with each round-trip time between a TestRequest and the Heartbeat answering it
+	// that exceeds the HeartbeatRTTThreshold of the session.
+	OnHeartbeatRTTExceeded(rtt time.Duration, sessionID SessionID)
+}
+
+// LastHeartbeatRTT returns the round-trip time between the last TestRequest answered by the counterparty and its
+// Heartbeat, zero if none was answered since the session was created. TestRequests are sent when the counterparty
+// is silent, and at the TestRequestInterval of the session if set. Safe to call from any goroutine.
+func (s *Session) LastHeartbeatRTT() time.Duration {
+	return time.Duration(s.health.lastHeartbeatRTT.Load())
+}
+
+// observeHeartbeatRTT records the round-trip time of the outstanding TestRequest, reporting it to the Application if
+// it exceeds the HeartbeatRTTThreshold.
+func (s *Session) observeHeartbeatRTT(rtt time.Duration) {
+	s.health.lastHeartbeatRTT.Store(int64(rtt))
+	if s.HeartbeatRTTThreshold <= 0 || rtt <= s.HeartbeatRTTThreshold {
+		return
+	}
+
+	s.log.OnEventf("Heartbeat round-trip time %v exceeds %v", rtt, s.HeartbeatRTTThreshold)
+	if handler, ok := s.application.(HeartbeatRTTHandler); ok {
+		handler.OnHeartbeatRTTExceeded(rtt, s.sessionID)
+	}
+}
+
+// checkTestRequestProbe sends a TestRequest once the TestRequestInterval has elapsed, unless one is outstanding.
+func (s *Session) checkTestRequestProbe(now time.Time) {
+	if s.TestRequestInterval <= 0 || !s.IsLoggedOn() || !s.testRequestSent.IsZero() {
+		return
+	}
+
+	if now.Before(s.nextTestRequestProbe) {
+		return
+	}
+	s.nextTestRequestProbe = now.Add(s.TestRequestInterval)
+
+	testReq := NewMessage()
+	testReq.Header.SetField(tagMsgType, FIXString("1"))
+	testReq.Body.SetField(tagTestReqID, FIXString(testRequestID))
+	if err := s.send(testReq); err != nil {
+		s.logError(err)
+		return
+	}
+	s.testRequestSent = now
+}
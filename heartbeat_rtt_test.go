@@ -0,0 +1,107 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type HeartbeatRTTTestSuite struct {
+	SessionSuiteRig
+	exceeded []time.Duration
+}
+
+func TestHeartbeatRTTTestSuite(t *testing.T) {
+	suite.Run(t, new(HeartbeatRTTTestSuite))
+}
+
+type heartbeatRTTApp struct {
+	*MockApp
+	suite *HeartbeatRTTTestSuite
+}
+
+func (a *heartbeatRTTApp) OnHeartbeatRTTExceeded(rtt time.Duration, _ SessionID) {
+	a.suite.exceeded = append(a.suite.exceeded, rtt)
+}
+
+func (s *HeartbeatRTTTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.HeartbeatRTTThreshold = 500 * time.Millisecond
+	s.Session.application = &heartbeatRTTApp{MockApp: &s.MockApp, suite: s}
+	s.exceeded = nil
+}
+
+// answer receives the Heartbeat answering the outstanding TestRequest after rtt.
+func (s *HeartbeatRTTTestSuite) answer(rtt time.Duration) {
+	heartbeat := s.Heartbeat()
+	heartbeat.Body.SetString(tagTestReqID, testRequestID)
+	in := fixIn{bytes: bytes.NewBuffer(heartbeat.Build()), receiveTime: s.Session.testRequestSent.Add(rtt)}
+	s.Session.Incoming(s.Session, in)
+}
+
+func (s *HeartbeatRTTTestSuite) TestLastHeartbeatRTT() {
+	s.Equal(time.Duration(0), s.Session.LastHeartbeatRTT())
+
+	s.MockApp.On("ToAdmin")
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.Session.Timeout(s.Session, internal.PeerTimeout)
+	s.answer(200 * time.Millisecond)
+	s.Equal(200*time.Millisecond, s.Session.LastHeartbeatRTT())
+	s.Empty(s.exceeded)
+
+	s.Session.Timeout(s.Session, internal.PeerTimeout)
+	s.answer(800 * time.Millisecond)
+	s.Equal(800*time.Millisecond, s.Session.LastHeartbeatRTT())
+	s.Equal([]time.Duration{800 * time.Millisecond}, s.exceeded)
+}
+
+func (s *HeartbeatRTTTestSuite) TestTestRequestProbe() {
+	s.Session.TestRequestInterval = time.Minute
+	s.MockApp.On("ToAdmin")
+	now := time.Now()
+
+	s.Session.checkTestRequestProbe(now)
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 1)
+	s.MessageType(string(msgTypeTestRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagTestReqID, testRequestID, s.MockApp.lastToAdmin.Body)
+	s.State(inSession{})
+
+	// No probe while one is outstanding, even once the interval has elapsed.
+	s.Session.checkTestRequestProbe(now.Add(2 * time.Minute))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 1)
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.answer(100 * time.Millisecond)
+	s.Equal(100*time.Millisecond, s.Session.LastHeartbeatRTT())
+
+	s.Session.checkTestRequestProbe(now.Add(time.Second))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 1)
+
+	s.Session.checkTestRequestProbe(now.Add(time.Minute))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 2)
+}
+
+func (s *HeartbeatRTTTestSuite) TestTestRequestProbeDisabled() {
+	s.Session.checkTestRequestProbe(time.Now())
+	s.NoMessageSent()
+}
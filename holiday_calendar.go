@@ -0,0 +1,60 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "time"
+
+// HolidayCalendar tells the days sessions stay down, such as exchange holidays, see WithHolidayCalendar.
+type HolidayCalendar interface {
+	// IsHoliday returns true if the session is down for the whole date of day, a time in the TimeZone of the session.
+	IsHoliday(sessionID SessionID, day time.Time) bool
+}
+
+// WithHolidayCalendar sets the HolidayCalendar of the sessions of the engine. On a holiday, a session is outside of
+// its session time: it logs out and does not connect until the next day in session time.
+func WithHolidayCalendar(calendar HolidayCalendar) EngineOption {
+	return func(o *engineOptions) { o.holidays = calendar }
+}
+
+// HolidayDates is a HolidayCalendar of dates in the format YYYYMMDD, applying to every session.
+type HolidayDates []string
+
+// IsHoliday returns true if the date of day is one of the dates.
+func (d HolidayDates) IsHoliday(_ SessionID, day time.Time) bool {
+	date := day.Format("20060102")
+	for _, holiday := range d {
+		if holiday == date {
+			return true
+		}
+	}
+	return false
+}
+
+// isInSessionTime returns true if now is within the SessionTime of the session, on a day that is not a holiday.
+func (s *Session) isInSessionTime(now time.Time) bool {
+	if !s.SessionTime.IsInRange(now) {
+		return false
+	}
+	if s.holidays == nil {
+		return true
+	}
+
+	loc := s.TimeZone
+	if loc == nil {
+		loc = time.UTC
+	}
+	return !s.holidays.IsHoliday(s.sessionID, now.In(loc))
+}
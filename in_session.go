@@ -22,6 +22,9 @@ import (
 	"github.com/quickfixgo/quickfix/internal"
 )
 
+// testRequestID is the TestReqID of the TestRequest sent when the counterparty is silent.
+const testRequestID = "TEST"
+
 type inSession struct{ loggedOn }
 
 func (state inSession) String() string { return "In Session" }
@@ -66,6 +69,10 @@ func (state inSession) FixMsgIn(session *Session, msg *Message) sessionState {
 func (state inSession) Timeout(session *Session, event internal.Event) (nextState sessionState) {
 	switch event {
 	case internal.NeedHeartbeat:
+		if session.heartbeatSuppressed() {
+			return state
+		}
+
 		heartBt := NewMessage()
 		heartBt.Header.SetField(tagMsgType, FIXString("0"))
 		if err := session.send(heartBt); err != nil {
@@ -74,11 +81,12 @@ func (state inSession) Timeout(session *Session, event internal.Event) (nextStat
 	case internal.PeerTimeout:
 		testReq := NewMessage()
 		testReq.Header.SetField(tagMsgType, FIXString("1"))
-		testReq.Body.SetField(tagTestReqID, FIXString("TEST"))
+		testReq.Body.SetField(tagTestReqID, FIXString(testRequestID))
 		if err := session.send(testReq); err != nil {
 			return handleStateError(session, err)
 		}
-		session.log.OnEvent("Sent test request TEST")
+		session.testRequestSent = session.now()
+		session.log.OnEvent("Sent test request " + testRequestID)
 		session.peerTimer.Reset(time.Duration(float64(1.2) * float64(session.HeartBtInt)))
 		return pendingTimeout{state}
 	}
@@ -210,6 +218,7 @@ func (state inSession) handleResendRequest(session *Session, msg *Message) (next
 	if err := state.resendMessages(session, int(beginSeqNo), endSeqNo, *msg); err != nil {
 		return handleStateError(session, err)
 	}
+	session.metrics().ResendRequestServed(session.sessionID)
 
 	if err := session.checkTargetTooLow(msg); err != nil {
 		return state
@@ -226,7 +235,8 @@ func (state inSession) handleResendRequest(session *Session, msg *Message) (next
 }
 
 func (state inSession) resendMessages(session *Session, beginSeqNo, endSeqNo int, inReplyTo Message) error {
-	if session.DisableMessagePersist {
+	// A drop copy session only sent admin messages, which are not resent.
+	if session.DisableMessagePersist || session.DropCopy {
 		return state.generateSequenceReset(session, beginSeqNo, endSeqNo+1, inReplyTo)
 	}
 
@@ -248,7 +258,7 @@ func (state inSession) resendMessages(session *Session, beginSeqNo, endSeqNo int
 		msgType, _ := msg.Header.GetBytes(tagMsgType)
 		sentMessageSeqNum, _ := msg.Header.GetInt(tagMsgSeqNum)
 
-		if isAdminMessageType(msgType) {
+		if isAdminMessageType(msgType) || session.canceled.has(sentMessageSeqNum) {
 			nextSeqNum = sentMessageSeqNum + 1
 			return nil
 		}
@@ -306,6 +316,7 @@ func (state inSession) processReject(session *Session, msg *Message, rej Message
 			nextState.messageStash = make(map[int]*Message)
 		}
 
+		msg.retained = true
 		nextState.messageStash[TypedError.ReceivedTarget] = msg
 
 		return nextState
@@ -313,10 +324,7 @@ func (state inSession) processReject(session *Session, msg *Message, rej Message
 	case targetTooLow:
 		return state.doTargetTooLow(session, msg, TypedError)
 	case incorrectBeginString:
-		if err := session.initiateLogout(rej.Error()); err != nil {
-			return handleStateError(session, err)
-		}
-		return logoutState{}
+		return state.doIncorrectBeginString(session, msg, TypedError)
 	}
 
 	switch rej.RejectReason() {
@@ -341,6 +349,30 @@ func (state inSession) processReject(session *Session, msg *Message, rej Message
 	}
 }
 
+func (state inSession) doIncorrectBeginString(session *Session, msg *Message, rej incorrectBeginString) (nextState sessionState) {
+	action := session.beginStringMismatchAction(msg, rej)
+	session.log.OnEventf("%v, %v", rej.Error(), action)
+
+	switch action {
+	case BeginStringMismatchDisconnect:
+		return latentState{}
+
+	case BeginStringMismatchReject:
+		if err := session.doReject(msg, rej); err != nil {
+			return handleStateError(session, err)
+		}
+		if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+			return handleStateError(session, err)
+		}
+		return state
+	}
+
+	if err := session.initiateLogout(rej.Error()); err != nil {
+		return handleStateError(session, err)
+	}
+	return logoutState{}
+}
+
 func (state inSession) doTargetTooLow(session *Session, msg *Message, rej targetTooLow) (nextState sessionState) {
 	var posDupFlag FIXBoolean
 	if msg.Header.Has(tagPossDupFlag) {
@@ -359,28 +391,10 @@ func (state inSession) doTargetTooLow(session *Session, msg *Message, rej target
 		return logoutState{}
 	}
 
-	if !msg.Header.Has(tagOrigSendingTime) {
-		if err := session.doReject(msg, RequiredTagMissing(tagOrigSendingTime)); err != nil {
-			return handleStateError(session, err)
-		}
-		return state
-	}
-
-	var origSendingTime FIXUTCTimestamp
-	if err := msg.Header.GetField(tagOrigSendingTime, &origSendingTime); err != nil {
-		if rejErr := session.doReject(msg, err); rejErr != nil {
-			return handleStateError(session, rejErr)
-		}
-		return state
-	}
-
-	sendingTime := new(FIXUTCTimestamp)
-	if err := msg.Header.GetField(tagSendingTime, sendingTime); err != nil {
-		return state.processReject(session, msg, err)
-	}
-
-	if sendingTime.Before(origSendingTime.Time) {
-		if err := session.doReject(msg, sendingTimeAccuracyProblem()); err != nil {
+	switch rej := session.checkPossDupOrigSendingTime(msg); {
+	case rej == nil:
+	case rej.RejectReason() == rejectReasonSendingTimeAccuracyProblem:
+		if err := session.doReject(msg, rej); err != nil {
 			return handleStateError(session, err)
 		}
 
@@ -388,6 +402,10 @@ func (state inSession) doTargetTooLow(session *Session, msg *Message, rej target
 			return handleStateError(session, err)
 		}
 		return logoutState{}
+	default:
+		if err := session.doReject(msg, rej); err != nil {
+			return handleStateError(session, err)
+		}
 	}
 
 	return state
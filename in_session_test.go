@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -44,6 +45,21 @@ func (s *InSessionTestSuite) TestPreliminary() {
 	s.True(s.Session.IsSessionTime())
 }
 
+func (s *InSessionTestSuite) TestIncomingBatch() {
+	s.MockApp.On("FromApp").Return(nil).Times(3)
+
+	var batch []fixIn
+	for i := 0; i < 3; i++ {
+		batch = append(batch, fixIn{bytes: bytes.NewBuffer(s.NewOrderSingle().Build()), receiveTime: time.Now()})
+	}
+	s.Session.incomingBatch(batch)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.Empty(s.Session.pendingIn)
+	s.State(inSession{})
+	s.NextTargetMsgSeqNum(4)
+}
+
 func (s *InSessionTestSuite) TestLogout() {
 	s.MockApp.On("FromAdmin").Return(nil)
 	s.MockApp.On("ToAdmin")
@@ -125,6 +141,44 @@ func (s *InSessionTestSuite) TestTimeoutNeedHeartbeat() {
 	s.NextSenderMsgSeqNum(2)
 }
 
+func (s *InSessionTestSuite) TestTimeoutNeedHeartbeatAfterMessageSent() {
+	var tests = []struct {
+		alwaysSendHeartbeats bool
+		sentAfterTimeout     bool
+		expectHeartbeat      bool
+	}{
+		{sentAfterTimeout: false, expectHeartbeat: true},
+		{sentAfterTimeout: true, expectHeartbeat: false},
+		{alwaysSendHeartbeats: true, sentAfterTimeout: true, expectHeartbeat: true},
+	}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.Session.AlwaysSendHeartbeats = test.alwaysSendHeartbeats
+		s.MockApp.On("ToApp").Return(nil)
+		s.MockApp.On("ToAdmin").Return(nil)
+
+		s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+		s.LastToAppMessageSent()
+
+		// The heartbeat timer fires, then a message may be sent before its event is handled.
+		s.Session.heartbeatTimeout.Store(s.Session.sentCount.Load() + 1)
+		if test.sentAfterTimeout {
+			s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+			s.LastToAppMessageSent()
+		}
+		s.Session.Timeout(s.Session, internal.NeedHeartbeat)
+
+		s.State(inSession{})
+		if test.expectHeartbeat {
+			s.LastToAdminMessageSent()
+			s.MessageType(string(msgTypeHeartbeat), s.MockApp.lastToAdmin)
+		} else {
+			s.NoMessageSent()
+		}
+	}
+}
+
 func (s *InSessionTestSuite) TestTimeoutPeerTimeout() {
 	s.MockApp.On("ToAdmin").Return(nil)
 	s.Session.Timeout(s.Session, internal.PeerTimeout)
@@ -335,6 +389,45 @@ func (s *InSessionTestSuite) TestFIXMsgInResendRequestNoMessagePersist() {
 	s.State(inSession{})
 }
 
+func (s *InSessionTestSuite) TestFIXMsgInResendRequestDropCopy() {
+	s.Session.DropCopy = true
+
+	s.MockApp.On("ToAdmin")
+	s.Session.Timeout(s.Session, internal.NeedHeartbeat)
+	s.LastToAdminMessageSent()
+	s.Session.Timeout(s.Session, internal.NeedHeartbeat)
+	s.LastToAdminMessageSent()
+	s.NextSenderMsgSeqNum(3)
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.fixMsgIn(s.Session, s.ResendRequest(1))
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 3)
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeSequenceReset), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagMsgSeqNum, 1, s.MockApp.lastToAdmin.Header)
+	s.FieldEquals(tagNewSeqNo, 3, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagGapFillFlag, true, s.MockApp.lastToAdmin.Body)
+
+	s.NextSenderMsgSeqNum(3)
+	s.State(inSession{})
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInRejectDropCopy() {
+	s.Session.DropCopy = true
+
+	s.MockApp.On("FromApp").Return(UnsupportedMessageType())
+	s.MockApp.On("ToApp").Return(nil)
+	s.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.LastToAppMessageSent()
+	s.MessageType("j", s.MockApp.lastToApp)
+	s.FieldEquals(tagRefSeqNum, 1, s.MockApp.lastToApp.Body)
+	s.NextTargetMsgSeqNum(2)
+	s.NextSenderMsgSeqNum(2)
+	s.State(inSession{})
+}
+
 func (s *InSessionTestSuite) TestFIXMsgInResendRequestDoNotSendApp() {
 	s.MockApp.On("ToAdmin")
 	s.Session.Timeout(s.Session, internal.NeedHeartbeat)
@@ -428,3 +521,141 @@ func (s *InSessionTestSuite) TestFIXMsgInTargetTooLowPossDup() {
 	s.State(inSession{})
 	s.NextTargetMsgSeqNum(2)
 }
+
+func (s *InSessionTestSuite) TestFIXMsgInPossDupInSequence() {
+	s.MockApp.On("FromApp").Return(nil)
+	nos := s.NewOrderSingle()
+	nos.Header.SetField(tagPossDupFlag, FIXBoolean(true))
+
+	s.fixMsgIn(s.Session, nos)
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 1)
+	s.NoMessageSent()
+	s.State(inSession{})
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInPossDupInSequenceReject() {
+	s.Session.origSendingTimeCheck = origSendingTimeReject
+	s.MockApp.On("ToAdmin")
+	nos := s.NewOrderSingle()
+	nos.Header.SetField(tagPossDupFlag, FIXBoolean(true))
+
+	s.fixMsgIn(s.Session, nos)
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 0)
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeReject), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagRefTagID, int(tagOrigSendingTime), s.MockApp.lastToAdmin.Body)
+	s.State(inSession{})
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInTargetTooHighOutstandingResend() {
+	s.MockApp.On("ToAdmin")
+	s.Session.resendRanges.add(1, SeqNumRange{Begin: 1, End: 2}, s.Session.now())
+
+	s.MessageFactory.seqNum = 5
+	s.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagBeginSeqNo, 3, s.MockApp.lastToAdmin.Body)
+	s.Equal([]SeqNumRange{{Begin: 1, End: 5}}, s.Session.status().OutstandingResendRanges)
+
+	resendState, ok := s.Session.State.(resendState)
+	s.True(ok)
+	s.Equal(5, resendState.resendRangeEnd)
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInTargetTooHighSuppressesRedundantResend() {
+	s.MockApp.On("ToAdmin")
+	s.Session.resendRanges.add(1, SeqNumRange{Begin: 1, End: 9}, s.Session.now())
+
+	s.MessageFactory.seqNum = 5
+	s.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.NoMessageSent()
+	s.MockApp.AssertNotCalled(s.T(), "ToAdmin", mock.Anything, mock.Anything)
+	s.Equal([]SeqNumRange{{Begin: 1, End: 9}}, s.Session.status().OutstandingResendRanges)
+
+	resendState, ok := s.Session.State.(resendState)
+	s.True(ok)
+	s.Contains(resendState.messageStash, 6)
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInTargetTooHighRequestsTimedOutRangeAgain() {
+	s.MockApp.On("ToAdmin")
+	s.Session.ResendRequestTimeout = time.Minute
+	s.Session.resendRanges.add(1, SeqNumRange{Begin: 1, End: 9}, s.Session.now().Add(-2*time.Minute))
+
+	s.MessageFactory.seqNum = 5
+	s.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagBeginSeqNo, 1, s.MockApp.lastToAdmin.Body)
+	s.Equal([]SeqNumRange{{Begin: 1, End: 9}}, s.Session.status().OutstandingResendRanges)
+}
+
+type beginStringMismatchApp struct {
+	*MockApp
+	action   BeginStringMismatchAction
+	received string
+}
+
+func (a *beginStringMismatchApp) OnBeginStringMismatch(beginString string, _ *Message, _ SessionID) BeginStringMismatchAction {
+	a.received = beginString
+	return a.action
+}
+
+func (s *InSessionTestSuite) incorrectBeginStringMessage() *Message {
+	msg := s.NewOrderSingle()
+	msg.Header.SetField(tagBeginString, FIXString(BeginStringFIX44))
+	return msg
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInIncorrectBeginString() {
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.Session, s.incorrectBeginStringMessage())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "Incorrect BeginString, expecting FIX.4.2 but received FIX.4.4", s.MockApp.lastToAdmin.Body)
+	s.State(logoutState{})
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInIncorrectBeginStringDisconnect() {
+	s.Session.beginStringMismatch = beginStringMismatchPolicy{action: BeginStringMismatchDisconnect}
+	s.MockApp.On("OnLogout")
+	s.fixMsgIn(s.Session, s.incorrectBeginStringMessage())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.NoMessageSent()
+	s.Disconnected()
+	s.State(latentState{})
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInIncorrectBeginStringReject() {
+	s.Session.beginStringMismatch = beginStringMismatchPolicy{action: BeginStringMismatchReject}
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.Session, s.incorrectBeginStringMessage())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeReject), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagRefTagID, int(tagBeginString), s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagSessionRejectReason, rejectReasonValueIsIncorrect, s.MockApp.lastToAdmin.Body)
+	s.State(inSession{})
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInIncorrectBeginStringCallback() {
+	app := &beginStringMismatchApp{MockApp: &s.MockApp, action: BeginStringMismatchReject}
+	s.Session.beginStringMismatch = beginStringMismatchPolicy{handler: app}
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.Session, s.incorrectBeginStringMessage())
+
+	s.Equal(BeginStringFIX44, app.received)
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeReject), s.MockApp.lastToAdmin)
+	s.State(inSession{})
+}
@@ -19,6 +19,8 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -34,67 +36,151 @@ type Initiator struct {
 	storeFactory    MessageStoreFactory
 	logFactory      LogFactory
 	globalLog       Log
+	dialer          proxy.ContextDialer
+	certificates    map[SessionID]*certificateReloader
 	stopChan        chan interface{}
 	wg              sync.WaitGroup
 	sessions        map[SessionID]*Session
+	sessionsLock    sync.RWMutex
+	handlers        map[SessionID]*initiatorHandler
+	reconnect       *ReconnectCoordinator
 	sessionFactory
 }
 
+// initiatorHandler stops the connection handler of a started session and reports when it has returned.
+type initiatorHandler struct {
+	stop chan interface{}
+	done chan interface{}
+}
+
 // Start Initiator.
 func (i *Initiator) Start() (err error) {
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
 	i.stopChan = make(chan interface{})
+	i.certificates = make(map[SessionID]*certificateReloader)
+	i.handlers = make(map[SessionID]*initiatorHandler)
 
 	for sessionID, settings := range i.sessionSettings {
-		// TODO: move into Session factory.
-		var tlsConfig *tls.Config
-		if tlsConfig, err = loadTLSConfig(settings); err != nil {
+		if err = i.startSession(sessionID, settings); err != nil {
 			return
 		}
+	}
+	return
+}
 
-		var dialer proxy.ContextDialer
+// startSession connects the session until the Initiator is stopped or the session is removed.
+// The caller holds sessionsLock.
+func (i *Initiator) startSession(sessionID SessionID, settings *SessionSettings) error {
+	// TODO: move into Session factory.
+	tlsConfig, certificates, err := loadReloadableTLSConfig(settings)
+	if err != nil {
+		return err
+	}
+
+	dialer := i.dialer
+	if dialer == nil {
 		if dialer, err = loadDialerConfig(settings); err != nil {
-			return
+			return err
 		}
+	}
 
-		i.wg.Add(1)
-		go func(sessID SessionID) {
-			i.handleConnection(i.sessions[sessID], tlsConfig, dialer)
-			i.wg.Done()
-		}(sessionID)
+	if certificates != nil {
+		i.certificates[sessionID] = certificates
+	}
+
+	handler := &initiatorHandler{stop: make(chan interface{}), done: make(chan interface{})}
+	i.handlers[sessionID] = handler
+
+	i.wg.Add(1)
+	go func(session *Session) {
+		i.handleConnection(session, tlsConfig, dialer, handler.stop)
+		close(handler.done)
+		i.wg.Done()
+	}(i.sessions[sessionID])
+	return nil
+}
+
+// started returns true between Start and Stop. The caller holds sessionsLock.
+func (i *Initiator) started() bool {
+	if i.stopChan == nil {
+		return false
+	}
+
+	select {
+	case <-i.stopChan:
+		return false
+	default:
+		return true
 	}
-	return
 }
 
 // Stop Initiator.
 func (i *Initiator) Stop() {
+	i.sessionsLock.Lock()
 	select {
 	case <-i.stopChan:
 		// Closed already.
+		i.sessionsLock.Unlock()
 		return
 	default:
 	}
 	close(i.stopChan)
+	for _, handler := range i.handlers {
+		close(handler.stop)
+	}
+	i.handlers = nil
+	i.sessionsLock.Unlock()
 
 	i.wg.Wait()
 
+	i.sessionsLock.RLock()
+	defer i.sessionsLock.RUnlock()
 	for sessionID := range i.sessionSettings {
-		err := UnregisterSession(sessionID)
+		err := i.sessionRegistry().unregister(sessionID)
 		if err != nil {
 			return
 		}
 	}
 }
 
-// NewInitiator creates and initializes a new Initiator.
-func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings *Settings, logFactory LogFactory) (*Initiator, error) {
+// RefreshTLS re-reads the certificate files of the SocketCertificateFile and SocketPrivateKeyFile settings of
+// each session after Start, so that reconnections use the rotated certificate. The previous certificate of a
+// session is kept if its files cannot be loaded.
+func (i *Initiator) RefreshTLS() error {
+	i.sessionsLock.RLock()
+	defer i.sessionsLock.RUnlock()
+
+	var errs []error
+	for sessionID, certificates := range i.certificates {
+		session := i.sessions[sessionID]
+		if err := certificates.reload(); err != nil {
+			session.log.OnEventf("Failed to refresh TLS certificate: %v", err)
+			errs = append(errs, fmt.Errorf("%v: %w", sessionID, err))
+			continue
+		}
+		session.log.OnEvent("Refreshed TLS certificate")
+	}
+	return errors.Join(errs...)
+}
+
+// NewInitiator creates and initializes a new Initiator. A nil storeFactory or logFactory defaults to an in-memory
+// store or a null log, unless set with WithStoreFactory or WithLogFactory.
+func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings *Settings, logFactory LogFactory, opts ...EngineOption) (*Initiator, error) {
+	o := newEngineOptions(storeFactory, logFactory, opts)
+	storeFactory, logFactory = o.storeFactory, o.logFactory
+
 	i := &Initiator{
 		app:             app,
 		storeFactory:    storeFactory,
 		settings:        appSettings,
 		sessionSettings: appSettings.SessionSettings(),
 		logFactory:      logFactory,
+		dialer:          o.dialer,
+		reconnect:       o.reconnectCoordinator,
 		sessions:        make(map[SessionID]*Session),
-		sessionFactory:  sessionFactory{true},
+		sessionFactory:  sessionFactory{BuildInitiators: true, metrics: o.metrics, clock: o.clock, tracer: o.tracer, seqNumPublisher: o.seqNumPublisher, recorder: o.recorder, holidays: o.holidays, fieldCrypter: o.fieldCrypter, rawDecoders: o.rawDecoders, registry: o.registry},
 	}
 
 	var err error
@@ -103,6 +189,7 @@ func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings
 		return i, err
 	}
 
+	preloadDataDictionaries(i.sessionSettings)
 	for sessionID, s := range i.sessionSettings {
 		session, err := i.createSession(sessionID, storeFactory, s, logFactory, app)
 		if err != nil {
@@ -115,8 +202,73 @@ func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings
 	return i, nil
 }
 
+// AddSession creates a session for settings, overlaying the global settings of the Initiator, and connects it
+// if the Initiator has been started. The message store and log of the session are created with the factories of
+// the Initiator. Returns the SessionID of the new session.
+func (i *Initiator) AddSession(settings *SessionSettings) (SessionID, error) {
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
+	sessionID, err := i.settings.AddSession(settings)
+	if err != nil {
+		return sessionID, err
+	}
+
+	sessionSettings := i.settings.overlaySessionSettings(settings)
+	session, err := i.createSession(sessionID, i.storeFactory, sessionSettings, i.logFactory, i.app)
+	if err != nil {
+		i.settings.removeSession(sessionID)
+		return sessionID, err
+	}
+
+	i.sessionSettings[sessionID] = sessionSettings
+	i.sessions[sessionID] = session
+
+	if i.started() {
+		if err = i.startSession(sessionID, sessionSettings); err != nil {
+			i.discardSession(sessionID)
+			_ = session.close()
+			return sessionID, err
+		}
+	}
+
+	return sessionID, nil
+}
+
+// RemoveSession logs out and disconnects the session, waits for its connection handler to return, then
+// unregisters the session and closes its message store and log. Returns ErrSessionNotFound if the Initiator has no
+// session for sessionID.
+func (i *Initiator) RemoveSession(sessionID SessionID) error {
+	i.sessionsLock.Lock()
+	session, ok := i.sessions[sessionID]
+	if !ok {
+		i.sessionsLock.Unlock()
+		return ErrSessionNotFound
+	}
+	handler := i.handlers[sessionID]
+	i.discardSession(sessionID)
+	i.sessionsLock.Unlock()
+
+	if handler != nil {
+		close(handler.stop)
+		<-handler.done
+	}
+
+	return session.close()
+}
+
+// discardSession forgets and unregisters the session. The caller holds sessionsLock.
+func (i *Initiator) discardSession(sessionID SessionID) {
+	delete(i.sessions, sessionID)
+	delete(i.sessionSettings, sessionID)
+	delete(i.certificates, sessionID)
+	delete(i.handlers, sessionID)
+	i.settings.removeSession(sessionID)
+	_ = i.sessionRegistry().unregister(sessionID)
+}
+
 // waitForInSessionTime returns true if the Session is in Session, false if the handler should stop.
-func (i *Initiator) waitForInSessionTime(session *Session) bool {
+func (i *Initiator) waitForInSessionTime(session *Session, stopChan <-chan interface{}) bool {
 	inSessionTime := make(chan interface{})
 	go func() {
 		session.waitForInSessionTime()
@@ -125,7 +277,7 @@ func (i *Initiator) waitForInSessionTime(session *Session) bool {
 
 	select {
 	case <-inSessionTime:
-	case <-i.stopChan:
+	case <-stopChan:
 		return false
 	}
 
@@ -133,17 +285,17 @@ func (i *Initiator) waitForInSessionTime(session *Session) bool {
 }
 
 // waitForReconnectInterval returns true if a reconnect should be re-attempted, false if handler should stop.
-func (i *Initiator) waitForReconnectInterval(reconnectInterval time.Duration) bool {
+func (i *Initiator) waitForReconnectInterval(reconnectInterval time.Duration, stopChan <-chan interface{}) bool {
 	select {
 	case <-time.After(reconnectInterval):
-	case <-i.stopChan:
+	case <-stopChan:
 		return false
 	}
 
 	return true
 }
 
-func (i *Initiator) handleConnection(session *Session, tlsConfig *tls.Config, dialer proxy.ContextDialer) {
+func (i *Initiator) handleConnection(session *Session, tlsConfig *tls.Config, dialer proxy.ContextDialer, stopChan <-chan interface{}) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -156,20 +308,38 @@ func (i *Initiator) handleConnection(session *Session, tlsConfig *tls.Config, di
 		wg.Wait()
 	}()
 
-	connectionAttempt := 0
+	endpoints := newConnectEndpoints(session)
 
 	for {
-		if !i.waitForInSessionTime(session) {
+		if !i.waitForInSessionTime(session, stopChan) {
 			return
 		}
 
+		index, backoff := endpoints.pick(session.now())
+		address := session.SocketConnectAddress[index]
+		if backoff > 0 {
+			session.log.OnEventf("All endpoints backed off, connecting to %v in %v", address, backoff)
+			if !i.waitForReconnectInterval(backoff, stopChan) {
+				return
+			}
+		}
+
+		if i.reconnect != nil {
+			if delay := i.reconnect.reserve(session.reconnectGroup(address), session.now()); delay > 0 {
+				session.log.OnEventf("Connection attempt delayed %v by the ReconnectCoordinator", delay)
+				if !i.waitForReconnectInterval(delay, stopChan) {
+					return
+				}
+			}
+		}
+
 		ctx, cancel := context.WithCancel(context.Background())
 
 		// We start a goroutine in order to be able to cancel the dialer mid-connection
 		// on receiving a stop signal to stop the initiator.
 		go func() {
 			select {
-			case <-i.stopChan:
+			case <-stopChan:
 				cancel()
 			case <-ctx.Done():
 				return
@@ -177,10 +347,10 @@ func (i *Initiator) handleConnection(session *Session, tlsConfig *tls.Config, di
 		}()
 
 		var disconnected chan interface{}
-		var msgIn chan fixIn
+		var msgIn chan []fixIn
 		var msgOut chan []byte
+		var connected bool
 
-		address := session.SocketConnectAddress[connectionAttempt%len(session.SocketConnectAddress)]
 		session.log.OnEventf("Connecting to: %v", address)
 
 		netConn, err := dialer.DialContext(ctx, "tcp", address)
@@ -205,17 +375,20 @@ func (i *Initiator) handleConnection(session *Session, tlsConfig *tls.Config, di
 			netConn = tlsConn
 		}
 
-		msgIn = make(chan fixIn, session.InChanCapacity)
+		msgIn = make(chan []fixIn, session.InChanCapacity)
 		msgOut = make(chan []byte)
 		if err := session.connect(msgIn, msgOut); err != nil {
 			session.log.OnEventf("Failed to initiate: %v", err)
 			goto reconnect
 		}
+		connected = true
+		endpoints.connected(index)
+		session.onConnectEndpoint(address, index)
 
-		go readLoop(newParser(bufio.NewReader(netConn)), msgIn, session.log)
+		go readLoop(newDecodingParser(bufio.NewReader(netConn), session.rawDecoder), msgIn, session.InBatchSize, session.log)
 		disconnected = make(chan interface{})
 		go func() {
-			writeLoop(netConn, msgOut, session.log)
+			writeLoop(session.egressWriter(netConn), msgOut, session.log)
 			if err := netConn.Close(); err != nil {
 				session.log.OnEvent(err.Error())
 			}
@@ -228,17 +401,46 @@ func (i *Initiator) handleConnection(session *Session, tlsConfig *tls.Config, di
 
 		select {
 		case <-disconnected:
-		case <-i.stopChan:
+		case <-stopChan:
 			return
 		}
 
 	reconnect:
 		cancel()
 
-		connectionAttempt++
-		session.log.OnEventf("Reconnecting in %v", session.ReconnectInterval)
-		if !i.waitForReconnectInterval(session.ReconnectInterval) {
+		if !connected {
+			endpoints.failed(index, session.now())
+		}
+		if lockout, locked := session.logonRejectLockout(); locked {
+			if lockout == 0 {
+				session.log.OnEventf("Logon rejected %v times, no longer reconnecting", session.logonRejects.get())
+				return
+			}
+
+			session.log.OnEventf("Logon rejected %v times, reconnecting in %v", session.logonRejects.get(), lockout)
+			if !i.waitForReconnectInterval(lockout, stopChan) {
+				return
+			}
+			session.logonRejects.reset()
+			continue
+		}
+
+		delay := session.reconnectDelay()
+		session.log.OnEventf("Reconnecting in %v", delay)
+		if !i.waitForReconnectInterval(delay, stopChan) {
 			return
 		}
 	}
 }
+
+// sessionList returns the sessions of the initiator.
+func (i *Initiator) sessionList() []*Session {
+	i.sessionsLock.RLock()
+	defer i.sessionsLock.RUnlock()
+
+	sessions := make([]*Session, 0, len(i.sessions))
+	for _, session := range i.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
@@ -0,0 +1,91 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitiatorAddRemoveSession(t *testing.T) {
+	conns := make(chan net.Conn, 1)
+	dialer := dialerFunc(func(_ context.Context, _, _ string) (net.Conn, error) {
+		client, server := net.Pipe()
+		conns <- server
+		return client, nil
+	})
+
+	app := &MockApp{}
+	app.On("ToAdmin")
+	app.On("OnLogout").Maybe()
+
+	settings := NewSettings()
+	initiator, err := NewInitiator(app, nil, settings, nil, WithDialer(dialer))
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "added")
+	sessionSettings.Set(config.TargetCompID, "target")
+	sessionSettings.Set(config.HeartBtInt, "30")
+	sessionSettings.Set(config.SocketConnectHost, "venue")
+	sessionSettings.Set(config.SocketConnectPort, "5001")
+
+	sessionID, err := initiator.AddSession(sessionSettings)
+	require.NoError(t, err)
+	_, err = GetSession(sessionID)
+	require.NoError(t, err)
+
+	var conn net.Conn
+	select {
+	case conn = <-conns:
+	case <-time.After(5 * time.Second):
+		t.Fatal("added session not connected")
+	}
+
+	logon := make([]byte, 1024)
+	n, err := conn.Read(logon)
+	require.NoError(t, err)
+	assert.Contains(t, string(logon[:n]), "\x0135=A\x01")
+
+	closed := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		close(closed)
+	}()
+
+	require.NoError(t, initiator.RemoveSession(sessionID))
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("removed session not disconnected")
+	}
+
+	_, err = GetSession(sessionID)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.Empty(t, settings.SessionSettings())
+	assert.ErrorIs(t, initiator.RemoveSession(sessionID), ErrSessionNotFound)
+}
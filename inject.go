@@ -0,0 +1,84 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrInjectionDisabled is returned by InjectInbound unless AllowInboundInjection is set for the Session.
+var ErrInjectionDisabled = errors.New("inbound injection disabled")
+
+// InjectOptions configures a message injected with InjectInbound.
+type InjectOptions struct {
+	// BypassSeqNum leaves the next target sequence number unchanged once the message is processed. Otherwise the
+	// message consumes it as if sent by the counterparty, whose message of that sequence number is then too low.
+	BypassSeqNum bool
+}
+
+type injectReq struct {
+	msg  *Message
+	opts InjectOptions
+	rep  chan<- error
+}
+
+// InjectInbound processes a synthetic message as if received from the counterparty, e.g. to simulate an
+// ExecutionReport of the venue in a drill. The header of the message is set as the counterparty would: its
+// CompIDs, SendingTime and MsgSeqNum, the next target sequence number. The message goes through the state machine
+// of the Session like any received message, so that it is validated, rejected to the counterparty if invalid, and
+// passed to FromApp or FromAdmin. Returns ErrInjectionDisabled unless AllowInboundInjection is set, and
+// ErrNotLoggedOn if the Session is not logged on.
+func (s *Session) InjectInbound(m Messagable, opts InjectOptions) error {
+	if !s.AllowInboundInjection {
+		return ErrInjectionDisabled
+	}
+
+	rep := make(chan error, 1)
+	s.admin <- injectReq{msg: m.ToMessage(), opts: opts, rep: rep}
+	return <-rep
+}
+
+func (s *Session) injectInbound(req injectReq) error {
+	if !s.IsLoggedOn() {
+		return ErrNotLoggedOn
+	}
+
+	seqNum := s.store.NextTargetMsgSeqNum()
+	msg := req.msg
+	msg.Header.SetString(tagBeginString, s.sessionID.BeginString)
+	msg.Header.SetString(tagSenderCompID, s.sessionID.TargetCompID)
+	optionallySetID(msg, tagSenderSubID, s.sessionID.TargetSubID)
+	optionallySetID(msg, tagSenderLocationID, s.sessionID.TargetLocationID)
+	msg.Header.SetString(tagTargetCompID, s.sessionID.SenderCompID)
+	optionallySetID(msg, tagTargetSubID, s.sessionID.SenderSubID)
+	optionallySetID(msg, tagTargetLocationID, s.sessionID.SenderLocationID)
+	s.insertSendingTime(msg)
+	msg.Header.SetInt(tagMsgSeqNum, seqNum)
+
+	in := fixIn{bytes: bytes.NewBuffer(msg.Build()), receiveTime: s.now()}
+	s.log.OnEventf("Injecting inbound message: %q", in.bytes.Bytes())
+	s.recordInbound([]fixIn{in})
+
+	s.targetMutex.Lock()
+	defer s.targetMutex.Unlock()
+	s.Incoming(s, in)
+
+	if req.opts.BypassSeqNum {
+		return s.store.SetNextTargetMsgSeqNum(seqNum)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InjectTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestInjectTestSuite(t *testing.T) {
+	suite.Run(t, new(InjectTestSuite))
+}
+
+func (s *InjectTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.admin = make(chan interface{})
+	s.Session.AllowInboundInjection = true
+}
+
+// inject injects msg, processing the request as the session goroutine would.
+func (s *InjectTestSuite) inject(msg Messagable, opts InjectOptions) error {
+	go func() { s.Session.onAdmin(<-s.Session.admin) }()
+	return s.Session.InjectInbound(msg, opts)
+}
+
+func (s *InjectTestSuite) TestInjectDisabled() {
+	s.Session.AllowInboundInjection = false
+	s.ErrorIs(s.Session.InjectInbound(s.NewOrderSingle(), InjectOptions{}), ErrInjectionDisabled)
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 0)
+}
+
+func (s *InjectTestSuite) TestInjectNotLoggedOn() {
+	s.Session.State = latentState{}
+	s.ErrorIs(s.inject(s.NewOrderSingle(), InjectOptions{}), ErrNotLoggedOn)
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 0)
+	s.NextTargetMsgSeqNum(1)
+}
+
+func (s *InjectTestSuite) TestInjectConsumesSeqNum() {
+	s.Require().Nil(s.MockStore.SetNextTargetMsgSeqNum(5))
+	s.MockApp.On("FromApp").Return(nil)
+
+	order := NewMessage()
+	order.Header.SetField(tagMsgType, FIXString("D"))
+	s.Require().Nil(s.inject(order, InjectOptions{}))
+
+	s.MockApp.AssertExpectations(s.T())
+	s.FieldEquals(tagSenderCompID, "TW", s.MockApp.lastFromApp.Header)
+	s.FieldEquals(tagTargetCompID, "ISLD", s.MockApp.lastFromApp.Header)
+	s.FieldEquals(tagMsgSeqNum, 5, s.MockApp.lastFromApp.Header)
+	s.NoMessageSent()
+	s.NextTargetMsgSeqNum(6)
+}
+
+func (s *InjectTestSuite) TestInjectBypassSeqNum() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.Require().Nil(s.inject(s.NewOrderSingle(), InjectOptions{BypassSeqNum: true}))
+
+	s.MockApp.AssertExpectations(s.T())
+	s.FieldEquals(tagMsgSeqNum, 1, s.MockApp.lastFromApp.Header)
+	s.NoMessageSent()
+	s.NextTargetMsgSeqNum(1)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "sync"
+
+// InboundInterceptor inspects the inbound application messages of a session, see RegisterInboundInterceptor.
+type InboundInterceptor interface {
+	// InterceptInbound is called synchronously for each inbound application message, after validation and before
+	// FromApp. Returning a non-nil Rejection rejects msg: FromApp is not called and the engine replies with the
+	// Rejection. The message is processed either way, the next expected MsgSeqNum is incremented.
+	InterceptInbound(msg *Message, sessionID SessionID) *Rejection
+}
+
+// Rejection is the business level rejection of an inbound application message by an InboundInterceptor.
+type Rejection struct {
+	// Reason is the BusinessRejectReason(380) of the BusinessMessageReject, e.g. 0 for other.
+	Reason int
+
+	// Text explains the rejection, set in the Text(58) of the BusinessMessageReject.
+	Text string
+
+	// BusinessRejectRefID is the BusinessRejectRefID(379) of the BusinessMessageReject, e.g. the ClOrdID of the
+	// message rejected. Omitted if empty.
+	BusinessRejectRefID string
+
+	// Reply, if set, is sent instead of a BusinessMessageReject, e.g. an ExecutionReport rejecting an order built
+	// from a template of the application. Its header is filled in by the session as for any message sent.
+	Reply Messagable
+}
+
+// RegisterInboundInterceptor adds an InboundInterceptor to the Session matching the Session id. InboundInterceptors
+// run in registration order, the first Rejection rejecting the message.
+func RegisterInboundInterceptor(sessionID SessionID, interceptor InboundInterceptor) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.interceptors.Lock()
+	defer session.interceptors.Unlock()
+	session.interceptors.list = append(session.interceptors.list, interceptor)
+	return nil
+}
+
+// inboundInterceptors are the InboundInterceptors of a session.
+type inboundInterceptors struct {
+	sync.Mutex
+	list []InboundInterceptor
+}
+
+// intercept runs the InboundInterceptors on msg, returning the first Rejection.
+func (i *inboundInterceptors) intercept(msg *Message, sessionID SessionID) *Rejection {
+	i.Lock()
+	defer i.Unlock()
+
+	for _, interceptor := range i.list {
+		if rejection := interceptor.InterceptInbound(msg, sessionID); rejection != nil {
+			return rejection
+		}
+	}
+	return nil
+}
+
+// interceptInbound runs the InboundInterceptors of the session on msg. A Rejection without Reply is returned as a
+// business reject, while the Reply of a Rejection is sent in reply to msg.
+func (s *Session) interceptInbound(msg *Message) (rejected bool, reject MessageRejectError) {
+	rejection := s.interceptors.intercept(msg, s.sessionID)
+	if rejection == nil {
+		return false, nil
+	}
+
+	reject = NewBusinessMessageRejectErrorWithRefID(rejection.Text, rejection.Reason, rejection.BusinessRejectRefID, nil)
+	s.saveDeadLetter(msg, reject)
+	if rejection.Reply == nil {
+		return true, reject
+	}
+
+	s.log.OnEventf("Message Rejected: %v", rejection.Text)
+	if err := s.sendInReplyTo(rejection.Reply.ToMessage(), msg); err != nil {
+		s.logError(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InterceptorTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(InterceptorTestSuite))
+}
+
+type interceptorFunc func(msg *Message, sessionID SessionID) *Rejection
+
+func (f interceptorFunc) InterceptInbound(msg *Message, sessionID SessionID) *Rejection {
+	return f(msg, sessionID)
+}
+
+func (s *InterceptorTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *InterceptorTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *InterceptorTestSuite) TestAccepted() {
+	var intercepted []SessionID
+	s.Require().Nil(RegisterInboundInterceptor(s.sessionID, interceptorFunc(func(_ *Message, sessionID SessionID) *Rejection {
+		intercepted = append(intercepted, sessionID)
+		return nil
+	})))
+
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.Equal([]SessionID{s.sessionID}, intercepted)
+	s.NoMessageSent()
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *InterceptorTestSuite) TestBusinessReject() {
+	s.Require().Nil(RegisterInboundInterceptor(s.sessionID, interceptorFunc(func(msg *Message, _ SessionID) *Rejection {
+		clOrdID, _ := msg.Body.GetString(tagClOrdID)
+		return &Rejection{Reason: 5, Text: "Unknown account", BusinessRejectRefID: clOrdID}
+	})))
+	s.Require().Nil(RegisterInboundInterceptor(s.sessionID, interceptorFunc(func(*Message, SessionID) *Rejection {
+		s.Fail("the first rejection stops the interceptors")
+		return nil
+	})))
+
+	s.MockApp.On("ToApp").Return(nil)
+	order := s.NewOrderSingle()
+	order.Body.SetString(tagClOrdID, "order1")
+	s.Session.fixMsgIn(s.Session, order)
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 0)
+	s.LastToAppMessageSent()
+	s.MessageType("j", s.MockApp.lastToApp)
+	s.FieldEquals(tagBusinessRejectReason, 5, s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagBusinessRejectRefID, "order1", s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagText, "Unknown account", s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagRefMsgType, "D", s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagRefSeqNum, 1, s.MockApp.lastToApp.Body)
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *InterceptorTestSuite) TestReply() {
+	s.Require().Nil(RegisterInboundInterceptor(s.sessionID, interceptorFunc(func(msg *Message, _ SessionID) *Rejection {
+		report := NewMessage()
+		report.Header.SetField(tagMsgType, FIXString("8"))
+		report.Body.SetField(tagClOrdID, FIXString("order1"))
+		report.Body.SetField(Tag(39), FIXString("8"))
+		return &Rejection{Text: "Market closed", Reply: report}
+	})))
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, s.NewOrderSingle())
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 0)
+	s.LastToAppMessageSent()
+	s.MessageType("8", s.MockApp.lastToApp)
+	s.FieldEquals(Tag(39), "8", s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagTargetCompID, s.sessionID.TargetCompID, s.MockApp.lastToApp.Header)
+	s.NextTargetMsgSeqNum(2)
+	s.NextSenderMsgSeqNum(2)
+}
+
+func (s *InterceptorTestSuite) TestSessionNotFound() {
+	s.Equal(ErrSessionNotFound, RegisterInboundInterceptor(SessionID{BeginString: "FIX.4.2"}, interceptorFunc(nil)))
+}
@@ -12,4 +12,6 @@ const (
 	LogonTimeout
 	// LogoutTimeout indicates the peer has not sent a logout request.
 	LogoutTimeout
+	// HandshakeTimeout indicates the post-logon handshake has not completed.
+	HandshakeTimeout
 )
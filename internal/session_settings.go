@@ -10,9 +10,11 @@ type SessionSettings struct {
 	ResetOnDisconnect            bool
 	HeartBtInt                   time.Duration
 	HeartBtIntOverride           bool
+	AlwaysSendHeartbeats         bool
 	SessionTime                  *TimeRange
 	InitiateLogon                bool
 	ResendRequestChunkSize       int
+	ResendRequestTimeout         time.Duration
 	EnableLastMsgSeqNumProcessed bool
 	EnableNextExpectedMsgSeqNum  bool
 	SkipCheckLatency             bool
@@ -22,6 +24,37 @@ type SessionSettings struct {
 	ResetSeqTime                 time.Time
 	EnableResetSeqTime           bool
 	InChanCapacity               int
+	InBatchSize                  int
+	MaxQueuedMessages            int
+	ReuseIncomingMessages        bool
+	AckTimeout                   time.Duration
+	SeqNumCheckpointInterval     time.Duration
+	SeqNumDriftThreshold         int
+	TestRequestInterval          time.Duration
+	HeartbeatRTTThreshold        time.Duration
+	SeqNumPublishInterval        time.Duration
+	SeqNumPublishMessageCount    int
+	SnapshotRate                 int
+	CrashDumpPath                string
+
+	// Validate outgoing admin messages against the data dictionaries.
+	ValidateOutgoingAdminMessages bool
+
+	// Allow synthetic inbound messages to be injected, for drills.
+	AllowInboundInjection bool
+
+	// Application MsgTypes held for approval, "*" for all.
+	ApprovalMsgTypes map[string]bool
+	ApprovalTimeout  time.Duration
+
+	// Receive application messages without ever sending any.
+	DropCopy bool
+
+	// Wait for a HandshakePolicy to complete after logon.
+	HandshakeTimeout time.Duration
+
+	// Arbitrary key=value labels for observability.
+	SessionLabels map[string]string
 
 	// Required on logon for FIX.T.1 messages.
 	DefaultApplVerID string
@@ -31,4 +64,21 @@ type SessionSettings struct {
 	LogoutTimeout        time.Duration
 	LogonTimeout         time.Duration
 	SocketConnectAddress []string
+
+	// Fail back to the first SocketConnectAddress rather than round-robin, see SocketConnectFailover.
+	SocketConnectPriority bool
+	// Zero unless failed addresses are backed off.
+	SocketConnectBackoffMax time.Duration
+
+	// Zero unless reconnections back off exponentially, see ReconnectBackoffBase.
+	ReconnectBackoffBase time.Duration
+	ReconnectBackoffMax  time.Duration
+	// Percentage either way the reconnect delay is randomized by.
+	ReconnectJitter int
+	// Group of the session for the ReconnectCoordinator, the host connected to if empty.
+	ReconnectGroup string
+
+	// Negative for unlimited retries.
+	MaxLogonRejectRetries int
+	LogonRejectLockout    time.Duration
 }
@@ -232,3 +232,71 @@ func (s *StoreTestSuite) TestMessageStoreCreationTime() {
 	s.Require().True(s.MsgStore.CreationTime().After(t0))
 	s.Require().True(s.MsgStore.CreationTime().Before(t1))
 }
+
+func (s *StoreTestSuite) TestDeadLetterStore() {
+	store, ok := s.MsgStore.(quickfix.DeadLetterStore)
+	if !ok {
+		s.T().Skip("MessageStore does not implement DeadLetterStore")
+	}
+
+	// Given two dead letters
+	receivedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	first, err := store.SaveDeadLetter(quickfix.DeadLetter{MsgType: "D", MsgSeqNum: 2, RawMessage: "8=FIX.4.2\x0135=D\x01", Reason: "Unsupported Message Type", RejectReason: 3, ReceivedAt: receivedAt})
+	s.Require().Nil(err)
+	second, err := store.SaveDeadLetter(quickfix.DeadLetter{MsgType: "F", MsgSeqNum: 3, Reason: "Unknown ID"})
+	s.Require().Nil(err)
+	s.NotEqual(first, second)
+
+	// When the store is reset, then the dead letters are kept
+	s.Require().Nil(s.MsgStore.Reset())
+	letters, err := store.DeadLetters()
+	s.Require().Nil(err)
+	s.Require().Len(letters, 2)
+	s.Equal(first, letters[0].ID)
+	s.Equal("8=FIX.4.2\x0135=D\x01", letters[0].RawMessage)
+	s.Equal(3, letters[0].RejectReason)
+	s.True(receivedAt.Equal(letters[0].ReceivedAt))
+	s.Equal(second, letters[1].ID)
+
+	// When a dead letter is deleted
+	s.Require().Nil(store.DeleteDeadLetter(first))
+	s.Equal(quickfix.ErrDeadLetterNotFound, store.DeleteDeadLetter(first))
+
+	// Then the other one remains
+	letters, err = store.DeadLetters()
+	s.Require().Nil(err)
+	s.Require().Len(letters, 1)
+	s.Equal(second, letters[0].ID)
+
+	// And new dead letters are not given a previous ID
+	third, err := store.SaveDeadLetter(quickfix.DeadLetter{MsgType: "G"})
+	s.Require().Nil(err)
+	s.Greater(third, second)
+}
+
+func (s *StoreTestSuite) TestEpochStore() {
+	store, ok := s.MsgStore.(quickfix.EpochStore)
+	if !ok {
+		s.T().Skip("MessageStore does not implement EpochStore")
+	}
+
+	// Given a store whose session was never resumed
+	epoch, err := store.Epoch()
+	s.Require().Nil(err)
+	s.Equal(uint64(0), epoch)
+
+	// When the epoch is claimed, then it can only be claimed once
+	swapped, err := store.CompareAndSwapEpoch(0, 1)
+	s.Require().Nil(err)
+	s.True(swapped)
+	swapped, err = store.CompareAndSwapEpoch(0, 1)
+	s.Require().Nil(err)
+	s.False(swapped)
+
+	// And the claimed epoch is kept across Refresh and Reset
+	s.Require().Nil(s.MsgStore.Refresh())
+	s.Require().Nil(s.MsgStore.Reset())
+	epoch, err = store.Epoch()
+	s.Require().Nil(err)
+	s.Equal(uint64(1), epoch)
+}
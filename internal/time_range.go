@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -39,6 +41,14 @@ type TimeRange struct {
 	weekdays           []time.Weekday
 	startDay, endDay   *time.Weekday
 	loc                *time.Location
+
+	// windows are the daily time bands of a range built with NewWindowRangeInLocation.
+	windows []*TimeRange
+}
+
+// TimeWindow is a daily time band of a TimeRange made of several windows.
+type TimeWindow struct {
+	Start, End TimeOfDay
 }
 
 // NewUTCTimeRange returns a time range in UTC.
@@ -78,6 +88,39 @@ func NewWeekRangeInLocation(startTime, endTime TimeOfDay, startDay, endDay time.
 	return r, nil
 }
 
+// NewWindowRangeInLocation returns a time range made of several windows each day, in a given location. The windows
+// must not cross midnight nor overlap.
+func NewWindowRangeInLocation(windows []TimeWindow, weekdays []time.Weekday, loc *time.Location) (*TimeRange, error) {
+	if len(windows) == 0 {
+		return nil, errors.New("time: no windows in call to NewWindowRangeInLocation")
+	}
+
+	sorted := append([]TimeWindow(nil), windows...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.d < sorted[j].Start.d })
+
+	r := &TimeRange{loc: loc}
+	for i, w := range sorted {
+		if w.Start.d >= w.End.d {
+			return nil, errors.Errorf("time: window %v-%v crosses midnight", w.Start, w.End)
+		}
+		if i > 0 && w.Start.d <= sorted[i-1].End.d {
+			return nil, errors.Errorf("time: window %v-%v overlaps %v-%v", w.Start, w.End, sorted[i-1].Start, sorted[i-1].End)
+		}
+
+		window, err := NewTimeRangeInLocation(w.Start, w.End, weekdays, loc)
+		if err != nil {
+			return nil, err
+		}
+		r.windows = append(r.windows, window)
+	}
+	return r, nil
+}
+
+// String returns the time of day in the format HH:MM:SS.
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.hour, t.minute, t.second)
+}
+
 func (r *TimeRange) isInWeekdays(day time.Weekday) bool {
 	if len(r.weekdays) > 0 {
 		found := false
@@ -171,6 +214,15 @@ func (r *TimeRange) IsInRange(t time.Time) bool {
 		return true
 	}
 
+	if r.windows != nil {
+		for _, window := range r.windows {
+			if window.isInTimeRange(t) {
+				return true
+			}
+		}
+		return false
+	}
+
 	if r.startDay != nil {
 		return r.isInWeekRange(t)
 	}
@@ -188,6 +240,13 @@ func (r *TimeRange) IsInSameRange(t1, t2 time.Time) bool {
 		return false
 	}
 
+	// The windows of a day are a single session.
+	if r.windows != nil {
+		y1, m1, d1 := t1.In(r.loc).Date()
+		y2, m2, d2 := t2.In(r.loc).Date()
+		return y1 == y2 && m1 == m2 && d1 == d2
+	}
+
 	if t2.Before(t1) {
 		t1, t2 = t2, t1
 	}
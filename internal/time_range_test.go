@@ -1044,3 +1044,89 @@ func TestTimeRangeIsInSameRangeWhenNil(t *testing.T) {
 	var tr *TimeRange
 	assert.True(t, tr.IsInSameRange(time1, time2), "always in same range if time range is nil")
 }
+
+func TestNewWindowRangeInLocation(t *testing.T) {
+	r, err := NewWindowRangeInLocation([]TimeWindow{
+		{Start: NewTimeOfDay(13, 0, 0), End: NewTimeOfDay(16, 0, 0)},
+		{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(11, 30, 0)},
+	}, []time.Weekday{time.Monday}, time.UTC)
+	assert.Nil(t, err)
+	assert.Len(t, r.windows, 2)
+	assert.Equal(t, NewTimeOfDay(9, 0, 0), r.windows[0].startTime, "windows are sorted")
+
+	_, err = NewWindowRangeInLocation([]TimeWindow{{Start: NewTimeOfDay(22, 0, 0), End: NewTimeOfDay(2, 0, 0)}}, nil, time.UTC)
+	assert.EqualError(t, err, "time: window 22:00:00-02:00:00 crosses midnight")
+
+	_, err = NewWindowRangeInLocation([]TimeWindow{
+		{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(12, 0, 0)},
+		{Start: NewTimeOfDay(11, 0, 0), End: NewTimeOfDay(16, 0, 0)},
+	}, nil, time.UTC)
+	assert.EqualError(t, err, "time: window 11:00:00-16:00:00 overlaps 09:00:00-12:00:00")
+
+	_, err = NewWindowRangeInLocation(nil, nil, time.UTC)
+	assert.NotNil(t, err)
+
+	_, err = NewWindowRangeInLocation([]TimeWindow{{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(12, 0, 0)}}, nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestWindowRangeIsInRange(t *testing.T) {
+	// A morning and an afternoon window, Monday to Friday.
+	r, err := NewWindowRangeInLocation([]TimeWindow{
+		{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(11, 30, 0)},
+		{Start: NewTimeOfDay(13, 0, 0), End: NewTimeOfDay(15, 0, 0)},
+	}, []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, time.UTC)
+	assert.Nil(t, err)
+
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	assert.False(t, r.IsInRange(monday.Add(8*time.Hour+59*time.Minute)))
+	assert.True(t, r.IsInRange(monday.Add(9*time.Hour)))
+	assert.True(t, r.IsInRange(monday.Add(11*time.Hour+30*time.Minute)))
+	assert.False(t, r.IsInRange(monday.Add(12*time.Hour)), "lunch break")
+	assert.True(t, r.IsInRange(monday.Add(14*time.Hour)))
+	assert.False(t, r.IsInRange(monday.Add(15*time.Hour+time.Second)))
+	assert.False(t, r.IsInRange(monday.AddDate(0, 0, -1).Add(10*time.Hour)), "Sunday")
+
+	assert.True(t, r.IsInSameRange(monday.Add(10*time.Hour), monday.Add(14*time.Hour)), "the windows of a day are one session")
+	assert.False(t, r.IsInSameRange(monday.Add(14*time.Hour), monday.AddDate(0, 0, 1).Add(10*time.Hour)))
+	assert.False(t, r.IsInSameRange(monday.Add(10*time.Hour), monday.Add(12*time.Hour)))
+}
+
+func TestWindowRangeAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	r, err := NewWindowRangeInLocation([]TimeWindow{
+		{Start: NewTimeOfDay(9, 30, 0), End: NewTimeOfDay(12, 0, 0)},
+		{Start: NewTimeOfDay(13, 0, 0), End: NewTimeOfDay(16, 0, 0)},
+	}, nil, loc)
+	assert.Nil(t, err)
+
+	// 2024-03-10 clocks go forward: 09:30 is 14:30 UTC on Friday the 8th, and 13:30 UTC on Monday the 11th.
+	assert.False(t, r.IsInRange(time.Date(2024, 3, 8, 14, 29, 0, 0, time.UTC)))
+	assert.True(t, r.IsInRange(time.Date(2024, 3, 8, 14, 30, 0, 0, time.UTC)))
+	assert.False(t, r.IsInRange(time.Date(2024, 3, 11, 13, 29, 0, 0, time.UTC)))
+	assert.True(t, r.IsInRange(time.Date(2024, 3, 11, 13, 30, 0, 0, time.UTC)))
+
+	// 2024-11-03 clocks go back: 16:00 is 20:00 UTC on Friday the 1st, and 21:00 UTC on Monday the 4th.
+	assert.True(t, r.IsInRange(time.Date(2024, 11, 1, 20, 0, 0, 0, time.UTC)))
+	assert.False(t, r.IsInRange(time.Date(2024, 11, 1, 20, 1, 0, 0, time.UTC)))
+	assert.True(t, r.IsInRange(time.Date(2024, 11, 4, 21, 0, 0, 0, time.UTC)))
+}
+
+func TestWeekRangeAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	// Sunday 17:00 to Friday 17:00 New York time, spanning the weekend clocks go forward.
+	r, err := NewWeekRangeInLocation(NewTimeOfDay(17, 0, 0), NewTimeOfDay(17, 0, 0), time.Sunday, time.Friday, loc)
+	assert.Nil(t, err)
+
+	assert.True(t, r.IsInRange(time.Date(2024, 3, 8, 21, 59, 0, 0, time.UTC)), "Friday 16:59 EST")
+	assert.False(t, r.IsInRange(time.Date(2024, 3, 8, 22, 1, 0, 0, time.UTC)), "Friday 17:01 EST")
+	assert.False(t, r.IsInRange(time.Date(2024, 3, 10, 20, 59, 0, 0, time.UTC)), "Sunday 16:59 EDT")
+	assert.True(t, r.IsInRange(time.Date(2024, 3, 10, 21, 0, 0, 0, time.UTC)), "Sunday 17:00 EDT")
+
+	assert.True(t, r.IsInSameRange(time.Date(2024, 3, 10, 21, 0, 0, 0, time.UTC), time.Date(2024, 3, 15, 20, 59, 0, 0, time.UTC)))
+	assert.False(t, r.IsInSameRange(time.Date(2024, 3, 8, 21, 0, 0, 0, time.UTC), time.Date(2024, 3, 10, 21, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,23 @@
+package internal
+
+import "time"
+
+// TradingDayLayout is the layout of the dates returned by TradingDay.Date.
+const TradingDayLayout = "20060102"
+
+// TradingDay is the trading date of sessions whose window starts every day at Start in Loc. A window spanning
+// midnight, e.g. 17:00 to 17:00, has a single trading date, the date it started on.
+type TradingDay struct {
+	Start TimeOfDay
+	Loc   *time.Location
+}
+
+// Date returns the trading date of t, the date in Loc of the latest window start at or before t.
+func (d TradingDay) Date(t time.Time) string {
+	t = t.In(d.Loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), d.Start.hour, d.Start.minute, d.Start.second, 0, d.Loc)
+	if t.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start.Format(TradingDayLayout)
+}
@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradingDayDate(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.Nil(t, err)
+
+	midnight := TradingDay{Loc: ny}
+	assert.Equal(t, "20240301", midnight.Date(time.Date(2024, 3, 1, 23, 59, 0, 0, ny)))
+	assert.Equal(t, "20240302", midnight.Date(time.Date(2024, 3, 2, 0, 0, 0, 0, ny)))
+	assert.Equal(t, "20240302", midnight.Date(time.Date(2024, 3, 2, 5, 30, 0, 0, time.UTC)), "computed in Loc")
+
+	evening := TradingDay{Start: NewTimeOfDay(17, 0, 0), Loc: ny}
+	assert.Equal(t, "20240229", evening.Date(time.Date(2024, 3, 1, 16, 59, 59, 0, ny)))
+	assert.Equal(t, "20240301", evening.Date(time.Date(2024, 3, 1, 17, 0, 0, 0, ny)))
+	assert.Equal(t, "20240301", evening.Date(time.Date(2024, 3, 2, 0, 1, 0, 0, ny)), "the window spans midnight")
+	assert.Equal(t, "20240301", evening.Date(time.Date(2024, 3, 2, 16, 59, 0, 0, ny)))
+	assert.Equal(t, "20240309", evening.Date(time.Date(2024, 3, 10, 12, 0, 0, 0, ny)), "across a DST change")
+}
@@ -0,0 +1,433 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// JSONCodec converts messages to and from the FIX JSON encoding. A message is an object with Header, Body and
+// Trailer members, each mapping field names to string values and repeating groups, named after their NumInGroup
+// field, to arrays of objects:
+//
+//	{"Header": {"BeginString": "FIX.4.4", "MsgType": "W", ...},
+//	 "Body": {"Symbol": "EUR/USD", "NoMDEntries": [{"MDEntryType": "0", "MDEntryPx": "1.1"}, ...]},
+//	 "Trailer": {"CheckSum": "042"}}
+//
+// Fields not defined by the data dictionaries are named by their tag number.
+type JSONCodec struct {
+	// AppDataDictionary defines the body fields, and the header and trailer fields unless TransportDataDictionary is
+	// set. Required.
+	AppDataDictionary *datadictionary.DataDictionary
+
+	// TransportDataDictionary defines the header and trailer fields of FIXT sessions.
+	TransportDataDictionary *datadictionary.DataDictionary
+
+	// EnumDescriptions renders enumerated values by their description, e.g. "Side": "BUY" rather than "1".
+	// Descriptions are accepted when decoding either way.
+	EnumDescriptions bool
+}
+
+// ToJSON encodes msg in the FIX JSON encoding using dict, see JSONCodec.
+func ToJSON(msg *Message, dict *datadictionary.DataDictionary) ([]byte, error) {
+	return JSONCodec{AppDataDictionary: dict}.Marshal(msg)
+}
+
+// FromJSON decodes a message in the FIX JSON encoding using dict, see JSONCodec.
+func FromJSON(data []byte, dict *datadictionary.DataDictionary) (*Message, error) {
+	return JSONCodec{AppDataDictionary: dict}.Unmarshal(data)
+}
+
+var errNoAppDataDictionary = errors.New("JSONCodec requires an AppDataDictionary")
+
+func (c JSONCodec) transportDataDictionary() *datadictionary.DataDictionary {
+	if c.TransportDataDictionary != nil {
+		return c.TransportDataDictionary
+	}
+	return c.AppDataDictionary
+}
+
+// messageDef returns the definition of msgType, and the data dictionary defining it. Session level messages are
+// defined by the TransportDataDictionary of FIXT sessions.
+func (c JSONCodec) messageDef(msgType string) (*datadictionary.DataDictionary, *datadictionary.MessageDef) {
+	if def, ok := c.AppDataDictionary.Messages[msgType]; ok {
+		return c.AppDataDictionary, def
+	}
+	transport := c.transportDataDictionary()
+	return transport, transport.Messages[msgType]
+}
+
+// fieldDefs returns the fields of def, which may be nil.
+func fieldDefs(def *datadictionary.MessageDef) map[int]*datadictionary.FieldDef {
+	if def == nil {
+		return nil
+	}
+	return def.Fields
+}
+
+// Marshal encodes msg. Repeating groups must be defined by the data dictionaries for the MsgType of msg.
+func (c JSONCodec) Marshal(msg *Message) ([]byte, error) {
+	if c.AppDataDictionary == nil {
+		return nil, errNoAppDataDictionary
+	}
+
+	msgType, err := msg.Header.GetString(tagMsgType)
+	if err != nil {
+		return nil, err
+	}
+	transport := c.transportDataDictionary()
+	bodyDict, msgDef := c.messageDef(msgType)
+	if msgDef == nil {
+		return nil, fmt.Errorf("unknown MsgType %v", msgType)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"Header":`)
+	if err := c.encodeFieldMap(&buf, transport, fieldDefs(transport.Header), &msg.Header.FieldMap); err != nil {
+		return nil, err
+	}
+	buf.WriteString(`,"Body":`)
+	if err := c.encodeFieldMap(&buf, bodyDict, msgDef.Fields, &msg.Body.FieldMap); err != nil {
+		return nil, err
+	}
+	buf.WriteString(`,"Trailer":`)
+	if err := c.encodeFieldMap(&buf, transport, fieldDefs(transport.Trailer), &msg.Trailer.FieldMap); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (c JSONCodec) encodeFieldMap(buf *bytes.Buffer, dict *datadictionary.DataDictionary, defs map[int]*datadictionary.FieldDef, m *FieldMap) error {
+	m.rwLock.RLock()
+	defer m.rwLock.RUnlock()
+
+	tags := make([]Tag, 0, len(m.tags))
+	for _, tag := range m.tags {
+		if _, ok := m.tagLookup[tag]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return m.compare(tags[i], tags[j]) })
+
+	buf.WriteByte('{')
+	for i, tag := range tags {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		c.encodeName(buf, dict, tag)
+
+		f := m.tagLookup[tag]
+		if def, ok := defs[int(tag)]; ok && def.IsGroup() {
+			rest, err := c.encodeGroup(buf, dict, def, f[1:])
+			if err != nil {
+				return err
+			}
+			if len(rest) > 0 {
+				return fmt.Errorf("field %v is not part of repeating group %v", rest[0].tag, tag)
+			}
+			continue
+		}
+		if len(f) > 1 {
+			return fmt.Errorf("repeating group %v is not defined", tag)
+		}
+		c.encodeValue(buf, dict, f[0])
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// encodeGroup encodes the entries of the repeating group def found at the start of tvs, returning the TagValues
+// following the group.
+func (c JSONCodec) encodeGroup(buf *bytes.Buffer, dict *datadictionary.DataDictionary, def *datadictionary.FieldDef, tvs []TagValue) ([]TagValue, error) {
+	children := make(map[Tag]*datadictionary.FieldDef, len(def.Fields))
+	for _, child := range def.Fields {
+		children[Tag(child.Tag())] = child
+	}
+	delimiter := Tag(def.Fields[0].Tag())
+
+	buf.WriteByte('[')
+	inEntry, members := false, 0
+	for len(tvs) > 0 {
+		tv := tvs[0]
+		child, ok := children[tv.tag]
+		if !ok {
+			break
+		}
+
+		switch {
+		case tv.tag == delimiter && inEntry:
+			buf.WriteString("},{")
+			members = 0
+		case tv.tag == delimiter:
+			buf.WriteByte('{')
+			inEntry = true
+		case !inEntry:
+			return nil, fmt.Errorf("repeating group %v does not start with its delimiter %v", def.Tag(), delimiter)
+		}
+
+		if members > 0 {
+			buf.WriteByte(',')
+		}
+		members++
+		c.encodeName(buf, dict, tv.tag)
+
+		tvs = tvs[1:]
+		if child.IsGroup() {
+			var err error
+			if tvs, err = c.encodeGroup(buf, dict, child, tvs); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		c.encodeValue(buf, dict, tv)
+	}
+	if inEntry {
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	return tvs, nil
+}
+
+func (c JSONCodec) encodeName(buf *bytes.Buffer, dict *datadictionary.DataDictionary, tag Tag) {
+	name := strconv.Itoa(int(tag))
+	if fieldType, ok := dict.FieldTypeByTag[int(tag)]; ok {
+		name = fieldType.Name()
+	}
+	writeJSONString(buf, name)
+	buf.WriteByte(':')
+}
+
+func (c JSONCodec) encodeValue(buf *bytes.Buffer, dict *datadictionary.DataDictionary, tv TagValue) {
+	value := string(tv.value)
+	if c.EnumDescriptions {
+		if fieldType, ok := dict.FieldTypeByTag[int(tv.tag)]; ok {
+			if enum, ok := fieldType.Enums[value]; ok && enum.Description != "" {
+				value = enum.Description
+			}
+		}
+	}
+	writeJSONString(buf, value)
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	// Marshaling a string cannot fail.
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+// Unmarshal decodes a message. The BodyLength and CheckSum fields are recomputed when the message is built.
+func (c JSONCodec) Unmarshal(data []byte) (*Message, error) {
+	if c.AppDataDictionary == nil {
+		return nil, errNoAppDataDictionary
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := value.(jsonObject)
+	if !ok {
+		return nil, errors.New("message must be a JSON object")
+	}
+
+	msg := NewMessage()
+	transport := c.transportDataDictionary()
+	bodyDict := c.AppDataDictionary
+	for _, member := range root {
+		obj, ok := member.value.(jsonObject)
+		if !ok {
+			return nil, fmt.Errorf("%v must be a JSON object", member.name)
+		}
+
+		switch member.name {
+		case "Header":
+			if err = c.decodeFieldMap(&msg.Header.FieldMap, transport, obj); err == nil {
+				if msgType, typeErr := msg.Header.GetString(tagMsgType); typeErr == nil {
+					bodyDict, _ = c.messageDef(msgType)
+				}
+			}
+		case "Body":
+			err = c.decodeFieldMap(&msg.Body.FieldMap, bodyDict, obj)
+		case "Trailer":
+			err = c.decodeFieldMap(&msg.Trailer.FieldMap, transport, obj)
+		default:
+			err = fmt.Errorf("unexpected member %v", member.name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
+}
+
+func (c JSONCodec) decodeFieldMap(m *FieldMap, dict *datadictionary.DataDictionary, obj jsonObject) error {
+	for _, member := range obj {
+		tag, err := decodeTag(dict, member.name)
+		if err != nil {
+			return err
+		}
+		f, err := c.decodeField(dict, tag, member.value)
+		if err != nil {
+			return err
+		}
+
+		m.rwLock.Lock()
+		m.add(f)
+		m.rwLock.Unlock()
+	}
+	return nil
+}
+
+// decodeField returns the TagValues of the field tag, a single one unless value is a repeating group.
+func (c JSONCodec) decodeField(dict *datadictionary.DataDictionary, tag Tag, value interface{}) (field, error) {
+	switch v := value.(type) {
+	case string:
+		f := make(field, 1)
+		initField(f, tag, []byte(decodeValue(dict, tag, v)))
+		return f, nil
+
+	case []jsonObject:
+		f := make(field, 1)
+		initField(f, tag, []byte(strconv.Itoa(len(v))))
+		for _, entry := range v {
+			for _, member := range entry {
+				childTag, err := decodeTag(dict, member.name)
+				if err != nil {
+					return nil, err
+				}
+				child, err := c.decodeField(dict, childTag, member.value)
+				if err != nil {
+					return nil, err
+				}
+				f = append(f, child...)
+			}
+		}
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("field %v must be a string or an array of objects", tag)
+}
+
+func decodeTag(dict *datadictionary.DataDictionary, name string) (Tag, error) {
+	if fieldType, ok := dict.FieldTypeByName[name]; ok {
+		return Tag(fieldType.Tag()), nil
+	}
+	if tag, err := strconv.Atoi(name); err == nil && tag > 0 {
+		return Tag(tag), nil
+	}
+	return 0, fmt.Errorf("unknown field %v", name)
+}
+
+// decodeValue maps an enum description back to its value.
+func decodeValue(dict *datadictionary.DataDictionary, tag Tag, value string) string {
+	fieldType, ok := dict.FieldTypeByTag[int(tag)]
+	if !ok || len(fieldType.Enums) == 0 {
+		return value
+	}
+	if _, ok := fieldType.Enums[value]; ok {
+		return value
+	}
+	for _, enum := range fieldType.Enums {
+		if enum.Description == value {
+			return enum.Value
+		}
+	}
+	return value
+}
+
+// jsonObject is a decoded JSON object, keeping the order of its members as the order of the fields of a repeating
+// group entry matters.
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	name string
+	// value is a string, a jsonObject or a []jsonObject.
+	value interface{}
+}
+
+func decodeJSONValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			return decodeJSONMembers(dec)
+		case '[':
+			entries := []jsonObject{}
+			for dec.More() {
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				entry, ok := value.(jsonObject)
+				if !ok {
+					return nil, errors.New("repeating group entries must be JSON objects")
+				}
+				entries = append(entries, entry)
+			}
+			_, err := dec.Token()
+			return entries, err
+		}
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return string(FIXBoolean(t).Write()), nil
+	}
+
+	return nil, fmt.Errorf("unexpected JSON value %v", tok)
+}
+
+func decodeJSONMembers(dec *json.Decoder) (jsonObject, error) {
+	obj := jsonObject{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected JSON token %v", tok)
+		}
+
+		value, err := decodeJSONValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		obj = append(obj, jsonMember{name: name, value: value})
+	}
+	_, err := dec.Token()
+	return obj, err
+}
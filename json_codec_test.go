@@ -0,0 +1,153 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+type JSONCodecSuite struct {
+	suite.Suite
+	dict *datadictionary.DataDictionary
+}
+
+func TestJSONCodecSuite(t *testing.T) {
+	suite.Run(t, new(JSONCodecSuite))
+}
+
+func (s *JSONCodecSuite) SetupTest() {
+	var err error
+	s.dict, err = datadictionary.Parse("spec/FIX44.xml")
+	s.Require().Nil(err)
+}
+
+const newOrderSingleJSON = `{
+	"Header": {"BeginString": "FIX.4.4", "BodyLength": "182", "MsgType": "D", "SenderCompID": "TW", "TargetCompID": "ISLD",
+		"MsgSeqNum": "2", "SendingTime": "20231231-20:19:41"},
+	"Body": {"ClOrdID": "13976", "HandlInst": "1", "OrderQty": "100", "OrdType": "2", "Price": "12", "Side": "1",
+		"Symbol": "TSLA", "TransactTime": "20231231-20:19:41",
+		"NoPartyIDs": [
+			{"PartyID": "4501", "PartyIDSource": "D", "PartyRole": "28",
+				"NoPartySubIDs": [{"PartySubID": "desk1", "PartySubIDType": "1"}]},
+			{"PartyID": "4502", "PartyIDSource": "D", "PartyRole": "3"}
+		]},
+	"Trailer": {"CheckSum": "032"}
+}`
+
+func (s *JSONCodecSuite) TestRoundTrip() {
+	msg, err := FromJSON([]byte(newOrderSingleJSON), s.dict)
+	s.Require().Nil(err)
+
+	parsed := NewMessage()
+	s.Require().Nil(ParseMessageWithDataDictionary(parsed, bytes.NewBuffer(msg.Build()), s.dict, s.dict))
+	s.FieldEquals(tagClOrdID, "13976", parsed.Body.FieldMap)
+
+	data, err := ToJSON(parsed, s.dict)
+	s.Require().Nil(err)
+	s.JSONEq(newOrderSingleJSON, string(data))
+}
+
+func (s *JSONCodecSuite) FieldEquals(tag Tag, expected string, fm FieldMap) {
+	actual, err := fm.GetString(tag)
+	s.Require().Nil(err)
+	s.Equal(expected, actual)
+}
+
+func (s *JSONCodecSuite) TestUndefinedFieldNamedByTag() {
+	msg, err := FromJSON([]byte(`{"Header": {"MsgType": "D"}, "Body": {"Symbol": "TSLA", "20001": "custom"}}`), s.dict)
+	s.Require().Nil(err)
+	s.FieldEquals(Tag(20001), "custom", msg.Body.FieldMap)
+
+	data, err := ToJSON(msg, s.dict)
+	s.Require().Nil(err)
+	s.JSONEq(`{"Header": {"MsgType": "D"}, "Body": {"Symbol": "TSLA", "20001": "custom"}, "Trailer": {}}`, string(data))
+}
+
+func (s *JSONCodecSuite) TestEnumDescriptions() {
+	codec := JSONCodec{AppDataDictionary: s.dict, EnumDescriptions: true}
+	msg, err := codec.Unmarshal([]byte(`{"Header": {"MsgType": "NEWORDERSINGLE"}, "Body": {"Side": "BUY", "OrdType": "2"}}`))
+	s.Require().Nil(err)
+	s.FieldEquals(tagMsgType, "D", msg.Header.FieldMap)
+	s.FieldEquals(Tag(54), "1", msg.Body.FieldMap)
+	s.FieldEquals(Tag(40), "2", msg.Body.FieldMap)
+
+	data, err := codec.Marshal(msg)
+	s.Require().Nil(err)
+	s.JSONEq(`{"Header": {"MsgType": "NEWORDERSINGLE"}, "Body": {"OrdType": "LIMIT", "Side": "BUY"}, "Trailer": {}}`, string(data))
+
+	// Values are kept by default.
+	data, err = ToJSON(msg, s.dict)
+	s.Require().Nil(err)
+	s.JSONEq(`{"Header": {"MsgType": "D"}, "Body": {"OrdType": "2", "Side": "1"}, "Trailer": {}}`, string(data))
+}
+
+func (s *JSONCodecSuite) TestTransportDataDictionary() {
+	transport, err := datadictionary.Parse("spec/FIXT11.xml")
+	s.Require().Nil(err)
+	app, err := datadictionary.Parse("spec/FIX50SP2.xml")
+	s.Require().Nil(err)
+
+	codec := JSONCodec{AppDataDictionary: app, TransportDataDictionary: transport}
+	msg, err := codec.Unmarshal([]byte(`{"Header": {"BeginString": "FIXT.1.1", "MsgType": "0"}, "Body": {"TestReqID": "t1"}}`))
+	s.Require().Nil(err)
+
+	data, err := codec.Marshal(msg)
+	s.Require().Nil(err)
+	s.JSONEq(`{"Header": {"BeginString": "FIXT.1.1", "MsgType": "0"}, "Body": {"TestReqID": "t1"}, "Trailer": {}}`, string(data))
+}
+
+func (s *JSONCodecSuite) TestMarshalErrors() {
+	msg := NewMessage()
+	msg.Header.SetString(tagMsgType, "D")
+	_, err := JSONCodec{}.Marshal(msg)
+	s.Equal(errNoAppDataDictionary, err)
+
+	msg.Header.SetString(tagMsgType, "ZZ")
+	_, err = ToJSON(msg, s.dict)
+	s.NotNil(err)
+
+	// Group entries must start with the delimiter.
+	msg.Header.SetString(tagMsgType, "D")
+	group := NewRepeatingGroup(Tag(453), GroupTemplate{GroupElement(Tag(452))})
+	group.Add().SetString(Tag(452), "28")
+	msg.Body.SetGroup(group)
+	_, err = ToJSON(msg, s.dict)
+	s.NotNil(err)
+}
+
+func (s *JSONCodecSuite) TestUnmarshalErrors() {
+	for _, data := range []string{
+		`[]`,
+		`{"Header": "D"}`,
+		`{"Envelope": {}}`,
+		`{"Body": {"NoSuchField": "1"}}`,
+		`{"Body": {"Symbol": {"a": "b"}}}`,
+		`{"Body": {"NoPartyIDs": ["4501"]}}`,
+		`{"Body": {"Symbol": null}}`,
+		`{"Body": `,
+	} {
+		_, err := FromJSON([]byte(data), s.dict)
+		s.NotNil(err, data)
+	}
+
+	_, err := JSONCodec{}.Unmarshal([]byte(`{}`))
+	s.Equal(errNoAppDataDictionary, err)
+}
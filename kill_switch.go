@@ -0,0 +1,356 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CancelStatus is the state of a cancel sent by a KillSwitch.
+type CancelStatus string
+
+const (
+	// CancelPending is a cancel sent and awaiting a response.
+	CancelPending CancelStatus = "PENDING"
+	// CancelConfirmed is a cancel confirmed by the counterparty.
+	CancelConfirmed CancelStatus = "CONFIRMED"
+	// CancelRejected is a cancel rejected by the counterparty.
+	CancelRejected CancelStatus = "REJECTED"
+	// CancelFailed is a cancel that could not be sent.
+	CancelFailed CancelStatus = "FAILED"
+)
+
+// OpenOrder is an order a KillSwitch cancels individually, fed by the application with KillSwitch.AddOrder, or a
+// TIFWatchdog handles.
+type OpenOrder struct {
+	ClOrdID string
+	Symbol  string
+	Side    string
+
+	// OrderQty is optional.
+	OrderQty string
+
+	// TimeInForce is optional, a day order if empty, see TIFWatchdog.
+	TimeInForce string
+}
+
+// OrderSource provides the open orders a KillSwitch cancels individually, such as an orderstate.Tracker.
+type OrderSource interface {
+	OpenOrders(sessionID SessionID) ([]OpenOrder, error)
+}
+
+// KillSwitchCancel is a cancel sent by a KillSwitch.
+type KillSwitchCancel struct {
+	SessionID SessionID
+	ClOrdID   string
+
+	// OrigClOrdID is the order canceled, empty for an OrderMassCancelRequest.
+	OrigClOrdID string
+	Status      CancelStatus
+
+	// Text is the reason of a rejected or failed cancel.
+	Text string
+}
+
+// KillSwitchOptions configure a KillSwitch.
+type KillSwitchOptions struct {
+	// Sessions are the sessions an OrderMassCancelRequest cancelling all orders is sent through when triggered.
+	// OrderMassCancelRequest requires FIX 4.3 or later.
+	Sessions []SessionID
+
+	// PerOrder sends an OrderCancelRequest for each order added with AddOrder, through the session of the order,
+	// instead of OrderMassCancelRequests.
+	PerOrder bool
+
+	// Orders, when set, provides the orders cancelled individually through each of Sessions, in addition to
+	// those added with AddOrder.
+	Orders OrderSource
+
+	// Monitor are the sessions whose disconnect while logged on triggers the KillSwitch, emulating cancel on disconnect.
+	Monitor []SessionID
+}
+
+// KillSwitch cancels open orders when triggered, either by a call to Trigger or by the disconnect of a monitored
+// session. The responses of the counterparties are tracked, see Cancels.
+type KillSwitch struct {
+	opts KillSwitchOptions
+
+	mu       sync.Mutex
+	monitor  map[SessionID]bool
+	sessions map[SessionID]bool
+	orders   map[SessionID]map[string]OpenOrder
+	cancels  []*KillSwitchCancel
+	lastID   int
+}
+
+// NewKillSwitch returns a KillSwitch attached to the sessions of opts, which must exist.
+func NewKillSwitch(opts KillSwitchOptions) (*KillSwitch, error) {
+	k := &KillSwitch{
+		opts:     opts,
+		monitor:  make(map[SessionID]bool),
+		sessions: make(map[SessionID]bool),
+		orders:   make(map[SessionID]map[string]OpenOrder),
+	}
+
+	for _, sessionID := range opts.Monitor {
+		k.monitor[sessionID] = true
+	}
+
+	for _, sessionID := range append(append([]SessionID{}, opts.Sessions...), opts.Monitor...) {
+		if err := k.attach(sessionID); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// attach makes the session pass its disconnects and incoming messages to the KillSwitch.
+func (k *KillSwitch) attach(sessionID SessionID) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.sessions[sessionID] {
+		return nil
+	}
+
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.killSwitches.Lock()
+	session.killSwitches.switches = append(session.killSwitches.switches, k)
+	session.killSwitches.Unlock()
+	k.sessions[sessionID] = true
+	return nil
+}
+
+// AddOrder registers an open order of the session, to be canceled with an OrderCancelRequest when PerOrder is set.
+func (k *KillSwitch) AddOrder(sessionID SessionID, order OpenOrder) error {
+	if err := k.attach(sessionID); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.orders[sessionID] == nil {
+		k.orders[sessionID] = make(map[string]OpenOrder)
+	}
+	k.orders[sessionID][order.ClOrdID] = order
+	return nil
+}
+
+// RemoveOrder unregisters an order no longer open.
+func (k *KillSwitch) RemoveOrder(sessionID SessionID, clOrdID string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.orders[sessionID], clOrdID)
+}
+
+// Trigger sends the cancels. Sessions not logged on are skipped, their cancels are recorded as failed.
+func (k *KillSwitch) Trigger(reason string) {
+	k.trigger(reason, nil)
+}
+
+// trigger sends the cancels, the cancels through the disconnected session failing.
+func (k *KillSwitch) trigger(reason string, disconnected *SessionID) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	if k.opts.PerOrder {
+		orders := k.openOrders()
+		for sessionID, sessionOrders := range orders {
+			for _, order := range sessionOrders {
+				k.sendCancel(sessionID, order.ClOrdID, k.orderCancelRequest(order, now), reason, disconnected)
+			}
+		}
+		return
+	}
+
+	for _, sessionID := range k.opts.Sessions {
+		k.sendCancel(sessionID, "", k.orderMassCancelRequest(now), reason, disconnected)
+	}
+}
+
+// openOrders returns the orders added with AddOrder and provided by Orders. k.mu must be held.
+func (k *KillSwitch) openOrders() map[SessionID]map[string]OpenOrder {
+	orders := make(map[SessionID]map[string]OpenOrder)
+	for sessionID, sessionOrders := range k.orders {
+		orders[sessionID] = make(map[string]OpenOrder)
+		for clOrdID, order := range sessionOrders {
+			orders[sessionID][clOrdID] = order
+		}
+	}
+	if k.opts.Orders == nil {
+		return orders
+	}
+
+	for _, sessionID := range k.opts.Sessions {
+		sessionOrders, err := k.opts.Orders.OpenOrders(sessionID)
+		if err != nil {
+			k.cancels = append(k.cancels, &KillSwitchCancel{SessionID: sessionID, Status: CancelFailed, Text: err.Error()})
+			continue
+		}
+		if orders[sessionID] == nil {
+			orders[sessionID] = make(map[string]OpenOrder)
+		}
+		for _, order := range sessionOrders {
+			orders[sessionID][order.ClOrdID] = order
+		}
+	}
+	return orders
+}
+
+// Cancels returns the cancels sent, oldest first.
+func (k *KillSwitch) Cancels() []KillSwitchCancel {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	cancels := make([]KillSwitchCancel, len(k.cancels))
+	for i, cancel := range k.cancels {
+		cancels[i] = *cancel
+	}
+	return cancels
+}
+
+// Pending returns the number of cancels awaiting a response.
+func (k *KillSwitch) Pending() (n int) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, cancel := range k.cancels {
+		if cancel.Status == CancelPending {
+			n++
+		}
+	}
+	return
+}
+
+func (k *KillSwitch) nextClOrdID(now time.Time) string {
+	k.lastID++
+	return fmt.Sprintf("KILL-%d-%d", now.UnixNano(), k.lastID)
+}
+
+func (k *KillSwitch) orderMassCancelRequest(now time.Time) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("q"))
+	msg.Body.SetField(tagClOrdID, FIXString(k.nextClOrdID(now)))
+	msg.Body.SetField(tagMassCancelRequestType, FIXString("7")) // Cancel all orders
+	msg.Body.SetField(tagTransactTime, FIXUTCTimestamp{Time: now})
+	return msg
+}
+
+func (k *KillSwitch) orderCancelRequest(order OpenOrder, now time.Time) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("F"))
+	msg.Body.SetField(tagOrigClOrdID, FIXString(order.ClOrdID))
+	msg.Body.SetField(tagClOrdID, FIXString(k.nextClOrdID(now)))
+	msg.Body.SetField(tagSymbol, FIXString(order.Symbol))
+	msg.Body.SetField(tagSide, FIXString(order.Side))
+	msg.Body.SetField(tagTransactTime, FIXUTCTimestamp{Time: now})
+	if order.OrderQty != "" {
+		msg.Body.SetField(tagOrderQty, FIXString(order.OrderQty))
+	}
+	return msg
+}
+
+// sendCancel sends a cancel through the session, bypassing ApprovalMsgTypes. k.mu must be held.
+func (k *KillSwitch) sendCancel(sessionID SessionID, origClOrdID string, msg *Message, reason string, disconnected *SessionID) {
+	clOrdID, _ := msg.Body.GetString(tagClOrdID)
+	cancel := &KillSwitchCancel{SessionID: sessionID, ClOrdID: clOrdID, OrigClOrdID: origClOrdID, Status: CancelPending}
+	k.cancels = append(k.cancels, cancel)
+
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		cancel.Status, cancel.Text = CancelFailed, ErrSessionNotFound.Error()
+		return
+	}
+	if (disconnected != nil && *disconnected == sessionID) || !session.IsLoggedOn() {
+		cancel.Status, cancel.Text = CancelFailed, ErrNotLoggedOn.Error()
+		return
+	}
+
+	session.log.OnEventf("Kill switch triggered: %v", reason)
+	if err := session.queueForSend(msg); err != nil {
+		cancel.Status, cancel.Text = CancelFailed, err.Error()
+	}
+}
+
+// onDisconnect triggers the KillSwitch if the session is monitored, called by the disconnected session while still
+// in its logged on state.
+func (k *KillSwitch) onDisconnect(sessionID SessionID) {
+	k.mu.Lock()
+	monitored := k.monitor[sessionID]
+	k.mu.Unlock()
+
+	if monitored {
+		k.trigger(fmt.Sprintf("disconnect of %v", sessionID), &sessionID)
+	}
+}
+
+// onResponse tracks the responses to the cancels sent.
+func (k *KillSwitch) onResponse(msg *Message, msgType []byte, sessionID SessionID) {
+	switch string(msgType) {
+	case "r", "8", "9":
+	default:
+		return
+	}
+
+	clOrdID, err := msg.Body.GetString(tagClOrdID)
+	if err != nil {
+		return
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, cancel := range k.cancels {
+		if cancel.SessionID != sessionID || cancel.ClOrdID != clOrdID || cancel.Status != CancelPending {
+			continue
+		}
+
+		text, _ := msg.Body.GetString(tagText)
+		switch string(msgType) {
+		case "r":
+			if response, _ := msg.Body.GetString(tagMassCancelResponse); response == "0" {
+				cancel.Status, cancel.Text = CancelRejected, text
+			} else {
+				cancel.Status = CancelConfirmed
+			}
+
+		case "8":
+			if ordStatus, _ := msg.Body.GetString(tagOrdStatus); ordStatus == "4" {
+				cancel.Status = CancelConfirmed
+				delete(k.orders[sessionID], cancel.OrigClOrdID)
+			}
+
+		case "9":
+			cancel.Status, cancel.Text = CancelRejected, text
+		}
+		return
+	}
+}
+
+// sessionKillSwitches are the KillSwitches attached to a session.
+type sessionKillSwitches struct {
+	sync.Mutex
+	switches []*KillSwitch
+}
+
+func (s *sessionKillSwitches) list() []*KillSwitch {
+	s.Lock()
+	defer s.Unlock()
+	return s.switches
+}
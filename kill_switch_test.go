@@ -0,0 +1,172 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KillSwitchTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestKillSwitchTestSuite(t *testing.T) {
+	suite.Run(t, new(KillSwitchTestSuite))
+}
+
+func (s *KillSwitchTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *KillSwitchTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *KillSwitchTestSuite) response(msgType string, fields map[Tag]string) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString(msgType))
+	for tag, value := range fields {
+		msg.Body.SetField(tag, FIXString(value))
+	}
+	return msg
+}
+
+func (s *KillSwitchTestSuite) TestMassCancel() {
+	k, err := NewKillSwitch(KillSwitchOptions{Sessions: []SessionID{s.sessionID}})
+	s.Require().Nil(err)
+
+	s.MockApp.On("ToApp").Return(nil)
+	k.Trigger("manual")
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 1)
+	s.MessageType("q", s.MockApp.lastToApp)
+	s.FieldEquals(tagMassCancelRequestType, "7", s.MockApp.lastToApp.Body)
+	s.NextSenderMsgSeqNum(2)
+
+	cancels := k.Cancels()
+	s.Require().Len(cancels, 1)
+	s.Equal(CancelPending, cancels[0].Status)
+	s.Equal(1, k.Pending())
+	s.FieldEquals(tagClOrdID, cancels[0].ClOrdID, s.MockApp.lastToApp.Body)
+
+	k.onResponse(s.response("r", map[Tag]string{tagClOrdID: "other", tagMassCancelResponse: "7"}), []byte("r"), s.sessionID)
+	s.Equal(1, k.Pending())
+
+	k.onResponse(s.response("r", map[Tag]string{tagClOrdID: cancels[0].ClOrdID, tagMassCancelResponse: "7"}), []byte("r"), s.sessionID)
+	s.Equal(0, k.Pending())
+	s.Equal(CancelConfirmed, k.Cancels()[0].Status)
+}
+
+func (s *KillSwitchTestSuite) TestMassCancelRejected() {
+	k, err := NewKillSwitch(KillSwitchOptions{Sessions: []SessionID{s.sessionID}})
+	s.Require().Nil(err)
+
+	s.MockApp.On("ToApp").Return(nil)
+	k.Trigger("manual")
+	clOrdID := k.Cancels()[0].ClOrdID
+
+	k.onResponse(s.response("r", map[Tag]string{tagClOrdID: clOrdID, tagMassCancelResponse: "0", tagText: "not supported"}), []byte("r"), s.sessionID)
+	s.Equal(CancelRejected, k.Cancels()[0].Status)
+	s.Equal("not supported", k.Cancels()[0].Text)
+}
+
+func (s *KillSwitchTestSuite) TestPerOrder() {
+	k, err := NewKillSwitch(KillSwitchOptions{PerOrder: true})
+	s.Require().Nil(err)
+	s.Require().Nil(k.AddOrder(s.sessionID, OpenOrder{ClOrdID: "order1", Symbol: "MSFT", Side: "1", OrderQty: "100"}))
+	s.Require().Nil(k.AddOrder(s.sessionID, OpenOrder{ClOrdID: "order2", Symbol: "MSFT", Side: "2"}))
+	k.RemoveOrder(s.sessionID, "order2")
+
+	s.MockApp.On("ToApp").Return(nil)
+	k.Trigger("manual")
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 1)
+	s.MessageType("F", s.MockApp.lastToApp)
+	s.FieldEquals(tagOrigClOrdID, "order1", s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagSymbol, "MSFT", s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagOrderQty, "100", s.MockApp.lastToApp.Body)
+
+	cancels := k.Cancels()
+	s.Require().Len(cancels, 1)
+	s.Equal("order1", cancels[0].OrigClOrdID)
+
+	// Execution reports not confirming the cancel are ignored.
+	k.onResponse(s.response("8", map[Tag]string{tagClOrdID: cancels[0].ClOrdID, tagOrdStatus: "6"}), []byte("8"), s.sessionID)
+	s.Equal(1, k.Pending())
+
+	k.onResponse(s.response("8", map[Tag]string{tagClOrdID: cancels[0].ClOrdID, tagOrdStatus: "4"}), []byte("8"), s.sessionID)
+	s.Equal(CancelConfirmed, k.Cancels()[0].Status)
+
+	// The canceled order is no longer registered.
+	k.Trigger("manual")
+	s.Len(k.Cancels(), 1)
+}
+
+func (s *KillSwitchTestSuite) TestCancelReject() {
+	k, err := NewKillSwitch(KillSwitchOptions{PerOrder: true})
+	s.Require().Nil(err)
+	s.Require().Nil(k.AddOrder(s.sessionID, OpenOrder{ClOrdID: "order1", Symbol: "MSFT", Side: "1"}))
+
+	s.MockApp.On("ToApp").Return(nil)
+	k.Trigger("manual")
+	clOrdID := k.Cancels()[0].ClOrdID
+
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.fromCallback(s.response("9", map[Tag]string{tagClOrdID: clOrdID, tagText: "too late"}))
+	s.Equal(CancelRejected, k.Cancels()[0].Status)
+	s.Equal("too late", k.Cancels()[0].Text)
+}
+
+func (s *KillSwitchTestSuite) TestDisconnect() {
+	k, err := NewKillSwitch(KillSwitchOptions{Sessions: []SessionID{s.sessionID}, Monitor: []SessionID{s.sessionID}})
+	s.Require().Nil(err)
+
+	s.MockApp.On("OnLogout")
+	s.Session.setState(s.Session, latentState{})
+
+	// The designated session is the disconnected one, so the cancel cannot be sent.
+	s.Require().Len(k.Cancels(), 1)
+	cancel := k.Cancels()[0]
+	s.Equal(CancelFailed, cancel.Status)
+	s.Equal(ErrNotLoggedOn.Error(), cancel.Text)
+	s.MockApp.AssertNotCalled(s.T(), "ToApp")
+}
+
+func (s *KillSwitchTestSuite) TestUnknownSession() {
+	_, err := NewKillSwitch(KillSwitchOptions{Monitor: []SessionID{{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}}})
+	s.ErrorIs(err, ErrSessionNotFound)
+}
+
+type orderSourceFunc func(sessionID SessionID) ([]OpenOrder, error)
+
+func (f orderSourceFunc) OpenOrders(sessionID SessionID) ([]OpenOrder, error) { return f(sessionID) }
+
+func (s *KillSwitchTestSuite) TestOrderSource() {
+	source := orderSourceFunc(func(sessionID SessionID) ([]OpenOrder, error) {
+		s.Equal(s.sessionID, sessionID)
+		return []OpenOrder{{ClOrdID: "order1", Symbol: "MSFT", Side: "1"}}, nil
+	})
+	k, err := NewKillSwitch(KillSwitchOptions{Sessions: []SessionID{s.sessionID}, PerOrder: true, Orders: source})
+	s.Require().Nil(err)
+	s.Require().Nil(k.AddOrder(s.sessionID, OpenOrder{ClOrdID: "order1", Symbol: "MSFT", Side: "1"}))
+
+	s.MockApp.On("ToApp").Return(nil)
+	k.Trigger("manual")
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 1)
+	s.FieldEquals(tagOrigClOrdID, "order1", s.MockApp.lastToApp.Body)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Labeler is an optional interface implemented by a Log or MessageStore that records the session's labels,
+// as configured with the SessionLabels setting.
+type Labeler interface {
+	SetLabels(labels map[string]string)
+}
+
+// Labels returns a copy of the labels configured for the session.
+func (s *Session) Labels() map[string]string {
+	labels := make(map[string]string, len(s.SessionLabels))
+	for k, v := range s.SessionLabels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// FormatLabels formats labels as space separated key=value pairs, sorted by key.
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + labels[k]
+	}
+	return strings.Join(pairs, " ")
+}
+
+// parseLabels parses a comma delimited list of key=value pairs.
+func parseLabels(value string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(pair, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("label %q is not a key=value pair", pair)
+		}
+		if _, dup := labels[k]; dup {
+			return nil, fmt.Errorf("duplicate label %q", k)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+func setLabels(target interface{}, labels map[string]string) {
+	if l, ok := target.(Labeler); ok {
+		l.SetLabels(labels)
+	}
+}
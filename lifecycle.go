@@ -0,0 +1,143 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ErrDrainTimeout is returned by RunUntilSignal when the engines did not stop within ShutdownOptions.DrainTimeout.
+var ErrDrainTimeout = errors.New("quickfix: drain timeout exceeded")
+
+// ShutdownOptions configures RunUntilSignal.
+type ShutdownOptions struct {
+	// Signals that trigger an orderly shutdown. Defaults to SIGINT and SIGTERM.
+	Signals []os.Signal
+
+	// Context, if set, also triggers an orderly shutdown when done.
+	Context context.Context
+
+	// DrainTimeout bounds how long the acceptor and initiators may take to log out their sessions.
+	// Zero waits indefinitely.
+	DrainTimeout time.Duration
+
+	// AdminShutdown is called last, after the engines have stopped and the stores are closed, so that
+	// status endpoints remain available while sessions drain. Typically http.Server.Shutdown.
+	AdminShutdown func(context.Context) error
+
+	// AdminShutdownTimeout bounds the context passed to AdminShutdown. Defaults to 5 seconds.
+	AdminShutdownTimeout time.Duration
+}
+
+// RunUntilSignal starts the acceptor (which may be nil) and initiators, blocks until one of the configured
+// signals is received, then shuts down in order: initiators and acceptor are stopped, logging out their
+// sessions within the drain timeout; the session message stores are closed so buffered state reaches
+// storage; finally the admin API is shut down.
+func RunUntilSignal(acceptor *Acceptor, initiators []*Initiator, opts ShutdownOptions) error {
+	signals := opts.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, signals...)
+	defer signal.Stop(sigChan)
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var stops []func()
+	var started []interface{ sessionList() []*Session }
+	startErr := func() error {
+		if acceptor != nil {
+			if err := acceptor.Start(); err != nil {
+				return fmt.Errorf("starting acceptor: %w", err)
+			}
+			stops = append(stops, acceptor.Stop)
+			started = append(started, acceptor)
+		}
+		for _, initiator := range initiators {
+			if err := initiator.Start(); err != nil {
+				return fmt.Errorf("starting initiator: %w", err)
+			}
+			stops = append(stops, initiator.Stop)
+			started = append(started, initiator)
+		}
+		return nil
+	}()
+
+	if startErr == nil {
+		select {
+		case <-sigChan:
+		case <-ctx.Done():
+		}
+	}
+
+	errs := []error{startErr}
+	errs = append(errs, drain(stops, opts.DrainTimeout))
+	// Collected after stopping, so that sessions added or removed at runtime are accounted for.
+	for _, engine := range started {
+		for _, session := range engine.sessionList() {
+			if err := session.close(); err != nil {
+				errs = append(errs, fmt.Errorf("closing %v: %w", session.sessionID, err))
+			}
+		}
+	}
+
+	if opts.AdminShutdown != nil {
+		timeout := opts.AdminShutdownTimeout
+		if timeout <= 0 {
+			timeout = 5 * time.Second
+		}
+		adminCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := opts.AdminShutdown(adminCtx); err != nil {
+			errs = append(errs, fmt.Errorf("shutting down admin API: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// drain stops initiators before the acceptor, so outbound sessions log out first, and waits up to timeout.
+func drain(stops []func(), timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}()
+
+	if timeout <= 0 {
+		<-done
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return ErrDrainTimeout
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunUntilSignal(t *testing.T) {
+	var adminCalled bool
+	done := make(chan error)
+	go func() {
+		done <- RunUntilSignal(nil, nil, ShutdownOptions{
+			Signals: []os.Signal{syscall.SIGUSR1},
+			AdminShutdown: func(ctx context.Context) error {
+				_, hasDeadline := ctx.Deadline()
+				assert.True(t, hasDeadline)
+				adminCalled = true
+				return nil
+			},
+		})
+	}()
+
+	// Retry until the handler is installed.
+	for {
+		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+			assert.True(t, adminCalled)
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestRunUntilSignalContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	adminErr := errors.New("admin")
+	err := RunUntilSignal(nil, nil, ShutdownOptions{
+		Context:       ctx,
+		AdminShutdown: func(context.Context) error { return adminErr },
+	})
+	assert.ErrorIs(t, err, adminErr)
+}
+
+func TestDrainOrder(t *testing.T) {
+	var order []int
+	err := drain([]func(){
+		func() { order = append(order, 1) },
+		func() { order = append(order, 2) },
+	}, time.Second)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, order)
+}
+
+func TestDrainTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	err := drain([]func(){func() { <-block }}, 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrDrainTimeout)
+}
@@ -15,7 +15,10 @@
 
 package quickfix
 
-// Log is a generic interface for logging FIX messages and events.
+import "io"
+
+// Log is a generic interface for logging FIX messages and events. A session Log may also implement io.Closer to
+// release the files or connections it holds, it is then closed with the MessageStore of its session.
 type Log interface {
 	// OnIncoming log incoming fix message.
 	OnIncoming([]byte)
@@ -38,3 +41,10 @@ type LogFactory interface {
 	// CreateSessionLog Session specific log.
 	CreateSessionLog(sessionID SessionID) (Log, error)
 }
+
+func closeLog(log Log) error {
+	if closer, ok := log.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
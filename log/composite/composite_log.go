@@ -16,6 +16,9 @@
 package composite
 
 import (
+	"errors"
+	"io"
+
 	"github.com/quickfixgo/quickfix"
 )
 
@@ -47,6 +50,25 @@ func (l compositeLog) OnEventf(format string, a ...interface{}) {
 	}
 }
 
+func (l compositeLog) SetLabels(labels map[string]string) {
+	for _, log := range l.logs {
+		if labeler, ok := log.(quickfix.Labeler); ok {
+			labeler.SetLabels(labels)
+		}
+	}
+}
+
+// Close implements io.Closer, closing the logs holding resources.
+func (l compositeLog) Close() error {
+	var errs []error
+	for _, log := range l.logs {
+		if closer, ok := log.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
 type compositeLogFactory struct {
 	logFactories []quickfix.LogFactory
 }
@@ -46,6 +46,15 @@ func (l fileLog) OnEventf(format string, v ...interface{}) {
 	l.eventLogger.Printf(format, v...)
 }
 
+// SetLabels prefixes every log entry with the session labels, following the timestamp.
+func (l fileLog) SetLabels(labels map[string]string) {
+	prefix := "[" + quickfix.FormatLabels(labels) + "] "
+	for _, logger := range []*log.Logger{l.eventLogger, l.messageLogger} {
+		logger.SetFlags(logger.Flags() | log.Lmsgprefix)
+		logger.SetPrefix(prefix)
+	}
+}
+
 type fileLogFactory struct {
 	globalLogPath   string
 	sessionLogPaths map[quickfix.SessionID]string
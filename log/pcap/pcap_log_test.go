@@ -0,0 +1,163 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type capturedBlock struct {
+	blockType uint32
+	body      []byte
+}
+
+func readBlocks(t *testing.T, data []byte) []capturedBlock {
+	var blocks []capturedBlock
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 12)
+		blockType := binary.LittleEndian.Uint32(data[0:])
+		totalLen := binary.LittleEndian.Uint32(data[4:])
+		require.Zero(t, totalLen%4, "blocks must be 32-bit aligned")
+		require.Equal(t, totalLen, binary.LittleEndian.Uint32(data[totalLen-4:]))
+		blocks = append(blocks, capturedBlock{blockType, data[8 : totalLen-4]})
+		data = data[totalLen:]
+	}
+	return blocks
+}
+
+func packetComment(body []byte) string {
+	capLen := binary.LittleEndian.Uint32(body[12:])
+	opts := body[20+pad4(int(capLen)):]
+	for len(opts) >= 4 {
+		code := binary.LittleEndian.Uint16(opts[0:])
+		length := binary.LittleEndian.Uint16(opts[2:])
+		if code == optComment {
+			return string(opts[4 : 4+length])
+		}
+		opts = opts[4+pad4(int(length)):]
+	}
+	return ""
+}
+
+func TestPcapLog_NewLogFactory(t *testing.T) {
+	cfg := `
+[DEFAULT]
+ConnectionType=initiator
+SenderCompID=TW
+
+[SESSION]
+BeginString=FIX.4.2
+TargetCompID=ISLD
+`
+	settings, err := quickfix.ParseSettings(strings.NewReader(cfg))
+	require.Nil(t, err)
+
+	_, err = NewLogFactory(settings)
+	assert.NotNil(t, err, "Should expect error when settings have no pcap log path")
+
+	settings.GlobalSettings().Set("PcapLogPath", t.TempDir())
+	for _, s := range settings.SessionSettings() {
+		s.Set("PcapLogPath", t.TempDir())
+	}
+
+	factory, err := NewLogFactory(settings)
+	require.Nil(t, err)
+
+	globalLog, err := factory.Create()
+	require.Nil(t, err)
+	globalLog.OnEvent("ignored")
+
+	_, err = factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "OTHER"})
+	assert.NotNil(t, err)
+
+	_, err = factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ISLD"})
+	assert.Nil(t, err)
+}
+
+func TestPcapLog_Capture(t *testing.T) {
+	logPath := t.TempDir()
+	l, err := newPcapLog("myprefix", logPath)
+	require.Nil(t, err)
+
+	outgoing := []byte("8=FIX.4.2\x019=40\x0135=D\x0134=2\x0149=TW\x0156=ISLD\x0152=20240101-00:00:00\x0110=000\x01")
+	incoming := []byte("8=FIX.4.2\x019=30\x0135=8\x0134=7\x0149=ISLD\x0156=TW\x0110=000\x01")
+	l.OnOutgoing(outgoing)
+	l.OnIncoming(incoming)
+	l.OnEvent("events are not captured")
+	require.Nil(t, l.Close())
+	l.OnOutgoing(outgoing)
+	require.Nil(t, l.Close())
+
+	info, err := os.Stat(path.Join(logPath, "myprefix.pcapng"))
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path.Join(logPath, "myprefix.pcapng"))
+	require.Nil(t, err)
+
+	blocks := readBlocks(t, data)
+	require.Len(t, blocks, 4)
+	assert.Equal(t, blockTypeSectionHeader, blocks[0].blockType)
+	assert.Equal(t, byteOrderMagic, binary.LittleEndian.Uint32(blocks[0].body))
+	assert.Equal(t, blockTypeInterfaceDesc, blocks[1].blockType)
+	assert.Equal(t, linkTypeIPv4, binary.LittleEndian.Uint16(blocks[1].body))
+
+	out, in := blocks[2], blocks[3]
+	assert.Equal(t, blockTypeEnhancedPacket, out.blockType)
+	assert.Equal(t, "outgoing FIX message: BeginString=FIX.4.2 MsgType=D MsgSeqNum=2 SenderCompID=TW TargetCompID=ISLD SendingTime=20240101-00:00:00", packetComment(out.body))
+	assert.Equal(t, "incoming FIX message: BeginString=FIX.4.2 MsgType=8 MsgSeqNum=7 SenderCompID=ISLD TargetCompID=TW", packetComment(in.body))
+
+	outPacket := out.body[20 : 20+binary.LittleEndian.Uint32(out.body[12:])]
+	assert.Equal(t, outgoing, outPacket[ipv4HeaderLen+tcpHeaderLen:])
+	assert.Equal(t, localEndpoint.addr[:], outPacket[12:16])
+	assert.Equal(t, remoteEndpoint.addr[:], outPacket[16:20])
+	assert.Zero(t, checksum(outPacket[:ipv4HeaderLen], 0), "IPv4 header checksum should verify")
+
+	inPacket := in.body[20 : 20+binary.LittleEndian.Uint32(in.body[12:])]
+	assert.Equal(t, remoteEndpoint.port, binary.BigEndian.Uint16(inPacket[ipv4HeaderLen:]))
+	inAck := binary.BigEndian.Uint32(inPacket[ipv4HeaderLen+8:])
+	assert.Equal(t, uint32(len(outgoing)), inAck, "incoming segment should acknowledge the outgoing bytes")
+}
+
+func TestPcapLog_AppendsSections(t *testing.T) {
+	logPath := t.TempDir()
+	for i := 0; i < 2; i++ {
+		l, err := newPcapLog("myprefix", logPath)
+		require.Nil(t, err)
+		l.OnOutgoing([]byte("8=FIX.4.2\x0135=0\x01"))
+		require.Nil(t, l.Close())
+	}
+
+	data, err := os.ReadFile(path.Join(logPath, "myprefix.pcapng"))
+	require.Nil(t, err)
+
+	var sections int
+	for _, b := range readBlocks(t, data) {
+		if b.blockType == blockTypeSectionHeader {
+			sections++
+		}
+	}
+	assert.Equal(t, 2, sections)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+const (
+	blockTypeSectionHeader    uint32 = 0x0A0D0D0A
+	blockTypeInterfaceDesc    uint32 = 0x00000001
+	blockTypeEnhancedPacket   uint32 = 0x00000006
+	byteOrderMagic            uint32 = 0x1A2B3C4D
+	optEndOfOpt               uint16 = 0
+	optComment                uint16 = 1
+	linkTypeIPv4              uint16 = 228
+	ipv4HeaderLen                    = 20
+	tcpHeaderLen                     = 20
+	maxSegmentPayload                = 0xFFFF - ipv4HeaderLen - tcpHeaderLen
+	tcpFlagsPshAck            uint8  = 0x18
+	tcpWindow                 uint16 = 0xFFFF
+	ipProtocolTCP             uint8  = 6
+	ipDefaultTTL              uint8  = 64
+	ipFlagDontFragment        uint16 = 0x4000
+	pcapngVersionMajor        uint16 = 1
+	pcapngVersionMinor        uint16 = 0
+	pcapngSectionLenUndefined uint64 = 0xFFFFFFFFFFFFFFFF // -1, section length not specified.
+)
+
+// endpoint is one side of the synthetic TCP connection written to the capture.
+type endpoint struct {
+	addr [4]byte
+	port uint16
+	seq  uint32
+}
+
+// pcapngWriter writes FIX messages as synthetic IPv4/TCP segments in pcapng format.
+// Wireshark's FIX dissector picks the payload up heuristically, and each message is
+// annotated with a packet comment.
+type pcapngWriter struct {
+	w      io.Writer
+	local  endpoint
+	remote endpoint
+	ipID   uint16
+}
+
+func newPcapngWriter(w io.Writer, local, remote endpoint) (*pcapngWriter, error) {
+	pw := &pcapngWriter{w: w, local: local, remote: remote}
+	if err := pw.writeSectionHeader(); err != nil {
+		return nil, err
+	}
+	if err := pw.writeInterfaceDescription(); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (pw *pcapngWriter) writeSectionHeader() error {
+	body := make([]byte, 16)
+	binary.LittleEndian.PutUint32(body[0:], byteOrderMagic)
+	binary.LittleEndian.PutUint16(body[4:], pcapngVersionMajor)
+	binary.LittleEndian.PutUint16(body[6:], pcapngVersionMinor)
+	binary.LittleEndian.PutUint64(body[8:], pcapngSectionLenUndefined)
+	return pw.writeBlock(blockTypeSectionHeader, body)
+}
+
+func (pw *pcapngWriter) writeInterfaceDescription() error {
+	body := make([]byte, 8)
+	binary.LittleEndian.PutUint16(body[0:], linkTypeIPv4)
+	binary.LittleEndian.PutUint32(body[4:], 0) // No snap length limit.
+	return pw.writeBlock(blockTypeInterfaceDesc, body)
+}
+
+// writeMessage writes msg as one or more TCP segments travelling in the given direction.
+// The comment is attached to the first segment.
+func (pw *pcapngWriter) writeMessage(ts time.Time, outgoing bool, msg []byte, comment string) error {
+	src, dst := &pw.remote, &pw.local
+	if outgoing {
+		src, dst = &pw.local, &pw.remote
+	}
+
+	for first := true; first || len(msg) > 0; first = false {
+		payload := msg
+		if len(payload) > maxSegmentPayload {
+			payload = payload[:maxSegmentPayload]
+		}
+		msg = msg[len(payload):]
+
+		packet := pw.buildSegment(src, dst, payload)
+		src.seq += uint32(len(payload))
+
+		segmentComment := ""
+		if first {
+			segmentComment = comment
+		}
+		if err := pw.writeEnhancedPacket(ts, packet, segmentComment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pw *pcapngWriter) buildSegment(src, dst *endpoint, payload []byte) []byte {
+	totalLen := ipv4HeaderLen + tcpHeaderLen + len(payload)
+	packet := make([]byte, totalLen)
+
+	ip := packet[:ipv4HeaderLen]
+	ip[0] = 0x45 // Version 4, 5 word header.
+	binary.BigEndian.PutUint16(ip[2:], uint16(totalLen))
+	binary.BigEndian.PutUint16(ip[4:], pw.ipID)
+	binary.BigEndian.PutUint16(ip[6:], ipFlagDontFragment)
+	ip[8] = ipDefaultTTL
+	ip[9] = ipProtocolTCP
+	copy(ip[12:16], src.addr[:])
+	copy(ip[16:20], dst.addr[:])
+	binary.BigEndian.PutUint16(ip[10:], checksum(ip, 0))
+	pw.ipID++
+
+	tcp := packet[ipv4HeaderLen:]
+	binary.BigEndian.PutUint16(tcp[0:], src.port)
+	binary.BigEndian.PutUint16(tcp[2:], dst.port)
+	binary.BigEndian.PutUint32(tcp[4:], src.seq)
+	binary.BigEndian.PutUint32(tcp[8:], dst.seq)
+	tcp[12] = (tcpHeaderLen / 4) << 4
+	tcp[13] = tcpFlagsPshAck
+	binary.BigEndian.PutUint16(tcp[14:], tcpWindow)
+	copy(tcp[tcpHeaderLen:], payload)
+
+	// The TCP checksum covers a pseudo header made of the addresses, protocol and segment length.
+	var pseudo uint32
+	pseudo += uint32(binary.BigEndian.Uint16(src.addr[0:])) + uint32(binary.BigEndian.Uint16(src.addr[2:]))
+	pseudo += uint32(binary.BigEndian.Uint16(dst.addr[0:])) + uint32(binary.BigEndian.Uint16(dst.addr[2:]))
+	pseudo += uint32(ipProtocolTCP) + uint32(len(tcp))
+	binary.BigEndian.PutUint16(tcp[16:], checksum(tcp, pseudo))
+
+	return packet
+}
+
+func (pw *pcapngWriter) writeEnhancedPacket(ts time.Time, packet []byte, comment string) error {
+	micros := uint64(ts.UnixNano() / int64(time.Microsecond))
+
+	body := make([]byte, 20, 20+pad4(len(packet))+pad4(len(comment))+8)
+	binary.LittleEndian.PutUint32(body[0:], 0) // Interface ID.
+	binary.LittleEndian.PutUint32(body[4:], uint32(micros>>32))
+	binary.LittleEndian.PutUint32(body[8:], uint32(micros))
+	binary.LittleEndian.PutUint32(body[12:], uint32(len(packet)))
+	binary.LittleEndian.PutUint32(body[16:], uint32(len(packet)))
+	body = appendPadded(body, packet)
+
+	if comment != "" {
+		body = appendOption(body, optComment, []byte(comment))
+		body = appendOption(body, optEndOfOpt, nil)
+	}
+
+	return pw.writeBlock(blockTypeEnhancedPacket, body)
+}
+
+func (pw *pcapngWriter) writeBlock(blockType uint32, body []byte) error {
+	totalLen := uint32(12 + len(body))
+	block := make([]byte, 0, totalLen)
+	block = binary.LittleEndian.AppendUint32(block, blockType)
+	block = binary.LittleEndian.AppendUint32(block, totalLen)
+	block = append(block, body...)
+	block = binary.LittleEndian.AppendUint32(block, totalLen)
+
+	_, err := pw.w.Write(block)
+	return err
+}
+
+func appendOption(b []byte, code uint16, value []byte) []byte {
+	b = binary.LittleEndian.AppendUint16(b, code)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(value)))
+	return appendPadded(b, value)
+}
+
+func appendPadded(b, value []byte) []byte {
+	b = append(b, value...)
+	for i := len(value); i < pad4(len(value)); i++ {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func pad4(n int) int {
+	return (n + 3) &^ 3
+}
+
+// checksum computes the internet checksum of b, seeded with sum.
+func checksum(b []byte, sum uint32) uint16 {
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
@@ -26,6 +26,10 @@ type screenLog struct {
 	prefix string
 }
 
+func (l *screenLog) SetLabels(labels map[string]string) {
+	l.prefix = fmt.Sprintf("%s [%s]", l.prefix, quickfix.FormatLabels(labels))
+}
+
 func (l screenLog) OnIncoming(s []byte) {
 	logTime := time.Now().UTC()
 	fmt.Printf("<%v, %s, incoming>\n  (%s)\n", logTime, l.prefix, s)
@@ -48,12 +52,12 @@ func (l screenLog) OnEventf(format string, a ...interface{}) {
 type screenLogFactory struct{}
 
 func (screenLogFactory) Create() (quickfix.Log, error) {
-	log := screenLog{"GLOBAL"}
+	log := &screenLog{"GLOBAL"}
 	return log, nil
 }
 
 func (screenLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
-	log := screenLog{sessionID.String()}
+	log := &screenLog{sessionID.String()}
 	return log, nil
 }
 
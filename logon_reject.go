@@ -0,0 +1,78 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sync"
+	"time"
+)
+
+// LogonRejectedHandler is an optional interface implemented by an Application to be notified when the counterparty
+// answers an initiator's Logon with a Logout, typically because of invalid credentials.
+type LogonRejectedHandler interface {
+	// OnLogonRejected is called with the Text of the Logout, empty if absent, and the Logout message itself.
+	OnLogonRejected(text string, msg *Message, sessionID SessionID)
+}
+
+// logonRejects counts consecutive logon rejections of an initiator, see MaxLogonRejectRetries.
+type logonRejects struct {
+	sync.Mutex
+	count int
+}
+
+func (r *logonRejects) add() {
+	r.Lock()
+	defer r.Unlock()
+	r.count++
+}
+
+func (r *logonRejects) reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.count = 0
+}
+
+func (r *logonRejects) get() int {
+	r.Lock()
+	defer r.Unlock()
+	return r.count
+}
+
+// onLogonRejected handles a Logout received in reply to our Logon.
+func (s *Session) onLogonRejected(msg *Message) {
+	text, _ := msg.Body.GetString(tagText)
+	s.log.OnEventf("Logon rejected: %v", text)
+	s.logonRejects.add()
+
+	if seqNum, err := msg.Header.GetInt(tagMsgSeqNum); err == nil && seqNum == s.store.NextTargetMsgSeqNum() {
+		if err := s.store.IncrNextTargetMsgSeqNum(); err != nil {
+			s.logError(err)
+		}
+	}
+
+	if handler, ok := s.application.(LogonRejectedHandler); ok {
+		handler.OnLogonRejected(text, msg, s.sessionID)
+	}
+}
+
+// logonRejectLockout reports whether consecutive logon rejections exceed MaxLogonRejectRetries, and if so how long
+// the initiator must wait before reconnecting. A zero duration means reconnecting stops altogether.
+func (s *Session) logonRejectLockout() (time.Duration, bool) {
+	if s.MaxLogonRejectRetries < 0 || s.logonRejects.get() <= s.MaxLogonRejectRetries {
+		return 0, false
+	}
+	return s.LogonRejectLockout, true
+}
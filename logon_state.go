@@ -31,6 +31,11 @@ func (s logonState) FixMsgIn(session *Session, msg *Message) (nextState sessionS
 		return handleStateError(session, err)
 	}
 
+	if session.InitiateLogon && bytes.Equal(msgType, msgTypeLogout) {
+		session.onLogonRejected(msg)
+		return latentState{}
+	}
+
 	if !bytes.Equal(msgType, msgTypeLogon) {
 		session.log.OnEventf("Invalid Session State: Received Msg %s while waiting for Logon", msg)
 		return latentState{}
@@ -50,13 +55,15 @@ func (s logonState) FixMsgIn(session *Session, msg *Message) (nextState sessionS
 				return shutdownWithReason(session, msg, false, tooHighErr.Error())
 			}
 
+			// The handshake may complete while resending.
+			session.startHandshake()
 			return
 
 		default:
 			return handleStateError(session, err)
 		}
 	}
-	return inSession{}
+	return session.startHandshake()
 }
 
 func (s logonState) Timeout(session *Session, e internal.Event) (nextState sessionState) {
@@ -206,6 +206,46 @@ func (s *LogonStateTestSuite) TestFixMsgInLogonInitiateLogon() {
 	s.NextSenderMsgSeqNum(2)
 }
 
+type logonRejectedApp struct {
+	*MockApp
+	text string
+}
+
+func (a *logonRejectedApp) OnLogonRejected(text string, _ *Message, _ SessionID) {
+	a.text = text
+}
+
+func (s *LogonStateTestSuite) TestFixMsgInLogoutInitiateLogon() {
+	app := &logonRejectedApp{MockApp: &s.MockApp}
+	s.Session.application = app
+	s.Session.InitiateLogon = true
+	s.Session.MaxLogonRejectRetries = 1
+	s.Session.LogonRejectLockout = time.Minute
+	s.IncrNextSenderMsgSeqNum()
+
+	logout := s.Logout()
+	logout.Body.SetField(tagText, FIXString("invalid password"))
+	s.MockApp.On("OnLogout")
+	s.fixMsgIn(s.Session, logout)
+
+	s.State(latentState{})
+	s.Equal("invalid password", app.text)
+	s.NextTargetMsgSeqNum(2)
+
+	_, locked := s.Session.logonRejectLockout()
+	s.False(locked)
+
+	s.Session.stateMachine.State = logonState{}
+	s.fixMsgIn(s.Session, s.Logout())
+	lockout, locked := s.Session.logonRejectLockout()
+	s.True(locked)
+	s.Equal(time.Minute, lockout)
+
+	s.Session.logonRejects.reset()
+	_, locked = s.Session.logonRejectLockout()
+	s.False(locked)
+}
+
 func (s *LogonStateTestSuite) TestFixMsgInLogonInitiateLogonExpectResetSeqNum() {
 	s.Session.InitiateLogon = true
 	s.Session.sentReset = true
@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -25,6 +26,16 @@ type memoryStore struct {
 	senderMsgSeqNum, targetMsgSeqNum int
 	creationTime                     time.Time
 	messageMap                       map[int][]byte
+	epoch                            uint64
+
+	// Dead letters are read and deleted from outside the session goroutine, and survive Reset.
+	deadLettersMu    sync.Mutex
+	deadLetters      []DeadLetter
+	lastDeadLetterID int
+
+	// The audit hash chain is read from outside the session goroutine, and survives Reset.
+	auditMu      sync.Mutex
+	auditRecords []AuditRecord
 }
 
 func (store *memoryStore) NextSenderMsgSeqNum() int {
@@ -62,6 +73,18 @@ func (store *memoryStore) SetCreationTime(t time.Time) {
 	store.creationTime = t
 }
 
+func (store *memoryStore) Epoch() (uint64, error) {
+	return store.epoch, nil
+}
+
+func (store *memoryStore) CompareAndSwapEpoch(old, next uint64) (bool, error) {
+	if store.epoch != old {
+		return false, nil
+	}
+	store.epoch = next
+	return true, nil
+}
+
 func (store *memoryStore) Reset() error {
 	store.senderMsgSeqNum = 0
 	store.targetMsgSeqNum = 0
@@ -117,6 +140,49 @@ func (store *memoryStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, err
 	return msgs, err
 }
 
+func (store *memoryStore) SaveDeadLetter(letter DeadLetter) (int, error) {
+	store.deadLettersMu.Lock()
+	defer store.deadLettersMu.Unlock()
+
+	store.lastDeadLetterID++
+	letter.ID = store.lastDeadLetterID
+	store.deadLetters = append(store.deadLetters, letter)
+	return letter.ID, nil
+}
+
+func (store *memoryStore) DeadLetters() ([]DeadLetter, error) {
+	store.deadLettersMu.Lock()
+	defer store.deadLettersMu.Unlock()
+	return append([]DeadLetter(nil), store.deadLetters...), nil
+}
+
+func (store *memoryStore) DeleteDeadLetter(id int) error {
+	store.deadLettersMu.Lock()
+	defer store.deadLettersMu.Unlock()
+
+	for i, letter := range store.deadLetters {
+		if letter.ID == id {
+			store.deadLetters = append(store.deadLetters[:i], store.deadLetters[i+1:]...)
+			return nil
+		}
+	}
+	return ErrDeadLetterNotFound
+}
+
+func (store *memoryStore) SaveAuditRecord(record AuditRecord) error {
+	store.auditMu.Lock()
+	defer store.auditMu.Unlock()
+
+	store.auditRecords = append(store.auditRecords, record)
+	return nil
+}
+
+func (store *memoryStore) AuditRecords() ([]AuditRecord, error) {
+	store.auditMu.Lock()
+	defer store.auditMu.Unlock()
+	return append([]AuditRecord(nil), store.auditRecords...), nil
+}
+
 type memoryStoreFactory struct{}
 
 func (f memoryStoreFactory) Create(_ SessionID) (MessageStore, error) {
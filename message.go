@@ -38,6 +38,13 @@ type msgParser struct {
 	trailerBytes            []byte
 	foundBody               bool
 	foundTrailer            bool
+	// dataFields maps the length tags of body data fields that may hold binary data to their data tags.
+	dataFields map[Tag]Tag
+	// msgDef is the definition of the message in the application data dictionary, looked up once its MsgType is parsed.
+	msgDef *datadictionary.MessageDef
+	// applVerDataDictionaries maps ApplVerIDs to the application data dictionary replacing appDataDictionary when the
+	// message has that ApplVerID(1128).
+	applVerDataDictionaries map[string]*datadictionary.DataDictionary
 }
 
 // in the message header, the first 3 tags in the message header must be 8,9,35.
@@ -117,6 +124,9 @@ type Message struct {
 
 	// Field bytes as they appear in the raw message.
 	fields []TagValue
+
+	// retained is set on an incoming message kept by the session for later processing, see ReuseIncomingMessages.
+	retained bool
 }
 
 // ToMessage returns the message itself.
@@ -166,12 +176,39 @@ func ParseMessageWithDataDictionary(
 	rawMessage *bytes.Buffer,
 	transportDataDictionary *datadictionary.DataDictionary,
 	appDataDictionary *datadictionary.DataDictionary,
+) (err error) {
+	return parseMessageWithDataFields(msg, rawMessage, transportDataDictionary, appDataDictionary, nil)
+}
+
+// parseMessageWithDataFields parses a FIX message, reading the values of the data fields in dataFields by the
+// preceding length field, as they may contain the field delimiter.
+func parseMessageWithDataFields(
+	msg *Message,
+	rawMessage *bytes.Buffer,
+	transportDataDictionary *datadictionary.DataDictionary,
+	appDataDictionary *datadictionary.DataDictionary,
+	dataFields map[Tag]Tag,
+) (err error) {
+	return parseMessageWithApplVerIDs(msg, rawMessage, transportDataDictionary, appDataDictionary, nil, dataFields)
+}
+
+// parseMessageWithApplVerIDs parses a FIX message like parseMessageWithDataFields, with the body parsed with the
+// data dictionary of its ApplVerID in applVerDataDictionaries if it has one, appDataDictionary otherwise.
+func parseMessageWithApplVerIDs(
+	msg *Message,
+	rawMessage *bytes.Buffer,
+	transportDataDictionary *datadictionary.DataDictionary,
+	appDataDictionary *datadictionary.DataDictionary,
+	applVerDataDictionaries map[string]*datadictionary.DataDictionary,
+	dataFields map[Tag]Tag,
 ) (err error) {
 	// Create msgparser before we go any further.
 	mp := &msgParser{
 		msg:                     msg,
 		transportDataDictionary: transportDataDictionary,
 		appDataDictionary:       appDataDictionary,
+		dataFields:              dataFields,
+		applVerDataDictionaries: applVerDataDictionaries,
 	}
 	mp.msg.rawMessage = rawMessage
 	mp.rawBytes = rawMessage.Bytes()
@@ -179,6 +216,16 @@ func ParseMessageWithDataDictionary(
 	return doParsing(mp)
 }
 
+// useApplVerID switches to the application data dictionary of applVerID, if there is one, for the rest of the message.
+func (mp *msgParser) useApplVerID(applVerID string) {
+	dict, ok := mp.applVerDataDictionaries[applVerID]
+	if !ok {
+		return
+	}
+	mp.appDataDictionary = dict
+	mp.msgDef = dict.Messages[string(mp.msg.fields[2].value)]
+}
+
 // doParsing executes the message parsing process.
 func doParsing(mp *msgParser) (err error) {
 	mp.msg.Header.rwLock.Lock()
@@ -226,11 +273,15 @@ func doParsing(mp *msgParser) (err error) {
 		return
 	}
 	mp.msg.Header.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
+	if mp.appDataDictionary != nil {
+		mp.msgDef = mp.appDataDictionary.Messages[string(mp.parsedFieldBytes.value)]
+	}
 
 	// Start parsing.
 	mp.fieldIndex++
 	xmlDataLen := 0
 	xmlDataMsg := false
+	dataLen, dataTag := 0, Tag(0)
 	mp.trailerBytes = []byte{}
 	mp.foundBody = false
 	mp.foundTrailer = false
@@ -240,6 +291,12 @@ func doParsing(mp *msgParser) (err error) {
 			mp.rawBytes, err = extractXMLDataField(mp.parsedFieldBytes, mp.rawBytes, xmlDataLen)
 			xmlDataLen = 0
 			xmlDataMsg = true
+		} else if dataLen > 0 {
+			mp.rawBytes, err = extractXMLDataField(mp.parsedFieldBytes, mp.rawBytes, dataLen)
+			if err == nil && mp.parsedFieldBytes.tag != dataTag {
+				err = parseError{OrigError: fmt.Sprintf("Data field %d must follow its length field", dataTag)}
+			}
+			dataLen = 0
 		} else {
 			mp.rawBytes, err = extractField(mp.parsedFieldBytes, mp.rawBytes)
 		}
@@ -250,10 +307,13 @@ func doParsing(mp *msgParser) (err error) {
 		switch {
 		case isHeaderField(mp.parsedFieldBytes.tag, mp.transportDataDictionary):
 			mp.msg.Header.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
+			if mp.parsedFieldBytes.tag == tagApplVerID {
+				mp.useApplVerID(string(mp.parsedFieldBytes.value))
+			}
 		case isTrailerField(mp.parsedFieldBytes.tag, mp.transportDataDictionary):
 			mp.msg.Trailer.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
 			mp.foundTrailer = true
-		case isNumInGroupField(mp.msg, []Tag{mp.parsedFieldBytes.tag}, mp.appDataDictionary):
+		case isNumInGroupField(mp.msgDef, []Tag{mp.parsedFieldBytes.tag}):
 			parseGroup(mp, []Tag{mp.parsedFieldBytes.tag})
 		default:
 			mp.foundBody = true
@@ -270,6 +330,9 @@ func doParsing(mp *msgParser) (err error) {
 
 		if mp.parsedFieldBytes.tag == tagXMLDataLen {
 			xmlDataLen, _ = mp.msg.Header.getIntNoLock(tagXMLDataLen)
+		} else if tag, ok := mp.dataFields[mp.parsedFieldBytes.tag]; ok {
+			dataLen, _ = atoi(mp.parsedFieldBytes.value)
+			dataTag = tag
 		}
 		mp.fieldIndex++
 	}
@@ -294,6 +357,11 @@ func doParsing(mp *msgParser) (err error) {
 		}
 	}
 
+	parsed := mp.msg.fields[:mp.fieldIndex+1]
+	mp.msg.Header.setParsed(parsed, sectionHeader, mp.transportDataDictionary)
+	mp.msg.Body.setParsed(parsed, sectionBody, mp.transportDataDictionary)
+	mp.msg.Trailer.setParsed(parsed, sectionTrailer, mp.transportDataDictionary)
+
 	bodyLength, err := mp.msg.Header.getIntNoLock(tagBodyLength)
 	if err != nil {
 		err = parseError{OrigError: err.Error()}
@@ -308,7 +376,7 @@ func doParsing(mp *msgParser) (err error) {
 func parseGroup(mp *msgParser, tags []Tag) {
 	mp.foundBody = true
 	dm := mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1]
-	fields := getGroupFields(mp.msg, tags, mp.appDataDictionary)
+	fields := getGroupFields(mp.msgDef, tags)
 
 	for {
 		mp.fieldIndex++
@@ -319,10 +387,10 @@ func parseGroup(mp *msgParser, tags []Tag) {
 		// Is this field a member for the group.
 		if isGroupMember(mp.parsedFieldBytes.tag, fields) {
 			// Is this field a nested repeating group.
-			if isNumInGroupField(mp.msg, append(tags, mp.parsedFieldBytes.tag), mp.appDataDictionary) {
+			if isNumInGroupField(mp.msgDef, append(tags, mp.parsedFieldBytes.tag)) {
 				dm = append(dm, *mp.parsedFieldBytes)
 				tags = append(tags, mp.parsedFieldBytes.tag)
-				fields = getGroupFields(mp.msg, tags, mp.appDataDictionary)
+				fields = getGroupFields(mp.msgDef, tags)
 				continue
 			}
 			// Add the field member to the group.
@@ -342,23 +410,23 @@ func parseGroup(mp *msgParser, tags []Tag) {
 			// Found a body field outside the group.
 			searchTags := []Tag{mp.parsedFieldBytes.tag}
 			// Is this a new group not inside the existing group.
-			if isNumInGroupField(mp.msg, searchTags, mp.appDataDictionary) {
+			if isNumInGroupField(mp.msgDef, searchTags) {
 				// Add the current repeating group.
 				mp.msg.Body.add(dm)
 				// Cycle again with the new group.
 				dm = mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1]
-				fields = getGroupFields(mp.msg, searchTags, mp.appDataDictionary)
+				fields = getGroupFields(mp.msgDef, searchTags)
 				continue
 			}
 			if len(tags) > 1 {
 				searchTags = tags[:len(tags)-1]
 			}
 			// Did this tag occur after a nested group and belongs to the parent group.
-			if isNumInGroupField(mp.msg, searchTags, mp.appDataDictionary) {
+			if isNumInGroupField(mp.msgDef, searchTags) {
 				// Add the field member to the group.
 				dm = append(dm, *mp.parsedFieldBytes)
 				// Continue parsing the parent group.
-				fields = getGroupFields(mp.msg, searchTags, mp.appDataDictionary)
+				fields = getGroupFields(mp.msgDef, searchTags)
 				continue
 			}
 			// Add the repeating group.
@@ -373,65 +441,49 @@ func parseGroup(mp *msgParser, tags []Tag) {
 
 // isNumInGroupField evaluates if this tag is the start of a repeating group.
 // tags slice will contain multiple tags if the tag in question is found while processing a group already.
-func isNumInGroupField(msg *Message, tags []Tag, appDataDictionary *datadictionary.DataDictionary) bool {
-	if appDataDictionary != nil {
-		msgt, err := msg.msgTypeNoLock()
-		if err != nil {
-			return false
-		}
-		mm, ok := appDataDictionary.Messages[msgt]
-		if ok {
-			fields := mm.Fields
-			for idx, tag := range tags {
-				fd, ok := fields[int(tag)]
-				if ok {
-					if idx == len(tags)-1 {
-						if len(fd.Fields) > 0 {
-							return true
-						}
-					} else {
-						// Map nested fields.
-						newFields := make(map[int]*datadictionary.FieldDef)
-						for _, ff := range fd.Fields {
-							newFields[ff.Tag()] = ff
-						}
-						fields = newFields
-					}
-				}
-			}
-		}
-	}
-	return false
+func isNumInGroupField(msgDef *datadictionary.MessageDef, tags []Tag) bool {
+	return len(getGroupFields(msgDef, tags)) > 0
 }
 
 // getGroupFields gets the relevant fields for parsing a repeating group if this tag is the start of a repeating group.
 // tags slice will contain multiple tags if the tag in question is found while processing a group already.
-func getGroupFields(msg *Message, tags []Tag, appDataDictionary *datadictionary.DataDictionary) (fields []*datadictionary.FieldDef) {
-	if appDataDictionary != nil {
-		msgt, err := msg.msgTypeNoLock()
-		if err != nil {
-			return
+func getGroupFields(msgDef *datadictionary.MessageDef, tags []Tag) []*datadictionary.FieldDef {
+	if msgDef == nil {
+		return nil
+	}
+
+	// Nested fields are searched in place rather than mapped by tag, sparing an allocation per lookup.
+	var nested []*datadictionary.FieldDef
+	inGroup := false
+	for idx, tag := range tags {
+		var fd *datadictionary.FieldDef
+		if inGroup {
+			fd = findFieldDef(nested, tag)
+		} else {
+			fd = msgDef.Fields[int(tag)]
 		}
-		mm, ok := appDataDictionary.Messages[msgt]
-		if ok {
-			fields := mm.Fields
-			for idx, tag := range tags {
-				fd, ok := fields[int(tag)]
-				if ok {
-					if idx == len(tags)-1 {
-						if len(fd.Fields) > 0 {
-							return fd.Fields
-						}
-					} else {
-						// Map nested fields.
-						newFields := make(map[int]*datadictionary.FieldDef)
-						for _, ff := range fd.Fields {
-							newFields[ff.Tag()] = ff
-						}
-						fields = newFields
-					}
-				}
+		if fd == nil {
+			continue
+		}
+
+		if idx == len(tags)-1 {
+			if len(fd.Fields) > 0 {
+				return fd.Fields
 			}
+		} else {
+			nested = fd.Fields
+			inGroup = true
+		}
+	}
+	return nil
+}
+
+// findFieldDef returns the field of fields with tag, nil if there is none. Of fields sharing a tag the last one is
+// returned.
+func findFieldDef(fields []*datadictionary.FieldDef, tag Tag) (fd *datadictionary.FieldDef) {
+	for _, f := range fields {
+		if f.Tag() == int(tag) {
+			fd = f
 		}
 	}
 	return
@@ -582,10 +634,6 @@ func (m *Message) String() string {
 	return string(m.Build())
 }
 
-func formatCheckSum(value int) string {
-	return fmt.Sprintf("%03d", value)
-}
-
 // Build constructs a []byte from a Message instance.
 func (m *Message) Build() []byte {
 	m.cook()
@@ -616,5 +664,6 @@ func (m *Message) cook() {
 	bodyLength := m.Header.length() + m.Body.length() + m.Trailer.length()
 	m.Header.SetInt(tagBodyLength, bodyLength)
 	checkSum := (m.Header.total() + m.Body.total() + m.Trailer.total()) % 256
-	m.Trailer.SetString(tagCheckSum, formatCheckSum(checkSum))
+	var buf [3]byte
+	m.Trailer.SetBytes(tagCheckSum, appendCheckSum(buf[:0], checkSum))
 }
@@ -0,0 +1,80 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sync"
+	"time"
+)
+
+// incomingMessages is the pool of the messages received by sessions with ReuseIncomingMessages.
+var incomingMessages = NewMessagePool()
+
+// MessagePool is a sync.Pool of Messages. Parsing into a Message from the pool reuses its field maps, tag slices and
+// field buffer instead of allocating them.
+type MessagePool struct {
+	pool sync.Pool
+}
+
+// NewMessagePool returns an empty MessagePool.
+func NewMessagePool() *MessagePool {
+	return &MessagePool{pool: sync.Pool{New: func() any { return NewMessage() }}}
+}
+
+// Get returns an empty Message from the pool, or a new one if the pool is empty.
+func (p *MessagePool) Get() *Message {
+	return p.pool.Get().(*Message)
+}
+
+// Put clears msg and returns it to the pool. msg must not be used afterwards.
+func (p *MessagePool) Put(msg *Message) {
+	if msg == nil {
+		return
+	}
+	msg.reset()
+	p.pool.Put(msg)
+}
+
+// reset clears the message, keeping the memory allocated for its fields.
+func (m *Message) reset() {
+	m.Header.Clear()
+	m.Body.Clear()
+	m.Trailer.Clear()
+
+	m.ReceiveTime = time.Time{}
+	m.rawMessage = nil
+	m.bodyBytes = nil
+	m.retained = false
+
+	// Drop the references to the raw message held by the fields.
+	clear(m.fields)
+	m.fields = m.fields[:0]
+}
+
+// newIncomingMessage returns the Message an incoming message is parsed into.
+func (s *Session) newIncomingMessage() *Message {
+	if s.ReuseIncomingMessages {
+		return incomingMessages.Get()
+	}
+	return NewMessage()
+}
+
+// releaseIncomingMessage returns msg to the pool once processed, unless it is kept for later processing.
+func (s *Session) releaseIncomingMessage(msg *Message) {
+	if s.ReuseIncomingMessages && !msg.retained {
+		incomingMessages.Put(msg)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMessageReset(t *testing.T) {
+	msg := NewMessagePool().Get()
+	require.Nil(t, ParseMessage(msg, bytes.NewBufferString("8=FIX.4.2\x019=104\x0135=D\x0134=2\x0149=TW\x0152=20140515-19:49:56.659\x0156=ISLD\x0111=100\x0121=1\x0140=1\x0154=1\x0155=TSLA\x0160=00010101-00:00:00.000\x0110=039\x01")))
+	msg.ReceiveTime = time.Now()
+
+	msg.reset()
+	assert.Empty(t, msg.Header.Tags())
+	assert.Empty(t, msg.Body.Tags())
+	assert.Empty(t, msg.Trailer.Tags())
+	assert.True(t, msg.ReceiveTime.IsZero())
+	assert.Nil(t, msg.rawMessage)
+	assert.Empty(t, msg.fields)
+
+	// A reset message parses as a new one.
+	raw := "8=FIX.4.2\x019=49\x0135=0\x0134=3\x0149=TW\x0152=20140515-19:49:56.659\x0156=ISLD\x0110=123\x01"
+	require.Nil(t, ParseMessage(msg, bytes.NewBufferString(raw)))
+	assert.Equal(t, raw, msg.String())
+	assert.False(t, msg.Body.Has(tagClOrdID))
+}
+
+func TestMessagePoolPutNil(t *testing.T) {
+	NewMessagePool().Put(nil)
+}
+
+type ReuseIncomingMessagesTestSuite struct {
+	SessionSuiteRig
+	received []*Message
+}
+
+func TestReuseIncomingMessagesTestSuite(t *testing.T) {
+	suite.Run(t, new(ReuseIncomingMessagesTestSuite))
+}
+
+func (s *ReuseIncomingMessagesTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.ReuseIncomingMessages = true
+}
+
+func (s *ReuseIncomingMessagesTestSuite) incoming(msg *Message) *Message {
+	s.Session.Incoming(s.Session, fixIn{bytes: bytes.NewBuffer(msg.Build()), receiveTime: time.Now()})
+	return s.MockApp.lastFromApp
+}
+
+func (s *ReuseIncomingMessagesTestSuite) TestReleasedAfterProcessing() {
+	s.MockApp.On("FromApp").Return(nil)
+	received := s.incoming(s.NewOrderSingle())
+
+	s.Require().NotNil(received)
+	s.Empty(received.Header.Tags(), "returned to the pool")
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *ReuseIncomingMessagesTestSuite) TestStashedMessageRetained() {
+	s.MessageFactory.SetNextSeqNum(5)
+	s.MockApp.On("ToAdmin")
+	s.incoming(s.NewOrderSingle())
+
+	resendState, ok := s.Session.State.(resendState)
+	s.Require().True(ok)
+	stashed, ok := resendState.messageStash[5]
+	s.Require().True(ok)
+	s.FieldEquals(tagMsgSeqNum, 5, stashed.Header)
+}
@@ -107,10 +107,11 @@ func (suite *MessageRouterTestSuite) resetRouter() {
 
 func (suite *MessageRouterTestSuite) SetupTest() {
 	suite.resetRouter()
-	sessionsLock.Lock()
-	defer sessionsLock.Unlock()
+	r := defaultEngine.registry
+	r.lock.Lock()
+	defer r.lock.Unlock()
 
-	sessions = make(map[SessionID]*Session)
+	r.sessions = make(map[SessionID]*Session)
 	suite.msg = NewMessage()
 }
 
@@ -205,3 +206,25 @@ func (suite *MessageRouterTestSuite) TestRouteFIXTAppWithDefaultApplVerID() {
 	suite.verifyMessageRoutedBy(ApplVerIDFIX50SP1, "D")
 	suite.Nil(rej)
 }
+
+func (suite *MessageRouterTestSuite) TestRouteFIXTAppWithDefaultApplVerIDOfEngineSession() {
+	suite.givenTheRoute(ApplVerIDFIX50SP1, "D")
+	engine := NewEngine()
+	sessionID := SessionID{BeginString: string(BeginStringFIXT11), SenderCompID: "ISLD", TargetCompID: "TW"}
+	suite.Require().Nil(engine.registry.register(&Session{sessionID: sessionID, targetDefaultApplVerID: "8"}))
+	defer func() { suite.Nil(engine.UnregisterSession(sessionID)) }()
+
+	suite.givenTheMessage([]byte("8=FIXT.1.19=8235=D49=TW34=356=ISLD52=20160424-16:48:2640=160=20160424-16:48:2611=id21=310=120"))
+	rej := suite.Route(suite.msg, suite.sessionID)
+	suite.verifyMessageRoutedBy(ApplVerIDFIX50SP1, "D")
+	suite.Nil(rej)
+}
+
+func (suite *MessageRouterTestSuite) TestRouteFIXTAppWithoutApplVerIDOrSession() {
+	suite.givenTheRoute(ApplVerIDFIX50SP1, "D")
+
+	suite.givenTheMessage([]byte("8=FIXT.1.19=8235=D49=TW34=356=ISLD52=20160424-16:48:2640=160=20160424-16:48:2611=id21=310=120"))
+	rej := suite.Route(suite.msg, suite.sessionID)
+	suite.verifyMessageNotRouted()
+	suite.Equal(RequiredTagMissing(tagApplVerID), rej)
+}
@@ -17,6 +17,7 @@ package quickfix
 
 import (
 	"bytes"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -34,6 +35,32 @@ func BenchmarkParseMessage(b *testing.B) {
 	}
 }
 
+func BenchmarkParseMessageWithDataDictionary(b *testing.B) {
+	dict, err := datadictionary.Parse("spec/FIX44.xml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	raw := []byte("8=FIX.4.4\x019=210\x0135=D\x0134=2\x01347=UTF-8\x0152=20231231-20:19:41\x0149=01001\x0150=01001a\x0156=TEST\x0144=12\x0111=13976\x011=10100400\x0121=1\x01386=1\x01336=NOPL\x0155=SYMABC\x0154=1\x0160=20231231-20:19:41\x0138=1\x0140=2\x0159=0\x01453=1\x01448=4501\x01447=D\x01452=28\x01354=6\x01355=Public\x0110=104\x01")
+
+	b.Run("NewMessage", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			msg := NewMessage()
+			_ = ParseMessageWithDataDictionary(msg, bytes.NewBuffer(raw), dict, dict)
+		}
+	})
+
+	b.Run("MessagePool", func(b *testing.B) {
+		b.ReportAllocs()
+		pool := NewMessagePool()
+		for i := 0; i < b.N; i++ {
+			msg := pool.Get()
+			_ = ParseMessageWithDataDictionary(msg, bytes.NewBuffer(raw), dict, dict)
+			pool.Put(msg)
+		}
+	})
+}
+
 type MessageSuite struct {
 	QuickFIXSuite
 	msg *Message
@@ -87,6 +114,41 @@ func (s *MessageSuite) TestParseMessage() {
 	s.False(s.msg.IsMsgTypeOf("A"))
 }
 
+func (s *MessageSuite) TestIterateReceivedOrder() {
+	rawMsg := bytes.NewBufferString("8=FIX.4.29=9735=D34=249=TW52=20140515-19:49:56.65956=ISLD55=TSLA453=2448=A447=D448=B447=D40=154=110=242")
+	s.Nil(ParseMessage(s.msg, rawMsg))
+
+	var body []string
+	s.msg.Body.Iterate(func(tag Tag, value []byte) bool {
+		body = append(body, fmt.Sprintf("%d=%s", tag, value))
+		return true
+	})
+	s.Equal([]string{"55=TSLA", "453=2", "448=A", "447=D", "448=B", "447=D", "40=1", "54=1"}, body)
+
+	var all []string
+	for c := s.msg.Cursor(); c.Next(); {
+		all = append(all, fmt.Sprintf("%d=%s", c.Tag(), c.Value()))
+	}
+	s.Equal("8=FIX.4.2", all[0])
+	s.Equal("448=B", all[11])
+	s.Equal("10=242", all[len(all)-1])
+	s.Len(all, len(s.msg.fields))
+
+	var fields []string
+	for tag, value := range s.msg.Fields() {
+		fields = append(fields, fmt.Sprintf("%d=%s", tag, value))
+	}
+	s.Equal(all, fields)
+
+	s.msg.Body.SetField(Tag(40), FIXString("2"))
+	body = body[:0]
+	s.msg.Body.Iterate(func(tag Tag, value []byte) bool {
+		body = append(body, fmt.Sprintf("%d=%s", tag, value))
+		return true
+	})
+	s.Equal("40=2", body[0], "a modified body comes in write order")
+}
+
 func (s *MessageSuite) TestParseMessageWithDataDictionary() {
 	dict := new(datadictionary.DataDictionary)
 	dict.Header = &datadictionary.MessageDef{
@@ -0,0 +1,130 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sync"
+	"time"
+)
+
+// Queue names reported to MetricsCollector.QueueDepth.
+const (
+	// MetricsQueueInbound is the queue of raw messages read from the connection, awaiting the session.
+	MetricsQueueInbound = "inbound"
+
+	// MetricsQueueOutbound is the queue of application messages awaiting send.
+	MetricsQueueOutbound = "outbound"
+)
+
+// MetricsCollector receives the metrics of sessions, typically to export them as counters and histograms. Package
+// metrics/prometheus provides a Prometheus implementation along with its SetMetricsRegistry hook.
+// Methods are called from the session goroutines and must not block.
+type MetricsCollector interface {
+	// MessageIn counts a message received and parsed.
+	MessageIn(sessionID SessionID, msgType string)
+
+	// MessageOut counts a message sent. Resent messages are not counted.
+	MessageOut(sessionID SessionID, msgType string)
+
+	// ResendRequestIssued counts a ResendRequest sent to the counterparty.
+	ResendRequestIssued(sessionID SessionID)
+
+	// ResendRequestServed counts a ResendRequest of the counterparty that was answered.
+	ResendRequestServed(sessionID SessionID)
+
+	// LogonAttempt counts a Logon sent by an initiator or received by an acceptor.
+	LogonAttempt(sessionID SessionID)
+
+	// Disconnect counts a disconnect of a connected session.
+	Disconnect(sessionID SessionID)
+
+	// HeartbeatLatency observes the time between a TestRequest and the Heartbeat answering it.
+	HeartbeatLatency(sessionID SessionID, latency time.Duration)
+
+	// QueueDepth reports the number of messages waiting in a queue of the session, see MetricsQueueInbound and
+	// MetricsQueueOutbound.
+	QueueDepth(sessionID SessionID, queue string, depth int)
+}
+
+var (
+	metricsLock      sync.RWMutex
+	metricsCollector MetricsCollector = noopMetrics{}
+)
+
+// SetMetricsCollector sets the MetricsCollector of all sessions, except those of engines created with WithMetrics.
+// A nil collector disables metrics.
+func SetMetricsCollector(collector MetricsCollector) {
+	if collector == nil {
+		collector = noopMetrics{}
+	}
+
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+	metricsCollector = collector
+}
+
+func globalMetrics() MetricsCollector {
+	metricsLock.RLock()
+	defer metricsLock.RUnlock()
+	return metricsCollector
+}
+
+// metrics returns the MetricsCollector of the session, see WithMetrics, or the one set with SetMetricsCollector.
+func (s *Session) metrics() MetricsCollector {
+	if s.metricsCollector != nil {
+		return s.metricsCollector
+	}
+	return globalMetrics()
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) MessageIn(SessionID, string)               {}
+func (noopMetrics) MessageOut(SessionID, string)              {}
+func (noopMetrics) ResendRequestIssued(SessionID)             {}
+func (noopMetrics) ResendRequestServed(SessionID)             {}
+func (noopMetrics) LogonAttempt(SessionID)                    {}
+func (noopMetrics) Disconnect(SessionID)                      {}
+func (noopMetrics) HeartbeatLatency(SessionID, time.Duration) {}
+func (noopMetrics) QueueDepth(SessionID, string, int)         {}
+
+// recordIncoming counts msg and observes the heartbeat latency when msg answers the outstanding TestRequest, see
+// LastHeartbeatRTT.
+func (s *Session) recordIncoming(msg *Message) {
+	msgType, err := msg.Header.GetString(tagMsgType)
+	if err != nil {
+		return
+	}
+
+	collector := s.metrics()
+	collector.MessageIn(s.sessionID, msgType)
+	s.stats.add(s.now(), statMessagesIn)
+	collector.QueueDepth(s.sessionID, MetricsQueueInbound, len(s.messageIn))
+
+	if s.testRequestSent.IsZero() || msgType != string(msgTypeHeartbeat) {
+		return
+	}
+	if testReqID, err := msg.Body.GetString(tagTestReqID); err == nil && testReqID == testRequestID {
+		received := msg.ReceiveTime
+		if received.IsZero() {
+			received = s.now()
+		}
+		rtt := received.Sub(s.testRequestSent)
+		collector.HeartbeatLatency(s.sessionID, rtt)
+		s.testRequestSent = time.Time{}
+		s.observeHeartbeatRTT(rtt)
+	}
+}
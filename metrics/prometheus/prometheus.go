@@ -0,0 +1,142 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Collector is a quickfix.MetricsCollector exporting the metrics of sessions as Prometheus counters, histograms and
+// gauges, labelled with the session id.
+type Collector struct {
+	messagesIn       *prometheus.CounterVec
+	messagesOut      *prometheus.CounterVec
+	resendsIssued    *prometheus.CounterVec
+	resendsServed    *prometheus.CounterVec
+	logonAttempts    *prometheus.CounterVec
+	disconnects      *prometheus.CounterVec
+	heartbeatLatency *prometheus.HistogramVec
+	queueDepth       *prometheus.GaugeVec
+}
+
+// NewCollector creates a Collector. Register it with a prometheus.Registerer, then set it with
+// quickfix.SetMetricsCollector or quickfix.WithMetrics, or use SetMetricsRegistry to do both.
+func NewCollector() *Collector {
+	counter := func(name, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "quickfix",
+			Name:      name,
+			Help:      help,
+		}, append([]string{"session"}, labels...))
+	}
+
+	return &Collector{
+		messagesIn:    counter("messages_in_total", "Messages received, by MsgType.", "msg_type"),
+		messagesOut:   counter("messages_out_total", "Messages sent, by MsgType. Resent messages are not counted.", "msg_type"),
+		resendsIssued: counter("resend_requests_issued_total", "ResendRequests sent to the counterparty."),
+		resendsServed: counter("resend_requests_served_total", "ResendRequests of the counterparty answered."),
+		logonAttempts: counter("logon_attempts_total", "Logons sent by an initiator or received by an acceptor."),
+		disconnects:   counter("disconnects_total", "Disconnects of connected sessions."),
+		heartbeatLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "quickfix",
+			Name:      "heartbeat_latency_seconds",
+			Help:      "Time between a TestRequest and the Heartbeat answering it.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+		}, []string{"session"}),
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "quickfix",
+			Name:      "queue_depth",
+			Help:      "Messages waiting in a queue of the session, by queue.",
+		}, []string{"session", "queue"}),
+	}
+}
+
+// SetMetricsRegistry registers a new Collector with reg and sets it as the MetricsCollector of all sessions, see
+// quickfix.SetMetricsCollector.
+func SetMetricsRegistry(reg prometheus.Registerer) (*Collector, error) {
+	c := NewCollector()
+	if err := reg.Register(c); err != nil {
+		return nil, err
+	}
+
+	quickfix.SetMetricsCollector(c)
+	return c, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.messagesIn, c.messagesOut, c.resendsIssued, c.resendsServed,
+		c.logonAttempts, c.disconnects, c.heartbeatLatency, c.queueDepth,
+	}
+}
+
+// MessageIn implements quickfix.MetricsCollector.
+func (c *Collector) MessageIn(sessionID quickfix.SessionID, msgType string) {
+	c.messagesIn.WithLabelValues(sessionID.String(), msgType).Inc()
+}
+
+// MessageOut implements quickfix.MetricsCollector.
+func (c *Collector) MessageOut(sessionID quickfix.SessionID, msgType string) {
+	c.messagesOut.WithLabelValues(sessionID.String(), msgType).Inc()
+}
+
+// ResendRequestIssued implements quickfix.MetricsCollector.
+func (c *Collector) ResendRequestIssued(sessionID quickfix.SessionID) {
+	c.resendsIssued.WithLabelValues(sessionID.String()).Inc()
+}
+
+// ResendRequestServed implements quickfix.MetricsCollector.
+func (c *Collector) ResendRequestServed(sessionID quickfix.SessionID) {
+	c.resendsServed.WithLabelValues(sessionID.String()).Inc()
+}
+
+// LogonAttempt implements quickfix.MetricsCollector.
+func (c *Collector) LogonAttempt(sessionID quickfix.SessionID) {
+	c.logonAttempts.WithLabelValues(sessionID.String()).Inc()
+}
+
+// Disconnect implements quickfix.MetricsCollector.
+func (c *Collector) Disconnect(sessionID quickfix.SessionID) {
+	c.disconnects.WithLabelValues(sessionID.String()).Inc()
+}
+
+// HeartbeatLatency implements quickfix.MetricsCollector.
+func (c *Collector) HeartbeatLatency(sessionID quickfix.SessionID, latency time.Duration) {
+	c.heartbeatLatency.WithLabelValues(sessionID.String()).Observe(latency.Seconds())
+}
+
+// QueueDepth implements quickfix.MetricsCollector.
+func (c *Collector) QueueDepth(sessionID quickfix.SessionID, queue string, depth int) {
+	c.queueDepth.WithLabelValues(sessionID.String(), queue).Set(float64(depth))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestSetMetricsRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c, err := SetMetricsRegistry(reg)
+	require.NoError(t, err)
+	defer quickfix.SetMetricsCollector(nil)
+
+	_, err = SetMetricsRegistry(reg)
+	assert.Error(t, err, "the metrics are registered once")
+
+	sessionID := quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	c.MessageIn(sessionID, "D")
+	c.MessageIn(sessionID, "D")
+	c.MessageOut(sessionID, "8")
+	c.ResendRequestIssued(sessionID)
+	c.ResendRequestServed(sessionID)
+	c.LogonAttempt(sessionID)
+	c.Disconnect(sessionID)
+	c.HeartbeatLatency(sessionID, 5*time.Millisecond)
+	c.QueueDepth(sessionID, quickfix.MetricsQueueOutbound, 3)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.messagesIn.WithLabelValues(sessionID.String(), "D")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(c.queueDepth.WithLabelValues(sessionID.String(), "outbound")))
+	assert.Equal(t, 1, testutil.CollectAndCount(c, "quickfix_heartbeat_latency_seconds"))
+
+	expected := `
+# HELP quickfix_resend_requests_issued_total ResendRequests sent to the counterparty.
+# TYPE quickfix_resend_requests_issued_total counter
+quickfix_resend_requests_issued_total{session="FIX.4.2:SENDER->TARGET"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "quickfix_resend_requests_issued_total"))
+}
@@ -0,0 +1,192 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type recordingMetrics struct {
+	mu                 sync.Mutex
+	in, out            map[string]int
+	resendsIssued      int
+	resendsServed      int
+	logonAttempts      int
+	disconnects        int
+	heartbeatLatencies []time.Duration
+	queueDepths        map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{in: map[string]int{}, out: map[string]int{}, queueDepths: map[string]int{}}
+}
+
+func (m *recordingMetrics) MessageIn(_ SessionID, msgType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.in[msgType]++
+}
+
+func (m *recordingMetrics) MessageOut(_ SessionID, msgType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.out[msgType]++
+}
+
+func (m *recordingMetrics) ResendRequestIssued(SessionID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resendsIssued++
+}
+
+func (m *recordingMetrics) ResendRequestServed(SessionID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resendsServed++
+}
+
+func (m *recordingMetrics) LogonAttempt(SessionID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logonAttempts++
+}
+
+func (m *recordingMetrics) Disconnect(SessionID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.disconnects++
+}
+
+func (m *recordingMetrics) HeartbeatLatency(_ SessionID, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heartbeatLatencies = append(m.heartbeatLatencies, latency)
+}
+
+func (m *recordingMetrics) QueueDepth(_ SessionID, queue string, depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queueDepths[queue] = depth
+}
+
+type MetricsTestSuite struct {
+	SessionSuiteRig
+	metrics *recordingMetrics
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (s *MetricsTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.metrics = newRecordingMetrics()
+	SetMetricsCollector(s.metrics)
+}
+
+func (s *MetricsTestSuite) TearDownTest() {
+	SetMetricsCollector(nil)
+}
+
+func (s *MetricsTestSuite) incoming(msg *Message, receiveTime time.Time) {
+	s.Session.Incoming(s.Session, fixIn{bytes: bytes.NewBuffer(msg.Build()), receiveTime: receiveTime})
+}
+
+func (s *MetricsTestSuite) TestMessagesInAndOut() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.incoming(s.NewOrderSingle(), time.Now())
+	s.incoming(s.NewOrderSingle(), time.Now())
+	s.incoming(s.Heartbeat(), time.Now())
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.Require().Nil(s.Session.send(s.Heartbeat()))
+
+	s.Equal(map[string]int{"D": 2, "0": 1}, s.metrics.in)
+	s.Equal(map[string]int{"D": 1, "0": 1}, s.metrics.out)
+}
+
+func (s *MetricsTestSuite) TestOutboundQueueDepth() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.queueForSend(s.NewOrderSingle()))
+	s.Require().Nil(s.Session.queueForSend(s.NewOrderSingle()))
+
+	s.Session.SendAppMessages(s.Session)
+	s.Equal(2, s.metrics.queueDepths[MetricsQueueOutbound])
+}
+
+func (s *MetricsTestSuite) TestHeartbeatLatency() {
+	s.MockApp.On("ToAdmin")
+	s.Session.Timeout(s.Session, internal.PeerTimeout)
+	s.State(pendingTimeout{inSession{}})
+	sent := s.Session.testRequestSent
+	s.False(sent.IsZero())
+
+	// A Heartbeat without the TestReqID does not answer the TestRequest.
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.incoming(s.Heartbeat(), sent.Add(time.Second))
+	s.Empty(s.metrics.heartbeatLatencies)
+
+	heartbeat := s.Heartbeat()
+	heartbeat.Body.SetString(tagTestReqID, testRequestID)
+	s.incoming(heartbeat, sent.Add(250*time.Millisecond))
+	s.Equal([]time.Duration{250 * time.Millisecond}, s.metrics.heartbeatLatencies)
+	s.True(s.Session.testRequestSent.IsZero())
+	s.State(inSession{})
+}
+
+func (s *MetricsTestSuite) TestResendRequests() {
+	s.MockApp.On("ToAdmin")
+	s.MockApp.On("FromApp").Return(nil)
+	s.MessageFactory.SetNextSeqNum(5)
+	s.incoming(s.NewOrderSingle(), time.Now())
+	s.Equal(1, s.metrics.resendsIssued)
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MessageFactory.SetNextSeqNum(1)
+	s.Session.State = inSession{}
+	s.incoming(s.ResendRequest(1), time.Now())
+	s.Equal(1, s.metrics.resendsServed)
+}
+
+func (s *MetricsTestSuite) TestLogonAttemptAndDisconnect() {
+	s.Session.State = latentState{}
+	s.Session.InitiateLogon = true
+	s.MockApp.On("ToAdmin")
+	s.Session.Connect(s.Session)
+	s.Equal(1, s.metrics.logonAttempts)
+
+	s.MockApp.On("OnLogout")
+	s.Session.Disconnected(s.Session)
+	s.Equal(1, s.metrics.disconnects)
+}
+
+func (s *MetricsTestSuite) TestDisabled() {
+	SetMetricsCollector(nil)
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.Empty(s.metrics.out)
+}
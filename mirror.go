@@ -0,0 +1,200 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultMirrorQueueSize = 1024
+
+// MirrorApplication receives copies of the application messages of the sessions a Mirror is attached to, for
+// surveillance or analytics. It cannot reject or alter the messages of the session.
+type MirrorApplication interface {
+	// OnInbound is called with a copy of an incoming application message that passed validation.
+	OnInbound(msg *Message, sessionID SessionID)
+
+	// OnOutbound is called with a copy of an outgoing application message, as sent.
+	OnOutbound(msg *Message, sessionID SessionID)
+}
+
+// MirrorOptions configure a Mirror.
+type MirrorOptions struct {
+	// Sessions the Mirror is attached to.
+	Sessions []SessionID
+
+	// QueueSize bounds the messages waiting for the MirrorApplication. Defaults to 1024.
+	QueueSize int
+}
+
+type mirroredMessage struct {
+	session  *Session
+	raw      []byte
+	outbound bool
+}
+
+// Mirror passes copies of application messages to a MirrorApplication on its own goroutine. Sessions never wait for
+// the Mirror: when its queue is full, copies are dropped and counted. Panics of the MirrorApplication are recovered
+// and counted as dropped.
+type Mirror struct {
+	app      MirrorApplication
+	queue    chan mirroredMessage
+	done     chan struct{}
+	dropped  atomic.Uint64
+	sessions []*Session
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewMirror returns a Mirror attached to the sessions of opts, which must exist.
+func NewMirror(app MirrorApplication, opts MirrorOptions) (*Mirror, error) {
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultMirrorQueueSize
+	}
+
+	var sessions []*Session
+	for _, sessionID := range opts.Sessions {
+		session, ok := lookupSession(sessionID)
+		if !ok {
+			return nil, ErrSessionNotFound
+		}
+		sessions = append(sessions, session)
+	}
+
+	m := &Mirror{
+		app:      app,
+		queue:    make(chan mirroredMessage, queueSize),
+		done:     make(chan struct{}),
+		sessions: sessions,
+	}
+	for _, session := range sessions {
+		session.mirrors.add(m)
+	}
+
+	go m.run()
+	return m, nil
+}
+
+// Dropped returns the number of copies dropped because the queue was full or the MirrorApplication panicked.
+func (m *Mirror) Dropped() uint64 {
+	return m.dropped.Load()
+}
+
+// Close detaches the Mirror from its sessions and waits for the queued copies to be delivered.
+func (m *Mirror) Close() {
+	for _, session := range m.sessions {
+		session.mirrors.remove(m)
+	}
+
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+
+	<-m.done
+}
+
+// publish queues a copy of raw without blocking.
+func (m *Mirror) publish(session *Session, raw []byte, outbound bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return
+	}
+
+	select {
+	case m.queue <- mirroredMessage{session: session, raw: raw, outbound: outbound}:
+	default:
+		m.dropped.Add(1)
+	}
+}
+
+func (m *Mirror) run() {
+	defer close(m.done)
+	for mirrored := range m.queue {
+		m.deliver(mirrored)
+	}
+}
+
+func (m *Mirror) deliver(mirrored mirroredMessage) {
+	defer func() {
+		if recover() != nil {
+			m.dropped.Add(1)
+		}
+	}()
+
+	// Parsed here rather than on the session goroutine, with the session's dictionaries and data fields.
+	session := mirrored.session
+	msg := NewMessage()
+	if err := session.ParseMessage(msg, bytes.NewBuffer(mirrored.raw)); err != nil {
+		m.dropped.Add(1)
+		return
+	}
+	if reject := session.compression.decompress(msg); reject != nil {
+		m.dropped.Add(1)
+		return
+	}
+
+	if mirrored.outbound {
+		m.app.OnOutbound(msg, session.sessionID)
+	} else {
+		m.app.OnInbound(msg, session.sessionID)
+	}
+}
+
+// sessionMirrors are the Mirrors attached to a session.
+type sessionMirrors struct {
+	sync.Mutex
+	mirrors []*Mirror
+}
+
+func (s *sessionMirrors) add(m *Mirror) {
+	s.Lock()
+	defer s.Unlock()
+	s.mirrors = append(s.mirrors, m)
+}
+
+func (s *sessionMirrors) remove(m *Mirror) {
+	s.Lock()
+	defer s.Unlock()
+	for i, mirror := range s.mirrors {
+		if mirror == m {
+			s.mirrors = append(s.mirrors[:i:i], s.mirrors[i+1:]...)
+			return
+		}
+	}
+}
+
+// publish passes a copy of the raw application message to the attached Mirrors.
+func (s *sessionMirrors) publish(session *Session, raw []byte, outbound bool) {
+	s.Lock()
+	mirrors := s.mirrors
+	s.Unlock()
+	if len(mirrors) == 0 {
+		return
+	}
+
+	raw = append([]byte(nil), raw...)
+	for _, m := range mirrors {
+		m.publish(session, raw, outbound)
+	}
+}
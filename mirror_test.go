@@ -0,0 +1,158 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type mirroredCopy struct {
+	msg       *Message
+	sessionID SessionID
+	outbound  bool
+}
+
+type mockMirrorApp struct {
+	copies  chan mirroredCopy
+	release chan struct{}
+}
+
+func (a *mockMirrorApp) OnInbound(msg *Message, sessionID SessionID) {
+	a.receive(mirroredCopy{msg: msg, sessionID: sessionID})
+}
+
+func (a *mockMirrorApp) OnOutbound(msg *Message, sessionID SessionID) {
+	a.receive(mirroredCopy{msg: msg, sessionID: sessionID, outbound: true})
+}
+
+func (a *mockMirrorApp) receive(c mirroredCopy) {
+	if a.release != nil {
+		<-a.release
+	}
+	a.copies <- c
+}
+
+type MirrorTestSuite struct {
+	SessionSuiteRig
+	app *mockMirrorApp
+}
+
+func TestMirrorTestSuite(t *testing.T) {
+	suite.Run(t, new(MirrorTestSuite))
+}
+
+func (s *MirrorTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+	s.app = &mockMirrorApp{copies: make(chan mirroredCopy, 10)}
+}
+
+func (s *MirrorTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *MirrorTestSuite) TestOutboundCopies() {
+	mirror, err := NewMirror(s.app, MirrorOptions{Sessions: []SessionID{s.sessionID}})
+	s.Require().Nil(err)
+
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(s.Session.send(s.Heartbeat()))
+
+	msg := s.NewOrderSingle()
+	msg.Body.SetString(tagClOrdID, "order1")
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(msg))
+	mirror.Close()
+
+	s.Require().Len(s.app.copies, 1, "only application messages are mirrored")
+	c := <-s.app.copies
+	s.True(c.outbound)
+	s.Equal(s.sessionID, c.sessionID)
+	s.FieldEquals(tagClOrdID, "order1", c.msg.Body)
+	s.FieldEquals(tagMsgSeqNum, 2, c.msg.Header)
+
+	// The copy is independent of the sent message.
+	c.msg.Body.SetString(tagClOrdID, "changed")
+	s.FieldEquals(tagClOrdID, "order1", s.MockApp.lastToApp.Body)
+}
+
+func (s *MirrorTestSuite) TestInboundCopies() {
+	mirror, err := NewMirror(s.app, MirrorOptions{Sessions: []SessionID{s.sessionID}})
+	s.Require().Nil(err)
+
+	msg := s.NewOrderSingle()
+	msg.Body.SetString(tagClOrdID, "order1")
+	s.MockApp.On("FromApp").Return(nil)
+	s.Session.fixMsgIn(s.Session, msg)
+	s.MockApp.AssertExpectations(s.T())
+	mirror.Close()
+
+	s.Require().Len(s.app.copies, 1)
+	c := <-s.app.copies
+	s.False(c.outbound)
+	s.FieldEquals(tagClOrdID, "order1", c.msg.Body)
+}
+
+func (s *MirrorTestSuite) TestRejectedInboundNotCopied() {
+	mirror, err := NewMirror(s.app, MirrorOptions{Sessions: []SessionID{s.sessionID}})
+	s.Require().Nil(err)
+
+	msg := s.NewOrderSingle()
+	msg.Header.SetString(tagSenderCompID, "OTHER")
+	s.MockApp.On("ToAdmin")
+	s.Session.fixMsgIn(s.Session, msg)
+	mirror.Close()
+
+	s.MockApp.AssertNotCalled(s.T(), "FromApp")
+	s.Empty(s.app.copies)
+}
+
+func (s *MirrorTestSuite) TestFullQueueDrops() {
+	s.app.release = make(chan struct{})
+	mirror, err := NewMirror(s.app, MirrorOptions{Sessions: []SessionID{s.sessionID}, QueueSize: 1})
+	s.Require().Nil(err)
+
+	// The mirror is blocked on the first copy, the second is queued, the rest are dropped.
+	s.MockApp.On("ToApp").Return(nil)
+	for i := 0; i < 5; i++ {
+		s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	}
+	s.GreaterOrEqual(mirror.Dropped(), uint64(3))
+
+	close(s.app.release)
+	mirror.Close()
+	s.Equal(5, len(s.app.copies)+int(mirror.Dropped()))
+}
+
+func (s *MirrorTestSuite) TestCloseDetaches() {
+	mirror, err := NewMirror(s.app, MirrorOptions{Sessions: []SessionID{s.sessionID}})
+	s.Require().Nil(err)
+	mirror.Close()
+	s.Empty(s.Session.mirrors.mirrors)
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.Empty(s.app.copies)
+}
+
+func (s *MirrorTestSuite) TestUnknownSession() {
+	other := SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}
+	_, err := NewMirror(s.app, MirrorOptions{Sessions: []SessionID{other}})
+	s.Equal(ErrSessionNotFound, err)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package orderstate
+
+import (
+	"github.com/quickfixgo/quickfix"
+)
+
+// Application is a quickfix.Application passing the messages accepted by the wrapped Application to a Tracker.
+// Messages are tracked when passed to ToApp, before they are persisted, so an order rejected afterwards, for
+// instance by a quickfix.RiskChecker, remains pending new.
+type Application struct {
+	quickfix.Application
+	tracker *Tracker
+
+	// OnError is called with the errors of the Tracker, which otherwise are ignored.
+	OnError func(err error, sessionID quickfix.SessionID)
+}
+
+// NewApplication returns an Application wrapping app and tracking its messages with tracker.
+func NewApplication(app quickfix.Application, tracker *Tracker) *Application {
+	return &Application{Application: app, tracker: tracker}
+}
+
+// ToApp implements quickfix.Application.
+func (a *Application) ToApp(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+	if err := a.Application.ToApp(msg, sessionID); err != nil {
+		return err
+	}
+	a.handle(a.tracker.Sent(msg, sessionID), sessionID)
+	return nil
+}
+
+// FromApp implements quickfix.Application.
+func (a *Application) FromApp(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	a.handle(a.tracker.Received(msg, sessionID), sessionID)
+	return a.Application.FromApp(msg, sessionID)
+}
+
+func (a *Application) handle(err error, sessionID quickfix.SessionID) {
+	if err != nil && a.OnError != nil {
+		a.OnError(err, sessionID)
+	}
+}
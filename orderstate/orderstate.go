@@ -0,0 +1,285 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package orderstate provides an optional tracker of the state of the orders of sessions, indexing the outgoing
+// orders and the incoming ExecutionReports by their ClOrdID chains and OrderIDs.
+package orderstate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const (
+	tagClOrdID     quickfix.Tag = 11
+	tagOrderID     quickfix.Tag = 37
+	tagOrigClOrdID quickfix.Tag = 41
+	tagOrdStatus   quickfix.Tag = 39
+	tagSymbol      quickfix.Tag = 55
+	tagSide        quickfix.Tag = 54
+	tagOrderQty    quickfix.Tag = 38
+	tagPrice       quickfix.Tag = 44
+	tagCumQty      quickfix.Tag = 14
+	tagLeavesQty   quickfix.Tag = 151
+	tagText        quickfix.Tag = 58
+	tagTimeInForce quickfix.Tag = 59
+)
+
+// OrdStatus values set by the Tracker for orders awaiting a response.
+const (
+	StatusPendingNew     = "A"
+	StatusPendingCancel  = "6"
+	StatusPendingReplace = "E"
+)
+
+// Order is the state of an order.
+type Order struct {
+	SessionID quickfix.SessionID
+
+	// ClOrdID is the last ClOrdID of the order, to be used as OrigClOrdID to cancel or replace it.
+	ClOrdID string
+
+	// ClOrdIDs is the chain of the ClOrdIDs of the order, oldest first.
+	ClOrdIDs []string
+
+	// OrderID is assigned by the counterparty, empty until the first ExecutionReport.
+	OrderID   string
+	Symbol    string
+	Side      string
+	OrderQty  string
+	Price     string
+	CumQty    string
+	LeavesQty string
+	OrdStatus string
+	Text      string
+	Created   time.Time
+	Updated   time.Time
+
+	// TimeInForce is empty for a day order sent without TimeInForce.
+	TimeInForce string
+}
+
+// IsOpen returns whether the order may still be executed.
+func (o Order) IsOpen() bool {
+	switch o.OrdStatus {
+	case "2", "3", "4", "8", "C": // Filled, DoneForDay, Canceled, Rejected, Expired
+		return false
+	}
+	return true
+}
+
+// Tracker tracks the orders sent and the ExecutionReports and OrderCancelRejects received by sessions, see
+// NewApplication. The messages of other types are ignored.
+type Tracker struct {
+	store Store
+	now   func() time.Time
+
+	// Updates of an order are read-modify-write.
+	mu sync.Mutex
+}
+
+// NewTracker returns a Tracker saving the orders to store.
+func NewTracker(store Store) *Tracker {
+	return &Tracker{store: store, now: time.Now}
+}
+
+// Sent tracks msg sent by the session: NewOrderSingle, OrderCancelRequest and OrderCancelReplaceRequest.
+func (t *Tracker) Sent(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+	msgType, rejectErr := msg.MsgType()
+	if rejectErr != nil {
+		return rejectErr
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch msgType {
+	case "D":
+		now := t.now()
+		order := Order{SessionID: sessionID, OrdStatus: StatusPendingNew, Created: now, Updated: now}
+		if order.ClOrdID, rejectErr = msg.Body.GetString(tagClOrdID); rejectErr != nil {
+			return rejectErr
+		}
+		order.ClOrdIDs = []string{order.ClOrdID}
+		order.Symbol, _ = msg.Body.GetString(tagSymbol)
+		order.Side, _ = msg.Body.GetString(tagSide)
+		order.OrderQty, _ = msg.Body.GetString(tagOrderQty)
+		order.Price, _ = msg.Body.GetString(tagPrice)
+		order.TimeInForce, _ = msg.Body.GetString(tagTimeInForce)
+		return t.store.Put(order)
+
+	case "F", "G":
+		origClOrdID, rejectErr := msg.Body.GetString(tagOrigClOrdID)
+		if rejectErr != nil {
+			return rejectErr
+		}
+		clOrdID, rejectErr := msg.Body.GetString(tagClOrdID)
+		if rejectErr != nil {
+			return rejectErr
+		}
+
+		order, ok, err := t.store.Get(sessionID, origClOrdID)
+		if err != nil || !ok {
+			return err
+		}
+		order.ClOrdID = clOrdID
+		order.ClOrdIDs = append(order.ClOrdIDs, clOrdID)
+		order.OrdStatus = StatusPendingCancel
+		if msgType == "G" {
+			order.OrdStatus = StatusPendingReplace
+		}
+		order.Updated = t.now()
+		return t.store.Put(order)
+	}
+	return nil
+}
+
+// Received tracks msg received by the session: ExecutionReport and OrderCancelReject.
+func (t *Tracker) Received(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+	msgType, rejectErr := msg.MsgType()
+	if rejectErr != nil {
+		return rejectErr
+	}
+	if msgType != "8" && msgType != "9" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok, err := t.lookup(msg, sessionID)
+	if err != nil || !ok {
+		return err
+	}
+
+	if ordStatus, err := msg.Body.GetString(tagOrdStatus); err == nil {
+		order.OrdStatus = ordStatus
+	}
+	if orderID, err := msg.Body.GetString(tagOrderID); err == nil && orderID != "NONE" {
+		order.OrderID = orderID
+	}
+	order.Text, _ = msg.Body.GetString(tagText)
+	order.Updated = t.now()
+
+	if msgType == "9" {
+		// The rejected ClOrdID is not to be used as OrigClOrdID.
+		if origClOrdID, err := msg.Body.GetString(tagOrigClOrdID); err == nil {
+			order.ClOrdID = origClOrdID
+		}
+		return t.store.Put(order)
+	}
+
+	if clOrdID, err := msg.Body.GetString(tagClOrdID); err == nil {
+		order.ClOrdID = clOrdID
+		if !contains(order.ClOrdIDs, clOrdID) {
+			order.ClOrdIDs = append(order.ClOrdIDs, clOrdID)
+		}
+	}
+	for tag, value := range map[quickfix.Tag]*string{
+		tagOrderQty:  &order.OrderQty,
+		tagPrice:     &order.Price,
+		tagCumQty:    &order.CumQty,
+		tagLeavesQty: &order.LeavesQty,
+	} {
+		if v, err := msg.Body.GetString(tag); err == nil {
+			*value = v
+		}
+	}
+	return t.store.Put(order)
+}
+
+// lookup returns the order msg refers to, by ClOrdID, OrigClOrdID then OrderID.
+func (t *Tracker) lookup(msg *quickfix.Message, sessionID quickfix.SessionID) (Order, bool, error) {
+	for _, tag := range []quickfix.Tag{tagClOrdID, tagOrigClOrdID} {
+		if clOrdID, err := msg.Body.GetString(tag); err == nil {
+			if order, ok, err := t.store.Get(sessionID, clOrdID); err != nil || ok {
+				return order, ok, err
+			}
+		}
+	}
+	if orderID, err := msg.Body.GetString(tagOrderID); err == nil {
+		return t.store.GetByOrderID(sessionID, orderID)
+	}
+	return Order{}, false, nil
+}
+
+// Order returns the order of the session with clOrdID in its chain.
+func (t *Tracker) Order(sessionID quickfix.SessionID, clOrdID string) (Order, bool, error) {
+	return t.store.Get(sessionID, clOrdID)
+}
+
+// OpenOrders returns the open orders of the session, oldest first.
+func (t *Tracker) OpenOrders(sessionID quickfix.SessionID) ([]Order, error) {
+	return t.openOrders(sessionID, func(Order) bool { return true })
+}
+
+// OpenOrdersBySymbol returns the open orders of the session for symbol, oldest first.
+func (t *Tracker) OpenOrdersBySymbol(sessionID quickfix.SessionID, symbol string) ([]Order, error) {
+	return t.openOrders(sessionID, func(o Order) bool { return o.Symbol == symbol })
+}
+
+func (t *Tracker) openOrders(sessionID quickfix.SessionID, match func(Order) bool) ([]Order, error) {
+	orders, err := t.store.List(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var open []Order
+	for _, order := range orders {
+		if order.IsOpen() && match(order) {
+			open = append(open, order)
+		}
+	}
+	return open, nil
+}
+
+// OrderSource returns a quickfix.OrderSource of the open orders of the Tracker, for a quickfix.KillSwitch.
+func (t *Tracker) OrderSource() quickfix.OrderSource {
+	return orderSource{t}
+}
+
+type orderSource struct {
+	tracker *Tracker
+}
+
+func (s orderSource) OpenOrders(sessionID quickfix.SessionID) ([]quickfix.OpenOrder, error) {
+	orders, err := s.tracker.OpenOrders(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make([]quickfix.OpenOrder, len(orders))
+	for i, order := range orders {
+		open[i] = quickfix.OpenOrder{
+			ClOrdID:     order.ClOrdID,
+			Symbol:      order.Symbol,
+			Side:        order.Side,
+			OrderQty:    order.OrderQty,
+			TimeInForce: order.TimeInForce,
+		}
+	}
+	return open, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package orderstate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+var sessionID = quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+func message(msgType string, fields map[quickfix.Tag]string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(35), msgType)
+	for tag, value := range fields {
+		msg.Body.SetString(tag, value)
+	}
+	return msg
+}
+
+func clOrdIDs(orders []Order) (ids []string) {
+	for _, order := range orders {
+		ids = append(ids, order.ClOrdID)
+	}
+	return
+}
+
+func TestTrackerChain(t *testing.T) {
+	tracker := NewTracker(NewMemoryStore())
+
+	require.NoError(t, tracker.Sent(message("D", map[quickfix.Tag]string{tagClOrdID: "1", tagSymbol: "MSFT", tagSide: "1", tagOrderQty: "100", tagPrice: "10", tagTimeInForce: "1"}), sessionID))
+	require.NoError(t, tracker.Sent(message("D", map[quickfix.Tag]string{tagClOrdID: "2", tagSymbol: "IBM", tagSide: "2", tagOrderQty: "50"}), sessionID))
+
+	order, ok, err := tracker.Order(sessionID, "1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, StatusPendingNew, order.OrdStatus)
+	assert.Equal(t, "MSFT", order.Symbol)
+	assert.Equal(t, "1", order.TimeInForce)
+
+	require.NoError(t, tracker.Received(message("8", map[quickfix.Tag]string{tagClOrdID: "1", tagOrderID: "X1", tagOrdStatus: "0", tagCumQty: "0", tagLeavesQty: "100"}), sessionID))
+	require.NoError(t, tracker.Sent(message("G", map[quickfix.Tag]string{tagClOrdID: "1a", tagOrigClOrdID: "1", tagOrderQty: "200"}), sessionID))
+
+	order, _, _ = tracker.Order(sessionID, "1a")
+	assert.Equal(t, StatusPendingReplace, order.OrdStatus)
+	assert.Equal(t, []string{"1", "1a"}, order.ClOrdIDs)
+
+	// A replace confirmed by OrderID only.
+	require.NoError(t, tracker.Received(message("8", map[quickfix.Tag]string{tagOrderID: "X1", tagOrdStatus: "0", tagOrderQty: "200", tagLeavesQty: "200"}), sessionID))
+	order, _, _ = tracker.Order(sessionID, "1")
+	assert.Equal(t, "0", order.OrdStatus)
+	assert.Equal(t, "200", order.OrderQty)
+	assert.Equal(t, "1a", order.ClOrdID)
+
+	// A rejected cancel reverts the ClOrdID.
+	require.NoError(t, tracker.Sent(message("F", map[quickfix.Tag]string{tagClOrdID: "1b", tagOrigClOrdID: "1a"}), sessionID))
+	require.NoError(t, tracker.Received(message("9", map[quickfix.Tag]string{tagClOrdID: "1b", tagOrigClOrdID: "1a", tagOrderID: "X1", tagOrdStatus: "0", tagText: "too late"}), sessionID))
+	order, _, _ = tracker.Order(sessionID, "1b")
+	assert.Equal(t, "1a", order.ClOrdID)
+	assert.Equal(t, "too late", order.Text)
+
+	open, err := tracker.OpenOrders(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1a", "2"}, clOrdIDs(open))
+
+	open, err = tracker.OpenOrdersBySymbol(sessionID, "IBM")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, clOrdIDs(open))
+
+	require.NoError(t, tracker.Received(message("8", map[quickfix.Tag]string{tagClOrdID: "2", tagOrderID: "X2", tagOrdStatus: "8"}), sessionID))
+	cancels, err := tracker.OrderSource().OpenOrders(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, []quickfix.OpenOrder{{ClOrdID: "1a", Symbol: "MSFT", Side: "1", OrderQty: "200", TimeInForce: "1"}}, cancels)
+
+	// Unknown orders are ignored.
+	require.NoError(t, tracker.Received(message("8", map[quickfix.Tag]string{tagClOrdID: "unknown", tagOrdStatus: "0"}), sessionID))
+	require.NoError(t, tracker.Sent(message("F", map[quickfix.Tag]string{tagClOrdID: "3", tagOrigClOrdID: "unknown"}), sessionID))
+	_, ok, _ = tracker.Order(sessionID, "unknown")
+	assert.False(t, ok)
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.jsonl")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	tracker := NewTracker(store)
+	require.NoError(t, tracker.Sent(message("D", map[quickfix.Tag]string{tagClOrdID: "1", tagSymbol: "MSFT"}), sessionID))
+	require.NoError(t, tracker.Received(message("8", map[quickfix.Tag]string{tagClOrdID: "1", tagOrderID: "X1", tagOrdStatus: "1"}), sessionID))
+	require.NoError(t, store.Close())
+
+	store, err = NewFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	order, ok, err := store.GetByOrderID(sessionID, "X1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "1", order.ClOrdID)
+	assert.Equal(t, "1", order.OrdStatus)
+	assert.Equal(t, sessionID, order.SessionID)
+}
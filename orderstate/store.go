@@ -0,0 +1,191 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package orderstate
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Store persists the orders of a Tracker.
+type Store interface {
+	// Put saves order, indexed by each ClOrdID of its chain and by its OrderID.
+	Put(order Order) error
+
+	// Get returns the order of the session with clOrdID in its chain.
+	Get(sessionID quickfix.SessionID, clOrdID string) (Order, bool, error)
+
+	// GetByOrderID returns the order of the session assigned orderID by the counterparty.
+	GetByOrderID(sessionID quickfix.SessionID, orderID string) (Order, bool, error)
+
+	// List returns the orders of the session, oldest first.
+	List(sessionID quickfix.SessionID) ([]Order, error)
+
+	Close() error
+}
+
+type orderKey struct {
+	sessionID quickfix.SessionID
+	id        string
+}
+
+// memoryStore is an in-memory Store.
+type memoryStore struct {
+	mu        sync.RWMutex
+	orders    map[orderKey]*Order
+	keys      []orderKey
+	byClOrdID map[orderKey]*Order
+	byOrderID map[orderKey]*Order
+}
+
+// NewMemoryStore returns a Store keeping the orders in memory.
+func NewMemoryStore() Store {
+	return newMemoryStore()
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		orders:    make(map[orderKey]*Order),
+		byClOrdID: make(map[orderKey]*Order),
+		byOrderID: make(map[orderKey]*Order),
+	}
+}
+
+func (s *memoryStore) Put(order Order) error {
+	if len(order.ClOrdIDs) == 0 {
+		return errors.New("order has no ClOrdID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Orders are identified by the first ClOrdID of their chain.
+	key := orderKey{order.SessionID, order.ClOrdIDs[0]}
+	stored := s.orders[key]
+	if stored == nil {
+		stored = new(Order)
+		s.orders[key] = stored
+		s.keys = append(s.keys, key)
+	}
+	*stored = order
+	stored.ClOrdIDs = append([]string(nil), order.ClOrdIDs...)
+
+	for _, clOrdID := range stored.ClOrdIDs {
+		s.byClOrdID[orderKey{order.SessionID, clOrdID}] = stored
+	}
+	if stored.OrderID != "" {
+		s.byOrderID[orderKey{order.SessionID, stored.OrderID}] = stored
+	}
+	return nil
+}
+
+func (s *memoryStore) get(index map[orderKey]*Order, key orderKey) (Order, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stored, ok := index[key]
+	if !ok {
+		return Order{}, false, nil
+	}
+	order := *stored
+	order.ClOrdIDs = append([]string(nil), stored.ClOrdIDs...)
+	return order, true, nil
+}
+
+func (s *memoryStore) Get(sessionID quickfix.SessionID, clOrdID string) (Order, bool, error) {
+	return s.get(s.byClOrdID, orderKey{sessionID, clOrdID})
+}
+
+func (s *memoryStore) GetByOrderID(sessionID quickfix.SessionID, orderID string) (Order, bool, error) {
+	return s.get(s.byOrderID, orderKey{sessionID, orderID})
+}
+
+func (s *memoryStore) List(sessionID quickfix.SessionID) ([]Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var orders []Order
+	for _, key := range s.keys {
+		if key.sessionID != sessionID {
+			continue
+		}
+		stored := s.orders[key]
+		order := *stored
+		order.ClOrdIDs = append([]string(nil), stored.ClOrdIDs...)
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func (s *memoryStore) Close() error { return nil }
+
+// fileStore is a Store appending each saved order to a file as a JSON line, replayed when opened.
+type fileStore struct {
+	*memoryStore
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileStore returns a Store persisting the orders to the file at path, created if missing. The orders saved
+// by previous runs are loaded when opened.
+func NewFileStore(path string) (Store, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening order file %v", path)
+	}
+
+	store := &fileStore{memoryStore: newMemoryStore(), file: file}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var order Order
+		if err := json.Unmarshal(scanner.Bytes(), &order); err != nil {
+			_ = file.Close()
+			return nil, errors.Wrapf(err, "reading order file %v", path)
+		}
+		if err := store.memoryStore.Put(order); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, errors.Wrapf(err, "reading order file %v", path)
+	}
+	return store, nil
+}
+
+func (s *fileStore) Put(order Order) error {
+	line, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "writing order file")
+	}
+	return s.memoryStore.Put(order)
+}
+
+func (s *fileStore) Close() error {
+	return s.file.Close()
+}
@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"io"
@@ -31,12 +32,34 @@ type parser struct {
 	bigBuffer, buffer []byte
 	reader            io.Reader
 	lastRead          time.Time
+
+	// Reads the messages in place of the tag=value framing if set, from decoded, see decodeWith.
+	decoder RawMessageDecoder
+	decoded *bufio.Reader
 }
 
 func newParser(reader io.Reader) *parser {
 	return &parser{reader: reader}
 }
 
+// newDecodingParser returns a parser of the messages read from reader, decoded by decoder unless it is nil.
+func newDecodingParser(reader io.Reader, decoder RawMessageDecoder) *parser {
+	p := newParser(reader)
+	p.decodeWith(decoder)
+	return p
+}
+
+// decodeWith has the messages read from then on decoded by decoder, starting with the bytes already buffered. Does
+// nothing if decoder is nil.
+func (p *parser) decodeWith(decoder RawMessageDecoder) {
+	if decoder == nil {
+		return
+	}
+	p.decoder = decoder
+	p.decoded = bufio.NewReader(io.MultiReader(bytes.NewReader(p.buffer), p.reader))
+	p.buffer = nil
+}
+
 func (p *parser) readMore() (int, error) {
 	if len(p.buffer) == cap(p.buffer) {
 		var newBuffer []byte
@@ -140,6 +163,15 @@ func (p *parser) jumpLength() (int, error) {
 }
 
 func (p *parser) ReadMessage() (msgBytes *bytes.Buffer, err error) {
+	if p.decoder != nil {
+		var decoded []byte
+		if decoded, err = p.decoder.DecodeMessage(p.decoded); err != nil {
+			return
+		}
+		p.lastRead = time.Now()
+		return bytes.NewBuffer(decoded), nil
+	}
+
 	start, err := p.findStart()
 	if err != nil {
 		return
@@ -163,3 +195,26 @@ func (p *parser) ReadMessage() (msgBytes *bytes.Buffer, err error) {
 
 	return
 }
+
+// errNoBufferedMessage is returned by the reader of readBuffered, as it does not read from the connection.
+var errNoBufferedMessage = errors.New("no buffered message")
+
+type bufferedOnlyReader struct{}
+
+func (bufferedOnlyReader) Read([]byte) (int, error) { return 0, errNoBufferedMessage }
+
+// readBuffered returns the next message if it has been read from the connection already, without reading more.
+// A message received incomplete is returned by the next ReadMessage, as is an error. Decoded messages are not
+// batched, as the decoder reads from the connection.
+func (p *parser) readBuffered() (*bytes.Buffer, bool) {
+	if p.decoder != nil {
+		return nil, false
+	}
+
+	reader, lastRead := p.reader, p.lastRead
+	p.reader = bufferedOnlyReader{}
+	msgBytes, err := p.ReadMessage()
+	p.reader, p.lastRead = reader, lastRead
+
+	return msgBytes, err == nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+)
+
+// ErrPendingMessageNotFound is returned when canceling a message that is not pending, because it was already sent
+// or dropped.
+var ErrPendingMessageNotFound = errors.New("pending message not found")
+
+// PendingMessage is an application message queued for send but not yet transmitted.
+type PendingMessage struct {
+	// ID is the MsgSeqNum of the message.
+	ID      int
+	MsgType string
+	Message *Message
+}
+
+// PendingCancelHandler is an optional interface implemented by an Application to be notified of the pending
+// messages canceled with CancelPending.
+type PendingCancelHandler interface {
+	OnPendingCanceled(msg PendingMessage, sessionID SessionID)
+}
+
+// PendingMessages returns the application messages queued for send but not yet transmitted, oldest first.
+func (s *Session) PendingMessages() []PendingMessage {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	var pending []PendingMessage
+	for _, msgBytes := range s.toSend {
+		if p, ok := s.parsePending(msgBytes); ok {
+			pending = append(pending, p)
+		}
+	}
+	return pending
+}
+
+// CancelPending removes the pending message id before it is transmitted. As its MsgSeqNum is already assigned, the
+// message is replaced by a SequenceReset-GapFill in the send queue, and gap filled if a ResendRequest covers it.
+//
+// Canceled messages are remembered in memory only: the stored message is resent if the session restarts before the
+// counterparty requested it.
+func (s *Session) CancelPending(id int) error {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	for i, msgBytes := range s.toSend {
+		p, ok := s.parsePending(msgBytes)
+		if !ok || p.ID != id {
+			continue
+		}
+
+		s.toSend[i] = s.buildGapFill(id)
+		s.canceled.add(id)
+		s.acks.forget(id)
+
+		s.log.OnEventf("Canceled pending message %v (MsgType %v)", p.ID, p.MsgType)
+		if handler, ok := s.application.(PendingCancelHandler); ok {
+			handler.OnPendingCanceled(p, s.sessionID)
+		}
+		return nil
+	}
+
+	return ErrPendingMessageNotFound
+}
+
+// parsePending parses queued msgBytes, returning false if it is not an application message sent for the first time.
+func (s *Session) parsePending(msgBytes []byte) (p PendingMessage, ok bool) {
+	msg := NewMessage()
+	if err := s.ParseMessage(msg, bytes.NewBuffer(append([]byte(nil), msgBytes...))); err != nil {
+		return
+	}
+
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil || isAdminMessageType(msgType) {
+		return
+	}
+
+	if msg.Header.Has(tagPossDupFlag) {
+		var possDup FIXBoolean
+		if err := msg.Header.GetField(tagPossDupFlag, &possDup); err != nil || possDup.Bool() {
+			return
+		}
+	}
+
+	seqNum, err := msg.Header.GetInt(tagMsgSeqNum)
+	if err != nil {
+		return
+	}
+	return PendingMessage{ID: seqNum, MsgType: string(msgType), Message: msg}, true
+}
+
+// buildGapFill returns a SequenceReset-GapFill taking the place of the message seqNum.
+func (s *Session) buildGapFill(seqNum int) []byte {
+	sequenceReset := NewMessage()
+	s.fillDefaultHeader(sequenceReset, nil)
+
+	sequenceReset.Header.SetField(tagMsgType, FIXString("4"))
+	sequenceReset.Header.SetField(tagMsgSeqNum, FIXInt(seqNum))
+	sequenceReset.Header.SetField(tagPossDupFlag, FIXBoolean(true))
+	sequenceReset.Body.SetField(tagNewSeqNo, FIXInt(seqNum+1))
+	sequenceReset.Body.SetField(tagGapFillFlag, FIXBoolean(true))
+
+	var origSendingTime FIXString
+	if err := sequenceReset.Header.GetField(tagSendingTime, &origSendingTime); err == nil {
+		sequenceReset.Header.SetField(tagOrigSendingTime, origSendingTime)
+	}
+
+	s.application.ToAdmin(sequenceReset, s.sessionID)
+	return sequenceReset.Build()
+}
+
+// canceledMessages is the set of sequence numbers of the messages canceled with CancelPending.
+type canceledMessages struct {
+	sync.Mutex
+	seqNums map[int]struct{}
+}
+
+func (c *canceledMessages) add(seqNum int) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.seqNums == nil {
+		c.seqNums = make(map[int]struct{})
+	}
+	c.seqNums[seqNum] = struct{}{}
+}
+
+func (c *canceledMessages) has(seqNum int) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	_, ok := c.seqNums[seqNum]
+	return ok
+}
+
+func (c *canceledMessages) clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.seqNums = nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PendingTestSuite struct {
+	SessionSuiteRig
+	canceled []PendingMessage
+}
+
+func TestPendingTestSuite(t *testing.T) {
+	suite.Run(t, new(PendingTestSuite))
+}
+
+type pendingCancelApp struct {
+	*MockApp
+	suite *PendingTestSuite
+}
+
+func (a *pendingCancelApp) OnPendingCanceled(msg PendingMessage, _ SessionID) {
+	a.suite.canceled = append(a.suite.canceled, msg)
+}
+
+func (s *PendingTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.application = &pendingCancelApp{MockApp: &s.MockApp, suite: s}
+	s.canceled = nil
+}
+
+func (s *PendingTestSuite) sendOrder(clOrdID string) {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("D"))
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	s.Require().Nil(s.Session.queueForSend(msg))
+}
+
+func (s *PendingTestSuite) TestPendingMessages() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.Empty(s.Session.PendingMessages())
+
+	s.sendOrder("order1")
+	s.Require().Nil(s.Session.queueForSend(s.Heartbeat()))
+	s.sendOrder("order2")
+
+	pending := s.Session.PendingMessages()
+	s.Require().Len(pending, 2)
+	s.Equal(1, pending[0].ID)
+	s.Equal("D", pending[0].MsgType)
+	s.FieldEquals(tagClOrdID, "order1", pending[0].Message.Body)
+	s.Equal(3, pending[1].ID)
+	s.FieldEquals(tagClOrdID, "order2", pending[1].Message.Body)
+}
+
+func (s *PendingTestSuite) TestCancelPending() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.sendOrder("order1")
+	s.sendOrder("order2")
+
+	s.Equal(ErrPendingMessageNotFound, s.Session.CancelPending(3))
+	s.Require().Nil(s.Session.CancelPending(1))
+	s.Equal(ErrPendingMessageNotFound, s.Session.CancelPending(1))
+
+	pending := s.Session.PendingMessages()
+	s.Require().Len(pending, 1)
+	s.Equal(2, pending[0].ID)
+
+	s.Require().Len(s.canceled, 1)
+	s.Equal(1, s.canceled[0].ID)
+	s.FieldEquals(tagClOrdID, "order1", s.canceled[0].Message.Body)
+
+	// The canceled message is replaced by a gap fill in the queue.
+	s.Require().Len(s.Session.toSend, 2)
+	gapFill := NewMessage()
+	s.Require().Nil(ParseMessage(gapFill, bytes.NewBuffer(s.Session.toSend[0])))
+	s.MessageType("4", gapFill)
+	s.FieldEquals(tagMsgSeqNum, 1, gapFill.Header)
+	s.FieldEquals(tagNewSeqNo, 2, gapFill.Body)
+	s.FieldEquals(tagGapFillFlag, true, gapFill.Body)
+	s.NextSenderMsgSeqNum(3)
+}
+
+func (s *PendingTestSuite) TestCanceledMessageNotResent() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.sendOrder("order1")
+	s.sendOrder("order2")
+	s.Require().Nil(s.Session.CancelPending(1))
+
+	s.Session.sendMutex.Lock()
+	s.Session.dropQueued()
+	s.Session.sendMutex.Unlock()
+
+	s.Require().Nil(inSession{}.resendMessages(s.Session, 1, 2, *NewMessage()))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 3)
+	s.FieldEquals(tagClOrdID, "order2", s.MockApp.lastToApp.Body)
+	s.FieldEquals(tagPossDupFlag, true, s.MockApp.lastToApp.Header)
+
+	// Forgotten once the store is reset.
+	s.Require().Nil(s.Session.dropAndReset())
+	s.False(s.Session.canceled.has(1))
+}
+
+func (s *PendingTestSuite) TestCancelPendingForgetsAck() {
+	s.Session.AckTimeout = time.Minute
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.sendOrder("order1")
+	s.Equal(1, s.Session.acks.len())
+
+	s.Require().Nil(s.Session.CancelPending(1))
+	s.Equal(0, s.Session.acks.len())
+}
+
+func (s *PendingTestSuite) TestSentMessagesNotPending() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.sendOrder("order1")
+
+	s.Session.sendMutex.Lock()
+	s.Session.dropQueued()
+	s.Session.sendMutex.Unlock()
+
+	s.Empty(s.Session.PendingMessages())
+	s.Equal(ErrPendingMessageNotFound, s.Session.CancelPending(1))
+}
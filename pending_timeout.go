@@ -21,6 +21,8 @@ type pendingTimeout struct {
 	sessionState
 }
 
+func (s pendingTimeout) String() string { return "Pending Timeout" }
+
 func (s pendingTimeout) Timeout(session *Session, event internal.Event) (nextState sessionState) {
 	switch event {
 	case internal.PeerTimeout:
@@ -83,6 +83,7 @@ type MockApp struct {
 	decorateToAdmin func(*Message)
 	lastToAdmin     *Message
 	lastToApp       *Message
+	lastFromApp     *Message
 }
 
 func (e *MockApp) OnCreate(_ SessionID) {
@@ -119,7 +120,8 @@ func (e *MockApp) ToApp(msg *Message, _ SessionID) (err error) {
 	return e.Called().Error(0)
 }
 
-func (e *MockApp) FromApp(_ *Message, _ SessionID) (reject MessageRejectError) {
+func (e *MockApp) FromApp(msg *Message, _ SessionID) (reject MessageRejectError) {
+	e.lastFromApp = msg
 	if err, ok := e.Called().Get(0).(MessageRejectError); ok {
 		return err
 	}
@@ -0,0 +1,79 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// RawMessageDecoder decodes the messages a session receives in FAST or another encoding into tag=value FIX messages,
+// which are then parsed as usual. A session decodes its inbound messages with the decoder named by its
+// RawMessageDecoder setting, registered with WithRawMessageDecoder. As the session of an Acceptor connection is only
+// known once the Logon opening it is parsed, that Logon is read as tag=value and the messages after it are decoded.
+type RawMessageDecoder interface {
+	// DecodeMessage reads the next encoded message from r and returns it as a tag=value FIX message. An error closes
+	// the connection, as the messages after it can no longer be delimited.
+	DecodeMessage(r *bufio.Reader) ([]byte, error)
+}
+
+// WithRawMessageDecoder registers decoder under name, for the sessions of the engine setting RawMessageDecoder to
+// name.
+func WithRawMessageDecoder(name string, decoder RawMessageDecoder) EngineOption {
+	return func(o *engineOptions) {
+		if o.rawDecoders == nil {
+			o.rawDecoders = make(map[string]RawMessageDecoder)
+		}
+		o.rawDecoders[name] = decoder
+	}
+}
+
+// ZlibFrameDecoder is a RawMessageDecoder of messages sent as frames of a 4 byte big-endian length followed by the
+// zlib compressed tag=value message. Frames and messages larger than 16 MiB are rejected.
+type ZlibFrameDecoder struct{}
+
+// DecodeMessage reads the next frame from r and returns the message it decompresses to.
+func (ZlibFrameDecoder) DecodeMessage(r *bufio.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxDecompressedDataSize {
+		return nil, fmt.Errorf("zlib frame of %v bytes exceeds %v bytes", size, maxDecompressedDataSize)
+	}
+
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(frame))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zlib frame: %w", err)
+	}
+	msg, err := io.ReadAll(io.LimitReader(zr, maxDecompressedDataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zlib frame: %w", err)
+	}
+	if len(msg) > maxDecompressedDataSize {
+		return nil, fmt.Errorf("zlib frame decompresses to more than %v bytes", maxDecompressedDataSize)
+	}
+	return msg, nil
+}
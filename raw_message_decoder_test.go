@@ -0,0 +1,88 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zlibFrame returns msg framed for ZlibFrameDecoder.
+func zlibFrame(t *testing.T, msg string) string {
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	_, err := w.Write([]byte(msg))
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+
+	frame := binary.BigEndian.AppendUint32(nil, uint32(compressed.Len()))
+	return string(append(frame, compressed.Bytes()...))
+}
+
+func TestParser_DecodeWith(t *testing.T) {
+	logon := "8=FIX.4.4\x019=5\x0135=A\x0110=103\x01"
+	order := "8=FIX.4.4\x019=5\x0135=D\x0110=103\x01"
+	heartbeat := "8=FIX.4.4\x019=5\x0135=0\x0110=103\x01"
+	p := newParser(strings.NewReader(logon + zlibFrame(t, order) + zlibFrame(t, heartbeat)))
+
+	msg, err := p.ReadMessage()
+	require.Nil(t, err)
+	assert.Equal(t, logon, msg.String())
+
+	p.decodeWith(ZlibFrameDecoder{})
+	for _, expected := range []string{order, heartbeat} {
+		_, ok := p.readBuffered()
+		assert.False(t, ok, "decoded messages are not batched")
+
+		msg, err = p.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, expected, msg.String())
+	}
+	_, err = p.ReadMessage()
+	assert.NotNil(t, err)
+}
+
+func TestParser_DecodeWithNil(t *testing.T) {
+	logon := "8=FIX.4.4\x019=5\x0135=A\x0110=103\x01"
+	p := newDecodingParser(strings.NewReader(logon), nil)
+
+	msg, err := p.ReadMessage()
+	require.Nil(t, err)
+	assert.Equal(t, logon, msg.String())
+}
+
+func TestZlibFrameDecoder_Invalid(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+	}{
+		{"truncated length", "\x00\x00"},
+		{"truncated frame", "\x00\x00\x00\x10abc"},
+		{"oversized frame", "\x7f\xff\xff\xff"},
+		{"not zlib", "\x00\x00\x00\x03abc"},
+	}
+	for _, test := range tests {
+		_, err := ZlibFrameDecoder{}.DecodeMessage(bufio.NewReader(strings.NewReader(test.input)))
+		assert.NotNil(t, err, test.name)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// SendRawOptions configures Session.SendRaw.
+type SendRawOptions struct {
+	// KeepSendingTime keeps the SendingTime of the message, e.g. of a replayed capture, instead of setting it to
+	// the current time. The message must then have a SendingTime.
+	KeepSendingTime bool
+}
+
+// SendRaw sends a message already serialized as FIX bytes, e.g. replayed from a vendor capture or built directly by
+// a low latency path. The MsgSeqNum and SendingTime of b are set by patching its standard header, inserting them
+// after MsgType if missing, and BodyLength and CheckSum are recomputed. The other fields are sent as they are, so
+// b must carry the CompIDs of the Session.
+//
+// b is validated minimally: it must be an application message of the BeginString of the Session, with BeginString,
+// BodyLength and MsgType first, CheckSum last and a BodyLength matching its length. It is validated against the
+// OutboundDataDictionary and checked by the RiskCheckers of the Session when they are configured, which parses it.
+// ToApp is not called, and message types requiring approval are rejected as b cannot be held. The message is
+// persisted and queued like one sent with SendToTarget; b is not retained.
+func (s *Session) SendRaw(b []byte, opts SendRawOptions) error {
+	if err := s.checkCanSend(); err != nil {
+		return err
+	}
+	if s.DropCopy {
+		return ErrDropCopy
+	}
+
+	raw, err := scanRawMessage(b)
+	if err != nil {
+		return ErrValidation{Details: "raw message", Err: err}
+	}
+	if string(raw.beginString) != s.sessionID.BeginString {
+		return ErrValidation{Details: fmt.Sprintf("raw message BeginString %s, expected %s", raw.beginString, s.sessionID.BeginString)}
+	}
+	if isAdminMessageType(raw.msgType) {
+		return ErrValidation{Details: fmt.Sprintf("raw message of admin MsgType %s", raw.msgType)}
+	}
+	if s.ApprovalMsgTypes["*"] || s.ApprovalMsgTypes[string(raw.msgType)] {
+		return ErrValidation{Details: fmt.Sprintf("raw message of MsgType %s requiring approval", raw.msgType)}
+	}
+	if opts.KeepSendingTime && raw.sendingTime.start == 0 {
+		return ErrValidation{Details: "raw message without SendingTime"}
+	}
+
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	if s.queueFull(raw.msgType) {
+		return ErrQueueFull
+	}
+
+	seqNum := s.store.NextSenderMsgSeqNum()
+	msgBytes := s.patchRawMessage(raw, seqNum, opts)
+
+	// The risk checks and the ack tracker work on a Message.
+	var msg *Message
+	if s.risk.active() || s.AckTimeout > 0 {
+		msg = NewMessage()
+		if err = s.ParseMessage(msg, bytes.NewBuffer(msgBytes)); err != nil {
+			return ErrValidation{Details: "raw message", Err: err}
+		}
+		if err = s.risk.check(msg, s.sessionID); err != nil {
+			return err
+		}
+	}
+
+	if s.outboundValidator != nil {
+		if err = s.validateOutbound(msgBytes); err != nil {
+			return err
+		}
+	}
+
+	if err = s.persist(seqNum, msgBytes); err != nil {
+		return err
+	}
+	s.checkSeqNumPublish(s.now(), false)
+
+	s.metrics().MessageOut(s.sessionID, string(raw.msgType))
+	s.stats.add(s.now(), statMessagesOut)
+	if msg != nil {
+		s.risk.record(msg, raw.msgType)
+		if s.AckTimeout > 0 {
+			s.acks.track(msg, raw.msgType, seqNum, s.now().Add(s.AckTimeout))
+		}
+	}
+	s.mirrors.publish(s, msgBytes, true)
+
+	s.toSend = append(s.toSend, msgBytes)
+	s.notifyMessageOut()
+	return nil
+}
+
+// rawField locates the value of a field of a rawMessage, start is zero if the field is missing.
+type rawField struct {
+	start, end int
+}
+
+// rawMessage locates the fields of a serialized message patched by SendRaw.
+type rawMessage struct {
+	bytes                []byte
+	beginString, msgType []byte
+
+	// bodyStart follows the BodyLength field, bodyEnd is the start of the CheckSum field.
+	bodyStart, bodyEnd int
+	msgTypeEnd         int
+
+	seqNum, sendingTime rawField
+}
+
+// scanRawMessage checks the framing of b and locates its MsgSeqNum and SendingTime, scanning the standard header
+// only so that the data fields of the body are not parsed.
+func scanRawMessage(b []byte) (raw rawMessage, err error) {
+	raw.bytes = b
+
+	// CheckSum is the last field, with a three digit value.
+	const checkSumLen = len("10=000\001")
+	if len(b) < checkSumLen+1 || b[len(b)-1] != '\001' || b[len(b)-checkSumLen-1] != '\001' ||
+		!bytes.HasPrefix(b[len(b)-checkSumLen:], []byte("10=")) {
+		return raw, errors.New("CheckSum must be the last field")
+	}
+	raw.bodyEnd = len(b) - checkSumLen
+
+	offset := 0
+	var bodyLength int
+	for i := 0; offset < raw.bodyEnd; i++ {
+		eq := bytes.IndexByte(b[offset:raw.bodyEnd], '=')
+		if eq <= 0 {
+			return raw, fmt.Errorf("malformed field at offset %d", offset)
+		}
+		tagValue, err := parseUInt(b[offset : offset+eq])
+		if err != nil {
+			return raw, fmt.Errorf("malformed tag at offset %d", offset)
+		}
+		tag := Tag(tagValue)
+
+		valueStart := offset + eq + 1
+		soh := bytes.IndexByte(b[valueStart:raw.bodyEnd], '\001')
+		if soh < 0 {
+			return raw, fmt.Errorf("unterminated field %d", tag)
+		}
+		valueEnd := valueStart + soh
+		value := b[valueStart:valueEnd]
+		offset = valueEnd + 1
+
+		switch {
+		case i == 0:
+			if tag != tagBeginString {
+				return raw, errors.New("BeginString must be the first field")
+			}
+			raw.beginString = value
+		case i == 1:
+			if tag != tagBodyLength {
+				return raw, errors.New("BodyLength must be the second field")
+			}
+			if bodyLength, err = parseUInt(value); err != nil {
+				return raw, fmt.Errorf("malformed BodyLength: %w", err)
+			}
+			raw.bodyStart = offset
+		case i == 2:
+			if tag != tagMsgType {
+				return raw, errors.New("MsgType must be the third field")
+			}
+			raw.msgType = value
+			raw.msgTypeEnd = offset
+		case !tag.IsHeader():
+			offset = raw.bodyEnd
+		case tag == tagMsgSeqNum:
+			raw.seqNum = rawField{valueStart, valueEnd}
+		case tag == tagSendingTime:
+			raw.sendingTime = rawField{valueStart, valueEnd}
+		}
+	}
+
+	if raw.msgTypeEnd == 0 {
+		return raw, errors.New("MsgType must be the third field")
+	}
+	if bodyLength != raw.bodyEnd-raw.bodyStart {
+		return raw, fmt.Errorf("BodyLength %d, expected %d", bodyLength, raw.bodyEnd-raw.bodyStart)
+	}
+	return raw, nil
+}
+
+// patchRawMessage returns the bytes of raw with the MsgSeqNum and SendingTime of the message sent by the Session.
+func (s *Session) patchRawMessage(raw rawMessage, seqNum int, opts SendRawOptions) []byte {
+	var seqNumBuf [20]byte
+	seqNumValue := appendInt(seqNumBuf[:0], seqNum)
+
+	var sendingTimeValue []byte
+	if !opts.KeepSendingTime {
+		precision := Seconds
+		if s.sessionID.BeginString >= BeginStringFIX42 {
+			precision = s.timestampPrecision
+		}
+		var buf [len(utcTimestampNanosFormat)]byte
+		sendingTimeValue = s.sendingTimes.append(buf[:0], s.now(), precision)
+	}
+
+	// The patches, in the order of their offset.
+	type patch struct {
+		field  rawField
+		insert bool
+		tag    Tag
+		value  []byte
+	}
+	patches := make([]patch, 0, 2)
+	bodyLength := raw.bodyEnd - raw.bodyStart
+	add := func(field rawField, tag Tag, value []byte) {
+		insert := field.start == 0
+		if insert {
+			// Missing fields are inserted after MsgType.
+			var tagBuf [maxTagWidth]byte
+			field = rawField{raw.msgTypeEnd, raw.msgTypeEnd}
+			bodyLength += len(appendInt(tagBuf[:0], int(tag))) + len("=\001")
+		}
+		bodyLength += len(value) - (field.end - field.start)
+		patches = append(patches, patch{field: field, insert: insert, tag: tag, value: value})
+	}
+	add(raw.seqNum, tagMsgSeqNum, seqNumValue)
+	if sendingTimeValue != nil {
+		add(raw.sendingTime, tagSendingTime, sendingTimeValue)
+	}
+	if patches[len(patches)-1].field.start < patches[0].field.start {
+		patches[0], patches[1] = patches[1], patches[0]
+	}
+
+	msgBytes := make([]byte, 0, bodyLength+len(raw.beginString)+32)
+	msgBytes = append(msgBytes, "8="...)
+	msgBytes = append(msgBytes, raw.beginString...)
+	msgBytes = append(msgBytes, "\0019="...)
+	msgBytes = appendInt(msgBytes, bodyLength)
+	msgBytes = append(msgBytes, '\001')
+
+	offset := raw.bodyStart
+	for _, p := range patches {
+		msgBytes = append(msgBytes, raw.bytes[offset:p.field.start]...)
+		if p.insert {
+			msgBytes = appendInt(msgBytes, int(p.tag))
+			msgBytes = append(msgBytes, '=')
+			msgBytes = append(msgBytes, p.value...)
+			msgBytes = append(msgBytes, '\001')
+		} else {
+			msgBytes = append(msgBytes, p.value...)
+		}
+		offset = p.field.end
+	}
+	msgBytes = append(msgBytes, raw.bytes[offset:raw.bodyEnd]...)
+
+	var checkSum int
+	for _, c := range msgBytes {
+		checkSum += int(c)
+	}
+	msgBytes = append(msgBytes, "10="...)
+	msgBytes = appendCheckSum(msgBytes, checkSum%256)
+	return append(msgBytes, '\001')
+}
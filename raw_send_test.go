@@ -0,0 +1,133 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RawSendTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestRawSendTestSuite(t *testing.T) {
+	suite.Run(t, new(RawSendTestSuite))
+}
+
+func (s *RawSendTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.clock = fixedClock{time.Date(2024, 3, 1, 9, 30, 15, 0, time.UTC)}
+	s.Require().Nil(s.MockStore.Reset())
+}
+
+// raw frames body, the fields following BodyLength delimited by '|', with a BodyLength and CheckSum.
+func raw(beginString, body string) []byte {
+	body = strings.ReplaceAll(body, "|", "\001")
+	msg := []byte("8=" + beginString + "\0019=" + strconv.Itoa(len(body)) + "\001" + body)
+	var checkSum int
+	for _, c := range msg {
+		checkSum += int(c)
+	}
+	return append(appendCheckSum(append(msg, "10="...), checkSum%256), '\001')
+}
+
+func (s *RawSendTestSuite) sent() string {
+	s.Require().Len(s.Session.toSend, 1)
+	return string(s.Session.toSend[0])
+}
+
+func (s *RawSendTestSuite) isValidationError(err error) {
+	var validation ErrValidation
+	s.True(errors.As(err, &validation), "expected ErrValidation, got %v", err)
+	s.NoMessageQueued()
+	s.NextSenderMsgSeqNum(1)
+}
+
+func (s *RawSendTestSuite) TestPatchesHeader() {
+	s.IncrNextSenderMsgSeqNum()
+
+	b := raw("FIX.4.2", "35=D|34=999|49=ISLD|52=20200101-00:00:00|56=TW|11=ID|95=3|96=a|b|")
+	original := string(b)
+	s.Require().Nil(s.Session.SendRaw(b, SendRawOptions{}))
+
+	expected := string(raw("FIX.4.2", "35=D|34=2|49=ISLD|52=20240301-09:30:15.000|56=TW|11=ID|95=3|96=a|b|"))
+	s.Equal(expected, s.sent())
+	s.Equal(original, string(b), "b is not modified")
+	s.NextSenderMsgSeqNum(3)
+
+	persisted, err := s.MockStore.GetMessages(2, 2)
+	s.Nil(err)
+	s.Require().Len(persisted, 1)
+	s.Equal(expected, string(persisted[0]))
+}
+
+func (s *RawSendTestSuite) TestInsertsMissingFields() {
+	s.Require().Nil(s.Session.SendRaw(raw("FIX.4.2", "35=D|49=ISLD|56=TW|11=ID|"), SendRawOptions{}))
+	s.Equal(string(raw("FIX.4.2", "35=D|34=1|52=20240301-09:30:15.000|49=ISLD|56=TW|11=ID|")), s.sent())
+}
+
+func (s *RawSendTestSuite) TestKeepSendingTime() {
+	b := raw("FIX.4.2", "35=D|49=ISLD|52=20200101-00:00:00|56=TW|11=ID|")
+	s.Require().Nil(s.Session.SendRaw(b, SendRawOptions{KeepSendingTime: true}))
+	s.Equal(string(raw("FIX.4.2", "35=D|34=1|49=ISLD|52=20200101-00:00:00|56=TW|11=ID|")), s.sent())
+}
+
+func (s *RawSendTestSuite) TestKeepSendingTimeMissing() {
+	s.isValidationError(s.Session.SendRaw(raw("FIX.4.2", "35=D|49=ISLD|56=TW|11=ID|"), SendRawOptions{KeepSendingTime: true}))
+}
+
+func (s *RawSendTestSuite) TestRejectsMalformed() {
+	valid := "35=D|49=ISLD|56=TW|11=ID|"
+	for name, b := range map[string][]byte{
+		"BeginString":   raw("FIX.4.4", valid),
+		"admin":         raw("FIX.4.2", "35=0|49=ISLD|56=TW|"),
+		"no CheckSum":   bytes.TrimSuffix(raw("FIX.4.2", valid), []byte("\001")),
+		"BodyLength":    bytes.Replace(raw("FIX.4.2", valid), []byte("9=25"), []byte("9=24"), 1),
+		"field order":   raw("FIX.4.2", "49=ISLD|35=D|56=TW|"),
+		"malformed tag": raw("FIX.4.2", "35=D|4x=ISLD|56=TW|"),
+		"unterminated":  []byte("8=FIX.4.2\0019=5\00135=D10=000\001"),
+		"empty":         nil,
+	} {
+		s.Run(name, func() {
+			s.isValidationError(s.Session.SendRaw(b, SendRawOptions{}))
+		})
+	}
+}
+
+func (s *RawSendTestSuite) TestApprovalRequired() {
+	s.Session.ApprovalMsgTypes = map[string]bool{"D": true}
+	s.isValidationError(s.Session.SendRaw(raw("FIX.4.2", "35=D|49=ISLD|56=TW|11=ID|"), SendRawOptions{}))
+}
+
+func (s *RawSendTestSuite) TestRiskChecked() {
+	s.Session.risk.checkers = []RiskChecker{&maxOrdersChecker{max: 1}}
+
+	s.Require().Nil(s.Session.SendRaw(raw("FIX.4.2", "35=D|49=ISLD|56=TW|11=ID|"), SendRawOptions{}))
+	s.Equal(1, s.Session.risk.stats.Orders)
+
+	var rejected ErrRiskRejected
+	s.True(errors.As(s.Session.SendRaw(raw("FIX.4.2", "35=D|49=ISLD|56=TW|11=ID2|"), SendRawOptions{}), &rejected))
+	s.Len(s.Session.toSend, 1)
+	s.NextSenderMsgSeqNum(2)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// reconnectAttempts counts the reconnections of an initiator since it last logged on, see ReconnectBackoffBase.
+type reconnectAttempts struct {
+	sync.Mutex
+	count int
+}
+
+func (r *reconnectAttempts) add() int {
+	r.Lock()
+	defer r.Unlock()
+	r.count++
+	return r.count
+}
+
+func (r *reconnectAttempts) reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.count = 0
+}
+
+// reconnectDelay returns how long an initiator waits before reconnecting, counting the reconnection. The delay is
+// ReconnectInterval, or ReconnectBackoffBase doubled with each reconnection since the last logon up to
+// ReconnectBackoffMax if set, randomized by up to ReconnectJitter percent either way.
+func (s *Session) reconnectDelay() time.Duration {
+	attempts := s.reconnectAttempts.add()
+
+	delay := s.ReconnectInterval
+	if s.ReconnectBackoffBase > 0 {
+		delay = s.ReconnectBackoffBase
+		for i := 1; i < attempts && delay < s.ReconnectBackoffMax; i++ {
+			delay *= 2
+		}
+		if delay > s.ReconnectBackoffMax {
+			delay = s.ReconnectBackoffMax
+		}
+	}
+
+	if s.ReconnectJitter > 0 {
+		spread := float64(delay) * float64(s.ReconnectJitter) / 100
+		delay += time.Duration((2*rand.Float64() - 1) * spread)
+	}
+	return delay
+}
@@ -0,0 +1,60 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix/internal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnectDelayInterval(t *testing.T) {
+	s := &Session{SessionSettings: internal.SessionSettings{ReconnectInterval: 30 * time.Second}}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, 30*time.Second, s.reconnectDelay())
+	}
+}
+
+func TestReconnectDelayBackoff(t *testing.T) {
+	s := &Session{SessionSettings: internal.SessionSettings{
+		ReconnectInterval:    30 * time.Second,
+		ReconnectBackoffBase: time.Second,
+		ReconnectBackoffMax:  5 * time.Second,
+	}}
+
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		assert.Equal(t, expected, s.reconnectDelay())
+	}
+
+	s.reconnectAttempts.reset()
+	assert.Equal(t, time.Second, s.reconnectDelay(), "logon resets the backoff")
+}
+
+func TestReconnectDelayJitter(t *testing.T) {
+	s := &Session{SessionSettings: internal.SessionSettings{ReconnectInterval: 10 * time.Second, ReconnectJitter: 20}}
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		delay := s.reconnectDelay()
+		assert.GreaterOrEqual(t, delay, 8*time.Second)
+		assert.LessOrEqual(t, delay, 12*time.Second)
+		seen[delay] = true
+	}
+	assert.Greater(t, len(seen), 1, "delays are randomized")
+}
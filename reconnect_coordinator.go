@@ -0,0 +1,123 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+)
+
+// ReconnectCoordinatorOptions configure a ReconnectCoordinator.
+type ReconnectCoordinatorOptions struct {
+	// Rate is the number of connection attempts per second allowed to each group of sessions.
+	Rate float64
+
+	// Burst is the number of connection attempts a group of sessions may make at once, 1 if zero.
+	Burst int
+
+	// Jitter delays each connection attempt by a random duration up to Jitter, so that the attempts allowed at
+	// once are spread out.
+	Jitter time.Duration
+}
+
+// ReconnectCoordinator limits the rate of the connection attempts of initiator sessions, so that sessions
+// disconnected together, e.g. by an outage of the venue, do not reconnect all at once and trip the connection rate
+// limits of the venue. Share a ReconnectCoordinator between Initiators with WithReconnectCoordinator.
+//
+// Sessions are grouped by their ReconnectGroup setting, or else by the host they connect to, and each group has a
+// token bucket holding up to Burst attempts and refilled at Rate. An attempt waits for a token of its group, then
+// for up to Jitter more.
+type ReconnectCoordinator struct {
+	opts ReconnectCoordinatorOptions
+
+	mu      sync.Mutex
+	buckets map[string]*reconnectBucket
+}
+
+// reconnectBucket holds the connection attempts available to a group, negative once attempts wait for tokens.
+type reconnectBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewReconnectCoordinator returns a ReconnectCoordinator, see WithReconnectCoordinator.
+func NewReconnectCoordinator(opts ReconnectCoordinatorOptions) (*ReconnectCoordinator, error) {
+	if opts.Rate <= 0 {
+		return nil, errors.New("Rate must be greater than zero")
+	}
+	if opts.Burst == 0 {
+		opts.Burst = 1
+	}
+	if opts.Burst < 0 {
+		return nil, errors.New("Burst must be greater than zero")
+	}
+	if opts.Jitter < 0 {
+		return nil, errors.New("Jitter must not be negative")
+	}
+
+	return &ReconnectCoordinator{opts: opts, buckets: make(map[string]*reconnectBucket)}, nil
+}
+
+// WithReconnectCoordinator limits the connection attempts of the sessions of an Initiator with coordinator.
+func WithReconnectCoordinator(coordinator *ReconnectCoordinator) EngineOption {
+	return func(o *engineOptions) { o.reconnectCoordinator = coordinator }
+}
+
+// reserve takes a token from the bucket of group at now, and returns how long the connection attempt waits for it,
+// jitter included.
+func (c *ReconnectCoordinator) reserve(group string, now time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	burst := float64(c.opts.Burst)
+	b, ok := c.buckets[group]
+	if !ok {
+		b = &reconnectBucket{tokens: burst, last: now}
+		c.buckets[group] = b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * c.opts.Rate
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+	b.tokens--
+
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / c.opts.Rate * float64(time.Second))
+	}
+	if c.opts.Jitter > 0 {
+		delay += rand.N(c.opts.Jitter)
+	}
+	return delay
+}
+
+// reconnectGroup returns the group of the Session connecting to address for the ReconnectCoordinator.
+func (s *Session) reconnectGroup(address string) string {
+	if s.ReconnectGroup != "" {
+		return s.ReconnectGroup
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
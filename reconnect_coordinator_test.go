@@ -0,0 +1,126 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReconnectCoordinator(t *testing.T) {
+	_, err := NewReconnectCoordinator(ReconnectCoordinatorOptions{})
+	assert.Error(t, err)
+	_, err = NewReconnectCoordinator(ReconnectCoordinatorOptions{Rate: 1, Burst: -1})
+	assert.Error(t, err)
+	_, err = NewReconnectCoordinator(ReconnectCoordinatorOptions{Rate: 1, Jitter: -time.Second})
+	assert.Error(t, err)
+
+	c, err := NewReconnectCoordinator(ReconnectCoordinatorOptions{Rate: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.opts.Burst)
+}
+
+func TestReconnectCoordinatorReserve(t *testing.T) {
+	c, err := NewReconnectCoordinator(ReconnectCoordinatorOptions{Rate: 10, Burst: 2})
+	require.NoError(t, err)
+
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), c.reserve("venue", now))
+	assert.Equal(t, time.Duration(0), c.reserve("venue", now))
+	assert.Equal(t, 100*time.Millisecond, c.reserve("venue", now))
+	assert.Equal(t, 200*time.Millisecond, c.reserve("venue", now))
+
+	// Groups have their own bucket.
+	assert.Equal(t, time.Duration(0), c.reserve("other", now))
+
+	// The waiting attempts consumed the tokens refilled meanwhile.
+	assert.Equal(t, 200*time.Millisecond, c.reserve("venue", now.Add(100*time.Millisecond)))
+
+	// The bucket refills up to Burst.
+	later := now.Add(time.Minute)
+	assert.Equal(t, time.Duration(0), c.reserve("venue", later))
+	assert.Equal(t, time.Duration(0), c.reserve("venue", later))
+	assert.Equal(t, 100*time.Millisecond, c.reserve("venue", later))
+}
+
+func TestReconnectCoordinatorJitter(t *testing.T) {
+	c, err := NewReconnectCoordinator(ReconnectCoordinatorOptions{Rate: 1000, Burst: 1000, Jitter: 50 * time.Millisecond})
+	require.NoError(t, err)
+
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		delay := c.reserve("venue", now)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, 50*time.Millisecond)
+	}
+}
+
+func TestSessionReconnectGroup(t *testing.T) {
+	s := &Session{}
+	assert.Equal(t, "fix.venue.com", s.reconnectGroup("fix.venue.com:5001"))
+	assert.Equal(t, "::1", s.reconnectGroup("[::1]:5001"))
+	assert.Equal(t, "venue", s.reconnectGroup("venue"))
+
+	s = &Session{SessionSettings: internal.SessionSettings{ReconnectGroup: "venue"}}
+	assert.Equal(t, "venue", s.reconnectGroup("fix.venue.com:5001"))
+}
+
+func TestInitiatorReconnectCoordinator(t *testing.T) {
+	var dials atomic.Int32
+	dialer := dialerFunc(func(_ context.Context, _, _ string) (net.Conn, error) {
+		dials.Add(1)
+		return nil, errors.New("connection refused")
+	})
+
+	coordinator, err := NewReconnectCoordinator(ReconnectCoordinatorOptions{Rate: 1})
+	require.NoError(t, err)
+
+	settings := NewSettings()
+	for _, target := range []string{"target1", "target2", "target3"} {
+		sessionSettings := NewSessionSettings()
+		sessionSettings.Set(config.BeginString, BeginStringFIX42)
+		sessionSettings.Set(config.SenderCompID, "sender")
+		sessionSettings.Set(config.TargetCompID, target)
+		sessionSettings.Set(config.HeartBtInt, "30")
+		sessionSettings.Set(config.ReconnectInterval, "10ms")
+		sessionSettings.Set(config.SocketConnectHost, "venue")
+		sessionSettings.Set(config.SocketConnectPort, "5001")
+		_, err = settings.AddSession(sessionSettings)
+		require.NoError(t, err)
+	}
+
+	initiator, err := NewInitiator(&MockApp{}, nil, settings, nil, WithDialer(dialer), WithReconnectCoordinator(coordinator))
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+
+	defer initiator.Stop()
+
+	require.Eventually(t, func() bool { return dials.Load() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// The sessions to the venue share a single connection attempt per second.
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), dials.Load())
+}
@@ -16,14 +16,12 @@
 package quickfix
 
 import (
+	"context"
 	"errors"
 	"sync"
 )
 
-var sessionsLock sync.RWMutex
-var sessions = make(map[SessionID]*Session)
 var errDuplicateSessionID = errors.New("Duplicate SessionID")
-var errUnknownSession = errors.New("Unknown Session")
 
 // Messagable is a Message or something that can be converted to a Message.
 type Messagable interface {
@@ -32,43 +30,103 @@ type Messagable interface {
 
 // Send determines the Session to send Messagable using header fields BeginString, TargetCompID, SenderCompID.
 func Send(m Messagable) (err error) {
+	return defaultEngine.Send(m)
+}
+
+// Send determines the Session to send Messagable using header fields BeginString, TargetCompID, SenderCompID.
+func (e *Engine) Send(m Messagable) (err error) {
+	msg := m.ToMessage()
+	sessionID, err := headerSessionID(msg)
+	if err != nil {
+		return err
+	}
+
+	return e.SendToTarget(msg, sessionID)
+}
+
+// SendWithContext is Send waiting for the send queue of the Session to have room, see MaxQueuedMessages, rather
+// than returning ErrQueueFull. Returns the error of ctx if it is done first.
+func SendWithContext(ctx context.Context, m Messagable) error {
+	return defaultEngine.SendWithContext(ctx, m)
+}
+
+// SendWithContext is Send waiting for the send queue of the Session to have room, see MaxQueuedMessages, rather
+// than returning ErrQueueFull. Returns the error of ctx if it is done first.
+func (e *Engine) SendWithContext(ctx context.Context, m Messagable) error {
 	msg := m.ToMessage()
+	sessionID, err := headerSessionID(msg)
+	if err != nil {
+		return err
+	}
+
+	session, ok := e.registry.lookup(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if err := session.checkCanSend(); err != nil {
+		return err
+	}
+
+	session.recordSend(msg)
+	if session.requiresApproval(msg) {
+		return session.hold(msg)
+	}
+	return session.queueForSendContext(ctx, msg)
+}
+
+// headerSessionID returns the SessionID of the header fields BeginString, TargetCompID, SenderCompID of msg.
+func headerSessionID(msg *Message) (SessionID, error) {
 	var beginString FIXString
 	if err := msg.Header.GetField(tagBeginString, &beginString); err != nil {
-		return err
+		return SessionID{}, err
 	}
 
 	var targetCompID FIXString
 	if err := msg.Header.GetField(tagTargetCompID, &targetCompID); err != nil {
-		return err
+		return SessionID{}, err
 	}
 
 	var senderCompID FIXString
 	if err := msg.Header.GetField(tagSenderCompID, &senderCompID); err != nil {
-		return err
+		return SessionID{}, err
 	}
 
-	sessionID := SessionID{BeginString: string(beginString), TargetCompID: string(targetCompID), SenderCompID: string(senderCompID)}
-
-	return SendToTarget(msg, sessionID)
+	return SessionID{BeginString: string(beginString), TargetCompID: string(targetCompID), SenderCompID: string(senderCompID)}, nil
 }
 
 // SendToTarget sends a message based on the sessionID. Convenient for use in FromApp since it provides a Session ID for incoming messages.
 func SendToTarget(m Messagable, sessionID SessionID) error {
+	return defaultEngine.SendToTarget(m, sessionID)
+}
+
+// SendToTarget sends a message based on the sessionID. Convenient for use in FromApp since it provides a Session ID for incoming messages.
+func (e *Engine) SendToTarget(m Messagable, sessionID SessionID) error {
 	msg := m.ToMessage()
-	session, ok := lookupSession(sessionID)
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return errUnknownSession
+		return ErrSessionNotFound
+	}
+	if err := session.checkCanSend(); err != nil {
+		return err
 	}
 
+	session.recordSend(msg)
+	if session.requiresApproval(msg) {
+		return session.hold(msg)
+	}
 	return session.queueForSend(msg)
 }
 
 // ResetSession resets Session's sequence numbers.
 func ResetSession(sessionID SessionID) error {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.ResetSession(sessionID)
+}
+
+// ResetSession resets Session's sequence numbers.
+func (e *Engine) ResetSession(sessionID SessionID) error {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return errUnknownSession
+		return ErrSessionNotFound
 	}
 	session.log.OnEvent("Session reset")
 	session.State.ShutdownNow(session)
@@ -82,96 +140,196 @@ func ResetSession(sessionID SessionID) error {
 
 // GetSession retrieves a Session by its SessionID.
 func GetSession(sessionID SessionID) (*Session, error) {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.GetSession(sessionID)
+}
+
+// GetSession retrieves a Session by its SessionID.
+func (e *Engine) GetSession(sessionID SessionID) (*Session, error) {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return nil, errUnknownSession
+		return nil, ErrSessionNotFound
 	}
 	return session, nil
 }
 
 // UnregisterSession removes a Session from the set of known sessions.
 func UnregisterSession(sessionID SessionID) error {
-	sessionsLock.Lock()
-	defer sessionsLock.Unlock()
-
-	if _, ok := sessions[sessionID]; ok {
-		delete(sessions, sessionID)
-		return nil
-	}
+	return defaultEngine.UnregisterSession(sessionID)
+}
 
-	return errUnknownSession
+// UnregisterSession removes a Session from the set of known sessions.
+func (e *Engine) UnregisterSession(sessionID SessionID) error {
+	return e.registry.unregister(sessionID)
 }
 
 // SetNextTargetMsgSeqNum set the next expected target message sequence number for the Session matching the Session id.
 func SetNextTargetMsgSeqNum(sessionID SessionID, seqNum int) error {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.SetNextTargetMsgSeqNum(sessionID, seqNum)
+}
+
+// SetNextTargetMsgSeqNum set the next expected target message sequence number for the Session matching the Session id.
+func (e *Engine) SetNextTargetMsgSeqNum(sessionID SessionID, seqNum int) error {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return errUnknownSession
+		return ErrSessionNotFound
 	}
-	return session.store.SetNextTargetMsgSeqNum(seqNum)
+	return session.SetNextTargetMsgSeqNum(seqNum)
 }
 
 // SetNextSenderMsgSeqNum sets the next outgoing message sequence number for the Session matching the Session id.
 func SetNextSenderMsgSeqNum(sessionID SessionID, seqNum int) error {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.SetNextSenderMsgSeqNum(sessionID, seqNum)
+}
+
+// SetNextSenderMsgSeqNum sets the next outgoing message sequence number for the Session matching the Session id.
+func (e *Engine) SetNextSenderMsgSeqNum(sessionID SessionID, seqNum int) error {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return errUnknownSession
+		return ErrSessionNotFound
 	}
-	return session.store.SetNextSenderMsgSeqNum(seqNum)
+	return session.SetNextSenderMsgSeqNum(seqNum)
 }
 
 // GetExpectedSenderNum retrieves the expected sender sequence number for the Session matching the Session id.
 func GetExpectedSenderNum(sessionID SessionID) (int, error) {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.GetExpectedSenderNum(sessionID)
+}
+
+// GetExpectedSenderNum retrieves the expected sender sequence number for the Session matching the Session id.
+func (e *Engine) GetExpectedSenderNum(sessionID SessionID) (int, error) {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return 0, errUnknownSession
+		return 0, ErrSessionNotFound
 	}
 	return session.store.NextSenderMsgSeqNum(), nil
 }
 
 // GetExpectedTargetNum retrieves the next target sequence number for the Session matching the Session id.
 func GetExpectedTargetNum(sessionID SessionID) (int, error) {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.GetExpectedTargetNum(sessionID)
+}
+
+// GetExpectedTargetNum retrieves the next target sequence number for the Session matching the Session id.
+func (e *Engine) GetExpectedTargetNum(sessionID SessionID) (int, error) {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return 0, errUnknownSession
+		return 0, ErrSessionNotFound
 	}
 	return session.store.NextTargetMsgSeqNum(), nil
 }
 
 // GetMessageStore returns the MessageStore interface for Session matching the Session id.
 func GetMessageStore(sessionID SessionID) (MessageStore, error) {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.GetMessageStore(sessionID)
+}
+
+// GetMessageStore returns the MessageStore interface for Session matching the Session id.
+func (e *Engine) GetMessageStore(sessionID SessionID) (MessageStore, error) {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return nil, errUnknownSession
+		return nil, ErrSessionNotFound
 	}
 	return session.store, nil
 }
 
 // GetLog returns the Log interface for Session matching the Session id.
 func GetLog(sessionID SessionID) (Log, error) {
-	session, ok := lookupSession(sessionID)
+	return defaultEngine.GetLog(sessionID)
+}
+
+// GetLog returns the Log interface for Session matching the Session id.
+func (e *Engine) GetLog(sessionID SessionID) (Log, error) {
+	session, ok := e.registry.lookup(sessionID)
 	if !ok {
-		return nil, errUnknownSession
+		return nil, ErrSessionNotFound
 	}
 	return session.log, nil
 }
 
-func registerSession(s *Session) error {
-	sessionsLock.Lock()
-	defer sessionsLock.Unlock()
+// registry holds the sessions of an Engine, indexed by SessionID and by routing metadata.
+type registry struct {
+	lock       sync.RWMutex
+	sessions   map[SessionID]*Session
+	routes     map[SessionID]map[string]string
+	routeIndex map[routeLabel]map[SessionID]struct{}
+}
 
-	if _, ok := sessions[s.sessionID]; ok {
+func newRegistry() *registry {
+	return &registry{
+		sessions:   make(map[SessionID]*Session),
+		routes:     make(map[SessionID]map[string]string),
+		routeIndex: make(map[routeLabel]map[SessionID]struct{}),
+	}
+}
+
+// activeRegistries holds the registries of every Engine with registered sessions, so that the functions taking a
+// Session id without an Engine method find the sessions of any Engine. activeRegistries.lock is always acquired
+// before the lock of a registry.
+var activeRegistries = struct {
+	lock       sync.RWMutex
+	registries map[*registry]struct{}
+}{registries: make(map[*registry]struct{})}
+
+func (r *registry) register(s *Session) error {
+	activeRegistries.lock.Lock()
+	defer activeRegistries.lock.Unlock()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.sessions[s.sessionID]; ok {
 		return errDuplicateSessionID
 	}
 
-	sessions[s.sessionID] = s
+	r.sessions[s.sessionID] = s
+	r.indexRoute(s.sessionID, s.SessionLabels)
+	activeRegistries.registries[r] = struct{}{}
 	return nil
 }
 
+func (r *registry) unregister(sessionID SessionID) error {
+	activeRegistries.lock.Lock()
+	defer activeRegistries.lock.Unlock()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.sessions[sessionID]; ok {
+		delete(r.sessions, sessionID)
+		r.unindexRoute(sessionID)
+		if len(r.sessions) == 0 {
+			delete(activeRegistries.registries, r)
+		}
+		return nil
+	}
+
+	return ErrSessionNotFound
+}
+
+func (r *registry) lookup(sessionID SessionID) (s *Session, ok bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	s, ok = r.sessions[sessionID]
+	return
+}
+
+// registerSession registers the Session with the default Engine.
+func registerSession(s *Session) error {
+	return defaultEngine.registry.register(s)
+}
+
+// lookupSession returns the Session matching the Session id in the registry of its owning Engine. The default
+// Engine is searched first, so that it wins when engines register the same Session id.
 func lookupSession(sessionID SessionID) (s *Session, ok bool) {
-	sessionsLock.RLock()
-	defer sessionsLock.RUnlock()
+	activeRegistries.lock.RLock()
+	defer activeRegistries.lock.RUnlock()
 
-	s, ok = sessions[sessionID]
+	if s, ok = defaultEngine.registry.lookup(sessionID); ok {
+		return
+	}
+	for r := range activeRegistries.registries {
+		if s, ok = r.lookup(sessionID); ok {
+			return
+		}
+	}
 	return
 }
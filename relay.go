@@ -0,0 +1,350 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BusinessRejectReason of the messages a Relay can neither forward nor keep for later.
+const businessRejectReasonApplicationNotAvailable = 4
+
+// RelayRule rewrites the body of the messages forwarded by a Relay. Rules apply to top level fields, not to the
+// fields of repeating groups. Rename applies first, then Map, Set and Remove.
+type RelayRule struct {
+	// MsgTypes the rule applies to, all if empty.
+	MsgTypes []string
+
+	// Rename moves the value of each tag to the mapped tag.
+	Rename map[Tag]Tag
+
+	// Map replaces the values of each tag, from the key to the value of the inner map. Other values are kept.
+	Map map[Tag]map[string]string
+
+	// Set sets each tag to the value, adding it if missing.
+	Set map[Tag]string
+
+	// Remove removes the tags.
+	Remove []Tag
+}
+
+// RelayOptions configure a Relay.
+type RelayOptions struct {
+	// Acceptor and Initiator are the sessions connected by the Relay.
+	Acceptor  SessionID
+	Initiator SessionID
+
+	// ToInitiator rewrite the messages forwarded from the Acceptor to the Initiator session, ToAcceptor the other way.
+	ToInitiator []RelayRule
+	ToAcceptor  []RelayRule
+
+	// StoreFactory creates the stores keeping the messages forwarded while their session is logged out, with the
+	// Qualifier of the session suffixed by "relay". Defaults to a MemoryStoreFactory.
+	StoreFactory MessageStoreFactory
+}
+
+// relaySide is a session connected by a Relay.
+type relaySide struct {
+	sessionID SessionID
+
+	// rules rewrite the messages forwarded to the session.
+	rules []RelayRule
+
+	mu       sync.Mutex
+	loggedOn bool
+
+	// pending keeps the messages waiting for the session to log on: sent up to its NextTargetMsgSeqNum, saved up to its
+	// NextSenderMsgSeqNum.
+	pending MessageStore
+}
+
+// Relay is an Application forwarding the application messages received by one of two sessions to the other, the core
+// of a FIX hub. Forwarded messages are sent with a new header, so the sequence numbers of the sessions are
+// independent, and rewritten by the RelayRules of their direction. Messages forwarded while the other session is
+// logged out are kept in a store and sent once it logs on.
+//
+// Callbacks are passed on to the wrapped Application, and the messages it rejects in FromApp are not forwarded.
+type Relay struct {
+	app                 Application
+	acceptor, initiator *relaySide
+}
+
+// NewRelay returns a Relay wrapping app, to be passed to the Acceptor and Initiator of the sessions of opts.
+func NewRelay(app Application, opts RelayOptions) (*Relay, error) {
+	if opts.Acceptor == opts.Initiator {
+		return nil, errors.New("relay sessions must differ")
+	}
+
+	storeFactory := opts.StoreFactory
+	if storeFactory == nil {
+		storeFactory = NewMemoryStoreFactory()
+	}
+
+	r := &Relay{
+		app:       app,
+		acceptor:  &relaySide{sessionID: opts.Acceptor, rules: opts.ToAcceptor},
+		initiator: &relaySide{sessionID: opts.Initiator, rules: opts.ToInitiator},
+	}
+	for _, side := range []*relaySide{r.acceptor, r.initiator} {
+		pending, err := storeFactory.Create(relayStoreID(side.sessionID))
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("relay store for %v: %w", side.sessionID, err)
+		}
+		side.pending = pending
+	}
+	return r, nil
+}
+
+func relayStoreID(sessionID SessionID) SessionID {
+	if sessionID.Qualifier == "" {
+		sessionID.Qualifier = "relay"
+	} else {
+		sessionID.Qualifier += "-relay"
+	}
+	return sessionID
+}
+
+// Close closes the stores of the Relay.
+func (r *Relay) Close() {
+	for _, side := range []*relaySide{r.acceptor, r.initiator} {
+		side.mu.Lock()
+		if side.pending != nil {
+			_ = side.pending.Close()
+		}
+		side.mu.Unlock()
+	}
+}
+
+// Pending returns the number of messages waiting for the session sessionID of the Relay to log on.
+func (r *Relay) Pending(sessionID SessionID) (int, error) {
+	side := r.side(sessionID)
+	if side == nil {
+		return 0, ErrSessionNotFound
+	}
+
+	side.mu.Lock()
+	defer side.mu.Unlock()
+	return side.pending.NextSenderMsgSeqNum() - side.pending.NextTargetMsgSeqNum(), nil
+}
+
+func (r *Relay) side(sessionID SessionID) *relaySide {
+	switch sessionID {
+	case r.acceptor.sessionID:
+		return r.acceptor
+	case r.initiator.sessionID:
+		return r.initiator
+	}
+	return nil
+}
+
+func (r *Relay) other(side *relaySide) *relaySide {
+	if side == r.acceptor {
+		return r.initiator
+	}
+	return r.acceptor
+}
+
+// OnCreate implements Application.
+func (r *Relay) OnCreate(sessionID SessionID) {
+	r.app.OnCreate(sessionID)
+}
+
+// OnLogon implements Application, sending the messages kept while the session was logged out.
+func (r *Relay) OnLogon(sessionID SessionID) {
+	r.app.OnLogon(sessionID)
+
+	side := r.side(sessionID)
+	if side == nil {
+		return
+	}
+	side.mu.Lock()
+	defer side.mu.Unlock()
+	side.loggedOn = true
+	side.sendPending()
+}
+
+// OnLogout implements Application.
+func (r *Relay) OnLogout(sessionID SessionID) {
+	if side := r.side(sessionID); side != nil {
+		side.mu.Lock()
+		side.loggedOn = false
+		side.mu.Unlock()
+	}
+	r.app.OnLogout(sessionID)
+}
+
+// ToAdmin implements Application.
+func (r *Relay) ToAdmin(msg *Message, sessionID SessionID) {
+	r.app.ToAdmin(msg, sessionID)
+}
+
+// ToApp implements Application.
+func (r *Relay) ToApp(msg *Message, sessionID SessionID) error {
+	return r.app.ToApp(msg, sessionID)
+}
+
+// FromAdmin implements Application.
+func (r *Relay) FromAdmin(msg *Message, sessionID SessionID) MessageRejectError {
+	return r.app.FromAdmin(msg, sessionID)
+}
+
+// FromApp implements Application, forwarding msg to the other session once accepted by the wrapped Application.
+func (r *Relay) FromApp(msg *Message, sessionID SessionID) MessageRejectError {
+	if reject := r.app.FromApp(msg, sessionID); reject != nil {
+		return reject
+	}
+
+	source := r.side(sessionID)
+	if source == nil {
+		return nil
+	}
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return nil
+	}
+
+	fwd, err := relayMessage(session, msg)
+	if err != nil {
+		session.log.OnEventf("Relay failed to copy message: %v", err)
+		return NewBusinessMessageRejectError("Relay failed to copy message", businessRejectReasonApplicationNotAvailable, nil)
+	}
+	target := r.other(source)
+	for _, rule := range target.rules {
+		rule.apply(fwd)
+	}
+	if err := target.forward(fwd); err != nil {
+		session.log.OnEventf("Relay failed to forward message: %v", err)
+		return NewBusinessMessageRejectError("Relay target not available", businessRejectReasonApplicationNotAvailable, nil)
+	}
+	return nil
+}
+
+// relayMessage copies the body of msg, received by session, into a message with a new header.
+func relayMessage(session *Session, msg *Message) (*Message, error) {
+	msgType, err := msg.Header.GetString(tagMsgType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsed again from the raw message so repeating groups are copied as well.
+	parsed := NewMessage()
+	if err := session.ParseMessage(parsed, bytes.NewBuffer(msg.Bytes())); err != nil {
+		return nil, err
+	}
+	if reject := session.compression.decompress(parsed); reject != nil {
+		return nil, reject
+	}
+
+	fwd := NewMessage()
+	fwd.Header.SetString(tagMsgType, msgType)
+	fwd.Body = parsed.Body
+	return fwd, nil
+}
+
+// apply rewrites msg if the rule applies to its MsgType.
+func (rule RelayRule) apply(msg *Message) {
+	if len(rule.MsgTypes) > 0 {
+		msgType, _ := msg.Header.GetString(tagMsgType)
+		found := false
+		for _, t := range rule.MsgTypes {
+			if t == msgType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
+	}
+
+	for from, to := range rule.Rename {
+		if value, err := msg.Body.GetBytes(from); err == nil {
+			msg.Body.Remove(from)
+			msg.Body.SetBytes(to, value)
+		}
+	}
+	for tag, values := range rule.Map {
+		if value, err := msg.Body.GetString(tag); err == nil {
+			if mapped, ok := values[value]; ok {
+				msg.Body.SetString(tag, mapped)
+			}
+		}
+	}
+	for tag, value := range rule.Set {
+		msg.Body.SetString(tag, value)
+	}
+	for _, tag := range rule.Remove {
+		msg.Body.Remove(tag)
+	}
+}
+
+// forward sends msg to the session, or keeps it until the session logs on. Messages are kept as well when sending
+// fails, other than by ToApp returning ErrDoNotSend.
+func (side *relaySide) forward(msg *Message) error {
+	side.mu.Lock()
+	defer side.mu.Unlock()
+
+	if side.loggedOn && side.pending.NextSenderMsgSeqNum() == side.pending.NextTargetMsgSeqNum() {
+		err := SendToTarget(msg, side.sessionID)
+		if err == nil || errors.Is(err, ErrDoNotSend) {
+			return nil
+		}
+		side.loggedOn = false
+	}
+
+	msg.Header.SetString(tagBeginString, side.sessionID.BeginString)
+	return side.pending.SaveMessageAndIncrNextSenderMsgSeqNum(side.pending.NextSenderMsgSeqNum(), msg.Build())
+}
+
+// sendPending sends the kept messages in order, stopping at the first that cannot be sent.
+func (side *relaySide) sendPending() {
+	session, ok := lookupSession(side.sessionID)
+	if !ok {
+		return
+	}
+
+	for side.pending.NextTargetMsgSeqNum() < side.pending.NextSenderMsgSeqNum() {
+		seqNum := side.pending.NextTargetMsgSeqNum()
+		raw, err := side.pending.GetMessages(seqNum, seqNum)
+		if err != nil || len(raw) == 0 {
+			session.log.OnEventf("Relay failed to load kept message %d: %v", seqNum, err)
+			return
+		}
+
+		msg := NewMessage()
+		if err := session.ParseMessage(msg, bytes.NewBuffer(raw[0])); err != nil {
+			session.log.OnEventf("Relay failed to parse kept message %d: %v", seqNum, err)
+			return
+		}
+		if err := SendToTarget(msg, side.sessionID); err != nil && !errors.Is(err, ErrDoNotSend) {
+			session.log.OnEventf("Relay failed to send kept message %d: %v", seqNum, err)
+			side.loggedOn = false
+			return
+		}
+		if err := side.pending.IncrNextTargetMsgSeqNum(); err != nil {
+			session.logError(err)
+			return
+		}
+	}
+
+	if err := side.pending.Reset(); err != nil {
+		session.logError(err)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RelayTestSuite struct {
+	SessionSuiteRig
+	upstream *Session
+	relay    *Relay
+}
+
+func TestRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
+
+func (s *RelayTestSuite) SetupTest() {
+	s.Init()
+	s.upstream = &Session{
+		sessionID: SessionID{BeginString: "FIX.4.2", SenderCompID: "HUB", TargetCompID: "BROKER"},
+		store:     new(memoryStore),
+		log:       nullLog{},
+	}
+	s.Require().Nil(s.upstream.store.Reset())
+	s.Require().Nil(registerSession(s.Session))
+	s.Require().Nil(registerSession(s.upstream))
+
+	s.MockApp.On("OnLogon")
+	s.MockApp.On("ToApp").Return(nil)
+	s.newRelay(RelayOptions{})
+}
+
+func (s *RelayTestSuite) TearDownTest() {
+	s.relay.Close()
+	_ = UnregisterSession(s.sessionID)
+	_ = UnregisterSession(s.upstream.sessionID)
+}
+
+func (s *RelayTestSuite) newRelay(opts RelayOptions) {
+	opts.Acceptor = s.sessionID
+	opts.Initiator = s.upstream.sessionID
+
+	var err error
+	s.relay, err = NewRelay(&s.MockApp, opts)
+	s.Require().Nil(err)
+	s.Session.application = s.relay
+	s.upstream.application = s.relay
+}
+
+// order has the acceptor session receive a NewOrderSingle with ClOrdID clOrdID.
+func (s *RelayTestSuite) order(clOrdID string) MessageRejectError {
+	msg := s.NewOrderSingle()
+	msg.Body.SetString(tagClOrdID, clOrdID)
+	msg.Body.SetString(Tag(1), "ACC1")
+	s.MockApp.On("FromApp").Return(nil).Once()
+	return s.relay.FromApp(msg, s.sessionID)
+}
+
+// forwarded returns the messages queued for sending by session.
+func (s *RelayTestSuite) forwarded(session *Session) []*Message {
+	var msgs []*Message
+	for _, raw := range session.toSend {
+		msg := NewMessage()
+		s.Require().Nil(ParseMessage(msg, bytes.NewBuffer(raw)))
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func (s *RelayTestSuite) TestForward() {
+	s.relay.OnLogon(s.upstream.sessionID)
+	s.Nil(s.order("order1"))
+
+	msgs := s.forwarded(s.upstream)
+	s.Require().Len(msgs, 1)
+	s.FieldEquals(tagMsgType, "D", msgs[0].Header)
+	s.FieldEquals(tagSenderCompID, "HUB", msgs[0].Header)
+	s.FieldEquals(tagTargetCompID, "BROKER", msgs[0].Header)
+	s.FieldEquals(tagMsgSeqNum, 1, msgs[0].Header)
+	s.FieldEquals(tagClOrdID, "order1", msgs[0].Body)
+	s.Empty(s.Session.toSend)
+}
+
+func (s *RelayTestSuite) TestSeqNumIsolation() {
+	s.relay.OnLogon(s.upstream.sessionID)
+	s.MessageFactory.SetNextSeqNum(42)
+	s.Nil(s.order("order1"))
+
+	msgs := s.forwarded(s.upstream)
+	s.Require().Len(msgs, 1)
+	s.FieldEquals(tagMsgSeqNum, 1, msgs[0].Header)
+}
+
+func (s *RelayTestSuite) TestRules() {
+	s.relay.Close()
+	s.newRelay(RelayOptions{ToInitiator: []RelayRule{
+		{
+			Rename: map[Tag]Tag{tagClOrdID: Tag(526)},
+			Map:    map[Tag]map[string]string{Tag(1): {"ACC1": "BROKER-ACC"}},
+			Set:    map[Tag]string{Tag(57): "DESK"},
+		},
+		{MsgTypes: []string{"D"}, Remove: []Tag{Tag(57)}, Set: map[Tag]string{Tag(58): "relayed"}},
+		{MsgTypes: []string{"F"}, Set: map[Tag]string{Tag(58): "cancel"}},
+	}})
+	s.relay.OnLogon(s.upstream.sessionID)
+	s.Nil(s.order("order1"))
+
+	msgs := s.forwarded(s.upstream)
+	s.Require().Len(msgs, 1)
+	s.False(msgs[0].Body.Has(tagClOrdID))
+	s.FieldEquals(Tag(526), "order1", msgs[0].Body)
+	s.FieldEquals(Tag(1), "BROKER-ACC", msgs[0].Body)
+	s.False(msgs[0].Body.Has(Tag(57)))
+	s.FieldEquals(Tag(58), "relayed", msgs[0].Body)
+}
+
+func (s *RelayTestSuite) TestKeptWhileLoggedOut() {
+	s.Nil(s.order("order1"))
+	s.Nil(s.order("order2"))
+	s.Empty(s.upstream.toSend)
+
+	pending, err := s.relay.Pending(s.upstream.sessionID)
+	s.Require().Nil(err)
+	s.Equal(2, pending)
+
+	s.relay.OnLogon(s.upstream.sessionID)
+	msgs := s.forwarded(s.upstream)
+	s.Require().Len(msgs, 2)
+	s.FieldEquals(tagClOrdID, "order1", msgs[0].Body)
+	s.FieldEquals(tagMsgSeqNum, 1, msgs[0].Header)
+	s.FieldEquals(tagClOrdID, "order2", msgs[1].Body)
+	s.FieldEquals(tagMsgSeqNum, 2, msgs[1].Header)
+
+	pending, err = s.relay.Pending(s.upstream.sessionID)
+	s.Require().Nil(err)
+	s.Equal(0, pending)
+}
+
+func (s *RelayTestSuite) TestKeptAfterLogout() {
+	s.relay.OnLogon(s.upstream.sessionID)
+	s.MockApp.On("OnLogout")
+	s.relay.OnLogout(s.upstream.sessionID)
+
+	s.Nil(s.order("order1"))
+	s.Empty(s.upstream.toSend)
+
+	pending, err := s.relay.Pending(s.upstream.sessionID)
+	s.Require().Nil(err)
+	s.Equal(1, pending)
+}
+
+func (s *RelayTestSuite) TestToAcceptor() {
+	s.relay.Close()
+	s.newRelay(RelayOptions{ToAcceptor: []RelayRule{{Set: map[Tag]string{Tag(58): "from broker"}}}})
+	s.relay.OnLogon(s.sessionID)
+
+	msg := NewMessage()
+	msg.Header.SetString(tagBeginString, "FIX.4.2")
+	msg.Header.SetString(tagMsgType, "8")
+	msg.Body.SetString(tagClOrdID, "order1")
+	s.MockApp.On("FromApp").Return(nil).Once()
+	s.Nil(s.relay.FromApp(msg, s.upstream.sessionID))
+
+	msgs := s.forwarded(s.Session)
+	s.Require().Len(msgs, 1)
+	s.FieldEquals(tagSenderCompID, "ISLD", msgs[0].Header)
+	s.FieldEquals(tagTargetCompID, "TW", msgs[0].Header)
+	s.FieldEquals(tagMsgType, "8", msgs[0].Header)
+	s.FieldEquals(Tag(58), "from broker", msgs[0].Body)
+}
+
+func (s *RelayTestSuite) TestRejectedNotForwarded() {
+	s.relay.OnLogon(s.upstream.sessionID)
+
+	msg := s.NewOrderSingle()
+	s.MockApp.On("FromApp").Return(UnsupportedMessageType()).Once()
+	s.Equal(UnsupportedMessageType(), s.relay.FromApp(msg, s.sessionID))
+	s.Empty(s.upstream.toSend)
+}
+
+func (s *RelayTestSuite) TestDoNotSend() {
+	s.MockApp.ExpectedCalls = nil
+	s.MockApp.On("OnLogon")
+	s.MockApp.On("ToApp").Return(ErrDoNotSend)
+	s.relay.OnLogon(s.upstream.sessionID)
+
+	s.Nil(s.order("order1"))
+	pending, err := s.relay.Pending(s.upstream.sessionID)
+	s.Require().Nil(err)
+	s.Equal(0, pending)
+}
+
+func (s *RelayTestSuite) TestUnknownSession() {
+	_, err := s.relay.Pending(SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"})
+	s.Equal(ErrSessionNotFound, err)
+
+	_, err = NewRelay(&s.MockApp, RelayOptions{Acceptor: s.sessionID, Initiator: s.sessionID})
+	s.NotNil(err)
+}
@@ -18,6 +18,7 @@ package quickfix
 import (
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
 )
 
@@ -121,8 +122,30 @@ func (f *RepeatingGroup) Add() *Group {
 	return g
 }
 
+// InsertAt inserts a new group at index i of the RepeatingGroup, shifting the groups from i on, and returns the new
+// Group. i may be Len() to append. Panics if i is out of range.
+func (f *RepeatingGroup) InsertAt(i int) *Group {
+	g := new(Group)
+	g.initWithOrdering(f.groupTagOrder())
+
+	f.groups = slices.Insert(f.groups, i, g)
+	return g
+}
+
+// RemoveAt removes the ith group of the RepeatingGroup. Panics if i is out of range.
+func (f *RepeatingGroup) RemoveAt(i int) {
+	f.groups = slices.Delete(f.groups, i, i+1)
+}
+
+// Swap swaps the ith and jth groups of the RepeatingGroup. Panics if i or j is out of range.
+func (f *RepeatingGroup) Swap(i, j int) {
+	f.groups[i], f.groups[j] = f.groups[j], f.groups[i]
+}
+
 // Write returns tagValues for all Items in the repeating group ordered by
-// Group sequence and Group template order.
+// Group sequence and Group template order. The NumInGroup field counts the groups
+// as of the call, so a RepeatingGroup modified with InsertAt, RemoveAt or Swap is
+// set again on its FieldMap with SetGroup.
 func (f RepeatingGroup) Write() []TagValue {
 	tvs := make([]TagValue, 1)
 	tvs[0].init(f.tag, []byte(strconv.Itoa(len(f.groups))))
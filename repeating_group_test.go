@@ -104,6 +104,38 @@ func TestRepeatingGroup_Write(t *testing.T) {
 	}
 }
 
+func TestRepeatingGroup_InsertRemoveSwap(t *testing.T) {
+	f := RepeatingGroup{tag: 453, template: GroupTemplate{GroupElement(448), GroupElement(447)}}
+	f.Add().SetString(448, "A")
+	f.Add().SetString(448, "C")
+
+	f.InsertAt(1).SetString(448, "B").SetString(447, "D")
+	f.InsertAt(f.Len()).SetString(448, "E")
+	assert.Equal(t, 4, f.Len())
+
+	f.RemoveAt(0)
+	f.Swap(0, 2)
+	assert.Equal(t, 3, f.Len())
+
+	var body FieldMap
+	body.init()
+	body.SetGroup(f)
+
+	var written []byte
+	for _, tv := range body.tagLookup[453] {
+		written = append(written, tv.bytes...)
+	}
+	assert.Equal(t, "453=3\x01448=E\x01448=C\x01448=B\x01447=D\x01", string(written))
+
+	f.RemoveAt(0)
+	f.RemoveAt(0)
+	f.RemoveAt(0)
+	body.SetGroup(f)
+	assert.Equal(t, "453=0\x01", string(body.tagLookup[453][0].bytes))
+
+	assert.Panics(t, func() { f.RemoveAt(0) })
+}
+
 func TestRepeatingGroup_ReadError(t *testing.T) {
 	singleFieldTemplate := GroupTemplate{GroupElement(1)}
 	tests := []struct {
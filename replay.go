@@ -0,0 +1,404 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// RecordedEventType is the type of an input of a session captured by a Recorder.
+type RecordedEventType string
+
+// Types of the inputs of a session captured by a Recorder.
+const (
+	// RecordedStart is the start of the session, with its settings and sequence numbers.
+	RecordedStart RecordedEventType = "start"
+
+	// RecordedConnect is a connection of the session established.
+	RecordedConnect RecordedEventType = "connect"
+
+	// RecordedInbound is a batch of messages read from the connection.
+	RecordedInbound RecordedEventType = "inbound"
+
+	// RecordedDisconnect is the connection closed by the counterparty or the network.
+	RecordedDisconnect RecordedEventType = "disconnect"
+
+	// RecordedTimeout is a timer of the session firing, see RecordedEvent.Timeout.
+	RecordedTimeout RecordedEventType = "timeout"
+
+	// RecordedTick is the check of the session schedule and timeouts done every second.
+	RecordedTick RecordedEventType = "tick"
+
+	// RecordedSend is an application message sent with SendToTarget.
+	RecordedSend RecordedEventType = "send"
+
+	// RecordedSendQueued is the session sending the messages queued for send.
+	RecordedSendQueued RecordedEventType = "send_queued"
+
+	// RecordedStop is the session being stopped.
+	RecordedStop RecordedEventType = "stop"
+)
+
+// Names of the timers in RecordedEvent.Timeout.
+var recordedTimeouts = map[internal.Event]string{
+	internal.PeerTimeout:      "peer_timeout",
+	internal.NeedHeartbeat:    "need_heartbeat",
+	internal.LogonTimeout:     "logon_timeout",
+	internal.LogoutTimeout:    "logout_timeout",
+	internal.HandshakeTimeout: "handshake_timeout",
+}
+
+// RecordedMessage is a message read from the connection, in a RecordedInbound event.
+type RecordedMessage struct {
+	Data        string    `json:"data"`
+	ReceiveTime time.Time `json:"receive_time"`
+}
+
+// RecordedEvent is an input of a session captured by a Recorder. Time is the time of the session's Clock when the
+// input was processed.
+type RecordedEvent struct {
+	Time      time.Time         `json:"time"`
+	SessionID SessionID         `json:"session_id"`
+	Type      RecordedEventType `json:"type"`
+
+	// Settings, Initiator, NextSenderMsgSeqNum and NextTargetMsgSeqNum describe the session of a RecordedStart event.
+	Settings            map[string]string `json:"settings,omitempty"`
+	Initiator           bool              `json:"initiator,omitempty"`
+	NextSenderMsgSeqNum int               `json:"next_sender_msg_seq_num,omitempty"`
+	NextTargetMsgSeqNum int               `json:"next_target_msg_seq_num,omitempty"`
+
+	// Messages are the messages of a RecordedInbound event.
+	Messages []RecordedMessage `json:"messages,omitempty"`
+
+	// Timeout names the timer of a RecordedTimeout event.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Message is the application message of a RecordedSend event.
+	Message string `json:"message,omitempty"`
+}
+
+// Recorder captures the inputs of sessions, to reproduce their processing with Replay. See WithRecorder.
+// Record is called from the session goroutines, and from the goroutines of the application sending messages.
+type Recorder interface {
+	Record(event RecordedEvent) error
+}
+
+// WithRecorder sets the Recorder capturing the inputs of the sessions of the engine.
+func WithRecorder(recorder Recorder) EngineOption {
+	return func(o *engineOptions) { o.recorder = recorder }
+}
+
+type jsonRecorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewRecorder returns a Recorder writing the events to w as JSON, one per line, see ReadRecording.
+func NewRecorder(w io.Writer) Recorder {
+	return &jsonRecorder{enc: json.NewEncoder(w)}
+}
+
+func (r *jsonRecorder) Record(event RecordedEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(event)
+}
+
+// ReadRecording reads the events written by a Recorder returned by NewRecorder.
+func ReadRecording(r io.Reader) ([]RecordedEvent, error) {
+	var events []RecordedEvent
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for dec.More() {
+		var event RecordedEvent
+		if err := dec.Decode(&event); err != nil {
+			return nil, fmt.Errorf("unable to read recorded event %d: %w", len(events)+1, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// recordable returns the settings as strings, as captured in a RecordedStart event. Data dictionaries set in memory
+// are not included.
+func (s *SessionSettings) recordable() map[string]string {
+	settings := make(map[string]string, len(s.settings))
+	for k, v := range s.settings {
+		settings[k] = string(v)
+	}
+	return settings
+}
+
+// record captures event, stamped with the session id and, unless set, the current time.
+func (s *Session) record(event RecordedEvent) {
+	if s.recorder == nil {
+		return
+	}
+
+	event.SessionID = s.sessionID
+	if event.Time.IsZero() {
+		event.Time = s.now()
+	}
+	if err := s.recorder.Record(event); err != nil {
+		s.log.OnEventf("Failed to record %v: %v", event.Type, err)
+	}
+}
+
+func (s *Session) recordStart() {
+	if s.recorder == nil {
+		return
+	}
+
+	s.record(RecordedEvent{
+		Type:                RecordedStart,
+		Settings:            s.recordedSettings,
+		Initiator:           s.InitiateLogon,
+		NextSenderMsgSeqNum: s.store.NextSenderMsgSeqNum(),
+		NextTargetMsgSeqNum: s.store.NextTargetMsgSeqNum(),
+	})
+}
+
+func (s *Session) recordAdmin(msg interface{}) {
+	switch msg.(type) {
+	case connect:
+		s.record(RecordedEvent{Type: RecordedConnect})
+	case stopReq:
+		s.record(RecordedEvent{Type: RecordedStop})
+	}
+}
+
+func (s *Session) recordInbound(batch []fixIn) {
+	if s.recorder == nil {
+		return
+	}
+
+	messages := make([]RecordedMessage, 0, len(batch))
+	for _, in := range batch {
+		messages = append(messages, RecordedMessage{Data: in.bytes.String(), ReceiveTime: in.receiveTime})
+	}
+	s.record(RecordedEvent{Type: RecordedInbound, Messages: messages})
+}
+
+func (s *Session) recordTimeout(evt internal.Event) {
+	s.record(RecordedEvent{Type: RecordedTimeout, Timeout: recordedTimeouts[evt]})
+}
+
+func (s *Session) recordSend(msg *Message) {
+	if s.recorder == nil {
+		return
+	}
+	// The header is completed on a copy so that the message can be parsed on replay.
+	recorded := NewMessage()
+	msg.CopyInto(recorded)
+	s.fillDefaultHeader(recorded, nil)
+	s.record(RecordedEvent{Type: RecordedSend, Message: string(recorded.Build())})
+}
+
+// ReplayResult is the outcome of a Replay.
+type ReplayResult struct {
+	// Outbound are the messages sent by each session, in order.
+	Outbound map[SessionID][][]byte
+
+	// Status is the status of each session at the end of the recording.
+	Status map[SessionID]SessionStatus
+}
+
+// replayClock is the Clock of the replayed sessions, set to the time of the event being replayed.
+type replayClock struct {
+	now time.Time
+}
+
+func (c *replayClock) Now() time.Time {
+	return c.now
+}
+
+// replaySession is a session of a Replay, along with the messages it sent.
+type replaySession struct {
+	*Session
+	outbound  [][]byte
+	collector sync.WaitGroup
+	mu        sync.Mutex
+}
+
+// replayOutBuffer is the capacity of the connection of a replayed session, large enough that sending does not
+// depend on the progress of the goroutine collecting the messages sent.
+const replayOutBuffer = 1 << 16
+
+// Replay re-runs the sessions of a recording, reproducing deterministically how they processed their inputs. The
+// sessions are created from the settings recorded, with the sequence numbers they started with, and are not
+// registered. Their inputs are processed in the recorded order on the calling goroutine, with the time of each
+// session's Clock set to the time recorded.
+//
+// Messages the application sent with SendToTarget are replayed from the recording, so app must not send messages.
+// A nil app accepts every message. The stores default to memory stores: messages sent before the recording started
+// cannot be resent. EngineOptions set the LogFactory, MessageStoreFactory and HolidayCalendar of the sessions.
+func Replay(events []RecordedEvent, app Application, opts ...EngineOption) (ReplayResult, error) {
+	o := newEngineOptions(nil, nil, opts)
+	if app == nil {
+		app = replayApplication{}
+	}
+
+	clock := &replayClock{}
+	done := make(chan struct{})
+	defer close(done)
+
+	sessions := make(map[SessionID]*replaySession)
+	for i, event := range events {
+		clock.now = event.Time
+
+		if event.Type == RecordedStart {
+			session, err := startReplaySession(event, app, o, clock, done)
+			if err != nil {
+				return ReplayResult{}, fmt.Errorf("unable to replay event %d: %w", i+1, err)
+			}
+			sessions[event.SessionID] = session
+			continue
+		}
+
+		session, ok := sessions[event.SessionID]
+		if !ok {
+			return ReplayResult{}, fmt.Errorf("unable to replay event %d: session %v not started", i+1, event.SessionID)
+		}
+		if err := session.replay(event); err != nil {
+			return ReplayResult{}, fmt.Errorf("unable to replay event %d: %w", i+1, err)
+		}
+	}
+
+	result := ReplayResult{Outbound: make(map[SessionID][][]byte), Status: make(map[SessionID]SessionStatus)}
+	for sessionID, session := range sessions {
+		if session.messageOut != nil {
+			close(session.messageOut)
+			session.messageOut = nil
+		}
+		session.collector.Wait()
+
+		result.Outbound[sessionID] = session.outbound
+		result.Status[sessionID] = session.status()
+	}
+	return result, nil
+}
+
+// startReplaySession creates the session of a RecordedStart event.
+func startReplaySession(event RecordedEvent, app Application, o engineOptions, clock Clock, done chan struct{}) (*replaySession, error) {
+	settings := NewSessionSettings()
+	for k, v := range event.Settings {
+		settings.Set(k, v)
+	}
+
+	factory := sessionFactory{BuildInitiators: event.Initiator, clock: clock, holidays: o.holidays}
+	s, err := factory.newSession(event.SessionID, o.storeFactory, settings, o.logFactory, app)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.SetNextSenderMsgSeqNum(event.NextSenderMsgSeqNum); err != nil {
+		return nil, err
+	}
+	if err := s.store.SetNextTargetMsgSeqNum(event.NextTargetMsgSeqNum); err != nil {
+		return nil, err
+	}
+
+	// Timers are replayed from the recording, those started by the session are discarded.
+	go func() {
+		for {
+			select {
+			case <-s.sessionEvent:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	s.Start(s)
+	return &replaySession{Session: s}, nil
+}
+
+// replay processes event as the session goroutine would.
+func (s *replaySession) replay(event RecordedEvent) error {
+	switch event.Type {
+	case RecordedConnect:
+		out := make(chan []byte, replayOutBuffer)
+		s.collector.Add(1)
+		go func() {
+			defer s.collector.Done()
+			for msg := range out {
+				s.mu.Lock()
+				s.outbound = append(s.outbound, msg)
+				s.mu.Unlock()
+			}
+		}()
+		s.onAdmin(connect{messageOut: out, messageIn: make(chan []fixIn)})
+
+	case RecordedInbound:
+		batch := make([]fixIn, 0, len(event.Messages))
+		for _, m := range event.Messages {
+			batch = append(batch, fixIn{bytes: bytes.NewBufferString(m.Data), receiveTime: m.ReceiveTime})
+		}
+		s.incomingBatch(batch)
+
+	case RecordedDisconnect:
+		s.Disconnected(s.Session)
+
+	case RecordedTimeout:
+		for evt, name := range recordedTimeouts {
+			if name == event.Timeout {
+				s.Timeout(s.Session, evt)
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown timeout %q", event.Timeout)
+
+	case RecordedTick:
+		s.onTick(event.Time)
+
+	case RecordedSend:
+		msg := NewMessage()
+		if err := s.ParseMessage(msg, bytes.NewBufferString(event.Message)); err != nil {
+			return err
+		}
+		if s.requiresApproval(msg) {
+			return s.hold(msg)
+		}
+		return s.queueForSend(msg)
+
+	case RecordedSendQueued:
+		s.SendAppMessages(s.Session)
+
+	case RecordedStop:
+		s.onAdmin(stopReq{})
+
+	default:
+		return fmt.Errorf("unknown event type %q", event.Type)
+	}
+	return nil
+}
+
+// replayApplication is the Application of a Replay without one, accepting every message.
+type replayApplication struct{}
+
+func (replayApplication) OnCreate(SessionID)                               {}
+func (replayApplication) OnLogon(SessionID)                                {}
+func (replayApplication) OnLogout(SessionID)                               {}
+func (replayApplication) ToAdmin(*Message, SessionID)                      {}
+func (replayApplication) ToApp(*Message, SessionID) error                  { return nil }
+func (replayApplication) FromAdmin(*Message, SessionID) MessageRejectError { return nil }
+func (replayApplication) FromApp(*Message, SessionID) MessageRejectError   { return nil }
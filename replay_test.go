@@ -0,0 +1,147 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReplayTestSuite struct {
+	QuickFIXSuite
+	MessageFactory
+	sessionID SessionID
+}
+
+func TestReplayTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplayTestSuite))
+}
+
+func (s *ReplayTestSuite) SetupTest() {
+	s.MessageFactory = MessageFactory{}
+	s.sessionID = SessionID{BeginString: "FIX.4.2", TargetCompID: "TW", SenderCompID: "ISLD"}
+}
+
+// receiveOutbound returns the next message sent on out.
+func (s *ReplayTestSuite) receiveOutbound(out <-chan []byte) []byte {
+	select {
+	case msg := <-out:
+		return msg
+	case <-time.After(5 * time.Second):
+		s.FailNow("no message sent")
+		return nil
+	}
+}
+
+func (s *ReplayTestSuite) TestRecordAndReplay() {
+	var recording bytes.Buffer
+	factory := sessionFactory{clock: fixedClock{time.Now().Truncate(time.Millisecond)}, recorder: NewRecorder(&recording)}
+	session, err := factory.newSession(s.sessionID, NewMemoryStoreFactory(), NewSessionSettings(), nullLogFactory{}, replayApplication{})
+	s.Require().Nil(err)
+
+	stopped := make(chan struct{})
+	go func() {
+		session.run()
+		close(stopped)
+	}()
+
+	in := make(chan []fixIn, 1)
+	out := make(chan []byte, 10)
+	s.Require().Nil(session.connect(in, out))
+
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(30))
+	in <- []fixIn{{bytes: bytes.NewBuffer(logon.Build()), receiveTime: time.Now()}}
+
+	var live [][]byte
+	live = append(live, s.receiveOutbound(out))
+
+	order := NewMessage()
+	order.Header.SetField(tagMsgType, FIXString("D"))
+	order.Body.SetField(tagClOrdID, FIXString("order1"))
+	s.Require().Nil(session.SendToTarget(order))
+	live = append(live, s.receiveOutbound(out))
+
+	close(in)
+	for msg := range out {
+		live = append(live, msg)
+	}
+	session.stop()
+	<-stopped
+
+	events, err := ReadRecording(&recording)
+	s.Require().Nil(err)
+	s.Require().NotEmpty(events)
+	s.Equal(RecordedStart, events[0].Type)
+	s.Equal(s.sessionID, events[0].SessionID)
+	s.Equal(1, events[0].NextSenderMsgSeqNum)
+
+	var types []RecordedEventType
+	for _, event := range events {
+		if event.Type != RecordedTick {
+			types = append(types, event.Type)
+		}
+	}
+	s.Equal([]RecordedEventType{
+		RecordedStart, RecordedConnect, RecordedInbound, RecordedSend, RecordedSendQueued, RecordedDisconnect, RecordedStop,
+	}, types)
+
+	result, err := Replay(events, nil)
+	s.Require().Nil(err)
+	s.Equal(live, result.Outbound[s.sessionID], "the replay sends the messages of the recording")
+	s.Equal(3, result.Status[s.sessionID].NextSenderMsgSeqNum)
+	s.Equal(2, result.Status[s.sessionID].NextTargetMsgSeqNum)
+
+	again, err := Replay(events, nil)
+	s.Require().Nil(err)
+	s.Equal(result, again)
+}
+
+func (s *ReplayTestSuite) TestReplayTimeout() {
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(30))
+	logon.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: start})
+
+	result, err := Replay([]RecordedEvent{
+		{Time: start, SessionID: s.sessionID, Type: RecordedStart, NextSenderMsgSeqNum: 1, NextTargetMsgSeqNum: 1},
+		{Time: start, SessionID: s.sessionID, Type: RecordedConnect},
+		{Time: start, SessionID: s.sessionID, Type: RecordedInbound, Messages: []RecordedMessage{{Data: string(logon.Build())}}},
+		{Time: start.Add(30 * time.Second), SessionID: s.sessionID, Type: RecordedTimeout, Timeout: "need_heartbeat"},
+	}, nil)
+	s.Require().Nil(err)
+
+	outbound := result.Outbound[s.sessionID]
+	s.Require().Len(outbound, 2)
+	heartbeat := NewMessage()
+	s.Require().Nil(ParseMessage(heartbeat, bytes.NewBuffer(outbound[1])))
+	s.FieldEquals(tagMsgType, string(msgTypeHeartbeat), heartbeat.Header)
+	s.FieldEquals(tagSendingTime, "20240301-09:30:30.000", heartbeat.Header)
+}
+
+func (s *ReplayTestSuite) TestSessionNotStarted() {
+	_, err := Replay([]RecordedEvent{{SessionID: s.sessionID, Type: RecordedConnect}}, nil)
+	s.NotNil(err)
+
+	_, err = Replay([]RecordedEvent{
+		{SessionID: s.sessionID, Type: RecordedStart, NextSenderMsgSeqNum: 1, NextTargetMsgSeqNum: 1},
+		{SessionID: s.sessionID, Type: RecordedTimeout, Timeout: "unknown"},
+	}, nil)
+	s.NotNil(err)
+}
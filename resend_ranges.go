@@ -0,0 +1,145 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SeqNumRange is an inclusive range of message sequence numbers.
+type SeqNumRange struct {
+	Begin, End int
+}
+
+func (r SeqNumRange) String() string {
+	return fmt.Sprintf("%v-%v", r.Begin, r.End)
+}
+
+// resendRanges tracks the ranges requested from the counterparty with a ResendRequest that have not been received yet.
+// A range left unanswered for the ResendRequestTimeout no longer suppresses ResendRequests, so that it is requested
+// again.
+type resendRanges struct {
+	sync.Mutex
+	ranges []requestedRange
+}
+
+// requestedRange is an outstanding range and when it was last requested.
+type requestedRange struct {
+	SeqNumRange
+	requested time.Time
+}
+
+// outstanding returns a copy of the ranges not yet received, given the next expected target sequence number.
+func (r *resendRanges) outstanding(nextTargetMsgSeqNum int) []SeqNumRange {
+	r.Lock()
+	defer r.Unlock()
+
+	r.pruneLocked(nextTargetMsgSeqNum)
+	if len(r.ranges) == 0 {
+		return nil
+	}
+	outstanding := make([]SeqNumRange, 0, len(r.ranges))
+	for _, rng := range r.ranges {
+		outstanding = append(outstanding, rng.SeqNumRange)
+	}
+	return outstanding
+}
+
+// uncovered trims the range begin-end against the outstanding ranges requested since expiry, the ranges requested
+// before having timed out. ok is false if the whole range is already outstanding.
+func (r *resendRanges) uncovered(nextTargetMsgSeqNum, begin, end int, expiry time.Time) (trimmed SeqNumRange, ok bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.pruneLocked(nextTargetMsgSeqNum)
+	trimmed = SeqNumRange{Begin: begin, End: end}
+	for _, rng := range r.ranges {
+		if !rng.requested.Before(expiry) && rng.Begin <= trimmed.Begin && rng.End >= trimmed.Begin {
+			trimmed.Begin = rng.End + 1
+		}
+	}
+	for i := len(r.ranges) - 1; i >= 0; i-- {
+		if rng := r.ranges[i]; !rng.requested.Before(expiry) && rng.Begin <= trimmed.End && rng.End >= trimmed.End {
+			trimmed.End = rng.Begin - 1
+		}
+	}
+	return trimmed, trimmed.Begin <= trimmed.End
+}
+
+// expired returns the first outstanding range requested before expiry, which the counterparty left unanswered.
+func (r *resendRanges) expired(nextTargetMsgSeqNum int, expiry time.Time) (SeqNumRange, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.pruneLocked(nextTargetMsgSeqNum)
+	for _, rng := range r.ranges {
+		if rng.requested.Before(expiry) {
+			return rng.SeqNumRange, true
+		}
+	}
+	return SeqNumRange{}, false
+}
+
+// add records a range requested at the time requested, coalescing it with overlapping or adjacent outstanding ranges.
+// Coalesced ranges keep the time of their latest request.
+func (r *resendRanges) add(nextTargetMsgSeqNum int, rng SeqNumRange, requested time.Time) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.pruneLocked(nextTargetMsgSeqNum)
+	ranges := append(r.ranges, requestedRange{SeqNumRange: rng, requested: requested})
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Begin < ranges[j].Begin })
+
+	merged := ranges[:1]
+	for _, next := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if next.Begin <= last.End+1 {
+			if next.End > last.End {
+				last.End = next.End
+			}
+			if next.requested.After(last.requested) {
+				last.requested = next.requested
+			}
+			continue
+		}
+		merged = append(merged, next)
+	}
+	r.ranges = merged
+}
+
+func (r *resendRanges) clear() {
+	r.Lock()
+	defer r.Unlock()
+	r.ranges = nil
+}
+
+// pruneLocked drops the ranges that have been received in full and trims the partially received ones.
+func (r *resendRanges) pruneLocked(nextTargetMsgSeqNum int) {
+	kept := r.ranges[:0]
+	for _, rng := range r.ranges {
+		if rng.End < nextTargetMsgSeqNum {
+			continue
+		}
+		if rng.Begin < nextTargetMsgSeqNum {
+			rng.Begin = nextTargetMsgSeqNum
+		}
+		kept = append(kept, rng)
+	}
+	r.ranges = kept
+}
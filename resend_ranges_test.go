@@ -0,0 +1,102 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResendRangesCoalesce(t *testing.T) {
+	var r resendRanges
+	r.add(1, SeqNumRange{Begin: 10, End: 20}, time.Time{})
+	r.add(1, SeqNumRange{Begin: 1, End: 5}, time.Time{})
+	r.add(1, SeqNumRange{Begin: 6, End: 8}, time.Time{})
+	r.add(1, SeqNumRange{Begin: 15, End: 25}, time.Time{})
+
+	assert.Equal(t, []SeqNumRange{{Begin: 1, End: 8}, {Begin: 10, End: 25}}, r.outstanding(1))
+}
+
+func TestResendRangesPrune(t *testing.T) {
+	var r resendRanges
+	r.add(1, SeqNumRange{Begin: 1, End: 5}, time.Time{})
+	r.add(1, SeqNumRange{Begin: 10, End: 20}, time.Time{})
+
+	assert.Equal(t, []SeqNumRange{{Begin: 12, End: 20}}, r.outstanding(12))
+	assert.Nil(t, r.outstanding(21))
+}
+
+func TestResendRangesUncovered(t *testing.T) {
+	var tests = []struct {
+		begin, end int
+		expected   SeqNumRange
+		expectedOK bool
+	}{
+		{1, 4, SeqNumRange{Begin: 1, End: 4}, true},
+		{1, 12, SeqNumRange{Begin: 1, End: 4}, true},
+		{5, 12, SeqNumRange{}, false},
+		{5, 30, SeqNumRange{Begin: 21, End: 30}, true},
+		{3, 25, SeqNumRange{Begin: 3, End: 25}, true},
+		{10, 15, SeqNumRange{}, false},
+	}
+
+	for _, test := range tests {
+		var r resendRanges
+		r.add(1, SeqNumRange{Begin: 5, End: 12}, time.Time{})
+		r.add(1, SeqNumRange{Begin: 10, End: 20}, time.Time{})
+
+		trimmed, ok := r.uncovered(1, test.begin, test.end, time.Time{})
+		assert.Equal(t, test.expectedOK, ok, "%v-%v", test.begin, test.end)
+		if ok {
+			assert.Equal(t, test.expected, trimmed, "%v-%v", test.begin, test.end)
+		}
+	}
+}
+
+func TestResendRangesClear(t *testing.T) {
+	var r resendRanges
+	r.add(1, SeqNumRange{Begin: 1, End: 5}, time.Time{})
+	r.clear()
+
+	assert.Nil(t, r.outstanding(1))
+}
+
+func TestResendRangesExpired(t *testing.T) {
+	requested := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	var r resendRanges
+	r.add(1, SeqNumRange{Begin: 1, End: 5}, requested)
+	r.add(1, SeqNumRange{Begin: 10, End: 20}, requested.Add(time.Minute))
+
+	_, ok := r.uncovered(1, 1, 5, requested)
+	assert.False(t, ok)
+	_, ok = r.expired(1, requested)
+	assert.False(t, ok)
+
+	expiry := requested.Add(time.Second)
+	trimmed, ok := r.uncovered(1, 1, 12, expiry)
+	assert.True(t, ok)
+	assert.Equal(t, SeqNumRange{Begin: 1, End: 9}, trimmed)
+	rng, ok := r.expired(1, expiry)
+	assert.True(t, ok)
+	assert.Equal(t, SeqNumRange{Begin: 1, End: 5}, rng)
+
+	r.add(1, SeqNumRange{Begin: 1, End: 9}, expiry)
+	_, ok = r.expired(1, expiry)
+	assert.False(t, ok, "requested again, the coalesced range has not timed out")
+	assert.Equal(t, []SeqNumRange{{Begin: 1, End: 20}}, r.outstanding(1))
+}
@@ -30,6 +30,9 @@ func (s resendState) Timeout(session *Session, event internal.Event) (nextState
 	nextState = inSession{}.Timeout(session, event)
 	switch nextState.(type) {
 	case inSession:
+		if err := session.retryResendRequest(); err != nil {
+			return handleStateError(session, err)
+		}
 		nextState = s
 	case pendingTimeout:
 		// Wrap pendingTimeout in resend. prevents us falling back to inSession if recovering
@@ -73,6 +76,9 @@ func (s resendState) FixMsgIn(session *Session, msg *Message) (nextState session
 	}
 
 	if s.resendRangeEnd >= session.store.NextTargetMsgSeqNum() {
+		if err := session.retryResendRequest(); err != nil {
+			return handleStateError(session, err)
+		}
 		return s
 	}
 
@@ -74,6 +74,24 @@ func (s *resendStateTestSuite) TestTimeoutUnchangedNeedHeartbeat() {
 	s.State(resendState{})
 }
 
+func (s *resendStateTestSuite) TestTimeoutNeedHeartbeatRequestsTimedOutRangeAgain() {
+	s.Session.ResendRequestTimeout = time.Minute
+	s.Session.resendRanges.add(1, SeqNumRange{Begin: 1, End: 4}, s.Session.now().Add(-2*time.Minute))
+
+	s.MockApp.On("ToAdmin")
+	s.Session.Timeout(s.Session, internal.NeedHeartbeat)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(resendState{})
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagBeginSeqNo, 1, s.MockApp.lastToAdmin.Body)
+
+	// Requested again just now, the range has not timed out anymore.
+	s.MockApp.On("ToAdmin")
+	s.Session.Timeout(s.Session, internal.NeedHeartbeat)
+	s.MessageType(string(msgTypeHeartbeat), s.MockApp.lastToAdmin)
+}
+
 func (s *resendStateTestSuite) TestFixMsgIn() {
 	s.Session.State = inSession{}
 
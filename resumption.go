@@ -0,0 +1,230 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const resumptionTokenVersion byte = 1
+
+// ErrStaleResumptionToken is returned when importing a ResumptionToken whose epoch has already been claimed,
+// i.e. the session was resumed by another engine instance in the meantime.
+var ErrStaleResumptionToken = errors.New("Stale resumption token")
+
+var errInvalidResumptionToken = errors.New("Invalid resumption token")
+
+var errResumptionRequiresEpochStore = errors.New("Resumption tokens require a MessageStore implementing EpochStore")
+
+// ResumptionToken captures the state required for a replacement engine instance to resume a FIX session,
+// e.g. behind a TCP load balancer.
+type ResumptionToken struct {
+	SessionID           SessionID
+	NextSenderMsgSeqNum int
+	NextTargetMsgSeqNum int
+	CreationTime        time.Time
+
+	// Epoch is the ownership epoch of the session at export time. Importing the token claims epoch Epoch+1,
+	// which fences off any instance still holding an older epoch.
+	Epoch uint64
+}
+
+// Encode returns the compact, URL safe string form of the token.
+func (t ResumptionToken) Encode() string {
+	b := []byte{resumptionTokenVersion}
+	b = binary.AppendUvarint(b, t.Epoch)
+	b = binary.AppendUvarint(b, uint64(t.NextSenderMsgSeqNum))
+	b = binary.AppendUvarint(b, uint64(t.NextTargetMsgSeqNum))
+	b = binary.AppendVarint(b, t.CreationTime.UnixNano())
+	for _, f := range t.sessionIDFields() {
+		b = binary.AppendUvarint(b, uint64(len(*f)))
+		b = append(b, *f...)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (t *ResumptionToken) sessionIDFields() []*string {
+	id := &t.SessionID
+	return []*string{
+		&id.BeginString, &id.SenderCompID, &id.SenderSubID, &id.SenderLocationID,
+		&id.TargetCompID, &id.TargetSubID, &id.TargetLocationID, &id.Qualifier,
+	}
+}
+
+// DecodeResumptionToken parses a token previously produced by ResumptionToken.Encode.
+func DecodeResumptionToken(s string) (t ResumptionToken, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, errInvalidResumptionToken
+	}
+	if len(b) == 0 || b[0] != resumptionTokenVersion {
+		return t, errInvalidResumptionToken
+	}
+	b = b[1:]
+
+	readUvarint := func() uint64 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			err = errInvalidResumptionToken
+			return 0
+		}
+		b = b[n:]
+		return v
+	}
+
+	t.Epoch = readUvarint()
+	t.NextSenderMsgSeqNum = int(readUvarint())
+	t.NextTargetMsgSeqNum = int(readUvarint())
+
+	creationTime, n := binary.Varint(b)
+	if err != nil || n <= 0 {
+		return t, errInvalidResumptionToken
+	}
+	b = b[n:]
+	t.CreationTime = time.Unix(0, creationTime)
+
+	for _, f := range t.sessionIDFields() {
+		l := readUvarint()
+		if err != nil || l > uint64(len(b)) {
+			return t, errInvalidResumptionToken
+		}
+		*f = string(b[:l])
+		b = b[l:]
+	}
+
+	if len(b) != 0 {
+		return t, errInvalidResumptionToken
+	}
+	return t, nil
+}
+
+type exportResumptionReq struct {
+	rep chan<- exportResumptionRep
+}
+
+type exportResumptionRep struct {
+	token ResumptionToken
+	err   error
+}
+
+type importResumptionReq struct {
+	token ResumptionToken
+	rep   chan<- error
+}
+
+// ExportResumptionToken returns a ResumptionToken for the Session matching the Session id.
+// The exporting instance should disconnect the session before handing the token over. The Session must be
+// running, and its MessageStore must implement EpochStore.
+func ExportResumptionToken(sessionID SessionID) (ResumptionToken, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ResumptionToken{}, ErrSessionNotFound
+	}
+
+	rep := make(chan exportResumptionRep, 1)
+	session.admin <- exportResumptionReq{rep: rep}
+	r := <-rep
+	return r.token, r.err
+}
+
+// ImportResumptionToken resumes the Session matching the token's Session id from the given token.
+// The session must be running but not connected, and its MessageStore must implement EpochStore. The token's
+// epoch is claimed atomically and ErrStaleResumptionToken is returned when another instance claimed it first.
+func ImportResumptionToken(token ResumptionToken) error {
+	session, ok := lookupSession(token.SessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	rep := make(chan error, 1)
+	session.admin <- importResumptionReq{token: token, rep: rep}
+	return <-rep
+}
+
+func (s *Session) exportResumptionToken() (ResumptionToken, error) {
+	epochStore, ok := s.store.(EpochStore)
+	if !ok {
+		return ResumptionToken{}, errResumptionRequiresEpochStore
+	}
+
+	epoch, err := epochStore.Epoch()
+	if err != nil {
+		return ResumptionToken{}, err
+	}
+
+	return ResumptionToken{
+		SessionID:           s.sessionID,
+		NextSenderMsgSeqNum: s.store.NextSenderMsgSeqNum(),
+		NextTargetMsgSeqNum: s.store.NextTargetMsgSeqNum(),
+		CreationTime:        s.store.CreationTime(),
+		Epoch:               epoch,
+	}, nil
+}
+
+func (s *Session) importResumptionToken(token ResumptionToken) error {
+	if token.SessionID != s.sessionID {
+		return errInvalidResumptionToken
+	}
+	if s.State != nil && s.IsConnected() {
+		return errors.New("Cannot import resumption token while connected")
+	}
+
+	epochStore, ok := s.store.(EpochStore)
+	if !ok {
+		return errResumptionRequiresEpochStore
+	}
+	swapped, err := epochStore.CompareAndSwapEpoch(token.Epoch, token.Epoch+1)
+	if err != nil {
+		return err
+	}
+	if !swapped {
+		return ErrStaleResumptionToken
+	}
+
+	if err := s.store.SetNextSenderMsgSeqNum(token.NextSenderMsgSeqNum); err != nil {
+		return err
+	}
+	if err := s.store.SetNextTargetMsgSeqNum(token.NextTargetMsgSeqNum); err != nil {
+		return err
+	}
+	s.store.SetCreationTime(token.CreationTime)
+	s.epoch = token.Epoch + 1
+
+	s.log.OnEventf("Resumed session at epoch %v, next sender seqnum %v, next target seqnum %v",
+		s.epoch, token.NextSenderMsgSeqNum, token.NextTargetMsgSeqNum)
+	return nil
+}
+
+// checkEpoch returns an error if the session was resumed by another engine instance.
+func (s *Session) checkEpoch() error {
+	epochStore, ok := s.store.(EpochStore)
+	if !ok {
+		return nil
+	}
+
+	epoch, err := epochStore.Epoch()
+	if err != nil {
+		return err
+	}
+	if epoch != s.epoch {
+		return fmt.Errorf("Session fenced, resumed by another instance at epoch %v (local epoch %v)", epoch, s.epoch)
+	}
+	return nil
+}
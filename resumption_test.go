@@ -0,0 +1,122 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ResumptionTokenSuite struct {
+	SessionSuiteRig
+}
+
+func TestResumptionTokenSuite(t *testing.T) {
+	suite.Run(t, new(ResumptionTokenSuite))
+}
+
+func (s *ResumptionTokenSuite) SetupTest() {
+	s.Init()
+	s.Require().Nil(s.Session.store.Reset())
+	s.Session.State = latentState{}
+}
+
+func (s *ResumptionTokenSuite) TestEncodeDecode() {
+	token := ResumptionToken{
+		SessionID:           SessionID{BeginString: "FIXT.1.1", SenderCompID: "ISLD", SenderSubID: "A", TargetCompID: "TW", Qualifier: "q"},
+		NextSenderMsgSeqNum: 1234,
+		NextTargetMsgSeqNum: 99,
+		CreationTime:        time.Date(2024, 3, 1, 7, 0, 0, 123, time.UTC),
+		Epoch:               7,
+	}
+
+	decoded, err := DecodeResumptionToken(token.Encode())
+	s.Require().Nil(err)
+	s.Equal(token.SessionID, decoded.SessionID)
+	s.Equal(token.NextSenderMsgSeqNum, decoded.NextSenderMsgSeqNum)
+	s.Equal(token.NextTargetMsgSeqNum, decoded.NextTargetMsgSeqNum)
+	s.True(token.CreationTime.Equal(decoded.CreationTime))
+	s.Equal(token.Epoch, decoded.Epoch)
+}
+
+func (s *ResumptionTokenSuite) TestDecodeInvalid() {
+	for _, encoded := range []string{"", "!!", "AA", ResumptionToken{}.Encode() + "AA"} {
+		_, err := DecodeResumptionToken(encoded)
+		s.NotNil(err, encoded)
+	}
+}
+
+func (s *ResumptionTokenSuite) TestExportImport() {
+	s.Require().Nil(s.store.SetNextSenderMsgSeqNum(10))
+	s.Require().Nil(s.store.SetNextTargetMsgSeqNum(20))
+
+	token, err := s.Session.exportResumptionToken()
+	s.Require().Nil(err)
+	s.Equal(10, token.NextSenderMsgSeqNum)
+	s.Equal(20, token.NextTargetMsgSeqNum)
+	s.Equal(uint64(0), token.Epoch)
+
+	replacement := &Session{sessionID: s.sessionID, store: new(memoryStore), log: nullLog{}}
+	s.Require().Nil(replacement.store.Reset())
+	s.Require().Nil(replacement.importResumptionToken(token))
+
+	s.Equal(10, replacement.store.NextSenderMsgSeqNum())
+	s.Equal(20, replacement.store.NextTargetMsgSeqNum())
+	s.Equal(uint64(1), replacement.epoch)
+}
+
+func (s *ResumptionTokenSuite) TestImportFencesSharedStore() {
+	token, err := s.Session.exportResumptionToken()
+	s.Require().Nil(err)
+
+	replacement := &Session{sessionID: s.sessionID, store: s.Session.store, log: nullLog{}}
+	s.Require().Nil(replacement.importResumptionToken(token))
+	s.Equal(ErrStaleResumptionToken, replacement.importResumptionToken(token), "token can only be claimed once")
+
+	rep := make(chan error, 1)
+	s.Session.onAdmin(connect{messageOut: s.Receiver.sendChannel, err: rep})
+	s.NotNil(<-rep, "original instance should be fenced off")
+	s.State(latentState{})
+}
+
+func (s *ResumptionTokenSuite) TestImportRequiresEpochStore() {
+	token, err := s.Session.exportResumptionToken()
+	s.Require().Nil(err)
+
+	replacement := &Session{sessionID: s.sessionID, store: struct{ MessageStore }{new(memoryStore)}, log: nullLog{}}
+	s.Equal(errResumptionRequiresEpochStore, replacement.importResumptionToken(token))
+	_, err = replacement.exportResumptionToken()
+	s.Equal(errResumptionRequiresEpochStore, err)
+}
+
+func (s *ResumptionTokenSuite) TestImportThroughAdmin() {
+	token, err := s.Session.exportResumptionToken()
+	s.Require().Nil(err)
+	s.Require().Nil(s.store.SetNextSenderMsgSeqNum(5))
+
+	rep := make(chan error, 1)
+	s.Session.onAdmin(importResumptionReq{token: token, rep: rep})
+	s.Nil(<-rep)
+	s.Equal(1, s.store.NextSenderMsgSeqNum())
+	s.Equal(uint64(1), s.Session.epoch)
+}
+
+func (s *ResumptionTokenSuite) TestImportWrongSession() {
+	token := ResumptionToken{SessionID: SessionID{BeginString: "FIX.4.4", SenderCompID: "A", TargetCompID: "B"}}
+	s.NotNil(s.Session.importResumptionToken(token))
+}
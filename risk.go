@@ -0,0 +1,169 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// RiskChecker is a pre-trade check of the outbound application messages of a session, see RegisterRiskChecker.
+type RiskChecker interface {
+	// CheckRisk is called synchronously before msg is queued for sending, with the statistics of the messages
+	// accepted so far. Returning an error rejects msg, which is then not sent and consumes no sequence number.
+	CheckRisk(msg *Message, sessionID SessionID, stats RiskStats) error
+}
+
+// RiskStats are the cumulative statistics of the outbound application messages of a session accepted by its
+// RiskCheckers, since the session was created or ResetRiskStats was called.
+type RiskStats struct {
+	// Messages is the number of application messages sent.
+	Messages int
+
+	// Orders is the number of NewOrderSingle messages sent.
+	Orders int
+
+	// GrossNotional is the sum of the OrderNotional of the NewOrderSingle messages sent.
+	GrossNotional decimal.Decimal
+}
+
+// ErrRiskRejected indicates that an outgoing message was rejected by a RiskChecker, use errors.As to retrieve the details.
+type ErrRiskRejected struct {
+	Reason string
+	Err    error
+}
+
+func (e ErrRiskRejected) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Risk check failed: %s: %s", e.Reason, e.Err.Error())
+	}
+	return fmt.Sprintf("Risk check failed: %s", e.Reason)
+}
+
+// Unwrap returns the underlying error, if any.
+func (e ErrRiskRejected) Unwrap() error { return e.Err }
+
+// OrderNotional returns OrderQty * Price of an order message, false if either field is missing or malformed.
+func OrderNotional(msg *Message) (decimal.Decimal, bool) {
+	var qty, price FIXDecimal
+	if err := msg.Body.GetField(tagOrderQty, &qty); err != nil {
+		return decimal.Zero, false
+	}
+	if err := msg.Body.GetField(tagPrice, &price); err != nil {
+		return decimal.Zero, false
+	}
+	return qty.Mul(price.Decimal), true
+}
+
+// riskChecks are the RiskCheckers of a session and the statistics of the messages they accepted.
+type riskChecks struct {
+	sync.Mutex
+	checkers []RiskChecker
+	stats    RiskStats
+}
+
+// RegisterRiskChecker adds a RiskChecker to the Session matching the Session id. RiskCheckers run in registration
+// order, the first error rejecting the message.
+func RegisterRiskChecker(sessionID SessionID, checker RiskChecker) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.risk.Lock()
+	defer session.risk.Unlock()
+	session.risk.checkers = append(session.risk.checkers, checker)
+	return nil
+}
+
+// GetRiskStats returns the RiskStats of the Session matching the Session id.
+func GetRiskStats(sessionID SessionID) (RiskStats, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return RiskStats{}, ErrSessionNotFound
+	}
+
+	session.risk.Lock()
+	defer session.risk.Unlock()
+	return session.risk.stats, nil
+}
+
+// ResetRiskStats clears the RiskStats of the Session matching the Session id, typically at the start of a trading day.
+func ResetRiskStats(sessionID SessionID) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.risk.reset()
+	return nil
+}
+
+// active returns whether RiskCheckers are registered.
+func (r *riskChecks) active() bool {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.checkers) > 0
+}
+
+// check runs the RiskCheckers on msg, returning an ErrRiskRejected if one of them rejects it.
+func (r *riskChecks) check(msg *Message, sessionID SessionID) error {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.checkers) == 0 {
+		return nil
+	}
+
+	for _, checker := range r.checkers {
+		if err := checker.CheckRisk(msg, sessionID, r.stats); err != nil {
+			var rejected ErrRiskRejected
+			if errors.As(err, &rejected) {
+				return err
+			}
+			return ErrRiskRejected{Reason: "rejected by risk checker", Err: err}
+		}
+	}
+	return nil
+}
+
+// record adds msg, accepted and persisted, to the statistics.
+func (r *riskChecks) record(msg *Message, msgType []byte) {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.checkers) == 0 {
+		return
+	}
+
+	r.stats.Messages++
+	if string(msgType) != "D" {
+		return
+	}
+
+	r.stats.Orders++
+	if notional, ok := OrderNotional(msg); ok {
+		r.stats.GrossNotional = r.stats.GrossNotional.Add(notional.Abs())
+	}
+}
+
+// reset clears the statistics.
+func (r *riskChecks) reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.stats = RiskStats{}
+}
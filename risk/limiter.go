@@ -0,0 +1,97 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package risk provides a sample quickfix.RiskChecker limiting the notional and the rate of new orders.
+package risk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Limits are the limits enforced by a Limiter on NewOrderSingle messages. Zero values disable a limit.
+type Limits struct {
+	// MaxOrderNotional is the largest OrderQty * Price of a single order.
+	MaxOrderNotional decimal.Decimal
+
+	// MaxGrossNotional is the largest gross notional of all the orders of a session, see quickfix.RiskStats.
+	MaxGrossNotional decimal.Decimal
+
+	// MaxOrders is the largest number of orders of a session within Interval.
+	MaxOrders int
+	Interval  time.Duration
+}
+
+// Limiter is a quickfix.RiskChecker enforcing Limits. Orders rejected by a RiskChecker registered after the
+// Limiter still count towards MaxOrders.
+type Limiter struct {
+	limits Limits
+	now    func() time.Time
+
+	mu     sync.Mutex
+	orders map[quickfix.SessionID][]time.Time
+}
+
+// NewLimiter returns a Limiter enforcing limits.
+func NewLimiter(limits Limits) *Limiter {
+	return &Limiter{limits: limits, now: time.Now, orders: make(map[quickfix.SessionID][]time.Time)}
+}
+
+// CheckRisk implements quickfix.RiskChecker.
+func (l *Limiter) CheckRisk(msg *quickfix.Message, sessionID quickfix.SessionID, stats quickfix.RiskStats) error {
+	msgType, err := msg.MsgType()
+	if err != nil || msgType != "D" {
+		return nil
+	}
+
+	if notional, ok := quickfix.OrderNotional(msg); ok {
+		notional = notional.Abs()
+		if l.limits.MaxOrderNotional.IsPositive() && notional.GreaterThan(l.limits.MaxOrderNotional) {
+			return quickfix.ErrRiskRejected{Reason: fmt.Sprintf("order notional %v exceeds %v", notional, l.limits.MaxOrderNotional)}
+		}
+
+		gross := stats.GrossNotional.Add(notional)
+		if l.limits.MaxGrossNotional.IsPositive() && gross.GreaterThan(l.limits.MaxGrossNotional) {
+			return quickfix.ErrRiskRejected{Reason: fmt.Sprintf("gross notional %v exceeds %v", gross, l.limits.MaxGrossNotional)}
+		}
+	}
+
+	if l.limits.MaxOrders <= 0 || l.limits.Interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	since := now.Add(-l.limits.Interval)
+	orders := l.orders[sessionID]
+	for len(orders) > 0 && !orders[0].After(since) {
+		orders = orders[1:]
+	}
+
+	if len(orders) >= l.limits.MaxOrders {
+		l.orders[sessionID] = orders
+		return quickfix.ErrRiskRejected{Reason: fmt.Sprintf("more than %v orders per %v", l.limits.MaxOrders, l.limits.Interval)}
+	}
+
+	l.orders[sessionID] = append(orders, now)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+var sessionID = quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+func order(msgType, qty, price string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(35), msgType)
+	msg.Body.SetString(quickfix.Tag(38), qty)
+	msg.Body.SetString(quickfix.Tag(44), price)
+	return msg
+}
+
+func TestLimiterNotional(t *testing.T) {
+	l := NewLimiter(Limits{MaxOrderNotional: decimal.NewFromInt(1000), MaxGrossNotional: decimal.NewFromInt(5000)})
+
+	assert.NoError(t, l.CheckRisk(order("D", "10", "100"), sessionID, quickfix.RiskStats{}))
+
+	err := l.CheckRisk(order("D", "10", "100.5"), sessionID, quickfix.RiskStats{})
+	assert.ErrorAs(t, err, &quickfix.ErrRiskRejected{})
+	assert.ErrorContains(t, err, "order notional 1005 exceeds 1000")
+
+	err = l.CheckRisk(order("D", "10", "100"), sessionID, quickfix.RiskStats{GrossNotional: decimal.NewFromInt(4500)})
+	assert.ErrorContains(t, err, "gross notional 5500 exceeds 5000")
+
+	assert.NoError(t, l.CheckRisk(order("F", "10", "1000"), sessionID, quickfix.RiskStats{}), "only new orders are checked")
+}
+
+func TestLimiterVelocity(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(Limits{MaxOrders: 2, Interval: time.Second})
+	l.now = func() time.Time { return now }
+
+	assert.NoError(t, l.CheckRisk(order("D", "1", "1"), sessionID, quickfix.RiskStats{}))
+	now = now.Add(500 * time.Millisecond)
+	assert.NoError(t, l.CheckRisk(order("D", "1", "1"), sessionID, quickfix.RiskStats{}))
+	assert.ErrorContains(t, l.CheckRisk(order("D", "1", "1"), sessionID, quickfix.RiskStats{}), "more than 2 orders per 1s")
+
+	other := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "OTHER", TargetCompID: "TARGET"}
+	assert.NoError(t, l.CheckRisk(order("D", "1", "1"), other, quickfix.RiskStats{}), "limits are per session")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.NoError(t, l.CheckRisk(order("D", "1", "1"), sessionID, quickfix.RiskStats{}))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+// maxOrdersChecker rejects orders beyond max, recording the stats it is called with.
+type maxOrdersChecker struct {
+	max   int
+	stats []RiskStats
+}
+
+func (c *maxOrdersChecker) CheckRisk(_ *Message, _ SessionID, stats RiskStats) error {
+	c.stats = append(c.stats, stats)
+	if stats.Orders >= c.max {
+		return errors.New("too many orders")
+	}
+	return nil
+}
+
+type RiskTestSuite struct {
+	SessionSuiteRig
+	checker *maxOrdersChecker
+}
+
+func TestRiskTestSuite(t *testing.T) {
+	suite.Run(t, new(RiskTestSuite))
+}
+
+func (s *RiskTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+	s.checker = &maxOrdersChecker{max: 1}
+	s.Require().Nil(RegisterRiskChecker(s.sessionID, s.checker))
+	s.MockApp.On("ToApp").Return(nil)
+}
+
+func (s *RiskTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *RiskTestSuite) order(qty, price string) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("D"))
+	msg.Body.SetField(tagOrderQty, FIXString(qty))
+	msg.Body.SetField(tagPrice, FIXString(price))
+	return msg
+}
+
+func (s *RiskTestSuite) TestRejected() {
+	s.Nil(s.Session.send(s.order("10", "-2.5")))
+	s.NextSenderMsgSeqNum(2)
+
+	stats, err := GetRiskStats(s.sessionID)
+	s.Require().Nil(err)
+	s.Equal(1, stats.Messages)
+	s.Equal(1, stats.Orders)
+	s.True(decimal.NewFromInt(25).Equal(stats.GrossNotional))
+
+	err = s.Session.send(s.order("10", "1"))
+	var rejected ErrRiskRejected
+	s.Require().True(errors.As(err, &rejected))
+	s.EqualError(rejected.Err, "too many orders")
+	s.NextSenderMsgSeqNum(2)
+	s.NoMessagePersisted(2)
+
+	s.Require().Len(s.checker.stats, 2)
+	s.Equal(0, s.checker.stats[0].Orders)
+	s.Equal(1, s.checker.stats[1].Orders)
+
+	s.Nil(ResetRiskStats(s.sessionID))
+	s.Nil(s.Session.send(s.order("10", "1")))
+	s.NextSenderMsgSeqNum(3)
+}
+
+func (s *RiskTestSuite) TestAdminMessagesNotChecked() {
+	s.checker.max = 0
+	heartbeat := NewMessage()
+	heartbeat.Header.SetField(tagMsgType, FIXString("0"))
+	s.MockApp.On("ToAdmin")
+	s.Nil(s.Session.send(heartbeat))
+	s.Empty(s.checker.stats)
+}
+
+func (s *RiskTestSuite) TestUnknownSession() {
+	other := SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}
+	s.ErrorIs(RegisterRiskChecker(other, s.checker), ErrSessionNotFound)
+	_, err := GetRiskStats(other)
+	s.ErrorIs(err, ErrSessionNotFound)
+	s.ErrorIs(ResetRiskStats(other), ErrSessionNotFound)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "sort"
+
+// Routing metadata identifies a session by what it routes to rather than by its CompIDs, e.g. a venue and an
+// account. It defaults to the labels of the session, see the SessionLabels setting, and is indexed along with the
+// registered sessions so that applications look sessions up by metadata instead of keeping a map of their own.
+
+type routeLabel struct {
+	key, value string
+}
+
+// SetRoutingMetadata replaces the routing metadata of the Session matching the Session id, which default to its labels.
+func SetRoutingMetadata(sessionID SessionID, metadata map[string]string) error {
+	return defaultEngine.SetRoutingMetadata(sessionID, metadata)
+}
+
+// SetRoutingMetadata replaces the routing metadata of the Session matching the Session id, which default to its labels.
+func (e *Engine) SetRoutingMetadata(sessionID SessionID, metadata map[string]string) error {
+	r := e.registry
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.sessions[sessionID]; !ok {
+		return ErrSessionNotFound
+	}
+	r.unindexRoute(sessionID)
+	r.indexRoute(sessionID, metadata)
+	return nil
+}
+
+// RoutingMetadata returns a copy of the routing metadata of the Session matching the Session id.
+func RoutingMetadata(sessionID SessionID) (map[string]string, error) {
+	return defaultEngine.RoutingMetadata(sessionID)
+}
+
+// RoutingMetadata returns a copy of the routing metadata of the Session matching the Session id.
+func (e *Engine) RoutingMetadata(sessionID SessionID) (map[string]string, error) {
+	r := e.registry
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if _, ok := r.sessions[sessionID]; !ok {
+		return nil, ErrSessionNotFound
+	}
+	metadata := make(map[string]string, len(r.routes[sessionID]))
+	for k, v := range r.routes[sessionID] {
+		metadata[k] = v
+	}
+	return metadata, nil
+}
+
+// FindSessionIDs returns the ids of the Sessions whose routing metadata contain every key=value pair of route,
+// sorted by their String. An empty route matches every Session.
+func FindSessionIDs(route map[string]string) []SessionID {
+	return defaultEngine.FindSessionIDs(route)
+}
+
+// FindSessionIDs returns the ids of the Sessions whose routing metadata contain every key=value pair of route,
+// sorted by their String. An empty route matches every Session.
+func (e *Engine) FindSessionIDs(route map[string]string) []SessionID {
+	r := e.registry
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var sessionIDs []SessionID
+	if len(route) == 0 {
+		for sessionID := range r.sessions {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	} else {
+		// Scan the smallest set of Sessions matching one of the pairs.
+		var candidates map[SessionID]struct{}
+		for k, v := range route {
+			matching := r.routeIndex[routeLabel{k, v}]
+			if candidates == nil || len(matching) < len(candidates) {
+				candidates = matching
+			}
+		}
+
+	candidateLoop:
+		for sessionID := range candidates {
+			for k, v := range route {
+				if value, ok := r.routes[sessionID][k]; !ok || value != v {
+					continue candidateLoop
+				}
+			}
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+
+	sort.Slice(sessionIDs, func(i, j int) bool { return sessionIDs[i].String() < sessionIDs[j].String() })
+	return sessionIDs
+}
+
+// LookupRoute returns the id of the Session whose routing metadata contain every key=value pair of route. It
+// returns ErrSessionNotFound if no Session matches and ErrAmbiguousRoute if several do.
+func LookupRoute(route map[string]string) (SessionID, error) {
+	return defaultEngine.LookupRoute(route)
+}
+
+// LookupRoute returns the id of the Session whose routing metadata contain every key=value pair of route. It
+// returns ErrSessionNotFound if no Session matches and ErrAmbiguousRoute if several do.
+func (e *Engine) LookupRoute(route map[string]string) (SessionID, error) {
+	sessionIDs := e.FindSessionIDs(route)
+	switch len(sessionIDs) {
+	case 0:
+		return SessionID{}, ErrSessionNotFound
+	case 1:
+		return sessionIDs[0], nil
+	}
+	return SessionID{}, ErrAmbiguousRoute
+}
+
+// SendToRoute sends a message to the Session returned by LookupRoute, e.g. by venue and account rather than CompIDs.
+func SendToRoute(m Messagable, route map[string]string) error {
+	return defaultEngine.SendToRoute(m, route)
+}
+
+// SendToRoute sends a message to the Session returned by LookupRoute, e.g. by venue and account rather than CompIDs.
+func (e *Engine) SendToRoute(m Messagable, route map[string]string) error {
+	sessionID, err := e.LookupRoute(route)
+	if err != nil {
+		return err
+	}
+	return e.SendToTarget(m, sessionID)
+}
+
+// indexRoute sets the routing metadata of the Session. The caller holds the lock of the registry.
+func (r *registry) indexRoute(sessionID SessionID, metadata map[string]string) {
+	copied := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+
+		label := routeLabel{k, v}
+		if r.routeIndex[label] == nil {
+			r.routeIndex[label] = make(map[SessionID]struct{})
+		}
+		r.routeIndex[label][sessionID] = struct{}{}
+	}
+	r.routes[sessionID] = copied
+}
+
+// unindexRoute removes the routing metadata of the Session. The caller holds the lock of the registry.
+func (r *registry) unindexRoute(sessionID SessionID) {
+	for k, v := range r.routes[sessionID] {
+		label := routeLabel{k, v}
+		delete(r.routeIndex[label], sessionID)
+		if len(r.routeIndex[label]) == 0 {
+			delete(r.routeIndex, label)
+		}
+	}
+	delete(r.routes, sessionID)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registerRoutedSession(t *testing.T, senderCompID string, labels map[string]string) SessionID {
+	session := &Session{sessionID: SessionID{BeginString: BeginStringFIX44, SenderCompID: senderCompID, TargetCompID: "routing"}}
+	session.SessionLabels = labels
+	require.NoError(t, registerSession(session))
+	t.Cleanup(func() { _ = UnregisterSession(session.sessionID) })
+	return session.sessionID
+}
+
+func TestLookupRoute(t *testing.T) {
+	nasdaqAcme := registerRoutedSession(t, "routing-1", map[string]string{"venue": "XNAS", "account": "acme"})
+	nasdaqInitech := registerRoutedSession(t, "routing-2", map[string]string{"venue": "XNAS", "account": "initech"})
+	nyseAcme := registerRoutedSession(t, "routing-3", map[string]string{"venue": "XNYS", "account": "acme"})
+
+	sessionID, err := LookupRoute(map[string]string{"venue": "XNAS", "account": "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, nasdaqAcme, sessionID)
+
+	assert.Equal(t, []SessionID{nasdaqAcme, nasdaqInitech}, FindSessionIDs(map[string]string{"venue": "XNAS"}))
+	assert.Equal(t, []SessionID{nasdaqAcme, nyseAcme}, FindSessionIDs(map[string]string{"account": "acme"}))
+
+	_, err = LookupRoute(map[string]string{"venue": "XNAS"})
+	assert.ErrorIs(t, err, ErrAmbiguousRoute)
+
+	_, err = LookupRoute(map[string]string{"venue": "XLON"})
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	assert.ErrorIs(t, SendToRoute(NewMessage(), map[string]string{"venue": "XLON"}), ErrSessionNotFound)
+}
+
+func TestSetRoutingMetadata(t *testing.T) {
+	labels := map[string]string{"venue": "XNAS"}
+	sessionID := registerRoutedSession(t, "routing-4", labels)
+
+	metadata, err := RoutingMetadata(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, labels, metadata)
+
+	require.NoError(t, SetRoutingMetadata(sessionID, map[string]string{"venue": "XLON", "account": "acme"}))
+	assert.Empty(t, FindSessionIDs(map[string]string{"venue": "XNAS"}))
+	found, err := LookupRoute(map[string]string{"venue": "XLON", "account": "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, found)
+	assert.Equal(t, "XNAS", labels["venue"], "the session labels are not modified")
+
+	require.NoError(t, UnregisterSession(sessionID))
+	assert.Empty(t, FindSessionIDs(map[string]string{"venue": "XLON"}))
+	assert.ErrorIs(t, SetRoutingMetadata(sessionID, nil), ErrSessionNotFound)
+	_, err = RoutingMetadata(sessionID)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
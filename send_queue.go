@@ -0,0 +1,81 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "context"
+
+// QueueDepth returns the number of messages queued for send, not yet written to the connection.
+func (s *Session) QueueDepth() int {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	return len(s.toSend)
+}
+
+// queueFull returns true if a message of msgType may not be queued as MaxQueuedMessages are already queued. Admin
+// messages are always queued for the Session to log on. sendMutex must be held.
+func (s *Session) queueFull(msgType []byte) bool {
+	if s.MaxQueuedMessages <= 0 || len(s.toSend) < s.MaxQueuedMessages {
+		return false
+	}
+	return !isAdminMessageType(msgType)
+}
+
+// queueSpaceLocked returns a channel closed once messages are removed from the send queue. sendMutex must be held.
+func (s *Session) queueSpaceLocked() <-chan struct{} {
+	if s.queueSpace == nil {
+		s.queueSpace = make(chan struct{})
+	}
+	return s.queueSpace
+}
+
+// signalQueueSpace wakes up the senders waiting for the send queue to have room. sendMutex must be held.
+func (s *Session) signalQueueSpace() {
+	if s.queueSpace != nil {
+		close(s.queueSpace)
+		s.queueSpace = nil
+	}
+}
+
+// queueForSendContext is queueForSend waiting for the send queue to have room rather than returning ErrQueueFull,
+// until ctx is done.
+func (s *Session) queueForSendContext(ctx context.Context, msg *Message) error {
+	if err := s.lockQueueRoom(ctx, msg); err != nil {
+		return err
+	}
+	defer s.sendMutex.Unlock()
+
+	return s.queueForSendLocked(msg)
+}
+
+// lockQueueRoom waits for the send queue to have room for msg and locks sendMutex, unless ctx is done first.
+func (s *Session) lockQueueRoom(ctx context.Context, msg *Message) error {
+	msgType, _ := msg.Header.GetBytes(tagMsgType)
+	for {
+		s.sendMutex.Lock()
+		if !s.queueFull(msgType) {
+			return nil
+		}
+		space := s.queueSpaceLocked()
+		s.sendMutex.Unlock()
+
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
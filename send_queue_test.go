@@ -0,0 +1,125 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SendQueueTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestSendQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(SendQueueTestSuite))
+}
+
+func (s *SendQueueTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.MaxQueuedMessages = 2
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *SendQueueTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *SendQueueTestSuite) order(clOrdID string) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagBeginString, FIXString(s.sessionID.BeginString))
+	msg.Header.SetField(tagSenderCompID, FIXString(s.sessionID.SenderCompID))
+	msg.Header.SetField(tagTargetCompID, FIXString(s.sessionID.TargetCompID))
+	msg.Header.SetField(tagMsgType, FIXString("D"))
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	return msg
+}
+
+func (s *SendQueueTestSuite) TestQueueFull() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(Send(s.order("order1")))
+	s.Require().Nil(Send(s.order("order2")))
+	s.Equal(ErrQueueFull, Send(s.order("order3")))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 2)
+	s.NextSenderMsgSeqNum(3)
+
+	// Admin messages are queued regardless.
+	s.Require().Nil(s.Session.queueForSend(s.Heartbeat()))
+	s.Equal(3, s.Session.QueueDepth())
+	s.NextSenderMsgSeqNum(4)
+
+	s.Session.sendMutex.Lock()
+	s.Session.dropQueued()
+	s.Session.sendMutex.Unlock()
+	s.Equal(0, s.Session.QueueDepth())
+	s.Nil(Send(s.order("order3")))
+}
+
+func (s *SendQueueTestSuite) TestQueueUnbounded() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.Session.MaxQueuedMessages = 0
+	for i := 0; i < 5; i++ {
+		s.Require().Nil(Send(s.order("order")))
+	}
+	s.Equal(5, s.Session.QueueDepth())
+}
+
+func (s *SendQueueTestSuite) TestSendWithContextTimeout() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(Send(s.order("order1")))
+	s.Require().Nil(Send(s.order("order2")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	s.Equal(context.DeadlineExceeded, SendWithContext(ctx, s.order("order3")))
+	s.Equal(2, s.Session.QueueDepth())
+	s.NextSenderMsgSeqNum(3)
+}
+
+func (s *SendQueueTestSuite) TestSendWithContextWaitsForRoom() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(Send(s.order("order1")))
+	s.Require().Nil(Send(s.order("order2")))
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- SendWithContext(context.Background(), s.order("order3"))
+	}()
+
+	select {
+	case err := <-sent:
+		s.FailNow("sent to a full queue", "%v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.Session.sendMutex.Lock()
+	s.Session.dropQueued()
+	s.Session.sendMutex.Unlock()
+
+	select {
+	case err := <-sent:
+		s.Nil(err)
+	case <-time.After(time.Second):
+		s.FailNow("SendWithContext did not return once the queue had room")
+	}
+	s.Equal(1, s.Session.QueueDepth())
+	s.NextSenderMsgSeqNum(4)
+}
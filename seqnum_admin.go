@@ -0,0 +1,72 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "fmt"
+
+// ErrInvalidMsgSeqNum is returned when setting a sequence number lower than 1.
+type ErrInvalidMsgSeqNum struct {
+	SeqNum int
+}
+
+func (e ErrInvalidMsgSeqNum) Error() string {
+	return fmt.Sprintf("invalid MsgSeqNum %d, must be at least 1", e.SeqNum)
+}
+
+// SetNextSenderMsgSeqNum sets, and persists in the MessageStore, the MsgSeqNum of the next message sent, to repair a
+// sequence desync with the counterparty without resetting the store. Messages already queued keep their MsgSeqNum.
+//
+// It is safe to call from any goroutine but the Application callbacks, and waits for a resend in progress to complete.
+func (s *Session) SetNextSenderMsgSeqNum(seqNum int) error {
+	if seqNum < 1 {
+		return ErrInvalidMsgSeqNum{SeqNum: seqNum}
+	}
+
+	// resendMutex must always be locked before sendMutex to prevent a potential deadlock
+	s.resendMutex.Lock()
+	defer s.resendMutex.Unlock()
+
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	prev := s.store.NextSenderMsgSeqNum()
+	if err := s.store.SetNextSenderMsgSeqNum(seqNum); err != nil {
+		return err
+	}
+	s.log.OnEventf("Next sender MsgSeqNum set from %d to %d", prev, seqNum)
+	return nil
+}
+
+// SetNextTargetMsgSeqNum sets, and persists in the MessageStore, the MsgSeqNum expected on the next message received,
+// to repair a sequence desync with the counterparty without resetting the store.
+//
+// It is safe to call from any goroutine but the Application callbacks, and waits for the inbound messages being
+// processed.
+func (s *Session) SetNextTargetMsgSeqNum(seqNum int) error {
+	if seqNum < 1 {
+		return ErrInvalidMsgSeqNum{SeqNum: seqNum}
+	}
+
+	s.targetMutex.Lock()
+	defer s.targetMutex.Unlock()
+
+	prev := s.store.NextTargetMsgSeqNum()
+	if err := s.store.SetNextTargetMsgSeqNum(seqNum); err != nil {
+		return err
+	}
+	s.log.OnEventf("Next target MsgSeqNum set from %d to %d", prev, seqNum)
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SeqNumAdminTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestSeqNumAdminTestSuite(t *testing.T) {
+	suite.Run(t, new(SeqNumAdminTestSuite))
+}
+
+func (s *SeqNumAdminTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *SeqNumAdminTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *SeqNumAdminTestSuite) TestSetNextSenderMsgSeqNum() {
+	s.Require().Nil(s.Session.SetNextSenderMsgSeqNum(20))
+	s.NextSenderMsgSeqNum(20)
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	seqNum, err := s.MockApp.lastToApp.Header.GetInt(tagMsgSeqNum)
+	s.Require().Nil(err)
+	s.Equal(20, seqNum)
+	s.NextSenderMsgSeqNum(21)
+}
+
+func (s *SeqNumAdminTestSuite) TestSetNextTargetMsgSeqNum() {
+	s.Require().Nil(s.Session.SetNextTargetMsgSeqNum(5))
+	s.NextTargetMsgSeqNum(5)
+
+	s.MockApp.On("FromApp").Return(nil)
+	s.SetNextSeqNum(5)
+	s.Session.fixMsgIn(s.Session, s.NewOrderSingle())
+	s.MockApp.AssertExpectations(s.T())
+	s.NextTargetMsgSeqNum(6)
+}
+
+func (s *SeqNumAdminTestSuite) TestInvalidMsgSeqNum() {
+	s.Equal(ErrInvalidMsgSeqNum{SeqNum: 0}, s.Session.SetNextSenderMsgSeqNum(0))
+	s.Equal(ErrInvalidMsgSeqNum{SeqNum: -1}, s.Session.SetNextTargetMsgSeqNum(-1))
+	s.NextSenderMsgSeqNum(1)
+	s.NextTargetMsgSeqNum(1)
+}
+
+func (s *SeqNumAdminTestSuite) TestRegistry() {
+	s.Require().Nil(SetNextSenderMsgSeqNum(s.sessionID, 7))
+	s.Require().Nil(SetNextTargetMsgSeqNum(s.sessionID, 9))
+	s.NextSenderMsgSeqNum(7)
+	s.NextTargetMsgSeqNum(9)
+
+	s.Equal(ErrSessionNotFound, SetNextSenderMsgSeqNum(SessionID{BeginString: "FIX.4.2", SenderCompID: "unknown"}, 1))
+}
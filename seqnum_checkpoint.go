@@ -0,0 +1,88 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "time"
+
+// SeqNumDriftHandler is an optional interface implemented by an Application to be alerted when the counterparty's
+// LastMsgSeqNumProcessed disagrees with the messages sent, see SeqNumCheckpointInterval.
+type SeqNumDriftHandler interface {
+	// OnSeqNumDrift is called when the drift first exceeds SeqNumDriftThreshold, or the counterparty claims to have
+	// processed messages that were never sent.
+	OnSeqNumDrift(lastSent, peerLastProcessed int, sessionID SessionID)
+}
+
+// seqNumCheckpoint is the state of sequence number checkpointing, see SeqNumCheckpointInterval.
+type seqNumCheckpoint struct {
+	next     time.Time
+	drifting bool
+}
+
+// checkSeqNumCheckpoint sends a Heartbeat carrying LastMsgSeqNumProcessed once the checkpoint interval has elapsed.
+func (s *Session) checkSeqNumCheckpoint(now time.Time) {
+	if s.SeqNumCheckpointInterval <= 0 || !s.IsLoggedOn() {
+		return
+	}
+
+	if now.Before(s.seqNumCheckpoint.next) {
+		return
+	}
+	s.seqNumCheckpoint.next = now.Add(s.SeqNumCheckpointInterval)
+
+	heartBt := NewMessage()
+	heartBt.Header.SetField(tagMsgType, FIXString("0"))
+	heartBt.Header.SetInt(tagLastMsgSeqNumProcessed, s.store.NextTargetMsgSeqNum()-1)
+	if err := s.send(heartBt); err != nil {
+		s.logError(err)
+	}
+}
+
+// checkSeqNumDrift compares the LastMsgSeqNumProcessed of a received message with the last message sent.
+// Resent messages are skipped as their LastMsgSeqNumProcessed is stale.
+func (s *Session) checkSeqNumDrift(msg *Message) {
+	if !msg.Header.Has(tagLastMsgSeqNumProcessed) {
+		return
+	}
+
+	if msg.Header.Has(tagPossDupFlag) {
+		var possDup FIXBoolean
+		if err := msg.Header.GetField(tagPossDupFlag, &possDup); err != nil || possDup.Bool() {
+			return
+		}
+	}
+
+	peerLastProcessed, err := msg.Header.GetInt(tagLastMsgSeqNumProcessed)
+	if err != nil {
+		return
+	}
+
+	lastSent := s.store.NextSenderMsgSeqNum() - 1
+	drifting := peerLastProcessed > lastSent || lastSent-peerLastProcessed > s.SeqNumDriftThreshold
+	if drifting == s.seqNumCheckpoint.drifting {
+		return
+	}
+	s.seqNumCheckpoint.drifting = drifting
+
+	if !drifting {
+		s.log.OnEventf("Sequence numbers back in agreement, last sent %v, counterparty last processed %v", lastSent, peerLastProcessed)
+		return
+	}
+
+	s.log.OnEventf("Sequence number drift detected, last sent %v, counterparty last processed %v", lastSent, peerLastProcessed)
+	if handler, ok := s.application.(SeqNumDriftHandler); ok {
+		handler.OnSeqNumDrift(lastSent, peerLastProcessed, s.sessionID)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SeqNumCheckpointTestSuite struct {
+	SessionSuiteRig
+	drifts [][2]int
+}
+
+func TestSeqNumCheckpointTestSuite(t *testing.T) {
+	suite.Run(t, new(SeqNumCheckpointTestSuite))
+}
+
+type seqNumDriftApp struct {
+	*MockApp
+	suite *SeqNumCheckpointTestSuite
+}
+
+func (a *seqNumDriftApp) OnSeqNumDrift(lastSent, peerLastProcessed int, _ SessionID) {
+	a.suite.drifts = append(a.suite.drifts, [2]int{lastSent, peerLastProcessed})
+}
+
+func (s *SeqNumCheckpointTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.SeqNumCheckpointInterval = time.Minute
+	s.Session.SeqNumDriftThreshold = 2
+	s.Session.application = &seqNumDriftApp{MockApp: &s.MockApp, suite: s}
+	s.drifts = nil
+}
+
+func (s *SeqNumCheckpointTestSuite) heartbeat(peerLastProcessed int) *Message {
+	msg := s.MessageFactory.buildMessage(string(msgTypeHeartbeat))
+	msg.Header.SetInt(tagLastMsgSeqNumProcessed, peerLastProcessed)
+	return msg
+}
+
+func (s *SeqNumCheckpointTestSuite) TestCheckpointSent() {
+	s.MockApp.On("ToAdmin")
+	s.IncrNextTargetMsgSeqNum()
+	now := time.Now()
+
+	s.Session.checkSeqNumCheckpoint(now)
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 1)
+	s.MessageType(string(msgTypeHeartbeat), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagLastMsgSeqNumProcessed, 1, s.MockApp.lastToAdmin.Header)
+
+	s.Session.checkSeqNumCheckpoint(now.Add(time.Second))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 1)
+
+	s.Session.checkSeqNumCheckpoint(now.Add(time.Minute))
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 2)
+}
+
+func (s *SeqNumCheckpointTestSuite) TestCheckpointDisabled() {
+	s.Session.SeqNumCheckpointInterval = 0
+	s.Session.checkSeqNumCheckpoint(time.Now())
+	s.NoMessageSent()
+}
+
+func (s *SeqNumCheckpointTestSuite) TestDrift() {
+	s.MockApp.On("FromAdmin").Return(nil)
+	for i := 0; i < 5; i++ {
+		s.IncrNextSenderMsgSeqNum()
+	}
+
+	s.fixMsgIn(s.Session, s.heartbeat(3))
+	s.Empty(s.drifts, "within threshold")
+
+	s.fixMsgIn(s.Session, s.heartbeat(2))
+	s.Equal([][2]int{{5, 2}}, s.drifts)
+
+	s.fixMsgIn(s.Session, s.heartbeat(1))
+	s.Len(s.drifts, 1, "alerted once while drifting")
+
+	s.fixMsgIn(s.Session, s.heartbeat(5))
+	s.fixMsgIn(s.Session, s.heartbeat(6))
+	s.Equal([][2]int{{5, 2}, {5, 6}}, s.drifts)
+}
+
+func (s *SeqNumCheckpointTestSuite) TestDriftIgnoresPossDup() {
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.IncrNextSenderMsgSeqNum()
+
+	msg := s.heartbeat(5)
+	msg.Header.SetField(tagPossDupFlag, FIXBoolean(true))
+	msg.Header.SetField(tagOrigSendingTime, FIXUTCTimestamp{Time: time.Now()})
+	s.fixMsgIn(s.Session, msg)
+	s.Empty(s.drifts)
+}
@@ -0,0 +1,143 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SeqNumPosition is the sequence number state of a session published by a SeqNumPublisher.
+type SeqNumPosition struct {
+	SessionID           SessionID
+	NextSenderMsgSeqNum int
+	NextTargetMsgSeqNum int
+
+	// Time the position was taken at, from the Clock of the session.
+	Time time.Time
+}
+
+// SeqNumPublisher publishes the sequence numbers of the sessions to an external coordination service, such as etcd
+// or Consul, so that failover tooling can decide which instance to promote. See WithSeqNumPublisher,
+// SeqNumPublishInterval and SeqNumPublishMessageCount.
+//
+// PublishSeqNumPosition is called from a goroutine of the session, one call at a time. Positions taken while a call
+// is in progress are coalesced, only the latest is published next. ctx is canceled once the session stops.
+type SeqNumPublisher interface {
+	PublishSeqNumPosition(ctx context.Context, pos SeqNumPosition) error
+}
+
+// WithSeqNumPublisher sets the SeqNumPublisher of the sessions of the engine. Sessions publish their position only
+// when SeqNumPublishInterval or SeqNumPublishMessageCount is set.
+func WithSeqNumPublisher(publisher SeqNumPublisher) EngineOption {
+	return func(o *engineOptions) { o.seqNumPublisher = publisher }
+}
+
+// seqNumPublishing is the state of the sequence number publishing of a session.
+type seqNumPublishing struct {
+	publisher SeqNumPublisher
+	interval  time.Duration
+	count     int
+
+	// latest holds the position waiting to be published.
+	latest chan SeqNumPosition
+
+	mu             sync.Mutex
+	next           time.Time
+	sender, target int
+}
+
+func newSeqNumPublishing(publisher SeqNumPublisher, interval time.Duration, count int) *seqNumPublishing {
+	if publisher == nil || (interval <= 0 && count <= 0) {
+		return nil
+	}
+	return &seqNumPublishing{
+		publisher: publisher,
+		interval:  interval,
+		count:     count,
+		latest:    make(chan SeqNumPosition, 1),
+	}
+}
+
+// checkSeqNumPublish takes the position of the session once SeqNumPublishInterval has elapsed or
+// SeqNumPublishMessageCount messages were sent and received since the last position. A sequence number reset is
+// taken immediately, as are all positions when force is set.
+func (s *Session) checkSeqNumPublish(now time.Time, force bool) {
+	p := s.seqNumPublishing
+	if p == nil {
+		return
+	}
+
+	sender, target := s.store.NextSenderMsgSeqNum(), s.store.NextTargetMsgSeqNum()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !force {
+		due := sender < p.sender || target < p.target ||
+			(p.interval > 0 && !now.Before(p.next)) ||
+			(p.count > 0 && (sender-p.sender)+(target-p.target) >= p.count)
+		if !due {
+			return
+		}
+	}
+	p.sender, p.target = sender, target
+	p.next = now.Add(p.interval)
+
+	p.offer(SeqNumPosition{SessionID: s.sessionID, NextSenderMsgSeqNum: sender, NextTargetMsgSeqNum: target, Time: now})
+}
+
+// offer replaces the position waiting to be published with pos. Callers hold mu.
+func (p *seqNumPublishing) offer(pos SeqNumPosition) {
+	select {
+	case <-p.latest:
+	default:
+	}
+	p.latest <- pos
+}
+
+// run publishes the positions taken by the session until stopChan is closed, then publishes the last position
+// waiting, if any.
+func (p *seqNumPublishing) run(s *Session, stopChan <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopChan
+		cancel()
+	}()
+
+	publish := func(pos SeqNumPosition) {
+		if err := p.publisher.PublishSeqNumPosition(ctx, pos); err != nil {
+			s.log.OnEventf("Failed to publish sequence numbers %v/%v: %v", pos.NextSenderMsgSeqNum, pos.NextTargetMsgSeqNum, err)
+		}
+	}
+
+	for {
+		select {
+		case pos := <-p.latest:
+			publish(pos)
+		case <-stopChan:
+			select {
+			case pos := <-p.latest:
+				// The final position is published with a context of its own, the session being stopped.
+				ctx = context.Background()
+				publish(pos)
+			default:
+			}
+			return
+		}
+	}
+}
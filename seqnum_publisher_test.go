@@ -0,0 +1,195 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type recordingSeqNumPublisher struct {
+	mu        sync.Mutex
+	positions []SeqNumPosition
+	err       error
+	published chan struct{}
+}
+
+func (p *recordingSeqNumPublisher) PublishSeqNumPosition(_ context.Context, pos SeqNumPosition) error {
+	p.mu.Lock()
+	p.positions = append(p.positions, pos)
+	p.mu.Unlock()
+	if p.published != nil {
+		p.published <- struct{}{}
+	}
+	return p.err
+}
+
+type SeqNumPublisherTestSuite struct {
+	SessionSuiteRig
+	publisher *recordingSeqNumPublisher
+}
+
+func TestSeqNumPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(SeqNumPublisherTestSuite))
+}
+
+func (s *SeqNumPublisherTestSuite) SetupTest() {
+	s.Init()
+	s.publisher = &recordingSeqNumPublisher{}
+}
+
+// taken returns the position waiting to be published, if any.
+func (s *SeqNumPublisherTestSuite) taken() (SeqNumPosition, bool) {
+	select {
+	case pos := <-s.Session.seqNumPublishing.latest:
+		return pos, true
+	default:
+		return SeqNumPosition{}, false
+	}
+}
+
+func (s *SeqNumPublisherTestSuite) TestDisabled() {
+	s.Nil(newSeqNumPublishing(nil, time.Second, 10))
+	s.Nil(newSeqNumPublishing(s.publisher, 0, 0))
+
+	s.Session.checkSeqNumPublish(time.Now(), true)
+}
+
+func (s *SeqNumPublisherTestSuite) TestMessageCount() {
+	s.Session.seqNumPublishing = newSeqNumPublishing(s.publisher, 0, 3)
+	now := time.Now()
+	s.Session.checkSeqNumPublish(now, true)
+	_, _ = s.taken()
+
+	s.IncrNextSenderMsgSeqNum()
+	s.IncrNextTargetMsgSeqNum()
+	s.Session.checkSeqNumPublish(now, false)
+	_, ok := s.taken()
+	s.False(ok, "2 messages since the last position")
+
+	s.IncrNextTargetMsgSeqNum()
+	s.Session.checkSeqNumPublish(now, false)
+	pos, ok := s.taken()
+	s.Require().True(ok)
+	s.Equal(SeqNumPosition{SessionID: s.sessionID, NextSenderMsgSeqNum: 2, NextTargetMsgSeqNum: 3, Time: now}, pos)
+
+	s.IncrNextSenderMsgSeqNum()
+	s.Session.checkSeqNumPublish(now.Add(time.Hour), false)
+	_, ok = s.taken()
+	s.False(ok, "counted from the last position")
+}
+
+func (s *SeqNumPublisherTestSuite) TestInterval() {
+	s.Session.seqNumPublishing = newSeqNumPublishing(s.publisher, time.Minute, 0)
+	now := time.Now()
+
+	s.Session.checkSeqNumPublish(now, true)
+	_, ok := s.taken()
+	s.True(ok, "forced")
+
+	s.IncrNextSenderMsgSeqNum()
+	s.Session.checkSeqNumPublish(now.Add(time.Second), false)
+	_, ok = s.taken()
+	s.False(ok)
+
+	s.Session.checkSeqNumPublish(now.Add(time.Minute), false)
+	pos, ok := s.taken()
+	s.Require().True(ok)
+	s.Equal(2, pos.NextSenderMsgSeqNum)
+}
+
+func (s *SeqNumPublisherTestSuite) TestReset() {
+	s.Session.seqNumPublishing = newSeqNumPublishing(s.publisher, time.Minute, 100)
+	now := time.Now()
+
+	s.IncrNextSenderMsgSeqNum()
+	s.IncrNextTargetMsgSeqNum()
+	s.Session.checkSeqNumPublish(now, true)
+	_, _ = s.taken()
+
+	s.Require().Nil(s.MockStore.Reset())
+	s.Session.checkSeqNumPublish(now, false)
+	pos, ok := s.taken()
+	s.Require().True(ok, "reset published immediately")
+	s.Equal(1, pos.NextSenderMsgSeqNum)
+	s.Equal(1, pos.NextTargetMsgSeqNum)
+}
+
+func (s *SeqNumPublisherTestSuite) TestCoalesced() {
+	s.Session.seqNumPublishing = newSeqNumPublishing(s.publisher, 0, 1)
+	now := time.Now()
+	s.Session.checkSeqNumPublish(now, true)
+
+	s.IncrNextSenderMsgSeqNum()
+	s.Session.checkSeqNumPublish(now, false)
+	s.IncrNextSenderMsgSeqNum()
+	s.Session.checkSeqNumPublish(now, false)
+
+	pos, ok := s.taken()
+	s.Require().True(ok)
+	s.Equal(3, pos.NextSenderMsgSeqNum, "only the latest position is kept")
+	_, ok = s.taken()
+	s.False(ok)
+}
+
+func (s *SeqNumPublisherTestSuite) TestRun() {
+	s.publisher.published = make(chan struct{}, 10)
+	s.publisher.err = errors.New("unavailable")
+	s.Session.seqNumPublishing = newSeqNumPublishing(s.publisher, 0, 1)
+	stopChan := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		s.Session.seqNumPublishing.run(s.Session, stopChan)
+		close(done)
+	}()
+
+	s.IncrNextSenderMsgSeqNum()
+	s.Session.checkSeqNumPublish(time.Now(), false)
+	select {
+	case <-s.publisher.published:
+	case <-time.After(time.Second):
+		s.FailNow("position not published")
+	}
+
+	close(stopChan)
+	<-done
+
+	s.publisher.mu.Lock()
+	defer s.publisher.mu.Unlock()
+	s.Require().Len(s.publisher.positions, 1)
+	s.Equal(2, s.publisher.positions[0].NextSenderMsgSeqNum)
+}
+
+func (s *SeqNumPublisherTestSuite) TestPublishedOnStop() {
+	s.Session.seqNumPublishing = newSeqNumPublishing(s.publisher, 0, 1)
+	s.IncrNextTargetMsgSeqNum()
+	s.Session.checkSeqNumPublish(time.Now(), false)
+
+	stopChan := make(chan struct{})
+	close(stopChan)
+	s.Session.seqNumPublishing.run(s.Session, stopChan)
+
+	s.publisher.mu.Lock()
+	defer s.publisher.mu.Unlock()
+	// The position may be published from either case of the select.
+	s.Require().Len(s.publisher.positions, 1)
+	s.Equal(2, s.publisher.positions[0].NextTargetMsgSeqNum)
+}
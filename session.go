@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/quickfixgo/quickfix/datadictionary"
@@ -34,16 +35,25 @@ type Session struct {
 	sessionID SessionID
 
 	messageOut chan<- []byte
-	messageIn  <-chan fixIn
+	messageIn  <-chan []fixIn
+
+	// Messages of the batch being processed, see InBatchSize.
+	pendingIn []fixIn
 
 	// Application messages are queued up for send here.
 	toSend [][]byte
 
 	// Mutex for access to toSend.
 	sendMutex sync.Mutex
+	// Closed once messages are removed from toSend, see MaxQueuedMessages.
+	queueSpace chan struct{}
+	// Messages awaited by SendAndWait.
+	deliveries deliveries
 	// Mutex to prevent messages being sent when resendRequest is active
 	// Must be locked before sendMutex to prevent a potential deadlock
 	resendMutex sync.RWMutex
+	// Mutex held while a batch of inbound messages is processed, serializing SetNextTargetMsgSeqNum with it.
+	targetMutex sync.Mutex
 
 	sessionEvent chan internal.Event
 	messageEvent chan bool
@@ -55,6 +65,10 @@ type Session struct {
 	sentReset  bool
 	stopOnce   sync.Once
 
+	// Number of messages sent, and that number plus one when the stateTimer fired, see heartbeatSuppressed.
+	sentCount        atomic.Uint64
+	heartbeatTimeout atomic.Uint64
+
 	targetDefaultApplVerID string
 
 	admin chan interface{}
@@ -62,10 +76,145 @@ type Session struct {
 	transportDataDictionary *datadictionary.DataDictionary
 	appDataDictionary       *datadictionary.DataDictionary
 
+	// The application data dictionaries and validators by ApplVerID, nil unless AppDataDictionary is set for several
+	// ApplVerIDs of a FIXT session.
+	applVerDataDictionaries map[string]*datadictionary.DataDictionary
+	applVerValidators       map[string]Validator
+
+	// Validates outgoing application messages, nil unless OutboundDataDictionary is set.
+	outboundValidator      Validator
+	outboundDataDictionary *datadictionary.DataDictionary
+
 	timestampPrecision      TimestampPrecision
+	sendingTimes            timestampCache
+	origSendingTimeCheck    origSendingTimeCheck
+	beginStringMismatch     beginStringMismatchPolicy
 	lastCheckedResetSeqTime time.Time
+
+	// Ownership epoch of the session, see ResumptionToken.
+	epoch uint64
+
+	// Ranges requested with a ResendRequest that have not been received yet.
+	resendRanges resendRanges
+
+	// Outbound order messages awaiting a response, see AckTimeout.
+	acks ackTracker
+
+	// Sequence numbers of the messages canceled with CancelPending, gap filled when resent.
+	canceled canceledMessages
+
+	// Rate limit of the outbound application messages, nil unless MaxMessagesPerSecond is set.
+	throttle *throttle
+
+	// Recent raw messages for diagnostic bundles, nil unless CrashDumpPath is set.
+	crashDump *crashDump
+
+	// Consecutive logon rejections of an initiator, see MaxLogonRejectRetries.
+	logonRejects logonRejects
+
+	// Reconnections of an initiator since it last logged on, see ReconnectBackoffBase.
+	reconnectAttempts reconnectAttempts
+
+	// Sequence number checkpointing state, see SeqNumCheckpointInterval.
+	seqNumCheckpoint seqNumCheckpoint
+
+	// Publishes the sequence numbers to a coordination service, nil unless a SeqNumPublisher is set, see
+	// WithSeqNumPublisher.
+	seqNumPublishing *seqNumPublishing
+
+	// Application state replayed after logon, see RegisterSnapshotProvider.
+	snapshots snapshotReplays
+
+	// Outbound messages awaiting approval, see ApprovalMsgTypes.
+	held heldMessages
+
+	// Pre-trade checks of outbound application messages, see RegisterRiskChecker.
+	risk riskChecks
+
+	// Business checks of inbound application messages, see RegisterInboundInterceptor.
+	interceptors inboundInterceptors
+
+	// Per minute counters, see StatsHistoryMinutes.
+	stats statsHistory
+
+	// KillSwitches tracking the disconnects and responses of the session, see NewKillSwitch.
+	killSwitches sessionKillSwitches
+
+	// TIFWatchdogs handling the resting day orders of the session, see NewTIFWatchdog.
+	tifWatchdogs sessionTIFWatchdogs
+
+	// Copies of application messages for surveillance, see NewMirror.
+	mirrors sessionMirrors
+
+	// Listeners of the state transitions of the session, see RegisterSessionStateListener.
+	stateListeners sessionStateListeners
+
+	// Notifiers posting the events of the session, see NewWebhookNotifier.
+	webhooks sessionWebhooks
+
+	// Post-logon handshake, see SetHandshakePolicy.
+	handshake handshake
+
+	// Egress bandwidth shared with other sessions, see EgressScheduler.
+	egress sessionEgress
+
+	// Fields stamped on outbound messages, see OutboundHeaderFields.
+	stamps fieldStamps
+
+	// Data fields compressed on the wire, see CompressedDataFields.
+	compression *dataCompression
+
+	// Fields encrypted on the wire, see EncryptedFields.
+	fieldEncryption *fieldEncryption
+
+	// Decodes the inbound messages, see RawMessageDecoder.
+	rawDecoder RawMessageDecoder
+
+	// When the outstanding TestRequest was sent, for the heartbeat latency metric and LastHeartbeatRTT.
+	testRequestSent time.Time
+
+	// When the next TestRequest is sent, see TestRequestInterval.
+	nextTestRequestProbe time.Time
+
+	// Overrides the MetricsCollector set with SetMetricsCollector, see WithMetrics.
+	metricsCollector MetricsCollector
+
+	// Source of the current time, see WithClock.
+	clock Clock
+
+	// Starts spans around ContextApplication callbacks, see WithCallbackTracer.
+	tracer CallbackTracer
+
+	// Inbound application messages rejected by FromApp, nil unless DeadLetterQueue is set.
+	deadLetters DeadLetterStore
+
+	// Days the session stays down, nil unless WithHolidayCalendar is set.
+	holidays HolidayCalendar
+
+	// Inputs of the session captured for Replay, nil unless WithRecorder is set.
+	recorder         Recorder
+	recordedSettings map[string]string
+
+	// Connectivity reported by HealthCheck.
+	health sessionHealth
+
+	// Audit hash chain of the inbound messages, nil unless AuditChain is set.
+	auditStore    AuditStore
+	lastAuditHash string
 }
 
+// origSendingTimeCheck controls the validation of OrigSendingTime on messages received with PossDupFlag=Y.
+type origSendingTimeCheck int
+
+const (
+	// origSendingTimeTooLow, the default, validates OrigSendingTime only on the PossDup messages received with a
+	// MsgSeqNum lower than expected, rejecting them as REJECT does, and processes the others as they come.
+	origSendingTimeTooLow origSendingTimeCheck = iota
+	origSendingTimeReject
+	origSendingTimeWarn
+	origSendingTimeAccept
+)
+
 func (s *Session) logError(err error) {
 	s.log.OnEvent(err.Error())
 }
@@ -78,11 +227,11 @@ func (s *Session) TargetDefaultApplicationVersionID() string {
 
 type connect struct {
 	messageOut chan<- []byte
-	messageIn  <-chan fixIn
+	messageIn  <-chan []fixIn
 	err        chan<- error
 }
 
-func (s *Session) connect(msgIn <-chan fixIn, msgOut chan<- []byte) error {
+func (s *Session) connect(msgIn <-chan []fixIn, msgOut chan<- []byte) error {
 	rep := make(chan error)
 	s.admin <- connect{
 		messageOut: msgOut,
@@ -102,6 +251,11 @@ func (s *Session) stop() {
 	})
 }
 
+// close closes the MessageStore and the Log of a session torn down.
+func (s *Session) close() error {
+	return errors.Join(s.store.Close(), closeLog(s.log))
+}
+
 type waitChan <-chan interface{}
 
 type waitForInSessionReq struct{ rep chan<- waitChan }
@@ -115,13 +269,13 @@ func (s *Session) waitForInSessionTime() {
 }
 
 func (s *Session) insertSendingTime(msg *Message) {
-	sendingTime := time.Now().UTC()
-
+	precision := Seconds
 	if s.sessionID.BeginString >= BeginStringFIX42 {
-		msg.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: sendingTime, Precision: s.timestampPrecision})
-	} else {
-		msg.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: sendingTime, Precision: Seconds})
+		precision = s.timestampPrecision
 	}
+
+	var buf [len(utcTimestampNanosFormat)]byte
+	msg.Header.SetBytes(tagSendingTime, s.sendingTimes.append(buf[:0], s.now(), precision))
 }
 
 func optionallySetID(msg *Message, field Tag, value string) {
@@ -153,6 +307,8 @@ func (s *Session) fillDefaultHeader(msg *Message, inReplyTo *Message) {
 			msg.Header.SetInt(tagLastMsgSeqNumProcessed, s.store.NextTargetMsgSeqNum()-1)
 		}
 	}
+
+	s.stamps.apply(msg, s.sessionID)
 }
 
 func (s *Session) shouldSendReset() bool {
@@ -278,6 +434,17 @@ func (s *Session) queueForSend(msg *Message) error {
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
+	msgType, _ := msg.Header.GetBytes(tagMsgType)
+	if s.DropCopy && !isAdminMessageType(msgType) {
+		return ErrDropCopy
+	}
+	if s.queueFull(msgType) {
+		return ErrQueueFull
+	}
+	return s.queueForSendLocked(msg)
+}
+
+func (s *Session) queueForSendLocked(msg *Message) error {
 	msgBytes, err := s.prepMessageForSend(msg, nil)
 	if err != nil {
 		return err
@@ -290,6 +457,21 @@ func (s *Session) queueForSend(msg *Message) error {
 	return nil
 }
 
+// checkCanSend returns an error if the application can currently not send messages. It is safe to call from any
+// goroutine. Messages sent within session time while the Session is logged off are queued until logon.
+func (s *Session) checkCanSend() error {
+	switch s.sendStatus.Load() {
+	case sendStatusDraining:
+		return ErrSessionDraining
+	case sendStatusNotSessionTime:
+		return ErrNotSessionTime
+	}
+	if s.handshake.pending.Load() {
+		return ErrHandshakePending
+	}
+	return nil
+}
+
 func (s *Session) notifyMessageOut() {
 	select {
 	case s.messageEvent <- true:
@@ -326,11 +508,26 @@ func (s *Session) sendInReplyTo(msg *Message, inReplyTo *Message) error {
 
 // dropAndReset will drop the send queue and reset the message store.
 func (s *Session) dropAndReset() error {
+	s.resendRanges.clear()
+	s.acks.clear()
+	s.canceled.clear()
+
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
 	s.dropQueued()
-	return s.store.Reset()
+	return s.resetStore()
+}
+
+// resetStore resets the message store, setting the sequence numbers back to 1.
+func (s *Session) resetStore() error {
+	// The sequence numbers awaited by SendAndWait are reused after the reset.
+	s.deliveries.fail(ErrNotDelivered, true)
+	if err := s.store.Reset(); err != nil {
+		return err
+	}
+	s.notifyWebhooks(WebhookSeqNumReset, "")
+	return nil
 }
 
 // dropAndSend will validate and persist the message, then drops the send queue and sends the message.
@@ -356,10 +553,11 @@ func (s *Session) dropAndSendInReplyTo(msg *Message, inReplyTo *Message) error {
 func (s *Session) prepMessageForSend(msg *Message, inReplyTo *Message) (msgBytes []byte, err error) {
 	s.fillDefaultHeader(msg, inReplyTo)
 	seqNum := s.store.NextSenderMsgSeqNum()
-	msg.Header.SetField(tagMsgSeqNum, FIXInt(seqNum))
+	msg.Header.SetInt(tagMsgSeqNum, seqNum)
 
 	msgType, err := msg.Header.GetBytes(tagMsgType)
 	if err != nil {
+		err = ErrValidation{Details: "missing MsgType", Err: err}
 		return
 	}
 
@@ -374,24 +572,62 @@ func (s *Session) prepMessageForSend(msg *Message, inReplyTo *Message) (msgBytes
 			}
 
 			if resetSeqNumFlag.Bool() {
-				if err = s.store.Reset(); err != nil {
+				if err = s.resetStore(); err != nil {
 					return
 				}
 
 				s.sentReset = true
 				seqNum = s.store.NextSenderMsgSeqNum()
-				msg.Header.SetField(tagMsgSeqNum, FIXInt(seqNum))
+				msg.Header.SetInt(tagMsgSeqNum, seqNum)
 			}
 		}
 	} else {
 		if err = s.application.ToApp(msg, s.sessionID); err != nil {
 			return
 		}
+
+		if err = s.risk.check(msg, s.sessionID); err != nil {
+			return
+		}
+
+		if err = s.compression.compress(msg); err != nil {
+			return
+		}
+
+		if err = s.fieldEncryption.encrypt(msg); err != nil {
+			return
+		}
 	}
 
 	// Message converted to bytes here.
 	msgBytes = msg.Build()
-	err = s.persist(seqNum, msgBytes)
+	if isAdminMessageType(msgType) {
+		if s.ValidateOutgoingAdminMessages {
+			if err = s.validateOutboundAdmin(msgBytes); err != nil {
+				return
+			}
+		}
+	} else if s.outboundValidator != nil {
+		if err = s.validateOutbound(msgBytes); err != nil {
+			return
+		}
+	}
+
+	if err = s.persist(seqNum, msgBytes); err != nil {
+		return
+	}
+	s.checkSeqNumPublish(s.now(), false)
+
+	s.metrics().MessageOut(s.sessionID, string(msgType))
+	s.stats.add(s.now(), statMessagesOut)
+	if !isAdminMessageType(msgType) {
+		s.risk.record(msg, msgType)
+		s.mirrors.publish(s, msgBytes, true)
+	}
+
+	if s.AckTimeout > 0 {
+		s.acks.track(msg, msgType, seqNum, s.now().Add(s.AckTimeout))
+	}
 
 	return
 }
@@ -405,9 +641,15 @@ func (s *Session) persist(seqNum int, msgBytes []byte) error {
 }
 
 func (s *Session) sendQueued(blockUntilSent bool) {
-	for i, msgBytes := range s.toSend {
-		if !s.sendBytes(msgBytes, blockUntilSent) {
+	for i := range s.toSend {
+		if !s.allowedByThrottle(i) {
+			s.toSend = s.toSend[i:]
+			s.signalQueueSpace()
+			return
+		}
+		if !s.sendBytes(s.toSend[i], blockUntilSent) {
 			s.toSend = s.toSend[i:]
+			s.signalQueueSpace()
 			s.notifyMessageOut()
 			return
 		}
@@ -418,6 +660,8 @@ func (s *Session) sendQueued(blockUntilSent bool) {
 
 func (s *Session) dropQueued() {
 	s.toSend = s.toSend[:0]
+	s.signalQueueSpace()
+	s.deliveries.fail(ErrNotDelivered, false)
 }
 
 func (s *Session) EnqueueBytesAndSend(msg []byte) {
@@ -436,24 +680,57 @@ func (s *Session) sendBytes(msg []byte, blockUntilSent bool) bool {
 
 	if blockUntilSent {
 		s.messageOut <- msg
-		s.log.OnOutgoing(msg)
-		s.stateTimer.Reset(s.HeartBtInt)
+		s.onSent(msg)
 		return true
 	}
 
 	select {
 	case s.messageOut <- msg:
-		s.log.OnOutgoing(msg)
-		s.stateTimer.Reset(s.HeartBtInt)
+		s.onSent(msg)
 		return true
 	default:
 		return false
 	}
 }
 
+// onSent logs msg once sent and resets the heartbeat timer, so that a Heartbeat is only sent when no other message
+// was sent for HeartBtInt, unless AlwaysSendHeartbeats is set.
+func (s *Session) onSent(msg []byte) {
+	s.log.OnOutgoing(msg)
+	s.crashDump.outgoing(msg)
+	s.sentCount.Add(1)
+	s.delivered(msg)
+
+	if s.AlwaysSendHeartbeats {
+		if msgType := queuedMsgType(msg); !bytes.Equal(msgType, msgTypeHeartbeat) && !bytes.Equal(msgType, msgTypeLogon) {
+			return
+		}
+	}
+	s.stateTimer.Reset(s.HeartBtInt)
+}
+
+// heartbeatSuppressed returns true if a message was sent after the heartbeat timer fired, which already reset the
+// timer, so that the Heartbeat is not needed.
+func (s *Session) heartbeatSuppressed() bool {
+	fired := s.heartbeatTimeout.Swap(0)
+	return !s.AlwaysSendHeartbeats && fired != 0 && fired-1 != s.sentCount.Load()
+}
+
+// doTargetTooHigh requests the missing messages, skipping any already requested and not yet received.
 func (s *Session) doTargetTooHigh(reject targetTooHigh) (nextState resendState, err error) {
 	s.log.OnEventf("MsgSeqNum too high, expecting %v but received %v", reject.ExpectedTarget, reject.ReceivedTarget)
-	return s.sendResendRequest(reject.ExpectedTarget, reject.ReceivedTarget-1)
+	s.stats.add(s.now(), statGaps)
+
+	gap, ok := s.resendRanges.uncovered(s.store.NextTargetMsgSeqNum(), reject.ExpectedTarget, reject.ReceivedTarget-1, s.resendExpiry())
+	if !ok {
+		s.log.OnEventf("Suppressed ResendRequest FROM: %v TO: %v, already requested", reject.ExpectedTarget, reject.ReceivedTarget-1)
+		nextState.resendRangeEnd = reject.ReceivedTarget - 1
+		return
+	}
+
+	nextState, err = s.sendResendRequest(gap.Begin, gap.End)
+	nextState.resendRangeEnd = reject.ReceivedTarget - 1
+	return
 }
 
 func (s *Session) sendResendRequest(beginSeq, endSeq int) (nextState resendState, err error) {
@@ -485,10 +762,38 @@ func (s *Session) sendResendRequest(beginSeq, endSeq int) (nextState resendState
 		return
 	}
 	s.log.OnEventf("Sent ResendRequest FROM: %v TO: %v", beginSeq, endSeqNo)
+	s.metrics().ResendRequestIssued(s.sessionID)
+
+	requestedEnd := endSeq
+	if nextState.currentResendRangeEnd != 0 {
+		requestedEnd = nextState.currentResendRangeEnd
+	}
+	s.resendRanges.add(s.store.NextTargetMsgSeqNum(), SeqNumRange{Begin: beginSeq, End: requestedEnd}, s.now())
 
 	return
 }
 
+// resendExpiry returns the time before which unanswered ResendRequests have timed out, the zero time if they never do.
+func (s *Session) resendExpiry() time.Time {
+	if s.ResendRequestTimeout <= 0 {
+		return time.Time{}
+	}
+	return s.now().Add(-s.ResendRequestTimeout)
+}
+
+// retryResendRequest requests again the first outstanding range the counterparty left unanswered for the
+// ResendRequestTimeout.
+func (s *Session) retryResendRequest() error {
+	rng, ok := s.resendRanges.expired(s.store.NextTargetMsgSeqNum(), s.resendExpiry())
+	if !ok {
+		return nil
+	}
+
+	s.log.OnEventf("ResendRequest FROM: %v TO: %v timed out", rng.Begin, rng.End)
+	_, err := s.sendResendRequest(rng.Begin, rng.End)
+	return err
+}
+
 func (s *Session) handleLogon(msg *Message) error {
 	// Grab default app ver id from fixt.1.1 logon.
 	if s.sessionID.BeginString == BeginStringFIXT11 {
@@ -506,6 +811,7 @@ func (s *Session) handleLogon(msg *Message) error {
 		s.log.OnEvent("Received logon response")
 	} else {
 		s.log.OnEvent("Received logon request")
+		s.metrics().LogonAttempt(s.sessionID)
 		resetStore = s.ResetOnLogon
 
 		if s.RefreshOnLogon {
@@ -533,7 +839,7 @@ func (s *Session) handleLogon(msg *Message) error {
 	}
 
 	if resetStore {
-		if err := s.store.Reset(); err != nil {
+		if err := s.resetStore(); err != nil {
 			return err
 		}
 	}
@@ -560,7 +866,15 @@ func (s *Session) handleLogon(msg *Message) error {
 	s.sentReset = false
 
 	s.peerTimer.Reset(time.Duration(float64(1.2) * float64(s.HeartBtInt)))
-	s.application.OnLogon(s.sessionID)
+	s.logonRejects.reset()
+	s.reconnectAttempts.reset()
+	s.seqNumCheckpoint = seqNumCheckpoint{next: s.now().Add(s.SeqNumCheckpointInterval)}
+	s.nextTestRequestProbe = s.now().Add(s.TestRequestInterval)
+	if s.handshake.get() == nil {
+		s.application.OnLogon(s.sessionID)
+		s.notifyLogon()
+	}
+	s.snapshots.start()
 
 	// Evaluate tag 789 to see if we end up with an implied gapfill/resend.
 	if s.EnableNextExpectedMsgSeqNum && !msg.Body.Has(tagResetSeqNumFlag) {
@@ -641,6 +955,12 @@ func (s *Session) verifySelect(msg *Message, checkTooHigh bool, checkTooLow bool
 		}
 	}
 
+	if s.origSendingTimeCheck != origSendingTimeTooLow {
+		if reject := s.checkPossDupOrigSendingTime(msg); reject != nil {
+			return reject
+		}
+	}
+
 	if checkAppImpl {
 		return s.verifyMsgAgainstAppImpl(msg)
 	}
@@ -649,8 +969,17 @@ func (s *Session) verifySelect(msg *Message, checkTooHigh bool, checkTooLow bool
 }
 
 func (s *Session) verifyMsgAgainstAppImpl(msg *Message) MessageRejectError {
-	if s.Validator != nil {
-		if reject := s.Validator.Validate(msg); reject != nil {
+	if msgType, err := msg.Header.GetBytes(tagMsgType); err == nil && !isAdminMessageType(msgType) {
+		if reject := s.fieldEncryption.decrypt(msg); reject != nil {
+			return reject
+		}
+		if reject := s.compression.decompress(msg); reject != nil {
+			return reject
+		}
+	}
+
+	if validator := s.inboundValidator(msg); validator != nil {
+		if reject := validator.Validate(msg); reject != nil {
 			return reject
 		}
 	}
@@ -664,11 +993,43 @@ func (s *Session) fromCallback(msg *Message) MessageRejectError {
 		return err
 	}
 
+	if s.AckTimeout > 0 {
+		s.acks.acknowledge(msg, msgType)
+	}
+
+	if s.SeqNumCheckpointInterval > 0 {
+		s.checkSeqNumDrift(msg)
+	}
+
+	for _, k := range s.killSwitches.list() {
+		k.onResponse(msg, msgType, s.sessionID)
+	}
+
+	if bytes.Equal(msgType, msgTypeReject) || string(msgType) == "j" {
+		s.stats.add(s.now(), statRejects)
+		s.webhookRejected()
+	}
+
 	if isAdminMessageType(msgType) {
-		return s.application.FromAdmin(msg, s.sessionID)
+		return s.fromAdmin(msg)
 	}
 
-	return s.application.FromApp(msg, s.sessionID)
+	s.mirrors.publish(s, msg.Bytes(), false)
+
+	if s.handshake.pending.Load() {
+		s.handshakeMessage(msg)
+		return nil
+	}
+
+	if rejected, reject := s.interceptInbound(msg); rejected {
+		return reject
+	}
+
+	reject := s.fromApp(msg)
+	if reject != nil {
+		s.saveDeadLetter(msg, reject)
+	}
+	return reject
 }
 
 func (s *Session) checkTargetTooLow(msg *Message) MessageRejectError {
@@ -739,40 +1100,114 @@ func (s *Session) checkSendingTime(msg *Message) MessageRejectError {
 		return err
 	}
 
-	if delta := time.Since(sendingTime); delta <= -1*s.MaxLatency || delta >= s.MaxLatency {
+	if delta := s.now().Sub(sendingTime); delta <= -1*s.MaxLatency || delta >= s.MaxLatency {
 		return sendingTimeAccuracyProblem()
 	}
 
 	return nil
 }
 
+// checkPossDupOrigSendingTime validates OrigSendingTime on messages with PossDupFlag=Y, according to
+// the PossDupOrigSendingTimeCheck setting.
+func (s *Session) checkPossDupOrigSendingTime(msg *Message) MessageRejectError {
+	if s.origSendingTimeCheck == origSendingTimeAccept || !msg.Header.Has(tagPossDupFlag) {
+		return nil
+	}
+
+	var possDupFlag FIXBoolean
+	if err := msg.Header.GetField(tagPossDupFlag, &possDupFlag); err != nil {
+		return err
+	}
+	if !possDupFlag.Bool() {
+		return nil
+	}
+
+	reject := func(rej MessageRejectError) MessageRejectError {
+		if s.origSendingTimeCheck == origSendingTimeWarn {
+			s.log.OnEventf("PossDup message with invalid OrigSendingTime accepted: %v", rej.Error())
+			return nil
+		}
+		return rej
+	}
+
+	if !msg.Header.Has(tagOrigSendingTime) {
+		return reject(RequiredTagMissing(tagOrigSendingTime))
+	}
+
+	var origSendingTime FIXUTCTimestamp
+	if err := msg.Header.GetField(tagOrigSendingTime, &origSendingTime); err != nil {
+		return reject(err)
+	}
+
+	var sendingTime FIXUTCTimestamp
+	if err := msg.Header.GetField(tagSendingTime, &sendingTime); err != nil {
+		return err
+	}
+
+	if sendingTime.Before(origSendingTime.Time) {
+		return reject(sendingTimeAccuracyProblem())
+	}
+
+	return nil
+}
+
 func (s *Session) checkBeginString(msg *Message) MessageRejectError {
 	switch beginString, err := msg.Header.GetBytes(tagBeginString); {
 	case err != nil:
 		return RequiredTagMissing(tagBeginString)
 	case s.sessionID.BeginString != string(beginString):
-		return incorrectBeginString{}
+		tag := tagBeginString
+		return incorrectBeginString{
+			messageRejectError: messageRejectError{
+				text:         "Value is incorrect (out of range) for this tag",
+				rejectReason: rejectReasonValueIsIncorrect,
+				refTagID:     &tag,
+			},
+			Received: string(beginString),
+			Expected: s.sessionID.BeginString,
+		}
 	}
 
 	return nil
 }
 
 func (s *Session) drainMessageIn() {
-	s.log.OnEventf("Draining %d messages from inbound channel...", len(s.messageIn))
+	s.incomingPending()
+
+	s.log.OnEventf("Draining %d batches of messages from inbound channel...", len(s.messageIn))
 	for {
 		select {
-		case fixInc, ok := <-s.messageIn:
+		case batch, ok := <-s.messageIn:
 			if !ok {
 				return
 			}
-			s.Incoming(s, fixInc)
+			s.incomingBatch(batch)
 		default:
 			return
 		}
 	}
 }
 
+// incomingBatch processes the messages received together.
+func (s *Session) incomingBatch(batch []fixIn) {
+	s.pendingIn = batch
+	s.incomingPending()
+}
+
+// incomingPending processes the messages left in the current batch. A message disconnecting the session drains
+// the rest of the batch before the inbound channel, keeping the messages in order.
+func (s *Session) incomingPending() {
+	for len(s.pendingIn) > 0 {
+		in := s.pendingIn[0]
+		s.pendingIn = s.pendingIn[1:]
+		s.Incoming(s, in)
+	}
+	s.pendingIn = nil
+}
+
 func (s *Session) doReject(msg *Message, rej MessageRejectError) error {
+	s.stats.add(s.now(), statRejects)
+	s.webhookRejected()
 	reply := msg.reverseRoute()
 
 	if s.sessionID.BeginString >= BeginStringFIX42 {
@@ -828,6 +1263,10 @@ type fixIn struct {
 
 func (s *Session) onDisconnect() {
 	s.log.OnEvent("Disconnected")
+	s.deliveries.fail(ErrNotDelivered, true)
+	s.handshake.reset()
+	s.resendRanges.clear()
+	s.testRequestSent = time.Time{}
 	if s.ResetOnDisconnect {
 		if err := s.dropAndReset(); err != nil {
 			s.logError(err)
@@ -852,7 +1291,7 @@ func (s *Session) onAdmin(msg interface{}) {
 
 		if s.IsConnected() {
 			if msg.err != nil {
-				msg.err <- errors.New("Already connected")
+				msg.err <- alreadyConnectedError{logout: s.buildDuplicateConnectionLogout()}
 				close(msg.err)
 			}
 			return
@@ -867,6 +1306,15 @@ func (s *Session) onAdmin(msg interface{}) {
 			return
 		}
 
+		if err := s.checkEpoch(); err != nil {
+			s.logError(err)
+			if msg.err != nil {
+				msg.err <- err
+				close(msg.err)
+			}
+			return
+		}
+
 		if msg.err != nil {
 			close(msg.err)
 		}
@@ -885,13 +1333,27 @@ func (s *Session) onAdmin(msg interface{}) {
 			msg.rep <- s.stateMachine.notifyOnInSessionTime
 		}
 		close(msg.rep)
+
+	case injectReq:
+		msg.rep <- s.injectInbound(msg)
+
+	case exportResumptionReq:
+		token, err := s.exportResumptionToken()
+		msg.rep <- exportResumptionRep{token: token, err: err}
+
+	case importResumptionReq:
+		msg.rep <- s.importResumptionToken(msg.token)
 	}
 }
 
 func (s *Session) run() {
+	defer s.recoverWithCrashDump()
+
+	s.recordStart()
 	s.Start(s)
 	var stopChan = make(chan struct{})
 	s.stateTimer = internal.NewEventTimer(func() {
+		s.heartbeatTimeout.Store(s.sentCount.Load() + 1)
 		select {
 		// Deadlock in write to chan s.sessionEvent after s.Stopped()==true and end of loop Session.go:766 because no reader of chan s.sessionEvent.
 		case s.sessionEvent <- internal.NeedHeartbeat:
@@ -916,6 +1378,11 @@ func (s *Session) run() {
 
 	ticker := time.NewTicker(time.Second)
 
+	if s.seqNumPublishing != nil {
+		go s.seqNumPublishing.run(s, stopChan)
+		s.checkSeqNumPublish(s.now(), true)
+	}
+
 	defer func() {
 		close(stopChan)
 		s.stateTimer.Stop()
@@ -927,34 +1394,116 @@ func (s *Session) run() {
 		select {
 
 		case msg := <-s.admin:
+			s.recordAdmin(msg)
 			s.onAdmin(msg)
 
 		case <-s.messageEvent:
+			s.record(RecordedEvent{Type: RecordedSendQueued})
 			s.SendAppMessages(s)
 
-		case fixIn, ok := <-s.messageIn:
+		case batch, ok := <-s.messageIn:
 			if !ok {
+				s.record(RecordedEvent{Type: RecordedDisconnect})
 				s.Disconnected(s)
 			} else {
-				s.Incoming(s, fixIn)
+				s.recordInbound(batch)
+				s.targetMutex.Lock()
+				s.incomingBatch(batch)
+				s.targetMutex.Unlock()
 			}
 
 		case evt := <-s.sessionEvent:
+			s.recordTimeout(evt)
 			s.Timeout(s, evt)
 
 		case now := <-ticker.C:
-			s.CheckSessionTime(s, now)
-			s.CheckResetTime(s, now)
+			s.record(RecordedEvent{Type: RecordedTick, Time: now})
+			s.onTick(now)
+		}
+	}
+}
+
+// onTick runs the checks of the session done every second.
+func (s *Session) onTick(now time.Time) {
+	// Day orders are handled before the session logs out at the end of its session time.
+	s.checkTIFWatchdogs(now)
+	s.CheckSessionTime(s, now)
+	s.CheckResetTime(s, now)
+	s.checkAckTimeouts(now)
+	s.checkSeqNumCheckpoint(now)
+	s.checkTestRequestProbe(now)
+	s.checkSeqNumPublish(now, false)
+	s.replaySnapshots()
+	s.checkHeldTimeouts(now)
+}
+
+// validateOutbound validates an outgoing message against OutboundDataDictionary.
+// The message is parsed back from its bytes, as validation works on the fields in wire order.
+func (s *Session) validateOutbound(msgBytes []byte) error {
+	msg := NewMessage()
+	if err := parseMessageWithDataFields(msg, bytes.NewBuffer(msgBytes), s.transportDataDictionary, s.outboundDataDictionary, s.compression.dataFields()); err != nil {
+		return ErrValidation{Details: "outbound data dictionary", Err: err}
+	}
+
+	if rejectErr := s.outboundValidator.Validate(msg); rejectErr != nil {
+		return ErrValidation{Details: "outbound data dictionary", Err: rejectErr}
+	}
+	return nil
+}
+
+// OutboundAdminValidationHandler is implemented by an Application notified of the outgoing admin messages failing
+// validation with ValidateOutgoingAdminMessages set. The messages are not sent.
+type OutboundAdminValidationHandler interface {
+	OnOutboundAdminInvalid(msg *Message, err error, sessionID SessionID)
+}
+
+// validateOutboundAdmin validates an outgoing admin message against OutboundDataDictionary if set, or else against
+// the session's data dictionaries. Violations are logged as events and reported to an OutboundAdminValidationHandler.
+func (s *Session) validateOutboundAdmin(msgBytes []byte) error {
+	validator, dataDictionary := s.Validator, s.appDataDictionary
+	if s.outboundValidator != nil {
+		validator, dataDictionary = s.outboundValidator, s.outboundDataDictionary
+	}
+
+	msg := NewMessage()
+	err := parseMessageWithDataFields(msg, bytes.NewBuffer(msgBytes), s.transportDataDictionary, dataDictionary, s.compression.dataFields())
+	if err == nil {
+		if rejectErr := validator.Validate(msg); rejectErr != nil {
+			err = rejectErr
 		}
 	}
+	if err == nil {
+		return nil
+	}
+
+	err = ErrValidation{Details: "outgoing admin message", Err: err}
+	s.log.OnEventf("%v: %q", err, msgBytes)
+	if handler, ok := s.application.(OutboundAdminValidationHandler); ok {
+		handler.OnOutboundAdminInvalid(msg, err, s.sessionID)
+	}
+	return err
 }
 
 // ParseMessage parses a FIX message from a raw byte buffer using the session's data dictionaries.
 func (s *Session) ParseMessage(msg *Message, rawMessage *bytes.Buffer) (err error) {
-	return ParseMessageWithDataDictionary(msg, rawMessage, s.transportDataDictionary, s.appDataDictionary)
+	appDataDictionary := s.appDataDictionary
+	if dict, ok := s.applVerDataDictionaries[s.inboundDefaultApplVerID()]; ok {
+		appDataDictionary = dict
+	}
+	return parseMessageWithApplVerIDs(msg, rawMessage, s.transportDataDictionary, appDataDictionary,
+		s.applVerDataDictionaries, s.compression.dataFields())
 }
 
 // SendToTarget sends a message to the target specified in the session's SessionID.
 func (s *Session) SendToTarget(m Messagable) error {
-	return s.queueForSend(m.ToMessage())
+	if err := s.checkCanSend(); err != nil {
+		return err
+	}
+
+	msg := m.ToMessage()
+	s.recordSend(msg)
+	if s.requiresApproval(msg) {
+		return s.hold(msg)
+	}
+	return s.queueForSend(msg)
 }
@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"compress/zlib"
 	"net"
 	"strconv"
 	"strings"
@@ -61,10 +62,68 @@ var applVerIDLookup = map[string]string{
 type sessionFactory struct {
 	// True if building sessions that initiate logon.
 	BuildInitiators bool
+
+	// Set on the sessions built, see WithMetrics, WithClock, WithCallbackTracer, WithSeqNumPublisher,
+	// WithRecorder, WithHolidayCalendar, WithFieldCrypter and WithRawMessageDecoder.
+	metrics         MetricsCollector
+	clock           Clock
+	tracer          CallbackTracer
+	seqNumPublisher SeqNumPublisher
+	recorder        Recorder
+	holidays        HolidayCalendar
+	fieldCrypter    Crypter
+	rawDecoders     map[string]RawMessageDecoder
+
+	// The registry of the Engine the sessions are registered with, the default Engine if nil.
+	registry *registry
 }
 
 const shortForm = "15:04:05"
 
+// preloadDataDictionaries starts parsing the data dictionaries of all sessions in parallel, ahead of
+// the sessions being created one by one.
+func preloadDataDictionaries(sessionSettings map[SessionID]*SessionSettings) {
+	for _, settings := range sessionSettings {
+		for _, setting := range []string{
+			config.DataDictionary, config.TransportDataDictionary, config.AppDataDictionary, config.OutboundDataDictionary,
+		} {
+			if path, err := settings.Setting(setting); err == nil {
+				datadictionary.DefaultCache.Load(path)
+			}
+		}
+		for _, setting := range applVerIDSettings(settings) {
+			if path, err := settings.Setting(setting); err == nil {
+				datadictionary.DefaultCache.Load(path)
+			}
+		}
+	}
+}
+
+// hasDataDictionary returns true if the data dictionary setting is set, either to a path or in memory.
+func hasDataDictionary(settings *SessionSettings, setting string) bool {
+	_, inMemory := settings.DataDictionarySetting(setting)
+	return inMemory || settings.HasSetting(setting)
+}
+
+// loadDataDictionary returns the data dictionary of setting, set in memory with SetDataDictionary or parsed from
+// the path of the setting.
+func loadDataDictionary(settings *SessionSettings, setting string) (*datadictionary.DataDictionary, error) {
+	if dict, ok := settings.DataDictionarySetting(setting); ok {
+		return dict, nil
+	}
+
+	path, err := settings.Setting(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, err := datadictionary.DefaultCache.Parse(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem parsing XML datadictionary path '%v' for setting '%v", path, setting)
+	}
+	return dict, nil
+}
+
 // Creates Session, associates with internal Session registry.
 func (f sessionFactory) createSession(
 	sessionID SessionID, storeFactory MessageStoreFactory, settings *SessionSettings,
@@ -75,7 +134,7 @@ func (f sessionFactory) createSession(
 		return
 	}
 
-	if err = registerSession(session); err != nil {
+	if err = f.sessionRegistry().register(session); err != nil {
 		return
 	}
 	application.OnCreate(session.sessionID)
@@ -84,12 +143,72 @@ func (f sessionFactory) createSession(
 	return
 }
 
+// configureThrottle limits the rate of the outbound application messages of the session, see MaxMessagesPerSecond.
+func (f sessionFactory) configureThrottle(s *Session, settings *SessionSettings) error {
+	maxMessagesPerSecond, err := settings.IntSetting(config.MaxMessagesPerSecond)
+	if err != nil {
+		return err
+	}
+	if maxMessagesPerSecond <= 0 {
+		return errors.New("MaxMessagesPerSecond must be greater than zero")
+	}
+
+	burstSize := maxMessagesPerSecond
+	if settings.HasSetting(config.BurstSize) {
+		if burstSize, err = settings.IntSetting(config.BurstSize); err != nil {
+			return err
+		}
+		if burstSize <= 0 {
+			return errors.New("BurstSize must be greater than zero")
+		}
+	}
+
+	policy := throttleQueue
+	if settings.HasSetting(config.ThrottlePolicy) {
+		policyStr, err := settings.Setting(config.ThrottlePolicy)
+		if err != nil {
+			return err
+		}
+
+		switch policyStr {
+		case "QUEUE":
+			policy = throttleQueue
+		case "DROP":
+			policy = throttleDrop
+		case "CALLBACK":
+			policy = throttleCallback
+		default:
+			return IncorrectFormatForSetting{Setting: config.ThrottlePolicy, Value: []byte(policyStr)}
+		}
+	}
+
+	s.throttle = newThrottle(maxMessagesPerSecond, burstSize, policy)
+	return nil
+}
+
+// sessionRegistry returns the registry the sessions are registered with.
+func (f sessionFactory) sessionRegistry() *registry {
+	if f.registry == nil {
+		return defaultEngine.registry
+	}
+	return f.registry
+}
+
 func (f sessionFactory) newSession(
 	sessionID SessionID, storeFactory MessageStoreFactory, settings *SessionSettings, logFactory LogFactory,
 	application Application) (s *Session, err error) {
 	s = &Session{
-		sessionID: sessionID,
-		stopOnce:  sync.Once{},
+		sessionID:        sessionID,
+		stopOnce:         sync.Once{},
+		metricsCollector: f.metrics,
+		clock:            f.clock,
+		tracer:           f.tracer,
+		holidays:         f.holidays,
+	}
+
+	if f.recorder != nil {
+		s.recorder = f.recorder
+		s.recordedSettings = settings.recordable()
 	}
 
 	var validatorSettings = defaultValidatorSettings
@@ -135,49 +254,61 @@ func (f sessionFactory) newSession(
 		}
 
 		// If the transport or app data dictionary setting is set, the other also needs to be set.
-		if settings.HasSetting(config.TransportDataDictionary) || settings.HasSetting(config.AppDataDictionary) {
-			var transportDataDictionaryPath, appDataDictionaryPath string
-			if transportDataDictionaryPath, err = settings.Setting(config.TransportDataDictionary); err != nil {
-				return
-			}
+		if hasDataDictionary(settings, config.TransportDataDictionary) || hasDataDictionary(settings, config.AppDataDictionary) {
+			// Start parsing both before waiting on either.
+			preloadDataDictionaries(map[SessionID]*SessionSettings{sessionID: settings})
 
-			if appDataDictionaryPath, err = settings.Setting(config.AppDataDictionary); err != nil {
+			if s.transportDataDictionary, err = loadDataDictionary(settings, config.TransportDataDictionary); err != nil {
 				return
 			}
 
-			if s.transportDataDictionary, err = datadictionary.Parse(transportDataDictionaryPath); err != nil {
-				err = errors.Wrapf(
-					err, "problem parsing XML datadictionary path '%v' for setting '%v",
-					settings.settings[config.TransportDataDictionary], config.TransportDataDictionary,
-				)
+			if s.appDataDictionary, err = loadDataDictionary(settings, config.AppDataDictionary); err != nil {
 				return
 			}
 
-			if s.appDataDictionary, err = datadictionary.Parse(appDataDictionaryPath); err != nil {
-				err = errors.Wrapf(
-					err, "problem parsing XML datadictionary path '%v' for setting '%v",
-					settings.settings[config.AppDataDictionary], config.AppDataDictionary,
-				)
+			s.Validator = NewValidator(validatorSettings, s.appDataDictionary, s.transportDataDictionary)
+
+			if err = s.loadApplVerIDDataDictionaries(settings, validatorSettings); err != nil {
 				return
 			}
+		} else if len(applVerIDSettings(settings)) > 0 {
+			err = ConditionallyRequiredSetting{Setting: config.AppDataDictionary}
+			return
+		}
+	} else if hasDataDictionary(settings, config.DataDictionary) {
+		if s.appDataDictionary, err = loadDataDictionary(settings, config.DataDictionary); err != nil {
+			return
+		}
 
-			s.Validator = NewValidator(validatorSettings, s.appDataDictionary, s.transportDataDictionary)
+		s.Validator = NewValidator(validatorSettings, s.appDataDictionary, nil)
+	}
+
+	if hasDataDictionary(settings, config.OutboundDataDictionary) {
+		if sessionID.IsFIXT() && s.transportDataDictionary == nil {
+			err = ConditionallyRequiredSetting{Setting: config.TransportDataDictionary}
+			return
 		}
-	} else if settings.HasSetting(config.DataDictionary) {
-		var dataDictionaryPath string
-		if dataDictionaryPath, err = settings.Setting(config.DataDictionary); err != nil {
+
+		if s.outboundDataDictionary, err = loadDataDictionary(settings, config.OutboundDataDictionary); err != nil {
 			return
 		}
 
-		if s.appDataDictionary, err = datadictionary.Parse(dataDictionaryPath); err != nil {
-			err = errors.Wrapf(
-				err, "problem parsing XML datadictionary path '%v' for setting '%v",
-				settings.settings[config.DataDictionary], config.DataDictionary,
-			)
+		s.outboundValidator = NewValidator(validatorSettings, s.outboundDataDictionary, s.transportDataDictionary)
+	}
+
+	if settings.HasSetting(config.ValidateOutgoingAdminMessages) {
+		if s.ValidateOutgoingAdminMessages, err = settings.BoolSetting(config.ValidateOutgoingAdminMessages); err != nil {
 			return
 		}
 
-		s.Validator = NewValidator(validatorSettings, s.appDataDictionary, nil)
+		if s.ValidateOutgoingAdminMessages && s.outboundDataDictionary == nil && s.appDataDictionary == nil {
+			setting := config.DataDictionary
+			if sessionID.IsFIXT() {
+				setting = config.TransportDataDictionary
+			}
+			err = ConditionallyRequiredSetting{Setting: setting}
+			return
+		}
 	}
 
 	if settings.HasSetting(config.ResetOnLogon) {
@@ -204,6 +335,18 @@ func (f sessionFactory) newSession(
 		}
 	}
 
+	if settings.HasSetting(config.AlwaysSendHeartbeats) {
+		if s.AlwaysSendHeartbeats, err = settings.BoolSetting(config.AlwaysSendHeartbeats); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.AllowInboundInjection) {
+		if s.AllowInboundInjection, err = settings.BoolSetting(config.AllowInboundInjection); err != nil {
+			return
+		}
+	}
+
 	if settings.HasSetting(config.EnableLastMsgSeqNumProcessed) {
 		if s.EnableLastMsgSeqNumProcessed, err = settings.BoolSetting(config.EnableLastMsgSeqNumProcessed); err != nil {
 			return
@@ -241,13 +384,42 @@ func (f sessionFactory) newSession(
 		s.MaxLatency = time.Duration(maxLatency) * time.Second
 	}
 
+	if settings.HasSetting(config.SessionLabels) {
+		var labelsStr string
+		if labelsStr, err = settings.Setting(config.SessionLabels); err != nil {
+			return
+		}
+		if s.SessionLabels, err = parseLabels(labelsStr); err != nil {
+			err = IncorrectFormatForSetting{Setting: config.SessionLabels, Value: []byte(labelsStr), Err: err}
+			return
+		}
+	}
+
 	if settings.HasSetting(config.ResendRequestChunkSize) {
 		if s.ResendRequestChunkSize, err = settings.IntSetting(config.ResendRequestChunkSize); err != nil {
 			return
 		}
 	}
 
-	if settings.HasSetting(config.StartTime) || settings.HasSetting(config.EndTime) {
+	if settings.HasSetting(config.ResendRequestTimeout) {
+		if s.ResendRequestTimeout, err = settings.DurationSetting(config.ResendRequestTimeout); err != nil {
+			var timeoutInt int
+			if timeoutInt, err = settings.IntSetting(config.ResendRequestTimeout); err != nil {
+				return
+			}
+			s.ResendRequestTimeout = time.Duration(timeoutInt) * time.Second
+		}
+		if s.ResendRequestTimeout < 0 {
+			err = errors.New("ResendRequestTimeout must not be negative")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SessionWindows) {
+		if err = f.configureSessionWindows(s, settings); err != nil {
+			return
+		}
+	} else if settings.HasSetting(config.StartTime) || settings.HasSetting(config.EndTime) {
 		var startTimeStr, endTimeStr string
 		if startTimeStr, err = settings.Setting(config.StartTime); err != nil {
 			return
@@ -274,42 +446,16 @@ func (f sessionFactory) newSession(
 			return
 		}
 
-		loc := time.UTC
-		if settings.HasSetting(config.TimeZone) {
-			var locStr string
-			if locStr, err = settings.Setting(config.TimeZone); err != nil {
-				return
-			}
-
-			loc, err = time.LoadLocation(locStr)
-			if err != nil {
-				err = errors.Wrapf(
-					err, "problem parsing time zone '%v' for setting '%v",
-					settings.settings[config.TimeZone], config.TimeZone,
-				)
-				return
-			}
+		var loc *time.Location
+		if loc, err = parseTimeZone(settings); err != nil {
+			return
 		}
 		s.TimeZone = loc
 
 		if !settings.HasSetting(config.StartDay) && !settings.HasSetting(config.EndDay) {
 			var weekdays []time.Weekday
-			if settings.HasSetting(config.Weekdays) {
-				var weekdaysStr string
-				if weekdaysStr, err = settings.Setting(config.Weekdays); err != nil {
-					return
-				}
-
-				dayStrs := strings.Split(weekdaysStr, ",")
-
-				for _, dayStr := range dayStrs {
-					day, ok := dayLookup[dayStr]
-					if !ok {
-						err = IncorrectFormatForSetting{Setting: config.Weekdays, Value: []byte(weekdaysStr)}
-						return
-					}
-					weekdays = append(weekdays, day)
-				}
+			if weekdays, err = parseWeekdays(settings); err != nil {
+				return
 			}
 
 			var sessionTime *internal.TimeRange
@@ -422,6 +568,371 @@ func (f sessionFactory) newSession(
 		}
 	}
 
+	if settings.HasSetting(config.PossDupOrigSendingTimeCheck) {
+		var checkStr string
+		if checkStr, err = settings.Setting(config.PossDupOrigSendingTimeCheck); err != nil {
+			return
+		}
+
+		switch checkStr {
+		case "REJECT":
+			s.origSendingTimeCheck = origSendingTimeReject
+		case "WARN":
+			s.origSendingTimeCheck = origSendingTimeWarn
+		case "ACCEPT":
+			s.origSendingTimeCheck = origSendingTimeAccept
+
+		default:
+			err = IncorrectFormatForSetting{Setting: config.PossDupOrigSendingTimeCheck, Value: []byte(checkStr)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.CrashDumpPath) {
+		if s.CrashDumpPath, err = settings.Setting(config.CrashDumpPath); err != nil {
+			return
+		}
+
+		messageCount := 100
+		if settings.HasSetting(config.CrashDumpMessageCount) {
+			if messageCount, err = settings.IntSetting(config.CrashDumpMessageCount); err != nil {
+				return
+			} else if messageCount <= 0 {
+				err = IncorrectFormatForSetting{Setting: config.CrashDumpMessageCount, Value: []byte(strconv.Itoa(messageCount))}
+				return
+			}
+		}
+		s.crashDump = newCrashDump(messageCount)
+	}
+
+	if settings.HasSetting(config.AckTimeout) {
+		if s.AckTimeout, err = settings.DurationSetting(config.AckTimeout); err != nil {
+			var timeoutInt int
+			if timeoutInt, err = settings.IntSetting(config.AckTimeout); err != nil {
+				return
+			}
+			s.AckTimeout = time.Duration(timeoutInt) * time.Second
+		}
+
+		if s.AckTimeout <= 0 {
+			err = errors.New("AckTimeout must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SeqNumCheckpointInterval) {
+		if s.SeqNumCheckpointInterval, err = settings.DurationSetting(config.SeqNumCheckpointInterval); err != nil {
+			var intervalInt int
+			if intervalInt, err = settings.IntSetting(config.SeqNumCheckpointInterval); err != nil {
+				return
+			}
+			s.SeqNumCheckpointInterval = time.Duration(intervalInt) * time.Second
+		}
+
+		if s.SeqNumCheckpointInterval <= 0 {
+			err = errors.New("SeqNumCheckpointInterval must be greater than zero")
+			return
+		}
+	}
+
+	s.SeqNumDriftThreshold = 20
+	if settings.HasSetting(config.SeqNumDriftThreshold) {
+		if !settings.HasSetting(config.SeqNumCheckpointInterval) {
+			err = ConditionallyRequiredSetting{Setting: config.SeqNumCheckpointInterval}
+			return
+		}
+
+		if s.SeqNumDriftThreshold, err = settings.IntSetting(config.SeqNumDriftThreshold); err != nil {
+			return
+		}
+
+		if s.SeqNumDriftThreshold < 0 {
+			err = errors.New("SeqNumDriftThreshold must not be negative")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.TestRequestInterval) {
+		if s.TestRequestInterval, err = settings.DurationSetting(config.TestRequestInterval); err != nil {
+			var intervalInt int
+			if intervalInt, err = settings.IntSetting(config.TestRequestInterval); err != nil {
+				return
+			}
+			s.TestRequestInterval = time.Duration(intervalInt) * time.Second
+		}
+
+		if s.TestRequestInterval <= 0 {
+			err = errors.New("TestRequestInterval must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.HeartbeatRTTThreshold) {
+		if s.HeartbeatRTTThreshold, err = settings.DurationSetting(config.HeartbeatRTTThreshold); err != nil {
+			var thresholdInt int
+			if thresholdInt, err = settings.IntSetting(config.HeartbeatRTTThreshold); err != nil {
+				return
+			}
+			s.HeartbeatRTTThreshold = time.Duration(thresholdInt) * time.Second
+		}
+
+		if s.HeartbeatRTTThreshold <= 0 {
+			err = errors.New("HeartbeatRTTThreshold must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SeqNumPublishInterval) {
+		if s.SeqNumPublishInterval, err = settings.DurationSetting(config.SeqNumPublishInterval); err != nil {
+			var intervalInt int
+			if intervalInt, err = settings.IntSetting(config.SeqNumPublishInterval); err != nil {
+				return
+			}
+			s.SeqNumPublishInterval = time.Duration(intervalInt) * time.Second
+		}
+
+		if s.SeqNumPublishInterval <= 0 {
+			err = errors.New("SeqNumPublishInterval must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SeqNumPublishMessageCount) {
+		if s.SeqNumPublishMessageCount, err = settings.IntSetting(config.SeqNumPublishMessageCount); err != nil {
+			return
+		}
+
+		if s.SeqNumPublishMessageCount <= 0 {
+			err = errors.New("SeqNumPublishMessageCount must be greater than zero")
+			return
+		}
+	}
+	s.seqNumPublishing = newSeqNumPublishing(f.seqNumPublisher, s.SeqNumPublishInterval, s.SeqNumPublishMessageCount)
+
+	s.SnapshotRate = 100
+	if settings.HasSetting(config.SnapshotRate) {
+		if s.SnapshotRate, err = settings.IntSetting(config.SnapshotRate); err != nil {
+			return
+		}
+
+		if s.SnapshotRate <= 0 {
+			err = errors.New("SnapshotRate must be greater than zero")
+			return
+		}
+	}
+
+	statsHistoryMinutes := 60
+	if settings.HasSetting(config.StatsHistoryMinutes) {
+		if statsHistoryMinutes, err = settings.IntSetting(config.StatsHistoryMinutes); err != nil {
+			return
+		}
+		if statsHistoryMinutes < 0 {
+			err = errors.New("StatsHistoryMinutes must not be negative")
+			return
+		}
+	}
+	s.stats = newStatsHistory(statsHistoryMinutes)
+
+	if settings.HasSetting(config.MaxMessagesPerSecond) {
+		if err = f.configureThrottle(s, settings); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.ApprovalMsgTypes) {
+		var msgTypes string
+		if msgTypes, err = settings.Setting(config.ApprovalMsgTypes); err != nil {
+			return
+		}
+
+		s.ApprovalMsgTypes = make(map[string]bool)
+		for _, msgType := range strings.Split(msgTypes, ",") {
+			if msgType = strings.TrimSpace(msgType); msgType == "" {
+				continue
+			}
+			if isAdminMessageType([]byte(msgType)) {
+				err = IncorrectFormatForSetting{Setting: config.ApprovalMsgTypes, Value: []byte(msgTypes), Err: errors.Errorf("admin MsgType %v cannot be held", msgType)}
+				return
+			}
+			s.ApprovalMsgTypes[msgType] = true
+		}
+
+		if len(s.ApprovalMsgTypes) == 0 {
+			err = IncorrectFormatForSetting{Setting: config.ApprovalMsgTypes, Value: []byte(msgTypes)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.ApprovalTimeout) {
+		if !settings.HasSetting(config.ApprovalMsgTypes) {
+			err = ConditionallyRequiredSetting{Setting: config.ApprovalMsgTypes}
+			return
+		}
+
+		if s.ApprovalTimeout, err = settings.DurationSetting(config.ApprovalTimeout); err != nil {
+			var timeoutInt int
+			if timeoutInt, err = settings.IntSetting(config.ApprovalTimeout); err != nil {
+				return
+			}
+			s.ApprovalTimeout = time.Duration(timeoutInt) * time.Second
+		}
+
+		if s.ApprovalTimeout <= 0 {
+			err = errors.New("ApprovalTimeout must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.DropCopy) {
+		if s.DropCopy, err = settings.BoolSetting(config.DropCopy); err != nil {
+			return
+		}
+
+		if s.DropCopy && len(s.ApprovalMsgTypes) > 0 {
+			err = errors.New("ApprovalMsgTypes cannot be set for a DropCopy session")
+			return
+		}
+	}
+
+	s.HandshakeTimeout = 10 * time.Second
+	if settings.HasSetting(config.HandshakeTimeout) {
+		if s.HandshakeTimeout, err = settings.DurationSetting(config.HandshakeTimeout); err != nil {
+			var timeoutInt int
+			if timeoutInt, err = settings.IntSetting(config.HandshakeTimeout); err != nil {
+				return
+			}
+			s.HandshakeTimeout = time.Duration(timeoutInt) * time.Second
+		}
+
+		if s.HandshakeTimeout <= 0 {
+			err = errors.New("HandshakeTimeout must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.BeginStringMismatchPolicy) {
+		var policyStr string
+		if policyStr, err = settings.Setting(config.BeginStringMismatchPolicy); err != nil {
+			return
+		}
+
+		switch policyStr {
+		case "LOGOUT":
+			s.beginStringMismatch = beginStringMismatchPolicy{action: BeginStringMismatchLogout}
+		case "DISCONNECT":
+			s.beginStringMismatch = beginStringMismatchPolicy{action: BeginStringMismatchDisconnect}
+		case "REJECT":
+			s.beginStringMismatch = beginStringMismatchPolicy{action: BeginStringMismatchReject}
+		case "CALLBACK":
+			handler, ok := application.(BeginStringMismatchHandler)
+			if !ok {
+				err = errors.Errorf("%v=CALLBACK requires the Application to implement BeginStringMismatchHandler", config.BeginStringMismatchPolicy)
+				return
+			}
+			s.beginStringMismatch = beginStringMismatchPolicy{handler: handler}
+
+		default:
+			err = IncorrectFormatForSetting{Setting: config.BeginStringMismatchPolicy, Value: []byte(policyStr)}
+			return
+		}
+	}
+
+	for _, stamped := range []struct {
+		setting string
+		header  bool
+	}{{config.OutboundHeaderFields, true}, {config.OutboundBodyFields, false}} {
+		if !settings.HasSetting(stamped.setting) {
+			continue
+		}
+
+		var value string
+		if value, err = settings.Setting(stamped.setting); err != nil {
+			return
+		}
+		var fields []stampedField
+		if fields, err = parseStampedFields(value); err != nil {
+			err = IncorrectFormatForSetting{Setting: stamped.setting, Value: []byte(value), Err: err}
+			return
+		}
+		for _, field := range fields {
+			if err = s.stamps.set(field.tag, field.value, stamped.header); err != nil {
+				return
+			}
+		}
+	}
+
+	if settings.HasSetting(config.CompressedDataFields) {
+		var value string
+		if value, err = settings.Setting(config.CompressedDataFields); err != nil {
+			return
+		}
+		var pairs []dataFieldPair
+		if pairs, err = parseDataFieldPairs(value); err != nil {
+			err = IncorrectFormatForSetting{Setting: config.CompressedDataFields, Value: []byte(value), Err: err}
+			return
+		}
+
+		level := zlib.DefaultCompression
+		if settings.HasSetting(config.DataCompressionLevel) {
+			if level, err = settings.IntSetting(config.DataCompressionLevel); err != nil {
+				return
+			} else if level < zlib.HuffmanOnly || level > zlib.BestCompression {
+				err = IncorrectFormatForSetting{Setting: config.DataCompressionLevel, Value: []byte(strconv.Itoa(level))}
+				return
+			}
+		}
+		s.compression = newDataCompression(pairs, level)
+	}
+
+	if settings.HasSetting(config.EncryptedFields) {
+		var value string
+		if value, err = settings.Setting(config.EncryptedFields); err != nil {
+			return
+		}
+		var tags []Tag
+		if tags, err = parseEncryptedFields(value); err != nil {
+			err = IncorrectFormatForSetting{Setting: config.EncryptedFields, Value: []byte(value), Err: err}
+			return
+		}
+		if f.fieldCrypter == nil {
+			err = errors.New("EncryptedFields requires a Crypter, see WithFieldCrypter")
+			return
+		}
+
+		var keyID string
+		if keyID, err = settings.Setting(config.FieldEncryptionKeyID); err != nil {
+			return
+		}
+		s.fieldEncryption = &fieldEncryption{crypter: f.fieldCrypter, keyID: keyID, tags: tags}
+	}
+
+	if settings.HasSetting(config.RawMessageDecoder) {
+		var name string
+		if name, err = settings.Setting(config.RawMessageDecoder); err != nil {
+			return
+		}
+		var ok bool
+		if s.rawDecoder, ok = f.rawDecoders[name]; !ok {
+			err = IncorrectFormatForSetting{Setting: config.RawMessageDecoder, Value: []byte(name),
+				Err: errors.New("no RawMessageDecoder registered with this name, see WithRawMessageDecoder")}
+			return
+		}
+	}
+
+	var deadLetterQueue bool
+	if settings.HasSetting(config.DeadLetterQueue) {
+		if deadLetterQueue, err = settings.BoolSetting(config.DeadLetterQueue); err != nil {
+			return
+		}
+	}
+
+	var auditChain bool
+	if settings.HasSetting(config.AuditChain) {
+		if auditChain, err = settings.BoolSetting(config.AuditChain); err != nil {
+			return
+		}
+	}
+
 	if settings.HasSetting(config.PersistMessages) {
 		var persistMessages bool
 		if persistMessages, err = settings.BoolSetting(config.PersistMessages); err != nil {
@@ -431,6 +942,34 @@ func (f sessionFactory) newSession(
 		s.DisableMessagePersist = !persistMessages
 	}
 
+	var transient bool
+	if settings.HasSetting(config.StorePolicy) {
+		var policyStr string
+		if policyStr, err = settings.Setting(config.StorePolicy); err != nil {
+			return
+		}
+
+		switch policyStr {
+		case "PERSISTENT":
+		case "TRANSIENT":
+			if settings.HasSetting(config.PersistMessages) && !s.DisableMessagePersist {
+				err = errors.New("PersistMessages must not be Y with StorePolicy TRANSIENT")
+				return
+			}
+			s.DisableMessagePersist = true
+			transient = true
+		default:
+			err = IncorrectFormatForSetting{Setting: config.StorePolicy, Value: []byte(policyStr)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.ReuseIncomingMessages) {
+		if s.ReuseIncomingMessages, err = settings.BoolSetting(config.ReuseIncomingMessages); err != nil {
+			return
+		}
+	}
+
 	if settings.HasSetting(config.InChanCapacity) {
 		if s.InChanCapacity, err = settings.IntSetting(config.InChanCapacity); err != nil {
 			return
@@ -443,6 +982,25 @@ func (f sessionFactory) newSession(
 		s.InChanCapacity = 1
 	}
 
+	s.InBatchSize = 1
+	if settings.HasSetting(config.InBatchSize) {
+		if s.InBatchSize, err = settings.IntSetting(config.InBatchSize); err != nil {
+			return
+		} else if s.InBatchSize <= 0 {
+			err = IncorrectFormatForSetting{Setting: config.InBatchSize, Value: []byte(strconv.Itoa(s.InBatchSize))}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.MaxQueuedMessages) {
+		if s.MaxQueuedMessages, err = settings.IntSetting(config.MaxQueuedMessages); err != nil {
+			return
+		} else if s.MaxQueuedMessages < 0 {
+			err = IncorrectFormatForSetting{Setting: config.MaxQueuedMessages, Value: []byte(strconv.Itoa(s.MaxQueuedMessages))}
+			return
+		}
+	}
+
 	if f.BuildInitiators {
 		if err = f.buildInitiatorSettings(s, settings); err != nil {
 			return
@@ -459,6 +1017,46 @@ func (f sessionFactory) newSession(
 		return
 	}
 
+	if len(s.SessionLabels) > 0 {
+		setLabels(s.log, s.SessionLabels)
+		setLabels(s.store, s.SessionLabels)
+		s.log.OnEventf("Session labels: %v", FormatLabels(s.SessionLabels))
+	}
+
+	if epochStore, ok := s.store.(EpochStore); ok {
+		if s.epoch, err = epochStore.Epoch(); err != nil {
+			return
+		}
+	}
+
+	if deadLetterQueue {
+		var ok bool
+		if s.deadLetters, ok = deadLetterStoreOf(s.store); !ok {
+			err = errors.New("DeadLetterQueue requires a MessageStore implementing DeadLetterStore")
+			return
+		}
+	}
+
+	if auditChain {
+		var ok bool
+		if s.auditStore, ok = auditStoreOf(s.store); !ok {
+			err = errors.New("AuditChain requires a MessageStore implementing AuditStore")
+			return
+		}
+
+		var records []AuditRecord
+		if records, err = s.auditStore.AuditRecords(); err != nil {
+			return
+		}
+		if len(records) > 0 {
+			s.lastAuditHash = records[len(records)-1].Hash
+		}
+	}
+
+	if transient {
+		s.store = newTransientStore(s.store)
+	}
+
 	s.sessionEvent = make(chan internal.Event)
 	s.messageEvent = make(chan bool, 1)
 	s.admin = make(chan interface{})
@@ -499,6 +1097,65 @@ func (f sessionFactory) buildInitiatorSettings(session *Session, settings *Sessi
 		}
 	}
 
+	if settings.HasSetting(config.ReconnectBackoffBase) {
+		base, err := settings.DurationSetting(config.ReconnectBackoffBase)
+		if err != nil {
+			baseInt, err := settings.IntSetting(config.ReconnectBackoffBase)
+			if err != nil {
+				return err
+			}
+
+			session.ReconnectBackoffBase = time.Duration(baseInt) * time.Second
+		} else {
+			session.ReconnectBackoffBase = base
+		}
+
+		if session.ReconnectBackoffBase <= 0 {
+			return errors.New("ReconnectBackoffBase must be greater than zero")
+		}
+
+		session.ReconnectBackoffMax = 5 * time.Minute
+		if settings.HasSetting(config.ReconnectBackoffMax) {
+			backoffMax, err := settings.DurationSetting(config.ReconnectBackoffMax)
+			if err != nil {
+				backoffMaxInt, err := settings.IntSetting(config.ReconnectBackoffMax)
+				if err != nil {
+					return err
+				}
+
+				session.ReconnectBackoffMax = time.Duration(backoffMaxInt) * time.Second
+			} else {
+				session.ReconnectBackoffMax = backoffMax
+			}
+		}
+
+		if session.ReconnectBackoffMax < session.ReconnectBackoffBase {
+			return errors.New("ReconnectBackoffMax must not be less than ReconnectBackoffBase")
+		}
+	} else if settings.HasSetting(config.ReconnectBackoffMax) {
+		return ConditionallyRequiredSetting{Setting: config.ReconnectBackoffBase}
+	}
+
+	if settings.HasSetting(config.ReconnectJitter) {
+		jitter, err := settings.IntSetting(config.ReconnectJitter)
+		if err != nil {
+			return err
+		}
+
+		if jitter < 0 || jitter > 100 {
+			return errors.New("ReconnectJitter must be between 0 and 100")
+		}
+		session.ReconnectJitter = jitter
+	}
+
+	if settings.HasSetting(config.ReconnectGroup) {
+		group, err := settings.Setting(config.ReconnectGroup)
+		if err != nil {
+			return err
+		}
+		session.ReconnectGroup = group
+	}
+
 	session.LogoutTimeout = 2 * time.Second
 	if settings.HasSetting(config.LogoutTimeout) {
 		timeout, err := settings.DurationSetting(config.LogoutTimeout)
@@ -537,7 +1194,167 @@ func (f sessionFactory) buildInitiatorSettings(session *Session, settings *Sessi
 		}
 	}
 
-	return f.configureSocketConnectAddress(session, settings)
+	session.MaxLogonRejectRetries = -1
+	if settings.HasSetting(config.MaxLogonRejectRetries) {
+		retries, err := settings.IntSetting(config.MaxLogonRejectRetries)
+		if err != nil {
+			return err
+		}
+
+		if retries < 0 {
+			return errors.New("MaxLogonRejectRetries must not be negative")
+		}
+		session.MaxLogonRejectRetries = retries
+	}
+
+	if settings.HasSetting(config.LogonRejectLockout) {
+		if !settings.HasSetting(config.MaxLogonRejectRetries) {
+			return ConditionallyRequiredSetting{Setting: config.MaxLogonRejectRetries}
+		}
+
+		lockout, err := settings.DurationSetting(config.LogonRejectLockout)
+		if err != nil {
+			lockoutInt, err := settings.IntSetting(config.LogonRejectLockout)
+			if err != nil {
+				return err
+			}
+
+			session.LogonRejectLockout = time.Duration(lockoutInt) * time.Second
+		} else {
+			session.LogonRejectLockout = lockout
+		}
+
+		if session.LogonRejectLockout <= 0 {
+			return errors.New("LogonRejectLockout must be greater than zero")
+		}
+	}
+
+	if err := f.configureSocketConnectAddress(session, settings); err != nil {
+		return err
+	}
+
+	if settings.HasSetting(config.SocketConnectFailover) {
+		failover, err := settings.Setting(config.SocketConnectFailover)
+		if err != nil {
+			return err
+		}
+
+		switch failover {
+		case "ROUND_ROBIN":
+		case "PRIORITY":
+			session.SocketConnectPriority = true
+		default:
+			return IncorrectFormatForSetting{Setting: config.SocketConnectFailover, Value: []byte(failover)}
+		}
+	}
+
+	if settings.HasSetting(config.SocketConnectBackoffMax) {
+		backoff, err := settings.DurationSetting(config.SocketConnectBackoffMax)
+		if err != nil {
+			backoffInt, err := settings.IntSetting(config.SocketConnectBackoffMax)
+			if err != nil {
+				return err
+			}
+
+			session.SocketConnectBackoffMax = time.Duration(backoffInt) * time.Second
+		} else {
+			session.SocketConnectBackoffMax = backoff
+		}
+
+		if session.SocketConnectBackoffMax <= 0 {
+			return errors.New("SocketConnectBackoffMax must be greater than zero")
+		}
+	}
+
+	return nil
+}
+
+// configureSessionWindows sets the SessionTime of the session to the windows of the SessionWindows setting.
+func (f sessionFactory) configureSessionWindows(session *Session, settings *SessionSettings) (err error) {
+	for _, setting := range []string{config.StartTime, config.EndTime, config.StartDay, config.EndDay} {
+		if settings.HasSetting(setting) {
+			return errors.Errorf("%v cannot be specified with %v", setting, config.SessionWindows)
+		}
+	}
+
+	var windowsStr string
+	if windowsStr, err = settings.Setting(config.SessionWindows); err != nil {
+		return
+	}
+
+	var windows []internal.TimeWindow
+	for _, windowStr := range strings.Split(windowsStr, ",") {
+		startStr, endStr, ok := strings.Cut(strings.TrimSpace(windowStr), "-")
+		if !ok {
+			return IncorrectFormatForSetting{Setting: config.SessionWindows, Value: []byte(windowsStr)}
+		}
+
+		var window internal.TimeWindow
+		if window.Start, err = internal.ParseTimeOfDay(startStr); err != nil {
+			return IncorrectFormatForSetting{Setting: config.SessionWindows, Value: []byte(windowsStr), Err: err}
+		}
+		if window.End, err = internal.ParseTimeOfDay(endStr); err != nil {
+			return IncorrectFormatForSetting{Setting: config.SessionWindows, Value: []byte(windowsStr), Err: err}
+		}
+		windows = append(windows, window)
+	}
+
+	if session.TimeZone, err = parseTimeZone(settings); err != nil {
+		return
+	}
+
+	var weekdays []time.Weekday
+	if weekdays, err = parseWeekdays(settings); err != nil {
+		return
+	}
+
+	if session.SessionTime, err = internal.NewWindowRangeInLocation(windows, weekdays, session.TimeZone); err != nil {
+		return IncorrectFormatForSetting{Setting: config.SessionWindows, Value: []byte(windowsStr), Err: err}
+	}
+	return
+}
+
+// parseTimeZone returns the location of the TimeZone setting, UTC if not set.
+func parseTimeZone(settings *SessionSettings) (*time.Location, error) {
+	if !settings.HasSetting(config.TimeZone) {
+		return time.UTC, nil
+	}
+
+	locStr, err := settings.Setting(config.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(locStr)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "problem parsing time zone '%v' for setting '%v",
+			settings.settings[config.TimeZone], config.TimeZone,
+		)
+	}
+	return loc, nil
+}
+
+// parseWeekdays returns the days of the Weekdays setting, none if not set.
+func parseWeekdays(settings *SessionSettings) ([]time.Weekday, error) {
+	if !settings.HasSetting(config.Weekdays) {
+		return nil, nil
+	}
+
+	weekdaysStr, err := settings.Setting(config.Weekdays)
+	if err != nil {
+		return nil, err
+	}
+
+	var weekdays []time.Weekday
+	for _, dayStr := range strings.Split(weekdaysStr, ",") {
+		day, ok := dayLookup[dayStr]
+		if !ok {
+			return nil, IncorrectFormatForSetting{Setting: config.Weekdays, Value: []byte(weekdaysStr)}
+		}
+		weekdays = append(weekdays, day)
+	}
+	return weekdays, nil
 }
 
 func (f sessionFactory) configureSocketConnectAddress(session *Session, settings *SessionSettings) (err error) {
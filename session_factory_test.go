@@ -16,12 +16,15 @@
 package quickfix
 
 import (
+	"compress/zlib"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
 
 	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/datadictionary"
 	"github.com/quickfixgo/quickfix/internal"
 )
 
@@ -68,6 +71,8 @@ func (s *SessionFactorySuite) TestDefaults() {
 	s.Equal(120*time.Second, session.MaxLatency)
 	s.False(session.DisableMessagePersist)
 	s.False(session.HeartBtIntOverride)
+	s.False(session.AlwaysSendHeartbeats)
+	s.False(session.AllowInboundInjection)
 }
 
 func (s *SessionFactorySuite) TestResetOnLogon() {
@@ -121,6 +126,40 @@ func (s *SessionFactorySuite) TestResetOnLogout() {
 	}
 }
 
+func (s *SessionFactorySuite) TestAlwaysSendHeartbeats() {
+	var tests = []struct {
+		setting  string
+		expected bool
+	}{{"Y", true}, {"N", false}}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.SessionSettings.Set(config.AlwaysSendHeartbeats, test.setting)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+		s.NotNil(session)
+
+		s.Equal(test.expected, session.AlwaysSendHeartbeats)
+	}
+}
+
+func (s *SessionFactorySuite) TestAllowInboundInjection() {
+	var tests = []struct {
+		setting  string
+		expected bool
+	}{{"Y", true}, {"N", false}}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.SessionSettings.Set(config.AllowInboundInjection, test.setting)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+		s.NotNil(session)
+
+		s.Equal(test.expected, session.AllowInboundInjection)
+	}
+}
+
 func (s *SessionFactorySuite) TestResetOnDisconnect() {
 	var tests = []struct {
 		setting  string
@@ -426,6 +465,116 @@ func (s *SessionFactorySuite) TestStartEndDayWithWeekdaysError() {
 	s.NotNil(err)
 }
 
+func (s *SessionFactorySuite) TestSessionWindows() {
+	s.SessionSettings.Set(config.SessionWindows, "13:00:00-15:00:00, 09:00:00-11:30:00")
+	s.SessionSettings.Set(config.TimeZone, "Asia/Tokyo")
+	s.SessionSettings.Set(config.Weekdays, "Mon,Tue,Wed,Thu,Fri")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Require().NotNil(session.SessionTime)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	s.Require().Nil(err)
+	s.Equal(tokyo, session.TimeZone)
+
+	expectedRange, err := internal.NewWindowRangeInLocation([]internal.TimeWindow{
+		{Start: internal.NewTimeOfDay(9, 0, 0), End: internal.NewTimeOfDay(11, 30, 0)},
+		{Start: internal.NewTimeOfDay(13, 0, 0), End: internal.NewTimeOfDay(15, 0, 0)},
+	}, []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, tokyo)
+	s.Require().Nil(err)
+	s.Equal(*expectedRange, *session.SessionTime)
+}
+
+func (s *SessionFactorySuite) TestSessionWindowsErrors() {
+	var tests = []struct {
+		windows string
+		extra   map[string]string
+	}{
+		{"09:00:00", nil},
+		{"09:00:00-25:00:00", nil},
+		{"09:00:00-12:00:00,11:00:00-15:00:00", nil},
+		{"22:00:00-02:00:00", nil},
+		{"09:00:00-12:00:00", map[string]string{config.StartTime: "09:00:00", config.EndTime: "12:00:00"}},
+		{"09:00:00-12:00:00", map[string]string{config.StartDay: "Mon", config.EndDay: "Fri"}},
+	}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.SessionSettings.Set(config.SessionWindows, test.windows)
+		for k, v := range test.extra {
+			s.SessionSettings.Set(k, v)
+		}
+
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, test.windows)
+	}
+}
+
+func (s *SessionFactorySuite) TestHolidayCalendar() {
+	s.sessionFactory.holidays = HolidayDates{"20241225"}
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(HolidayDates{"20241225"}, session.holidays)
+}
+
+func (s *SessionFactorySuite) TestStatsHistoryMinutes() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Len(session.stats.minutes, 60)
+
+	s.SessionSettings.Set(config.StatsHistoryMinutes, "0")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Empty(session.stats.minutes)
+
+	s.SessionSettings.Set(config.StatsHistoryMinutes, "-1")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestThrottle() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Nil(session.throttle)
+
+	s.SessionSettings.Set(config.MaxMessagesPerSecond, "10")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Require().NotNil(session.throttle)
+	s.Equal(10.0, session.throttle.rate)
+	s.Equal(10.0, session.throttle.burst)
+	s.Equal(throttleQueue, session.throttle.policy)
+
+	s.SessionSettings.Set(config.BurstSize, "50")
+	s.SessionSettings.Set(config.ThrottlePolicy, "CALLBACK")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(50.0, session.throttle.burst)
+	s.Equal(50.0, session.throttle.tokens)
+	s.Equal(throttleCallback, session.throttle.policy)
+}
+
+func (s *SessionFactorySuite) TestThrottleErrors() {
+	var tests = []map[string]string{
+		{config.MaxMessagesPerSecond: "0"},
+		{config.MaxMessagesPerSecond: "fast"},
+		{config.MaxMessagesPerSecond: "10", config.BurstSize: "-1"},
+		{config.MaxMessagesPerSecond: "10", config.ThrottlePolicy: "REJECT"},
+	}
+
+	for _, test := range tests {
+		s.SetupTest()
+		for k, v := range test {
+			s.SessionSettings.Set(k, v)
+		}
+
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, test)
+	}
+}
+
 func (s *SessionFactorySuite) TestDefaultApplVerID() {
 	s.SessionID = SessionID{BeginString: BeginStringFIXT11, TargetCompID: "TW", SenderCompID: "ISLD"}
 
@@ -487,6 +636,7 @@ func (s *SessionFactorySuite) TestDuplicateSession() {
 	UnregisterSession(s.SessionID)
 	_, err = s.createSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
 	s.Nil(err)
+	UnregisterSession(s.SessionID)
 }
 
 func (s *SessionFactorySuite) TestNewSessionBuildAcceptors() {
@@ -618,6 +768,143 @@ func (s *SessionFactorySuite) TestNewSessionBuildInitiatorsValidLogonTimeout() {
 	s.NotNil(err, "LogonTimeout must be greater than zero")
 }
 
+func (s *SessionFactorySuite) TestNewSessionBuildInitiatorsLogonRejectRetries() {
+	s.sessionFactory.BuildInitiators = true
+	s.SessionSettings.Set(config.HeartBtInt, "34")
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "3000")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(-1, session.MaxLogonRejectRetries)
+	s.Zero(session.LogonRejectLockout)
+
+	s.SessionSettings.Set(config.LogonRejectLockout, "60")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "LogonRejectLockout requires MaxLogonRejectRetries")
+
+	s.SessionSettings.Set(config.MaxLogonRejectRetries, "3")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(3, session.MaxLogonRejectRetries)
+	s.Equal(60*time.Second, session.LogonRejectLockout)
+
+	s.SessionSettings.Set(config.LogonRejectLockout, "15m")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(15*time.Minute, session.LogonRejectLockout)
+
+	s.SessionSettings.Set(config.LogonRejectLockout, "0")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "LogonRejectLockout must be greater than zero")
+
+	s.SessionSettings.Set(config.LogonRejectLockout, "60")
+	s.SessionSettings.Set(config.MaxLogonRejectRetries, "-1")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "MaxLogonRejectRetries must not be negative")
+}
+
+func (s *SessionFactorySuite) TestNewSessionBuildInitiatorsReconnectBackoff() {
+	s.sessionFactory.BuildInitiators = true
+	s.SessionSettings.Set(config.HeartBtInt, "34")
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "3000")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Zero(session.ReconnectBackoffBase)
+	s.Zero(session.ReconnectBackoffMax)
+	s.Zero(session.ReconnectJitter)
+
+	s.SessionSettings.Set(config.ReconnectBackoffMax, "60")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "ReconnectBackoffMax requires ReconnectBackoffBase")
+
+	s.SessionSettings.Set(config.ReconnectBackoffBase, "500ms")
+	s.SessionSettings.Set(config.ReconnectJitter, "25")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(500*time.Millisecond, session.ReconnectBackoffBase)
+	s.Equal(60*time.Second, session.ReconnectBackoffMax)
+	s.Equal(25, session.ReconnectJitter)
+
+	s.SetupTest()
+	s.sessionFactory.BuildInitiators = true
+	s.SessionSettings.Set(config.HeartBtInt, "34")
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "3000")
+	s.SessionSettings.Set(config.ReconnectBackoffBase, "2")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(2*time.Second, session.ReconnectBackoffBase)
+	s.Equal(5*time.Minute, session.ReconnectBackoffMax)
+
+	s.SessionSettings.Set(config.ReconnectBackoffMax, "1s")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "ReconnectBackoffMax must not be less than ReconnectBackoffBase")
+
+	s.SessionSettings.Set(config.ReconnectBackoffMax, "1m")
+	s.SessionSettings.Set(config.ReconnectBackoffBase, "0")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "ReconnectBackoffBase must be greater than zero")
+
+	s.SessionSettings.Set(config.ReconnectBackoffBase, "1")
+	s.SessionSettings.Set(config.ReconnectJitter, "101")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "ReconnectJitter must be between 0 and 100")
+}
+
+func (s *SessionFactorySuite) TestNewSessionBuildInitiatorsReconnectGroup() {
+	s.sessionFactory.BuildInitiators = true
+	s.SessionSettings.Set(config.HeartBtInt, "34")
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "3000")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Empty(session.ReconnectGroup)
+
+	s.SessionSettings.Set(config.ReconnectGroup, "XNAS")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal("XNAS", session.ReconnectGroup)
+}
+
+func (s *SessionFactorySuite) TestNewSessionBuildInitiatorsSocketConnectFailover() {
+	s.sessionFactory.BuildInitiators = true
+	s.SessionSettings.Set(config.HeartBtInt, "34")
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "3000")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.False(session.SocketConnectPriority)
+	s.Zero(session.SocketConnectBackoffMax)
+
+	s.SessionSettings.Set(config.SocketConnectFailover, "PRIORITY")
+	s.SessionSettings.Set(config.SocketConnectBackoffMax, "5m")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.True(session.SocketConnectPriority)
+	s.Equal(5*time.Minute, session.SocketConnectBackoffMax)
+
+	s.SessionSettings.Set(config.SocketConnectFailover, "ROUND_ROBIN")
+	s.SessionSettings.Set(config.SocketConnectBackoffMax, "60")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.False(session.SocketConnectPriority)
+	s.Equal(60*time.Second, session.SocketConnectBackoffMax)
+
+	s.SessionSettings.Set(config.SocketConnectBackoffMax, "0")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "SocketConnectBackoffMax must be greater than zero")
+
+	s.SessionSettings.Set(config.SocketConnectBackoffMax, "60")
+	s.SessionSettings.Set(config.SocketConnectFailover, "RANDOM")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "SocketConnectFailover must be ROUND_ROBIN or PRIORITY")
+}
+
 func (s *SessionFactorySuite) TestConfigureSocketConnectAddress() {
 	sess := new(Session)
 	err := s.configureSocketConnectAddress(sess, s.SessionSettings)
@@ -709,6 +996,319 @@ func (s *SessionFactorySuite) TestNewSessionTimestampPrecision() {
 	}
 }
 
+func (s *SessionFactorySuite) TestNewSessionPossDupOrigSendingTimeCheck() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(origSendingTimeTooLow, session.origSendingTimeCheck)
+
+	s.SessionSettings.Set(config.PossDupOrigSendingTimeCheck, "blah")
+
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	var tests = []struct {
+		config   string
+		expected origSendingTimeCheck
+	}{
+		{"REJECT", origSendingTimeReject},
+		{"WARN", origSendingTimeWarn},
+		{"ACCEPT", origSendingTimeAccept},
+	}
+
+	for _, test := range tests {
+		s.SessionSettings.Set(config.PossDupOrigSendingTimeCheck, test.config)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+
+		s.Equal(test.expected, session.origSendingTimeCheck)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionCrashDump() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.crashDump)
+
+	s.SessionSettings.Set(config.CrashDumpPath, "dumps")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal("dumps", session.CrashDumpPath)
+	s.Require().NotNil(session.crashDump)
+	s.Equal(100, cap(session.crashDump.inbound.msgs))
+
+	s.SessionSettings.Set(config.CrashDumpMessageCount, "10")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(10, cap(session.crashDump.outbound.msgs))
+
+	s.SessionSettings.Set(config.CrashDumpMessageCount, "0")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestNewSessionAckTimeout() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(time.Duration(0), session.AckTimeout)
+
+	var tests = []struct {
+		config   string
+		expected time.Duration
+	}{
+		{"5", 5 * time.Second},
+		{"500ms", 500 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		s.SessionSettings.Set(config.AckTimeout, test.config)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+		s.Equal(test.expected, session.AckTimeout)
+	}
+
+	for _, invalid := range []string{"0", "-1", "blah"} {
+		s.SessionSettings.Set(config.AckTimeout, invalid)
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionResendRequestTimeout() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(time.Duration(0), session.ResendRequestTimeout, "ranges are not requested again by default")
+
+	var tests = []struct {
+		config   string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"5", 5 * time.Second},
+		{"500ms", 500 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		s.SessionSettings.Set(config.ResendRequestTimeout, test.config)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+		s.Equal(test.expected, session.ResendRequestTimeout)
+	}
+
+	for _, invalid := range []string{"-1", "-1s", "blah"} {
+		s.SessionSettings.Set(config.ResendRequestTimeout, invalid)
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionOutboundDataDictionary() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.outboundValidator)
+
+	s.SessionSettings.Set(config.OutboundDataDictionary, "spec/FIX42.xml")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.NotNil(session.outboundValidator)
+	s.NotNil(session.outboundDataDictionary)
+
+	s.SessionSettings.Set(config.OutboundDataDictionary, "spec/bogus.xml")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestNewSessionOutboundDataDictionaryFIXT() {
+	s.SessionID = SessionID{BeginString: BeginStringFIXT11, SenderCompID: "A", TargetCompID: "B"}
+	s.SessionSettings.Set(config.DefaultApplVerID, "FIX.5.0SP2")
+	s.SessionSettings.Set(config.OutboundDataDictionary, "spec/FIX50SP2.xml")
+	_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "OutboundDataDictionary requires TransportDataDictionary for FIXT")
+
+	s.SessionSettings.Set(config.TransportDataDictionary, "spec/FIXT11.xml")
+	s.SessionSettings.Set(config.AppDataDictionary, "spec/FIX50SP2.xml")
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.NotNil(session.outboundValidator)
+}
+
+func (s *SessionFactorySuite) TestNewSessionValidateOutgoingAdminMessages() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.False(session.ValidateOutgoingAdminMessages)
+
+	s.SessionSettings.Set(config.ValidateOutgoingAdminMessages, "Y")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Equal(ConditionallyRequiredSetting{Setting: config.DataDictionary}, err)
+
+	s.SessionSettings.Set(config.DataDictionary, "spec/FIX42.xml")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.True(session.ValidateOutgoingAdminMessages)
+
+	s.SessionSettings.Set(config.ValidateOutgoingAdminMessages, "blah")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestNewSessionInMemoryDataDictionary() {
+	dict, err := datadictionary.ParseFS(os.DirFS("spec"), "FIX42.xml")
+	s.Require().Nil(err)
+
+	s.SessionSettings.SetDataDictionary(config.DataDictionary, dict)
+	s.SessionSettings.SetDataDictionary(config.OutboundDataDictionary, dict)
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Same(dict, session.appDataDictionary)
+	s.Same(dict, session.outboundDataDictionary)
+	s.NotNil(session.outboundValidator)
+}
+
+func (s *SessionFactorySuite) TestNewSessionInMemoryDataDictionaryFIXT() {
+	transport, err := datadictionary.NewDictionaryBuilder("FIXT", 1, 1, 0).
+		Field(8, "BeginString", "STRING").
+		Field(10, "CheckSum", "STRING").
+		Header(datadictionary.FieldMember("BeginString", true)).
+		Trailer(datadictionary.FieldMember("CheckSum", true)).
+		Build()
+	s.Require().Nil(err)
+
+	s.SessionID = SessionID{BeginString: BeginStringFIXT11, SenderCompID: "A", TargetCompID: "B"}
+	s.SessionSettings.Set(config.DefaultApplVerID, "FIX.5.0SP2")
+	s.SessionSettings.SetDataDictionary(config.TransportDataDictionary, transport)
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "AppDataDictionary is required with TransportDataDictionary")
+
+	s.SessionSettings.Set(config.AppDataDictionary, "spec/FIX50SP2.xml")
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Same(transport, session.transportDataDictionary)
+	s.Equal(5, session.appDataDictionary.Major)
+}
+
+func (s *SessionFactorySuite) TestNewSessionSeqNumCheckpoint() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(time.Duration(0), session.SeqNumCheckpointInterval)
+	s.Equal(20, session.SeqNumDriftThreshold)
+
+	s.SessionSettings.Set(config.SeqNumDriftThreshold, "5")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "SeqNumDriftThreshold requires SeqNumCheckpointInterval")
+
+	s.SessionSettings.Set(config.SeqNumCheckpointInterval, "5m")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(5*time.Minute, session.SeqNumCheckpointInterval)
+	s.Equal(5, session.SeqNumDriftThreshold)
+
+	s.SessionSettings.Set(config.SeqNumCheckpointInterval, "30")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(30*time.Second, session.SeqNumCheckpointInterval)
+
+	for _, invalid := range []string{"0", "-1", "blah"} {
+		s.SessionSettings.Set(config.SeqNumCheckpointInterval, invalid)
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+
+	s.SessionSettings.Set(config.SeqNumCheckpointInterval, "30")
+	s.SessionSettings.Set(config.SeqNumDriftThreshold, "-1")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "SeqNumDriftThreshold must not be negative")
+}
+
+func (s *SessionFactorySuite) TestNewSessionSeqNumPublish() {
+	publisher := &recordingSeqNumPublisher{}
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.seqNumPublishing)
+
+	s.sessionFactory.seqNumPublisher = publisher
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.seqNumPublishing, "neither interval nor message count set")
+
+	s.SessionSettings.Set(config.SeqNumPublishInterval, "500ms")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(500*time.Millisecond, session.SeqNumPublishInterval)
+	s.Require().NotNil(session.seqNumPublishing)
+	s.Equal(500*time.Millisecond, session.seqNumPublishing.interval)
+
+	s.SessionSettings.Set(config.SeqNumPublishInterval, "10")
+	s.SessionSettings.Set(config.SeqNumPublishMessageCount, "50")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(10*time.Second, session.SeqNumPublishInterval)
+	s.Equal(50, session.SeqNumPublishMessageCount)
+	s.Require().NotNil(session.seqNumPublishing)
+	s.Equal(50, session.seqNumPublishing.count)
+
+	for _, invalid := range []string{"0", "-1", "blah"} {
+		s.SessionSettings.Set(config.SeqNumPublishInterval, invalid)
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+
+	s.SessionSettings.Set(config.SeqNumPublishInterval, "10")
+	for _, invalid := range []string{"0", "-1", "blah"} {
+		s.SessionSettings.Set(config.SeqNumPublishMessageCount, invalid)
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionSnapshotRate() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(100, session.SnapshotRate)
+
+	s.SessionSettings.Set(config.SnapshotRate, "25")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(25, session.SnapshotRate)
+
+	for _, invalid := range []string{"0", "-1", "blah"} {
+		s.SessionSettings.Set(config.SnapshotRate, invalid)
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionBeginStringMismatchPolicy() {
+	s.SessionSettings.Set(config.BeginStringMismatchPolicy, "blah")
+	_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.Set(config.BeginStringMismatchPolicy, "CALLBACK")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "CALLBACK requires a BeginStringMismatchHandler")
+
+	var tests = []struct {
+		config   string
+		expected BeginStringMismatchAction
+	}{
+		{"LOGOUT", BeginStringMismatchLogout},
+		{"DISCONNECT", BeginStringMismatchDisconnect},
+		{"REJECT", BeginStringMismatchReject},
+	}
+
+	for _, test := range tests {
+		s.SessionSettings.Set(config.BeginStringMismatchPolicy, test.config)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+		s.Equal(test.expected, session.beginStringMismatch.action)
+		s.Nil(session.beginStringMismatch.handler)
+	}
+
+	app := &beginStringMismatchApp{MockApp: s.App}
+	s.SessionSettings.Set(config.BeginStringMismatchPolicy, "CALLBACK")
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, app)
+	s.Nil(err)
+	s.Equal(app, session.beginStringMismatch.handler)
+}
+
 func (s *SessionFactorySuite) TestNewSessionMaxLatency() {
 	s.SessionSettings.Set(config.MaxLatency, "not a number")
 	_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
@@ -744,3 +1344,325 @@ func (s *SessionFactorySuite) TestPersistMessages() {
 		s.Equal(test.expected, session.DisableMessagePersist)
 	}
 }
+
+func (s *SessionFactorySuite) TestStorePolicy() {
+	var tests = []struct {
+		policy          string
+		persistMessages string
+		expected        bool
+		valid           bool
+	}{
+		{"PERSISTENT", "", false, true},
+		{"PERSISTENT", "N", true, true},
+		{"TRANSIENT", "", true, true},
+		{"TRANSIENT", "N", true, true},
+		{"TRANSIENT", "Y", false, false},
+		{"transient", "", false, false},
+	}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.SessionSettings.Set(config.StorePolicy, test.policy)
+		if test.persistMessages != "" {
+			s.SessionSettings.Set(config.PersistMessages, test.persistMessages)
+		}
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		if !test.valid {
+			s.NotNil(err, "%v %v", test.policy, test.persistMessages)
+			continue
+		}
+		s.Nil(err)
+		s.Equal(test.expected, session.DisableMessagePersist, "%v %v", test.policy, test.persistMessages)
+		_, transient := session.store.(*transientEpochStore)
+		s.Equal(test.policy == "TRANSIENT", transient, "%v %v", test.policy, test.persistMessages)
+	}
+}
+
+func (s *SessionFactorySuite) TestDropCopy() {
+	s.SessionSettings.Set(config.DropCopy, "Y")
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.True(session.DropCopy)
+
+	s.SessionSettings.Set(config.ApprovalMsgTypes, "D")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "ApprovalMsgTypes cannot be combined with DropCopy")
+}
+
+func (s *SessionFactorySuite) TestReuseIncomingMessages() {
+	var tests = []struct {
+		setting  string
+		expected bool
+	}{{"Y", true}, {"N", false}}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.SessionSettings.Set(config.ReuseIncomingMessages, test.setting)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+		s.NotNil(session)
+
+		s.Equal(test.expected, session.ReuseIncomingMessages)
+	}
+}
+
+func (s *SessionFactorySuite) TestInBatchSize() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(1, session.InBatchSize)
+
+	s.SessionSettings.Set(config.InBatchSize, "64")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(64, session.InBatchSize)
+
+	s.SessionSettings.Set(config.InBatchSize, "0")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestMaxQueuedMessages() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(0, session.MaxQueuedMessages)
+
+	s.SessionSettings.Set(config.MaxQueuedMessages, "1000")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(1000, session.MaxQueuedMessages)
+
+	s.SessionSettings.Set(config.MaxQueuedMessages, "-1")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+type labeledLog struct {
+	nullLog
+	labels map[string]string
+}
+
+func (l *labeledLog) SetLabels(labels map[string]string) { l.labels = labels }
+
+type labeledLogFactory struct {
+	nullLogFactory
+	log *labeledLog
+}
+
+func (f labeledLogFactory) CreateSessionLog(_ SessionID) (Log, error) { return f.log, nil }
+
+func (s *SessionFactorySuite) TestSessionLabels() {
+	s.SessionSettings.Set(config.SessionLabels, "Env=UAT, Venue=XNAS,Tenant=acme")
+	log := new(labeledLog)
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, labeledLogFactory{log: log}, s.App)
+	s.Nil(err)
+	s.NotNil(session)
+
+	expected := map[string]string{"Env": "UAT", "Venue": "XNAS", "Tenant": "acme"}
+	s.Equal(expected, session.Labels())
+	s.Equal(expected, log.labels)
+	s.Equal("Env=UAT Tenant=acme Venue=XNAS", FormatLabels(session.Labels()))
+}
+
+func (s *SessionFactorySuite) TestSessionLabelsInvalid() {
+	for _, labels := range []string{"Env", "=UAT", "Env=UAT,Env=PROD"} {
+		s.SetupTest()
+		s.SessionSettings.Set(config.SessionLabels, labels)
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, labels)
+		s.IsType(IncorrectFormatForSetting{}, err)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionStampedFields() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Empty(session.stamps.fields)
+
+	s.SessionSettings.Set(config.OutboundHeaderFields, "115=BROKER, 128=VENUE")
+	s.SessionSettings.Set(config.OutboundBodyFields, "20001=DESK1")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal([]stampedField{
+		{tag: Tag(115), value: "BROKER", header: true},
+		{tag: Tag(128), value: "VENUE", header: true},
+		{tag: Tag(20001), value: "DESK1"},
+	}, session.stamps.fields)
+
+	for _, invalid := range []string{"", "115", "115=", "x=BROKER", "0=BROKER", "34=1"} {
+		s.SessionSettings.Set(config.OutboundHeaderFields, invalid)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionCompressedDataFields() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.compression)
+
+	s.SessionSettings.Set(config.CompressedDataFields, "354:355")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Require().NotNil(session.compression)
+	s.Equal([]dataFieldPair{{lengthTag: Tag(354), dataTag: Tag(355)}}, session.compression.pairs)
+	s.Equal(zlib.DefaultCompression, session.compression.level)
+
+	s.SessionSettings.Set(config.DataCompressionLevel, "9")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(zlib.BestCompression, session.compression.level)
+
+	s.SessionSettings.Set(config.DataCompressionLevel, "10")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.Set(config.DataCompressionLevel, "9")
+	s.SessionSettings.Set(config.CompressedDataFields, "355")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestNewSessionApproval() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.ApprovalMsgTypes)
+
+	s.SessionSettings.Set(config.ApprovalTimeout, "30")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "ApprovalTimeout requires ApprovalMsgTypes")
+
+	s.SessionSettings.Set(config.ApprovalMsgTypes, "D, F")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(map[string]bool{"D": true, "F": true}, session.ApprovalMsgTypes)
+	s.Equal(30*time.Second, session.ApprovalTimeout)
+
+	s.SessionSettings.Set(config.ApprovalTimeout, "2m")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(2*time.Minute, session.ApprovalTimeout)
+
+	for _, invalid := range []string{"0", "-1s", "blah"} {
+		s.SessionSettings.Set(config.ApprovalTimeout, invalid)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+
+	s.SessionSettings.Set(config.ApprovalTimeout, "30")
+	for _, invalid := range []string{"", " , ", "D,A"} {
+		s.SessionSettings.Set(config.ApprovalMsgTypes, invalid)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionHandshakeTimeout() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(10*time.Second, session.HandshakeTimeout)
+
+	s.SessionSettings.Set(config.HandshakeTimeout, "45")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(45*time.Second, session.HandshakeTimeout)
+
+	s.SessionSettings.Set(config.HandshakeTimeout, "1m30s")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(90*time.Second, session.HandshakeTimeout)
+
+	for _, invalid := range []string{"0", "-20", "not a number"} {
+		s.SessionSettings.Set(config.HandshakeTimeout, invalid)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+}
+
+// plainStoreFactory creates MessageStores implementing none of the optional store interfaces.
+type plainStoreFactory struct{}
+
+func (plainStoreFactory) Create(sessionID SessionID) (MessageStore, error) {
+	store, err := NewMemoryStoreFactory().Create(sessionID)
+	return struct{ MessageStore }{store}, err
+}
+
+func (s *SessionFactorySuite) TestNewSessionDeadLetterQueue() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.deadLetters)
+
+	s.SessionSettings.Set(config.DeadLetterQueue, "Y")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.NotNil(session.deadLetters)
+
+	_, err = s.newSession(s.SessionID, plainStoreFactory{}, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestNewSessionEncryptedFields() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.fieldEncryption)
+
+	s.SessionSettings.Set(config.EncryptedFields, "1, 79")
+	s.SessionSettings.Set(config.FieldEncryptionKeyID, "venue")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "EncryptedFields requires a Crypter")
+
+	s.fieldCrypter = xorCrypter{}
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Require().NotNil(session.fieldEncryption)
+	s.Equal([]Tag{1, 79}, session.fieldEncryption.tags)
+	s.Equal("venue", session.fieldEncryption.keyID)
+
+	for _, invalid := range []string{"", "0", "blah", "49", "10"} {
+		s.SessionSettings.Set(config.EncryptedFields, invalid)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid)
+	}
+
+	s.SessionSettings = NewSessionSettings()
+	s.SessionSettings.Set(config.EncryptedFields, "1")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "EncryptedFields requires FieldEncryptionKeyID")
+}
+
+func (s *SessionFactorySuite) TestNewSessionRawMessageDecoder() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.rawDecoder)
+
+	s.SessionSettings.Set(config.RawMessageDecoder, "zlib")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err, "no decoder registered as zlib")
+
+	s.rawDecoders = map[string]RawMessageDecoder{"zlib": ZlibFrameDecoder{}}
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(ZlibFrameDecoder{}, session.rawDecoder)
+}
+
+func (s *SessionFactorySuite) TestNewSessionHeartbeatRTT() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(time.Duration(0), session.TestRequestInterval)
+	s.Equal(time.Duration(0), session.HeartbeatRTTThreshold)
+
+	s.SessionSettings.Set(config.TestRequestInterval, "30")
+	s.SessionSettings.Set(config.HeartbeatRTTThreshold, "500ms")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(30*time.Second, session.TestRequestInterval)
+	s.Equal(500*time.Millisecond, session.HeartbeatRTTThreshold)
+
+	for _, setting := range []string{config.TestRequestInterval, config.HeartbeatRTTThreshold} {
+		for _, invalid := range []string{"0", "-1s", "blah"} {
+			s.SessionSettings = NewSessionSettings()
+			s.SessionSettings.Set(setting, invalid)
+			_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+			s.NotNil(err, setting+"="+invalid)
+		}
+	}
+}
@@ -20,9 +20,15 @@ import (
 )
 
 // IncorrectBeginString is a message reject specific to incorrect begin strings.
-type incorrectBeginString struct{ messageRejectError }
+type incorrectBeginString struct {
+	messageRejectError
+	Received string
+	Expected string
+}
 
-func (e incorrectBeginString) Error() string { return "Incorrect BeginString" }
+func (e incorrectBeginString) Error() string {
+	return fmt.Sprintf("Incorrect BeginString, expecting %s but received %s", e.Expected, e.Received)
+}
 
 // targetTooHigh is a MessageReject where the sequence number is larger than expected.
 type targetTooHigh struct {
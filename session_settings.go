@@ -19,11 +19,16 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
 )
 
 // SessionSettings maps Session settings to values with typed accessors.
 type SessionSettings struct {
 	settings map[string][]byte
+
+	// Data dictionary settings set in memory, see SetDataDictionary.
+	dataDictionaries map[string]*datadictionary.DataDictionary
 }
 
 // ConditionallyRequiredSetting indicates a missing setting.
@@ -49,6 +54,7 @@ func (e IncorrectFormatForSetting) Error() string {
 // Init initializes or resets SessionSettings.
 func (s *SessionSettings) Init() {
 	s.settings = make(map[string][]byte)
+	s.dataDictionaries = nil
 }
 
 // NewSessionSettings returns a newly initialized SessionSettings instance.
@@ -67,6 +73,7 @@ func (s *SessionSettings) SetRaw(setting string, val []byte) {
 	}
 
 	s.settings[setting] = val
+	delete(s.dataDictionaries, setting)
 }
 
 // Set assigns a string value to a setting on SessionSettings.
@@ -77,6 +84,31 @@ func (s *SessionSettings) Set(setting string, val string) {
 	}
 
 	s.settings[setting] = []byte(val)
+	delete(s.dataDictionaries, setting)
+}
+
+// SetDataDictionary sets a data dictionary setting, one of DataDictionary, TransportDataDictionary,
+// AppDataDictionary or OutboundDataDictionary, to dict instead of the path of a dictionary file. The dictionary may
+// be built in memory with datadictionary.DictionaryBuilder, or parsed from an embed.FS with datadictionary.ParseFS.
+// It is shared by the sessions using these settings and must not be modified.
+func (s *SessionSettings) SetDataDictionary(setting string, dict *datadictionary.DataDictionary) {
+	// Lazy init.
+	if s.settings == nil {
+		s.Init()
+	}
+	if s.dataDictionaries == nil {
+		s.dataDictionaries = make(map[string]*datadictionary.DataDictionary)
+	}
+
+	s.dataDictionaries[setting] = dict
+	delete(s.settings, setting)
+}
+
+// DataDictionarySetting returns the data dictionary set with SetDataDictionary, or false if the setting is not set
+// in memory.
+func (s *SessionSettings) DataDictionarySetting(setting string) (*datadictionary.DataDictionary, bool) {
+	dict, ok := s.dataDictionaries[setting]
+	return dict, ok
 }
 
 // HasSetting returns true if a setting is set, false if not.
@@ -154,16 +186,16 @@ func (s SessionSettings) BoolSetting(setting string) (bool, error) {
 
 func (s *SessionSettings) overlay(overlay *SessionSettings) {
 	for key, val := range overlay.settings {
-		s.settings[key] = val
+		s.SetRaw(key, val)
+	}
+	for key, dict := range overlay.dataDictionaries {
+		s.SetDataDictionary(key, dict)
 	}
 }
 
 func (s *SessionSettings) clone() *SessionSettings {
 	sClone := NewSessionSettings()
-
-	for k, v := range s.settings {
-		sClone.settings[k] = v
-	}
+	sClone.overlay(s)
 
 	return sClone
 }
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/datadictionary"
 )
 
 func TestSessionSettings_StringSettings(t *testing.T) {
@@ -232,3 +233,42 @@ func TestSessionSettings_Overlay(t *testing.T) {
 		}
 	}
 }
+
+func TestSessionSettings_DataDictionary(t *testing.T) {
+	dict := &datadictionary.DataDictionary{FIXType: "FIX", Major: 4, Minor: 2}
+
+	s := NewSessionSettings()
+	s.Set(config.DataDictionary, "spec/FIX42.xml")
+	s.SetDataDictionary(config.DataDictionary, dict)
+	if s.HasSetting(config.DataDictionary) {
+		t.Error("Expected the path to be replaced by the dictionary")
+	}
+	if got, ok := s.DataDictionarySetting(config.DataDictionary); !ok || got != dict {
+		t.Errorf("Expected %v got %v", dict, got)
+	}
+
+	cloned := s.clone()
+	if got, ok := cloned.DataDictionarySetting(config.DataDictionary); !ok || got != dict {
+		t.Errorf("Expected clone to have %v got %v", dict, got)
+	}
+
+	overlay := NewSessionSettings()
+	overlay.Set(config.DataDictionary, "spec/FIX44.xml")
+	cloned.overlay(overlay)
+	if _, ok := cloned.DataDictionarySetting(config.DataDictionary); ok {
+		t.Error("Expected the dictionary to be overlaid by the path")
+	}
+	if path, _ := cloned.Setting(config.DataDictionary); path != "spec/FIX44.xml" {
+		t.Errorf("Expected spec/FIX44.xml got %v", path)
+	}
+
+	overlay = NewSessionSettings()
+	overlay.SetDataDictionary(config.DataDictionary, dict)
+	cloned.overlay(overlay)
+	if cloned.HasSetting(config.DataDictionary) {
+		t.Error("Expected the path to be overlaid by the dictionary")
+	}
+	if got, ok := cloned.DataDictionarySetting(config.DataDictionary); !ok || got != dict {
+		t.Errorf("Expected %v got %v", dict, got)
+	}
+}
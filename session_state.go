@@ -17,6 +17,7 @@ package quickfix
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/quickfixgo/quickfix/internal"
@@ -26,17 +27,30 @@ type stateMachine struct {
 	State                 sessionState
 	pendingStop, stopped  bool
 	notifyOnInSessionTime chan interface{}
+
+	// sendStatus publishes whether the application can send to the goroutines of the application, which cannot
+	// read State, pendingStop and stopped owned by the session goroutine. See checkCanSend.
+	sendStatus atomic.Int32
 }
 
+const (
+	sendStatusOK int32 = iota
+	sendStatusNotSessionTime
+	sendStatusDraining
+)
+
 func (sm *stateMachine) Start(s *Session) {
 	sm.pendingStop = false
 	sm.stopped = false
 
 	sm.State = latentState{}
-	sm.CheckSessionTime(s, time.Now())
+	sm.publishSendStatus()
+	sm.CheckSessionTime(s, s.now())
 }
 
 func (sm *stateMachine) Connect(session *Session) {
+	session.notifyConnect()
+
 	// No special logon logic needed for FIX Acceptors.
 	if !session.InitiateLogon {
 		sm.setState(session, logonState{})
@@ -51,7 +65,7 @@ func (sm *stateMachine) Connect(session *Session) {
 	}
 
 	if session.ResetOnLogon {
-		if err := session.store.Reset(); err != nil {
+		if err := session.resetStore(); err != nil {
 			session.logError(err)
 			return
 		}
@@ -62,6 +76,7 @@ func (sm *stateMachine) Connect(session *Session) {
 		session.logError(err)
 		return
 	}
+	session.metrics().LogonAttempt(session.sessionID)
 
 	sm.setState(session, logonState{})
 	// Fire logon timeout event after the pre-configured delay period.
@@ -84,20 +99,29 @@ func (sm *stateMachine) Disconnected(session *Session) {
 }
 
 func (sm *stateMachine) Incoming(session *Session, m fixIn) {
-	sm.CheckSessionTime(session, time.Now())
+	sm.CheckSessionTime(session, session.now())
 	if !sm.IsConnected() {
 		return
 	}
 
 	session.log.OnIncoming(m.bytes.Bytes())
+	session.crashDump.incoming(m.bytes.Bytes())
+	session.health.lastReceived.Store(session.now().UnixNano())
 
-	msg := NewMessage()
+	rawMessage := m.bytes.Bytes()
+	msg := session.newIncomingMessage()
 	if err := session.ParseMessage(msg, m.bytes); err != nil {
 		session.log.OnEventf("Msg Parse Error: %v, %q", err.Error(), m.bytes)
+		session.auditIncoming(rawMessage, nil, m.receiveTime)
 	} else {
+		session.auditIncoming(rawMessage, msg, m.receiveTime)
 		msg.ReceiveTime = m.receiveTime
+		session.recordIncoming(msg)
 		sm.fixMsgIn(session, msg)
+		session.acknowledged(msg)
 	}
+	session.releaseIncomingMessage(msg)
+	session.checkSeqNumPublish(session.now(), false)
 
 	session.peerTimer.Reset(time.Duration(float64(1.2) * float64(session.HeartBtInt)))
 }
@@ -107,11 +131,12 @@ func (sm *stateMachine) fixMsgIn(session *Session, m *Message) {
 }
 
 func (sm *stateMachine) SendAppMessages(session *Session) {
-	sm.CheckSessionTime(session, time.Now())
+	sm.CheckSessionTime(session, session.now())
 
 	session.sendMutex.Lock()
 	defer session.sendMutex.Unlock()
 
+	session.metrics().QueueDepth(session.sessionID, MetricsQueueOutbound, len(session.toSend))
 	if session.IsLoggedOn() {
 		session.sendQueued(false)
 	} else {
@@ -120,12 +145,18 @@ func (sm *stateMachine) SendAppMessages(session *Session) {
 }
 
 func (sm *stateMachine) Timeout(session *Session, e internal.Event) {
-	sm.CheckSessionTime(session, time.Now())
+	sm.CheckSessionTime(session, session.now())
+	if e == internal.HandshakeTimeout {
+		if nextState, ok := session.checkHandshakeTimeout(session.now()); ok {
+			sm.setState(session, nextState)
+		}
+		return
+	}
 	sm.setState(session, sm.State.Timeout(session, e))
 }
 
 func (sm *stateMachine) CheckSessionTime(session *Session, now time.Time) {
-	if !session.SessionTime.IsInRange(now) {
+	if !session.isInSessionTime(now) {
 		if sm.IsSessionTime() {
 			session.log.OnEvent("Not in Session")
 		}
@@ -191,7 +222,24 @@ func (sm *stateMachine) setState(session *Session, nextState sessionState) {
 		}
 	}
 
+	prevState := sm.State
 	sm.State = nextState
+	sm.publishSendStatus()
+	session.notifyStateChange(prevState, nextState)
+}
+
+// publishSendStatus publishes the current sendStatus, it must be called whenever State, pendingStop or stopped change.
+func (sm *stateMachine) publishSendStatus() {
+	switch {
+	case sm.State == nil:
+		sm.sendStatus.Store(sendStatusOK)
+	case sm.pendingStop || sm.stopped:
+		sm.sendStatus.Store(sendStatusDraining)
+	case !sm.State.IsSessionTime():
+		sm.sendStatus.Store(sendStatusNotSessionTime)
+	default:
+		sm.sendStatus.Store(sendStatusOK)
+	}
 }
 
 func (sm *stateMachine) notifyInSessionTime() {
@@ -215,8 +263,16 @@ func (sm *stateMachine) handleDisconnectState(s *Session) {
 
 	if doOnLogout {
 		s.application.OnLogout(s.sessionID)
+		s.notifyLogout()
+
+		for _, k := range s.killSwitches.list() {
+			k.onDisconnect(s.sessionID)
+		}
 	}
 
+	s.metrics().Disconnect(s.sessionID)
+	s.stats.add(s.now(), statDisconnects)
+	s.notifyDisconnect()
 	s.onDisconnect()
 }
 
@@ -234,6 +290,7 @@ func (sm *stateMachine) IsSessionTime() bool {
 
 func handleStateError(s *Session, err error) sessionState {
 	s.logError(err)
+	s.writeCrashDump(err.Error())
 	return latentState{}
 }
 
@@ -0,0 +1,111 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "sync"
+
+// SessionStateListener is notified of the connection and state transitions of a session, including those the
+// Application callbacks do not surface, such as resending or awaiting a TestRequest response.
+// See RegisterSessionStateListener. Callbacks are called on the session goroutine and must not block.
+type SessionStateListener interface {
+	// OnConnect is called when a connection of the session is established, before logon.
+	OnConnect(sessionID SessionID)
+
+	// OnLogon is called when the session logs on, along with Application.OnLogon.
+	OnLogon(sessionID SessionID)
+
+	// OnLogout is called when the session logs out, along with Application.OnLogout.
+	OnLogout(sessionID SessionID)
+
+	// OnDisconnect is called when the connection of the session is closed.
+	OnDisconnect(sessionID SessionID)
+
+	// OnStateChange is called when the session changes state, with the names of the states: "Latent State",
+	// "Logon State", "In Session", "Resend", "Pending Timeout", "Logout State" and "Not Session time".
+	OnStateChange(sessionID SessionID, oldState, newState string)
+}
+
+type sessionStateListeners struct {
+	sync.Mutex
+	listeners []SessionStateListener
+}
+
+// RegisterSessionStateListener adds a SessionStateListener to the Session matching the Session id.
+func RegisterSessionStateListener(sessionID SessionID, listener SessionStateListener) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.stateListeners.Lock()
+	defer session.stateListeners.Unlock()
+	session.stateListeners.listeners = append(session.stateListeners.listeners, listener)
+	return nil
+}
+
+func (l *sessionStateListeners) list() []SessionStateListener {
+	l.Lock()
+	defer l.Unlock()
+	return l.listeners
+}
+
+func (s *Session) notifyConnect() {
+	for _, l := range s.stateListeners.list() {
+		l.OnConnect(s.sessionID)
+	}
+}
+
+func (s *Session) notifyLogon() {
+	s.health.loggedOn.Store(true)
+	s.stats.add(s.now(), statLogons)
+	for _, l := range s.stateListeners.list() {
+		l.OnLogon(s.sessionID)
+	}
+	s.notifyWebhooks(WebhookLogon, "")
+}
+
+func (s *Session) notifyLogout() {
+	s.health.loggedOn.Store(false)
+	for _, l := range s.stateListeners.list() {
+		l.OnLogout(s.sessionID)
+	}
+	s.notifyWebhooks(WebhookLogout, "")
+}
+
+func (s *Session) notifyDisconnect() {
+	s.health.loggedOn.Store(false)
+	for _, l := range s.stateListeners.list() {
+		l.OnDisconnect(s.sessionID)
+	}
+	s.notifyWebhooks(WebhookDisconnect, "")
+}
+
+// notifyStateChange notifies the listeners if the state changes from oldState to newState. A state replaced by
+// another of the same kind is not a change.
+func (s *Session) notifyStateChange(oldState, newState sessionState) {
+	listeners := s.stateListeners.list()
+	if len(listeners) == 0 || oldState == nil {
+		return
+	}
+
+	oldName, newName := oldState.String(), newState.String()
+	if oldName == newName {
+		return
+	}
+	for _, l := range listeners {
+		l.OnStateChange(s.sessionID, oldName, newName)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type recordingStateListener struct {
+	events []string
+}
+
+func (l *recordingStateListener) OnConnect(SessionID)    { l.events = append(l.events, "connect") }
+func (l *recordingStateListener) OnLogon(SessionID)      { l.events = append(l.events, "logon") }
+func (l *recordingStateListener) OnLogout(SessionID)     { l.events = append(l.events, "logout") }
+func (l *recordingStateListener) OnDisconnect(SessionID) { l.events = append(l.events, "disconnect") }
+func (l *recordingStateListener) OnStateChange(_ SessionID, oldState, newState string) {
+	l.events = append(l.events, fmt.Sprintf("%v -> %v", oldState, newState))
+}
+
+type SessionStateListenerTestSuite struct {
+	SessionSuiteRig
+	listener *recordingStateListener
+}
+
+func TestSessionStateListenerTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionStateListenerTestSuite))
+}
+
+func (s *SessionStateListenerTestSuite) SetupTest() {
+	s.Init()
+	s.Require().Nil(registerSession(s.Session))
+	s.listener = &recordingStateListener{}
+	s.Require().Nil(RegisterSessionStateListener(s.sessionID, s.listener))
+}
+
+func (s *SessionStateListenerTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *SessionStateListenerTestSuite) TestUnknownSession() {
+	s.Equal(ErrSessionNotFound, RegisterSessionStateListener(SessionID{BeginString: "FIX.4.2"}, s.listener))
+}
+
+func (s *SessionStateListenerTestSuite) TestConnect() {
+	s.Session.State = latentState{}
+	s.Session.Connect(s.Session)
+
+	s.Equal([]string{"connect", "Latent State -> Logon State"}, s.listener.events)
+}
+
+func (s *SessionStateListenerTestSuite) TestPendingTimeout() {
+	s.Session.State = inSession{}
+	s.MockApp.On("ToAdmin").Return(nil)
+	s.Session.Timeout(s.Session, internal.PeerTimeout)
+	s.State(pendingTimeout{inSession{}})
+
+	s.Equal([]string{"In Session -> Pending Timeout"}, s.listener.events)
+}
+
+func (s *SessionStateListenerTestSuite) TestDisconnect() {
+	s.Session.State = inSession{}
+	s.MockApp.On("OnLogout").Return(nil)
+	s.Session.Disconnected(s.Session)
+
+	s.Equal([]string{"logout", "disconnect", "In Session -> Latent State"}, s.listener.events)
+}
+
+func (s *SessionStateListenerTestSuite) TestUnchangedState() {
+	s.Session.State = inSession{}
+	s.Session.setState(s.Session, inSession{})
+	s.Empty(s.listener.events)
+}
+
+func (s *SessionStateListenerTestSuite) TestLogon() {
+	s.Session.State = logonState{}
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogon")
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.Session, logon)
+	s.State(inSession{})
+
+	s.Equal([]string{"logon", "Logon State -> In Session"}, s.listener.events)
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/quickfixgo/quickfix/datadictionary"
 	"github.com/quickfixgo/quickfix/internal"
 
 	"github.com/stretchr/testify/require"
@@ -167,6 +168,8 @@ func (s *SessionSuite) TestCheckBeginString() {
 	err := s.Session.checkBeginString(msg)
 	s.Require().NotNil(err, "wrong begin string should return error")
 	s.IsType(incorrectBeginString{}, err)
+	s.Equal("FIX.4.4", err.(incorrectBeginString).Received)
+	s.Contains(err.Error(), "received FIX.4.4")
 
 	msg.Header.SetField(tagBeginString, FIXString(s.Session.sessionID.BeginString))
 	s.Nil(s.Session.checkBeginString(msg))
@@ -224,6 +227,34 @@ func (s *SessionSuite) TestCheckSendingTime() {
 	s.Require().Nil(err, "should skip latency check")
 }
 
+func (s *SessionSuite) TestCheckPossDupOrigSendingTime() {
+	msg := NewMessage()
+	now := time.Now()
+	msg.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: now})
+	s.Nil(s.Session.checkPossDupOrigSendingTime(msg), "not a PossDup message")
+
+	msg.Header.SetField(tagPossDupFlag, FIXBoolean(true))
+	err := s.Session.checkPossDupOrigSendingTime(msg)
+	s.Require().NotNil(err, "OrigSendingTime is required on PossDup messages")
+	s.Equal(rejectReasonRequiredTagMissing, err.RejectReason())
+
+	msg.Header.SetField(tagOrigSendingTime, FIXUTCTimestamp{Time: now.Add(time.Second)})
+	err = s.Session.checkPossDupOrigSendingTime(msg)
+	s.Require().NotNil(err, "OrigSendingTime later than SendingTime")
+	s.Equal(rejectReasonSendingTimeAccuracyProblem, err.RejectReason())
+
+	s.Session.origSendingTimeCheck = origSendingTimeWarn
+	s.Nil(s.Session.checkPossDupOrigSendingTime(msg), "should only warn")
+
+	s.Session.origSendingTimeCheck = origSendingTimeAccept
+	msg.Header.Remove(tagOrigSendingTime)
+	s.Nil(s.Session.checkPossDupOrigSendingTime(msg), "should accept")
+
+	s.Session.origSendingTimeCheck = origSendingTimeReject
+	msg.Header.SetField(tagOrigSendingTime, FIXUTCTimestamp{Time: now.Add(-time.Second)})
+	s.Nil(s.Session.checkPossDupOrigSendingTime(msg), "OrigSendingTime should be ok")
+}
+
 func (s *SessionSuite) TestCheckTargetTooLow() {
 	msg := NewMessage()
 	s.Require().Nil(s.Session.store.SetNextTargetMsgSeqNum(45))
@@ -440,6 +471,39 @@ func (s *SessionSuite) TestCheckSessionTimeNotInRange() {
 	}
 }
 
+func (s *SessionSuite) TestCheckSessionTimeOnHoliday() {
+	s.Session.State = inSession{}
+	s.IncrNextSenderMsgSeqNum()
+	s.IncrNextTargetMsgSeqNum()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	s.Require().Nil(err)
+
+	now := time.Now().UTC()
+	sessionTime, err := internal.NewUTCTimeRange(
+		internal.NewTimeOfDay(now.Add(-time.Hour).Clock()),
+		internal.NewTimeOfDay(now.Add(time.Hour).Clock()),
+		[]time.Weekday{},
+	)
+	s.Require().Nil(err)
+	s.Session.SessionTime = sessionTime
+	s.Session.TimeZone = tokyo
+
+	s.Session.holidays = HolidayDates{now.In(tokyo).AddDate(0, 0, 1).Format("20060102")}
+	s.Session.CheckSessionTime(s.Session, now)
+	s.State(inSession{})
+
+	s.Session.holidays = HolidayDates{now.In(tokyo).Format("20060102")}
+	s.MockApp.On("OnLogout")
+	s.MockApp.On("ToAdmin")
+	s.Session.CheckSessionTime(s.Session, now)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(notSessionTime{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+}
+
 func (s *SessionSuite) TestCheckSessionTimeInRangeButNotSameRangeAsStore() {
 	var tests = []struct {
 		before           sessionState
@@ -949,6 +1013,21 @@ func (suite *SessionSendTestSuite) TestSendDisableMessagePersist() {
 	suite.NextSenderMsgSeqNum(2)
 }
 
+func (suite *SessionSendTestSuite) TestSendDropCopy() {
+	suite.Session.State = inSession{}
+	suite.Session.DropCopy = true
+
+	suite.ErrorIs(suite.SendToTarget(suite.NewOrderSingle()), ErrDropCopy)
+	suite.ErrorIs(suite.SendRaw([]byte("8=FIX.4.2\0019=5\00135=D\00110=000\001"), SendRawOptions{}), ErrDropCopy)
+	suite.MockApp.AssertNotCalled(suite.T(), "ToApp")
+	suite.NoMessageSent()
+	suite.NextSenderMsgSeqNum(1)
+
+	suite.MockApp.On("ToAdmin")
+	suite.Require().Nil(suite.send(suite.Heartbeat()))
+	suite.LastToAdminMessageSent()
+}
+
 func (suite *SessionSendTestSuite) TestDropAndSendAdminMessage() {
 	suite.MockApp.On("ToAdmin")
 	suite.Require().Nil(suite.dropAndSend(suite.Heartbeat()))
@@ -1002,6 +1081,103 @@ func (suite *SessionSendTestSuite) TestDropAndSendDropsQueueWithReset() {
 	suite.NoMessageSent()
 }
 
+func (suite *SessionSendTestSuite) TestSendToTargetErrors() {
+	unknown := SessionID{BeginString: "FIX.4.2", SenderCompID: "UNKNOWN", TargetCompID: "UNKNOWN"}
+	_, err := GetSession(unknown)
+	suite.ErrorIs(err, ErrSessionNotFound)
+	suite.ErrorIs(SendToTarget(suite.NewOrderSingle(), unknown), ErrSessionNotFound)
+
+	suite.MockApp.On("OnLogout")
+	suite.Session.setState(suite.Session, notSessionTime{})
+	suite.ErrorIs(suite.SendToTarget(suite.NewOrderSingle()), ErrNotSessionTime)
+
+	suite.Session.pendingStop = true
+	suite.Session.setState(suite.Session, inSession{})
+	suite.ErrorIs(suite.SendToTarget(suite.NewOrderSingle()), ErrSessionDraining)
+	suite.NoMessageSent()
+}
+
+func (suite *SessionSendTestSuite) TestQueueForSendMissingMsgType() {
+	msg := NewMessage()
+	err := suite.queueForSend(msg)
+
+	var validationErr ErrValidation
+	suite.Require().ErrorAs(err, &validationErr)
+	suite.Equal("missing MsgType", validationErr.Details)
+}
+
+func (suite *SessionSendTestSuite) TestQueueForSendOutboundDataDictionary() {
+	dict, err := datadictionary.Parse("spec/FIX42.xml")
+	suite.Require().Nil(err)
+	suite.Session.outboundDataDictionary = dict
+	suite.Session.outboundValidator = NewValidator(defaultValidatorSettings, dict, nil)
+	suite.MockApp.On("ToApp").Return(nil)
+
+	businessReject := func() *Message {
+		msg := NewMessage()
+		msg.Header.SetField(tagMsgType, FIXString("j"))
+		msg.Body.SetField(Tag(372), FIXString("D"))
+		msg.Body.SetField(Tag(380), FIXInt(0))
+		return msg
+	}
+
+	suite.Require().Nil(suite.queueForSend(businessReject()))
+	suite.NextSenderMsgSeqNum(2)
+
+	prohibited := businessReject()
+	prohibited.Body.SetField(tagClOrdID, FIXString("order1"))
+	err = suite.queueForSend(prohibited)
+
+	var validationErr ErrValidation
+	suite.Require().ErrorAs(err, &validationErr)
+	suite.Equal("outbound data dictionary", validationErr.Details)
+	suite.NextSenderMsgSeqNum(2)
+}
+
+type outboundAdminInvalidApp struct {
+	*MockApp
+	invalid []error
+}
+
+func (a *outboundAdminInvalidApp) OnOutboundAdminInvalid(_ *Message, err error, _ SessionID) {
+	a.invalid = append(a.invalid, err)
+}
+
+func (suite *SessionSendTestSuite) TestQueueForSendValidateOutgoingAdminMessages() {
+	dict, err := datadictionary.Parse("spec/FIX42.xml")
+	suite.Require().Nil(err)
+	suite.Session.appDataDictionary = dict
+	suite.Session.Validator = NewValidator(defaultValidatorSettings, dict, nil)
+	app := &outboundAdminInvalidApp{MockApp: &suite.MockApp}
+	suite.Session.application = app
+	suite.MockApp.On("ToAdmin")
+
+	heartbeat := func() *Message {
+		msg := NewMessage()
+		msg.Header.SetField(tagMsgType, FIXString("0"))
+		msg.Body.SetField(tagClOrdID, FIXString("order1"))
+		return msg
+	}
+
+	suite.Require().Nil(suite.queueForSend(heartbeat()))
+	suite.NextSenderMsgSeqNum(2)
+
+	suite.Session.ValidateOutgoingAdminMessages = true
+	err = suite.queueForSend(heartbeat())
+
+	var validationErr ErrValidation
+	suite.Require().ErrorAs(err, &validationErr)
+	suite.Equal("outgoing admin message", validationErr.Details)
+	suite.NextSenderMsgSeqNum(2)
+	suite.Require().Len(app.invalid, 1)
+	suite.ErrorIs(app.invalid[0], err)
+
+	valid := NewMessage()
+	valid.Header.SetField(tagMsgType, FIXString("0"))
+	suite.Nil(suite.queueForSend(valid))
+	suite.NextSenderMsgSeqNum(3)
+}
+
 func (s *SessionSuite) TestSeqNumResetTime() {
 	s.MockApp.On("ToAdmin")
 	s.SetupTest()
@@ -1326,3 +1502,21 @@ func (s *SessionSuite) TestSeqNumResetTime_NanoSeconds() {
 	s.NextSenderMsgSeqNum(2)
 	s.NextTargetMsgSeqNum(1)
 }
+
+type closingLog struct {
+	nullLog
+	closed bool
+}
+
+func (l *closingLog) Close() error {
+	l.closed = true
+	return nil
+}
+
+func (s *SessionSuite) TestCloseClosesLog() {
+	log := &closingLog{}
+	s.Session.log = &encryptedLog{Log: log}
+
+	s.Nil(s.Session.close())
+	s.True(log.closed, "the log is closed through the wrappers implementing io.Closer")
+}
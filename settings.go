@@ -29,12 +29,14 @@ import (
 type Settings struct {
 	globalSettings  *SessionSettings
 	sessionSettings map[SessionID]*SessionSettings
+	profiles        map[string]*SessionSettings
 }
 
 // Init initializes or resets a Settings instance.
 func (s *Settings) Init() {
 	s.globalSettings = NewSessionSettings()
 	s.sessionSettings = make(map[SessionID]*SessionSettings)
+	s.profiles = make(map[string]*SessionSettings)
 }
 
 func (s *Settings) lazyInit() {
@@ -50,10 +52,11 @@ func NewSettings() *Settings {
 	return s
 }
 
-func sessionIDFromSessionSettings(globalSettings *SessionSettings, sessionSettings *SessionSettings) SessionID {
+// sessionIDFromSessionSettings builds the SessionID from settings overlaid in order.
+func sessionIDFromSessionSettings(overlays ...*SessionSettings) SessionID {
 	sessionID := SessionID{}
 
-	for _, settings := range []*SessionSettings{globalSettings, sessionSettings} {
+	for _, settings := range overlays {
 		if settings.HasSetting(config.BeginString) {
 			sessionID.BeginString, _ = settings.Setting(config.BeginString)
 		}
@@ -100,10 +103,14 @@ func ParseSettings(reader io.Reader) (*Settings, error) {
 	commentRegEx := regexp.MustCompile(`^#.*`)
 	defaultRegEx := regexp.MustCompile(`^\[(?i)DEFAULT\]\s*$`)
 	sessionRegEx := regexp.MustCompile(`^\[(?i)SESSION\]\s*$`)
+	profileRegEx := regexp.MustCompile(`^\[(?i:PROFILE):\s*([^\]\s]+)\s*\]\s*$`)
 	settingRegEx := regexp.MustCompile(`^([^=]*)=(.*)$`)
 
 	var settings *SessionSettings
 
+	// Sessions are added once all profiles are known.
+	var sessions []*SessionSettings
+
 	lineNumber := 0
 	for scanner.Scan() {
 		lineNumber++
@@ -117,14 +124,16 @@ func ParseSettings(reader io.Reader) (*Settings, error) {
 			settings = s.GlobalSettings()
 
 		case sessionRegEx.MatchString(line):
-			if settings != nil && settings != s.GlobalSettings() {
-				if _, err := s.AddSession(settings); err != nil {
-					return nil, err
-				}
-			}
 			settings = NewSessionSettings()
+			sessions = append(sessions, settings)
+
+		case profileRegEx.MatchString(line):
+			settings = NewSessionSettings()
+			if err := s.AddProfile(profileRegEx.FindStringSubmatch(line)[1], settings); err != nil {
+				return nil, fmt.Errorf("error parsing line %v: %w", lineNumber, err)
+			}
 
-		case settingRegEx.MatchString(line):
+		case settingRegEx.MatchString(line) && settings != nil:
 			parts := settingRegEx.FindStringSubmatch(line)
 			settings.Set(parts[1], parts[2])
 
@@ -137,12 +146,17 @@ func ParseSettings(reader io.Reader) (*Settings, error) {
 		return s, err
 	}
 
-	if settings == nil || settings == s.GlobalSettings() {
+	if len(sessions) == 0 {
 		return s, fmt.Errorf("no sessions declared")
 	}
-	_, err := s.AddSession(settings)
 
-	return s, err
+	for _, settings := range sessions {
+		if _, err := s.AddSession(settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
 }
 
 // GlobalSettings are default setting inherited by all Session settings.
@@ -156,19 +170,77 @@ func (s *Settings) SessionSettings() map[SessionID]*SessionSettings {
 	allSessionSettings := make(map[SessionID]*SessionSettings)
 
 	for sessionID, settings := range s.sessionSettings {
-		cloneSettings := s.globalSettings.clone()
-		cloneSettings.overlay(settings)
-		allSessionSettings[sessionID] = cloneSettings
+		allSessionSettings[sessionID] = s.overlaySessionSettings(settings)
 	}
 
 	return allSessionSettings
 }
 
+// overlaySessionSettings returns a clone of the global settings overlaid with the profiles of settings and settings.
+func (s *Settings) overlaySessionSettings(settings *SessionSettings) *SessionSettings {
+	cloneSettings := s.globalSettings.clone()
+	// Validated when the session was added.
+	profiles, _ := s.profileChain(settings)
+	for _, profile := range profiles {
+		cloneSettings.overlay(profile)
+	}
+	cloneSettings.overlay(settings)
+	return cloneSettings
+}
+
+// AddProfile adds named settings that sessions and other profiles inherit with the Profile setting.
+// Returns an error if a profile with the same name has already been added.
+func (s *Settings) AddProfile(name string, profileSettings *SessionSettings) error {
+	s.lazyInit()
+
+	if _, dup := s.profiles[name]; dup {
+		return fmt.Errorf("duplicate profile configured for %v", name)
+	}
+
+	s.profiles[name] = profileSettings
+	return nil
+}
+
+// profileChain returns the profiles inherited by settings, most general first. Settings without a Profile
+// inherit the Profile of the global settings, if any.
+func (s *Settings) profileChain(settings *SessionSettings) ([]*SessionSettings, error) {
+	var chain []*SessionSettings
+	seen := make(map[string]bool)
+
+	if !settings.HasSetting(config.Profile) {
+		settings = s.globalSettings
+	}
+
+	for settings.HasSetting(config.Profile) {
+		name, _ := settings.Setting(config.Profile)
+		if seen[name] {
+			return nil, fmt.Errorf("profile %v inherits from itself", name)
+		}
+		seen[name] = true
+
+		profile, ok := s.profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %v", name)
+		}
+
+		chain = append([]*SessionSettings{profile}, chain...)
+		settings = profile
+	}
+
+	return chain, nil
+}
+
 // AddSession adds Session Settings to Settings instance. Returns an error if Session settings with duplicate sessionID has already been added.
 func (s *Settings) AddSession(sessionSettings *SessionSettings) (SessionID, error) {
 	s.lazyInit()
 
-	sessionID := sessionIDFromSessionSettings(s.GlobalSettings(), sessionSettings)
+	profiles, err := s.profileChain(sessionSettings)
+	if err != nil {
+		return SessionID{}, err
+	}
+
+	overlays := append(append([]*SessionSettings{s.GlobalSettings()}, profiles...), sessionSettings)
+	sessionID := sessionIDFromSessionSettings(overlays...)
 
 	switch sessionID.BeginString {
 	case BeginStringFIX40:
@@ -189,3 +261,9 @@ func (s *Settings) AddSession(sessionSettings *SessionSettings) (SessionID, erro
 
 	return sessionID, nil
 }
+
+// removeSession removes the Session settings added for sessionID, if any.
+func (s *Settings) removeSession(sessionID SessionID) {
+	s.lazyInit()
+	delete(s.sessionSettings, sessionID)
+}
@@ -387,3 +387,112 @@ func TestSettings_SessionIDFromSessionSettings(t *testing.T) {
 		}
 	}
 }
+
+func TestSettings_ParseSettings_WithProfiles(t *testing.T) {
+	s, err := ParseSettings(strings.NewReader(`
+[DEFAULT]
+ConnectionType=initiator
+SenderCompID=TW
+HeartBtInt=60
+
+[SESSION]
+Profile=retail-fast
+TargetCompID=ISLD
+
+[SESSION]
+Profile=retail
+TargetCompID=ARCA
+StartTime=08:00:00
+
+[PROFILE:retail]
+BeginString=FIX.4.2
+HeartBtInt=30
+StartTime=12:00:00
+
+[Profile:retail-fast]
+Profile=retail
+HeartBtInt=10
+`))
+	require.Nil(t, err)
+
+	sessionSettings := s.SessionSettings()
+	require.Len(t, sessionSettings, 2)
+
+	var testCases = []struct {
+		sessionID SessionID
+		setting   string
+		expected  string
+	}{
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ARCA"}, "ConnectionType", "initiator"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ARCA"}, "HeartBtInt", "30"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ARCA"}, "StartTime", "08:00:00"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ISLD"}, "HeartBtInt", "10"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "ISLD"}, "StartTime", "12:00:00"},
+	}
+
+	for _, tc := range testCases {
+		settings, ok := sessionSettings[tc.sessionID]
+		require.True(t, ok, "No Session recalled for %v", tc.sessionID)
+		actual, err := settings.Setting(tc.setting)
+
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, actual, tc.setting)
+	}
+}
+
+func TestSettings_DefaultProfile(t *testing.T) {
+	s := NewSettings()
+	s.GlobalSettings().Set(config.Profile, "fix42")
+
+	profile := NewSessionSettings()
+	profile.Set(config.BeginString, BeginStringFIX42)
+	require.Nil(t, s.AddProfile("fix42", profile))
+
+	session := NewSessionSettings()
+	session.Set(config.SenderCompID, "TW")
+	session.Set(config.TargetCompID, "ARCA")
+	sessionID, err := s.AddSession(session)
+	require.Nil(t, err)
+	assert.Equal(t, SessionID{BeginString: BeginStringFIX42, SenderCompID: "TW", TargetCompID: "ARCA"}, sessionID)
+}
+
+func TestSettings_ProfileErrors(t *testing.T) {
+	var testCases = []struct {
+		name string
+		cfg  string
+	}{
+		{"unknown profile", `
+[SESSION]
+Profile=missing
+BeginString=FIX.4.2
+SenderCompID=TW
+TargetCompID=ARCA`},
+		{"profile cycle", `
+[PROFILE:a]
+Profile=b
+[PROFILE:b]
+Profile=a
+[SESSION]
+Profile=a
+BeginString=FIX.4.2
+SenderCompID=TW
+TargetCompID=ARCA`},
+		{"duplicate profile", `
+[PROFILE:a]
+HeartBtInt=30
+[PROFILE:a]
+HeartBtInt=10
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=TW
+TargetCompID=ARCA`},
+		{"no sessions", `
+[PROFILE:a]
+BeginString=FIX.4.2`},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseSettings(strings.NewReader(tc.cfg))
+		assert.NotNil(t, err, tc.name)
+	}
+}
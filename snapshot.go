@@ -0,0 +1,123 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "sync"
+
+// SnapshotProvider supplies application state messages, such as open order statuses or quotes, that are sent
+// after each logon. See RegisterSnapshotProvider.
+type SnapshotProvider interface {
+	// NextSnapshot returns up to limit messages following cursor, an empty cursor starting the snapshot, along with
+	// the cursor of the next call. done reports that the snapshot is complete.
+	NextSnapshot(sessionID SessionID, cursor string, limit int) (msgs []*Message, next string, done bool, err error)
+}
+
+// snapshotReplay is the progress of a SnapshotProvider. The cursor of an interrupted replay is kept so that
+// the replay resumes where it left off on the next logon.
+type snapshotReplay struct {
+	provider SnapshotProvider
+	cursor   string
+	active   bool
+}
+
+type snapshotReplays struct {
+	sync.Mutex
+	replays []*snapshotReplay
+}
+
+// RegisterSnapshotProvider adds a SnapshotProvider to the Session matching the Session id. After each logon its
+// messages are sent at no more than SnapshotRate messages per second, and only while no other application messages
+// are waiting to be sent.
+func RegisterSnapshotProvider(sessionID SessionID, provider SnapshotProvider) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.snapshots.Lock()
+	defer session.snapshots.Unlock()
+	session.snapshots.replays = append(session.snapshots.replays, &snapshotReplay{provider: provider})
+	return nil
+}
+
+// start activates all snapshot replays, called on logon.
+func (r *snapshotReplays) start() {
+	r.Lock()
+	defer r.Unlock()
+	for _, replay := range r.replays {
+		replay.active = true
+	}
+}
+
+// pending returns the number of snapshot replays not complete.
+func (r *snapshotReplays) pending() (n int) {
+	r.Lock()
+	defer r.Unlock()
+	for _, replay := range r.replays {
+		if replay.active {
+			n++
+		}
+	}
+	return
+}
+
+// replaySnapshots sends the next snapshot messages within the per second budget of SnapshotRate.
+func (s *Session) replaySnapshots() {
+	if !s.IsLoggedOn() {
+		return
+	}
+
+	s.sendMutex.Lock()
+	queued := len(s.toSend)
+	s.sendMutex.Unlock()
+	if queued > 0 {
+		return
+	}
+
+	s.snapshots.Lock()
+	defer s.snapshots.Unlock()
+
+	budget := s.SnapshotRate
+	for _, replay := range s.snapshots.replays {
+		if !replay.active {
+			continue
+		}
+		if budget <= 0 {
+			return
+		}
+
+		msgs, next, done, err := replay.provider.NextSnapshot(s.sessionID, replay.cursor, budget)
+		if err != nil {
+			s.log.OnEventf("Snapshot failed: %v", err)
+			continue
+		}
+
+		for _, msg := range msgs {
+			if err := s.send(msg); err != nil {
+				s.log.OnEventf("Snapshot failed: %v", err)
+				return
+			}
+		}
+		budget -= len(msgs)
+		replay.cursor = next
+
+		if done {
+			replay.active = false
+			replay.cursor = ""
+			s.log.OnEvent("Snapshot complete")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// sliceSnapshot serves numbered messages, using the index of the next message as cursor.
+type sliceSnapshot struct {
+	count  int
+	limits []int
+}
+
+func (p *sliceSnapshot) NextSnapshot(_ SessionID, cursor string, limit int) ([]*Message, string, bool, error) {
+	p.limits = append(p.limits, limit)
+	start := 0
+	if cursor != "" {
+		start, _ = strconv.Atoi(cursor)
+	}
+
+	var msgs []*Message
+	i := start
+	for ; i < p.count && len(msgs) < limit; i++ {
+		msg := NewMessage()
+		msg.Header.SetField(tagMsgType, FIXString("8"))
+		msg.Body.SetField(tagClOrdID, FIXString(strconv.Itoa(i)))
+		msgs = append(msgs, msg)
+	}
+	return msgs, strconv.Itoa(i), i == p.count, nil
+}
+
+type SnapshotTestSuite struct {
+	SessionSuiteRig
+	provider *sliceSnapshot
+}
+
+func TestSnapshotTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.SnapshotRate = 2
+	s.provider = &sliceSnapshot{count: 3}
+	s.Session.snapshots.replays = []*snapshotReplay{{provider: s.provider}}
+	s.MockApp.On("ToApp").Return(nil)
+}
+
+func (s *SnapshotTestSuite) TestReplayThrottled() {
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNotCalled(s.T(), "ToApp", "not started before logon")
+
+	s.Session.snapshots.start()
+	s.Equal(1, s.Session.status().PendingSnapshots)
+
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 2)
+	s.Equal(1, s.Session.status().PendingSnapshots)
+
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 3)
+	s.FieldEquals(tagClOrdID, "2", s.MockApp.lastToApp.Body)
+	s.Equal(0, s.Session.status().PendingSnapshots)
+
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 3)
+}
+
+func (s *SnapshotTestSuite) TestReplayResumes() {
+	s.Session.snapshots.start()
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 2)
+
+	s.Session.State = latentState{}
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 2)
+
+	s.Session.State = inSession{}
+	s.Session.snapshots.start()
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 3)
+	s.FieldEquals(tagClOrdID, "2", s.MockApp.lastToApp.Body)
+
+	// A completed snapshot starts over on the next logon.
+	s.Session.snapshots.start()
+	s.Session.replaySnapshots()
+	s.FieldEquals(tagClOrdID, "1", s.MockApp.lastToApp.Body)
+}
+
+func (s *SnapshotTestSuite) TestReplayYieldsToQueuedMessages() {
+	s.Session.snapshots.start()
+	s.Session.toSend = [][]byte{[]byte("queued")}
+
+	s.Session.replaySnapshots()
+	s.MockApp.AssertNotCalled(s.T(), "ToApp")
+	s.Empty(s.provider.limits)
+}
+
+func (s *SnapshotTestSuite) TestRegisterSnapshotProviderUnknownSession() {
+	s.ErrorIs(RegisterSnapshotProvider(SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}, s.provider), ErrSessionNotFound)
+}
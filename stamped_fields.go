@@ -0,0 +1,174 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldStamper computes fields stamped on every outbound message of a session, see SetFieldStamper. It is called
+// before the Application's ToAdmin or ToApp callback.
+type FieldStamper func(msg *Message, sessionID SessionID)
+
+// managedTags are set by the session and cannot be stamped.
+var managedTags = map[Tag]bool{
+	tagBeginString:     true,
+	tagBodyLength:      true,
+	tagMsgType:         true,
+	tagMsgSeqNum:       true,
+	tagSenderCompID:    true,
+	tagTargetCompID:    true,
+	tagSendingTime:     true,
+	tagPossDupFlag:     true,
+	tagOrigSendingTime: true,
+	tagCheckSum:        true,
+}
+
+type stampedField struct {
+	tag    Tag
+	value  string
+	header bool
+}
+
+// fieldStamps are the fields stamped on every outbound message of a session.
+type fieldStamps struct {
+	mu      sync.RWMutex
+	fields  []stampedField
+	stamper FieldStamper
+}
+
+// set stamps the field tag with value, replacing any previous stamp of tag.
+func (f *fieldStamps) set(tag Tag, value string, header bool) error {
+	if tag <= 0 || managedTags[tag] {
+		return fmt.Errorf("tag %v cannot be stamped", tag)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.removeLocked(tag)
+	f.fields = append(f.fields, stampedField{tag: tag, value: value, header: header})
+	return nil
+}
+
+func (f *fieldStamps) removeLocked(tag Tag) {
+	for i, field := range f.fields {
+		if field.tag == tag {
+			f.fields = append(f.fields[:i:i], f.fields[i+1:]...)
+			return
+		}
+	}
+}
+
+// apply stamps the fields missing from msg, then calls the FieldStamper.
+func (f *fieldStamps) apply(msg *Message, sessionID SessionID) {
+	f.mu.RLock()
+	fields, stamper := f.fields, f.stamper
+	f.mu.RUnlock()
+
+	for _, field := range fields {
+		fieldMap := &msg.Body.FieldMap
+		if field.header {
+			fieldMap = &msg.Header.FieldMap
+		}
+		if !fieldMap.Has(field.tag) {
+			fieldMap.SetString(field.tag, field.value)
+		}
+	}
+
+	if stamper != nil {
+		stamper(msg, sessionID)
+	}
+}
+
+// parseStampedFields parses a comma delimited list of tag=value pairs.
+func parseStampedFields(value string) ([]stampedField, error) {
+	var fields []stampedField
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		tagStr, fieldValue, ok := strings.Cut(pair, "=")
+		if !ok || fieldValue == "" {
+			return nil, fmt.Errorf("%q is not a tag=value pair", pair)
+		}
+		tag, err := strconv.Atoi(strings.TrimSpace(tagStr))
+		if err != nil || tag <= 0 {
+			return nil, fmt.Errorf("invalid tag in %q", pair)
+		}
+		if managedTags[Tag(tag)] {
+			return nil, fmt.Errorf("tag %v cannot be stamped", tag)
+		}
+		fields = append(fields, stampedField{tag: Tag(tag), value: fieldValue})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no tag=value pair")
+	}
+	return fields, nil
+}
+
+// StampHeaderField stamps the header field tag with value on every outbound message of the Session matching the
+// Session id, unless the message already has the field. Fields managed by the session, such as MsgSeqNum, cannot be
+// stamped.
+func StampHeaderField(sessionID SessionID, tag Tag, value string) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return session.stamps.set(tag, value, true)
+}
+
+// StampBodyField stamps the body field tag with value on every outbound message of the Session matching the
+// Session id, unless the message already has the field.
+func StampBodyField(sessionID SessionID, tag Tag, value string) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return session.stamps.set(tag, value, false)
+}
+
+// RemoveStampedField stops stamping the field tag, set by StampHeaderField, StampBodyField or the session settings.
+func RemoveStampedField(sessionID SessionID, tag Tag) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.stamps.mu.Lock()
+	defer session.stamps.mu.Unlock()
+	session.stamps.removeLocked(tag)
+	return nil
+}
+
+// SetFieldStamper sets the FieldStamper of the Session matching the Session id, called after the fixed fields are
+// stamped. A nil stamper removes it.
+func SetFieldStamper(sessionID SessionID, stamper FieldStamper) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.stamps.mu.Lock()
+	defer session.stamps.mu.Unlock()
+	session.stamps.stamper = stamper
+	return nil
+}
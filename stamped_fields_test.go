@@ -0,0 +1,112 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StampedFieldsTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestStampedFieldsTestSuite(t *testing.T) {
+	suite.Run(t, new(StampedFieldsTestSuite))
+}
+
+func (s *StampedFieldsTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *StampedFieldsTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *StampedFieldsTestSuite) TestStampFields() {
+	s.Require().Nil(StampHeaderField(s.sessionID, tagOnBehalfOfCompID, "BROKER"))
+	s.Require().Nil(StampBodyField(s.sessionID, Tag(20001), "DESK1"))
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.FieldEquals(tagOnBehalfOfCompID, "BROKER", s.MockApp.lastToApp.Header)
+	s.FieldEquals(Tag(20001), "DESK1", s.MockApp.lastToApp.Body)
+	s.LastToAppMessageSent()
+
+	// Admin messages are stamped too.
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(s.Session.send(s.Heartbeat()))
+	s.FieldEquals(tagOnBehalfOfCompID, "BROKER", s.MockApp.lastToAdmin.Header)
+}
+
+func (s *StampedFieldsTestSuite) TestStampDoesNotOverride() {
+	s.Require().Nil(StampHeaderField(s.sessionID, tagOnBehalfOfCompID, "BROKER"))
+	s.Require().Nil(StampHeaderField(s.sessionID, tagOnBehalfOfCompID, "OTHER"))
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.FieldEquals(tagOnBehalfOfCompID, "OTHER", s.MockApp.lastToApp.Header)
+
+	msg := s.NewOrderSingle()
+	msg.Header.SetString(tagOnBehalfOfCompID, "EXPLICIT")
+	s.Require().Nil(s.Session.send(msg))
+	s.FieldEquals(tagOnBehalfOfCompID, "EXPLICIT", s.MockApp.lastToApp.Header)
+}
+
+func (s *StampedFieldsTestSuite) TestRemoveStampedField() {
+	s.Require().Nil(StampHeaderField(s.sessionID, tagOnBehalfOfCompID, "BROKER"))
+	s.Require().Nil(RemoveStampedField(s.sessionID, tagOnBehalfOfCompID))
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.False(s.MockApp.lastToApp.Header.Has(tagOnBehalfOfCompID))
+}
+
+func (s *StampedFieldsTestSuite) TestFieldStamper() {
+	count := 0
+	s.Require().Nil(SetFieldStamper(s.sessionID, func(msg *Message, sessionID SessionID) {
+		s.Equal(s.sessionID, sessionID)
+		count++
+		msg.Body.SetInt(Tag(20002), count)
+	}))
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.FieldEquals(Tag(20002), 1, s.MockApp.lastToApp.Body)
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.FieldEquals(Tag(20002), 2, s.MockApp.lastToApp.Body)
+
+	s.Require().Nil(SetFieldStamper(s.sessionID, nil))
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+	s.False(s.MockApp.lastToApp.Body.Has(Tag(20002)))
+}
+
+func (s *StampedFieldsTestSuite) TestManagedTagsNotStamped() {
+	s.NotNil(StampHeaderField(s.sessionID, tagMsgSeqNum, "1"))
+	s.NotNil(StampHeaderField(s.sessionID, tagSenderCompID, "X"))
+	s.NotNil(StampBodyField(s.sessionID, Tag(0), "X"))
+}
+
+func (s *StampedFieldsTestSuite) TestUnknownSession() {
+	other := SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}
+	s.Equal(ErrSessionNotFound, StampHeaderField(other, tagOnBehalfOfCompID, "BROKER"))
+	s.Equal(ErrSessionNotFound, StampBodyField(other, Tag(20001), "DESK1"))
+	s.Equal(ErrSessionNotFound, RemoveStampedField(other, Tag(20001)))
+	s.Equal(ErrSessionNotFound, SetFieldStamper(other, nil))
+}
@@ -0,0 +1,179 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MinuteStats are the counters of a session during one minute, see GetStatsHistory.
+type MinuteStats struct {
+	// Minute is the start of the minute, in UTC.
+	Minute time.Time `json:"minute"`
+
+	MessagesIn  int `json:"messages_in"`
+	MessagesOut int `json:"messages_out"`
+
+	// Rejects counts the Reject and BusinessMessageReject messages sent and received.
+	Rejects int `json:"rejects"`
+
+	// Gaps counts the sequence gaps detected in the inbound messages.
+	Gaps int `json:"gaps"`
+
+	Logons      int `json:"logons"`
+	Disconnects int `json:"disconnects"`
+}
+
+// SessionStatsHistory is the history of a session, as served by StatsHistoryHandler.
+type SessionStatsHistory struct {
+	SessionID string        `json:"session_id"`
+	Minutes   []MinuteStats `json:"minutes"`
+}
+
+// GetStatsHistory returns the per minute counters of the Session matching the Session id over the last
+// StatsHistoryMinutes minutes, oldest first and up to the current minute. Minutes without activity are included
+// with zero counters, minutes before the session was created are not.
+func GetStatsHistory(sessionID SessionID) ([]MinuteStats, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return session.stats.history(session.now()), nil
+}
+
+// StatsHistoryHandler returns a handler serving the GetStatsHistory of the sessions as a JSON array of
+// SessionStatsHistory, for support tools. The session query parameter, a SessionID as formatted by its String,
+// selects a single session; all the registered sessions are served otherwise.
+func StatsHistoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selected := r.URL.Query().Get("session")
+
+		histories := []SessionStatsHistory{}
+		for _, sessionID := range FindSessionIDs(nil) {
+			if selected != "" && sessionID.String() != selected {
+				continue
+			}
+			if minutes, err := GetStatsHistory(sessionID); err == nil {
+				histories = append(histories, SessionStatsHistory{SessionID: sessionID.String(), Minutes: minutes})
+			}
+		}
+
+		if selected != "" && len(histories) == 0 {
+			http.Error(w, ErrSessionNotFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(histories)
+	})
+}
+
+type statCounter int
+
+const (
+	statMessagesIn statCounter = iota
+	statMessagesOut
+	statRejects
+	statGaps
+	statLogons
+	statDisconnects
+)
+
+// statsHistory is a ring of the MinuteStats of a session, see StatsHistoryMinutes. The zero value keeps no history.
+type statsHistory struct {
+	sync.Mutex
+	minutes []MinuteStats
+
+	// Index of the current minute in minutes, and the number of minutes recorded.
+	head, count int
+}
+
+func newStatsHistory(minutes int) statsHistory {
+	return statsHistory{minutes: make([]MinuteStats, minutes)}
+}
+
+// add increments the counter of the minute of now.
+func (h *statsHistory) add(now time.Time, counter statCounter) {
+	if len(h.minutes) == 0 {
+		return
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	current := h.advance(now)
+	switch counter {
+	case statMessagesIn:
+		current.MessagesIn++
+	case statMessagesOut:
+		current.MessagesOut++
+	case statRejects:
+		current.Rejects++
+	case statGaps:
+		current.Gaps++
+	case statLogons:
+		current.Logons++
+	case statDisconnects:
+		current.Disconnects++
+	}
+}
+
+// history returns the recorded minutes, oldest first, up to the minute of now.
+func (h *statsHistory) history(now time.Time) []MinuteStats {
+	if len(h.minutes) == 0 {
+		return nil
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.advance(now)
+	history := make([]MinuteStats, 0, h.count)
+	for i := h.count - 1; i >= 0; i-- {
+		history = append(history, h.minutes[(h.head-i+len(h.minutes))%len(h.minutes)])
+	}
+	return history
+}
+
+// advance moves the ring to the minute of now, starting a bucket for each minute elapsed, and returns the bucket
+// of the current minute. A clock moving backwards counts in the current minute. The caller holds the lock.
+func (h *statsHistory) advance(now time.Time) *MinuteStats {
+	minute := now.UTC().Truncate(time.Minute)
+	if h.count == 0 {
+		h.head, h.count = 0, 1
+		h.minutes[0] = MinuteStats{Minute: minute}
+		return &h.minutes[0]
+	}
+
+	last := h.minutes[h.head].Minute
+	elapsed := int(minute.Sub(last) / time.Minute)
+	if elapsed > len(h.minutes) {
+		// Only the most recent minutes fit in the ring.
+		elapsed = len(h.minutes)
+		last = minute.Add(-time.Duration(elapsed) * time.Minute)
+	}
+	for i := 1; i <= elapsed; i++ {
+		h.head = (h.head + 1) % len(h.minutes)
+		h.minutes[h.head] = MinuteStats{Minute: last.Add(time.Duration(i) * time.Minute)}
+		if h.count < len(h.minutes) {
+			h.count++
+		}
+	}
+	return &h.minutes[h.head]
+}
@@ -0,0 +1,131 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestStatsHistoryRing(t *testing.T) {
+	start := time.Date(2024, 3, 1, 14, 30, 10, 0, time.UTC)
+	h := newStatsHistory(3)
+
+	h.add(start, statMessagesIn)
+	h.add(start.Add(20*time.Second), statMessagesIn)
+	h.add(start.Add(time.Minute), statMessagesOut)
+	h.add(start.Add(-time.Hour), statRejects)
+
+	history := h.history(start.Add(time.Minute))
+	assert.Equal(t, []MinuteStats{
+		{Minute: start.Truncate(time.Minute), MessagesIn: 2},
+		{Minute: start.Truncate(time.Minute).Add(time.Minute), MessagesOut: 1, Rejects: 1},
+	}, history, "a clock moving backwards counts in the current minute")
+
+	history = h.history(start.Add(3 * time.Minute))
+	assert.Len(t, history, 3, "the ring keeps the last minutes")
+	assert.Equal(t, start.Truncate(time.Minute).Add(time.Minute), history[0].Minute)
+	assert.Equal(t, 1, history[0].MessagesOut)
+	assert.Equal(t, MinuteStats{Minute: start.Truncate(time.Minute).Add(3 * time.Minute)}, history[2])
+
+	h.add(start.Add(time.Hour), statDisconnects)
+	history = h.history(start.Add(time.Hour))
+	assert.Len(t, history, 3)
+	assert.Equal(t, start.Truncate(time.Minute).Add(58*time.Minute), history[0].Minute)
+	assert.Equal(t, MinuteStats{Minute: start.Truncate(time.Minute).Add(time.Hour), Disconnects: 1}, history[2])
+
+	var disabled statsHistory
+	disabled.add(start, statMessagesIn)
+	assert.Nil(t, disabled.history(start))
+}
+
+type StatsHistoryTestSuite struct {
+	SessionSuiteRig
+	now time.Time
+}
+
+func TestStatsHistoryTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsHistoryTestSuite))
+}
+
+func (s *StatsHistoryTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.stats = newStatsHistory(60)
+	s.now = time.Date(2024, 3, 1, 14, 32, 0, 0, time.UTC)
+	s.Session.clock = fixedClock{s.now}
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *StatsHistoryTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *StatsHistoryTestSuite) TestCounters() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.Session.notifyLogon()
+
+	order := s.NewOrderSingle()
+	order.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: s.now})
+	s.Session.Incoming(s.Session, fixIn{bytes: bytes.NewBuffer(order.Build()), receiveTime: s.now})
+	s.Require().Nil(s.Session.send(s.NewOrderSingle()))
+
+	s.Session.clock = fixedClock{s.now.Add(time.Minute)}
+	gap := s.NewOrderSingle()
+	gap.Header.SetField(tagMsgSeqNum, FIXInt(5))
+	gap.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: s.now})
+	s.Session.Incoming(s.Session, fixIn{bytes: bytes.NewBuffer(gap.Build()), receiveTime: s.now})
+
+	history, err := GetStatsHistory(s.sessionID)
+	s.Require().Nil(err)
+	s.Equal([]MinuteStats{
+		{Minute: s.now, MessagesIn: 1, MessagesOut: 1, Logons: 1},
+		{Minute: s.now.Add(time.Minute), MessagesIn: 1, MessagesOut: 1, Gaps: 1},
+	}, history)
+
+	_, err = GetStatsHistory(SessionID{BeginString: "FIX.4.2"})
+	s.Equal(ErrSessionNotFound, err)
+}
+
+func (s *StatsHistoryTestSuite) TestHandler() {
+	s.Session.notifyLogon()
+
+	rec := httptest.NewRecorder()
+	StatsHistoryHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?session="+url.QueryEscape(s.sessionID.String()), nil))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("application/json", rec.Header().Get("Content-Type"))
+
+	var histories []SessionStatsHistory
+	s.Require().Nil(json.NewDecoder(rec.Body).Decode(&histories))
+	s.Equal([]SessionStatsHistory{{
+		SessionID: s.sessionID.String(),
+		Minutes:   []MinuteStats{{Minute: s.now, Logons: 1}},
+	}}, histories)
+
+	rec = httptest.NewRecorder()
+	StatsHistoryHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?session=unknown", nil))
+	s.Equal(http.StatusNotFound, rec.Code)
+}
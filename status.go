@@ -0,0 +1,58 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// SessionStatus is a point in time snapshot of a Session.
+type SessionStatus struct {
+	SessionID           SessionID
+	NextSenderMsgSeqNum int
+	NextTargetMsgSeqNum int
+
+	// OutstandingResendRanges lists the ranges requested from the counterparty with a ResendRequest
+	// that have not been received yet, lowest first.
+	OutstandingResendRanges []SeqNumRange
+
+	// UnacknowledgedMessages is the number of outbound order messages awaiting a response, see AckTimeout.
+	UnacknowledgedMessages int
+
+	// PendingSnapshots is the number of registered SnapshotProviders whose snapshot has not been sent since logon.
+	PendingSnapshots int
+
+	// HeldMessages is the number of outbound messages awaiting approval, see ApprovalMsgTypes.
+	HeldMessages int
+}
+
+// GetSessionStatus returns the status of the Session matching the Session id.
+func GetSessionStatus(sessionID SessionID) (SessionStatus, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return SessionStatus{}, ErrSessionNotFound
+	}
+	return session.status(), nil
+}
+
+func (s *Session) status() SessionStatus {
+	nextTarget := s.store.NextTargetMsgSeqNum()
+	return SessionStatus{
+		SessionID:               s.sessionID,
+		NextSenderMsgSeqNum:     s.store.NextSenderMsgSeqNum(),
+		NextTargetMsgSeqNum:     nextTarget,
+		OutstandingResendRanges: s.resendRanges.outstanding(nextTarget),
+		UnacknowledgedMessages:  s.acks.len(),
+		PendingSnapshots:        s.snapshots.pending(),
+		HeldMessages:            s.held.len(),
+	}
+}
@@ -44,6 +44,25 @@ type MessageStore interface {
 	Close() error
 }
 
+// EpochStore is an optional interface implemented by a MessageStore to fence off engine instances
+// once the session has been resumed elsewhere with ImportResumptionToken, which requires it.
+// Stores shared between engine instances should read the epoch through to their backing storage, and keep it
+// across Reset.
+type EpochStore interface {
+	// Epoch returns the current ownership epoch of the session.
+	Epoch() (uint64, error)
+
+	// CompareAndSwapEpoch sets the epoch to next only if it currently is old, reporting whether it did so.
+	CompareAndSwapEpoch(old, next uint64) (bool, error)
+}
+
+// FlushStore is an optional interface implemented by a MessageStore buffering writes, such as the FileStore with
+// FileStoreSyncInterval set.
+type FlushStore interface {
+	// Flush writes the buffered messages and sequence numbers to the backing storage.
+	Flush() error
+}
+
 // The MessageStoreFactory interface is used by Session to create a Session specific message store.
 type MessageStoreFactory interface {
 	Create(sessionID SessionID) (MessageStore, error)
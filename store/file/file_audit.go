@@ -0,0 +1,95 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/quickfixgo/quickfix"
+)
+
+// auditFname is the file of the audit hash chain, one JSON record per line. It is not removed by Reset.
+func (store *fileStore) auditFname() string {
+	return path.Join(store.dirname, fmt.Sprintf("%s.%s", store.sessionPrefix, "audit"))
+}
+
+// SaveAuditRecord appends record to the audit file, kept open as a record is saved for every inbound message.
+func (store *fileStore) SaveAuditRecord(record quickfix.AuditRecord) error {
+	store.auditMu.Lock()
+	defer store.auditMu.Unlock()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "marshal audit record")
+	}
+
+	if store.auditFile == nil {
+		if store.auditFile, err = os.OpenFile(store.auditFname(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660); err != nil {
+			return err
+		}
+	}
+
+	if _, err = store.auditFile.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("unable to write to file: %s: %s", store.auditFname(), err.Error())
+	}
+	if store.fileSync {
+		if err = store.auditFile.Sync(); err != nil {
+			return fmt.Errorf("unable to flush file: %s: %s", store.auditFname(), err.Error())
+		}
+	}
+	return nil
+}
+
+// AuditRecords returns the audit hash chain of the file, oldest first.
+func (store *fileStore) AuditRecords() ([]quickfix.AuditRecord, error) {
+	store.auditMu.Lock()
+	defer store.auditMu.Unlock()
+
+	f, err := os.Open(store.auditFname())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []quickfix.AuditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var record quickfix.AuditRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse audit record in %s", store.auditFname())
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// closeAuditFile closes the audit file, if open.
+func (store *fileStore) closeAuditFile() error {
+	store.auditMu.Lock()
+	defer store.auditMu.Unlock()
+
+	err := closeSyncFile(store.auditFile)
+	store.auditFile = nil
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/quickfixgo/quickfix"
+)
+
+// deadLettersFname is the file of the dead letters, one JSON object per line. It is not removed by Reset.
+func (store *fileStore) deadLettersFname() string {
+	return path.Join(store.dirname, fmt.Sprintf("%s.%s", store.sessionPrefix, "deadletters"))
+}
+
+// SaveDeadLetter appends letter to the dead letters file.
+func (store *fileStore) SaveDeadLetter(letter quickfix.DeadLetter) (int, error) {
+	store.deadLettersMu.Lock()
+	defer store.deadLettersMu.Unlock()
+
+	letters, err := store.readDeadLettersLocked()
+	if err != nil {
+		return 0, err
+	}
+	letter.ID = 1
+	if len(letters) > 0 {
+		letter.ID = letters[len(letters)-1].ID + 1
+	}
+
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return 0, errors.Wrap(err, "marshal dead letter")
+	}
+
+	f, err := os.OpenFile(store.deadLettersFname(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return 0, fmt.Errorf("unable to write to file: %s: %s", store.deadLettersFname(), err.Error())
+	}
+	if store.fileSync {
+		if err = f.Sync(); err != nil {
+			return 0, fmt.Errorf("unable to flush file: %s: %s", store.deadLettersFname(), err.Error())
+		}
+	}
+	return letter.ID, nil
+}
+
+// DeadLetters returns the dead letters of the file, oldest first.
+func (store *fileStore) DeadLetters() ([]quickfix.DeadLetter, error) {
+	store.deadLettersMu.Lock()
+	defer store.deadLettersMu.Unlock()
+	return store.readDeadLettersLocked()
+}
+
+// DeleteDeadLetter rewrites the dead letters file without the dead letter id.
+func (store *fileStore) DeleteDeadLetter(id int) error {
+	store.deadLettersMu.Lock()
+	defer store.deadLettersMu.Unlock()
+
+	letters, err := store.readDeadLettersLocked()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	found := false
+	for _, letter := range letters {
+		if letter.ID == id {
+			found = true
+			continue
+		}
+		if err = enc.Encode(letter); err != nil {
+			return errors.Wrap(err, "marshal dead letter")
+		}
+	}
+	if !found {
+		return quickfix.ErrDeadLetterNotFound
+	}
+
+	tmpFname := store.deadLettersFname() + ".tmp"
+	if err = os.WriteFile(tmpFname, buf.Bytes(), 0660); err != nil {
+		return err
+	}
+	return os.Rename(tmpFname, store.deadLettersFname())
+}
+
+func (store *fileStore) readDeadLettersLocked() ([]quickfix.DeadLetter, error) {
+	f, err := os.Open(store.deadLettersFname())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var letters []quickfix.DeadLetter
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var letter quickfix.DeadLetter
+		if err = json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse dead letter in %s", store.deadLettersFname())
+		}
+		letters = append(letters, letter)
+	}
+	return letters, scanner.Err()
+}
@@ -0,0 +1,92 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// epochFname is the file of the ownership epoch of the session, see quickfix.EpochStore. It is not removed by Reset.
+func (store *fileStore) epochFname() string {
+	return path.Join(store.dirname, fmt.Sprintf("%s.%s", store.sessionPrefix, "epoch"))
+}
+
+// Epoch reads the ownership epoch of the session through to its file, zero if the session was never resumed.
+func (store *fileStore) Epoch() (uint64, error) {
+	store.epochMu.Lock()
+	defer store.epochMu.Unlock()
+	return store.readEpochLocked()
+}
+
+// CompareAndSwapEpoch sets the ownership epoch to next if it currently is old. The file is replaced by a rename,
+// so that it is never read partially written. The swap is atomic among the stores of this process only, instances
+// sharing the store directory must not resume the session concurrently.
+func (store *fileStore) CompareAndSwapEpoch(old, next uint64) (bool, error) {
+	store.epochMu.Lock()
+	defer store.epochMu.Unlock()
+
+	epoch, err := store.readEpochLocked()
+	if err != nil {
+		return false, err
+	}
+	if epoch != old {
+		return false, nil
+	}
+
+	fname := store.epochFname()
+	tmp, err := os.CreateTemp(store.dirname, path.Base(fname)+".*")
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = removeFile(tmp.Name()) }()
+
+	if _, err = tmp.WriteString(strconv.FormatUint(next, 10)); err != nil {
+		_ = tmp.Close()
+		return false, fmt.Errorf("unable to write to file: %s: %s", tmp.Name(), err.Error())
+	}
+	if store.fileSync {
+		if err = tmp.Sync(); err != nil {
+			_ = tmp.Close()
+			return false, fmt.Errorf("unable to flush file: %s: %s", tmp.Name(), err.Error())
+		}
+	}
+	if err = tmp.Close(); err != nil {
+		return false, err
+	}
+	if err = os.Rename(tmp.Name(), fname); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (store *fileStore) readEpochLocked() (uint64, error) {
+	b, err := os.ReadFile(store.epochFname())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	epoch, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse epoch file: %s: %s", store.epochFname(), err.Error())
+	}
+	return epoch, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// loadTradingDay returns the trading day of the session: its window starts at StartTime, or at the start of the
+// first of its SessionWindows, in the session TimeZone or UTC. The trading date of sessions without a schedule is
+// the calendar date.
+func loadTradingDay(settings *quickfix.SessionSettings) (*internal.TradingDay, error) {
+	day := &internal.TradingDay{Loc: time.UTC}
+	if settings.HasSetting(config.TimeZone) {
+		locStr, err := settings.Setting(config.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		if day.Loc, err = time.LoadLocation(locStr); err != nil {
+			return nil, errors.Wrapf(err, "problem parsing time zone '%v' for setting '%v'", locStr, config.TimeZone)
+		}
+	}
+
+	var startStr string
+	switch {
+	case settings.HasSetting(config.StartTime):
+		startStr, _ = settings.Setting(config.StartTime)
+	case settings.HasSetting(config.SessionWindows):
+		windows, _ := settings.Setting(config.SessionWindows)
+		firstWindow, _, _ := strings.Cut(windows, ",")
+		startStr, _, _ = strings.Cut(strings.TrimSpace(firstWindow), "-")
+	default:
+		return day, nil
+	}
+
+	var err error
+	if day.Start, err = internal.ParseTimeOfDay(startStr); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing the start of the session window '%v'", startStr)
+	}
+	return day, nil
+}
+
+// partitionKey returns the trading date of the current time.
+func (store *fileStore) partitionKey() string {
+	return store.partitionDay.Date(store.now())
+}
+
+func (store *fileStore) partitionFnames(partition string) (bodyFname, headerFname string) {
+	bodyFname = path.Join(store.dirname, fmt.Sprintf("%s.%s.%s", store.sessionPrefix, "body", partition))
+	headerFname = path.Join(store.dirname, fmt.Sprintf("%s.%s.%s", store.sessionPrefix, "header", partition))
+	return
+}
+
+func (store *fileStore) setActivePartition(partition string) {
+	store.activePartition = partition
+	store.bodyFname, store.headerFname = store.partitionFnames(partition)
+}
+
+// rollPartitionLocked switches body and header files over to a new partition once the trading date changed.
+func (store *fileStore) rollPartitionLocked() (err error) {
+	if store.partitionDay == nil {
+		return nil
+	}
+	partition := store.partitionKey()
+	if partition == store.activePartition {
+		return nil
+	}
+
+	// Batched messages belong to the partition being closed.
+	if err = store.flushLocked(); err != nil {
+		return err
+	}
+
+	if err = closeSyncFile(store.bodyFile); err != nil {
+		return err
+	}
+	if err = closeSyncFile(store.headerFile); err != nil {
+		return err
+	}
+	store.bodyFile, store.headerFile = nil, nil
+
+	store.setActivePartition(partition)
+	if store.bodyFile, err = openOrCreateFile(store.bodyFname, 0660); err != nil {
+		return err
+	}
+	if store.headerFile, err = openOrCreateFile(store.headerFname, 0660); err != nil {
+		return err
+	}
+	return nil
+}
+
+// partitions returns the trading dates with a header file on disk, oldest first.
+func (store *fileStore) partitions() ([]string, error) {
+	entries, err := os.ReadDir(store.dirname)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read dir %v", store.dirname)
+	}
+
+	headerPrefix := store.sessionPrefix + ".header."
+	var partitions []string
+	for _, entry := range entries {
+		partition, ok := strings.CutPrefix(entry.Name(), headerPrefix)
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse(internal.TradingDayLayout, partition); err != nil {
+			continue
+		}
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+	return partitions, nil
+}
+
+// removePartitions drops all partition files of the session.
+func (store *fileStore) removePartitions() error {
+	partitions, err := store.partitions()
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		bodyFname, headerFname := store.partitionFnames(partition)
+		if err := removeFile(bodyFname); err != nil {
+			return err
+		}
+		if err := removeFile(headerFname); err != nil {
+			return err
+		}
+	}
+	return nil
+}
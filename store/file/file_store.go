@@ -16,6 +16,7 @@
 package file
 
 import (
+	stderrors "errors"
 	"fmt"
 	"io"
 	"os"
@@ -28,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
 )
 
 type fileStoreFactory struct {
@@ -50,6 +52,29 @@ type fileStore struct {
 	senderSeqNumsFile *os.File
 	targetSeqNumsFile *os.File
 	fileSync          bool
+
+	// When syncInterval is set, messages are buffered in batch and written at most syncInterval after the first of
+	// them, or once maxBatch are buffered, see FileStoreSyncInterval.
+	syncInterval  time.Duration
+	maxBatch      int
+	batch         []batchedMessage
+	batchTimer    *time.Timer
+	seqNumsDirty  bool
+	batchFlushErr error
+
+	// When partitionDay is set, body and header files are split by trading date.
+	partitionDay    *internal.TradingDay
+	dirname         string
+	sessionPrefix   string
+	activePartition string
+	now             func() time.Time
+
+	deadLettersMu sync.Mutex
+
+	epochMu sync.Mutex
+
+	auditMu   sync.Mutex
+	auditFile *os.File
 }
 
 // NewStoreFactory returns a file-based implementation of MessageStoreFactory.
@@ -84,10 +109,56 @@ func (f fileStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfi
 	} else {
 		fsync = true //existing behavior is to fsync writes
 	}
-	return newFileStore(sessionID, dirname, fsync)
+
+	var partitionDay *internal.TradingDay
+	if sessionSettings.HasSetting(config.FileStorePartitionByDate) {
+		partitionByDate, err := sessionSettings.BoolSetting(config.FileStorePartitionByDate)
+		if err != nil {
+			return nil, err
+		}
+		if partitionByDate {
+			if partitionDay, err = loadTradingDay(sessionSettings); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var syncInterval time.Duration
+	if sessionSettings.HasSetting(config.FileStoreSyncInterval) {
+		if syncInterval, err = sessionSettings.DurationSetting(config.FileStoreSyncInterval); err != nil {
+			var intervalInt int
+			if intervalInt, err = sessionSettings.IntSetting(config.FileStoreSyncInterval); err != nil {
+				return nil, err
+			}
+			syncInterval = time.Duration(intervalInt) * time.Second
+		}
+		if syncInterval <= 0 {
+			return nil, errors.New("FileStoreSyncInterval must be greater than zero")
+		}
+	}
+
+	maxBatch := defaultMaxBatch
+	if sessionSettings.HasSetting(config.FileStoreMaxBatch) {
+		if syncInterval == 0 {
+			return nil, quickfix.ConditionallyRequiredSetting{Setting: config.FileStoreSyncInterval}
+		}
+		if maxBatch, err = sessionSettings.IntSetting(config.FileStoreMaxBatch); err != nil {
+			return nil, err
+		}
+		if maxBatch <= 0 {
+			return nil, errors.New("FileStoreMaxBatch must be greater than zero")
+		}
+	}
+
+	store, err := newFileStore(sessionID, dirname, fsync, partitionDay)
+	if err != nil {
+		return nil, err
+	}
+	store.syncInterval, store.maxBatch = syncInterval, maxBatch
+	return store, nil
 }
 
-func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (*fileStore, error) {
+func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool, partitionDay *internal.TradingDay) (*fileStore, error) {
 	if err := os.MkdirAll(dirname, os.ModePerm); err != nil {
 		return nil, err
 	}
@@ -108,6 +179,10 @@ func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (
 		senderSeqNumsFname: path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "senderseqnums")),
 		targetSeqNumsFname: path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "targetseqnums")),
 		fileSync:           fileSync,
+		partitionDay:       partitionDay,
+		dirname:            dirname,
+		sessionPrefix:      sessionPrefix,
+		now:                time.Now,
 	}
 
 	if err := store.Refresh(); err != nil {
@@ -117,11 +192,18 @@ func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (
 	return store, nil
 }
 
+// SetLabels records the session labels in a metadata file next to the store files.
+func (store *fileStore) SetLabels(labels map[string]string) {
+	fname := path.Join(store.dirname, fmt.Sprintf("%s.%s", store.sessionPrefix, "labels"))
+	_ = os.WriteFile(fname, []byte(quickfix.FormatLabels(labels)+"\n"), 0660)
+}
+
 // Reset deletes the store files and sets the seqnums back to 1.
 func (store *fileStore) Reset() error {
 	if err := store.cache.Reset(); err != nil {
 		return errors.Wrap(err, "cache reset")
 	}
+	store.discardBatch()
 
 	if err := store.Close(); err != nil {
 		return errors.Wrap(err, "close")
@@ -132,6 +214,9 @@ func (store *fileStore) Reset() error {
 	if err := removeFile(store.headerFname); err != nil {
 		return err
 	}
+	if err := store.removePartitions(); err != nil {
+		return err
+	}
 	if err := removeFile(store.sessionFname); err != nil {
 		return err
 	}
@@ -160,6 +245,10 @@ func (store *fileStore) Refresh() (err error) {
 		return err
 	}
 
+	if store.partitionDay != nil {
+		store.setActivePartition(store.partitionKey())
+	}
+
 	if store.bodyFile, err = openOrCreateFile(store.bodyFname, 0660); err != nil {
 		return err
 	}
@@ -252,6 +341,11 @@ func (store *fileStore) setSeqNum(f *os.File, seqNum int) error {
 	if _, err := fmt.Fprintf(f, "%019d", seqNum); err != nil {
 		return fmt.Errorf("unable to write to file: %s: %s", f.Name(), err.Error())
 	}
+	if store.syncInterval > 0 {
+		// Synced with the next batch of messages.
+		store.seqNumsDirty = true
+		return nil
+	}
 	if store.fileSync {
 		if err := f.Sync(); err != nil {
 			return fmt.Errorf("unable to flush file: %s: %s", f.Name(), err.Error())
@@ -314,6 +408,12 @@ func (store *fileStore) SetCreationTime(_ time.Time) {
 func (store *fileStore) SaveMessage(seqNum int, msg []byte) error {
 	store.fileMu.Lock()
 	defer store.fileMu.Unlock()
+	if err := store.rollPartitionLocked(); err != nil {
+		return err
+	}
+	if store.syncInterval > 0 {
+		return store.bufferMessageLocked(seqNum, msg)
+	}
 	offset, err := store.bodyFile.Seek(0, io.SeekEnd)
 	if err != nil {
 		return fmt.Errorf("unable to seek to end of file: %s: %s", store.bodyFname, err.Error())
@@ -352,27 +452,52 @@ func (store *fileStore) syncBodyAndHeaderFilesLocked() error {
 }
 
 func (store *fileStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
-	// Sync files
+	// Flush the batched messages and sync files
 	store.fileMu.Lock()
-	err := store.syncBodyAndHeaderFilesLocked()
+	err := store.flushLocked()
+	if err == nil {
+		err = store.syncBodyAndHeaderFilesLocked()
+	}
 	store.fileMu.Unlock()
 	if err != nil {
 		return err
 	}
 
-	// Open a read only view to body and header file
-	bodyFile, err := openOrCreateFile(store.bodyFname, 0440)
+	if store.partitionDay == nil {
+		_, err = iterateFiles(store.bodyFname, store.headerFname, beginSeqNum, endSeqNum, cb)
+		return err
+	}
+
+	// Read across partitions in trading date order, a resend may span the partition boundary.
+	partitions, err := store.partitions()
 	if err != nil {
 		return err
 	}
+	for _, partition := range partitions {
+		bodyFname, headerFname := store.partitionFnames(partition)
+		if done, err := iterateFiles(bodyFname, headerFname, beginSeqNum, endSeqNum, cb); err != nil || done {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterateFiles calls cb for each message between beginSeqNum and endSeqNum stored in the given body and header files.
+// done reports whether a message past endSeqNum was reached.
+func iterateFiles(bodyFname, headerFname string, beginSeqNum, endSeqNum int, cb func([]byte) error) (done bool, err error) {
+	// Open a read only view to body and header file
+	bodyFile, err := openOrCreateFile(bodyFname, 0440)
+	if err != nil {
+		return false, err
+	}
 	defer func() { _ = bodyFile.Close() }()
-	headerFile, err := openOrCreateFile(store.headerFname, 0440)
+	headerFile, err := openOrCreateFile(headerFname, 0440)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer func() { _ = headerFile.Close() }()
 	if _, err = headerFile.Seek(0, io.SeekStart); err != nil {
-		return fmt.Errorf("unable to seek to start of file: %s: %s", store.headerFname, err.Error())
+		return false, fmt.Errorf("unable to seek to start of file: %s: %s", headerFname, err.Error())
 	}
 
 	// Iterate over the header file
@@ -383,10 +508,10 @@ func (store *fileStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]by
 			if errors.Is(err, io.EOF) {
 				break
 			}
-			return fmt.Errorf("unable to read from file: %s: %s", store.headerFname, err.Error())
+			return false, fmt.Errorf("unable to read from file: %s: %s", headerFname, err.Error())
 		} else if cnt < 3 || seqNum > endSeqNum {
 			// If we have reached the end of possible iteration then break
-			break
+			return true, nil
 		} else if seqNum < beginSeqNum {
 			// If we have not yet reached the starting sequence number then continue
 			continue
@@ -394,12 +519,12 @@ func (store *fileStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]by
 		// Otherwise process the file
 		msg := make([]byte, size)
 		if _, err := bodyFile.ReadAt(msg, offset); err != nil {
-			return fmt.Errorf("unable to read from file: %s: %s", store.bodyFname, err.Error())
+			return false, fmt.Errorf("unable to read from file: %s: %s", bodyFname, err.Error())
 		} else if err = cb(msg); err != nil {
-			return err
+			return false, err
 		}
 	}
-	return nil
+	return false, nil
 }
 
 func (store *fileStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
@@ -411,23 +536,17 @@ func (store *fileStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error
 	return msgs, err
 }
 
-// Close closes the store's files.
+// Close flushes the batched messages and closes the store's files, all of them even if flushing or closing one fails.
 func (store *fileStore) Close() error {
-	if err := closeSyncFile(store.bodyFile); err != nil {
-		return err
-	}
-	if err := closeSyncFile(store.headerFile); err != nil {
-		return err
-	}
-	if err := closeSyncFile(store.sessionFile); err != nil {
-		return err
-	}
-	if err := closeSyncFile(store.senderSeqNumsFile); err != nil {
-		return err
-	}
-	if err := closeSyncFile(store.targetSeqNumsFile); err != nil {
-		return err
-	}
+	err := stderrors.Join(
+		store.Flush(),
+		closeSyncFile(store.bodyFile),
+		closeSyncFile(store.headerFile),
+		closeSyncFile(store.sessionFile),
+		closeSyncFile(store.senderSeqNumsFile),
+		closeSyncFile(store.targetSeqNumsFile),
+		store.closeAuditFile(),
+	)
 
 	store.bodyFile = nil
 	store.headerFile = nil
@@ -435,5 +554,5 @@ func (store *fileStore) Close() error {
 	store.senderSeqNumsFile = nil
 	store.targetSeqNumsFile = nil
 
-	return nil
+	return err
 }
@@ -0,0 +1,143 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// defaultMaxBatch is the number of messages written at once by default when FileStoreSyncInterval is set.
+const defaultMaxBatch = 1000
+
+// batchedMessage is a message saved but not yet written to the body file.
+type batchedMessage struct {
+	seqNum int
+	msg    []byte
+}
+
+// bufferMessageLocked adds msg to the batch, writing the batch once it is full and otherwise within syncInterval.
+// The sequence numbers are written right away, ahead of the batch: a crash loses the batched messages but not
+// their sequence numbers, and the lost messages are gap filled when resent.
+func (store *fileStore) bufferMessageLocked(seqNum int, msg []byte) error {
+	if err := store.batchFlushErr; err != nil {
+		store.batchFlushErr = nil
+		return err
+	}
+
+	store.batch = append(store.batch, batchedMessage{seqNum: seqNum, msg: append([]byte(nil), msg...)})
+	if len(store.batch) >= store.maxBatch {
+		return store.flushLocked()
+	}
+	if store.batchTimer == nil {
+		store.batchTimer = time.AfterFunc(store.syncInterval, store.flushOnTimer)
+	}
+	return nil
+}
+
+// flushOnTimer writes the batch once syncInterval has elapsed. The error is returned by the next save or Flush.
+func (store *fileStore) flushOnTimer() {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+	store.batchTimer = nil
+	if err := store.flushLocked(); err != nil {
+		store.batchFlushErr = err
+	}
+}
+
+// Flush writes the batched messages to the store files and syncs them, along with the sequence numbers, if
+// FileStoreSync is set. Only needed with FileStoreSyncInterval, the store is otherwise written through.
+func (store *fileStore) Flush() error {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	err := store.flushLocked()
+	if err == nil {
+		err = store.batchFlushErr
+	}
+	store.batchFlushErr = nil
+	return err
+}
+
+// flushLocked writes the batch. The body and header files are synced before the sequence number files, so that
+// after a crash a message the sequence numbers account for is either in the files or gap filled when resent.
+func (store *fileStore) flushLocked() error {
+	if store.batchTimer != nil {
+		store.batchTimer.Stop()
+		store.batchTimer = nil
+	}
+	if store.bodyFile == nil || (len(store.batch) == 0 && !store.seqNumsDirty) {
+		return nil
+	}
+
+	if len(store.batch) > 0 {
+		offset, err := store.bodyFile.Seek(0, io.SeekEnd)
+		if err != nil {
+			return fmt.Errorf("unable to seek to end of file: %s: %s", store.bodyFname, err.Error())
+		}
+		if _, err := store.headerFile.Seek(0, io.SeekEnd); err != nil {
+			return fmt.Errorf("unable to seek to end of file: %s: %s", store.headerFname, err.Error())
+		}
+
+		var header, body bytes.Buffer
+		for _, m := range store.batch {
+			fmt.Fprintf(&header, "%d,%d,%d\n", m.seqNum, offset+int64(body.Len()), len(m.msg))
+			body.Write(m.msg)
+		}
+
+		// The body is written first, so the header never points past the end of the body file.
+		if _, err := store.bodyFile.Write(body.Bytes()); err != nil {
+			return fmt.Errorf("unable to write to file: %s: %s", store.bodyFname, err.Error())
+		}
+		if _, err := store.headerFile.Write(header.Bytes()); err != nil {
+			return fmt.Errorf("unable to write to file: %s: %s", store.headerFname, err.Error())
+		}
+		clear(store.batch)
+		store.batch = store.batch[:0]
+	}
+
+	if store.fileSync {
+		if err := store.syncBodyAndHeaderFilesLocked(); err != nil {
+			return err
+		}
+		if store.seqNumsDirty {
+			if err := store.senderSeqNumsFile.Sync(); err != nil {
+				return fmt.Errorf("unable to flush file: %s: %s", store.senderSeqNumsFname, err.Error())
+			}
+			if err := store.targetSeqNumsFile.Sync(); err != nil {
+				return fmt.Errorf("unable to flush file: %s: %s", store.targetSeqNumsFname, err.Error())
+			}
+		}
+	}
+	store.seqNumsDirty = false
+	return nil
+}
+
+// discardBatch drops the batched messages, before the store files are removed.
+func (store *fileStore) discardBatch() {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	if store.batchTimer != nil {
+		store.batchTimer.Stop()
+		store.batchTimer = nil
+	}
+	clear(store.batch)
+	store.batch = store.batch[:0]
+	store.batchFlushErr = nil
+}
@@ -16,6 +16,7 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -25,6 +26,8 @@ import (
 	"time"
 
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
 	"github.com/quickfixgo/quickfix/internal/testsuite"
 	assert2 "github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,3 +80,281 @@ func TestStringParse(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(6, i)
 }
+
+// PartitionedFileStoreTestSuite runs all tests in the MessageStoreTestSuite against a FileStore partitioned by date.
+type PartitionedFileStoreTestSuite struct {
+	testsuite.StoreTestSuite
+	fileStorePath string
+}
+
+func (suite *PartitionedFileStoreTestSuite) SetupTest() {
+	suite.fileStorePath = suite.T().TempDir()
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+FileStorePath=%s
+FileStorePartitionByDate=Y
+TimeZone=America/New_York
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, suite.fileStorePath, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	suite.MsgStore, err = NewStoreFactory(settings).Create(sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *PartitionedFileStoreTestSuite) TearDownTest() {
+	suite.MsgStore.Close()
+}
+
+func (suite *PartitionedFileStoreTestSuite) TestReadAcrossPartitionBoundary() {
+	store := suite.MsgStore.(*fileStore)
+	ny, err := time.LoadLocation("America/New_York")
+	suite.Require().Nil(err)
+
+	now := time.Date(2024, 3, 1, 23, 59, 0, 0, ny)
+	store.now = func() time.Time { return now }
+	suite.Require().Nil(store.Reset())
+
+	suite.Require().Nil(store.SaveMessage(1, []byte("late")))
+	now = now.Add(2 * time.Minute)
+	suite.Require().Nil(store.SaveMessage(2, []byte("early")))
+	suite.Require().Nil(store.SaveMessage(3, []byte("later")))
+
+	partitions, err := store.partitions()
+	suite.Require().Nil(err)
+	suite.Equal([]string{"20240301", "20240302"}, partitions)
+
+	msgs, err := store.GetMessages(1, 2)
+	suite.Require().Nil(err)
+	suite.Equal([][]byte{[]byte("late"), []byte("early")}, msgs)
+
+	msgs, err = store.GetMessages(2, 3)
+	suite.Require().Nil(err)
+	suite.Equal([][]byte{[]byte("early"), []byte("later")}, msgs)
+
+	suite.Require().Nil(store.Reset())
+	partitions, err = store.partitions()
+	suite.Require().Nil(err)
+	suite.Equal([]string{"20240302"}, partitions, "only the freshly opened active partition should remain")
+
+	msgs, err = store.GetMessages(1, 3)
+	suite.Require().Nil(err)
+	suite.Empty(msgs)
+}
+
+func (suite *PartitionedFileStoreTestSuite) TestPartitionFollowsSessionWindow() {
+	store := suite.MsgStore.(*fileStore)
+	store.partitionDay.Start = internal.NewTimeOfDay(17, 0, 0)
+	now := time.Date(2024, 3, 1, 17, 0, 0, 0, store.partitionDay.Loc)
+	store.now = func() time.Time { return now }
+	suite.Require().Nil(store.Reset())
+
+	suite.Require().Nil(store.SaveMessage(1, []byte("evening")))
+	now = now.Add(12 * time.Hour)
+	suite.Require().Nil(store.SaveMessage(2, []byte("morning")))
+
+	partitions, err := store.partitions()
+	suite.Require().Nil(err)
+	suite.Equal([]string{"20240301"}, partitions, "the session window spans midnight")
+}
+
+func TestLoadTradingDay(t *testing.T) {
+	settings := quickfix.NewSessionSettings()
+	day, err := loadTradingDay(settings)
+	require.Nil(t, err)
+	assert2.Equal(t, time.UTC, day.Loc)
+	assert2.Equal(t, "20240301", day.Date(time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)))
+
+	settings.Set(config.TimeZone, "America/New_York")
+	settings.Set(config.StartTime, "17:00:00")
+	day, err = loadTradingDay(settings)
+	require.Nil(t, err)
+	assert2.Equal(t, "20240229", day.Date(time.Date(2024, 3, 1, 16, 0, 0, 0, day.Loc)))
+
+	settings = quickfix.NewSessionSettings()
+	settings.Set(config.SessionWindows, "08:00:00-12:00:00, 13:00:00-17:00:00")
+	day, err = loadTradingDay(settings)
+	require.Nil(t, err)
+	assert2.Equal(t, "20240229", day.Date(time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)))
+
+	settings.Set(config.SessionWindows, "bogus")
+	_, err = loadTradingDay(settings)
+	assert2.NotNil(t, err)
+}
+
+func TestPartitionedFileStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(PartitionedFileStoreTestSuite))
+}
+
+// BatchedFileStoreTestSuite runs all tests in the MessageStoreTestSuite against a FileStore batching its writes.
+type BatchedFileStoreTestSuite struct {
+	testsuite.StoreTestSuite
+	fileStorePath string
+	sessionID     quickfix.SessionID
+	settings      *quickfix.Settings
+}
+
+func (suite *BatchedFileStoreTestSuite) SetupTest() {
+	suite.fileStorePath = suite.T().TempDir()
+	suite.sessionID = quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	var err error
+	suite.settings, err = quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+FileStorePath=%s
+FileStoreSyncInterval=1h
+FileStoreMaxBatch=3
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, suite.fileStorePath, suite.sessionID.BeginString, suite.sessionID.SenderCompID, suite.sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	suite.MsgStore, err = NewStoreFactory(suite.settings).Create(suite.sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *BatchedFileStoreTestSuite) TearDownTest() {
+	suite.MsgStore.Close()
+}
+
+// headerLines returns the number of messages written to the header file.
+func (suite *BatchedFileStoreTestSuite) headerLines() int {
+	header, err := os.ReadFile(suite.MsgStore.(*fileStore).headerFname)
+	suite.Require().Nil(err)
+	return strings.Count(string(header), "\n")
+}
+
+func (suite *BatchedFileStoreTestSuite) TestBuffered() {
+	suite.Require().Nil(suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("one")))
+	suite.Require().Nil(suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("two")))
+	suite.Equal(0, suite.headerLines())
+
+	// Sequence numbers are written through.
+	seqNums, err := os.ReadFile(suite.MsgStore.(*fileStore).senderSeqNumsFname)
+	suite.Require().Nil(err)
+	suite.Equal(fmt.Sprintf("%019d", 3), string(seqNums))
+
+	suite.Require().Nil(suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(3, []byte("three")))
+	suite.Equal(3, suite.headerLines(), "written once FileStoreMaxBatch is reached")
+
+	suite.Require().Nil(suite.MsgStore.SaveMessage(4, []byte("four")))
+	suite.Equal(3, suite.headerLines())
+	suite.Require().Nil(suite.MsgStore.(quickfix.FlushStore).Flush())
+	suite.Equal(4, suite.headerLines())
+}
+
+func (suite *BatchedFileStoreTestSuite) TestReadFlushesBatch() {
+	suite.Require().Nil(suite.MsgStore.SaveMessage(1, []byte("one")))
+	suite.Require().Nil(suite.MsgStore.SaveMessage(2, []byte("two")))
+
+	msgs, err := suite.MsgStore.GetMessages(1, 2)
+	suite.Require().Nil(err)
+	suite.Equal([][]byte{[]byte("one"), []byte("two")}, msgs)
+	suite.Equal(2, suite.headerLines())
+}
+
+func (suite *BatchedFileStoreTestSuite) TestFlushedOnInterval() {
+	store := suite.MsgStore.(*fileStore)
+	store.syncInterval = time.Millisecond
+
+	suite.Require().Nil(store.SaveMessage(1, []byte("one")))
+	suite.Eventually(func() bool {
+		store.fileMu.Lock()
+		defer store.fileMu.Unlock()
+		return len(store.batch) == 0
+	}, time.Second, time.Millisecond)
+	suite.Equal(1, suite.headerLines())
+}
+
+func (suite *BatchedFileStoreTestSuite) TestFlushedOnClose() {
+	suite.Require().Nil(suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("one")))
+	suite.Require().Nil(suite.MsgStore.Close())
+
+	var err error
+	suite.MsgStore, err = NewStoreFactory(suite.settings).Create(suite.sessionID)
+	suite.Require().Nil(err)
+	suite.Equal(2, suite.MsgStore.NextSenderMsgSeqNum())
+	msgs, err := suite.MsgStore.GetMessages(1, 1)
+	suite.Require().Nil(err)
+	suite.Equal([][]byte{[]byte("one")}, msgs)
+}
+
+func (suite *BatchedFileStoreTestSuite) TestCloseAfterFailedFlush() {
+	store := suite.MsgStore.(*fileStore)
+	flushErr := errors.New("flush failed")
+	store.batchFlushErr = flushErr
+	bodyFile, seqNumsFile := store.bodyFile, store.senderSeqNumsFile
+
+	suite.ErrorIs(store.Close(), flushErr)
+	_, err := bodyFile.Write([]byte("x"))
+	suite.ErrorIs(err, os.ErrClosed, "the files are closed even though the flush failed")
+	_, err = seqNumsFile.Write([]byte("x"))
+	suite.ErrorIs(err, os.ErrClosed)
+}
+
+func (suite *BatchedFileStoreTestSuite) TestResetDiscardsBatch() {
+	suite.Require().Nil(suite.MsgStore.SaveMessage(1, []byte("one")))
+	suite.Require().Nil(suite.MsgStore.Reset())
+
+	msgs, err := suite.MsgStore.GetMessages(1, 1)
+	suite.Require().Nil(err)
+	suite.Empty(msgs)
+}
+
+func TestBatchedFileStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchedFileStoreTestSuite))
+}
+
+func TestFileStoreBatchSettings(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	create := func(extra string) (*fileStore, error) {
+		settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+FileStorePath=%s
+%s
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, t.TempDir(), extra, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+		require.Nil(t, err)
+
+		store, err := NewStoreFactory(settings).Create(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		t.Cleanup(func() { store.Close() })
+		return store.(*fileStore), nil
+	}
+
+	store, err := create("")
+	require.Nil(t, err)
+	assert2.Equal(t, time.Duration(0), store.syncInterval)
+
+	store, err = create("FileStoreSyncInterval=5ms")
+	require.Nil(t, err)
+	assert2.Equal(t, 5*time.Millisecond, store.syncInterval)
+	assert2.Equal(t, defaultMaxBatch, store.maxBatch)
+
+	store, err = create("FileStoreSyncInterval=2\nFileStoreMaxBatch=50")
+	require.Nil(t, err)
+	assert2.Equal(t, 2*time.Second, store.syncInterval)
+	assert2.Equal(t, 50, store.maxBatch)
+
+	for _, invalid := range []string{
+		"FileStoreSyncInterval=0",
+		"FileStoreSyncInterval=blah",
+		"FileStoreMaxBatch=50",
+		"FileStoreSyncInterval=5ms\nFileStoreMaxBatch=0",
+	} {
+		_, err = create(invalid)
+		assert2.NotNil(t, err, invalid)
+	}
+}
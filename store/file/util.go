@@ -49,20 +49,20 @@ func createFilenamePrefix(s quickfix.SessionID) string {
 }
 
 // closeSyncFile behaves like Sync and Close, except that no error is returned if the file does not exist.
+// closeSyncFile syncs and closes f, closing it even if the sync fails.
 func closeSyncFile(f *os.File) error {
-	if f != nil {
-		if err := f.Sync(); err != nil {
-			if !os.IsNotExist(err) {
-				return err
-			}
-		}
-		if err := f.Close(); err != nil {
-			if !os.IsNotExist(err) {
-				return err
-			}
-		}
+	if f == nil {
+		return nil
 	}
-	return nil
+
+	syncErr := f.Sync()
+	if os.IsNotExist(syncErr) {
+		syncErr = nil
+	}
+	if err := f.Close(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return syncErr
 }
 
 // removeFile behaves like os.Remove, except that no error is returned if the file does not exist.
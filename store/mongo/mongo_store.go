@@ -145,6 +145,8 @@ type mongoQuickFixEntryData struct {
 	CreationTime   time.Time `bson:"creation_time,omitempty"`
 	IncomingSeqNum int       `bson:"incoming_seq_num,omitempty"`
 	OutgoingSeqNum int       `bson:"outgoing_seq_num,omitempty"`
+	// Ownership epoch, never written by the updates of the session data above.
+	Epoch int64 `bson:"epoch,omitempty"`
 	// Indexed data.
 	BeginString      string `bson:"begin_string"`
 	SessionQualifier string `bson:"session_qualifier"`
@@ -223,6 +225,48 @@ func (store *mongoStore) populateCache() error {
 	return nil
 }
 
+// Epoch reads the ownership epoch of the session through to the database, zero if the session was never resumed.
+func (store *mongoStore) Epoch() (uint64, error) {
+	msgFilter := generateMessageFilter(&store.sessionID)
+	res := store.db.Database(store.mongoDatabase).Collection(store.sessionsCollection).FindOne(context.Background(), msgFilter)
+	if res.Err() == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if res.Err() != nil {
+		return 0, errors.Wrap(res.Err(), "query")
+	}
+
+	sessionData := &mongoQuickFixEntryData{}
+	if err := res.Decode(&sessionData); err != nil {
+		return 0, errors.Wrap(err, "decode")
+	}
+	return uint64(sessionData.Epoch), nil
+}
+
+// CompareAndSwapEpoch sets the ownership epoch to next if it currently is old, in a single conditional update.
+func (store *mongoStore) CompareAndSwapEpoch(old, next uint64) (bool, error) {
+	msgFilterBytes, err := bson.Marshal(generateMessageFilter(&store.sessionID))
+	if err != nil {
+		return false, err
+	}
+	epochFilter := bson.M{}
+	if err = bson.Unmarshal(msgFilterBytes, &epochFilter); err != nil {
+		return false, err
+	}
+	if old == 0 {
+		// The epoch of a session never resumed is not set.
+		epochFilter["epoch"] = bson.M{"$in": bson.A{int64(0), nil}}
+	} else {
+		epochFilter["epoch"] = int64(old)
+	}
+
+	res, err := store.db.Database(store.mongoDatabase).Collection(store.sessionsCollection).UpdateOne(context.Background(), epochFilter, bson.M{"$set": bson.M{"epoch": int64(next)}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
 // NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
 func (store *mongoStore) NextSenderMsgSeqNum() int {
 	return store.cache.NextSenderMsgSeqNum()
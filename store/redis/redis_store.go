@@ -0,0 +1,400 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultKeyPrefix = "quickfix"
+
+	// Messages read per round trip when iterating.
+	iterateChunkSize = 1000
+
+	// Session hash fields.
+	fieldCreationTime   = "creation_time"
+	fieldIncomingSeqNum = "incoming_seq_num"
+	fieldOutgoingSeqNum = "outgoing_seq_num"
+)
+
+type redisStoreFactory struct {
+	settings *quickfix.Settings
+}
+
+type redisStore struct {
+	sessionID   quickfix.SessionID
+	cache       quickfix.MessageStore
+	client      *client
+	sessionKey  string
+	messagesKey string
+	epochKey    string
+	ttl         time.Duration
+}
+
+// NewStoreFactory returns a redis-based implementation of MessageStoreFactory.
+func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return redisStoreFactory{settings: settings}
+}
+
+// Create creates a new RedisStore implementation of the MessageStore interface.
+func (f redisStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix.MessageStore, err error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+
+	c := &client{timeout: 10 * time.Second}
+	if c.address, err = sessionSettings.Setting(config.RedisStoreAddress); err != nil {
+		return nil, err
+	}
+
+	// Optional.
+	c.username, _ = sessionSettings.Setting(config.RedisStoreUsername)
+	c.password, _ = sessionSettings.Setting(config.RedisStorePassword)
+	if c.username != "" && c.password == "" {
+		return nil, quickfix.ConditionallyRequiredSetting{Setting: config.RedisStorePassword}
+	}
+
+	if sessionSettings.HasSetting(config.RedisStoreDB) {
+		if c.db, err = sessionSettings.IntSetting(config.RedisStoreDB); err != nil {
+			return nil, err
+		}
+		if c.db < 0 {
+			return nil, errors.Errorf("%v must not be negative", config.RedisStoreDB)
+		}
+	}
+
+	keyPrefix := defaultKeyPrefix
+	if sessionSettings.HasSetting(config.RedisStoreKeyPrefix) {
+		if keyPrefix, err = sessionSettings.Setting(config.RedisStoreKeyPrefix); err != nil {
+			return nil, err
+		}
+		if keyPrefix == "" {
+			return nil, errors.Errorf("%v must not be empty", config.RedisStoreKeyPrefix)
+		}
+	}
+
+	var ttl time.Duration
+	if sessionSettings.HasSetting(config.RedisStoreTTL) {
+		if ttl, err = sessionSettings.DurationSetting(config.RedisStoreTTL); err != nil {
+			var ttlInt int
+			if ttlInt, err = sessionSettings.IntSetting(config.RedisStoreTTL); err != nil {
+				return nil, err
+			}
+			ttl = time.Duration(ttlInt) * time.Second
+		}
+		if ttl <= 0 {
+			return nil, errors.Errorf("%v must be greater than zero", config.RedisStoreTTL)
+		}
+	}
+
+	return newRedisStore(sessionID, c, keyPrefix, ttl)
+}
+
+func newRedisStore(sessionID quickfix.SessionID, c *client, keyPrefix string, ttl time.Duration) (store *redisStore, err error) {
+	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	if memErr != nil {
+		err = errors.Wrap(memErr, "cache creation")
+		return
+	}
+
+	store = &redisStore{
+		sessionID:   sessionID,
+		cache:       memStore,
+		client:      c,
+		sessionKey:  keyPrefix + ":" + sessionID.String() + ":session",
+		messagesKey: keyPrefix + ":" + sessionID.String() + ":messages",
+		epochKey:    keyPrefix + ":" + sessionID.String() + ":epoch",
+		ttl:         ttl,
+	}
+
+	if err = store.cache.Reset(); err != nil {
+		err = errors.Wrap(err, "cache reset")
+		return
+	}
+
+	if err = store.populateCache(); err != nil {
+		_ = c.close()
+	}
+	return
+}
+
+// expire returns the commands refreshing the TTL of keys, if any.
+func (store *redisStore) expire(keys ...string) [][]string {
+	if store.ttl <= 0 {
+		return nil
+	}
+
+	ms := strconv.FormatInt(store.ttl.Milliseconds(), 10)
+	cmds := make([][]string, len(keys))
+	for i, key := range keys {
+		cmds[i] = []string{"PEXPIRE", key, ms}
+	}
+	return cmds
+}
+
+// setSession returns the command storing the session state.
+func (store *redisStore) setSession(creationTime time.Time, incoming, outgoing int) []string {
+	return []string{"HSET", store.sessionKey,
+		fieldCreationTime, strconv.FormatInt(creationTime.UnixNano(), 10),
+		fieldIncomingSeqNum, strconv.Itoa(incoming),
+		fieldOutgoingSeqNum, strconv.Itoa(outgoing),
+	}
+}
+
+// saveMessage returns the commands storing msg, replacing any message stored for seqNum.
+func (store *redisStore) saveMessage(seqNum int, msg []byte) [][]string {
+	score := strconv.Itoa(seqNum)
+	return [][]string{
+		{"ZREMRANGEBYSCORE", store.messagesKey, score, score},
+		{"ZADD", store.messagesKey, score, string(msg)},
+	}
+}
+
+// Reset deletes the store records and sets the seqnums back to 1.
+func (store *redisStore) Reset() error {
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+
+	cmds := [][]string{
+		{"DEL", store.messagesKey},
+		store.setSession(store.cache.CreationTime(), store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum()),
+	}
+	_, err := store.client.exec(append(cmds, store.expire(store.sessionKey)...)...)
+	return err
+}
+
+// Refresh reloads the store from Redis.
+func (store *redisStore) Refresh() error {
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+	return store.populateCache()
+}
+
+func (store *redisStore) populateCache() error {
+	replies, err := store.client.do([]string{"HGETALL", store.sessionKey})
+	if err != nil {
+		return errors.Wrap(err, "query")
+	}
+	values, _ := replies[0].([]interface{})
+
+	if len(values) == 0 {
+		// Session record not found, create it.
+		cmds := [][]string{store.setSession(store.cache.CreationTime(), store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum())}
+		if _, err := store.client.exec(append(cmds, store.expire(store.sessionKey)...)...); err != nil {
+			return errors.Wrap(err, "insert")
+		}
+		return nil
+	}
+
+	fields := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		name, _ := values[i].([]byte)
+		value, _ := values[i+1].([]byte)
+		fields[string(name)] = string(value)
+	}
+
+	creationTime, err := strconv.ParseInt(fields[fieldCreationTime], 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "decode creation time")
+	}
+	incoming, err := strconv.Atoi(fields[fieldIncomingSeqNum])
+	if err != nil {
+		return errors.Wrap(err, "decode next target")
+	}
+	outgoing, err := strconv.Atoi(fields[fieldOutgoingSeqNum])
+	if err != nil {
+		return errors.Wrap(err, "decode next sender")
+	}
+
+	store.cache.SetCreationTime(time.Unix(0, creationTime))
+	if err := store.cache.SetNextTargetMsgSeqNum(incoming); err != nil {
+		return errors.Wrap(err, "cache set next target")
+	}
+	if err := store.cache.SetNextSenderMsgSeqNum(outgoing); err != nil {
+		return errors.Wrap(err, "cache set next sender")
+	}
+	return nil
+}
+
+// NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
+func (store *redisStore) NextSenderMsgSeqNum() int {
+	return store.cache.NextSenderMsgSeqNum()
+}
+
+// NextTargetMsgSeqNum returns the next MsgSeqNum that should be received.
+func (store *redisStore) NextTargetMsgSeqNum() int {
+	return store.cache.NextTargetMsgSeqNum()
+}
+
+// SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
+func (store *redisStore) SetNextSenderMsgSeqNum(next int) error {
+	cmds := [][]string{{"HSET", store.sessionKey, fieldOutgoingSeqNum, strconv.Itoa(next)}}
+	if _, err := store.client.exec(append(cmds, store.expire(store.sessionKey)...)...); err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+// SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
+func (store *redisStore) SetNextTargetMsgSeqNum(next int) error {
+	cmds := [][]string{{"HSET", store.sessionKey, fieldIncomingSeqNum, strconv.Itoa(next)}}
+	if _, err := store.client.exec(append(cmds, store.expire(store.sessionKey)...)...); err != nil {
+		return err
+	}
+	return store.cache.SetNextTargetMsgSeqNum(next)
+}
+
+// IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
+func (store *redisStore) IncrNextSenderMsgSeqNum() error {
+	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "save sequence number")
+	}
+	return nil
+}
+
+// IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
+func (store *redisStore) IncrNextTargetMsgSeqNum() error {
+	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "save sequence number")
+	}
+	return nil
+}
+
+// CreationTime returns the creation time of the store.
+func (store *redisStore) CreationTime() time.Time {
+	return store.cache.CreationTime()
+}
+
+// SetCreationTime is a no-op for RedisStore.
+func (store *redisStore) SetCreationTime(_ time.Time) {
+}
+
+func (store *redisStore) SaveMessage(seqNum int, msg []byte) error {
+	cmds := store.saveMessage(seqNum, msg)
+	_, err := store.client.exec(append(cmds, store.expire(store.messagesKey)...)...)
+	return err
+}
+
+func (store *redisStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	next := store.cache.NextSenderMsgSeqNum() + 1
+
+	cmds := append(store.saveMessage(seqNum, msg), []string{"HSET", store.sessionKey, fieldOutgoingSeqNum, strconv.Itoa(next)})
+	if _, err := store.client.exec(append(cmds, store.expire(store.messagesKey, store.sessionKey)...)...); err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *redisStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	end := strconv.Itoa(endSeqNum)
+	for next := beginSeqNum; next <= endSeqNum; {
+		replies, err := store.client.do([]string{"ZRANGEBYSCORE", store.messagesKey, strconv.Itoa(next), end,
+			"WITHSCORES", "LIMIT", "0", strconv.Itoa(iterateChunkSize)})
+		if err != nil {
+			return err
+		}
+		values, _ := replies[0].([]interface{})
+
+		for i := 0; i+1 < len(values); i += 2 {
+			msg, _ := values[i].([]byte)
+			score, _ := values[i+1].([]byte)
+			seqNum, err := strconv.Atoi(string(score))
+			if err != nil {
+				return errors.Wrap(err, "decode sequence number")
+			}
+			if err := cb(msg); err != nil {
+				return err
+			}
+			next = seqNum + 1
+		}
+
+		if len(values) < 2*iterateChunkSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (store *redisStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// Epoch reads the ownership epoch of the session through to Redis, zero if the session was never resumed. The epoch
+// key is kept by Reset and never expires.
+func (store *redisStore) Epoch() (uint64, error) {
+	replies, err := store.client.do([]string{"GET", store.epochKey})
+	if err != nil {
+		return 0, err
+	}
+	value, _ := replies[0].([]byte)
+	return parseEpoch(value)
+}
+
+// CompareAndSwapEpoch sets the ownership epoch to next if it currently is old, atomically with WATCH.
+func (store *redisStore) CompareAndSwapEpoch(old, next uint64) (bool, error) {
+	var parseErr error
+	swapped, err := store.client.compareAndSet(store.epochKey, strconv.FormatUint(next, 10), func(current []byte) bool {
+		var epoch uint64
+		epoch, parseErr = parseEpoch(current)
+		return parseErr == nil && epoch == old
+	})
+	if parseErr != nil {
+		return false, parseErr
+	}
+	return swapped, err
+}
+
+func parseEpoch(value []byte) (uint64, error) {
+	if value == nil {
+		return 0, nil
+	}
+	epoch, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "decode epoch")
+	}
+	return epoch, nil
+}
+
+// Close closes the store's connection to Redis.
+func (store *redisStore) Close() error {
+	if err := store.client.close(); err != nil {
+		return errors.Wrap(err, "error disconnecting from redis")
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+)
+
+type zmember struct {
+	score  int
+	member string
+}
+
+// fakeServer serves the subset of Redis used by the store.
+type fakeServer struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	strings map[string]string
+	hashes  map[string]map[string]string
+	zsets   map[string][]zmember
+	ttls    map[string]string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	s := &fakeServer{
+		listener: listener,
+		strings:  make(map[string]string),
+		hashes:   make(map[string]map[string]string),
+		zsets:    make(map[string][]zmember),
+		ttls:     make(map[string]string),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+
+	var queued [][]string
+	inMulti := false
+	watched := make(map[string]*string)
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		values, _ := reply.([]interface{})
+		cmd := make([]string, len(values))
+		for i, v := range values {
+			b, _ := v.([]byte)
+			cmd[i] = string(b)
+		}
+
+		switch {
+		case strings.EqualFold(cmd[0], "MULTI"):
+			inMulti, queued = true, nil
+			writeReply(w, "OK")
+		case strings.EqualFold(cmd[0], "WATCH"):
+			s.mu.Lock()
+			watched[cmd[1]] = s.stringLocked(cmd[1])
+			s.mu.Unlock()
+			writeReply(w, "OK")
+		case strings.EqualFold(cmd[0], "UNWATCH"):
+			watched = make(map[string]*string)
+			writeReply(w, "OK")
+		case strings.EqualFold(cmd[0], "EXEC"):
+			s.mu.Lock()
+			var results []interface{}
+			aborted := false
+			for key, value := range watched {
+				if current := s.stringLocked(key); (current == nil) != (value == nil) || (current != nil && *current != *value) {
+					aborted = true
+				}
+			}
+			if !aborted {
+				results = make([]interface{}, len(queued))
+				for i, queuedCmd := range queued {
+					results[i] = s.execLocked(queuedCmd)
+				}
+			}
+			s.mu.Unlock()
+			inMulti, watched = false, make(map[string]*string)
+			writeReply(w, results)
+		case inMulti:
+			queued = append(queued, cmd)
+			writeReply(w, "QUEUED")
+		default:
+			s.mu.Lock()
+			writeReply(w, s.execLocked(cmd))
+			s.mu.Unlock()
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) stringLocked(key string) *string {
+	if value, ok := s.strings[key]; ok {
+		return &value
+	}
+	return nil
+}
+
+func (s *fakeServer) execLocked(cmd []string) interface{} {
+	switch strings.ToUpper(cmd[0]) {
+	case "PING", "AUTH", "SELECT":
+		return "OK"
+	case "GET":
+		if value, ok := s.strings[cmd[1]]; ok {
+			return []byte(value)
+		}
+		return nil
+	case "SET":
+		s.strings[cmd[1]] = cmd[2]
+		return "OK"
+	case "HGETALL":
+		var fields []interface{}
+		for name, value := range s.hashes[cmd[1]] {
+			fields = append(fields, []byte(name), []byte(value))
+		}
+		return fields
+	case "HSET":
+		hash, ok := s.hashes[cmd[1]]
+		if !ok {
+			hash = make(map[string]string)
+			s.hashes[cmd[1]] = hash
+		}
+		for i := 2; i+1 < len(cmd); i += 2 {
+			hash[cmd[i]] = cmd[i+1]
+		}
+		return int64(0)
+	case "DEL":
+		delete(s.hashes, cmd[1])
+		delete(s.zsets, cmd[1])
+		delete(s.ttls, cmd[1])
+		return int64(1)
+	case "PEXPIRE":
+		s.ttls[cmd[1]] = cmd[2]
+		return int64(1)
+	case "ZADD":
+		score, _ := strconv.Atoi(cmd[2])
+		zset := s.zsets[cmd[1]]
+		for i, m := range zset {
+			if m.member == cmd[3] {
+				zset = append(zset[:i], zset[i+1:]...)
+				break
+			}
+		}
+		zset = append(zset, zmember{score: score, member: cmd[3]})
+		sort.SliceStable(zset, func(i, j int) bool { return zset[i].score < zset[j].score })
+		s.zsets[cmd[1]] = zset
+		return int64(1)
+	case "ZREMRANGEBYSCORE":
+		min, _ := strconv.Atoi(cmd[2])
+		max, _ := strconv.Atoi(cmd[3])
+		var kept []zmember
+		for _, m := range s.zsets[cmd[1]] {
+			if m.score < min || m.score > max {
+				kept = append(kept, m)
+			}
+		}
+		s.zsets[cmd[1]] = kept
+		return int64(0)
+	case "ZRANGEBYSCORE":
+		min, _ := strconv.Atoi(cmd[2])
+		max, _ := strconv.Atoi(cmd[3])
+		count, _ := strconv.Atoi(cmd[7])
+		var members []interface{}
+		for _, m := range s.zsets[cmd[1]] {
+			if m.score >= min && m.score <= max && len(members) < 2*count {
+				members = append(members, []byte(m.member), []byte(strconv.Itoa(m.score)))
+			}
+		}
+		return members
+	}
+	return redisError("ERR unknown command '" + cmd[0] + "'")
+}
+
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case string:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case redisError:
+		fmt.Fprintf(w, "-%s\r\n", string(v))
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case []byte:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case nil:
+		fmt.Fprint(w, "$-1\r\n")
+	case []interface{}:
+		if v == nil {
+			fmt.Fprint(w, "*-1\r\n")
+			return
+		}
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, e := range v {
+			writeReply(w, e)
+		}
+	}
+}
+
+var testSessionID = quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+func newTestStore(t *testing.T, server *fakeServer, extraSettings string) quickfix.MessageStore {
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+RedisStoreAddress=%s
+%s
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, server.listener.Addr(), extraSettings,
+		testSessionID.BeginString, testSessionID.SenderCompID, testSessionID.TargetCompID)))
+	require.Nil(t, err)
+
+	store, err := NewStoreFactory(settings).Create(testSessionID)
+	require.Nil(t, err)
+	return store
+}
+
+// RedisStoreTestSuite runs all tests in the MessageStoreTestSuite against the RedisStore implementation.
+type RedisStoreTestSuite struct {
+	testsuite.StoreTestSuite
+}
+
+func (suite *RedisStoreTestSuite) SetupTest() {
+	suite.MsgStore = newTestStore(suite.T(), newFakeServer(suite.T()), "")
+}
+
+func (suite *RedisStoreTestSuite) TearDownTest() {
+	suite.MsgStore.Close()
+}
+
+func TestRedisStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(RedisStoreTestSuite))
+}
+
+func TestRedisStoreKeyPrefixAndTTL(t *testing.T) {
+	server := newFakeServer(t)
+	store := newTestStore(t, server, "RedisStoreKeyPrefix=fix:prod\nRedisStoreTTL=24h")
+	defer store.Close()
+
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("hello")))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Equal(t, "2", server.hashes["fix:prod:FIX.4.4:SENDER->TARGET:session"][fieldOutgoingSeqNum])
+	require.Len(t, server.zsets["fix:prod:FIX.4.4:SENDER->TARGET:messages"], 1)
+	require.Equal(t, "86400000", server.ttls["fix:prod:FIX.4.4:SENDER->TARGET:session"])
+	require.Equal(t, "86400000", server.ttls["fix:prod:FIX.4.4:SENDER->TARGET:messages"])
+}
+
+func TestRedisStoreTTLSeconds(t *testing.T) {
+	server := newFakeServer(t)
+	store := newTestStore(t, server, "RedisStoreTTL=60")
+	defer store.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Equal(t, "60000", server.ttls["quickfix:FIX.4.4:SENDER->TARGET:session"])
+}
+
+func TestRedisStoreSharedAcrossInstances(t *testing.T) {
+	server := newFakeServer(t)
+	primary := newTestStore(t, server, "")
+	defer primary.Close()
+
+	require.Nil(t, primary.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("one")))
+	require.Nil(t, primary.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("two")))
+	require.Nil(t, primary.IncrNextTargetMsgSeqNum())
+
+	// A standby instance picks up the session state.
+	standby := newTestStore(t, server, "")
+	defer standby.Close()
+	require.Equal(t, 3, standby.NextSenderMsgSeqNum())
+	require.Equal(t, 2, standby.NextTargetMsgSeqNum())
+	require.True(t, primary.CreationTime().Equal(standby.CreationTime()))
+
+	msgs, err := standby.GetMessages(1, 2)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("one"), []byte("two")}, msgs)
+
+	require.Nil(t, primary.IncrNextSenderMsgSeqNum())
+	require.Nil(t, standby.Refresh())
+	require.Equal(t, 4, standby.NextSenderMsgSeqNum())
+}
+
+func TestRedisStoreEpochSharedAcrossInstances(t *testing.T) {
+	server := newFakeServer(t)
+	primary := newTestStore(t, server, "").(quickfix.EpochStore)
+	standby := newTestStore(t, server, "").(quickfix.EpochStore)
+
+	swapped, err := standby.CompareAndSwapEpoch(0, 1)
+	require.Nil(t, err)
+	require.True(t, swapped)
+
+	// The primary is fenced off.
+	swapped, err = primary.CompareAndSwapEpoch(0, 1)
+	require.Nil(t, err)
+	require.False(t, swapped)
+	epoch, err := primary.Epoch()
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), epoch)
+}
+
+func TestRedisStoreInvalidSettings(t *testing.T) {
+	server := newFakeServer(t)
+	for _, extra := range []string{
+		"RedisStoreKeyPrefix=",
+		"RedisStoreTTL=0",
+		"RedisStoreTTL=soon",
+		"RedisStoreDB=-1",
+		"RedisStoreUsername=fix",
+	} {
+		settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+RedisStoreAddress=%s
+%s
+
+[SESSION]
+BeginString=FIX.4.4
+SenderCompID=SENDER
+TargetCompID=TARGET`, server.listener.Addr(), extra)))
+		require.Nil(t, err)
+
+		_, err = NewStoreFactory(settings).Create(testSessionID)
+		require.NotNil(t, err, extra)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// redisError is an error reply of the server.
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// client is a minimal client of the Redis serialization protocol, RESP2, covering what the store needs. Commands
+// are serialized over a single connection, dialed again after an I/O error.
+type client struct {
+	address  string
+	username string
+	password string
+	db       int
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+func (c *client) connectLocked() error {
+	conn, err := net.DialTimeout("tcp", c.address, c.timeout)
+	if err != nil {
+		return err
+	}
+	c.conn, c.r, c.w = conn, bufio.NewReader(conn), bufio.NewWriter(conn)
+
+	var setup [][]string
+	if c.password != "" {
+		auth := []string{"AUTH"}
+		if c.username != "" {
+			auth = append(auth, c.username)
+		}
+		setup = append(setup, append(auth, c.password))
+	}
+	if c.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.db)})
+	}
+	if len(setup) == 0 {
+		setup = append(setup, []string{"PING"})
+	}
+
+	if _, err := c.roundTripLocked(setup); err != nil {
+		c.closeLocked()
+		return errors.Wrap(err, "connection setup")
+	}
+	return nil
+}
+
+// do sends cmds in a single round trip, returning their replies. The first error reply is returned as error.
+func (c *client) do(cmds ...[]string) ([]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connectLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return c.roundTripLocked(cmds)
+}
+
+func (c *client) roundTripLocked(cmds [][]string) ([]interface{}, error) {
+	replies, err := c.exchangeLocked(cmds)
+	if err != nil {
+		// The connection state is unknown.
+		c.closeLocked()
+		return nil, err
+	}
+
+	for _, reply := range replies {
+		if e, ok := reply.(redisError); ok {
+			return replies, e
+		}
+	}
+	return replies, nil
+}
+
+func (c *client) exchangeLocked(cmds [][]string) ([]interface{}, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+
+	for _, cmd := range cmds {
+		fmt.Fprintf(c.w, "*%d\r\n", len(cmd))
+		for _, arg := range cmd {
+			fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies := make([]interface{}, len(cmds))
+	for i := range replies {
+		reply, err := readReply(c.r)
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+// exec runs cmds in a MULTI/EXEC transaction, returning their replies.
+func (c *client) exec(cmds ...[]string) ([]interface{}, error) {
+	tx := make([][]string, 0, len(cmds)+2)
+	tx = append(tx, []string{"MULTI"})
+	tx = append(tx, cmds...)
+	tx = append(tx, []string{"EXEC"})
+
+	replies, err := c.do(tx...)
+	if err != nil {
+		return nil, err
+	}
+	results, ok := replies[len(replies)-1].([]interface{})
+	if !ok {
+		return nil, errors.New("redis: transaction aborted")
+	}
+	for _, result := range results {
+		if e, ok := result.(redisError); ok {
+			return results, e
+		}
+	}
+	return results, nil
+}
+
+// compareAndSet sets key to next if matches accepts its current value, nil for a missing key. The key is watched, so
+// that the update is aborted if another client changes it in between. Reports whether key was set.
+func (c *client) compareAndSet(key, next string, matches func(current []byte) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connectLocked(); err != nil {
+			return false, err
+		}
+	}
+
+	replies, err := c.roundTripLocked([][]string{{"WATCH", key}, {"GET", key}})
+	if err != nil {
+		return false, err
+	}
+	current, _ := replies[1].([]byte)
+	if !matches(current) {
+		_, err = c.roundTripLocked([][]string{{"UNWATCH"}})
+		return false, err
+	}
+
+	if replies, err = c.roundTripLocked([][]string{{"MULTI"}, {"SET", key, next}, {"EXEC"}}); err != nil {
+		return false, err
+	}
+	// EXEC replies nil if the key changed since WATCH.
+	_, ok := replies[2].([]interface{})
+	return ok, nil
+}
+
+func (c *client) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+func (c *client) closeLocked() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.r, c.w = nil, nil, nil
+	return err
+}
+
+// readReply reads a reply: a string for simple strings, a redisError, an int64, a []byte or nil for bulk strings,
+// and a []interface{} or nil for arrays.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.Errorf("redis: invalid reply %q", line)
+	}
+	body := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return body, nil
+	case '-':
+		return redisError(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		array := make([]interface{}, n)
+		for i := range array {
+			if array[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	}
+
+	return nil, errors.Errorf("redis: invalid reply %q", line)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultBatchInterval = 10 * time.Millisecond
+
+type batchedMessage struct {
+	seqNum int
+	msg    string
+}
+
+// messageBatch holds the outgoing messages written to the database in a single transaction.
+type messageBatch struct {
+	size     int
+	interval time.Duration
+
+	mu       sync.Mutex
+	messages []batchedMessage
+	// nextSender is the next sender MsgSeqNum to write with the batch, 0 if unchanged.
+	nextSender int
+	timer      *time.Timer
+	// err is the error of the last write on timer, returned by the next call.
+	err error
+}
+
+func (store *sqlStore) batching() bool {
+	return store.batch.size > 1
+}
+
+// addToBatch adds msg to the batch, writing the batch once full. A nextSender of 0 leaves the next sender MsgSeqNum
+// unchanged.
+func (store *sqlStore) addToBatch(seqNum int, msg []byte, nextSender int) error {
+	b := &store.batch
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.err; err != nil {
+		b.err = nil
+		return err
+	}
+
+	b.messages = append(b.messages, batchedMessage{seqNum: seqNum, msg: string(msg)})
+	if nextSender > 0 {
+		b.nextSender = nextSender
+	}
+
+	if len(b.messages) >= b.size {
+		return store.flushLocked()
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, store.flushOnTimer)
+	}
+	return nil
+}
+
+func (store *sqlStore) flushOnTimer() {
+	b := &store.batch
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.timer = nil
+	if err := store.flushLocked(); err != nil {
+		b.err = err
+	}
+}
+
+// flush writes the pending batch, if any.
+func (store *sqlStore) flush() error {
+	b := &store.batch
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.err; err != nil {
+		b.err = nil
+		return err
+	}
+	return store.flushLocked()
+}
+
+// discardBatch drops the pending batch without writing it.
+func (store *sqlStore) discardBatch() {
+	b := &store.batch
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.messages, b.nextSender, b.err = nil, 0, nil
+}
+
+// flushLocked writes the pending batch. The batch is dropped even if the write fails, as a retry would fail the same.
+func (store *sqlStore) flushLocked() error {
+	b := &store.batch
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.messages) == 0 {
+		return nil
+	}
+
+	messages, nextSender := b.messages, b.nextSender
+	b.messages, b.nextSender = nil, 0
+
+	insertMessage, err := store.insertMessageSQL()
+	if err != nil {
+		return err
+	}
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(sqlString(insertMessage, store.placeholder))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	s := store.sessionID
+	for _, m := range messages {
+		_, err = stmt.Exec(m.seqNum, m.msg,
+			s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+		if err != nil {
+			return errors.Wrapf(err, "batch insert of message %d", m.seqNum)
+		}
+	}
+
+	if nextSender > 0 {
+		_, err = tx.Exec(sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
+			nextSender, s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,100 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func createEpochsTableSQL(epochsTable string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		%s,
+		epoch BIGINT NOT NULL,
+		PRIMARY KEY (%s))`, epochsTable, idColumnsDDL, idColumns)
+}
+
+// sqlEpochStore is the sqlStore of a database holding the epochs table, implementing quickfix.EpochStore. Without
+// the table the store does not implement EpochStore, so that databases set up before the table was introduced keep
+// working, only without resumption tokens.
+type sqlEpochStore struct {
+	*sqlStore
+}
+
+// hasEpochsTable reports whether the epochs table exists, creating it if the driver supports it.
+func (store *sqlStore) hasEpochsTable() bool {
+	table := epochsTable(store.sessionsTable)
+	if rows, err := store.db.Query(fmt.Sprintf(`SELECT epoch FROM %s WHERE 1=0`, table)); err == nil {
+		_ = rows.Close()
+		return true
+	}
+	_, err := store.db.Exec(createEpochsTableSQL(table))
+	return err == nil
+}
+
+// Epoch reads the ownership epoch of the session through to the database, zero if the session was never resumed.
+func (store *sqlEpochStore) Epoch() (uint64, error) {
+	s := store.sessionID
+	var epoch int64
+	err := store.db.QueryRow(sqlString(fmt.Sprintf(`SELECT epoch FROM %s WHERE %s`,
+		epochsTable(store.sessionsTable), idWhereClause), store.placeholder),
+		s.BeginString, s.Qualifier,
+		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+		s.TargetCompID, s.TargetSubID, s.TargetLocationID).Scan(&epoch)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return uint64(epoch), err
+}
+
+// CompareAndSwapEpoch sets the ownership epoch to next if it currently is old, in a single conditional update, or
+// insert if the session was never resumed.
+func (store *sqlEpochStore) CompareAndSwapEpoch(old, next uint64) (bool, error) {
+	s := store.sessionID
+	table := epochsTable(store.sessionsTable)
+	res, err := store.db.Exec(sqlString(fmt.Sprintf(`UPDATE %s SET epoch=? WHERE %s AND epoch=?`,
+		table, idWhereClause), store.placeholder),
+		int64(next),
+		s.BeginString, s.Qualifier,
+		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+		s.TargetCompID, s.TargetSubID, s.TargetLocationID,
+		int64(old))
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 1 {
+		return n == 1, err
+	}
+	if old != 0 {
+		return false, nil
+	}
+
+	_, err = store.db.Exec(sqlString(fmt.Sprintf(`INSERT INTO %s (
+		epoch, %s) VALUES (?, %s)`, table, idColumns, idPlaceholders), store.placeholder),
+		int64(next),
+		s.BeginString, s.Qualifier,
+		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+	if err == nil {
+		return true, nil
+	}
+
+	// The insert fails on the primary key if another instance claimed the epoch first.
+	if epoch, readErr := store.Epoch(); readErr == nil && epoch != 0 {
+		return false, nil
+	}
+	return false, err
+}
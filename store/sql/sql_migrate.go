@@ -0,0 +1,171 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// schema names the store tables a migration applies to.
+type schema struct {
+	messagesTable string
+	sessionsTable string
+	timestampType string
+}
+
+// epochsTable is the table of the ownership epochs of the sessions, see quickfix.EpochStore.
+func epochsTable(sessionsTable string) string {
+	return sessionsTable + "_epochs"
+}
+
+// migration is a change of the store schema, applied once.
+type migration struct {
+	version     int
+	description string
+	statement   func(s schema) string
+}
+
+// migrations are applied in order. Released migrations must never change, add a migration instead.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create sessions table",
+		statement: func(s schema) string {
+			return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		%s,
+		creation_time %s NOT NULL,
+		incoming_seqnum INTEGER NOT NULL,
+		outgoing_seqnum INTEGER NOT NULL,
+		PRIMARY KEY (%s))`, s.sessionsTable, idColumnsDDL, s.timestampType, idColumns)
+		},
+	},
+	{
+		// The primary key ends with msgseqnum, so that resend lookups are range scans of the index.
+		version:     2,
+		description: "create messages table",
+		statement: func(s schema) string {
+			return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		%s,
+		msgseqnum INTEGER NOT NULL,
+		message TEXT NOT NULL,
+		PRIMARY KEY (%s, msgseqnum))`, s.messagesTable, idColumnsDDL, idColumns)
+		},
+	},
+	{
+		version:     3,
+		description: "create epochs table",
+		statement: func(s schema) string {
+			return createEpochsTableSQL(epochsTable(s.sessionsTable))
+		},
+	},
+}
+
+// migrateMu serializes migrations of the stores created by this process.
+var migrateMu sync.Mutex
+
+func timestampType(driver string) (string, error) {
+	switch driver {
+	case "postgres", "pgx":
+		return "TIMESTAMP WITH TIME ZONE", nil
+	case "mysql", "sqlite3":
+		return "DATETIME", nil
+	}
+	return "", errors.Errorf("migrations are not supported for driver %q", driver)
+}
+
+// Migrate creates the store tables in db, or upgrades them to the current schema. Applied migrations are recorded
+// in the "<sessionsTable>_migrations" table, so that Migrate may be called on every start. The driver is the
+// SQLStoreDriver, one of postgres, pgx, mysql or sqlite3.
+func Migrate(db *sql.DB, driver, messagesTable, sessionsTable string) error {
+	tsType, err := timestampType(driver)
+	if err != nil {
+		return err
+	}
+	var placeholder placeholderFunc
+	if driver == "postgres" || driver == "pgx" {
+		placeholder = postgresPlaceholder
+	}
+	s := schema{messagesTable: messagesTable, sessionsTable: sessionsTable, timestampType: tsType}
+	migrationsTable := sessionsTable + "_migrations"
+
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+
+	_, err = db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version INTEGER NOT NULL,
+		description VARCHAR(255) NOT NULL,
+		applied_at %s NOT NULL,
+		PRIMARY KEY (version))`, migrationsTable, tsType))
+	if err != nil {
+		return errors.Wrapf(err, "create %v", migrationsTable)
+	}
+
+	applied, err := appliedMigrations(db, migrationsTable)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		if err := applyMigration(db, placeholder, migrationsTable, m, s); err != nil {
+			return errors.Wrapf(err, "migration %d (%v)", m.version, m.description)
+		}
+	}
+	return nil
+}
+
+func appliedMigrations(db *sql.DB, migrationsTable string) (map[int]bool, error) {
+	rows, err := db.Query(fmt.Sprintf(`SELECT version FROM %s`, migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err = rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+func applyMigration(db *sql.DB, placeholder placeholderFunc, migrationsTable string, m migration, s schema) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(m.statement(s)); err != nil {
+		return err
+	}
+	_, err = tx.Exec(sqlString(fmt.Sprintf(`INSERT INTO %s (version, description, applied_at) VALUES (?, ?, ?)`,
+		migrationsTable), placeholder), m.version, m.description, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,177 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+const idColumnsDDL = `beginstring CHAR(8) NOT NULL,
+		session_qualifier VARCHAR(64) NOT NULL,
+		sendercompid VARCHAR(64) NOT NULL,
+		sendersubid VARCHAR(64) NOT NULL,
+		senderlocid VARCHAR(64) NOT NULL,
+		targetcompid VARCHAR(64) NOT NULL,
+		targetsubid VARCHAR(64) NOT NULL,
+		targetlocid VARCHAR(64) NOT NULL`
+
+// loadTradingDay returns the trading day of the session: its window starts at StartTime, or at the start of the
+// first of its SessionWindows, in the session TimeZone or UTC. The trading date of sessions without a schedule is
+// the calendar date.
+func loadTradingDay(settings *quickfix.SessionSettings) (*internal.TradingDay, error) {
+	day := &internal.TradingDay{Loc: time.UTC}
+	if settings.HasSetting(config.TimeZone) {
+		locStr, err := settings.Setting(config.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		if day.Loc, err = time.LoadLocation(locStr); err != nil {
+			return nil, errors.Wrapf(err, "problem parsing time zone '%v' for setting '%v'", locStr, config.TimeZone)
+		}
+	}
+
+	var startStr string
+	switch {
+	case settings.HasSetting(config.StartTime):
+		startStr, _ = settings.Setting(config.StartTime)
+	case settings.HasSetting(config.SessionWindows):
+		windows, _ := settings.Setting(config.SessionWindows)
+		firstWindow, _, _ := strings.Cut(windows, ",")
+		startStr, _, _ = strings.Cut(strings.TrimSpace(firstWindow), "-")
+	default:
+		return day, nil
+	}
+
+	var err error
+	if day.Start, err = internal.ParseTimeOfDay(startStr); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing the start of the session window '%v'", startStr)
+	}
+	return day, nil
+}
+
+// partitionTable returns the name of the session's partition table for the current trading date.
+// Tables are per session, so that dropping one never touches another session's messages.
+func (store *sqlStore) partitionTable() string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(store.sessionID.String()))
+	date := store.partitionDay.Date(store.now())
+	return fmt.Sprintf("%s_%08x_%s", store.messagesTable, h.Sum32(), date)
+}
+
+func (store *sqlStore) createPartitionsTable() error {
+	_, err := store.db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		partition_table VARCHAR(128) NOT NULL,
+		%s,
+		PRIMARY KEY (partition_table))`, store.partitionsTable, idColumnsDDL))
+	return err
+}
+
+// insertMessageSQL returns the statement inserting a message, creating the partition table for
+// the current trading date if required.
+func (store *sqlStore) insertMessageSQL() (string, error) {
+	if store.partitionDay == nil {
+		return store.sqlInsertMessage, nil
+	}
+
+	table := store.partitionTable()
+	if table == store.activePartition {
+		return insertMessageSQL(table), nil
+	}
+
+	_, err := store.db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		%s,
+		msgseqnum INTEGER NOT NULL,
+		message TEXT NOT NULL,
+		PRIMARY KEY (%s, msgseqnum))`, table, idColumnsDDL, idColumns))
+	if err != nil {
+		return "", errors.Wrapf(err, "create partition %v", table)
+	}
+
+	s := store.sessionID
+	_, err = store.db.Exec(sqlString(store.registerPartitionSQL(), store.placeholder),
+		table, s.BeginString, s.Qualifier,
+		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+	if err != nil {
+		return "", errors.Wrapf(err, "register partition %v", table)
+	}
+
+	store.activePartition = table
+	return insertMessageSQL(table), nil
+}
+
+// registerPartitionSQL returns the statement adding a partition table to the partitions table, doing nothing if
+// it is registered already, so that stores of the same session never race between checking and inserting.
+func (store *sqlStore) registerPartitionSQL() string {
+	insert := "INSERT"
+	conflict := " ON CONFLICT (partition_table) DO NOTHING"
+	if store.sqlDriver == "mysql" {
+		insert, conflict = "INSERT IGNORE", ""
+	}
+	return fmt.Sprintf(`%s INTO %s (partition_table, %s) VALUES (?, %s)%s`,
+		insert, store.partitionsTable, idColumns, idPlaceholders, conflict)
+}
+
+// partitionTables returns the session's partition tables, oldest trading date first.
+func (store *sqlStore) partitionTables() ([]string, error) {
+	s := store.sessionID
+	rows, err := store.db.Query(sqlString(fmt.Sprintf(`SELECT partition_table FROM %s WHERE %s ORDER BY partition_table`,
+		store.partitionsTable, idWhereClause), store.placeholder),
+		s.BeginString, s.Qualifier,
+		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// dropPartitions drops all of the session's partition tables.
+func (store *sqlStore) dropPartitions() error {
+	tables, err := store.partitionTables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err = store.db.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS %s`, table)); err != nil {
+			return errors.Wrapf(err, "drop partition %v", table)
+		}
+		if _, err = store.db.Exec(sqlString(fmt.Sprintf(`DELETE FROM %s WHERE partition_table=?`, store.partitionsTable), store.placeholder), table); err != nil {
+			return err
+		}
+	}
+	store.activePartition = ""
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
 )
 
 const (
@@ -47,6 +48,14 @@ type sqlStore struct {
 	messagesTable      string
 	sessionsTable      string
 
+	// When partitionDay is set, messages are written to one table per trading date.
+	partitionDay    *internal.TradingDay
+	partitionsTable string
+	activePartition string
+	now             func() time.Time
+
+	batch messageBatch
+
 	sqlUpdateSeqNums      string
 	sqlInsertSession      string
 	sqlGetSeqNums         string
@@ -59,6 +68,13 @@ type sqlStore struct {
 	sqlDeleteMessages     string
 }
 
+// storeOptions are the optional behaviours of the store.
+type storeOptions struct {
+	migrate       bool
+	batchSize     int
+	batchInterval time.Duration
+}
+
 type placeholderFunc func(int) string
 
 var rePlaceholder = regexp.MustCompile(`\?`)
@@ -125,10 +141,53 @@ func (f sqlStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix
 		}
 	}
 
-	return newSQLStore(sessionID, sqlDriver, sqlDataSourceName, messagesTableName, sessionsTableName, sqlConnMaxLifetime)
+	var partitionDay *internal.TradingDay
+	if sessionSettings.HasSetting(config.SQLStorePartitionByDate) {
+		partitionByDate, err := sessionSettings.BoolSetting(config.SQLStorePartitionByDate)
+		if err != nil {
+			return nil, err
+		}
+		if partitionByDate {
+			if partitionDay, err = loadTradingDay(sessionSettings); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	opts := storeOptions{batchSize: 1, batchInterval: defaultBatchInterval}
+	if sessionSettings.HasSetting(config.SQLStoreMigrate) {
+		if opts.migrate, err = sessionSettings.BoolSetting(config.SQLStoreMigrate); err != nil {
+			return nil, err
+		}
+	}
+	if sessionSettings.HasSetting(config.SQLStoreBatchSize) {
+		if opts.batchSize, err = sessionSettings.IntSetting(config.SQLStoreBatchSize); err != nil {
+			return nil, err
+		}
+		if opts.batchSize <= 0 {
+			return nil, errors.Errorf("%v must be greater than zero", config.SQLStoreBatchSize)
+		}
+	}
+	if sessionSettings.HasSetting(config.SQLStoreBatchInterval) {
+		if opts.batchInterval, err = sessionSettings.DurationSetting(config.SQLStoreBatchInterval); err != nil {
+			return nil, err
+		}
+		if opts.batchInterval <= 0 {
+			return nil, errors.Errorf("%v must be greater than zero", config.SQLStoreBatchInterval)
+		}
+	}
+
+	store, err := newSQLStore(sessionID, sqlDriver, sqlDataSourceName, messagesTableName, sessionsTableName, sqlConnMaxLifetime, partitionDay, opts)
+	if err != nil {
+		return nil, err
+	}
+	if store.hasEpochsTable() {
+		return &sqlEpochStore{sqlStore: store}, nil
+	}
+	return store, nil
 }
 
-func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesTableName, sessionsTableName string, connMaxLifetime time.Duration) (store *sqlStore, err error) {
+func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesTableName, sessionsTableName string, connMaxLifetime time.Duration, partitionDay *internal.TradingDay, opts storeOptions) (store *sqlStore, err error) {
 
 	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
 	if memErr != nil {
@@ -144,6 +203,10 @@ func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesT
 		sqlConnMaxLifetime: connMaxLifetime,
 		messagesTable:      messagesTableName,
 		sessionsTable:      sessionsTableName,
+		partitionDay:       partitionDay,
+		partitionsTable:    messagesTableName + "_partitions",
+		now:                time.Now,
+		batch:              messageBatch{size: opts.batchSize, interval: opts.batchInterval},
 	}
 	if err = store.cache.Reset(); err != nil {
 		err = errors.Wrap(err, "cache reset")
@@ -163,8 +226,20 @@ func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesT
 		return nil, err
 	}
 
+	if opts.migrate {
+		if err = Migrate(store.db, store.sqlDriver, store.messagesTable, store.sessionsTable); err != nil {
+			return nil, err
+		}
+	}
+
 	store.setSQLStatements()
 
+	if store.partitionDay != nil {
+		if err = store.createPartitionsTable(); err != nil {
+			return nil, err
+		}
+	}
+
 	if err = store.populateCache(); err != nil {
 		return nil, err
 	}
@@ -172,20 +247,31 @@ func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesT
 	return store, nil
 }
 
-func (store *sqlStore) setSQLStatements() {
-	idColumns := `beginstring, session_qualifier, sendercompid, sendersubid, senderlocid, targetcompid, targetsubid, targetlocid`
-	idPlaceholders := `?,?,?,?,?,?,?,?`
-	idWhereClause := `beginstring=? AND session_qualifier=? AND sendercompid=? AND sendersubid=? AND senderlocid=? AND targetcompid=? AND targetsubid=? AND targetlocid=?`
+const (
+	idColumns      = `beginstring, session_qualifier, sendercompid, sendersubid, senderlocid, targetcompid, targetsubid, targetlocid`
+	idPlaceholders = `?,?,?,?,?,?,?,?`
+	idWhereClause  = `beginstring=? AND session_qualifier=? AND sendercompid=? AND sendersubid=? AND senderlocid=? AND targetcompid=? AND targetsubid=? AND targetlocid=?`
+)
 
-	store.sqlInsertMessage = fmt.Sprintf(`INSERT INTO %s (
+func insertMessageSQL(messagesTable string) string {
+	return fmt.Sprintf(`INSERT INTO %s (
 		msgseqnum, message, %s) VALUES (?, ?, %s)`,
-		store.messagesTable, idColumns, idPlaceholders)
+		messagesTable, idColumns, idPlaceholders)
+}
+
+func getMessagesSQL(messagesTable string) string {
+	return fmt.Sprintf(`SELECT message FROM %s WHERE %s AND msgseqnum>=? AND msgseqnum<=? ORDER BY msgseqnum`,
+		messagesTable, idWhereClause)
+}
+
+func (store *sqlStore) setSQLStatements() {
+
+	store.sqlInsertMessage = insertMessageSQL(store.messagesTable)
 
 	store.sqlUpdateMessage = fmt.Sprintf(`UPDATE %s SET message=? WHERE %s AND msgseqnum=?`,
 		store.messagesTable, idWhereClause)
 
-	store.sqlGetMessages = fmt.Sprintf(`SELECT message FROM %s WHERE %s AND msgseqnum>=? AND msgseqnum<=? ORDER BY msgseqnum`,
-		store.messagesTable, idWhereClause)
+	store.sqlGetMessages = getMessagesSQL(store.messagesTable)
 
 	store.sqlDeleteMessages = fmt.Sprintf(`DELETE FROM %s WHERE %s`,
 		store.messagesTable, idWhereClause)
@@ -212,11 +298,18 @@ func (store *sqlStore) setSQLStatements() {
 
 // Reset deletes the store records and sets the seqnums back to 1.
 func (store *sqlStore) Reset() error {
+	store.discardBatch()
+
 	s := store.sessionID
-	_, err := store.db.Exec(sqlString(store.sqlDeleteMessages, store.placeholder),
-		s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+	var err error
+	if store.partitionDay != nil {
+		err = store.dropPartitions()
+	} else {
+		_, err = store.db.Exec(sqlString(store.sqlDeleteMessages, store.placeholder),
+			s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+	}
 	if err != nil {
 		return err
 	}
@@ -236,6 +329,9 @@ func (store *sqlStore) Reset() error {
 
 // Refresh reloads the store from the database.
 func (store *sqlStore) Refresh() error {
+	if err := store.flush(); err != nil {
+		return err
+	}
 	if err := store.cache.Reset(); err != nil {
 		return err
 	}
@@ -294,6 +390,11 @@ func (store *sqlStore) NextTargetMsgSeqNum() int {
 
 // SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
 func (store *sqlStore) SetNextSenderMsgSeqNum(next int) error {
+	// Write any batch first, so that its next sender MsgSeqNum does not overwrite next.
+	if err := store.flush(); err != nil {
+		return err
+	}
+
 	s := store.sessionID
 	_, err := store.db.Exec(sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
 		next, s.BeginString, s.Qualifier,
@@ -344,9 +445,18 @@ func (store *sqlStore) SetCreationTime(_ time.Time) {
 }
 
 func (store *sqlStore) SaveMessage(seqNum int, msg []byte) error {
+	if store.batching() {
+		return store.addToBatch(seqNum, msg, 0)
+	}
+
 	s := store.sessionID
 
-	_, err := store.db.Exec(sqlString(store.sqlInsertMessage, store.placeholder),
+	insertMessage, err := store.insertMessageSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = store.db.Exec(sqlString(insertMessage, store.placeholder),
 		seqNum, string(msg),
 		s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
@@ -356,15 +466,28 @@ func (store *sqlStore) SaveMessage(seqNum int, msg []byte) error {
 }
 
 func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	if store.batching() {
+		next := store.cache.NextSenderMsgSeqNum() + 1
+		if err := store.addToBatch(seqNum, msg, next); err != nil {
+			return err
+		}
+		return store.cache.SetNextSenderMsgSeqNum(next)
+	}
+
 	s := store.sessionID
 
+	insertMessage, err := store.insertMessageSQL()
+	if err != nil {
+		return err
+	}
+
 	tx, err := store.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec(sqlString(store.sqlInsertMessage, store.placeholder),
+	_, err = tx.Exec(sqlString(insertMessage, store.placeholder),
 		seqNum, string(msg),
 		s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
@@ -391,8 +514,30 @@ func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []b
 }
 
 func (store *sqlStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	if err := store.flush(); err != nil {
+		return err
+	}
+
+	if store.partitionDay == nil {
+		return store.iterateMessages(store.sqlGetMessages, beginSeqNum, endSeqNum, cb)
+	}
+
+	// Read across partitions in trading date order, a resend may span the partition boundary.
+	partitions, err := store.partitionTables()
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		if err := store.iterateMessages(getMessagesSQL(partition), beginSeqNum, endSeqNum, cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *sqlStore) iterateMessages(getMessages string, beginSeqNum, endSeqNum int, cb func([]byte) error) error {
 	s := store.sessionID
-	rows, err := store.db.Query(sqlString(store.sqlGetMessages, store.placeholder),
+	rows, err := store.db.Query(sqlString(getMessages, store.placeholder),
 		s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
 		s.TargetCompID, s.TargetSubID, s.TargetLocationID,
@@ -423,11 +568,13 @@ func (store *sqlStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error)
 	return msgs, err
 }
 
-// Close closes the store's database connection.
+// Close writes any pending batch and closes the store's database connection.
 func (store *sqlStore) Close() error {
-	if store.db != nil {
-		store.db.Close()
-		store.db = nil
+	if store.db == nil {
+		return nil
 	}
-	return nil
+	err := store.flush()
+	store.db.Close()
+	store.db = nil
+	return err
 }
@@ -27,6 +27,7 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal"
 	"github.com/quickfixgo/quickfix/internal/testsuite"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -167,3 +168,201 @@ func (suite *SQLStoreTestSuite) TearDownTest() {
 func TestSqlStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(SQLStoreTestSuite))
 }
+
+// PartitionedSQLStoreTestSuite runs all tests in the MessageStoreTestSuite against a SqlStore partitioned by date.
+type PartitionedSQLStoreTestSuite struct {
+	SQLStoreTestSuite
+}
+
+func (suite *PartitionedSQLStoreTestSuite) SetupTest() {
+	suite.SQLStoreTestSuite.SetupTest()
+	suite.MsgStore.Close()
+
+	store := suite.MsgStore.(*sqlEpochStore).sqlStore
+	var err error
+	suite.MsgStore, err = newSQLStore(store.sessionID, store.sqlDriver, store.sqlDataSourceName,
+		defaultMessagesTable, defaultSessionsTable, 0, &internal.TradingDay{Loc: time.UTC}, storeOptions{batchSize: 1})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *PartitionedSQLStoreTestSuite) TestReadAcrossPartitionBoundary() {
+	store := suite.MsgStore.(*sqlStore)
+	now := time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	suite.Require().Nil(store.SaveMessage(1, []byte("late")))
+	now = now.Add(2 * time.Minute)
+	suite.Require().Nil(store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("early")))
+	suite.Require().Nil(store.SaveMessage(3, []byte("later")))
+
+	tables, err := store.partitionTables()
+	suite.Require().Nil(err)
+	suite.Require().Len(tables, 2)
+	suite.Contains(tables[0], "_20240301")
+	suite.Contains(tables[1], "_20240302")
+
+	msgs, err := store.GetMessages(1, 3)
+	suite.Require().Nil(err)
+	suite.Equal([][]byte{[]byte("late"), []byte("early"), []byte("later")}, msgs)
+
+	suite.Require().Nil(store.Reset())
+	tables, err = store.partitionTables()
+	suite.Require().Nil(err)
+	suite.Empty(tables)
+
+	msgs, err = store.GetMessages(1, 3)
+	suite.Require().Nil(err)
+	suite.Empty(msgs)
+
+	suite.Require().Nil(store.SaveMessage(1, []byte("again")), "partition should be recreated after a reset")
+}
+
+func (suite *PartitionedSQLStoreTestSuite) TestPartitionFollowsSessionWindow() {
+	store := suite.MsgStore.(*sqlStore)
+	store.partitionDay.Start = internal.NewTimeOfDay(17, 0, 0)
+	now := time.Date(2024, 3, 1, 17, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	suite.Require().Nil(store.SaveMessage(1, []byte("evening")))
+	now = now.Add(12 * time.Hour)
+	suite.Require().Nil(store.SaveMessage(2, []byte("morning")))
+
+	tables, err := store.partitionTables()
+	suite.Require().Nil(err)
+	suite.Require().Len(tables, 1, "the session window spans midnight")
+	suite.Contains(tables[0], "_20240301")
+}
+
+func (suite *PartitionedSQLStoreTestSuite) TestRegisterPartitionTwice() {
+	store := suite.MsgStore.(*sqlStore)
+	suite.Require().Nil(store.SaveMessage(1, []byte("one")))
+
+	// Another store of the session registers the partition again.
+	store.activePartition = ""
+	suite.Require().Nil(store.SaveMessage(2, []byte("two")))
+
+	tables, err := store.partitionTables()
+	suite.Require().Nil(err)
+	suite.Len(tables, 1)
+}
+
+func TestPartitionedSQLStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(PartitionedSQLStoreTestSuite))
+}
+
+// MigratedSQLStoreTestSuite runs all tests in the MessageStoreTestSuite against a SqlStore with tables created by
+// Migrate, batching outgoing messages.
+type MigratedSQLStoreTestSuite struct {
+	SQLStoreTestSuite
+	sqlDsn string
+}
+
+func (suite *MigratedSQLStoreTestSuite) SetupTest() {
+	suite.sqlStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("MigratedSqlStoreTestSuite-%d", os.Getpid()))
+	require.Nil(suite.T(), os.MkdirAll(suite.sqlStoreRootPath, os.ModePerm))
+	suite.sqlDsn = path.Join(suite.sqlStoreRootPath, fmt.Sprintf("%d.db", time.Now().UnixNano()))
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=sqlite3
+SQLStoreDataSourceName=%s
+SQLStoreMigrate=Y
+SQLStoreBatchSize=3
+SQLStoreBatchInterval=1h
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, suite.sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	suite.MsgStore, err = NewStoreFactory(settings).Create(sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *MigratedSQLStoreTestSuite) queryInt(query string) int {
+	db, err := sql.Open("sqlite3", suite.sqlDsn)
+	suite.Require().Nil(err)
+	defer db.Close()
+
+	var count int
+	suite.Require().Nil(db.QueryRow(query).Scan(&count))
+	return count
+}
+
+func (suite *MigratedSQLStoreTestSuite) TestMigrationsRecorded() {
+	suite.Equal(len(migrations), suite.queryInt(`SELECT COUNT(*) FROM sessions_migrations`))
+
+	// Migrating again is a no-op.
+	store := suite.MsgStore.(*sqlEpochStore).sqlStore
+	suite.Require().Nil(Migrate(store.db, "sqlite3", defaultMessagesTable, defaultSessionsTable))
+	suite.Equal(len(migrations), suite.queryInt(`SELECT COUNT(*) FROM sessions_migrations`))
+}
+
+func (suite *MigratedSQLStoreTestSuite) TestBatchedInserts() {
+	store := suite.MsgStore.(*sqlEpochStore).sqlStore
+	suite.Require().Nil(store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("one")))
+	suite.Require().Nil(store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("two")))
+	suite.Equal(3, store.NextSenderMsgSeqNum())
+	suite.Equal(0, suite.queryInt(`SELECT COUNT(*) FROM messages`), "batch should not be written before it is full")
+	suite.Equal(1, suite.queryInt(`SELECT outgoing_seqnum FROM sessions`))
+
+	suite.Require().Nil(store.SaveMessageAndIncrNextSenderMsgSeqNum(3, []byte("three")))
+	suite.Equal(3, suite.queryInt(`SELECT COUNT(*) FROM messages`))
+	suite.Equal(4, suite.queryInt(`SELECT outgoing_seqnum FROM sessions`))
+
+	// Resend lookups include the pending batch.
+	suite.Require().Nil(store.SaveMessage(4, []byte("four")))
+	msgs, err := store.GetMessages(3, 4)
+	suite.Require().Nil(err)
+	suite.Equal([][]byte{[]byte("three"), []byte("four")}, msgs)
+}
+
+func (suite *MigratedSQLStoreTestSuite) TestBatchWrittenAfterInterval() {
+	store := suite.MsgStore.(*sqlEpochStore).sqlStore
+	store.batch.interval = time.Millisecond
+	suite.Require().Nil(store.SaveMessage(1, []byte("one")))
+
+	suite.Eventually(func() bool {
+		return suite.queryInt(`SELECT COUNT(*) FROM messages`) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (suite *MigratedSQLStoreTestSuite) TestResetDiscardsBatch() {
+	store := suite.MsgStore.(*sqlEpochStore).sqlStore
+	suite.Require().Nil(store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("one")))
+	suite.Require().Nil(store.Reset())
+	suite.Require().Nil(store.Refresh())
+
+	suite.Equal(1, store.NextSenderMsgSeqNum())
+	suite.Equal(0, suite.queryInt(`SELECT COUNT(*) FROM messages`))
+}
+
+func TestMigratedSQLStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(MigratedSQLStoreTestSuite))
+}
+
+func TestMigrateExistingSchema(t *testing.T) {
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", path.Join(dir, "existing.db"))
+	require.Nil(t, err)
+	defer db.Close()
+
+	ddlFnames, err := filepath.Glob("../../_sql/sqlite3/*.sql")
+	require.Nil(t, err)
+	for _, fname := range ddlFnames {
+		sqlBytes, err := os.ReadFile(fname)
+		require.Nil(t, err)
+		_, err = db.Exec(string(sqlBytes))
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, Migrate(db, "sqlite3", defaultMessagesTable, defaultSessionsTable))
+
+	var count int
+	require.Nil(t, db.QueryRow(`SELECT COUNT(*) FROM sessions_migrations`).Scan(&count))
+	require.Equal(t, len(migrations), count)
+
+	require.NotNil(t, Migrate(db, "oracle", defaultMessagesTable, defaultSessionsTable))
+}
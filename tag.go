@@ -55,22 +55,32 @@ const (
 	tagHopSendingTime         Tag = 629
 	tagHopRefID               Tag = 630
 
-	tagHeartBtInt           Tag = 108
-	tagBusinessRejectReason Tag = 380
-	tagSessionRejectReason  Tag = 373
-	tagRefMsgType           Tag = 372
-	tagBusinessRejectRefID  Tag = 379
-	tagRefTagID             Tag = 371
-	tagRefSeqNum            Tag = 45
-	tagEncryptMethod        Tag = 98
-	tagResetSeqNumFlag      Tag = 141
-	tagDefaultApplVerID     Tag = 1137
-	tagText                 Tag = 58
-	tagTestReqID            Tag = 112
-	tagGapFillFlag          Tag = 123
-	tagNewSeqNo             Tag = 36
-	tagBeginSeqNo           Tag = 7
-	tagEndSeqNo             Tag = 16
+	tagHeartBtInt            Tag = 108
+	tagBusinessRejectReason  Tag = 380
+	tagSessionRejectReason   Tag = 373
+	tagRefMsgType            Tag = 372
+	tagBusinessRejectRefID   Tag = 379
+	tagRefTagID              Tag = 371
+	tagRefSeqNum             Tag = 45
+	tagEncryptMethod         Tag = 98
+	tagResetSeqNumFlag       Tag = 141
+	tagDefaultApplVerID      Tag = 1137
+	tagText                  Tag = 58
+	tagTestReqID             Tag = 112
+	tagGapFillFlag           Tag = 123
+	tagNewSeqNo              Tag = 36
+	tagBeginSeqNo            Tag = 7
+	tagEndSeqNo              Tag = 16
+	tagClOrdID               Tag = 11
+	tagOrderQty              Tag = 38
+	tagPrice                 Tag = 44
+	tagOrigClOrdID           Tag = 41
+	tagSymbol                Tag = 55
+	tagSide                  Tag = 54
+	tagTransactTime          Tag = 60
+	tagOrdStatus             Tag = 39
+	tagMassCancelRequestType Tag = 530
+	tagMassCancelResponse    Tag = 531
 
 	tagSignatureLength Tag = 93
 	tagSignature       Tag = 89
@@ -18,9 +18,11 @@ package quickfix
 import (
 	"bytes"
 	"fmt"
-	"strconv"
 )
 
+// maxTagWidth is the number of digits of the largest tag.
+const maxTagWidth = 10
+
 // TagValue is a low-level FIX field abstraction.
 type TagValue struct {
 	tag   Tag
@@ -29,13 +31,15 @@ type TagValue struct {
 }
 
 func (tv *TagValue) init(tag Tag, value []byte) {
-	tv.bytes = strconv.AppendInt(nil, int64(tag), 10)
-	tv.bytes = append(tv.bytes, []byte("=")...)
+	tv.bytes = make([]byte, 0, maxTagWidth+len(value)+2)
+	tv.bytes = appendInt(tv.bytes, int(tag))
+	tv.bytes = append(tv.bytes, '=')
+	start := len(tv.bytes)
 	tv.bytes = append(tv.bytes, value...)
-	tv.bytes = append(tv.bytes, []byte("")...)
+	tv.bytes = append(tv.bytes, '\001')
 
 	tv.tag = tag
-	tv.value = value
+	tv.value = tv.bytes[start : len(tv.bytes)-1 : len(tv.bytes)-1]
 }
 
 func (tv *TagValue) parse(rawFieldBytes []byte) error {
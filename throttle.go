@@ -0,0 +1,150 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"time"
+)
+
+// OutboundThrottledHandler is an optional interface implemented by an Application to decide about the outbound
+// application messages exceeding MaxMessagesPerSecond, when ThrottlePolicy is CALLBACK.
+type OutboundThrottledHandler interface {
+	// OnOutboundThrottled is called once per throttled message. It returns true to keep the message in the send queue
+	// until the rate allows it, false to drop it. It is called while the send queue is locked and must not send
+	// messages.
+	OnOutboundThrottled(msg PendingMessage, sessionID SessionID) bool
+}
+
+type throttlePolicy int
+
+const (
+	throttleQueue throttlePolicy = iota
+	throttleDrop
+	throttleCallback
+)
+
+// throttle is a token bucket limiting the rate of the outbound application messages, see MaxMessagesPerSecond.
+// It is guarded by the sendMutex of the session.
+type throttle struct {
+	rate   float64
+	burst  float64
+	policy throttlePolicy
+
+	tokens float64
+	last   time.Time
+
+	// Wakes the session up once the rate allows the next message.
+	wake *time.Timer
+
+	// MsgSeqNum of the last message passed to OnOutboundThrottled.
+	decided int
+}
+
+func newThrottle(maxMessagesPerSecond, burstSize int, policy throttlePolicy) *throttle {
+	return &throttle{
+		rate:   float64(maxMessagesPerSecond),
+		burst:  float64(burstSize),
+		policy: policy,
+		tokens: float64(burstSize),
+	}
+}
+
+// take takes a token at now, returning how long to wait for the next token if there is none.
+func (t *throttle) take(now time.Time) time.Duration {
+	if now.After(t.last) {
+		if !t.last.IsZero() {
+			t.tokens += now.Sub(t.last).Seconds() * t.rate
+			if t.tokens > t.burst {
+				t.tokens = t.burst
+			}
+		}
+		t.last = now
+	}
+
+	if t.tokens >= 1 {
+		t.tokens--
+		return 0
+	}
+	return time.Duration((1 - t.tokens) / t.rate * float64(time.Second))
+}
+
+// schedule calls wake after wait, replacing the call already scheduled.
+func (t *throttle) schedule(wait time.Duration, wake func()) {
+	if t.wake == nil {
+		t.wake = time.AfterFunc(wait, wake)
+		return
+	}
+	t.wake.Reset(wait)
+}
+
+// allowedByThrottle returns true if the queued message i can be sent now: it is not an application message, the
+// rate allows it, or it was dropped and replaced by a gap fill. Otherwise the session sends it once the rate allows.
+// The caller holds sendMutex.
+func (s *Session) allowedByThrottle(i int) bool {
+	t := s.throttle
+	if t == nil || isAdminMessageType(queuedMsgType(s.toSend[i])) {
+		return true
+	}
+
+	wait := t.take(s.now())
+	if wait == 0 {
+		return true
+	}
+
+	if t.policy != throttleQueue {
+		if p, ok := s.parsePending(s.toSend[i]); ok && p.ID != t.decided {
+			t.decided = p.ID
+			if t.policy == throttleDrop || !s.keepThrottled(p) {
+				s.dropThrottled(i, p)
+				return true
+			}
+		}
+	}
+
+	t.schedule(wait, s.notifyMessageOut)
+	return false
+}
+
+// keepThrottled returns true if the Application keeps the throttled message p queued.
+func (s *Session) keepThrottled(p PendingMessage) bool {
+	if handler, ok := s.application.(OutboundThrottledHandler); ok {
+		return handler.OnOutboundThrottled(p, s.sessionID)
+	}
+	return true
+}
+
+// dropThrottled replaces the queued message i by a SequenceReset-GapFill, as its MsgSeqNum is already assigned.
+// The caller holds sendMutex.
+func (s *Session) dropThrottled(i int, p PendingMessage) {
+	s.toSend[i] = s.buildGapFill(p.ID)
+	s.canceled.add(p.ID)
+	s.acks.forget(p.ID)
+	s.log.OnEventf("Dropped throttled message %v (MsgType %v)", p.ID, p.MsgType)
+}
+
+// queuedMsgType returns the MsgType of the queued msgBytes, nil if it has none.
+func queuedMsgType(msgBytes []byte) []byte {
+	i := bytes.Index(msgBytes, []byte("\00135="))
+	if i < 0 {
+		return nil
+	}
+	msgType := msgBytes[i+4:]
+	if end := bytes.IndexByte(msgType, '\001'); end >= 0 {
+		return msgType[:end]
+	}
+	return nil
+}
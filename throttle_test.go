@@ -0,0 +1,175 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ThrottleTestSuite struct {
+	SessionSuiteRig
+	now       time.Time
+	throttled []PendingMessage
+	keep      bool
+}
+
+func TestThrottleTestSuite(t *testing.T) {
+	suite.Run(t, new(ThrottleTestSuite))
+}
+
+type throttledApp struct {
+	*MockApp
+	suite *ThrottleTestSuite
+}
+
+func (a *throttledApp) OnOutboundThrottled(msg PendingMessage, _ SessionID) bool {
+	a.suite.throttled = append(a.suite.throttled, msg)
+	return a.suite.keep
+}
+
+func (s *ThrottleTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.application = &throttledApp{MockApp: &s.MockApp, suite: s}
+	s.now = time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	s.Session.clock = fixedClock{s.now}
+	s.throttled = nil
+	s.keep = false
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+}
+
+func (s *ThrottleTestSuite) advance(d time.Duration) {
+	s.now = s.now.Add(d)
+	s.Session.clock = fixedClock{s.now}
+}
+
+func (s *ThrottleTestSuite) sendOrder(clOrdID string) {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("D"))
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	s.Require().Nil(s.Session.send(msg))
+}
+
+func (s *ThrottleTestSuite) sendQueued() {
+	s.Session.sendMutex.Lock()
+	defer s.Session.sendMutex.Unlock()
+	s.Session.sendQueued(false)
+}
+
+// sent returns the messages sent since the last call.
+func (s *ThrottleTestSuite) sent() []*Message {
+	var msgs []*Message
+	for {
+		select {
+		case msgBytes := <-s.Receiver.sendChannel:
+			msg := NewMessage()
+			s.Require().Nil(ParseMessage(msg, bytes.NewBuffer(msgBytes)))
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+func (s *ThrottleTestSuite) TestQueue() {
+	s.Session.throttle = newThrottle(2, 2, throttleQueue)
+	defer func() { s.Session.throttle.wake.Stop() }()
+
+	s.sendOrder("order1")
+	s.sendOrder("order2")
+	s.sendOrder("order3")
+	s.Require().Nil(s.Session.send(s.Heartbeat()))
+
+	s.Len(s.sent(), 2, "the burst is sent at once")
+	s.Len(s.Session.toSend, 2, "the heartbeat waits behind the throttled order")
+
+	s.advance(100 * time.Millisecond)
+	s.sendQueued()
+	s.Empty(s.sent())
+
+	s.advance(400 * time.Millisecond)
+	s.sendQueued()
+	msgs := s.sent()
+	s.Require().Len(msgs, 2)
+	s.FieldEquals(tagClOrdID, "order3", msgs[0].Body)
+	s.MessageType(string(msgTypeHeartbeat), msgs[1])
+	s.NoMessageQueued()
+	s.NotNil(s.Session.throttle.wake, "the session is woken up once the rate allows the next message")
+}
+
+func (s *ThrottleTestSuite) TestAdminMessagesNotThrottled() {
+	s.Session.throttle = newThrottle(1, 1, throttleQueue)
+
+	s.sendOrder("order1")
+	s.Require().Nil(s.Session.send(s.Heartbeat()))
+	s.Require().Nil(s.Session.send(s.Heartbeat()))
+	s.Len(s.sent(), 3)
+	s.NoMessageQueued()
+}
+
+func (s *ThrottleTestSuite) TestDrop() {
+	s.Session.throttle = newThrottle(1, 1, throttleDrop)
+
+	s.sendOrder("order1")
+	s.sendOrder("order2")
+
+	msgs := s.sent()
+	s.Require().Len(msgs, 2)
+	s.FieldEquals(tagClOrdID, "order1", msgs[0].Body)
+	s.MessageType(string(msgTypeSequenceReset), msgs[1])
+	s.FieldEquals(tagMsgSeqNum, 2, msgs[1].Header)
+	s.FieldEquals(tagNewSeqNo, 3, msgs[1].Body)
+	s.FieldEquals(tagGapFillFlag, true, msgs[1].Body)
+	s.NoMessageQueued()
+	s.True(s.Session.canceled.has(2), "the dropped message is gap filled when resent")
+}
+
+func (s *ThrottleTestSuite) TestCallback() {
+	s.Session.throttle = newThrottle(1, 1, throttleCallback)
+	defer func() { s.Session.throttle.wake.Stop() }()
+
+	s.keep = true
+	s.sendOrder("order1")
+	s.sendOrder("order2")
+	s.Len(s.sent(), 1)
+	s.Require().Len(s.throttled, 1)
+	s.Equal(2, s.throttled[0].ID)
+	s.FieldEquals(tagClOrdID, "order2", s.throttled[0].Message.Body)
+
+	s.advance(100 * time.Millisecond)
+	s.sendQueued()
+	s.Len(s.throttled, 1, "the application decides once per message")
+
+	s.keep = false
+	s.sendOrder("order3")
+	s.Len(s.throttled, 1, "the queued message is still throttled")
+
+	s.advance(900 * time.Millisecond)
+	s.sendQueued()
+	msgs := s.sent()
+	s.Require().Len(msgs, 2)
+	s.FieldEquals(tagClOrdID, "order2", msgs[0].Body)
+	s.MessageType(string(msgTypeSequenceReset), msgs[1])
+	s.FieldEquals(tagMsgSeqNum, 3, msgs[1].Header)
+	s.Require().Len(s.throttled, 2)
+	s.Equal(3, s.throttled[1].ID)
+}
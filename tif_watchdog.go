@@ -0,0 +1,234 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TimeInForceDay is the TimeInForce of day orders, the default of orders without TimeInForce.
+const TimeInForceDay = "0"
+
+// DayOrderHandler is an optional interface implemented by an Application notified by a TIFWatchdog of the resting
+// day orders of a session, Lead before the end of its session time or before a cutoff. reason is "session end" or
+// the cutoff, e.g. "cutoff 16:00:00".
+type DayOrderHandler interface {
+	OnRestingDayOrders(orders []OpenOrder, reason string, sessionID SessionID)
+}
+
+// TIFWatchdogOptions configure a TIFWatchdog.
+type TIFWatchdogOptions struct {
+	// Sessions are the sessions whose day orders are watched.
+	Sessions []SessionID
+
+	// Orders provides the open orders of the sessions, such as an orderstate.Tracker.
+	Orders OrderSource
+
+	// Lead is how long before the end of the session time, and before each of Cutoffs, the TIFWatchdog runs, so
+	// that cancels are sent before the session logs out. 1 minute if zero.
+	Lead time.Duration
+
+	// Cutoffs are times of day, in the format HH:MM:SS and the TimeZone of each session, the TIFWatchdog runs at
+	// too, e.g. the order cutoff of the venue.
+	Cutoffs []string
+
+	// Cancel sends an OrderCancelRequest for each resting day order, in addition to notifying the DayOrderHandler.
+	Cancel bool
+}
+
+// TIFWatchdog handles the resting day orders of sessions before their session time ends, and before configured
+// cutoffs: the Application is notified if it implements DayOrderHandler, and cancels are sent with Cancel. The
+// TIFWatchdog runs on the ticks of each session, before the session checks its session time, so that it runs
+// before the session logs out.
+type TIFWatchdog struct {
+	opts    TIFWatchdogOptions
+	cutoffs []tifCutoff
+
+	mu     sync.Mutex
+	states map[SessionID]*tifWatchdogState
+	lastID int
+}
+
+// tifCutoff is a time of day of TIFWatchdogOptions.Cutoffs.
+type tifCutoff struct {
+	name                 string
+	hour, minute, second int
+}
+
+// tifWatchdogState is the progress of a TIFWatchdog for a session.
+type tifWatchdogState struct {
+	// lastCheck is the time of the previous tick of the session.
+	lastCheck time.Time
+	// endHandled is when the end of the session time was last handled.
+	endHandled time.Time
+}
+
+// NewTIFWatchdog returns a TIFWatchdog attached to the sessions of opts, which must exist.
+func NewTIFWatchdog(opts TIFWatchdogOptions) (*TIFWatchdog, error) {
+	if opts.Orders == nil {
+		return nil, errors.New("Orders is required")
+	}
+	if opts.Lead == 0 {
+		opts.Lead = time.Minute
+	}
+	if opts.Lead < 0 {
+		return nil, errors.New("Lead must be greater than zero")
+	}
+
+	w := &TIFWatchdog{opts: opts, states: make(map[SessionID]*tifWatchdogState)}
+	for _, cutoff := range opts.Cutoffs {
+		t, err := time.Parse("15:04:05", cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("cutoff %q must be in the format HH:MM:SS: %w", cutoff, err)
+		}
+		hour, minute, second := t.Clock()
+		w.cutoffs = append(w.cutoffs, tifCutoff{name: cutoff, hour: hour, minute: minute, second: second})
+	}
+
+	for _, sessionID := range opts.Sessions {
+		session, ok := lookupSession(sessionID)
+		if !ok {
+			return nil, ErrSessionNotFound
+		}
+		w.states[sessionID] = &tifWatchdogState{}
+
+		session.tifWatchdogs.Lock()
+		session.tifWatchdogs.watchdogs = append(session.tifWatchdogs.watchdogs, w)
+		session.tifWatchdogs.Unlock()
+	}
+	return w, nil
+}
+
+// onTick runs the TIFWatchdog for the session if its session time ends within Lead, or a cutoff is reached.
+func (w *TIFWatchdog) onTick(session *Session, now time.Time) {
+	w.mu.Lock()
+	state, ok := w.states[session.sessionID]
+	if !ok {
+		w.mu.Unlock()
+		return
+	}
+
+	var reasons []string
+	if session.SessionTime != nil && session.isInSessionTime(now) && !session.isInSessionTime(now.Add(w.opts.Lead)) &&
+		(state.endHandled.IsZero() || !session.SessionTime.IsInSameRange(state.endHandled, now)) {
+		state.endHandled = now
+		reasons = append(reasons, "session end")
+	}
+
+	if !state.lastCheck.IsZero() {
+		loc := session.TimeZone
+		if loc == nil {
+			loc = time.UTC
+		}
+		for _, cutoff := range w.cutoffs {
+			if cutoff.reached(state.lastCheck, now, w.opts.Lead, loc) {
+				reasons = append(reasons, "cutoff "+cutoff.name)
+			}
+		}
+	}
+	state.lastCheck = now
+	w.mu.Unlock()
+
+	for _, reason := range reasons {
+		w.run(session, reason, now)
+	}
+}
+
+// reached returns true if the cutoff, less lead, is after prev and not after now.
+func (c tifCutoff) reached(prev, now time.Time, lead time.Duration, loc *time.Location) bool {
+	year, month, day := now.In(loc).Date()
+	for _, offset := range []int{0, 1} {
+		at := time.Date(year, month, day+offset, c.hour, c.minute, c.second, 0, loc).Add(-lead)
+		if at.After(prev) && !at.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// run notifies the Application of the resting day orders of the session and cancels them with Cancel.
+func (w *TIFWatchdog) run(session *Session, reason string, now time.Time) {
+	orders, err := w.opts.Orders.OpenOrders(session.sessionID)
+	if err != nil {
+		session.log.OnEventf("TIF watchdog failed to get the open orders: %v", err)
+		return
+	}
+
+	var dayOrders []OpenOrder
+	for _, order := range orders {
+		if order.TimeInForce == "" || order.TimeInForce == TimeInForceDay {
+			dayOrders = append(dayOrders, order)
+		}
+	}
+	if len(dayOrders) == 0 {
+		return
+	}
+
+	session.log.OnEventf("TIF watchdog: %v resting day orders at %v", len(dayOrders), reason)
+	if handler, ok := session.application.(DayOrderHandler); ok {
+		handler.OnRestingDayOrders(dayOrders, reason, session.sessionID)
+	}
+
+	if !w.opts.Cancel {
+		return
+	}
+	for _, order := range dayOrders {
+		if err := session.queueForSend(w.orderCancelRequest(order, now)); err != nil {
+			session.log.OnEventf("TIF watchdog failed to cancel %v: %v", order.ClOrdID, err)
+		}
+	}
+}
+
+func (w *TIFWatchdog) orderCancelRequest(order OpenOrder, now time.Time) *Message {
+	w.mu.Lock()
+	w.lastID++
+	clOrdID := fmt.Sprintf("TIF-%d-%d", now.UnixNano(), w.lastID)
+	w.mu.Unlock()
+
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("F"))
+	msg.Body.SetField(tagOrigClOrdID, FIXString(order.ClOrdID))
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	msg.Body.SetField(tagSymbol, FIXString(order.Symbol))
+	msg.Body.SetField(tagSide, FIXString(order.Side))
+	msg.Body.SetField(tagTransactTime, FIXUTCTimestamp{Time: now})
+	if order.OrderQty != "" {
+		msg.Body.SetField(tagOrderQty, FIXString(order.OrderQty))
+	}
+	return msg
+}
+
+// sessionTIFWatchdogs are the TIFWatchdogs attached to a session.
+type sessionTIFWatchdogs struct {
+	sync.Mutex
+	watchdogs []*TIFWatchdog
+}
+
+func (s *sessionTIFWatchdogs) list() []*TIFWatchdog {
+	s.Lock()
+	defer s.Unlock()
+	return s.watchdogs
+}
+
+// checkTIFWatchdogs runs the TIFWatchdogs attached to the session.
+func (s *Session) checkTIFWatchdogs(now time.Time) {
+	for _, w := range s.tifWatchdogs.list() {
+		w.onTick(s, now)
+	}
+}
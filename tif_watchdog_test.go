@@ -0,0 +1,154 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix/internal"
+	"github.com/stretchr/testify/suite"
+)
+
+type TIFWatchdogTestSuite struct {
+	SessionSuiteRig
+	notified []string
+	orders   [][]OpenOrder
+}
+
+func TestTIFWatchdogTestSuite(t *testing.T) {
+	suite.Run(t, new(TIFWatchdogTestSuite))
+}
+
+type dayOrderApp struct {
+	*MockApp
+	suite *TIFWatchdogTestSuite
+}
+
+func (a *dayOrderApp) OnRestingDayOrders(orders []OpenOrder, reason string, _ SessionID) {
+	a.suite.notified = append(a.suite.notified, reason)
+	a.suite.orders = append(a.suite.orders, orders)
+}
+
+type staticOrderSource []OpenOrder
+
+func (s staticOrderSource) OpenOrders(SessionID) ([]OpenOrder, error) {
+	return s, nil
+}
+
+func (s *TIFWatchdogTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Session.application = &dayOrderApp{MockApp: &s.MockApp, suite: s}
+	s.notified, s.orders = nil, nil
+	s.Require().Nil(registerSession(s.Session))
+}
+
+func (s *TIFWatchdogTestSuite) TearDownTest() {
+	_ = UnregisterSession(s.sessionID)
+}
+
+var tifOrders = staticOrderSource{
+	{ClOrdID: "day1", Symbol: "MSFT", Side: "1", OrderQty: "100"},
+	{ClOrdID: "gtc", Symbol: "IBM", Side: "2", TimeInForce: "1"},
+	{ClOrdID: "day2", Symbol: "AAPL", Side: "2", TimeInForce: TimeInForceDay},
+}
+
+func (s *TIFWatchdogTestSuite) TestNewTIFWatchdog() {
+	_, err := NewTIFWatchdog(TIFWatchdogOptions{Sessions: []SessionID{s.sessionID}})
+	s.NotNil(err, "Orders is required")
+
+	_, err = NewTIFWatchdog(TIFWatchdogOptions{Sessions: []SessionID{s.sessionID}, Orders: tifOrders, Cutoffs: []string{"16:00"}})
+	s.NotNil(err)
+
+	_, err = NewTIFWatchdog(TIFWatchdogOptions{Sessions: []SessionID{{BeginString: "FIX.4.2"}}, Orders: tifOrders})
+	s.True(errors.Is(err, ErrSessionNotFound))
+
+	w, err := NewTIFWatchdog(TIFWatchdogOptions{Sessions: []SessionID{s.sessionID}, Orders: tifOrders})
+	s.Require().Nil(err)
+	s.Equal(time.Minute, w.opts.Lead)
+}
+
+func (s *TIFWatchdogTestSuite) TestSessionEnd() {
+	var err error
+	s.Session.SessionTime, err = internal.NewUTCTimeRange(internal.NewTimeOfDay(9, 0, 0), internal.NewTimeOfDay(17, 0, 0), nil)
+	s.Require().Nil(err)
+	_, err = NewTIFWatchdog(TIFWatchdogOptions{Sessions: []SessionID{s.sessionID}, Orders: tifOrders, Cancel: true})
+	s.Require().Nil(err)
+
+	s.MockApp.On("ToApp").Return(nil)
+	day := time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+	s.Session.checkTIFWatchdogs(day.Add(16*time.Hour + 58*time.Minute))
+	s.Empty(s.notified)
+
+	s.Session.checkTIFWatchdogs(day.Add(16*time.Hour + 59*time.Minute + 30*time.Second))
+	s.Equal([]string{"session end"}, s.notified)
+	s.Require().Len(s.orders[0], 2)
+	s.Equal("day1", s.orders[0][0].ClOrdID)
+	s.Equal("day2", s.orders[0][1].ClOrdID)
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 2)
+	s.MessageType("F", s.MockApp.lastToApp)
+	s.FieldEquals(tagOrigClOrdID, "day2", s.MockApp.lastToApp.Body)
+	s.Equal(2, s.Session.QueueDepth())
+
+	// Once per session.
+	s.Session.checkTIFWatchdogs(day.Add(16*time.Hour + 59*time.Minute + 31*time.Second))
+	s.Len(s.notified, 1)
+
+	s.Session.checkTIFWatchdogs(day.AddDate(0, 0, 1).Add(16*time.Hour + 59*time.Minute + 30*time.Second))
+	s.Len(s.notified, 2)
+}
+
+func (s *TIFWatchdogTestSuite) TestCutoff() {
+	_, err := NewTIFWatchdog(TIFWatchdogOptions{Sessions: []SessionID{s.sessionID}, Orders: tifOrders, Lead: time.Second, Cutoffs: []string{"16:00:00"}})
+	s.Require().Nil(err)
+
+	cutoff := time.Date(2024, time.March, 4, 16, 0, 0, 0, time.UTC)
+	s.Session.checkTIFWatchdogs(cutoff.Add(-2 * time.Second))
+	s.Empty(s.notified)
+
+	s.Session.checkTIFWatchdogs(cutoff.Add(-time.Second))
+	s.Equal([]string{"cutoff 16:00:00"}, s.notified)
+
+	s.Session.checkTIFWatchdogs(cutoff)
+	s.Len(s.notified, 1)
+
+	// Nothing is sent without Cancel.
+	s.Equal(0, s.Session.QueueDepth())
+}
+
+func (s *TIFWatchdogTestSuite) TestNoDayOrders() {
+	_, err := NewTIFWatchdog(TIFWatchdogOptions{Sessions: []SessionID{s.sessionID}, Orders: staticOrderSource{tifOrders[1]}, Cutoffs: []string{"16:00:00"}})
+	s.Require().Nil(err)
+
+	cutoff := time.Date(2024, time.March, 4, 16, 0, 0, 0, time.UTC)
+	s.Session.checkTIFWatchdogs(cutoff.Add(-2 * time.Minute))
+	s.Session.checkTIFWatchdogs(cutoff.Add(-time.Minute))
+	s.Empty(s.notified)
+}
+
+func TestTIFCutoffReachedAcrossMidnight(t *testing.T) {
+	cutoff := tifCutoff{name: "00:00:30", second: 30}
+	now := time.Date(2024, time.March, 4, 23, 59, 30, 0, time.UTC)
+	if !cutoff.reached(now.Add(-time.Second), now, time.Minute, time.UTC) {
+		t.Error("expected the cutoff of the next day to be reached")
+	}
+	if cutoff.reached(now, now.Add(time.Second), time.Minute, time.UTC) {
+		t.Error("expected the cutoff to be reached once")
+	}
+}
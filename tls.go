@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/quickfixgo/quickfix/config"
 )
@@ -63,7 +64,15 @@ func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
 	tlsConfig := defaultTLSConfig()
 	tlsConfig.ServerName = serverName
 	tlsConfig.InsecureSkipVerify = insecureSkipVerify
-	setMinVersionExplicit(settings, tlsConfig)
+	if err = setMinVersionExplicit(settings, tlsConfig); err != nil {
+		return nil, err
+	}
+	if err = setCipherSuites(settings, tlsConfig); err != nil {
+		return nil, err
+	}
+	if err = setALPNProtocols(settings, tlsConfig); err != nil {
+		return nil, err
+	}
 
 	if settings.HasSetting(config.SocketPrivateKeyFile) || settings.HasSetting(config.SocketCertificateFile) {
 
@@ -163,23 +172,115 @@ func defaultTLSConfig() *tls.Config {
 	}
 }
 
-func setMinVersionExplicit(settings *SessionSettings, tlsConfig *tls.Config) {
-	if settings.HasSetting(config.SocketMinimumTLSVersion) {
-		minVersion, err := settings.Setting(config.SocketMinimumTLSVersion)
-		if err != nil {
-			return
+func setMinVersionExplicit(settings *SessionSettings, tlsConfig *tls.Config) error {
+	if !settings.HasSetting(config.SocketMinimumTLSVersion) {
+		return nil
+	}
+
+	minVersion, err := settings.Setting(config.SocketMinimumTLSVersion)
+	if err != nil {
+		return err
+	}
+
+	switch minVersion {
+	case "SSL30":
+		//nolint:staticcheck // SA1019 min version ok
+		tlsConfig.MinVersion = tls.VersionSSL30
+	case "TLS10":
+		tlsConfig.MinVersion = tls.VersionTLS10
+	case "TLS11":
+		tlsConfig.MinVersion = tls.VersionTLS11
+	case "TLS12":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "TLS13":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return IncorrectFormatForSetting{Setting: config.SocketMinimumTLSVersion, Value: []byte(minVersion),
+			Err: fmt.Errorf("unknown TLS version %v", minVersion)}
+	}
+	return nil
+}
+
+// setCipherSuites applies SocketTLSCipherSuites, rejecting suites that are unknown, insecure, or
+// unusable with the configured minimum version.
+func setCipherSuites(settings *SessionSettings, tlsConfig *tls.Config) error {
+	if !settings.HasSetting(config.SocketTLSCipherSuites) {
+		return nil
+	}
+
+	value, err := settings.Setting(config.SocketTLSCipherSuites)
+	if err != nil {
+		return err
+	}
+
+	invalid := func(err error) error {
+		return IncorrectFormatForSetting{Setting: config.SocketTLSCipherSuites, Value: []byte(value), Err: err}
+	}
+
+	if tlsConfig.MinVersion >= tls.VersionTLS13 {
+		return invalid(errors.New("cipher suites are not configurable for TLS 1.3"))
+	}
+
+	suites := make(map[string]*tls.CipherSuite)
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite
+	}
+
+	for _, name := range splitList(value) {
+		suite, ok := suites[name]
+		if !ok {
+			return invalid(fmt.Errorf("unknown or insecure cipher suite %v", name))
+		}
+
+		usable := false
+		for _, version := range suite.SupportedVersions {
+			if version >= tlsConfig.MinVersion && version < tls.VersionTLS13 {
+				usable = true
+			}
+		}
+		if !usable {
+			return invalid(fmt.Errorf("cipher suite %v does not support the minimum TLS version", name))
 		}
 
-		switch minVersion {
-		case "SSL30":
-			//nolint:staticcheck // SA1019 min version ok
-			tlsConfig.MinVersion = tls.VersionSSL30
-		case "TLS10":
-			tlsConfig.MinVersion = tls.VersionTLS10
-		case "TLS11":
-			tlsConfig.MinVersion = tls.VersionTLS11
-		case "TLS12":
-			tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, suite.ID)
+	}
+
+	if len(tlsConfig.CipherSuites) == 0 {
+		return invalid(errors.New("no cipher suites"))
+	}
+	return nil
+}
+
+func setALPNProtocols(settings *SessionSettings, tlsConfig *tls.Config) error {
+	if !settings.HasSetting(config.SocketALPNProtocols) {
+		return nil
+	}
+
+	value, err := settings.Setting(config.SocketALPNProtocols)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig.NextProtos = splitList(value)
+	for _, proto := range tlsConfig.NextProtos {
+		if len(proto) > 255 {
+			return IncorrectFormatForSetting{Setting: config.SocketALPNProtocols, Value: []byte(value),
+				Err: fmt.Errorf("protocol %v exceeds 255 bytes", proto)}
+		}
+	}
+	if len(tlsConfig.NextProtos) == 0 {
+		return IncorrectFormatForSetting{Setting: config.SocketALPNProtocols, Value: []byte(value),
+			Err: errors.New("no protocols")}
+	}
+	return nil
+}
+
+// splitList splits a comma separated setting value, dropping empty entries.
+func splitList(value string) (items []string) {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
+	return
 }
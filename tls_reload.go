@@ -0,0 +1,125 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// TLSRefresher is implemented by the Acceptor and Initiator, see RefreshTLSOnSignal.
+type TLSRefresher interface {
+	// RefreshTLS re-reads the certificate files of the SocketCertificateFile and SocketPrivateKeyFile settings.
+	// Established connections are not affected, new handshakes use the new certificate.
+	RefreshTLS() error
+}
+
+// RefreshTLSOnSignal calls RefreshTLS on the refreshers each time one of the signals is received, until stop is
+// called. Signals default to SIGHUP. Failures are logged by the refreshers, which keep their previous certificate.
+func RefreshTLSOnSignal(refreshers []TLSRefresher, signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, signals...)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigChan:
+				for _, r := range refreshers {
+					_ = r.RefreshTLS()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigChan)
+			close(done)
+		})
+	}
+}
+
+// certificateReloader serves the key pair of the SocketCertificateFile and SocketPrivateKeyFile settings
+// through the GetCertificate and GetClientCertificate callbacks of a tls.Config, so that it can be re-read.
+type certificateReloader struct {
+	certificateFile, privateKeyFile string
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+}
+
+// loadReloadableTLSConfig is loadTLSConfig, with the key pair files served by a certificateReloader. The
+// certificateReloader is nil if the key pair is not read from files.
+func loadReloadableTLSConfig(settings *SessionSettings) (*tls.Config, *certificateReloader, error) {
+	tlsConfig, err := loadTLSConfig(settings)
+	if err != nil || tlsConfig == nil || !settings.HasSetting(config.SocketCertificateFile) || len(tlsConfig.Certificates) != 1 {
+		return tlsConfig, nil, err
+	}
+
+	// Both settings are present, loadTLSConfig fails otherwise.
+	r := &certificateReloader{certificate: &tlsConfig.Certificates[0]}
+	if r.certificateFile, err = settings.Setting(config.SocketCertificateFile); err != nil {
+		return nil, nil, err
+	}
+	if r.privateKeyFile, err = settings.Setting(config.SocketPrivateKeyFile); err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig.Certificates = nil
+	tlsConfig.GetCertificate = r.getCertificate
+	tlsConfig.GetClientCertificate = r.getClientCertificate
+	return tlsConfig, r, nil
+}
+
+// reload re-reads the key pair files, keeping the current certificate if they cannot be loaded.
+func (r *certificateReloader) reload() error {
+	certificate, err := tls.LoadX509KeyPair(r.certificateFile, r.privateKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.certificate = &certificate
+	return nil
+}
+
+func (r *certificateReloader) current() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.certificate
+}
+
+func (r *certificateReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+func (r *certificateReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
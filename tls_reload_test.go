@@ -0,0 +1,173 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type TLSReloadTestSuite struct {
+	suite.Suite
+	settings                        *Settings
+	CertificateFile, PrivateKeyFile string
+}
+
+func TestTLSReloadTestSuite(t *testing.T) {
+	suite.Run(t, new(TLSReloadTestSuite))
+}
+
+func (s *TLSReloadTestSuite) SetupTest() {
+	dir := s.T().TempDir()
+	s.CertificateFile = filepath.Join(dir, "client.crt")
+	s.PrivateKeyFile = filepath.Join(dir, "client.key")
+	s.copyFile("_test_data/localhost.crt", s.CertificateFile)
+	s.copyFile("_test_data/localhost.key", s.PrivateKeyFile)
+
+	s.settings = NewSettings()
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+}
+
+func (s *TLSReloadTestSuite) copyFile(src, dst string) {
+	b, err := os.ReadFile(src)
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(dst, b, 0o600))
+}
+
+// rotate replaces the key pair files with a new self-signed certificate with the given serial number.
+func (s *TLSReloadTestSuite) rotate(serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.Require().NoError(err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	s.Require().NoError(err)
+
+	s.Require().NoError(os.WriteFile(s.CertificateFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	s.Require().NoError(os.WriteFile(s.PrivateKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+func (s *TLSReloadTestSuite) serial(certificate *tls.Certificate) int64 {
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	s.Require().NoError(err)
+	return leaf.SerialNumber.Int64()
+}
+
+func (s *TLSReloadTestSuite) TestReload() {
+	tlsConfig, certificates, err := loadReloadableTLSConfig(s.settings.GlobalSettings())
+	s.Require().NoError(err)
+	s.Require().NotNil(certificates)
+	s.Empty(tlsConfig.Certificates)
+
+	initial, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	s.Require().NoError(err)
+
+	s.rotate(42)
+	s.Require().NoError(certificates.reload())
+
+	clientCertificate, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	s.Require().NoError(err)
+	s.NotEqual(s.serial(initial), s.serial(clientCertificate))
+	s.Equal(int64(42), s.serial(clientCertificate))
+
+	serverCertificate, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{})
+	s.Require().NoError(err)
+	s.Equal(int64(42), s.serial(serverCertificate))
+}
+
+func (s *TLSReloadTestSuite) TestReloadFailureKeepsCertificate() {
+	tlsConfig, certificates, err := loadReloadableTLSConfig(s.settings.GlobalSettings())
+	s.Require().NoError(err)
+
+	s.rotate(42)
+	s.Require().NoError(os.WriteFile(s.PrivateKeyFile, []byte("truncated"), 0o600))
+	s.Error(certificates.reload())
+
+	certificate, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	s.Require().NoError(err)
+	s.NotEqual(int64(42), s.serial(certificate))
+}
+
+func (s *TLSReloadTestSuite) TestKeyPairBytesNotReloadable() {
+	certificateBytes, err := os.ReadFile(s.CertificateFile)
+	s.Require().NoError(err)
+	privateKeyBytes, err := os.ReadFile(s.PrivateKeyFile)
+	s.Require().NoError(err)
+
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketCertificateBytes, string(certificateBytes))
+	settings.GlobalSettings().Set(config.SocketPrivateKeyBytes, string(privateKeyBytes))
+
+	tlsConfig, certificates, err := loadReloadableTLSConfig(settings.GlobalSettings())
+	s.Require().NoError(err)
+	s.Nil(certificates)
+	s.Len(tlsConfig.Certificates, 1)
+}
+
+func (s *TLSReloadTestSuite) TestAcceptorRefreshTLS() {
+	logger, err := NewNullLogFactory().Create()
+	s.Require().NoError(err)
+	acceptor := &Acceptor{globalLog: logger}
+	s.NoError(acceptor.RefreshTLS(), "nothing to refresh")
+
+	_, acceptor.certificates, err = loadReloadableTLSConfig(s.settings.GlobalSettings())
+	s.Require().NoError(err)
+	s.rotate(42)
+	s.Require().NoError(acceptor.RefreshTLS())
+	s.Equal(int64(42), s.serial(acceptor.certificates.current()))
+
+	s.Require().NoError(os.Remove(s.CertificateFile))
+	s.Error(acceptor.RefreshTLS())
+}
+
+type countingTLSRefresher struct{ refreshed atomic.Int32 }
+
+func (r *countingTLSRefresher) RefreshTLS() error {
+	r.refreshed.Add(1)
+	return nil
+}
+
+func (s *TLSReloadTestSuite) TestRefreshTLSOnSignal() {
+	refresher := &countingTLSRefresher{}
+	stop := RefreshTLSOnSignal([]TLSRefresher{refresher}, syscall.SIGUSR2)
+	defer stop()
+
+	s.Require().NoError(syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	s.Eventually(func() bool { return refresher.refreshed.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
@@ -242,6 +242,76 @@ func (s *TLSTestSuite) TestMinimumTLSVersion() {
 	s.Equal(tlsConfig.MinVersion, uint16(tls.VersionTLS12))
 }
 
+func (s *TLSTestSuite) TestMinimumTLSVersionTLS13() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketMinimumTLSVersion, "TLS13")
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Equal(uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+}
+
+func (s *TLSTestSuite) TestMinimumTLSVersionInvalid() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketMinimumTLSVersion, "TLS99")
+
+	_, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *TLSTestSuite) TestCipherSuites() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketTLSCipherSuites,
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, tlsConfig.CipherSuites)
+}
+
+func (s *TLSTestSuite) TestCipherSuitesInvalid() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+
+	// Unknown.
+	s.settings.GlobalSettings().Set(config.SocketTLSCipherSuites, "TLS_BOGUS")
+	_, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+
+	// Insecure.
+	s.settings.GlobalSettings().Set(config.SocketTLSCipherSuites, "TLS_RSA_WITH_RC4_128_SHA")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+
+	// TLS 1.3 only suite.
+	s.settings.GlobalSettings().Set(config.SocketTLSCipherSuites, "TLS_AES_128_GCM_SHA256")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+
+	// Not configurable with a TLS 1.3 minimum.
+	s.settings.GlobalSettings().Set(config.SocketMinimumTLSVersion, "TLS13")
+	s.settings.GlobalSettings().Set(config.SocketTLSCipherSuites, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *TLSTestSuite) TestALPNProtocols() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketALPNProtocols, "fix,fixt")
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Equal([]string{"fix", "fixt"}, tlsConfig.NextProtos)
+
+	s.settings.GlobalSettings().Set(config.SocketALPNProtocols, ",")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
 func (s *TLSTestSuite) TestLoadTLSBytesMissingKeyOrCert() {
 	s.settings.GlobalSettings().SetRaw(config.SocketPrivateKeyBytes, s.PrivateKeyBytes)
 	_, err := loadTLSConfig(s.settings.GlobalSettings())
@@ -0,0 +1,65 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// transientStore is the MessageStore of a session with StorePolicy TRANSIENT. Sequence numbers and the creation time
+// are kept by the wrapped store, while outbound messages are discarded rather than saved, so that they never reach its
+// backing storage.
+type transientStore struct {
+	MessageStore
+}
+
+func newTransientStore(store MessageStore) MessageStore {
+	transient := &transientStore{MessageStore: store}
+	if epochStore, ok := store.(EpochStore); ok {
+		return &transientEpochStore{transientStore: transient, EpochStore: epochStore}
+	}
+	return transient
+}
+
+func (s *transientStore) SetLabels(labels map[string]string) {
+	setLabels(s.MessageStore, labels)
+}
+
+// Flush implements FlushStore, flushing the wrapped store if it buffers writes.
+func (s *transientStore) Flush() error {
+	if flushStore, ok := s.MessageStore.(FlushStore); ok {
+		return flushStore.Flush()
+	}
+	return nil
+}
+
+func (s *transientStore) SaveMessage(int, []byte) error {
+	return nil
+}
+
+func (s *transientStore) SaveMessageAndIncrNextSenderMsgSeqNum(int, []byte) error {
+	return s.MessageStore.IncrNextSenderMsgSeqNum()
+}
+
+func (s *transientStore) GetMessages(int, int) ([][]byte, error) {
+	return nil, nil
+}
+
+func (s *transientStore) IterateMessages(int, int, func([]byte) error) error {
+	return nil
+}
+
+// transientEpochStore preserves the EpochStore implementation of the wrapped store.
+type transientEpochStore struct {
+	*transientStore
+	EpochStore
+}
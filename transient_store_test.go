@@ -0,0 +1,44 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransientStore(t *testing.T) {
+	backing, err := NewMemoryStoreFactory().Create(SessionID{})
+	require.Nil(t, err)
+	store := newTransientStore(backing)
+
+	require.Nil(t, store.SaveMessage(1, []byte("quote")))
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("quote")))
+	assert.Equal(t, 2, store.NextSenderMsgSeqNum())
+	assert.Equal(t, 2, backing.NextSenderMsgSeqNum())
+
+	msgs, err := store.GetMessages(1, 1)
+	require.Nil(t, err)
+	assert.Empty(t, msgs)
+	msgs, err = backing.GetMessages(1, 1)
+	require.Nil(t, err)
+	assert.Empty(t, msgs, "no message reaches the wrapped store")
+
+	_, ok := store.(EpochStore)
+	assert.True(t, ok)
+}
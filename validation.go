@@ -308,6 +308,10 @@ func validateRequired(transportDD *datadictionary.DataDictionary, appDD *datadic
 		return err
 	}
 
+	if err := validateConditions(appDD.Messages[msgType].Conditions, message); err != nil {
+		return err
+	}
+
 	if err := validateRequiredFieldMap(message, transportDD.Trailer.RequiredTags, message.Trailer.FieldMap); err != nil {
 		return err
 	}
@@ -326,6 +330,38 @@ func validateRequiredFieldMap(_ *Message, requiredTags map[int]struct{}, fieldMa
 	return nil
 }
 
+// validateConditions checks the conditionally required and forbidden fields of the body.
+func validateConditions(conditions []*datadictionary.Condition, message *Message) MessageRejectError {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	value := func(tag int) (string, bool) {
+		for _, fieldMap := range []*FieldMap{&message.Body.FieldMap, &message.Header.FieldMap} {
+			if v, err := fieldMap.GetString(Tag(tag)); err == nil {
+				return v, true
+			}
+		}
+		return "", false
+	}
+
+	for _, condition := range conditions {
+		if !condition.Holds(value) {
+			continue
+		}
+
+		tag := Tag(condition.Tag)
+		switch has := message.Body.Has(tag); {
+		case condition.Presence == datadictionary.PresenceRequired && !has:
+			return ConditionallyRequiredFieldMissing(tag)
+		case condition.Presence == datadictionary.PresenceForbidden && has:
+			return TagNotDefinedForThisMessageType(tag)
+		}
+	}
+
+	return nil
+}
+
 func validateFields(transportDD *datadictionary.DataDictionary,
 	appDD *datadictionary.DataDictionary,
 	settings ValidatorSettings,
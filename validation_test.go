@@ -86,6 +86,9 @@ func TestValidate(t *testing.T) {
 		tcCheckUserDefinedFieldsDisabled(),
 		tcCheckUserDefinedFieldsDisabledFixT(),
 		tcMultipleRepeatingGroupFields(),
+		tcConditionallyRequiredFieldMissing(),
+		tcConditionallyRequiredFieldPresent(),
+		tcConditionallyForbiddenField(),
 	}
 
 	msg := NewMessage()
@@ -195,6 +198,27 @@ func createFIX50SP2NewOrderSingle() *Message {
 	return msg
 }
 
+func createOrchestraNewOrderSingle(ordType string) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("D"))
+	msg.Header.SetField(tagBeginString, FIXString("FIX.4.4"))
+	msg.Header.SetField(tagBodyLength, FIXString("0"))
+	msg.Header.SetField(tagSenderCompID, FIXString("0"))
+	msg.Header.SetField(tagTargetCompID, FIXString("0"))
+	msg.Header.SetField(tagMsgSeqNum, FIXString("0"))
+	msg.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now()})
+
+	msg.Body.SetField(Tag(11), FIXString("A"))
+	msg.Body.SetField(Tag(55), FIXString("A"))
+	msg.Body.SetField(Tag(54), FIXString("1"))
+	msg.Body.SetField(Tag(60), FIXUTCTimestamp{Time: time.Now()})
+	msg.Body.SetField(Tag(40), FIXString(ordType))
+
+	msg.Trailer.SetField(tagCheckSum, FIXString("000"))
+
+	return msg
+}
+
 func tcInvalidTagNumberHeader() validateTest {
 	dict, _ := datadictionary.Parse("spec/FIX40.xml")
 	validator := NewValidator(defaultValidatorSettings, dict, nil)
@@ -1267,3 +1291,48 @@ func TestValidateVisitField(t *testing.T) {
 		}
 	}
 }
+
+func tcConditionallyRequiredFieldMissing() validateTest {
+	dict, _ := datadictionary.Parse("_test_data/orchestra44.xml")
+	validator := NewValidator(defaultValidatorSettings, dict, nil)
+	invalidMsg := createOrchestraNewOrderSingle("2")
+	tag := Tag(44)
+
+	return validateTest{
+		TestName:             "Conditionally Required Field Missing",
+		Validator:            validator,
+		MessageBytes:         invalidMsg.Build(),
+		ExpectedRejectReason: rejectReasonConditionallyRequiredFieldMissing,
+		ExpectedRefTagID:     &tag,
+	}
+}
+
+func tcConditionallyRequiredFieldPresent() validateTest {
+	dict, _ := datadictionary.Parse("_test_data/orchestra44.xml")
+	validator := NewValidator(defaultValidatorSettings, dict, nil)
+	validMsg := createOrchestraNewOrderSingle("2")
+	validMsg.Body.SetField(Tag(44), FIXString("10.5"))
+
+	return validateTest{
+		TestName:          "Conditionally Required Field Present",
+		Validator:         validator,
+		MessageBytes:      validMsg.Build(),
+		DoNotExpectReject: true,
+	}
+}
+
+func tcConditionallyForbiddenField() validateTest {
+	dict, _ := datadictionary.Parse("_test_data/orchestra44.xml")
+	validator := NewValidator(defaultValidatorSettings, dict, nil)
+	invalidMsg := createOrchestraNewOrderSingle("1")
+	tag := Tag(44)
+	invalidMsg.Body.SetField(tag, FIXString("10.5"))
+
+	return validateTest{
+		TestName:             "Conditionally Forbidden Field",
+		Validator:            validator,
+		MessageBytes:         invalidMsg.Build(),
+		ExpectedRejectReason: rejectReasonTagNotDefinedForThisMessageType,
+		ExpectedRefTagID:     &tag,
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWebhookQueueSize            = 256
+	defaultWebhookMaxAttempts          = 4
+	defaultWebhookBackoff              = time.Second
+	defaultWebhookTimeout              = 5 * time.Second
+	defaultWebhookRejectSpikeThreshold = 10
+	defaultWebhookRejectSpikeWindow    = time.Minute
+)
+
+// Headers of the requests of a WebhookNotifier.
+const (
+	WebhookEventHeader     = "X-Quickfix-Event"
+	WebhookTimestampHeader = "X-Quickfix-Timestamp"
+	WebhookSignatureHeader = "X-Quickfix-Signature"
+)
+
+// WebhookEventType is the type of a WebhookEvent.
+type WebhookEventType string
+
+const (
+	// WebhookLogon is sent when the session logs on.
+	WebhookLogon WebhookEventType = "logon"
+	// WebhookLogout is sent when a logged on session logs out.
+	WebhookLogout WebhookEventType = "logout"
+	// WebhookDisconnect is sent when the session disconnects.
+	WebhookDisconnect WebhookEventType = "disconnect"
+	// WebhookSeqNumReset is sent when the sequence numbers of the session are reset.
+	WebhookSeqNumReset WebhookEventType = "seqnum_reset"
+	// WebhookRejectSpike is sent when RejectSpikeThreshold Reject or BusinessMessageReject messages are sent or
+	// received within RejectSpikeWindow.
+	WebhookRejectSpike WebhookEventType = "reject_spike"
+)
+
+// WebhookEvent is the JSON body of the requests of a WebhookNotifier.
+type WebhookEvent struct {
+	Type      WebhookEventType `json:"type"`
+	SessionID string           `json:"session_id"`
+	Time      time.Time        `json:"time"`
+	Detail    string           `json:"detail,omitempty"`
+}
+
+// WebhookOptions configure a WebhookNotifier.
+type WebhookOptions struct {
+	// URLs the events are posted to.
+	URLs []string
+
+	// Sessions the WebhookNotifier is attached to.
+	Sessions []SessionID
+
+	// Events posted, all if empty.
+	Events []WebhookEventType
+
+	// Secret, if set, signs the requests: the WebhookSignatureHeader is "sha256=" followed by the hex encoded
+	// HMAC-SHA256 of the WebhookTimestampHeader, a dot and the body.
+	Secret []byte
+
+	// MaxAttempts bounds the requests posting an event to a URL. Defaults to 4.
+	MaxAttempts int
+
+	// Backoff is the wait before the second request posting an event, doubled for each further request. Defaults
+	// to 1 second.
+	Backoff time.Duration
+
+	// Timeout bounds each request. Defaults to 5 seconds.
+	Timeout time.Duration
+
+	// QueueSize bounds the events waiting to be posted. Defaults to 256.
+	QueueSize int
+
+	// RejectSpikeThreshold and RejectSpikeWindow define a WebhookRejectSpike. Default to 10 and 1 minute.
+	RejectSpikeThreshold int
+	RejectSpikeWindow    time.Duration
+
+	// Client posts the events. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// WebhookNotifier posts session events to HTTP endpoints, providing alerting without a metrics stack. Events are
+// posted on its own goroutine, in order, and retried with exponential backoff on network errors, 5xx and 429
+// responses. Sessions never wait for the WebhookNotifier: when its queue is full, events are dropped and counted.
+type WebhookNotifier struct {
+	opts     WebhookOptions
+	events   map[WebhookEventType]bool
+	queue    chan WebhookEvent
+	stop     chan struct{}
+	done     chan struct{}
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+	sessions []*Session
+
+	rejectsMu sync.Mutex
+	rejects   map[SessionID][]time.Time
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewWebhookNotifier returns a WebhookNotifier attached to the sessions of opts, which must exist.
+func NewWebhookNotifier(opts WebhookOptions) (*WebhookNotifier, error) {
+	if len(opts.URLs) == 0 {
+		return nil, errors.New("webhook URLs required")
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultWebhookBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultWebhookTimeout
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultWebhookQueueSize
+	}
+	if opts.RejectSpikeThreshold <= 0 {
+		opts.RejectSpikeThreshold = defaultWebhookRejectSpikeThreshold
+	}
+	if opts.RejectSpikeWindow <= 0 {
+		opts.RejectSpikeWindow = defaultWebhookRejectSpikeWindow
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	var sessions []*Session
+	for _, sessionID := range opts.Sessions {
+		session, ok := lookupSession(sessionID)
+		if !ok {
+			return nil, ErrSessionNotFound
+		}
+		sessions = append(sessions, session)
+	}
+
+	w := &WebhookNotifier{
+		opts:     opts,
+		queue:    make(chan WebhookEvent, opts.QueueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		sessions: sessions,
+		rejects:  make(map[SessionID][]time.Time),
+	}
+	if len(opts.Events) > 0 {
+		w.events = make(map[WebhookEventType]bool)
+		for _, event := range opts.Events {
+			w.events[event] = true
+		}
+	}
+	for _, session := range sessions {
+		session.webhooks.add(w)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Dropped returns the number of events dropped because the queue was full.
+func (w *WebhookNotifier) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Failed returns the number of events that could not be posted to a URL within MaxAttempts.
+func (w *WebhookNotifier) Failed() uint64 {
+	return w.failed.Load()
+}
+
+// Close detaches the WebhookNotifier from its sessions and waits for the queued events to be posted, without
+// retrying failed requests.
+func (w *WebhookNotifier) Close() {
+	for _, session := range w.sessions {
+		session.webhooks.remove(w)
+	}
+
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.stop)
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+}
+
+// notify queues an event without blocking.
+func (w *WebhookNotifier) notify(event WebhookEvent) {
+	if w.events != nil && !w.events[event.Type] {
+		return
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return
+	}
+
+	select {
+	case w.queue <- event:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// rejected records a Reject sent or received at now, notifying a WebhookRejectSpike once RejectSpikeThreshold are
+// recorded within RejectSpikeWindow.
+func (w *WebhookNotifier) rejected(sessionID SessionID, now time.Time) {
+	w.rejectsMu.Lock()
+	rejects := w.rejects[sessionID]
+	start := 0
+	for start < len(rejects) && now.Sub(rejects[start]) >= w.opts.RejectSpikeWindow {
+		start++
+	}
+	rejects = append(rejects[start:], now)
+	spike := len(rejects) >= w.opts.RejectSpikeThreshold
+	if spike {
+		// A further spike needs RejectSpikeThreshold new rejects.
+		rejects = rejects[:0]
+	}
+	w.rejects[sessionID] = rejects
+	w.rejectsMu.Unlock()
+
+	if spike {
+		w.notify(WebhookEvent{
+			Type:      WebhookRejectSpike,
+			SessionID: sessionID.String(),
+			Time:      now,
+			Detail:    fmt.Sprintf("%d rejects within %v", w.opts.RejectSpikeThreshold, w.opts.RejectSpikeWindow),
+		})
+	}
+}
+
+func (w *WebhookNotifier) run() {
+	defer close(w.done)
+	for event := range w.queue {
+		body, err := json.Marshal(event)
+		if err != nil {
+			w.failed.Add(1)
+			continue
+		}
+		for _, url := range w.opts.URLs {
+			if !w.deliver(url, event.Type, body) {
+				w.failed.Add(1)
+			}
+		}
+	}
+}
+
+// deliver posts body to url, retrying with backoff, and reports whether it succeeded.
+func (w *WebhookNotifier) deliver(url string, eventType WebhookEventType, body []byte) bool {
+	backoff := w.opts.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(url, eventType, body)
+		if err == nil {
+			return true
+		}
+		if !retry || attempt >= w.opts.MaxAttempts {
+			return false
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-w.stop:
+			return false
+		}
+	}
+}
+
+// post sends a single request, reporting whether a failure may be retried.
+func (w *WebhookNotifier) post(url string, eventType WebhookEventType, body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(eventType))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if len(w.opts.Secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(w.opts.Secret, timestamp, body))
+	}
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook %v: %v", url, resp.Status)
+	default:
+		return false, fmt.Errorf("webhook %v: %v", url, resp.Status)
+	}
+}
+
+// SignWebhook returns the hex encoded HMAC-SHA256 of timestamp, a dot and body, as set in the
+// WebhookSignatureHeader. Receivers verify the requests of a WebhookNotifier by comparing it with hmac.Equal.
+func SignWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sessionWebhooks are the WebhookNotifiers attached to a session.
+type sessionWebhooks struct {
+	sync.Mutex
+	notifiers []*WebhookNotifier
+}
+
+func (s *sessionWebhooks) add(w *WebhookNotifier) {
+	s.Lock()
+	defer s.Unlock()
+	s.notifiers = append(s.notifiers, w)
+}
+
+func (s *sessionWebhooks) remove(w *WebhookNotifier) {
+	s.Lock()
+	defer s.Unlock()
+	for i, notifier := range s.notifiers {
+		if notifier == w {
+			s.notifiers = append(s.notifiers[:i:i], s.notifiers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *sessionWebhooks) list() []*WebhookNotifier {
+	s.Lock()
+	defer s.Unlock()
+	return s.notifiers
+}
+
+// notifyWebhooks passes an event of the session to the attached WebhookNotifiers.
+func (s *Session) notifyWebhooks(eventType WebhookEventType, detail string) {
+	notifiers := s.webhooks.list()
+	if len(notifiers) == 0 {
+		return
+	}
+
+	event := WebhookEvent{Type: eventType, SessionID: s.sessionID.String(), Time: s.now(), Detail: detail}
+	for _, w := range notifiers {
+		w.notify(event)
+	}
+}
+
+// webhookRejected records a Reject or BusinessMessageReject sent or received by the session.
+func (s *Session) webhookRejected() {
+	notifiers := s.webhooks.list()
+	if len(notifiers) == 0 {
+		return
+	}
+
+	now := s.now()
+	for _, w := range notifiers {
+		w.rejected(s.sessionID, now)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+	event  WebhookEvent
+}
+
+type WebhookTestSuite struct {
+	SessionSuiteRig
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []webhookRequest
+	statuses []int
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+func (s *WebhookTestSuite) SetupTest() {
+	s.Init()
+	s.Session.State = inSession{}
+	s.Require().Nil(registerSession(s.Session))
+
+	s.requests, s.statuses = nil, nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := webhookRequest{header: r.Header, body: body}
+		s.NoError(json.Unmarshal(body, &req.event))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+}
+
+func (s *WebhookTestSuite) TearDownTest() {
+	s.server.Close()
+	_ = UnregisterSession(s.sessionID)
+}
+
+func (s *WebhookTestSuite) newNotifier(opts WebhookOptions) *WebhookNotifier {
+	opts.URLs = []string{s.server.URL}
+	opts.Sessions = []SessionID{s.sessionID}
+	opts.Backoff = time.Millisecond
+	w, err := NewWebhookNotifier(opts)
+	s.Require().Nil(err)
+	return w
+}
+
+func (s *WebhookTestSuite) received() []webhookRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *WebhookTestSuite) TestSignedEvent() {
+	secret := []byte("secret")
+	w := s.newNotifier(WebhookOptions{Secret: secret})
+
+	s.Require().Nil(s.Session.resetStore())
+	w.Close()
+
+	requests := s.received()
+	s.Require().Len(requests, 1)
+	req := requests[0]
+	s.Equal(WebhookSeqNumReset, req.event.Type)
+	s.Equal(s.sessionID.String(), req.event.SessionID)
+	s.False(req.event.Time.IsZero())
+	s.Equal("application/json", req.header.Get("Content-Type"))
+	s.Equal(string(WebhookSeqNumReset), req.header.Get(WebhookEventHeader))
+
+	timestamp := req.header.Get(WebhookTimestampHeader)
+	s.NotEmpty(timestamp)
+	s.Equal("sha256="+SignWebhook(secret, timestamp, req.body), req.header.Get(WebhookSignatureHeader))
+	s.NotEqual("sha256="+SignWebhook([]byte("other"), timestamp, req.body), req.header.Get(WebhookSignatureHeader))
+}
+
+func (s *WebhookTestSuite) TestUnsigned() {
+	w := s.newNotifier(WebhookOptions{})
+	s.Session.notifyWebhooks(WebhookLogon, "")
+	w.Close()
+
+	requests := s.received()
+	s.Require().Len(requests, 1)
+	s.Empty(requests[0].header.Get(WebhookSignatureHeader))
+}
+
+func (s *WebhookTestSuite) TestLogoutAndDisconnect() {
+	w := s.newNotifier(WebhookOptions{})
+	s.MockApp.On("OnLogout")
+	s.Session.setState(s.Session, latentState{})
+	w.Close()
+
+	requests := s.received()
+	s.Require().Len(requests, 2)
+	s.Equal(WebhookLogout, requests[0].event.Type)
+	s.Equal(WebhookDisconnect, requests[1].event.Type)
+}
+
+func (s *WebhookTestSuite) TestEventFilter() {
+	w := s.newNotifier(WebhookOptions{Events: []WebhookEventType{WebhookDisconnect}})
+	s.Session.notifyWebhooks(WebhookLogon, "")
+	s.Session.notifyWebhooks(WebhookDisconnect, "")
+	w.Close()
+
+	requests := s.received()
+	s.Require().Len(requests, 1)
+	s.Equal(WebhookDisconnect, requests[0].event.Type)
+}
+
+func (s *WebhookTestSuite) TestRetry() {
+	s.statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	w := s.newNotifier(WebhookOptions{})
+	s.Session.notifyWebhooks(WebhookLogon, "")
+	s.Eventually(func() bool { return len(s.received()) == 3 }, time.Second, time.Millisecond)
+	w.Close()
+
+	s.Equal(uint64(0), w.Failed())
+}
+
+func (s *WebhookTestSuite) TestRetriesExhausted() {
+	s.statuses = []int{http.StatusInternalServerError, http.StatusInternalServerError}
+	w := s.newNotifier(WebhookOptions{MaxAttempts: 2})
+	s.Session.notifyWebhooks(WebhookLogon, "")
+	s.Eventually(func() bool { return w.Failed() == 1 }, time.Second, time.Millisecond)
+	w.Close()
+
+	s.Len(s.received(), 2)
+}
+
+func (s *WebhookTestSuite) TestClientErrorNotRetried() {
+	s.statuses = []int{http.StatusBadRequest}
+	w := s.newNotifier(WebhookOptions{})
+	s.Session.notifyWebhooks(WebhookLogon, "")
+	w.Close()
+
+	s.Len(s.received(), 1)
+	s.Equal(uint64(1), w.Failed())
+}
+
+func (s *WebhookTestSuite) TestRejectSpike() {
+	w := s.newNotifier(WebhookOptions{RejectSpikeThreshold: 3, RejectSpikeWindow: time.Minute})
+	now := time.Now()
+
+	w.rejected(s.sessionID, now)
+	w.rejected(s.sessionID, now.Add(30*time.Second))
+	w.rejected(s.sessionID, now.Add(61*time.Second))
+	s.Empty(w.queue, "the first reject is out of the window")
+
+	w.rejected(s.sessionID, now.Add(62*time.Second))
+	s.Len(w.queue, 1)
+	w.rejected(s.sessionID, now.Add(63*time.Second))
+	s.Len(w.queue, 1, "counted anew after a spike")
+	w.Close()
+
+	requests := s.received()
+	s.Require().Len(requests, 1)
+	s.Equal(WebhookRejectSpike, requests[0].event.Type)
+	s.Equal("3 rejects within 1m0s", requests[0].event.Detail)
+}
+
+func (s *WebhookTestSuite) TestRejectsReceived() {
+	w := s.newNotifier(WebhookOptions{RejectSpikeThreshold: 2})
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("FromApp").Return(nil)
+
+	s.Session.fromCallback(s.MessageFactory.buildMessage(string(msgTypeReject)))
+	s.Session.fromCallback(s.MessageFactory.buildMessage("j"))
+	w.Close()
+
+	requests := s.received()
+	s.Require().Len(requests, 1)
+	s.Equal(WebhookRejectSpike, requests[0].event.Type)
+}
+
+func (s *WebhookTestSuite) TestDropped() {
+	release := make(chan struct{})
+	posted := make(chan struct{}, 10)
+	blocking := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		posted <- struct{}{}
+		<-release
+	}))
+	defer blocking.Close()
+
+	w, err := NewWebhookNotifier(WebhookOptions{URLs: []string{blocking.URL}, Sessions: []SessionID{s.sessionID}, QueueSize: 1})
+	s.Require().Nil(err)
+
+	s.Session.notifyWebhooks(WebhookLogon, "")
+	<-posted
+	s.Session.notifyWebhooks(WebhookLogout, "")
+	s.Session.notifyWebhooks(WebhookDisconnect, "")
+	s.Equal(uint64(1), w.Dropped())
+
+	close(release)
+	w.Close()
+	s.Len(posted, 1, "the queued event is posted on Close")
+}
+
+func (s *WebhookTestSuite) TestOptions() {
+	_, err := NewWebhookNotifier(WebhookOptions{Sessions: []SessionID{s.sessionID}})
+	s.NotNil(err, "URLs required")
+
+	_, err = NewWebhookNotifier(WebhookOptions{URLs: []string{s.server.URL}, Sessions: []SessionID{{BeginString: "FIX.4.2"}}})
+	s.Equal(ErrSessionNotFound, err)
+}
+
+func (s *WebhookTestSuite) TestDetached() {
+	w := s.newNotifier(WebhookOptions{})
+	w.Close()
+	s.Session.notifyWebhooks(WebhookLogon, "")
+	s.Empty(s.Session.webhooks.list())
+	s.Empty(s.received())
+}
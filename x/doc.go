@@ -0,0 +1,31 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package x holds the experimental packages of QuickFIX/Go, such as the ingest and multicast bridges.
+//
+// The exported API of the quickfix package and of the packages outside of x is stable: it changes only in a
+// backward compatible way between releases, which TestStableAPI enforces against the API recorded in
+// _test_data/api.txt. The packages under x carry no such guarantee: they may change or be removed in any release,
+// and graduate out of x once their API settles.
+//
+// Relay and Mirror are kept in the quickfix package, and stable, on purpose: the session itself feeds them, from its
+// own goroutine and with its dictionaries, stores and compression, and moving them under x would take exporting those
+// hooks as stable API instead.
+//
+// The packages under x build only with the quickfix_experimental build tag, so that depending on them is a
+// deliberate choice:
+//
+//	go build -tags quickfix_experimental ./...
+package x
@@ -0,0 +1,304 @@
+//go:build quickfix_experimental
+
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package ingest provides a listener accepting protobuf messages generated by generate-pb over TCP or a unix
+// domain socket, converting them to FIX messages and sending them to a session, turning an engine into a
+// FIX gateway.
+//
+// Each request is a frame made of a big endian uint32 length followed by the session id, the full name of the
+// protobuf message, each prefixed by a big endian uint16 length, and the protobuf encoded message. The session id
+// is the string form of a quickfix.SessionID added to the Server; when empty the message is routed by its header.
+// Each request is answered by a frame made of a big endian uint32 length followed by a status byte, 0 on success,
+// and on failure the error text.
+//
+// This package is experimental, see package x.
+package ingest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// MaxFrameSize is the largest frame accepted.
+const MaxFrameSize = 16 << 20
+
+const (
+	statusOK    byte = 0
+	statusError byte = 1
+)
+
+// ErrFrameTooLarge is returned when a frame exceeds MaxFrameSize.
+var ErrFrameTooLarge = errors.New("ingest: frame too large")
+
+// Converter converts a protobuf message to a FIX message. It is typically backed by the FromProto functions
+// generated by generate-pb, keyed by protobuf message name.
+type Converter interface {
+	FromProto(name string, payload []byte) (*quickfix.Message, error)
+}
+
+// ConverterFunc is an adapter to allow the use of ordinary functions as Converters.
+type ConverterFunc func(name string, payload []byte) (*quickfix.Message, error)
+
+// FromProto calls f(name, payload).
+func (f ConverterFunc) FromProto(name string, payload []byte) (*quickfix.Message, error) {
+	return f(name, payload)
+}
+
+// Request is a protobuf message to send to a session.
+type Request struct {
+	SessionID string
+	Name      string
+	Payload   []byte
+}
+
+// Server accepts Requests and sends their converted messages.
+type Server struct {
+	converter Converter
+	send      func(m quickfix.Messagable, sessionID quickfix.SessionID) error
+
+	mu        sync.Mutex
+	sessions  map[string]quickfix.SessionID
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewServer returns a Server converting Requests with converter.
+func NewServer(converter Converter) *Server {
+	return &Server{
+		converter: converter,
+		send:      quickfix.SendToTarget,
+		sessions:  make(map[string]quickfix.SessionID),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// AddSession allows Requests to target sessionID by its string form.
+func (s *Server) AddSession(sessionID quickfix.SessionID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID.String()] = sessionID
+}
+
+// ListenAndServe listens on network, "tcp" or "unix", and address, then calls Serve.
+func (s *Server) ListenAndServe(network, address string) error {
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l until the Server is closed, serving each connection on its own goroutine.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = l.Close()
+		return net.ErrClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.listeners, l)
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops all listeners and connections and waits for the connections being served to return.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for l := range s.listeners {
+		_ = l.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		req, err := ReadRequest(r)
+		if err != nil {
+			return
+		}
+
+		if err := writeResponse(conn, s.handle(req)); err != nil {
+			return
+		}
+	}
+}
+
+// handle converts and sends the message of req.
+func (s *Server) handle(req Request) error {
+	msg, err := s.converter.FromProto(req.Name, req.Payload)
+	if err != nil {
+		return fmt.Errorf("converting %v: %w", req.Name, err)
+	}
+
+	if req.SessionID == "" {
+		return quickfix.Send(msg)
+	}
+
+	s.mu.Lock()
+	sessionID, ok := s.sessions[req.SessionID]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown session %v", req.SessionID)
+	}
+	return s.send(msg, sessionID)
+}
+
+// WriteRequest writes req as a frame to w.
+func WriteRequest(w io.Writer, req Request) error {
+	if len(req.SessionID) > math.MaxUint16 || len(req.Name) > math.MaxUint16 {
+		return ErrFrameTooLarge
+	}
+	size := 2 + len(req.SessionID) + 2 + len(req.Name) + len(req.Payload)
+	if size > MaxFrameSize {
+		return ErrFrameTooLarge
+	}
+
+	frame := make([]byte, 0, 4+size)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(size))
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(req.SessionID)))
+	frame = append(frame, req.SessionID...)
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(req.Name)))
+	frame = append(frame, req.Name...)
+	frame = append(frame, req.Payload...)
+	_, err := w.Write(frame)
+	return err
+}
+
+// ReadRequest reads a Request frame from r.
+func ReadRequest(r io.Reader) (req Request, err error) {
+	frame, err := readFrame(r)
+	if err != nil {
+		return
+	}
+
+	if req.SessionID, frame, err = readString(frame); err != nil {
+		return
+	}
+	if req.Name, frame, err = readString(frame); err != nil {
+		return
+	}
+	req.Payload = frame
+	return
+}
+
+// ReadResponse reads a response frame from r, returning the error of a failed request.
+func ReadResponse(r io.Reader) error {
+	frame, err := readFrame(r)
+	if err != nil {
+		return err
+	}
+	if len(frame) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+	if frame[0] != statusOK {
+		return errors.New(string(frame[1:]))
+	}
+	return nil
+}
+
+func writeResponse(w io.Writer, result error) error {
+	status, text := statusOK, ""
+	if result != nil {
+		status, text = statusError, result.Error()
+	}
+
+	frame := make([]byte, 0, 5+len(text))
+	frame = binary.BigEndian.AppendUint32(frame, uint32(1+len(text)))
+	frame = append(frame, status)
+	frame = append(frame, text...)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+
+	n := binary.BigEndian.Uint32(size[:])
+	if n > MaxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+
+	frame := make([]byte, n)
+	_, err := io.ReadFull(r, frame)
+	return frame, err
+}
+
+func readString(frame []byte) (string, []byte, error) {
+	if len(frame) < 2 {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+	n := int(binary.BigEndian.Uint16(frame))
+	if len(frame) < 2+n {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+	return string(frame[2 : 2+n]), frame[2+n:], nil
+}
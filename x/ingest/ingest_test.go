@@ -0,0 +1,100 @@
+//go:build quickfix_experimental
+
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestRequestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	req := Request{SessionID: "FIX.4.4:SENDER->TARGET", Name: "fix44.NewOrderSingle", Payload: []byte{1, 2, 3}}
+	require.NoError(t, WriteRequest(&buf, req))
+
+	read, err := ReadRequest(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, req, read)
+
+	buf.Write([]byte{0, 0, 0, 1, 0})
+	_, err = ReadRequest(&buf)
+	assert.Error(t, err, "truncated session id")
+
+	buf.Reset()
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	_, err = ReadRequest(&buf)
+	assert.ErrorIs(t, err, ErrFrameTooLarge)
+}
+
+func TestServer(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	converter := ConverterFunc(func(name string, payload []byte) (*quickfix.Message, error) {
+		if name != "fix44.NewOrderSingle" {
+			return nil, errors.New("unknown message")
+		}
+		msg := quickfix.NewMessage()
+		msg.Header.SetString(quickfix.Tag(35), "D")
+		msg.Body.SetString(quickfix.Tag(11), string(payload))
+		return msg, nil
+	})
+
+	var sent []string
+	s := NewServer(converter)
+	s.send = func(m quickfix.Messagable, target quickfix.SessionID) error {
+		assert.Equal(t, sessionID, target)
+		clOrdID, err := m.ToMessage().Body.GetString(quickfix.Tag(11))
+		require.NoError(t, err)
+		sent = append(sent, clOrdID)
+		return nil
+	}
+	s.AddSession(sessionID)
+
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "ingest.sock"))
+	require.NoError(t, err)
+	served := make(chan error)
+	go func() { served <- s.Serve(l) }()
+
+	conn, err := net.Dial("unix", l.Addr().String())
+	require.NoError(t, err)
+	r := bufio.NewReader(conn)
+
+	require.NoError(t, WriteRequest(conn, Request{SessionID: sessionID.String(), Name: "fix44.NewOrderSingle", Payload: []byte("order1")}))
+	assert.NoError(t, ReadResponse(r))
+	assert.Equal(t, []string{"order1"}, sent)
+
+	require.NoError(t, WriteRequest(conn, Request{SessionID: sessionID.String(), Name: "fix44.Unknown"}))
+	assert.ErrorContains(t, ReadResponse(r), "converting fix44.Unknown")
+
+	require.NoError(t, WriteRequest(conn, Request{SessionID: "FIX.4.4:OTHER->TARGET", Name: "fix44.NewOrderSingle"}))
+	assert.ErrorContains(t, ReadResponse(r), "unknown session")
+
+	require.NoError(t, s.Close())
+	assert.NoError(t, <-served)
+	_, err = r.ReadByte()
+	assert.Error(t, err, "connection closed")
+}
@@ -0,0 +1,293 @@
+//go:build quickfix_experimental
+
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package multicast provides a receiver of market data sent over UDP multicast, delivering the messages to the
+// FromApp callback of a quickfix.Application so that downstream code does not depend on the transport.
+//
+// Each datagram is a packet made of a big endian uint64 packet sequence number followed by a payload decoded by
+// a Decoder, by default one or more FIX messages. Gaps in the packet sequence numbers are recovered with a
+// Recoverer, such as a TCPRecoverer connected to the retransmission service of the feed.
+//
+// This package is experimental, see package x.
+package multicast
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// MaxPacketSize is the largest datagram received.
+const MaxPacketSize = 65535
+
+const headerSize = 8
+
+// Decoder decodes the payload of a packet into messages, for instance FIX, or FAST or SBE encoded messages.
+type Decoder interface {
+	Decode(payload []byte) ([]*quickfix.Message, error)
+}
+
+// DecoderFunc is an adapter to allow the use of ordinary functions as Decoders.
+type DecoderFunc func(payload []byte) ([]*quickfix.Message, error)
+
+// Decode calls f(payload).
+func (f DecoderFunc) Decode(payload []byte) ([]*quickfix.Message, error) {
+	return f(payload)
+}
+
+// FIXDecoder decodes payloads made of consecutive FIX messages.
+var FIXDecoder Decoder = DecoderFunc(decodeFIX)
+
+func decodeFIX(payload []byte) (msgs []*quickfix.Message, err error) {
+	for len(payload) > 0 {
+		end := bytes.Index(payload, []byte("\00110="))
+		if end < 0 {
+			return nil, errors.New("multicast: truncated FIX message")
+		}
+		end += 4
+		soh := bytes.IndexByte(payload[end:], '\001')
+		if soh < 0 {
+			return nil, errors.New("multicast: truncated FIX message")
+		}
+		end += soh + 1
+
+		msg := quickfix.NewMessage()
+		if err := quickfix.ParseMessage(msg, bytes.NewBuffer(append([]byte(nil), payload[:end]...))); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+		payload = payload[end:]
+	}
+	return
+}
+
+// ErrGap is reported when the packets First to Last were lost and could not be recovered.
+type ErrGap struct {
+	First, Last uint64
+	Err         error
+}
+
+func (e ErrGap) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("multicast: packets %d to %d lost: %v", e.First, e.Last, e.Err)
+	}
+	return fmt.Sprintf("multicast: packets %d to %d lost", e.First, e.Last)
+}
+
+// Unwrap returns the underlying error, if any.
+func (e ErrGap) Unwrap() error { return e.Err }
+
+// Options configure a Receiver.
+type Options struct {
+	// Group is the multicast group address, as host:port, joined by ListenAndServe.
+	Group string
+
+	// Interface is the name of the network interface joining Group, the system default when empty.
+	Interface string
+
+	// SessionID identifies the feed in the callbacks of the Application.
+	SessionID quickfix.SessionID
+
+	// Decoder decodes the payloads, FIXDecoder when nil.
+	Decoder Decoder
+
+	// Recoverer recovers the packets lost, which are otherwise skipped.
+	Recoverer Recoverer
+
+	// OnError is called with the packets that could not be decoded, ErrGaps and the rejects of FromApp.
+	OnError func(err error)
+}
+
+// Stats are the counters of a Receiver.
+type Stats struct {
+	Packets uint64
+
+	// Recovered and Lost count the packets of the gaps recovered or not.
+	Recovered uint64
+	Lost      uint64
+
+	// Duplicates counts the packets received again, which are dropped.
+	Duplicates uint64
+
+	// NextSeqNum is the packet sequence number expected next, zero before the first packet.
+	NextSeqNum uint64
+}
+
+// Receiver receives packets and delivers their messages to an Application, in packet sequence number order.
+type Receiver struct {
+	app  quickfix.Application
+	opts Options
+
+	mu     sync.Mutex
+	stats  Stats
+	conn   net.PacketConn
+	closed bool
+}
+
+// NewReceiver returns a Receiver delivering messages to app.
+func NewReceiver(app quickfix.Application, opts Options) *Receiver {
+	if opts.Decoder == nil {
+		opts.Decoder = FIXDecoder
+	}
+	app.OnCreate(opts.SessionID)
+	return &Receiver{app: app, opts: opts}
+}
+
+// ListenAndServe joins the multicast group of the Options, then calls Serve.
+func (r *Receiver) ListenAndServe() error {
+	addr, err := net.ResolveUDPAddr("udp", r.opts.Group)
+	if err != nil {
+		return err
+	}
+
+	var ifi *net.Interface
+	if r.opts.Interface != "" {
+		if ifi, err = net.InterfaceByName(r.opts.Interface); err != nil {
+			return err
+		}
+	}
+
+	conn, err := net.ListenMulticastUDP("udp", ifi, addr)
+	if err != nil {
+		return err
+	}
+	return r.Serve(conn)
+}
+
+// Serve receives packets from conn until the Receiver is closed. OnLogon is called when serving starts and OnLogout
+// when it stops.
+func (r *Receiver) Serve(conn net.PacketConn) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		_ = conn.Close()
+		return net.ErrClosed
+	}
+	r.conn = conn
+	r.mu.Unlock()
+
+	r.app.OnLogon(r.opts.SessionID)
+	defer r.app.OnLogout(r.opts.SessionID)
+
+	buf := make([]byte, MaxPacketSize)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			r.mu.Lock()
+			closed := r.closed
+			r.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		r.receive(buf[:n])
+	}
+}
+
+// Close stops serving.
+func (r *Receiver) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	if r.conn != nil {
+		return r.conn.Close()
+	}
+	return nil
+}
+
+// Stats returns the counters of the Receiver.
+func (r *Receiver) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// receive delivers the messages of a packet, first recovering the packets lost before it.
+func (r *Receiver) receive(packet []byte) {
+	if len(packet) < headerSize {
+		r.onError(errors.New("multicast: packet too short"))
+		return
+	}
+	seqNum := binary.BigEndian.Uint64(packet)
+
+	r.mu.Lock()
+	next := r.stats.NextSeqNum
+	if next != 0 && seqNum < next {
+		r.stats.Duplicates++
+		r.mu.Unlock()
+		return
+	}
+	r.stats.Packets++
+	r.stats.NextSeqNum = seqNum + 1
+	r.mu.Unlock()
+
+	if next != 0 && seqNum > next {
+		r.recover(next, seqNum-1)
+	}
+	r.deliver(packet[headerSize:])
+}
+
+func (r *Receiver) recover(first, last uint64) {
+	var payloads [][]byte
+	var err error
+	if r.opts.Recoverer != nil {
+		payloads, err = r.opts.Recoverer.Recover(first, last)
+	}
+
+	recovered := uint64(len(payloads))
+	if recovered > last-first+1 {
+		recovered = last - first + 1
+	}
+
+	r.mu.Lock()
+	r.stats.Recovered += recovered
+	r.stats.Lost += last - first + 1 - recovered
+	r.mu.Unlock()
+
+	for _, payload := range payloads[:recovered] {
+		r.deliver(payload)
+	}
+	if recovered <= last-first {
+		r.onError(ErrGap{First: first + recovered, Last: last, Err: err})
+	}
+}
+
+func (r *Receiver) deliver(payload []byte) {
+	msgs, err := r.opts.Decoder.Decode(payload)
+	if err != nil {
+		r.onError(err)
+		return
+	}
+
+	for _, msg := range msgs {
+		if reject := r.app.FromApp(msg, r.opts.SessionID); reject != nil {
+			r.onError(reject)
+		}
+	}
+}
+
+func (r *Receiver) onError(err error) {
+	if r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
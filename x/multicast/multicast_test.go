@@ -0,0 +1,166 @@
+//go:build quickfix_experimental
+
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package multicast
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type app struct {
+	mu      sync.Mutex
+	symbols []string
+	logons  int
+}
+
+func (a *app) OnCreate(quickfix.SessionID)                       {}
+func (a *app) OnLogon(quickfix.SessionID)                        { a.mu.Lock(); a.logons++; a.mu.Unlock() }
+func (a *app) OnLogout(quickfix.SessionID)                       {}
+func (a *app) ToAdmin(*quickfix.Message, quickfix.SessionID)     {}
+func (a *app) ToApp(*quickfix.Message, quickfix.SessionID) error { return nil }
+func (a *app) FromAdmin(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+func (a *app) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	symbol, _ := msg.Body.GetString(quickfix.Tag(55))
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.symbols = append(a.symbols, symbol)
+	return nil
+}
+
+func (a *app) received() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.symbols...)
+}
+
+func fixMessage(symbol string) []byte {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(8), "FIX.4.4")
+	msg.Header.SetString(quickfix.Tag(35), "W")
+	msg.Body.SetString(quickfix.Tag(55), symbol)
+	return []byte(msg.String())
+}
+
+func packet(seqNum uint64, payload ...[]byte) []byte {
+	p := binary.BigEndian.AppendUint64(nil, seqNum)
+	for _, b := range payload {
+		p = append(p, b...)
+	}
+	return p
+}
+
+type recovererFunc func(first, last uint64) ([][]byte, error)
+
+func (f recovererFunc) Recover(first, last uint64) ([][]byte, error) { return f(first, last) }
+
+func TestFIXDecoder(t *testing.T) {
+	msgs, err := FIXDecoder.Decode(append(fixMessage("A"), fixMessage("B")...))
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	symbol, _ := msgs[1].Body.GetString(quickfix.Tag(55))
+	assert.Equal(t, "B", symbol)
+
+	_, err = FIXDecoder.Decode(fixMessage("A")[:20])
+	assert.Error(t, err)
+}
+
+func TestReceiverGaps(t *testing.T) {
+	a := new(app)
+	var errs []error
+	r := NewReceiver(a, Options{
+		Recoverer: recovererFunc(func(first, last uint64) ([][]byte, error) {
+			if first == 2 {
+				return [][]byte{fixMessage("B")}, nil
+			}
+			return nil, errors.New("unavailable")
+		}),
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	r.receive(packet(1, fixMessage("A")))
+	r.receive(packet(3, fixMessage("C")))
+	r.receive(packet(2, fixMessage("B")))
+	r.receive(packet(6, fixMessage("F")))
+
+	assert.Equal(t, []string{"A", "B", "C", "F"}, a.received())
+	assert.Equal(t, Stats{Packets: 3, Recovered: 1, Lost: 2, Duplicates: 1, NextSeqNum: 7}, r.Stats())
+
+	require.Len(t, errs, 1)
+	var gap ErrGap
+	require.ErrorAs(t, errs[0], &gap)
+	assert.Equal(t, uint64(4), gap.First)
+	assert.Equal(t, uint64(5), gap.Last)
+}
+
+func TestServe(t *testing.T) {
+	a := new(app)
+	r := NewReceiver(a, Options{})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error)
+	go func() { served <- r.Serve(conn) }()
+
+	sender, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer sender.Close()
+	_, err = sender.Write(packet(10, fixMessage("A"), fixMessage("B")))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(a.received()) == 2 }, time.Second, time.Millisecond)
+	require.NoError(t, r.Close())
+	assert.NoError(t, <-served)
+	assert.Equal(t, 1, a.logons)
+}
+
+func TestTCPRecoverer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req [16]byte
+		if _, err := conn.Read(req[:]); err != nil {
+			return
+		}
+		// Only the first packet requested is available.
+		payload := []byte("packet" + string(rune('0'+binary.BigEndian.Uint64(req[:8]))))
+		_, _ = conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(payload))), payload...))
+	}()
+
+	payloads, err := TCPRecoverer{Address: l.Addr().String(), Timeout: time.Second}.Recover(4, 5)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("packet4")}, payloads)
+}
@@ -0,0 +1,88 @@
+//go:build quickfix_experimental
+
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package multicast
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+// Recoverer retrieves lost packets.
+type Recoverer interface {
+	// Recover returns the payloads of the packets first to last, in order. Fewer payloads are returned when the
+	// later packets are not available.
+	Recover(first, last uint64) ([][]byte, error)
+}
+
+// TCPRecoverer recovers packets from a retransmission service over TCP. Each recovery opens a connection and
+// writes the first and last packet sequence numbers as big endian uint64s. The service answers each packet
+// available with its payload prefixed by a big endian uint32 length, then closes the connection.
+type TCPRecoverer struct {
+	Address string
+
+	// Timeout bounds each recovery, unbounded when zero.
+	Timeout time.Duration
+}
+
+// Recover implements Recoverer.
+func (t TCPRecoverer) Recover(first, last uint64) (payloads [][]byte, err error) {
+	conn, err := net.DialTimeout("tcp", t.Address, t.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if t.Timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(t.Timeout)); err != nil {
+			return nil, err
+		}
+	}
+
+	var req [16]byte
+	binary.BigEndian.PutUint64(req[:8], first)
+	binary.BigEndian.PutUint64(req[8:], last)
+	if _, err = conn.Write(req[:]); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	for seqNum := first; seqNum <= last; seqNum++ {
+		var size [4]byte
+		if _, err = io.ReadFull(r, size[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return
+		}
+
+		n := binary.BigEndian.Uint32(size[:])
+		if n > MaxPacketSize {
+			return payloads, errors.New("multicast: recovered packet too large")
+		}
+		payload := make([]byte, n)
+		if _, err = io.ReadFull(r, payload); err != nil {
+			return
+		}
+		payloads = append(payloads, payload)
+	}
+	return
+}